@@ -65,7 +65,23 @@ type Alerts struct {
 	alertsSize           int
 	skippedAlerts        uint32
 	duplicatedAlerts     uint32
+	rateLimitedAlerts    uint32
 	isConnected          bool
+	aggregatedAlerts     map[string]*aggregatedAlert
+	aggregationQueue     []*aggregatedAlert
+	tagRates             map[string]*tagRate
+}
+
+type aggregatedAlert struct {
+	key       string
+	item      cloudprotocol.AlertItem
+	count     uint32
+	windowEnd time.Time
+}
+
+type tagRate struct {
+	count     int
+	windowEnd time.Time
 }
 
 /***********************************************************************************************************************
@@ -81,6 +97,8 @@ func New(config config.Alerts, sender Sender) (instance *Alerts, err error) {
 		sender:               sender,
 		alertsChannel:        make(chan cloudprotocol.AlertItem, alertChannelSize),
 		alertsPackageChannel: make(chan cloudprotocol.Alerts, config.MaxOfflineMessages),
+		aggregatedAlerts:     make(map[string]*aggregatedAlert),
+		tagRates:             make(map[string]*tagRate),
 	}
 
 	ctx, cancelFunction := context.WithCancel(context.Background())
@@ -194,12 +212,21 @@ func (instance *Alerts) addAlert(item cloudprotocol.AlertItem) (bufferIsFull boo
 	instance.Lock()
 	defer instance.Unlock()
 
+	if instance.isAggregated(item) {
+		instance.aggregateAlert(item)
+		return false
+	}
+
 	if len(instance.currentAlerts) != 0 &&
 		reflect.DeepEqual(instance.currentAlerts[len(instance.currentAlerts)-1].Payload, item.Payload) {
 		instance.duplicatedAlerts++
 		return
 	}
 
+	return instance.appendAlert(item)
+}
+
+func (instance *Alerts) appendAlert(item cloudprotocol.AlertItem) (bufferIsFull bool) {
 	data, err := json.Marshal(item)
 	if err != nil {
 		log.Errorf("Can't marshal alert: %v", err)
@@ -211,10 +238,95 @@ func (instance *Alerts) addAlert(item cloudprotocol.AlertItem) (bufferIsFull boo
 	return instance.alertsSize >= instance.config.MaxMessageSize
 }
 
+func (instance *Alerts) isAggregated(item cloudprotocol.AlertItem) bool {
+	if instance.config.Aggregation == nil || instance.config.Aggregation.Window.Duration <= 0 {
+		return false
+	}
+
+	for _, tag := range instance.config.Aggregation.ExemptTags {
+		if tag == item.Tag {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (instance *Alerts) aggregateAlert(item cloudprotocol.AlertItem) {
+	data, err := json.Marshal(item.Payload)
+	if err != nil {
+		log.Errorf("Can't marshal alert payload: %v", err)
+
+		instance.appendAlert(item)
+
+		return
+	}
+
+	key := item.Tag + ":" + string(data)
+
+	if aggregated, ok := instance.aggregatedAlerts[key]; ok {
+		aggregated.count++
+		return
+	}
+
+	now := time.Now()
+	window := instance.config.Aggregation.Window.Duration
+
+	if limit := instance.config.Aggregation.MaxAlertsPerTag; limit > 0 {
+		rate, ok := instance.tagRates[item.Tag]
+		if !ok || !now.Before(rate.windowEnd) {
+			rate = &tagRate{windowEnd: now.Add(window)}
+			instance.tagRates[item.Tag] = rate
+		}
+
+		if rate.count >= limit {
+			instance.rateLimitedAlerts++
+			return
+		}
+
+		rate.count++
+	}
+
+	aggregated := &aggregatedAlert{key: key, item: item, count: 1, windowEnd: now.Add(window)}
+
+	instance.aggregatedAlerts[key] = aggregated
+	instance.aggregationQueue = append(instance.aggregationQueue, aggregated)
+}
+
+func (instance *Alerts) flushAggregatedAlerts() {
+	now := time.Now()
+	pendingAlerts := make([]*aggregatedAlert, 0, len(instance.aggregationQueue))
+
+	for _, aggregated := range instance.aggregationQueue {
+		if now.Before(aggregated.windowEnd) {
+			pendingAlerts = append(pendingAlerts, aggregated)
+			continue
+		}
+
+		if aggregated.count > 1 {
+			aggregated.item.Count = aggregated.count
+		}
+
+		instance.appendAlert(aggregated.item)
+
+		delete(instance.aggregatedAlerts, aggregated.key)
+	}
+
+	instance.aggregationQueue = pendingAlerts
+
+	for tag, rate := range instance.tagRates {
+		if !now.Before(rate.windowEnd) {
+			delete(instance.tagRates, tag)
+		}
+	}
+}
+
 func (instance *Alerts) prepareAlertsPackage() {
 	instance.Lock()
 	defer instance.Unlock()
 
+	instance.flushAggregatedAlerts()
+
 	if instance.alertsSize == 0 {
 		return
 	}
@@ -229,6 +341,10 @@ func (instance *Alerts) prepareAlertsPackage() {
 		if instance.duplicatedAlerts != 0 {
 			log.WithField("count", instance.duplicatedAlerts).Warn("Alerts skipped due to duplication")
 		}
+
+		if instance.rateLimitedAlerts != 0 {
+			log.WithField("count", instance.rateLimitedAlerts).Warn("Alerts skipped due to rate limit")
+		}
 	} else {
 		log.Warn("Skip sending alerts due to channel is full")
 	}
@@ -236,5 +352,6 @@ func (instance *Alerts) prepareAlertsPackage() {
 	instance.currentAlerts = []cloudprotocol.AlertItem{}
 	instance.skippedAlerts = 0
 	instance.duplicatedAlerts = 0
+	instance.rateLimitedAlerts = 0
 	instance.alertsSize = 0
 }
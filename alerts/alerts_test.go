@@ -161,6 +161,77 @@ func TestAlertsDuplicationMessages(t *testing.T) {
 	}
 }
 
+func TestAlertsAggregation(t *testing.T) {
+	const numAlerts = 5
+
+	sender := newTestSender()
+
+	alertsHandler, err := alerts.New(config.Alerts{
+		SendPeriod:         aostypes.Duration{Duration: 100 * time.Millisecond},
+		MaxMessageSize:     1024,
+		MaxOfflineMessages: 32,
+		Aggregation: &config.AlertsAggregation{
+			Window:          aostypes.Duration{Duration: 1 * time.Second},
+			MaxAlertsPerTag: 1,
+			ExemptTags:      []string{cloudprotocol.AlertTagSystemError},
+		},
+	},
+		sender)
+	if err != nil {
+		t.Fatalf("Can't create alerts: %v", err)
+	}
+	defer alertsHandler.Close()
+
+	sender.consumer.CloudConnected()
+
+	aggregatedAlert := cloudprotocol.AlertItem{
+		Timestamp: time.Now(),
+		Tag:       cloudprotocol.AlertTagAosCore,
+		Payload:   cloudprotocol.CoreAlert{CoreComponent: "CM", Message: randomString(32)},
+	}
+
+	for i := 0; i < numAlerts; i++ {
+		alertsHandler.SendAlert(aggregatedAlert)
+	}
+
+	// Exceeds rate limit of the tag
+
+	alertsHandler.SendAlert(cloudprotocol.AlertItem{
+		Timestamp: time.Now(),
+		Tag:       cloudprotocol.AlertTagAosCore,
+		Payload:   cloudprotocol.CoreAlert{CoreComponent: "CM", Message: randomString(32)},
+	})
+
+	// Exempt alert is sent without aggregation
+
+	exemptAlert := cloudprotocol.AlertItem{
+		Timestamp: time.Now(),
+		Tag:       cloudprotocol.AlertTagSystemError,
+		Payload:   cloudprotocol.SystemAlert{Message: randomString(32)},
+	}
+
+	alertsHandler.SendAlert(exemptAlert)
+
+	alerts, err := sender.waitResult(1 * time.Second)
+	if err != nil {
+		t.Fatalf("Wait alerts error: %v", err)
+	}
+
+	if !reflect.DeepEqual(alerts, cloudprotocol.Alerts{exemptAlert}) {
+		t.Errorf("Incorrect alerts: %v", alerts)
+	}
+
+	if alerts, err = sender.waitResult(2 * time.Second); err != nil {
+		t.Fatalf("Wait alerts error: %v", err)
+	}
+
+	aggregatedAlert.Count = numAlerts
+
+	if !reflect.DeepEqual(alerts, cloudprotocol.Alerts{aggregatedAlert}) {
+		t.Errorf("Incorrect alerts: %v", alerts)
+	}
+}
+
 func TestAlertsOfflineMessages(t *testing.T) {
 	const (
 		numOfflineMessages = 32
@@ -24,13 +24,18 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
 	"github.com/aosedge/aos_common/api/cloudprotocol"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 )
@@ -44,9 +49,20 @@ const (
 	sendMaxTry         = 3
 	sendTimeout        = 1 * time.Minute
 	receiveChannelSize = 16
+	monitoringBufSize  = 16
 	maxLenLogMessage   = 340
 )
 
+const redactedValue = "***"
+
+const (
+	discoveryMaxAttempts   = 3
+	discoveryMaxRetryAfter = 1 * time.Minute
+
+	discoveryDNSMaxAttempts   = 5
+	discoveryDNSMaxRetryDelay = 30 * time.Second
+)
+
 const (
 	amqpSecureScheme   = "amqps"
 	amqpInsecureScheme = "amqp"
@@ -67,6 +83,10 @@ type AmqpHandler struct { //nolint:stylecheck
 	pendingChannel chan cloudprotocol.Message
 	sendTry        int
 
+	pendingStore        PendingStore
+	pendingStoreChannel chan struct{}
+	pendingFromStore    bool
+
 	sendConnection    *amqp.Connection
 	receiveConnection *amqp.Connection
 
@@ -79,9 +99,56 @@ type AmqpHandler struct { //nolint:stylecheck
 	wg sync.WaitGroup
 
 	isConnected               bool
+	insecure                  atomic.Bool
 	connectionEventsConsumers []ConnectionEventsConsumer
+
+	lastConnectionInfo cloudprotocol.ConnectionInfo
+	lastRawResponse    []byte
+
+	messageSettingsMutex   sync.RWMutex
+	unknownMessageLogLevel log.Level
+	unknownMessageHandler  UnknownMessageHandler
+	messageDispatcher      MessageDispatcher
+
+	prefetchCount int
+	manualAck     bool
+
+	unitStatusChunkSize int
+
+	codec messageCodec
+
+	clockMutex        sync.Mutex
+	cloudTime         time.Time
+	cloudTimeReceived time.Time
+
+	monitoringMutex   sync.Mutex
+	monitoringBuffer  []cloudprotocol.Monitoring
+	monitoringChannel chan struct{}
+	monitoringDropped uint64
+
+	messageTTLs     map[string]time.Duration
+	expiredMessages atomic.Uint64
+}
+
+// MonitoringBufferMetrics monitoring buffer metrics.
+type MonitoringBufferMetrics struct {
+	Buffered int
+	Dropped  uint64
 }
 
+// UnknownMessage raw cloud message of unknown type.
+type UnknownMessage struct {
+	MessageType string
+	Data        []byte
+}
+
+// UnknownMessageHandler handler for cloud messages of unknown type.
+type UnknownMessageHandler func(message UnknownMessage)
+
+// MessageDispatcher dispatches received cloud message. In manual ack mode the message is acknowledged only after
+// the dispatcher returns without error.
+type MessageDispatcher func(message Message) error
+
 // CryptoContext interface to access crypto functions.
 type CryptoContext interface {
 	GetTLSConfig() (*tls.Config, error)
@@ -97,6 +164,17 @@ type ConnectionEventsConsumer interface {
 	CloudDisconnected()
 }
 
+type hostResolver interface {
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+type consumerChannel interface {
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	Consume(
+		queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table,
+	) (<-chan amqp.Delivery, error)
+}
+
 /***********************************************************************************************************************
  * Variables
  **********************************************************************************************************************/
@@ -123,6 +201,9 @@ var messageMap = map[string]func() interface{}{ //nolint:gochecknoglobals
 	cloudprotocol.OverrideEnvVarsType: func() interface{} {
 		return &cloudprotocol.OverrideEnvVars{}
 	},
+	cloudprotocol.SetLogLevelType: func() interface{} {
+		return &cloudprotocol.SetLogLevel{}
+	},
 }
 
 var (
@@ -130,6 +211,18 @@ var (
 	ErrNotConnected = errors.New("not connected")
 	// ErrSendChannelFull indicates AMQP send channel is full.
 	ErrSendChannelFull = errors.New("send channel full")
+	// ErrDiscoveryResolve indicates service discovery host can't be resolved (misconfiguration or DNS problem).
+	ErrDiscoveryResolve = errors.New("service discovery host resolution failed")
+	// ErrDiscoveryServer indicates service discovery server responded with error.
+	ErrDiscoveryServer = errors.New("service discovery server error")
+
+	errSendMaxTry = errors.New("sending message max try reached")
+)
+
+//nolint:gochecknoglobals // used in unit tests
+var (
+	discoveryResolver      hostResolver = net.DefaultResolver
+	discoveryDNSRetryDelay              = 1 * time.Second
 )
 
 var importantMessages = []string{ //nolint:gochecknoglobals // used as const
@@ -149,13 +242,123 @@ func New() (*AmqpHandler, error) {
 	log.Debug("New AMQP")
 
 	handler := &AmqpHandler{
-		sendChannel:    make(chan cloudprotocol.Message, sendChannelSize),
-		pendingChannel: make(chan cloudprotocol.Message, 1),
+		sendChannel:            make(chan cloudprotocol.Message, sendChannelSize),
+		pendingChannel:         make(chan cloudprotocol.Message, 1),
+		pendingStore:           NewMemoryPendingStore(sendChannelSize),
+		pendingStoreChannel:    make(chan struct{}, 1),
+		unknownMessageLogLevel: log.WarnLevel,
+		monitoringBuffer:       make([]cloudprotocol.Monitoring, 0, monitoringBufSize),
+		monitoringChannel:      make(chan struct{}, 1),
+		codec:                  jsonCodec{},
+		messageTTLs:            make(map[string]time.Duration),
 	}
 
 	return handler, nil
 }
 
+// SetUnknownMessageHandling sets log level and optional handler for cloud messages of unknown type.
+func (handler *AmqpHandler) SetUnknownMessageHandling(logLevel log.Level, unknownHandler UnknownMessageHandler) {
+	handler.messageSettingsMutex.Lock()
+	defer handler.messageSettingsMutex.Unlock()
+
+	handler.unknownMessageLogLevel = logLevel
+	handler.unknownMessageHandler = unknownHandler
+}
+
+// SetConsumerQoS sets consumer prefetch count and ack mode. With manual ack received messages are acknowledged after
+// successful dispatching, so unprocessed messages are redelivered by the broker. Applied on next connection.
+func (handler *AmqpHandler) SetConsumerQoS(prefetchCount int, manualAck bool) {
+	handler.messageSettingsMutex.Lock()
+	defer handler.messageSettingsMutex.Unlock()
+
+	handler.prefetchCount = prefetchCount
+	handler.manualAck = manualAck
+}
+
+// SetUnitStatusChunkSize sets max size of encoded unit status message. Bigger unit status is sent as sequence of
+// chunk messages not exceeding this size. Zero disables chunking.
+func (handler *AmqpHandler) SetUnitStatusChunkSize(chunkSize int) {
+	handler.Lock()
+	defer handler.Unlock()
+
+	handler.unitStatusChunkSize = chunkSize
+}
+
+// SetMessageEncoding sets encoding of sent messages: JSON (default) or CBOR. Received messages are decoded according
+// to their content type. Should be set before connecting to the cloud.
+func (handler *AmqpHandler) SetMessageEncoding(encoding string) error {
+	codec, err := newMessageCodec(encoding)
+	if err != nil {
+		return err
+	}
+
+	handler.Lock()
+	defer handler.Unlock()
+
+	handler.codec = codec
+
+	return nil
+}
+
+// SetMessageDispatcher sets dispatcher for received cloud messages. By default messages are sent to the message
+// channel.
+func (handler *AmqpHandler) SetMessageDispatcher(dispatcher MessageDispatcher) {
+	handler.messageSettingsMutex.Lock()
+	defer handler.messageSettingsMutex.Unlock()
+
+	handler.messageDispatcher = dispatcher
+}
+
+// SetPendingStore sets store for important messages pending to be sent. In-memory store is used by default.
+// Should be set before connecting to the cloud.
+func (handler *AmqpHandler) SetPendingStore(store PendingStore) {
+	handler.Lock()
+	defer handler.Unlock()
+
+	handler.pendingStore = store
+}
+
+// SetMessageTTL sets TTL for messages of specified type queued to be sent. Messages which are not published before
+// TTL expires are dropped. Zero TTL means messages never expire.
+func (handler *AmqpHandler) SetMessageTTL(messageType string, ttl time.Duration) {
+	handler.Lock()
+	defer handler.Unlock()
+
+	if ttl == 0 {
+		delete(handler.messageTTLs, messageType)
+
+		return
+	}
+
+	handler.messageTTLs[messageType] = ttl
+}
+
+// GetExpiredMessagesCount returns number of queued messages dropped due to expired TTL.
+func (handler *AmqpHandler) GetExpiredMessagesCount() uint64 {
+	return handler.expiredMessages.Load()
+}
+
+// GetClockSkew returns difference between local clock and cloud clock estimated by timestamp of the last received
+// cloud message. Returns false if no timestamped message is received yet.
+func (handler *AmqpHandler) GetClockSkew() (skew time.Duration, ok bool) {
+	handler.clockMutex.Lock()
+	defer handler.clockMutex.Unlock()
+
+	if handler.cloudTime.IsZero() {
+		return 0, false
+	}
+
+	// Elapsed time is measured by monotonic clock and local time by wall clock to detect wall clock adjustments
+	cloudNow := handler.cloudTime.Add(time.Since(handler.cloudTimeReceived))
+
+	return time.Now().Round(0).Sub(cloudNow), true
+}
+
+// IsInsecure returns true if AMQP connection is requested in insecure mode.
+func (handler *AmqpHandler) IsInsecure() bool {
+	return handler.insecure.Load()
+}
+
 // Connect connects to cloud.
 func (handler *AmqpHandler) Connect(cryptoContext CryptoContext, sdURL, systemID string, insecure bool) error {
 	handler.Lock()
@@ -166,6 +369,13 @@ func (handler *AmqpHandler) Connect(cryptoContext CryptoContext, sdURL, systemID
 	handler.cryptoContext = cryptoContext
 	handler.systemID = systemID
 
+	handler.insecure.Store(insecure)
+
+	if insecure {
+		log.WithFields(log.Fields{"component": "amqp", "insecure": true, "url": sdURL}).Warn(
+			"AMQP connection runs in insecure mode, TLS is disabled")
+	}
+
 	tlsConfig, err := handler.cryptoContext.GetTLSConfig()
 	if err != nil {
 		return aoserrors.Wrap(err)
@@ -173,12 +383,13 @@ func (handler *AmqpHandler) Connect(cryptoContext CryptoContext, sdURL, systemID
 
 	var (
 		connectionInfo cloudprotocol.ConnectionInfo
+		rawResponse    []byte
 		ctx            context.Context
 	)
 
 	ctx, handler.cancelFunc = context.WithCancel(context.Background())
 
-	if connectionInfo, err = getConnectionInfo(ctx, sdURL,
+	if connectionInfo, rawResponse, err = getConnectionInfo(ctx, sdURL,
 		handler.createCloudMessage(cloudprotocol.ServiceDiscoveryType,
 			cloudprotocol.ServiceDiscoveryRequest{}), tlsConfig); err != nil {
 		return aoserrors.Wrap(err)
@@ -194,6 +405,9 @@ func (handler *AmqpHandler) Connect(cryptoContext CryptoContext, sdURL, systemID
 		return aoserrors.Wrap(err)
 	}
 
+	handler.lastConnectionInfo = redactConnectionInfo(connectionInfo)
+	handler.lastRawResponse = redactRawResponse(rawResponse)
+
 	handler.isConnected = true
 
 	handler.notifyCloudConnected()
@@ -229,20 +443,90 @@ func (handler *AmqpHandler) Disconnect() error {
 	return nil
 }
 
+// GetLastConnectionInfo returns connection info and raw service discovery response of the last successful connect.
+// Secrets are redacted.
+func (handler *AmqpHandler) GetLastConnectionInfo() (info cloudprotocol.ConnectionInfo, rawResponse []byte) {
+	handler.Lock()
+	defer handler.Unlock()
+
+	return handler.lastConnectionInfo, append(rawResponse, handler.lastRawResponse...)
+}
+
 // SendUnitStatus sends unit status.
 func (handler *AmqpHandler) SendUnitStatus(unitStatus cloudprotocol.UnitStatus) error {
 	handler.Lock()
 	defer handler.Unlock()
 
-	return handler.scheduleMessage(cloudprotocol.UnitStatusType, unitStatus, false)
+	if handler.unitStatusChunkSize <= 0 {
+		return handler.scheduleMessage(cloudprotocol.UnitStatusType, unitStatus, false)
+	}
+
+	message, err := handler.codec.marshal(handler.createCloudMessage(cloudprotocol.UnitStatusType, unitStatus))
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if len(message) <= handler.unitStatusChunkSize {
+		return handler.scheduleMessage(cloudprotocol.UnitStatusType, unitStatus, false)
+	}
+
+	chunkDataSize, err := handler.getChunkDataSize()
+	if err != nil {
+		return err
+	}
+
+	chunks, err := splitUnitStatus(unitStatus, chunkDataSize)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"correlationID": chunks[0].CorrelationID, "chunks": len(chunks),
+	}).Debug("Send unit status in chunks")
+
+	messages := make([]cloudprotocol.Message, 0, len(chunks))
+
+	for _, chunk := range chunks {
+		messages = append(messages, handler.createCloudMessage(cloudprotocol.UnitStatusChunkType, chunk))
+	}
+
+	return handler.scheduleMessages(messages)
 }
 
-// SendMonitoringData sends monitoring data.
-func (handler *AmqpHandler) SendMonitoringData(monitoringData cloudprotocol.Monitoring) error {
+// SendNewServices sends notification about new services which instances are going to be started.
+func (handler *AmqpHandler) SendNewServices(newServices cloudprotocol.NewServices) error {
 	handler.Lock()
 	defer handler.Unlock()
 
-	return handler.scheduleMessage(cloudprotocol.MonitoringDataType, monitoringData, false)
+	return handler.scheduleMessage(cloudprotocol.NewServicesType, newServices, true)
+}
+
+// SendMonitoringData sends monitoring data. Monitoring data is kept in dedicated bounded buffer which drops the
+// oldest data when full and is flushed only when connected.
+func (handler *AmqpHandler) SendMonitoringData(monitoringData cloudprotocol.Monitoring) error {
+	handler.monitoringMutex.Lock()
+	defer handler.monitoringMutex.Unlock()
+
+	if len(handler.monitoringBuffer) >= monitoringBufSize {
+		handler.monitoringBuffer = handler.monitoringBuffer[1:]
+		handler.monitoringDropped++
+
+		log.WithField("dropped", handler.monitoringDropped).Warn("Monitoring buffer is full, drop oldest data")
+	}
+
+	handler.monitoringBuffer = append(handler.monitoringBuffer, monitoringData)
+
+	handler.notifyMonitoring()
+
+	return nil
+}
+
+// GetMonitoringBufferMetrics returns monitoring buffer metrics.
+func (handler *AmqpHandler) GetMonitoringBufferMetrics() MonitoringBufferMetrics {
+	handler.monitoringMutex.Lock()
+	defer handler.monitoringMutex.Unlock()
+
+	return MonitoringBufferMetrics{Buffered: len(handler.monitoringBuffer), Dropped: handler.monitoringDropped}
 }
 
 // SendServiceNewState sends new state message.
@@ -359,47 +643,208 @@ func (handler *AmqpHandler) Close() {
 // service discovery implementation.
 func getConnectionInfo(
 	ctx context.Context, url string, request cloudprotocol.Message, tlsConfig *tls.Config,
-) (info cloudprotocol.ConnectionInfo, err error) {
+) (info cloudprotocol.ConnectionInfo, rawResponse []byte, err error) {
 	reqJSON, err := json.Marshal(request)
 	if err != nil {
-		return info, aoserrors.Wrap(err)
+		return info, nil, aoserrors.Wrap(err)
 	}
 
 	log.WithField("request", string(reqJSON)).Info("AMQP service discovery request")
 
+	if err = resolveDiscoveryHost(ctx, url); err != nil {
+		return info, nil, err
+	}
+
 	transport := &http.Transport{TLSClientConfig: tlsConfig}
 	client := &http.Client{Transport: transport}
 
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+
+		if rawResponse, retryAfter, err = sendDiscoveryRequest(ctx, client, url, reqJSON); err == nil {
+			break
+		}
+
+		if retryAfter == 0 || attempt >= discoveryMaxAttempts {
+			return info, nil, err
+		}
+
+		log.WithFields(log.Fields{"attempt": attempt, "retryAfter": retryAfter}).Warnf(
+			"Service discovery failed, retry: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return info, nil, aoserrors.Wrap(ctx.Err())
+
+		case <-time.After(retryAfter):
+		}
+	}
+
+	var jsonResp cloudprotocol.ServiceDiscoveryResponse
+
+	err = json.Unmarshal(rawResponse, &jsonResp)
+	if err != nil {
+		return info, nil, aoserrors.Wrap(err)
+	}
+
+	return jsonResp.Connection, rawResponse, nil
+}
+
+// resolveDiscoveryHost resolves service discovery host with backoff. DNS failures are retried separately from HTTP
+// status retries as resolution may recover after network is configured.
+func resolveDiscoveryHost(ctx context.Context, discoveryURL string) error {
+	parsedURL, err := url.Parse(discoveryURL)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	host := parsedURL.Hostname()
+	if host == "" || net.ParseIP(host) != nil {
+		return nil
+	}
+
+	retryDelay := discoveryDNSRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		if _, err = discoveryResolver.LookupHost(ctx, host); err == nil {
+			return nil
+		}
+
+		if attempt >= discoveryDNSMaxAttempts {
+			return aoserrors.Errorf("%w: %s: %v", ErrDiscoveryResolve, host, err)
+		}
+
+		log.WithFields(log.Fields{"host": host, "attempt": attempt, "retryDelay": retryDelay}).Warnf(
+			"Can't resolve service discovery host, retry: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return aoserrors.Wrap(ctx.Err())
+
+		case <-time.After(retryDelay):
+		}
+
+		retryDelay = min(2*retryDelay, discoveryDNSMaxRetryDelay)
+	}
+}
+
+func sendDiscoveryRequest(
+	ctx context.Context, client *http.Client, url string, reqJSON []byte,
+) (htmlData []byte, retryAfter time.Duration, err error) {
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(reqJSON))
 	if err != nil {
-		return info, aoserrors.Wrap(err)
+		return nil, 0, aoserrors.Wrap(err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
-		return info, aoserrors.Wrap(err)
+		var dnsErr *net.DNSError
+
+		if errors.As(err, &dnsErr) {
+			return nil, 0, aoserrors.Errorf("%w: %v", ErrDiscoveryResolve, err)
+		}
+
+		return nil, 0, aoserrors.Wrap(err)
 	}
 	defer resp.Body.Close()
 
-	htmlData, err := io.ReadAll(resp.Body)
+	htmlData, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return info, aoserrors.Wrap(err)
+		return nil, 0, aoserrors.Wrap(err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return info, aoserrors.Errorf("%s: %s", resp.Status, string(htmlData))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+
+		return nil, retryAfter, aoserrors.Errorf("%w: %s: %s", ErrDiscoveryServer, resp.Status, string(htmlData))
 	}
 
-	var jsonResp cloudprotocol.ServiceDiscoveryResponse
+	return htmlData, 0, nil
+}
+
+// parseRetryAfter parses Retry-After header value in seconds or HTTP-date format.
+func parseRetryAfter(value string) (retryAfter time.Duration) {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		retryAfter = time.Until(date)
+	} else {
+		log.WithField("value", value).Warn("Can't parse Retry-After header")
+
+		return 0
+	}
+
+	if retryAfter <= 0 {
+		// Retry immediately
+		return time.Millisecond
+	}
+
+	if retryAfter > discoveryMaxRetryAfter {
+		retryAfter = discoveryMaxRetryAfter
+	}
+
+	return retryAfter
+}
+
+func redactConnectionInfo(info cloudprotocol.ConnectionInfo) cloudprotocol.ConnectionInfo {
+	if info.SendParams.Password != "" {
+		info.SendParams.Password = redactedValue
+	}
+
+	if info.ReceiveParams.Password != "" {
+		info.ReceiveParams.Password = redactedValue
+	}
+
+	return info
+}
 
-	err = json.Unmarshal(htmlData, &jsonResp)
+func redactRawResponse(rawResponse []byte) []byte {
+	var response interface{}
+
+	if err := json.Unmarshal(rawResponse, &response); err != nil {
+		log.Errorf("Can't parse service discovery response: %v", err)
+
+		return nil
+	}
+
+	redactedResponse, err := json.Marshal(redactSecrets(response))
 	if err != nil {
-		return info, aoserrors.Wrap(err)
+		log.Errorf("Can't marshal service discovery response: %v", err)
+
+		return nil
+	}
+
+	return redactedResponse
+}
+
+func redactSecrets(value interface{}) interface{} {
+	switch data := value.(type) {
+	case map[string]interface{}:
+		for key, item := range data {
+			if key == "password" {
+				data[key] = redactedValue
+
+				continue
+			}
+
+			data[key] = redactSecrets(item)
+		}
+
+	case []interface{}:
+		for i, item := range data {
+			data[i] = redactSecrets(item)
+		}
 	}
 
-	return jsonResp.Connection, nil
+	return value
 }
 
 func (handler *AmqpHandler) setupConnections(
@@ -467,12 +912,17 @@ func (handler *AmqpHandler) runSender(amqpChannel *amqp.Channel, params cloudpro
 
 	errorChannel := handler.sendConnection.NotifyClose(make(chan *amqp.Error, 1))
 	confirmChannel := amqpChannel.NotifyPublish(make(chan amqp.Confirmation, 1))
-	sendChannel := handler.sendChannel
+	sendChannel, monitoringChannel, pendingStoreChannel := handler.sendChannel, handler.monitoringChannel,
+		handler.pendingStoreChannel
+	storeChannel := pendingStoreChannel
 
 	if len(handler.pendingChannel) > 0 {
-		sendChannel = nil
+		sendChannel, monitoringChannel, storeChannel = nil, nil, nil
 	}
 
+	// Resend messages stored while disconnected or before restart
+	handler.notifyPendingStore()
+
 	for {
 		select {
 		case err := <-errorChannel:
@@ -482,27 +932,39 @@ func (handler *AmqpHandler) runSender(amqpChannel *amqp.Channel, params cloudpro
 
 			return
 
+		case <-storeChannel:
+			if message, ok := handler.getStoredMessage(); ok {
+				sendChannel, monitoringChannel, storeChannel = nil, nil, nil
+				handler.setPendingMessage(message)
+			}
+
 		case message := <-sendChannel:
-			handler.sendTry = 0
-			sendChannel = nil
-			handler.pendingChannel <- message
+			sendChannel, monitoringChannel, storeChannel = nil, nil, nil
+			handler.setPendingMessage(message)
+
+		case <-monitoringChannel:
+			if message, ok := handler.getMonitoringMessage(); ok {
+				sendChannel, monitoringChannel, storeChannel = nil, nil, nil
+				handler.setPendingMessage(message)
+			}
 
 		case message := <-handler.pendingChannel:
-			if err := handler.sendMessage(message, amqpChannel, params); err != nil {
+			confirmed, err := handler.publishPendingMessage(message, amqpChannel, params, confirmChannel)
+			if err != nil {
 				log.Warnf("Can't send message: %v", err)
 
-				sendChannel = handler.sendChannel
-
-				break
-			}
-
-			if confirm, ok := <-confirmChannel; !ok || !confirm.Ack {
+				// Stored messages are not sent anymore on this connection to keep them in order
+				if !handler.processSendError(err) {
+					pendingStoreChannel = nil
+				}
+			} else if !confirmed {
 				handler.pendingChannel <- message
 
 				break
 			}
 
-			sendChannel = handler.sendChannel
+			sendChannel, monitoringChannel, storeChannel = handler.sendChannel, handler.monitoringChannel,
+				pendingStoreChannel
 		}
 	}
 }
@@ -532,15 +994,7 @@ func (handler *AmqpHandler) setupReceiveConnection(
 		return aoserrors.Wrap(err)
 	}
 
-	deliveryChannel, err := amqpChannel.Consume(
-		params.Queue.Name, // queue
-		params.Consumer,   // consumer
-		true,              // auto-ack param.AutoAck
-		params.Exclusive,  // exclusive
-		params.NoLocal,    // no-local
-		params.NoWait,     // no-wait
-		nil,               // args
-	)
+	deliveryChannel, err := handler.consume(amqpChannel, params)
 	if err != nil {
 		return aoserrors.Wrap(err)
 	}
@@ -554,6 +1008,37 @@ func (handler *AmqpHandler) setupReceiveConnection(
 	return nil
 }
 
+func (handler *AmqpHandler) consume(
+	amqpChannel consumerChannel, params cloudprotocol.ReceiveParams,
+) (<-chan amqp.Delivery, error) {
+	handler.messageSettingsMutex.RLock()
+	prefetchCount, manualAck := handler.prefetchCount, handler.manualAck
+	handler.messageSettingsMutex.RUnlock()
+
+	if prefetchCount > 0 {
+		if err := amqpChannel.Qos(prefetchCount, 0, false); err != nil {
+			return nil, aoserrors.Wrap(err)
+		}
+	}
+
+	log.WithFields(log.Fields{"prefetchCount": prefetchCount, "manualAck": manualAck}).Debug("Setup AMQP consumer")
+
+	deliveryChannel, err := amqpChannel.Consume(
+		params.Queue.Name, // queue
+		params.Consumer,   // consumer
+		!manualAck,        // auto-ack
+		params.Exclusive,  // exclusive
+		params.NoLocal,    // no-local
+		params.NoWait,     // no-wait
+		nil,               // args
+	)
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	return deliveryChannel, nil
+}
+
 func (handler *AmqpHandler) runReceiver(deliveryChannel <-chan amqp.Delivery, param cloudprotocol.ReceiveParams) {
 	log.Info("Start AMQP receiver")
 
@@ -580,39 +1065,112 @@ func (handler *AmqpHandler) runReceiver(deliveryChannel <-chan amqp.Delivery, pa
 				return
 			}
 
-			var incomingMsg cloudprotocol.ReceivedMessage
+			handler.processDelivery(delivery)
+		}
+	}
+}
 
-			if err := json.Unmarshal(delivery.Body, &incomingMsg); err != nil {
-				log.Errorf("Can't parse message header: %s", err)
-				continue
-			}
+func (handler *AmqpHandler) processDelivery(delivery amqp.Delivery) {
+	handler.messageSettingsMutex.RLock()
+	manualAck, dispatcher := handler.manualAck, handler.messageDispatcher
+	handler.messageSettingsMutex.RUnlock()
 
-			if incomingMsg.Header.Version != cloudprotocol.ProtocolVersion {
-				log.Errorf("Unsupported protocol version: %d", incomingMsg.Header.Version)
-				continue
-			}
+	if !delivery.Timestamp.IsZero() {
+		handler.clockMutex.Lock()
+		handler.cloudTime, handler.cloudTimeReceived = delivery.Timestamp, time.Now()
+		handler.clockMutex.Unlock()
+	}
 
-			messageTypeFunc, ok := messageMap[incomingMsg.Header.MessageType]
-			if !ok {
-				log.Warnf("AMQP unsupported message type: %s", incomingMsg.Header.MessageType)
-				continue
-			}
+	err := handler.dispatchDelivery(delivery, dispatcher)
+	if err != nil {
+		log.Errorf("Can't process incoming message: %v", err)
+	}
 
-			decodedData := messageTypeFunc()
+	if !manualAck {
+		return
+	}
 
-			log.Infof("AMQP receive message: %s", incomingMsg.Header.MessageType)
+	// Message which can't be processed is rejected without requeue to not receive it again
+	if err != nil {
+		if nackErr := delivery.Nack(false, false); nackErr != nil {
+			log.Errorf("Can't nack incoming message: %v", nackErr)
+		}
 
-			if err := handler.decodeData(incomingMsg.Data, decodedData); err != nil {
-				log.Errorf("Can't decode incoming message %s", err)
-				continue
-			}
+		return
+	}
+
+	if ackErr := delivery.Ack(false); ackErr != nil {
+		log.Errorf("Can't ack incoming message: %v", ackErr)
+	}
+}
+
+func (handler *AmqpHandler) dispatchDelivery(delivery amqp.Delivery, dispatcher MessageDispatcher) error {
+	var incomingMsg cloudprotocol.ReceivedMessage
+
+	codec := getContentTypeCodec(delivery.ContentType)
+
+	if err := codec.unmarshal(delivery.Body, &incomingMsg); err != nil {
+		return aoserrors.Errorf("can't parse message header: %v", err)
+	}
+
+	if incomingMsg.Header.Version != cloudprotocol.ProtocolVersion {
+		return aoserrors.Errorf("unsupported protocol version: %d", incomingMsg.Header.Version)
+	}
+
+	messageTypeFunc, ok := messageMap[incomingMsg.Header.MessageType]
+	if !ok {
+		handler.processUnknownMessage(incomingMsg)
+		return nil
+	}
+
+	decodedData := messageTypeFunc()
+
+	log.Infof("AMQP receive message: %s", incomingMsg.Header.MessageType)
+
+	if err := handler.decodeData(codec, incomingMsg.Data, decodedData); err != nil {
+		return aoserrors.Errorf("can't decode incoming message: %v", err)
+	}
+
+	if dispatcher == nil {
+		handler.MessageChannel <- decodedData
+		return nil
+	}
+
+	return aoserrors.Wrap(dispatcher(decodedData))
+}
+
+func (handler *AmqpHandler) processUnknownMessage(incomingMsg cloudprotocol.ReceivedMessage) {
+	handler.messageSettingsMutex.RLock()
+	logLevel, unknownHandler := handler.unknownMessageLogLevel, handler.unknownMessageHandler
+	handler.messageSettingsMutex.RUnlock()
+
+	log.WithField("messageType", incomingMsg.Header.MessageType).Log(logLevel, "AMQP unsupported message type")
+
+	if unknownHandler == nil {
+		return
+	}
+
+	var data []byte
+
+	if len(incomingMsg.Data) != 0 {
+		var err error
 
-			handler.MessageChannel <- decodedData
+		if data, err = handler.cryptoContext.DecryptMetadata(incomingMsg.Data); err != nil {
+			log.Errorf("Can't decrypt unknown message: %v", err)
+			return
 		}
 	}
+
+	defer func() {
+		if recoverErr := recover(); recoverErr != nil {
+			log.Errorf("Unknown message handler failed: %v", recoverErr)
+		}
+	}()
+
+	unknownHandler(UnknownMessage{MessageType: incomingMsg.Header.MessageType, Data: data})
 }
 
-func (handler *AmqpHandler) decodeData(data []byte, result interface{}) error {
+func (handler *AmqpHandler) decodeData(codec messageCodec, data []byte, result interface{}) error {
 	if len(data) == 0 {
 		return nil
 	}
@@ -622,8 +1180,8 @@ func (handler *AmqpHandler) decodeData(data []byte, result interface{}) error {
 		return aoserrors.Wrap(err)
 	}
 
-	if err = json.Unmarshal(decryptData, result); err != nil {
-		return aoserrors.Wrap(err)
+	if err = codec.unmarshal(decryptData, result); err != nil {
+		return err
 	}
 
 	desiredStatus, ok := result.(*cloudprotocol.DesiredStatus)
@@ -717,6 +1275,22 @@ func (handler *AmqpHandler) scheduleMessage(messageType string, data interface{}
 		return ErrNotConnected
 	}
 
+	if important {
+		message := PendingMessage{Message: handler.createCloudMessage(messageType, data)}
+
+		if ttl, ok := handler.messageTTLs[messageType]; ok {
+			message.Deadline = time.Now().Add(ttl)
+		}
+
+		if err := handler.pendingStore.Enqueue(message); err != nil {
+			return aoserrors.Wrap(err)
+		}
+
+		handler.notifyPendingStore()
+
+		return nil
+	}
+
 	select {
 	case handler.sendChannel <- handler.createCloudMessage(messageType, data):
 		return nil
@@ -726,6 +1300,186 @@ func (handler *AmqpHandler) scheduleMessage(messageType string, data interface{}
 	}
 }
 
+// scheduleMessages schedules not important messages at once: either all messages are scheduled or none of them.
+func (handler *AmqpHandler) scheduleMessages(messages []cloudprotocol.Message) error {
+	if !handler.isConnected {
+		return ErrNotConnected
+	}
+
+	if len(messages) > cap(handler.sendChannel) {
+		return aoserrors.Errorf("too many messages to schedule: %d", len(messages))
+	}
+
+	// Send channel is filled under handler lock only, so free space can't be taken by other senders meanwhile
+	if cap(handler.sendChannel)-len(handler.sendChannel) < len(messages) {
+		return ErrSendChannelFull
+	}
+
+	for _, message := range messages {
+		handler.sendChannel <- message
+	}
+
+	return nil
+}
+
+// getChunkDataSize returns size of unit status data which fits into chunk message. Chunk data is base64 encoded in
+// JSON message, so only 3/4 of space left after message envelope is used. It also covers CBOR byte string header.
+func (handler *AmqpHandler) getChunkDataSize() (int, error) {
+	envelope, err := handler.codec.marshal(handler.createCloudMessage(cloudprotocol.UnitStatusChunkType,
+		cloudprotocol.UnitStatusChunk{
+			CorrelationID: uuid.Nil.String(), Sequence: math.MaxUint64, Total: math.MaxUint64, Data: []byte{},
+		}))
+	if err != nil {
+		return 0, aoserrors.Wrap(err)
+	}
+
+	chunkDataSize := (handler.unitStatusChunkSize - len(envelope)) / 4 * 3
+	if chunkDataSize <= 0 {
+		return 0, aoserrors.Errorf("unit status chunk size %d is less than chunk envelope", handler.unitStatusChunkSize)
+	}
+
+	return chunkDataSize, nil
+}
+
+func (handler *AmqpHandler) notifyPendingStore() {
+	select {
+	case handler.pendingStoreChannel <- struct{}{}:
+
+	default:
+	}
+}
+
+// peekPendingMessage returns the oldest pending message dropping messages with expired TTL.
+func (handler *AmqpHandler) peekPendingMessage() (cloudprotocol.Message, bool, error) {
+	for {
+		message, ok, err := handler.pendingStore.Peek()
+		if err != nil || !ok {
+			return message.Message, ok, err
+		}
+
+		if message.Deadline.IsZero() || time.Now().Before(message.Deadline) {
+			return message.Message, true, nil
+		}
+
+		log.WithFields(log.Fields{
+			"messageType": message.Header.MessageType, "deadline": message.Deadline,
+		}).Warn("Drop expired message")
+
+		if err = handler.pendingStore.Dequeue(); err != nil {
+			return message.Message, false, aoserrors.Wrap(err)
+		}
+
+		handler.expiredMessages.Add(1)
+	}
+}
+
+// setPendingMessage starts sending of the message. Other messages are not taken until it is processed.
+func (handler *AmqpHandler) setPendingMessage(message cloudprotocol.Message) {
+	handler.sendTry = 0
+	handler.pendingChannel <- message
+}
+
+// getStoredMessage returns the oldest stored message and marks it as pending.
+func (handler *AmqpHandler) getStoredMessage() (cloudprotocol.Message, bool) {
+	message, ok, err := handler.peekPendingMessage()
+	if err != nil {
+		log.Errorf("Can't get pending message: %v", err)
+
+		return message, false
+	}
+
+	if ok {
+		handler.pendingFromStore = true
+	}
+
+	return message, ok
+}
+
+// publishPendingMessage sends pending message and waits for its confirmation. Returns false if the message is not
+// confirmed and should be resent.
+func (handler *AmqpHandler) publishPendingMessage(
+	message cloudprotocol.Message, amqpChannel *amqp.Channel, params cloudprotocol.SendParams,
+	confirmChannel <-chan amqp.Confirmation,
+) (bool, error) {
+	if err := handler.sendMessage(message, amqpChannel, params); err != nil {
+		return false, err
+	}
+
+	if confirm, ok := <-confirmChannel; !ok || !confirm.Ack {
+		return false, nil
+	}
+
+	handler.releasePendingMessage()
+
+	return true, nil
+}
+
+// releasePendingMessage removes processed message from the pending store and schedules next stored message.
+func (handler *AmqpHandler) releasePendingMessage() {
+	if !handler.pendingFromStore {
+		return
+	}
+
+	handler.pendingFromStore = false
+
+	if err := handler.pendingStore.Dequeue(); err != nil {
+		log.Errorf("Can't remove pending message: %v", err)
+	}
+
+	handler.notifyPendingStore()
+}
+
+// processSendError handles message which can't be sent. Stored message is kept in the pending store to be resent on
+// next connection unless it can't be encoded. Returns false if the stored message is kept.
+func (handler *AmqpHandler) processSendError(err error) bool {
+	if handler.pendingFromStore && errors.Is(err, errSendMaxTry) {
+		handler.pendingFromStore = false
+
+		return false
+	}
+
+	handler.releasePendingMessage()
+
+	return true
+}
+
+func (handler *AmqpHandler) notifyMonitoring() {
+	select {
+	case handler.monitoringChannel <- struct{}{}:
+
+	default:
+	}
+}
+
+func (handler *AmqpHandler) getMonitoringMessage() (message cloudprotocol.Message, ok bool) {
+	// Main send path has priority over monitoring data
+	if len(handler.sendChannel) > 0 {
+		handler.notifyMonitoring()
+
+		return message, false
+	}
+
+	return handler.popMonitoringMessage()
+}
+
+func (handler *AmqpHandler) popMonitoringMessage() (message cloudprotocol.Message, ok bool) {
+	handler.monitoringMutex.Lock()
+	defer handler.monitoringMutex.Unlock()
+
+	if len(handler.monitoringBuffer) == 0 {
+		return message, false
+	}
+
+	message = handler.createCloudMessage(cloudprotocol.MonitoringDataType, handler.monitoringBuffer[0])
+	handler.monitoringBuffer = handler.monitoringBuffer[1:]
+
+	if len(handler.monitoringBuffer) > 0 {
+		handler.notifyMonitoring()
+	}
+
+	return message, true
+}
+
 func isMessageImportant(messageType string) bool {
 	for _, importantType := range importantMessages {
 		if messageType == importantType {
@@ -747,19 +1501,26 @@ func getMessageDataForLog(messageType string, data []byte) string {
 func (handler *AmqpHandler) sendMessage(
 	message cloudprotocol.Message, amqpChannel *amqp.Channel, params cloudprotocol.SendParams,
 ) error {
-	data, err := json.Marshal(message)
+	data, err := handler.codec.marshal(message)
 	if err != nil {
-		return aoserrors.Wrap(err)
+		return err
+	}
+
+	logFields := log.Fields{"messageType": message.Header.MessageType, "size": len(data)}
+
+	// Binary encoded data is not logged
+	if _, ok := handler.codec.(jsonCodec); ok {
+		logFields = log.Fields{"data": getMessageDataForLog(message.Header.MessageType, data)}
 	}
 
 	if handler.sendTry > 1 {
-		log.WithField("data", getMessageDataForLog(message.Header.MessageType, data)).Debug("AMQP retry message")
+		log.WithFields(logFields).Debug("AMQP retry message")
 	} else {
-		log.WithField("data", getMessageDataForLog(message.Header.MessageType, data)).Debug("AMQP send message")
+		log.WithFields(logFields).Debug("AMQP send message")
 	}
 
 	if handler.sendTry++; handler.sendTry > sendMaxTry {
-		return aoserrors.New("sending message max try reached")
+		return aoserrors.Wrap(errSendMaxTry)
 	}
 
 	if err := amqpChannel.Publish(
@@ -768,7 +1529,7 @@ func (handler *AmqpHandler) sendMessage(
 		params.Mandatory,     // mandatory
 		params.Immediate,     // immediate
 		amqp.Publishing{
-			ContentType:  "application/json",
+			ContentType:  handler.codec.contentType(),
 			DeliveryMode: amqp.Persistent,
 			UserId:       params.User,
 			Body:         data,
@@ -779,3 +1540,35 @@ func (handler *AmqpHandler) sendMessage(
 
 	return nil
 }
+
+// splitUnitStatus splits JSON encoded unit status into chunks. Returns nil if unit status fits into one chunk.
+func splitUnitStatus(unitStatus cloudprotocol.UnitStatus, chunkSize int) ([]cloudprotocol.UnitStatusChunk, error) {
+	data, err := json.Marshal(unitStatus)
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	if len(data) <= chunkSize {
+		return nil, nil
+	}
+
+	total := (len(data) + chunkSize - 1) / chunkSize
+	correlationID := uuid.New().String()
+	chunks := make([]cloudprotocol.UnitStatusChunk, 0, total)
+
+	for i := 0; i < total; i++ {
+		end := (i + 1) * chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunks = append(chunks, cloudprotocol.UnitStatusChunk{
+			CorrelationID: correlationID,
+			Sequence:      uint64(i + 1),
+			Total:         uint64(total),
+			Data:          data[i*chunkSize : end],
+		})
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,873 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqphandler
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/aosedge/aos_common/aostypes"
+	"github.com/aosedge/aos_common/api/cloudprotocol"
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+
+	"github.com/aosedge/aos_communicationmanager/utils/health"
+	"github.com/aosedge/aos_communicationmanager/utils/loglevel"
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+type testConsumerChannel struct {
+	prefetchCount int
+	autoAck       bool
+}
+
+type testAcknowledger struct {
+	acked  int
+	nacked int
+}
+
+type testCryptoContext struct{}
+
+type testResolver struct {
+	failures int
+	attempts int
+}
+
+type testLogHook struct {
+	sync.Mutex
+
+	entries []log.Entry
+}
+
+/***********************************************************************************************************************
+ * Tests
+ **********************************************************************************************************************/
+
+func TestConsumerQoS(t *testing.T) {
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	handler.SetConsumerQoS(5, true)
+
+	amqpChannel := &testConsumerChannel{autoAck: true}
+
+	if _, err = handler.consume(amqpChannel, cloudprotocol.ReceiveParams{}); err != nil {
+		t.Fatalf("Can't consume: %v", err)
+	}
+
+	if amqpChannel.prefetchCount != 5 {
+		t.Errorf("Wrong prefetch count: %d", amqpChannel.prefetchCount)
+	}
+
+	if amqpChannel.autoAck {
+		t.Error("Auto ack should be disabled")
+	}
+
+	body, err := json.Marshal(cloudprotocol.ReceivedMessage{
+		Header: cloudprotocol.MessageHeader{
+			MessageType: cloudprotocol.DesiredStatusType, Version: cloudprotocol.ProtocolVersion,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal message: %v", err)
+	}
+
+	acknowledger := &testAcknowledger{}
+	dispatched := 0
+
+	handler.SetMessageDispatcher(func(message Message) error {
+		dispatched++
+
+		if acknowledger.acked != 0 {
+			t.Error("Message is acked before dispatching finished")
+		}
+
+		if _, ok := message.(*cloudprotocol.DesiredStatus); !ok {
+			t.Errorf("Wrong dispatched message: %v", message)
+		}
+
+		return nil
+	})
+
+	handler.processDelivery(amqp.Delivery{Acknowledger: acknowledger, Body: body})
+
+	if dispatched != 1 || acknowledger.acked != 1 || acknowledger.nacked != 0 {
+		t.Errorf("Wrong ack state: dispatched %d, acked %d, nacked %d",
+			dispatched, acknowledger.acked, acknowledger.nacked)
+	}
+
+	// Failed message should be rejected
+
+	handler.SetMessageDispatcher(func(message Message) error {
+		return aoserrors.New("process error")
+	})
+
+	handler.processDelivery(amqp.Delivery{Acknowledger: acknowledger, Body: body})
+
+	if acknowledger.acked != 1 || acknowledger.nacked != 1 {
+		t.Errorf("Wrong ack state: acked %d, nacked %d", acknowledger.acked, acknowledger.nacked)
+	}
+}
+
+func TestFilePendingStore(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "pending.json")
+
+	store, err := NewFilePendingStore(fileName, 2)
+	if err != nil {
+		t.Fatalf("Can't create pending store: %v", err)
+	}
+
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	messages := []PendingMessage{
+		{Message: handler.createCloudMessage(cloudprotocol.AlertsType, cloudprotocol.Alerts{})},
+		{
+			Message: handler.createCloudMessage(
+				cloudprotocol.StateRequestType, cloudprotocol.StateRequest{Default: true}),
+			Deadline: time.Now().Add(time.Hour),
+		},
+	}
+
+	for _, message := range messages {
+		if err = store.Enqueue(message); err != nil {
+			t.Fatalf("Can't enqueue message: %v", err)
+		}
+	}
+
+	if err = store.Enqueue(messages[0]); !errors.Is(err, ErrSendChannelFull) {
+		t.Errorf("Wrong enqueue error: %v", err)
+	}
+
+	// Messages should be reloaded in the same order after restart
+
+	for _, expectedMessage := range messages {
+		if store, err = NewFilePendingStore(fileName, 2); err != nil {
+			t.Fatalf("Can't create pending store: %v", err)
+		}
+
+		message, ok, err := store.Peek()
+		if err != nil || !ok {
+			t.Fatalf("Can't peek message: %v", err)
+		}
+
+		expectedData, _ := json.Marshal(expectedMessage)
+		data, _ := json.Marshal(message)
+
+		if string(data) != string(expectedData) {
+			t.Errorf("Wrong reloaded message: %s", data)
+		}
+
+		if err = store.Dequeue(); err != nil {
+			t.Fatalf("Can't dequeue message: %v", err)
+		}
+	}
+
+	if _, ok, _ := store.Peek(); ok {
+		t.Error("Store should be empty")
+	}
+}
+
+func TestExpiredPendingMessages(t *testing.T) {
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	const alertsTTL = 100 * time.Millisecond
+
+	handler.SetMessageTTL(cloudprotocol.AlertsType, alertsTTL)
+
+	// Messages are queued while disconnected
+
+	if err = handler.SendAlerts(cloudprotocol.Alerts{}); err != nil {
+		t.Fatalf("Can't send alerts: %v", err)
+	}
+
+	if err = handler.SendInstanceStateRequest(cloudprotocol.StateRequest{Default: true}); err != nil {
+		t.Fatalf("Can't send state request: %v", err)
+	}
+
+	time.Sleep(2 * alertsTTL)
+
+	// On reconnect expired alerts should be dropped and message without TTL should be sent
+
+	message, ok, err := handler.peekPendingMessage()
+	if err != nil || !ok {
+		t.Fatalf("Can't get pending message: %v", err)
+	}
+
+	if message.Header.MessageType != cloudprotocol.StateRequestType {
+		t.Errorf("Wrong pending message type: %s", message.Header.MessageType)
+	}
+
+	if count := handler.GetExpiredMessagesCount(); count != 1 {
+		t.Errorf("Wrong expired messages count: %d", count)
+	}
+}
+
+func TestPendingMessageSendFailure(t *testing.T) {
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	if err = handler.SendInstanceStateRequest(cloudprotocol.StateRequest{Default: true}); err != nil {
+		t.Fatalf("Can't send state request: %v", err)
+	}
+
+	message, ok, err := handler.peekPendingMessage()
+	if err != nil || !ok {
+		t.Fatalf("Can't get pending message: %v", err)
+	}
+
+	handler.pendingFromStore = true
+	handler.sendTry = sendMaxTry
+
+	// Send retries are exhausted: message should stay in the store to be resent on next connection
+
+	if err = handler.sendMessage(message, nil, cloudprotocol.SendParams{}); !errors.Is(err, errSendMaxTry) {
+		t.Fatalf("Wrong send error: %v", err)
+	}
+
+	if handler.processSendError(err) {
+		t.Error("Stored message should be kept")
+	}
+
+	if handler.pendingFromStore {
+		t.Error("Stored message should not be marked as pending")
+	}
+
+	if message, ok, err = handler.peekPendingMessage(); err != nil || !ok {
+		t.Fatalf("Can't get pending message: %v", err)
+	}
+
+	if message.Header.MessageType != cloudprotocol.StateRequestType {
+		t.Errorf("Wrong pending message type: %s", message.Header.MessageType)
+	}
+}
+
+func TestDiscoveryDNSRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		response, _ := json.Marshal(cloudprotocol.ServiceDiscoveryResponse{
+			Connection: cloudprotocol.ConnectionInfo{SendParams: cloudprotocol.SendParams{Host: "amqpHost"}},
+		})
+
+		_, _ = w.Write(response)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Can't get server port: %v", err)
+	}
+
+	discoveryURL := "http://localhost:" + port
+
+	savedResolver, savedDelay := discoveryResolver, discoveryDNSRetryDelay
+	defer func() { discoveryResolver, discoveryDNSRetryDelay = savedResolver, savedDelay }()
+
+	discoveryDNSRetryDelay = 10 * time.Millisecond
+
+	// Resolution fails twice then succeeds
+
+	resolver := &testResolver{failures: 2}
+	discoveryResolver = resolver
+
+	info, _, err := getConnectionInfo(context.Background(), discoveryURL, cloudprotocol.Message{}, nil)
+	if err != nil {
+		t.Fatalf("Can't get connection info: %v", err)
+	}
+
+	if info.SendParams.Host != "amqpHost" {
+		t.Errorf("Wrong send host: %s", info.SendParams.Host)
+	}
+
+	if resolver.attempts != 3 {
+		t.Errorf("Wrong resolve attempts: %d", resolver.attempts)
+	}
+
+	// Permanent resolution failure
+
+	resolver = &testResolver{failures: math.MaxInt}
+	discoveryResolver = resolver
+
+	if _, _, err = getConnectionInfo(
+		context.Background(), discoveryURL, cloudprotocol.Message{}, nil); !errors.Is(err, ErrDiscoveryResolve) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if resolver.attempts != discoveryDNSMaxAttempts {
+		t.Errorf("Wrong resolve attempts: %d", resolver.attempts)
+	}
+
+	// Server failure
+
+	discoveryResolver = &testResolver{}
+
+	_, _, err = getConnectionInfo(context.Background(), discoveryURL+"/fail", cloudprotocol.Message{}, nil)
+	if !errors.Is(err, ErrDiscoveryServer) || errors.Is(err, ErrDiscoveryResolve) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	if _, ok := handler.GetClockSkew(); ok {
+		t.Error("Clock skew should be unknown")
+	}
+
+	handler.SetMessageDispatcher(func(message Message) error { return nil })
+
+	handler.processDelivery(amqp.Delivery{
+		Acknowledger: &testAcknowledger{}, Body: []byte("{}"), Timestamp: time.Now().Add(-time.Hour),
+	})
+
+	skew, ok := handler.GetClockSkew()
+	if !ok {
+		t.Fatal("Clock skew should be known")
+	}
+
+	if skew < time.Hour-time.Minute || skew > time.Hour+time.Minute {
+		t.Errorf("Wrong clock skew: %v", skew)
+	}
+}
+
+func TestInsecureConnect(t *testing.T) {
+	hook := &testLogHook{}
+
+	log.AddHook(hook)
+
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	aggregator := health.New()
+
+	aggregator.Register("amqp", handler)
+
+	if status := aggregator.GetStatus(); status.Insecure {
+		t.Error("Health status should be secure")
+	}
+
+	// Service discovery is not available, only insecure mode handling is checked here
+	if err := handler.Connect(&testCryptoContext{}, "http://localhost:1", "systemID", true); err == nil {
+		t.Error("Error expected")
+	}
+
+	if !handler.IsInsecure() {
+		t.Error("AMQP handler should be insecure")
+	}
+
+	if status := aggregator.GetStatus(); !status.Insecure ||
+		!reflect.DeepEqual(status.InsecureComponents, []string{"amqp"}) {
+		t.Errorf("Wrong health status: %v", status)
+	}
+
+	if !hook.hasInsecureWarning("amqp") {
+		t.Error("Insecure warning is not logged")
+	}
+}
+
+func TestSetLogLevelMessage(t *testing.T) {
+	defer log.SetLevel(log.GetLevel())
+
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	handler.SetMessageDispatcher(func(message Message) error {
+		setLogLevel, ok := message.(*cloudprotocol.SetLogLevel)
+		if !ok {
+			return aoserrors.Errorf("unexpected message: %v", message)
+		}
+
+		return loglevel.SetLevel(setLogLevel.Level)
+	})
+
+	handler.cryptoContext = &testCryptoContext{}
+
+	for _, level := range []log.Level{log.ErrorLevel, log.TraceLevel} {
+		data, err := json.Marshal(cloudprotocol.SetLogLevel{Level: level.String()})
+		if err != nil {
+			t.Fatalf("Can't marshal data: %v", err)
+		}
+
+		body, err := json.Marshal(cloudprotocol.ReceivedMessage{
+			Header: cloudprotocol.MessageHeader{
+				Version: cloudprotocol.ProtocolVersion, MessageType: cloudprotocol.SetLogLevelType,
+			},
+			Data: data,
+		})
+		if err != nil {
+			t.Fatalf("Can't marshal message: %v", err)
+		}
+
+		if err = handler.dispatchDelivery(amqp.Delivery{Body: body}, handler.messageDispatcher); err != nil {
+			t.Fatalf("Can't dispatch message: %v", err)
+		}
+
+		if log.GetLevel() != level {
+			t.Errorf("Wrong log level: %v", log.GetLevel())
+		}
+	}
+}
+
+func TestSplitUnitStatus(t *testing.T) {
+	unitStatus := cloudprotocol.UnitStatus{}
+
+	for i := 0; i < 100; i++ {
+		unitStatus.Services = append(unitStatus.Services, cloudprotocol.ServiceStatus{
+			ID: "service" + strconv.Itoa(i), AosVersion: 1, Status: cloudprotocol.InstalledStatus,
+		})
+	}
+
+	data, err := json.Marshal(unitStatus)
+	if err != nil {
+		t.Fatalf("Can't marshal unit status: %v", err)
+	}
+
+	chunkSize := 1024
+
+	// Small unit status is sent as single message
+
+	if chunks, err := splitUnitStatus(unitStatus, len(data)); err != nil || chunks != nil {
+		t.Errorf("Unit status should not be split: %v", err)
+	}
+
+	chunks, err := splitUnitStatus(unitStatus, chunkSize)
+	if err != nil {
+		t.Fatalf("Can't split unit status: %v", err)
+	}
+
+	expectedTotal := (len(data) + chunkSize - 1) / chunkSize
+
+	if len(chunks) != expectedTotal {
+		t.Fatalf("Wrong chunks count: %d, expected %d", len(chunks), expectedTotal)
+	}
+
+	var reassembled []byte
+
+	for i, chunk := range chunks {
+		if chunk.CorrelationID == "" || chunk.CorrelationID != chunks[0].CorrelationID {
+			t.Errorf("Wrong chunk correlation ID: %s", chunk.CorrelationID)
+		}
+
+		if chunk.Sequence != uint64(i+1) || chunk.Total != uint64(expectedTotal) {
+			t.Errorf("Wrong chunk sequence: %d/%d", chunk.Sequence, chunk.Total)
+		}
+
+		if len(chunk.Data) > chunkSize {
+			t.Errorf("Wrong chunk size: %d", len(chunk.Data))
+		}
+
+		reassembled = append(reassembled, chunk.Data...)
+	}
+
+	var reassembledStatus cloudprotocol.UnitStatus
+
+	if err = json.Unmarshal(reassembled, &reassembledStatus); err != nil {
+		t.Fatalf("Can't unmarshal reassembled unit status: %v", err)
+	}
+
+	if !reflect.DeepEqual(reassembledStatus, unitStatus) {
+		t.Error("Wrong reassembled unit status")
+	}
+}
+
+func TestSendUnitStatusChunks(t *testing.T) {
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	const chunkSize = 1024
+
+	handler.SetUnitStatusChunkSize(chunkSize)
+	handler.isConnected = true
+
+	unitStatus := cloudprotocol.UnitStatus{}
+
+	for i := 0; i < 100; i++ {
+		unitStatus.Services = append(unitStatus.Services, cloudprotocol.ServiceStatus{
+			ID: "service" + strconv.Itoa(i), AosVersion: 1, Status: cloudprotocol.InstalledStatus,
+		})
+	}
+
+	if err = handler.SendUnitStatus(unitStatus); err != nil {
+		t.Fatalf("Can't send unit status: %v", err)
+	}
+
+	if len(handler.sendChannel) < 2 {
+		t.Fatalf("Unit status should be split: %d", len(handler.sendChannel))
+	}
+
+	// Each encoded chunk message should fit into chunk size
+
+	var reassembled []byte
+
+	for len(handler.sendChannel) > 0 {
+		message := <-handler.sendChannel
+
+		data, err := handler.codec.marshal(message)
+		if err != nil {
+			t.Fatalf("Can't marshal message: %v", err)
+		}
+
+		if len(data) > chunkSize {
+			t.Errorf("Wrong chunk message size: %d", len(data))
+		}
+
+		chunk, ok := message.Data.(cloudprotocol.UnitStatusChunk)
+		if !ok {
+			t.Fatalf("Wrong message data: %T", message.Data)
+		}
+
+		reassembled = append(reassembled, chunk.Data...)
+	}
+
+	var reassembledStatus cloudprotocol.UnitStatus
+
+	if err = json.Unmarshal(reassembled, &reassembledStatus); err != nil {
+		t.Fatalf("Can't unmarshal reassembled unit status: %v", err)
+	}
+
+	if !reflect.DeepEqual(reassembledStatus, unitStatus) {
+		t.Error("Wrong reassembled unit status")
+	}
+
+	// Chunks are not scheduled partially if send channel has no space for all of them
+
+	for i := 0; i < sendChannelSize-1; i++ {
+		handler.sendChannel <- cloudprotocol.Message{}
+	}
+
+	if err = handler.SendUnitStatus(unitStatus); !errors.Is(err, ErrSendChannelFull) {
+		t.Errorf("Wrong send error: %v", err)
+	}
+
+	if len(handler.sendChannel) != sendChannelSize-1 {
+		t.Errorf("Wrong send channel length: %d", len(handler.sendChannel))
+	}
+}
+
+func TestCBORBinaryFields(t *testing.T) {
+	sha256 := []byte{0x00, 0xff, 0x10, 0x80, 0x7f, 0x01, 0xfe, 0x00}
+
+	desiredStatus := cloudprotocol.DesiredStatus{
+		UnitConfig: json.RawMessage(`{"formatVersion":1,"vendorVersion":"1.0.0"}`),
+		Components: []cloudprotocol.ComponentInfo{{
+			VersionInfo: aostypes.VersionInfo{AosVersion: 1, VendorVersion: "1.0"},
+			ID:          "component0",
+			DecryptDataStruct: cloudprotocol.DecryptDataStruct{
+				URLs: []string{"https://example.com/component0"}, Sha256: sha256, Size: 1024,
+				DecryptionInfo: &cloudprotocol.DecryptionInfo{
+					BlockAlg: "AES256/CBC/pkcs7", BlockIv: []byte{0x01, 0x02}, BlockKey: []byte{0x03, 0x04},
+				},
+			},
+		}},
+		FOTASchedule: cloudprotocol.ScheduleRule{
+			TTL: 3600, Type: "timetable",
+			Timetable: []cloudprotocol.TimetableEntry{{
+				DayOfWeek: 1,
+				TimeSlots: []cloudprotocol.TimeSlot{{
+					Start:  aostypes.Time{Time: time.Date(0, 1, 1, 10, 0, 0, 0, time.Local)},
+					Finish: aostypes.Time{Time: time.Date(0, 1, 1, 12, 30, 0, 0, time.Local)},
+				}},
+			}},
+		},
+	}
+
+	cborData, err := cborCodec{}.marshal(desiredStatus)
+	if err != nil {
+		t.Fatalf("Can't marshal CBOR: %v", err)
+	}
+
+	// Binary field should be encoded as CBOR byte string, not as base64 text
+
+	if !bytes.Contains(cborData, append([]byte{0x40 | byte(len(sha256))}, sha256...)) {
+		t.Error("Binary field is not encoded as CBOR byte string")
+	}
+
+	if bytes.Contains(cborData, []byte(base64.StdEncoding.EncodeToString(sha256))) {
+		t.Error("Binary field is encoded as base64")
+	}
+
+	var decodedStatus cloudprotocol.DesiredStatus
+
+	if err = (cborCodec{}).unmarshal(cborData, &decodedStatus); err != nil {
+		t.Fatalf("Can't unmarshal CBOR: %v", err)
+	}
+
+	if !reflect.DeepEqual(decodedStatus, desiredStatus) {
+		t.Errorf("Wrong CBOR decoded desired status: %v", decodedStatus)
+	}
+
+	// Binary data of received message should be decoded from CBOR byte string
+
+	body, err := cborCodec{}.marshal(cloudprotocol.ReceivedMessage{
+		Header: cloudprotocol.MessageHeader{Version: cloudprotocol.ProtocolVersion},
+		Data:   sha256,
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal CBOR: %v", err)
+	}
+
+	var receivedMessage cloudprotocol.ReceivedMessage
+
+	if err = (cborCodec{}).unmarshal(body, &receivedMessage); err != nil {
+		t.Fatalf("Can't unmarshal CBOR: %v", err)
+	}
+
+	if !bytes.Equal(receivedMessage.Data, sha256) {
+		t.Errorf("Wrong received message data: %v", receivedMessage.Data)
+	}
+
+	// Time should be encoded with nanoseconds precision
+
+	renewCert := cloudprotocol.RenewCertData{
+		Type: "online", Serial: "serial", ValidTill: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+	}
+
+	if cborData, err = (cborCodec{}).marshal(renewCert); err != nil {
+		t.Fatalf("Can't marshal CBOR: %v", err)
+	}
+
+	var decodedRenewCert cloudprotocol.RenewCertData
+
+	if err = (cborCodec{}).unmarshal(cborData, &decodedRenewCert); err != nil {
+		t.Fatalf("Can't unmarshal CBOR: %v", err)
+	}
+
+	if !decodedRenewCert.ValidTill.Equal(renewCert.ValidTill) {
+		t.Errorf("Wrong decoded time: %v", decodedRenewCert.ValidTill)
+	}
+}
+
+func TestCBORMessageEncoding(t *testing.T) {
+	unitStatus := cloudprotocol.UnitStatus{
+		UnitConfig: []cloudprotocol.UnitConfigStatus{{VendorVersion: "1.0.0", Status: cloudprotocol.InstalledStatus}},
+		Components: []cloudprotocol.ComponentStatus{
+			{ID: "component0", AosVersion: math.MaxUint64, VendorVersion: "2.0", Status: cloudprotocol.InstalledStatus},
+		},
+		Instances: []cloudprotocol.InstanceStatus{{
+			InstanceIdent: aostypes.InstanceIdent{ServiceID: "service0", SubjectID: "subject0", Instance: 1},
+			AosVersion:    1, RunState: cloudprotocol.InstanceStateFailed, NodeID: "node0",
+			ErrorInfo: &cloudprotocol.ErrorInfo{AosCode: 1, ExitCode: -1, Message: "unicode message: ✓"},
+		}},
+		UnitSubjects:  []string{"subject0"},
+		CorrelationID: "correlationID",
+	}
+
+	for i := 0; i < 100; i++ {
+		unitStatus.Services = append(unitStatus.Services, cloudprotocol.ServiceStatus{
+			ID: "service" + strconv.Itoa(i), AosVersion: uint64(i * 1000), Status: cloudprotocol.InstalledStatus,
+		})
+	}
+
+	jsonData, err := jsonCodec{}.marshal(unitStatus)
+	if err != nil {
+		t.Fatalf("Can't marshal JSON: %v", err)
+	}
+
+	cborData, err := cborCodec{}.marshal(unitStatus)
+	if err != nil {
+		t.Fatalf("Can't marshal CBOR: %v", err)
+	}
+
+	if len(cborData) >= len(jsonData) {
+		t.Errorf("CBOR data size %d is not less than JSON data size %d", len(cborData), len(jsonData))
+	}
+
+	var jsonStatus, cborStatus cloudprotocol.UnitStatus
+
+	if err = (jsonCodec{}).unmarshal(jsonData, &jsonStatus); err != nil {
+		t.Fatalf("Can't unmarshal JSON: %v", err)
+	}
+
+	if err = (cborCodec{}).unmarshal(cborData, &cborStatus); err != nil {
+		t.Fatalf("Can't unmarshal CBOR: %v", err)
+	}
+
+	if !reflect.DeepEqual(cborStatus, jsonStatus) || !reflect.DeepEqual(cborStatus, unitStatus) {
+		t.Errorf("Wrong CBOR decoded unit status: %v", cborStatus)
+	}
+
+	if err = (cborCodec{}).unmarshal(cborData[:len(cborData)-1], &cborStatus); err == nil {
+		t.Error("Error expected for truncated CBOR data")
+	}
+
+	// Received CBOR messages are decoded by registered message factories
+
+	handler, err := New()
+	if err != nil {
+		t.Fatalf("Can't create AMQP handler: %v", err)
+	}
+
+	if err = handler.SetMessageEncoding("xml"); err == nil {
+		t.Error("Error expected for unsupported encoding")
+	}
+
+	if err = handler.SetMessageEncoding(EncodingCBOR); err != nil {
+		t.Fatalf("Can't set message encoding: %v", err)
+	}
+
+	handler.cryptoContext = &testCryptoContext{}
+
+	data, err := handler.codec.marshal(cloudprotocol.SetLogLevel{Level: "debug"})
+	if err != nil {
+		t.Fatalf("Can't marshal data: %v", err)
+	}
+
+	body, err := handler.codec.marshal(cloudprotocol.ReceivedMessage{
+		Header: cloudprotocol.MessageHeader{
+			Version: cloudprotocol.ProtocolVersion, MessageType: cloudprotocol.SetLogLevelType,
+		},
+		Data: data,
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal message: %v", err)
+	}
+
+	var receivedMessage Message
+
+	if err = handler.dispatchDelivery(amqp.Delivery{ContentType: handler.codec.contentType(), Body: body},
+		func(message Message) error {
+			receivedMessage = message
+
+			return nil
+		}); err != nil {
+		t.Fatalf("Can't dispatch message: %v", err)
+	}
+
+	if !reflect.DeepEqual(receivedMessage, &cloudprotocol.SetLogLevel{Level: "debug"}) {
+		t.Errorf("Wrong received message: %v", receivedMessage)
+	}
+}
+
+/***********************************************************************************************************************
+ * Interfaces
+ **********************************************************************************************************************/
+
+func (amqpChannel *testConsumerChannel) Qos(prefetchCount, prefetchSize int, global bool) error {
+	amqpChannel.prefetchCount = prefetchCount
+
+	return nil
+}
+
+func (amqpChannel *testConsumerChannel) Consume(
+	queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table,
+) (<-chan amqp.Delivery, error) {
+	amqpChannel.autoAck = autoAck
+
+	return make(chan amqp.Delivery), nil
+}
+
+func (acknowledger *testAcknowledger) Ack(tag uint64, multiple bool) error {
+	acknowledger.acked++
+
+	return nil
+}
+
+func (acknowledger *testAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	acknowledger.nacked++
+
+	return nil
+}
+
+func (acknowledger *testAcknowledger) Reject(tag uint64, requeue bool) error {
+	return nil
+}
+
+func (context *testCryptoContext) GetTLSConfig() (*tls.Config, error) {
+	return &tls.Config{MinVersion: tls.VersionTLS12}, nil
+}
+
+func (context *testCryptoContext) DecryptMetadata(input []byte) ([]byte, error) {
+	return input, nil
+}
+
+func (resolver *testResolver) LookupHost(ctx context.Context, host string) (addrs []string, err error) {
+	resolver.attempts++
+
+	if resolver.attempts <= resolver.failures {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return []string{"127.0.0.1"}, nil
+}
+
+func (hook *testLogHook) Levels() []log.Level {
+	return []log.Level{log.WarnLevel}
+}
+
+func (hook *testLogHook) Fire(entry *log.Entry) error {
+	hook.Lock()
+	defer hook.Unlock()
+
+	hook.entries = append(hook.entries, *entry)
+
+	return nil
+}
+
+func (hook *testLogHook) hasInsecureWarning(component string) bool {
+	hook.Lock()
+	defer hook.Unlock()
+
+	for _, entry := range hook.entries {
+		if entry.Data["component"] == component && entry.Data["insecure"] == true {
+			return true
+		}
+	}
+
+	return false
+}
@@ -23,9 +23,13 @@ import (
 	"errors"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path"
 	"reflect"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -354,6 +358,64 @@ func TestReceiveMessages(t *testing.T) {
 	}
 }
 
+func TestUnknownMessageHandling(t *testing.T) {
+	const unknownMessageType = "unknownMessage"
+
+	type customMessage struct {
+		Value string `json:"value"`
+	}
+
+	cryptoContext := &testCryptoContext{}
+
+	amqpHandler, err := amqphandler.New()
+	if err != nil {
+		t.Fatalf("Can't create amqp: %v", err)
+	}
+	defer amqpHandler.Close()
+
+	unknownMessages := make(chan amqphandler.UnknownMessage, 1)
+
+	amqpHandler.SetUnknownMessageHandling(log.DebugLevel, func(message amqphandler.UnknownMessage) {
+		unknownMessages <- message
+	})
+
+	if err := amqpHandler.Connect(cryptoContext, serviceDiscoveryURL, systemID, true); err != nil {
+		t.Fatalf("Can't establish connection: %v", err)
+	}
+
+	// Unknown message should be forwarded to unknown message handler
+
+	expectedMessage := &customMessage{Value: "custom"}
+	cryptoContext.currentMessage = expectedMessage
+
+	if err = sendCloudMessage(unknownMessageType, expectedMessage); err != nil {
+		t.Fatalf("Can't send message: %v", err)
+	}
+
+	select {
+	case unknownMessage := <-unknownMessages:
+		if unknownMessage.MessageType != unknownMessageType {
+			t.Errorf("Wrong unknown message type: %s", unknownMessage.MessageType)
+		}
+
+		var receivedData customMessage
+
+		if err := json.Unmarshal(unknownMessage.Data, &receivedData); err != nil {
+			t.Errorf("Can't parse unknown message data: %v", err)
+		}
+
+		if receivedData != *expectedMessage {
+			t.Errorf("Wrong unknown message data: %v", receivedData)
+		}
+
+	case receiveMessage := <-amqpHandler.MessageChannel:
+		t.Errorf("Unexpected message received: %v", receiveMessage)
+
+	case <-time.After(5 * time.Second):
+		t.Error("Waiting unknown message timeout")
+	}
+}
+
 func TestSendMessages(t *testing.T) {
 	cryptoContext := &testCryptoContext{}
 
@@ -521,6 +583,11 @@ func TestSendMessages(t *testing.T) {
 		},
 	}
 
+	newServices := cloudprotocol.NewServices{
+		CorrelationID: "correlation1",
+		Services:      []cloudprotocol.NewService{{ServiceID: "service1", NumInstances: 2}},
+	}
+
 	testData := []messageDesc{
 		{
 			call: func() error {
@@ -554,6 +621,22 @@ func TestSendMessages(t *testing.T) {
 				return &cloudprotocol.UnitStatus{}
 			},
 		},
+		{
+			call: func() error {
+				return aoserrors.Wrap(amqpHandler.SendNewServices(newServices))
+			},
+			data: cloudprotocol.Message{
+				Header: cloudprotocol.MessageHeader{
+					MessageType: cloudprotocol.NewServicesType,
+					SystemID:    systemID,
+					Version:     cloudprotocol.ProtocolVersion,
+				},
+				Data: &newServices,
+			},
+			getDataType: func() interface{} {
+				return &cloudprotocol.NewServices{}
+			},
+		},
 		{
 			call: func() error {
 				return aoserrors.Wrap(amqpHandler.SendMonitoringData(monitoringData))
@@ -795,6 +878,104 @@ func TestConnectionEvents(t *testing.T) {
 	}
 }
 
+func TestGetLastConnectionInfo(t *testing.T) {
+	amqpHandler, err := amqphandler.New()
+	if err != nil {
+		t.Fatalf("Can't create amqp: %v", err)
+	}
+	defer amqpHandler.Close()
+
+	if err := amqpHandler.Connect(&testCryptoContext{}, serviceDiscoveryURL, systemID, true); err != nil {
+		t.Fatalf("Can't connect to cloud: %v", err)
+	}
+
+	defer func() {
+		if err := amqpHandler.Disconnect(); err != nil {
+			t.Errorf("Can't disconnect from cloud: %v", err)
+		}
+	}()
+
+	expectedInfo := cloudprotocol.ConnectionInfo{
+		SendParams: cloudprotocol.SendParams{
+			Host:     amqpURL.Host,
+			User:     amqpURL.User.Username(),
+			Password: "***",
+			Exchange: cloudprotocol.ExchangeParams{Name: exchangeName},
+		},
+		ReceiveParams: cloudprotocol.ReceiveParams{
+			Host:     amqpURL.Host,
+			User:     amqpURL.User.Username(),
+			Password: "***",
+			Consumer: consumerName,
+			Queue:    cloudprotocol.QueueInfo{Name: outQueueName},
+		},
+	}
+
+	info, rawResponse := amqpHandler.GetLastConnectionInfo()
+
+	if !reflect.DeepEqual(info, expectedInfo) {
+		t.Errorf("Wrong connection info: %v", info)
+	}
+
+	var response cloudprotocol.ServiceDiscoveryResponse
+
+	if err := json.Unmarshal(rawResponse, &response); err != nil {
+		t.Fatalf("Can't parse raw response: %v", err)
+	}
+
+	if response.Version != 4 {
+		t.Errorf("Wrong response version: %d", response.Version)
+	}
+
+	if !reflect.DeepEqual(response.Connection, expectedInfo) {
+		t.Errorf("Wrong raw response connection info: %v", response.Connection)
+	}
+}
+
+func TestDiscoveryRetryAfter(t *testing.T) {
+	const retryAfter = 1 * time.Second
+
+	var requestCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requestCount, 1) == 1 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		serviceDiscovery(w, r)
+	}))
+	defer server.Close()
+
+	amqpHandler, err := amqphandler.New()
+	if err != nil {
+		t.Fatalf("Can't create amqp: %v", err)
+	}
+	defer amqpHandler.Close()
+
+	startTime := time.Now()
+
+	if err := amqpHandler.Connect(&testCryptoContext{}, server.URL, systemID, true); err != nil {
+		t.Fatalf("Can't connect to cloud: %v", err)
+	}
+
+	defer func() {
+		if err := amqpHandler.Disconnect(); err != nil {
+			t.Errorf("Can't disconnect from cloud: %v", err)
+		}
+	}()
+
+	if elapsed := time.Since(startTime); elapsed < retryAfter {
+		t.Errorf("Retry-After is not honored: %v", elapsed)
+	}
+
+	if count := atomic.LoadInt32(&requestCount); count != 2 {
+		t.Errorf("Wrong discovery request count: %d", count)
+	}
+}
+
 func TestConnectionEventsError(t *testing.T) {
 	amqpHandler, err := amqphandler.New()
 	if err != nil {
@@ -948,6 +1129,171 @@ func TestSendDisconnectMessages(t *testing.T) {
 	}
 }
 
+func TestSendPendingMessagesAfterRestart(t *testing.T) {
+	pendingFile := path.Join("tmp", "pending.json")
+	defer os.Remove(pendingFile)
+
+	pendingStore, err := amqphandler.NewFilePendingStore(pendingFile, 0)
+	if err != nil {
+		t.Fatalf("Can't create pending store: %v", err)
+	}
+
+	amqpHandler, err := amqphandler.New()
+	if err != nil {
+		t.Fatalf("Can't create amqp: %v", err)
+	}
+
+	amqpHandler.SetPendingStore(pendingStore)
+
+	// Important message is stored while disconnected
+
+	if err := amqpHandler.SendAlerts(cloudprotocol.Alerts{}); err != nil {
+		t.Errorf("Can't send important message: %v", err)
+	}
+
+	amqpHandler.Close()
+
+	// Simulate process restart: pending messages are reloaded from disk
+
+	if pendingStore, err = amqphandler.NewFilePendingStore(pendingFile, 0); err != nil {
+		t.Fatalf("Can't create pending store: %v", err)
+	}
+
+	if amqpHandler, err = amqphandler.New(); err != nil {
+		t.Fatalf("Can't create amqp: %v", err)
+	}
+	defer amqpHandler.Close()
+
+	amqpHandler.SetPendingStore(pendingStore)
+
+	if err = amqpHandler.Connect(&testCryptoContext{}, serviceDiscoveryURL, systemID, true); err != nil {
+		t.Errorf("Can't establish connection: %v", err)
+	}
+
+	select {
+	case delivery := <-testClient.delivery:
+		var message cloudprotocol.Message
+
+		if err = json.Unmarshal(delivery.Body, &message); err != nil {
+			t.Fatalf("Error parsing message: %v", err)
+		}
+
+		if message.Header.MessageType != cloudprotocol.AlertsType {
+			t.Errorf("Wrong message type: %s", message.Header.MessageType)
+		}
+
+	case err = <-testClient.errChannel:
+		t.Errorf("AMQP error: %v", err)
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("Waiting message timeout")
+	}
+
+	// Confirmed message should be removed from the store
+
+	for start := time.Now(); ; time.Sleep(100 * time.Millisecond) {
+		_, ok, err := pendingStore.Peek()
+		if err != nil {
+			t.Fatalf("Can't peek pending message: %v", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("Pending message is not removed")
+		}
+	}
+}
+
+func TestSendMonitoringBackpressure(t *testing.T) {
+	const (
+		monitoringBufferSize = 16
+		numMonitoring        = monitoringBufferSize + 4
+	)
+
+	amqpHandler, err := amqphandler.New()
+	if err != nil {
+		t.Fatalf("Can't create amqp: %v", err)
+	}
+	defer amqpHandler.Close()
+
+	// Generate monitoring while disconnected: oldest monitoring should be dropped
+
+	for i := 0; i < numMonitoring; i++ {
+		if err := amqpHandler.SendMonitoringData(cloudprotocol.Monitoring{
+			Nodes: []cloudprotocol.NodeMonitoringData{{NodeID: strconv.Itoa(i)}},
+		}); err != nil {
+			t.Errorf("Can't send monitoring data: %v", err)
+		}
+	}
+
+	// Important message should not be affected by monitoring
+
+	if err := amqpHandler.SendAlerts(cloudprotocol.Alerts{}); err != nil {
+		t.Errorf("Can't send important message: %v", err)
+	}
+
+	expectedMetrics := amqphandler.MonitoringBufferMetrics{
+		Buffered: monitoringBufferSize, Dropped: numMonitoring - monitoringBufferSize,
+	}
+
+	if metrics := amqpHandler.GetMonitoringBufferMetrics(); !reflect.DeepEqual(metrics, expectedMetrics) {
+		t.Errorf("Wrong monitoring buffer metrics: %v", metrics)
+	}
+
+	if err = amqpHandler.Connect(&testCryptoContext{}, serviceDiscoveryURL, systemID, true); err != nil {
+		t.Errorf("Can't establish connection: %v", err)
+	}
+
+	expectedNodeID := numMonitoring - monitoringBufferSize
+
+	for i := 0; i < monitoringBufferSize+1; i++ {
+		select {
+		case delivery := <-testClient.delivery:
+			var message struct {
+				Header cloudprotocol.MessageHeader `json:"header"`
+				Data   cloudprotocol.Monitoring    `json:"data"`
+			}
+
+			if err = json.Unmarshal(delivery.Body, &message); err != nil {
+				t.Errorf("Error parsing message: %v", err)
+				continue
+			}
+
+			if i == 0 {
+				if message.Header.MessageType != cloudprotocol.AlertsType {
+					t.Errorf("Wrong message type: %s", message.Header.MessageType)
+				}
+
+				continue
+			}
+
+			if message.Header.MessageType != cloudprotocol.MonitoringDataType {
+				t.Errorf("Wrong message type: %s", message.Header.MessageType)
+				continue
+			}
+
+			if len(message.Data.Nodes) != 1 || message.Data.Nodes[0].NodeID != strconv.Itoa(expectedNodeID) {
+				t.Errorf("Wrong monitoring data: %v", message.Data)
+			}
+
+			expectedNodeID++
+
+		case err = <-testClient.errChannel:
+			t.Errorf("AMQP error: %v", err)
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("Waiting message timeout")
+		}
+	}
+
+	if metrics := amqpHandler.GetMonitoringBufferMetrics(); metrics.Buffered != 0 {
+		t.Errorf("Monitoring buffer should be flushed: %v", metrics)
+	}
+}
+
 /***********************************************************************************************************************
  * Interfaces
  **********************************************************************************************************************/
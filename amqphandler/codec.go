@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqphandler
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/fxamacker/cbor/v2"
+)
+
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+// Message encodings.
+const (
+	EncodingJSON = "json"
+	EncodingCBOR = "cbor"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeCBOR = "application/cbor"
+)
+
+const cborMaxNestedLevels = 64
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// messageCodec encodes and decodes AMQP payloads.
+type messageCodec interface {
+	contentType() string
+	marshal(v interface{}) ([]byte, error)
+	unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+// cborCodec encodes values into CBOR directly. Struct fields are named by JSON tags, binary fields are encoded as
+// CBOR byte strings and types with text representation (time, duration) are encoded as CBOR text strings.
+type cborCodec struct{}
+
+/***********************************************************************************************************************
+ * Vars
+ **********************************************************************************************************************/
+
+//nolint:gochecknoglobals
+var (
+	cborEncOptions = cbor.EncOptions{
+		Sort:            cbor.SortCoreDeterministic,
+		Time:            cbor.TimeRFC3339Nano,
+		BinaryMarshaler: cbor.BinaryMarshalerNone,
+		TextMarshaler:   cbor.TextMarshalerTextString,
+	}
+
+	cborDecOptions = cbor.DecOptions{
+		MaxNestedLevels:   cborMaxNestedLevels,
+		DefaultMapType:    reflect.TypeOf(map[string]interface{}(nil)),
+		BinaryUnmarshaler: cbor.BinaryUnmarshalerNone,
+		TextUnmarshaler:   cbor.TextUnmarshalerTextString,
+	}
+)
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func newMessageCodec(encoding string) (messageCodec, error) {
+	switch encoding {
+	case "", EncodingJSON:
+		return jsonCodec{}, nil
+
+	case EncodingCBOR:
+		return cborCodec{}, nil
+
+	default:
+		return nil, aoserrors.Errorf("unsupported message encoding: %s", encoding)
+	}
+}
+
+// getContentTypeCodec returns codec of received message content type. JSON is used by default.
+func getContentTypeCodec(contentType string) messageCodec {
+	if contentType == contentTypeCBOR {
+		return cborCodec{}
+	}
+
+	return jsonCodec{}
+}
+
+func (jsonCodec) contentType() string {
+	return contentTypeJSON
+}
+
+func (jsonCodec) marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+
+	return data, aoserrors.Wrap(err)
+}
+
+func (jsonCodec) unmarshal(data []byte, v interface{}) error {
+	return aoserrors.Wrap(json.Unmarshal(data, v))
+}
+
+func (cborCodec) contentType() string {
+	return contentTypeCBOR
+}
+
+func (cborCodec) marshal(v interface{}) ([]byte, error) {
+	encMode, err := cborEncOptions.EncMode()
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	data, err := encMode.Marshal(v)
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	return data, nil
+}
+
+func (cborCodec) unmarshal(data []byte, v interface{}) error {
+	decMode, err := cborDecOptions.DecMode()
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return aoserrors.Wrap(decMode.Unmarshal(data, v))
+}
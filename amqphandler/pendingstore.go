@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/aosedge/aos_common/api/cloudprotocol"
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// PendingStore storage of important messages pending to be sent to the cloud. Message is removed from the store only
+// after it is confirmed by the cloud.
+type PendingStore interface {
+	Enqueue(message PendingMessage) error
+	Peek() (message PendingMessage, ok bool, err error)
+	Dequeue() error
+}
+
+// PendingMessage message pending to be sent to the cloud.
+type PendingMessage struct {
+	cloudprotocol.Message
+	// Deadline is time after which message is obsolete and dropped. Zero deadline means message never expires.
+	Deadline time.Time `json:"deadline"`
+}
+
+// MemoryPendingStore in-memory pending store. Pending messages are lost on process restart.
+type MemoryPendingStore struct {
+	sync.Mutex
+
+	maxMessages int
+	messages    []PendingMessage
+}
+
+// FilePendingStore file backed pending store. Pending messages survive process restart.
+type FilePendingStore struct {
+	sync.Mutex
+
+	fileName string
+	memory   *MemoryPendingStore
+}
+
+type storedMessage struct {
+	Header   cloudprotocol.MessageHeader `json:"header"`
+	Data     json.RawMessage             `json:"data"`
+	Deadline time.Time                   `json:"deadline"`
+}
+
+/***********************************************************************************************************************
+ * Public
+ **********************************************************************************************************************/
+
+// NewMemoryPendingStore creates in-memory pending store. Zero max messages means unlimited store.
+func NewMemoryPendingStore(maxMessages int) *MemoryPendingStore {
+	return &MemoryPendingStore{maxMessages: maxMessages}
+}
+
+// Enqueue adds message to the store.
+func (store *MemoryPendingStore) Enqueue(message PendingMessage) error {
+	store.Lock()
+	defer store.Unlock()
+
+	if store.maxMessages != 0 && len(store.messages) >= store.maxMessages {
+		return ErrSendChannelFull
+	}
+
+	store.messages = append(store.messages, message)
+
+	return nil
+}
+
+// Peek returns the oldest message without removing it.
+func (store *MemoryPendingStore) Peek() (message PendingMessage, ok bool, err error) {
+	store.Lock()
+	defer store.Unlock()
+
+	if len(store.messages) == 0 {
+		return message, false, nil
+	}
+
+	return store.messages[0], true, nil
+}
+
+// Dequeue removes the oldest message.
+func (store *MemoryPendingStore) Dequeue() error {
+	store.Lock()
+	defer store.Unlock()
+
+	if len(store.messages) == 0 {
+		return aoserrors.New("pending store is empty")
+	}
+
+	store.messages = store.messages[1:]
+
+	return nil
+}
+
+// NewFilePendingStore creates file backed pending store and loads messages stored before. Zero max messages means
+// unlimited store.
+func NewFilePendingStore(fileName string, maxMessages int) (*FilePendingStore, error) {
+	store := &FilePendingStore{fileName: fileName, memory: NewMemoryPendingStore(maxMessages)}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Enqueue adds message to the store.
+func (store *FilePendingStore) Enqueue(message PendingMessage) error {
+	store.Lock()
+	defer store.Unlock()
+
+	if err := store.memory.Enqueue(message); err != nil {
+		return err
+	}
+
+	if err := store.save(); err != nil {
+		store.memory.Lock()
+		store.memory.messages = store.memory.messages[:len(store.memory.messages)-1]
+		store.memory.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Peek returns the oldest message without removing it.
+func (store *FilePendingStore) Peek() (message PendingMessage, ok bool, err error) {
+	return store.memory.Peek()
+}
+
+// Dequeue removes the oldest message.
+func (store *FilePendingStore) Dequeue() error {
+	store.Lock()
+	defer store.Unlock()
+
+	if err := store.memory.Dequeue(); err != nil {
+		return err
+	}
+
+	return store.save()
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func (store *FilePendingStore) load() error {
+	data, err := os.ReadFile(store.fileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return aoserrors.Wrap(err)
+	}
+
+	var messages []storedMessage
+
+	if err = json.Unmarshal(data, &messages); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	for _, message := range messages {
+		store.memory.messages = append(store.memory.messages, PendingMessage{
+			Message: cloudprotocol.Message{Header: message.Header, Data: message.Data}, Deadline: message.Deadline,
+		})
+	}
+
+	return nil
+}
+
+func (store *FilePendingStore) save() error {
+	store.memory.Lock()
+	data, err := json.Marshal(store.memory.messages)
+	store.memory.Unlock()
+
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(store.fileName), 0o755); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	tmpFileName := store.fileName + ".tmp"
+
+	if err = os.WriteFile(tmpFileName, data, 0o600); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if err = os.Rename(tmpFileName, store.fileName); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
 	"github.com/aosedge/aos_common/api/cloudprotocol"
@@ -31,8 +32,10 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/aosedge/aos_communicationmanager/config"
+	"github.com/aosedge/aos_communicationmanager/utils/health"
 )
 
 /***********************************************************************************************************************
@@ -62,6 +65,12 @@ type UpdateState int
 type UpdateStatus struct {
 	State UpdateState
 	Error string
+	// NextWindow is start time of next timetable window if update is deferred by schedule.
+	NextWindow time.Time
+	// TTLDate is deadline after which update is canceled.
+	TTLDate time.Time
+	// Emergency indicates that update ignores schedule and is applied immediately.
+	Emergency bool
 }
 
 // UpdateFOTAStatus FOTA update status for update scheduler service.
@@ -88,6 +97,13 @@ type UpdateHandler interface {
 	GetSOTAStatus() (status UpdateSOTAStatus)
 	StartFOTAUpdate() (err error)
 	StartSOTAUpdate() (err error)
+	ExtendFOTATTL(extension time.Duration) (err error)
+	ExtendSOTATTL(extension time.Duration) (err error)
+}
+
+// HealthProvider provides aggregated CM health status.
+type HealthProvider interface {
+	GetStatus() (status health.Status)
 }
 
 // CMServer CM server instance.
@@ -100,6 +116,7 @@ type CMServer struct {
 	currentSOTAStatus UpdateSOTAStatus
 	stopChannel       chan bool
 	updatehandler     UpdateHandler
+	healthProvider    HealthProvider
 	sync.Mutex
 }
 
@@ -114,7 +131,7 @@ type CertificateProvider interface {
 
 // New creates new IAM server instance.
 func New(
-	cfg *config.Config, handler UpdateHandler, certProvider CertificateProvider,
+	cfg *config.Config, handler UpdateHandler, healthProvider HealthProvider, certProvider CertificateProvider,
 	cryptcoxontext *cryptutils.CryptoContext, insecure bool,
 ) (server *CMServer, err error) {
 	server = &CMServer{
@@ -122,6 +139,7 @@ func New(
 		currentSOTAStatus: handler.GetSOTAStatus(),
 		stopChannel:       make(chan bool, 1),
 		updatehandler:     handler,
+		healthProvider:    healthProvider,
 	}
 
 	if cfg.CMServerURL != "" {
@@ -254,6 +272,29 @@ func (server *CMServer) StartSOTAUpdate(ctx context.Context, req *empty.Empty) (
 	return &emptypb.Empty{}, aoserrors.Wrap(server.updatehandler.StartSOTAUpdate())
 }
 
+// ExtendFOTATTL extends TTL deadline of FOTA update awaiting trigger.
+func (server *CMServer) ExtendFOTATTL(ctx context.Context, req *pb.ExtendTTLRequest) (ret *empty.Empty, err error) {
+	return &emptypb.Empty{}, aoserrors.Wrap(server.updatehandler.ExtendFOTATTL(req.GetExtension().AsDuration()))
+}
+
+// ExtendSOTATTL extends TTL deadline of SOTA update awaiting trigger.
+func (server *CMServer) ExtendSOTATTL(ctx context.Context, req *pb.ExtendTTLRequest) (ret *empty.Empty, err error) {
+	return &emptypb.Empty{}, aoserrors.Wrap(server.updatehandler.ExtendSOTATTL(req.GetExtension().AsDuration()))
+}
+
+// GetHealthStatus returns aggregated CM health status.
+func (server *CMServer) GetHealthStatus(ctx context.Context, req *empty.Empty) (ret *pb.HealthStatus, err error) {
+	if server.healthProvider == nil {
+		return &pb.HealthStatus{}, nil
+	}
+
+	status := server.healthProvider.GetStatus()
+
+	return &pb.HealthStatus{
+		Insecure: status.Insecure, InsecureComponents: status.InsecureComponents, Errors: status.Errors,
+	}, nil
+}
+
 func (state UpdateState) String() string {
 	return [...]string{"no update", "downloading", "ready to update", "updating"}[state]
 }
@@ -322,6 +363,16 @@ func (updateStatus *UpdateSOTAStatus) convertToPBStatus() (pbStatus *pb.UpdateSO
 
 	pbStatus.State = updateStatus.State.getPbState()
 
+	pbStatus.Emergency = updateStatus.Emergency
+
+	if !updateStatus.NextWindow.IsZero() {
+		pbStatus.NextWindow = timestamppb.New(updateStatus.NextWindow)
+	}
+
+	if !updateStatus.TTLDate.IsZero() {
+		pbStatus.TtlDate = timestamppb.New(updateStatus.TTLDate)
+	}
+
 	for _, layer := range updateStatus.InstallLayers {
 		pbStatus.InstallLayers = append(pbStatus.GetInstallLayers(), &pb.LayerInfo{
 			Id:         layer.ID,
@@ -357,6 +408,15 @@ func (updateStatus *UpdateFOTAStatus) convertToPBStatus() (pbStatus *pb.UpdateFO
 	pbStatus = new(pb.UpdateFOTAStatus)
 	pbStatus.Error = updateStatus.Error
 	pbStatus.State = updateStatus.State.getPbState()
+	pbStatus.Emergency = updateStatus.Emergency
+
+	if !updateStatus.NextWindow.IsZero() {
+		pbStatus.NextWindow = timestamppb.New(updateStatus.NextWindow)
+	}
+
+	if !updateStatus.TTLDate.IsZero() {
+		pbStatus.TtlDate = timestamppb.New(updateStatus.TTLDate)
+	}
 
 	for _, component := range updateStatus.Components {
 		pbStatus.Components = append(pbStatus.GetComponents(), &pb.ComponentInfo{
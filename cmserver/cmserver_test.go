@@ -20,6 +20,7 @@ package cmserver_test
 import (
 	"context"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -29,10 +30,12 @@ import (
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/aosedge/aos_communicationmanager/cmserver"
 	"github.com/aosedge/aos_communicationmanager/config"
+	"github.com/aosedge/aos_communicationmanager/utils/health"
 )
 
 /***********************************************************************************************************************
@@ -57,6 +60,12 @@ type testUpdateHandler struct {
 	sotaChannel chan cmserver.UpdateSOTAStatus
 	startFOTA   bool
 	startSOTA   bool
+	fotaTTL     time.Duration
+	sotaTTL     time.Duration
+}
+
+type testHealthProvider struct {
+	status health.Status
 }
 
 /***********************************************************************************************************************
@@ -87,7 +96,7 @@ func TestConnection(t *testing.T) {
 		fotaChannel: make(chan cmserver.UpdateFOTAStatus, 10),
 	}
 
-	cmServer, err := cmserver.New(&cmConfig, &unitStatusHandler, nil, nil, true)
+	cmServer, err := cmserver.New(&cmConfig, &unitStatusHandler, nil, nil, nil, true)
 	if err != nil {
 		t.Fatalf("Can't create CM server: %s", err)
 	}
@@ -125,10 +134,15 @@ func TestConnection(t *testing.T) {
 		}
 	}
 
+	nextWindow := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	ttlDate := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
 	statusFotaNotification := cmserver.UpdateFOTAStatus{
-		Components:   []cloudprotocol.ComponentStatus{{ID: "1234", AosVersion: 123, VendorVersion: "4321"}},
-		UnitConfig:   &cloudprotocol.UnitConfigStatus{VendorVersion: "bc_version"},
-		UpdateStatus: cmserver.UpdateStatus{State: cmserver.ReadyToUpdate},
+		Components: []cloudprotocol.ComponentStatus{{ID: "1234", AosVersion: 123, VendorVersion: "4321"}},
+		UnitConfig: &cloudprotocol.UnitConfigStatus{VendorVersion: "bc_version"},
+		UpdateStatus: cmserver.UpdateStatus{
+			State: cmserver.ReadyToUpdate, NextWindow: nextWindow, TTLDate: ttlDate, Emergency: true,
+		},
 	}
 
 	unitStatusHandler.fotaChannel <- statusFotaNotification
@@ -171,12 +185,26 @@ func TestConnection(t *testing.T) {
 		t.Error("Incorrect unit config version")
 	}
 
+	if status.GetNextWindow() == nil || !status.GetNextWindow().AsTime().Equal(nextWindow) {
+		t.Errorf("Incorrect next window: %v", status.GetNextWindow())
+	}
+
+	if status.GetTtlDate() == nil || !status.GetTtlDate().AsTime().Equal(ttlDate) {
+		t.Errorf("Incorrect TTL date: %v", status.GetTtlDate())
+	}
+
+	if !status.GetEmergency() {
+		t.Error("FOTA update should be emergency")
+	}
+
 	statusNotification := cmserver.UpdateSOTAStatus{
 		InstallServices: []cloudprotocol.ServiceStatus{{ID: "s1", AosVersion: 42}},
 		RemoveServices:  []cloudprotocol.ServiceStatus{{ID: "s2", AosVersion: 42}},
 		InstallLayers:   []cloudprotocol.LayerStatus{{ID: "l1", Digest: "someSha", AosVersion: 42}},
 		RemoveLayers:    []cloudprotocol.LayerStatus{{ID: "l2", Digest: "someSha", AosVersion: 42}},
-		UpdateStatus:    cmserver.UpdateStatus{State: cmserver.Downloading, Error: "SOTA error"},
+		UpdateStatus: cmserver.UpdateStatus{
+			State: cmserver.Downloading, Error: "SOTA error", NextWindow: nextWindow, TTLDate: ttlDate,
+		},
 	}
 
 	unitStatusHandler.sotaChannel <- statusNotification
@@ -199,6 +227,18 @@ func TestConnection(t *testing.T) {
 		t.Error("Incorrect error message: ", status.GetError())
 	}
 
+	if sotaStatus.GetNextWindow() == nil || !sotaStatus.GetNextWindow().AsTime().Equal(nextWindow) {
+		t.Errorf("Incorrect next window: %v", sotaStatus.GetNextWindow())
+	}
+
+	if sotaStatus.GetTtlDate() == nil || !sotaStatus.GetTtlDate().AsTime().Equal(ttlDate) {
+		t.Errorf("Incorrect TTL date: %v", sotaStatus.GetTtlDate())
+	}
+
+	if sotaStatus.GetEmergency() {
+		t.Error("SOTA update should not be emergency")
+	}
+
 	if len(sotaStatus.GetInstallServices()) != 1 {
 		t.Fatal("Incorrect count of services")
 	}
@@ -243,11 +283,76 @@ func TestConnection(t *testing.T) {
 		t.Error("SOTA update should be started")
 	}
 
+	if _, err := client.pbclient.ExtendFOTATTL(
+		ctx, &pb.ExtendTTLRequest{Extension: durationpb.New(time.Hour)}); err != nil {
+		t.Fatalf("Can't extend FOTA TTL: %v", err)
+	}
+
+	if unitStatusHandler.fotaTTL != time.Hour {
+		t.Errorf("Incorrect FOTA TTL extension: %v", unitStatusHandler.fotaTTL)
+	}
+
+	if _, err := client.pbclient.ExtendSOTATTL(
+		ctx, &pb.ExtendTTLRequest{Extension: durationpb.New(2 * time.Hour)}); err != nil {
+		t.Fatalf("Can't extend SOTA TTL: %v", err)
+	}
+
+	if unitStatusHandler.sotaTTL != 2*time.Hour {
+		t.Errorf("Incorrect SOTA TTL extension: %v", unitStatusHandler.sotaTTL)
+	}
+
 	client.close()
 
 	time.Sleep(time.Second)
 }
 
+func TestHealthStatus(t *testing.T) {
+	cmConfig := config.Config{
+		CMServerURL: serverURL,
+	}
+
+	unitStatusHandler := testUpdateHandler{
+		sotaChannel: make(chan cmserver.UpdateSOTAStatus, 10),
+		fotaChannel: make(chan cmserver.UpdateFOTAStatus, 10),
+	}
+
+	healthProvider := testHealthProvider{status: health.Status{
+		Insecure: true, InsecureComponents: []string{"amqp"}, Errors: map[string]string{"launcher": "some error"},
+	}}
+
+	cmServer, err := cmserver.New(&cmConfig, &unitStatusHandler, &healthProvider, nil, nil, true)
+	if err != nil {
+		t.Fatalf("Can't create CM server: %s", err)
+	}
+	defer cmServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := newTestClient(serverURL)
+	if err != nil {
+		t.Fatalf("Can't create test client: %s", err)
+	}
+	defer client.close()
+
+	status, err := client.pbclient.GetHealthStatus(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("Can't get health status: %v", err)
+	}
+
+	if !status.GetInsecure() {
+		t.Error("Insecure mode should be reported")
+	}
+
+	if !reflect.DeepEqual(status.GetInsecureComponents(), healthProvider.status.InsecureComponents) {
+		t.Errorf("Wrong insecure components: %v", status.GetInsecureComponents())
+	}
+
+	if !reflect.DeepEqual(status.GetErrors(), healthProvider.status.Errors) {
+		t.Errorf("Wrong health errors: %v", status.GetErrors())
+	}
+}
+
 /***********************************************************************************************************************
  * Private
  **********************************************************************************************************************/
@@ -305,3 +410,19 @@ func (handler *testUpdateHandler) StartSOTAUpdate() (err error) {
 
 	return nil
 }
+
+func (handler *testUpdateHandler) ExtendFOTATTL(extension time.Duration) (err error) {
+	handler.fotaTTL = extension
+
+	return nil
+}
+
+func (handler *testUpdateHandler) ExtendSOTATTL(extension time.Duration) (err error) {
+	handler.sotaTTL = extension
+
+	return nil
+}
+
+func (provider *testHealthProvider) GetStatus() (status health.Status) {
+	return provider.status
+}
@@ -56,6 +56,10 @@ import (
 	"github.com/aosedge/aos_communicationmanager/umcontroller"
 	"github.com/aosedge/aos_communicationmanager/unitconfig"
 	"github.com/aosedge/aos_communicationmanager/unitstatushandler"
+	"github.com/aosedge/aos_communicationmanager/utils/health"
+	"github.com/aosedge/aos_communicationmanager/utils/loglevel"
+	"github.com/aosedge/aos_communicationmanager/utils/metrics"
+	"github.com/aosedge/aos_communicationmanager/utils/uidgidpool"
 )
 
 /***********************************************************************************************************************
@@ -65,6 +69,7 @@ import (
 const (
 	initReconnectTimeout = 10 * time.Second
 	maxReconnectTimeout  = 10 * time.Minute
+	idPoolsCheckPeriod   = 1 * time.Minute
 )
 
 /***********************************************************************************************************************
@@ -91,6 +96,15 @@ type communicationManager struct {
 	network           *networkmanager.NetworkManager
 	storageState      *storagestate.StorageState
 	cmServer          *cmserver.CMServer
+	health            *health.Aggregator
+	metricsServer     *metrics.Server
+}
+
+type idPoolMonitor struct {
+	name           string
+	threshold      int
+	getUtilization func() uidgidpool.Utilization
+	aboveThreshold bool
 }
 
 type journalHook struct {
@@ -148,6 +162,41 @@ func newCommunicationManager(cfg *config.Config) (cm *communicationManager, err
 		return cm, aoserrors.Wrap(err)
 	}
 
+	if cfg.UnknownMessageLogLevel != "" {
+		logLevel, err := log.ParseLevel(cfg.UnknownMessageLogLevel)
+		if err != nil {
+			return cm, aoserrors.Wrap(err)
+		}
+
+		cm.amqp.SetUnknownMessageHandling(logLevel, nil)
+	}
+
+	cm.amqp.SetConsumerQoS(cfg.AMQPConsumer.PrefetchCount, cfg.AMQPConsumer.ManualAck)
+	cm.amqp.SetUnitStatusChunkSize(cfg.UnitStatusChunkSize)
+
+	if err = cm.amqp.SetMessageEncoding(cfg.AMQPMessageEncoding); err != nil {
+		return cm, aoserrors.Wrap(err)
+	}
+
+	for messageType, ttl := range cfg.AMQPMessageTTL {
+		cm.amqp.SetMessageTTL(messageType, ttl.Duration)
+	}
+
+	// Process messages in receiver to ack them only after successful processing
+	if cfg.AMQPConsumer.ManualAck {
+		cm.amqp.SetMessageDispatcher(cm.processMessage)
+	}
+
+	// Keep important messages on disk to send them after restart
+	if cfg.PendingMessagesFile != "" {
+		pendingStore, err := amqp.NewFilePendingStore(cfg.PendingMessagesFile, 0)
+		if err != nil {
+			return cm, aoserrors.Wrap(err)
+		}
+
+		cm.amqp.SetPendingStore(pendingStore)
+	}
+
 	if cm.cryptoContext, err = cryptutils.NewCryptoContext(cfg.Crypt.CACert); err != nil {
 		return nil, aoserrors.Wrap(err)
 	}
@@ -198,6 +247,11 @@ func newCommunicationManager(cfg *config.Config) (cm *communicationManager, err
 		return cm, aoserrors.Wrap(err)
 	}
 
+	cm.health = health.New()
+
+	cm.health.Register("amqp", cm.amqp)
+	cm.health.Register("umcontroller", cm.umController)
+
 	if cm.unitConfig, err = unitconfig.New(cfg, cm.smController); err != nil {
 		return cm, aoserrors.Wrap(err)
 	}
@@ -219,15 +273,25 @@ func newCommunicationManager(cfg *config.Config) (cm *communicationManager, err
 		return cm, aoserrors.Wrap(err)
 	}
 
+	cm.health.RegisterErrorProvider("launcher", cm.launcher)
+
 	if cm.statusHandler, err = unitstatushandler.New(cfg, cm.unitConfig, cm.umController, cm.imagemanager, cm.launcher,
 		cm.downloader, cm.db, cm.amqp); err != nil {
 		return cm, aoserrors.Wrap(err)
 	}
 
-	if cm.cmServer, err = cmserver.New(cfg, cm.statusHandler, cm.iam, cm.cryptoContext, false); err != nil {
+	cm.statusHandler.SetPolicyChecker(unitstatushandler.NewConfigPolicy(cfg.UpdatePolicy))
+
+	if cm.cmServer, err = cmserver.New(cfg, cm.statusHandler, cm.health, cm.iam, cm.cryptoContext, false); err != nil {
 		return cm, aoserrors.Wrap(err)
 	}
 
+	if cfg.MetricsListenAddress != "" {
+		if cm.metricsServer, err = metrics.NewServer(cfg.MetricsListenAddress, cm.newMetricsRegistry()); err != nil {
+			return cm, aoserrors.Wrap(err)
+		}
+	}
+
 	return cm, nil
 }
 
@@ -245,6 +309,13 @@ func initPKCS(cfg config.Crypt) (err error) {
 }
 
 func (cm *communicationManager) close() {
+	// Close metrics server
+	if cm.metricsServer != nil {
+		if err := cm.metricsServer.Close(); err != nil {
+			log.Errorf("Can't close metrics server: %v", err)
+		}
+	}
+
 	// Close CM server
 	if cm.cmServer != nil {
 		cm.cmServer.Close()
@@ -300,9 +371,11 @@ func (cm *communicationManager) close() {
 		cm.monitorcontroller.Close()
 	}
 
-	// Close downloader
+	// Shutdown downloader
 	if cm.downloader != nil {
-		cm.downloader.Close()
+		if err := cm.downloader.Shutdown(); err != nil {
+			log.Errorf("Can't shutdown downloader: %v", err)
+		}
 	}
 
 	// Close iam
@@ -391,6 +464,13 @@ func (cm *communicationManager) processMessage(message amqp.Message) (err error)
 			return aoserrors.Wrap(err)
 		}
 
+	case *cloudprotocol.SetLogLevel:
+		log.WithField("level", data.Level).Info("Receive set log level message")
+
+		if err = loglevel.SetLevel(data.Level); err != nil {
+			return aoserrors.Wrap(err)
+		}
+
 	case *cloudprotocol.IssuedUnitCerts:
 		log.Info("Receive issued unit certificates message")
 
@@ -424,7 +504,12 @@ func (cm *communicationManager) handleMessages(ctx context.Context) {
 	}
 }
 
-func (cm *communicationManager) handleConnection(ctx context.Context, serviceDiscoveryURLs []string) {
+func (cm *communicationManager) handleConnection(
+	ctx context.Context, serviceDiscoveryURLs []string, nodesConnectionTimeout time.Duration,
+) {
+	// Desired status is received on cloud connection: wait for nodes to have them all in the first balancing
+	cm.waitForNodes(ctx, nodesConnectionTimeout)
+
 	for {
 		_ = retryhelper.Retry(ctx,
 			func() (err error) {
@@ -444,8 +529,24 @@ func (cm *communicationManager) handleConnection(ctx context.Context, serviceDis
 			},
 			0, initReconnectTimeout, maxReconnectTimeout)
 
-		if err := cm.statusHandler.SendUnitStatus(); err != nil {
-			log.Errorf("Can't send unit status: %s", err)
+		if ctx.Err() == nil {
+			connectionInfo, rawResponse := cm.amqp.GetLastConnectionInfo()
+
+			log.WithFields(log.Fields{
+				"sendHost":    connectionInfo.SendParams.Host,
+				"receiveHost": connectionInfo.ReceiveParams.Host,
+				"queue":       connectionInfo.ReceiveParams.Queue.Name,
+			}).Info("Connected to cloud")
+
+			log.Debugf("Service discovery response: %s", rawResponse)
+		}
+
+		if status := cm.health.GetStatus(); status.Insecure {
+			log.WithField("insecureComponents", status.InsecureComponents).Warn("CM runs in insecure mode")
+		}
+
+		if status := cm.health.GetStatus(); len(status.Errors) != 0 {
+			log.WithField("errors", status.Errors).Warn("CM components are unhealthy")
 		}
 
 		cm.handleMessages(ctx)
@@ -460,6 +561,19 @@ func (cm *communicationManager) handleConnection(ctx context.Context, serviceDis
 	}
 }
 
+func (cm *communicationManager) waitForNodes(ctx context.Context, timeout time.Duration) {
+	waitCtx, cancelFunc := context.WithTimeout(ctx, timeout)
+	defer cancelFunc()
+
+	if err := cm.launcher.WaitForAllNodes(waitCtx); err != nil {
+		log.Warnf("Not all nodes are connected: %v", err)
+
+		return
+	}
+
+	log.Debug("All nodes are connected")
+}
+
 func (cm *communicationManager) handleStatusChannels(ctx context.Context) {
 	for {
 		select {
@@ -469,7 +583,71 @@ func (cm *communicationManager) handleStatusChannels(ctx context.Context) {
 			}
 
 		case instanceStatus := <-cm.smController.GetUpdateInstancesStatusChannel():
-			cm.statusHandler.ProcessUpdateInstanceStatus(instanceStatus)
+			cm.statusHandler.ProcessUpdateInstanceStatus(cm.launcher.ProcessUpdateInstanceStatus(instanceStatus))
+
+		case instanceEvent := <-cm.launcher.GetInstanceEventsChannel():
+			logInstanceEvent(instanceEvent)
+
+		case nodeEvent := <-cm.launcher.GetNodeEventsChannel():
+			log.WithField("nodeID", nodeEvent.NodeID).Infof("Node %s", nodeEvent.EventType)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func logInstanceEvent(event launcher.InstanceEvent) {
+	fields := log.Fields{
+		"serviceID": event.ServiceID,
+		"subjectID": event.SubjectID,
+		"instance":  event.Instance,
+		"nodeID":    event.NodeID,
+	}
+
+	if event.PrevNodeID != "" {
+		fields["prevNodeID"] = event.PrevNodeID
+	}
+
+	log.WithFields(fields).Infof("Instance %s", event.EventType)
+}
+
+func (cm *communicationManager) handleIDPools(ctx context.Context, monitors []*idPoolMonitor) {
+	ticker := time.NewTicker(idPoolsCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, monitor := range monitors {
+				cm.checkIDPool(monitor)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleSchedulingSignals pauses launcher scheduling on SIGUSR1 and resumes it on SIGUSR2 for emergency maintenance.
+func (cm *communicationManager) handleSchedulingSignals(ctx context.Context) {
+	signalChannel := make(chan os.Signal, 1)
+
+	signal.Notify(signalChannel, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(signalChannel)
+
+	for {
+		select {
+		case sig := <-signalChannel:
+			if sig == syscall.SIGUSR1 {
+				cm.launcher.Pause()
+
+				continue
+			}
+
+			if err := cm.launcher.Resume(); err != nil {
+				log.Errorf("Can't resume launcher: %v", err)
+			}
 
 		case <-ctx.Done():
 			return
@@ -477,6 +655,33 @@ func (cm *communicationManager) handleStatusChannels(ctx context.Context) {
 	}
 }
 
+func (cm *communicationManager) checkIDPool(monitor *idPoolMonitor) {
+	utilization := monitor.getUtilization()
+
+	if !utilization.IsAboveThreshold(monitor.threshold) {
+		monitor.aboveThreshold = false
+
+		return
+	}
+
+	// Alert is sent once when utilization crosses the threshold
+	if monitor.aboveThreshold {
+		return
+	}
+
+	monitor.aboveThreshold = true
+
+	message := fmt.Sprintf("%s pool utilization is above threshold: %d of %d allocated", monitor.name,
+		utilization.Allocated, utilization.Capacity)
+
+	log.Warn(message)
+
+	cm.alerts.SendAlert(cloudprotocol.AlertItem{
+		Timestamp: time.Now(), Tag: cloudprotocol.AlertTagAosCore,
+		Payload: cloudprotocol.CoreAlert{CoreComponent: "CM", Message: message},
+	})
+}
+
 /***********************************************************************************************************************
  * Systemd journal hook
  **********************************************************************************************************************/
@@ -526,6 +731,128 @@ func (hook *journalHook) Levels() []log.Level {
  * Private
  **********************************************************************************************************************/
 
+func (cm *communicationManager) newMetricsRegistry() (registry *metrics.Registry) {
+	registry = metrics.New()
+
+	registry.Register("amqp", func() []metrics.Metric {
+		bufferMetrics := cm.amqp.GetMonitoringBufferMetrics()
+
+		return []metrics.Metric{
+			{
+				Name: "monitoring_buffered", Help: "Number of buffered monitoring messages.", Type: metrics.TypeGauge,
+				Value: float64(bufferMetrics.Buffered),
+			},
+			{
+				Name: "monitoring_dropped_total", Help: "Number of dropped monitoring messages.",
+				Type: metrics.TypeCounter, Value: float64(bufferMetrics.Dropped),
+			},
+			{
+				Name: "expired_messages_total", Help: "Number of queued messages dropped due to expired TTL.",
+				Type: metrics.TypeCounter, Value: float64(cm.amqp.GetExpiredMessagesCount()),
+			},
+		}
+	})
+
+	registry.Register("balancing", func() (balancingMetrics []metrics.Metric) {
+		launcherMetrics := cm.launcher.GetBalancingMetrics()
+
+		for nodeID, count := range launcherMetrics.ScheduledInstances {
+			balancingMetrics = append(balancingMetrics, metrics.Metric{
+				Name: "scheduled_instances_total", Help: "Number of instances scheduled on node.",
+				Type: metrics.TypeCounter, Labels: map[string]string{"node": nodeID}, Value: float64(count),
+			})
+		}
+
+		for filter, count := range launcherMetrics.SchedulingFailures {
+			balancingMetrics = append(balancingMetrics, metrics.Metric{
+				Name: "scheduling_failures_total", Help: "Number of instances failed to schedule by balancing filter.",
+				Type: metrics.TypeCounter, Labels: map[string]string{"filter": filter}, Value: float64(count),
+			})
+		}
+
+		return append(balancingMetrics,
+			metrics.Metric{
+				Name: "rebalancing_events_total", Help: "Number of rebalancing events.", Type: metrics.TypeCounter,
+				Value: float64(launcherMetrics.RebalancingEvents),
+			},
+			metrics.Metric{
+				Name: "average_placement_seconds", Help: "Average instance placement time.", Type: metrics.TypeGauge,
+				Value: launcherMetrics.AveragePlacementTime.Seconds(),
+			})
+	})
+
+	registry.Register("idpools", func() []metrics.Metric {
+		uidUtilization := cm.launcher.GetUIDPoolUtilization()
+		gidUtilization := cm.imagemanager.GetGIDPoolUtilization()
+
+		return []metrics.Metric{
+			{
+				Name: "allocated_ids", Help: "Number of allocated IDs.", Type: metrics.TypeGauge,
+				Labels: map[string]string{"pool": "uid"}, Value: float64(uidUtilization.Allocated),
+			},
+			{
+				Name: "capacity_ids", Help: "Number of IDs available in the pool.", Type: metrics.TypeGauge,
+				Labels: map[string]string{"pool": "uid"}, Value: float64(uidUtilization.Capacity),
+			},
+			{
+				Name: "allocated_ids", Help: "Number of allocated IDs.", Type: metrics.TypeGauge,
+				Labels: map[string]string{"pool": "gid"}, Value: float64(gidUtilization.Allocated),
+			},
+			{
+				Name: "capacity_ids", Help: "Number of IDs available in the pool.", Type: metrics.TypeGauge,
+				Labels: map[string]string{"pool": "gid"}, Value: float64(gidUtilization.Capacity),
+			},
+		}
+	})
+
+	registry.Register("downloads", cm.getDownloadMetrics)
+
+	registry.Register("network", cm.getNetworkMetrics)
+
+	return registry
+}
+
+func (cm *communicationManager) getDownloadMetrics() (downloadMetrics []metrics.Metric) {
+	inProgress := 0
+	downloadedBytes := make(map[string]uint64)
+
+	for _, stat := range cm.downloader.GetDownloadStats() {
+		if stat.InProgress {
+			inProgress++
+		}
+
+		downloadedBytes[stat.TargetType] += stat.Bytes
+	}
+
+	for targetType, bytes := range downloadedBytes {
+		downloadMetrics = append(downloadMetrics, metrics.Metric{
+			Name: "download_bytes", Help: "Number of downloaded bytes of recent downloads.", Type: metrics.TypeGauge,
+			Labels: map[string]string{"targetType": targetType}, Value: float64(bytes),
+		})
+	}
+
+	return append(downloadMetrics, metrics.Metric{
+		Name: "downloads_in_progress", Help: "Number of downloads in progress.", Type: metrics.TypeGauge,
+		Value: float64(inProgress),
+	})
+}
+
+func (cm *communicationManager) getNetworkMetrics() (networkMetrics []metrics.Metric) {
+	for networkID, stat := range cm.network.GetSubnetStats() {
+		networkMetrics = append(networkMetrics,
+			metrics.Metric{
+				Name: "allocated_ips", Help: "Number of allocated subnet IPs.", Type: metrics.TypeGauge,
+				Labels: map[string]string{"network": networkID}, Value: float64(stat.Allocated),
+			},
+			metrics.Metric{
+				Name: "free_ips", Help: "Number of free subnet IPs.", Type: metrics.TypeGauge,
+				Labels: map[string]string{"network": networkID}, Value: float64(stat.Free),
+			})
+	}
+
+	return networkMetrics
+}
+
 func reset(cfg *config.Config) (err error) {
 	log.Info("Cleanup working directory")
 
@@ -540,6 +867,26 @@ func reset(cfg *config.Config) (err error) {
  * Main
  **********************************************************************************************************************/
 
+func initLog(strLogLevel string, useJournal bool) {
+	// Set log output
+
+	if useJournal {
+		log.AddHook(newJournalHook())
+		log.SetOutput(io.Discard)
+	} else {
+		log.SetOutput(os.Stdout)
+	}
+
+	// Set log level
+
+	logLevel, err := log.ParseLevel(strLogLevel)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	log.SetLevel(logLevel)
+}
+
 func main() {
 	// Initialize command line flags
 	configFile := flag.String("c", "aos_communicationmanager.cfg", "path to config file")
@@ -558,23 +905,7 @@ func main() {
 		return
 	}
 
-	// Set log output
-
-	if *useJournal {
-		log.AddHook(newJournalHook())
-		log.SetOutput(io.Discard)
-	} else {
-		log.SetOutput(os.Stdout)
-	}
-
-	// Set log level
-
-	logLevel, err := log.ParseLevel(*strLogLevel)
-	if err != nil {
-		log.Fatalf("Error: %s", err)
-	}
-
-	log.SetLevel(logLevel)
+	initLog(*strLogLevel, *useJournal)
 
 	// Parse config
 
@@ -616,8 +947,19 @@ func main() {
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
-	go cm.handleConnection(ctx, cm.crypt.GetServiceDiscoveryURLs())
+	go cm.handleConnection(ctx, cm.crypt.GetServiceDiscoveryURLs(), cfg.SMController.NodesConnectionTimeout.Duration)
 	go cm.handleStatusChannels(ctx)
+	go cm.handleIDPools(ctx, []*idPoolMonitor{
+		{
+			name: "UID", threshold: cfg.SMController.UIDPoolWarningThreshold,
+			getUtilization: cm.launcher.GetUIDPoolUtilization,
+		},
+		{
+			name: "GID", threshold: cfg.GIDPoolWarningThreshold,
+			getUtilization: cm.imagemanager.GetGIDPoolUtilization,
+		},
+	})
+	go cm.handleSchedulingSignals(ctx)
 
 	// Handle SIGTERM
 
@@ -22,6 +22,9 @@ import (
 	"encoding/json"
 	"os"
 	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
@@ -30,6 +33,13 @@ import (
 	"github.com/aosedge/aos_common/resourcemonitor"
 )
 
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+// EnvPrefix prefix of environment variables overriding configuration values.
+const EnvPrefix = "AOS_CM_"
+
 /***********************************************************************************************************************
  * Types
  **********************************************************************************************************************/
@@ -43,17 +53,29 @@ type Crypt struct {
 
 // UMController configuration for update controller.
 type UMController struct {
-	FileServerURL string            `json:"fileServerUrl"`
-	CMServerURL   string            `json:"cmServerUrl"`
-	UMClients     []UMClientConfig  `json:"umClients"`
-	UpdateTTL     aostypes.Duration `json:"updateTtl"`
+	FileServerURL     string            `json:"fileServerUrl"`
+	CMServerURL       string            `json:"cmServerUrl"`
+	UMClients         []UMClientConfig  `json:"umClients"`
+	UpdateTTL         aostypes.Duration `json:"updateTtl"`
+	ConnectionTimeout aostypes.Duration `json:"connectionTimeout,omitempty"`
+	DisableAutoRevert bool              `json:"disableAutoRevert,omitempty"`
+	ContinueOnError   bool              `json:"continueOnError,omitempty"`
+	ParallelApply     bool              `json:"parallelApply,omitempty"`
+	UnitConfigOrder   string            `json:"unitConfigOrder,omitempty"`
 }
 
 // UMClientConfig update manager config.
 type UMClientConfig struct {
-	UMID     string `json:"umId"`
-	Priority uint32 `json:"priority"`
-	IsLocal  bool   `json:"isLocal,omitempty"`
+	UMID              string            `json:"umId"`
+	Priority          uint32            `json:"priority"`
+	IsLocal           bool              `json:"isLocal,omitempty"`
+	ConnectionTimeout aostypes.Duration `json:"connectionTimeout,omitempty"`
+}
+
+// AMQPConsumer AMQP consumer QoS configuration.
+type AMQPConsumer struct {
+	PrefetchCount int  `json:"prefetchCount"`
+	ManualAck     bool `json:"manualAck"`
 }
 
 // Monitoring configuration for system monitoring.
@@ -62,9 +84,18 @@ type Monitoring struct {
 	MaxOfflineMessages int                     `json:"maxOfflineMessages"`
 }
 
+// AlertsAggregation configuration for alerts aggregation: identical alerts received within window are coalesced
+// into one alert with count, number of different alerts per tag within window is limited by MaxAlertsPerTag.
+type AlertsAggregation struct {
+	Window          aostypes.Duration `json:"window"`
+	MaxAlertsPerTag int               `json:"maxAlertsPerTag,omitempty"`
+	ExemptTags      []string          `json:"exemptTags,omitempty"`
+}
+
 // Alerts configuration for alerts.
 type Alerts struct {
 	JournalAlerts      *journalalerts.Config `json:"journalAlerts,omitempty"`
+	Aggregation        *AlertsAggregation    `json:"aggregation,omitempty"`
 	SendPeriod         aostypes.Duration     `json:"sendPeriod"`
 	MaxMessageSize     int                   `json:"maxMessageSize"`
 	MaxOfflineMessages int                   `json:"maxOfflineMessages"`
@@ -79,44 +110,113 @@ type Migration struct {
 // Downloader downloader configuration.
 type Downloader struct {
 	DownloadDir            string            `json:"downloadDir"`
+	FirmwareDownloadDir    string            `json:"firmwareDownloadDir,omitempty"`
+	SoftwareDownloadDir    string            `json:"softwareDownloadDir,omitempty"`
+	CandidateDownloadDirs  []string          `json:"candidateDownloadDirs,omitempty"`
 	MaxConcurrentDownloads int               `json:"maxConcurrentDownloads"`
 	RetryDelay             aostypes.Duration `json:"retryDelay"`
 	MaxRetryDelay          aostypes.Duration `json:"maxRetryDelay"`
 	DownloadPartLimit      int               `json:"downloadPartLimit"`
+	ShutdownTimeout        aostypes.Duration `json:"shutdownTimeout,omitempty"`
+	MaxDownloadRate        uint64            `json:"maxDownloadRate,omitempty"`
+	DownloadGroupWeights   map[string]uint   `json:"downloadGroupWeights,omitempty"`
 }
 
 // SMController SM controller configuration.
 type SMController struct {
-	FileServerURL          string            `json:"fileServerUrl"`
-	CMServerURL            string            `json:"cmServerUrl"`
-	NodeIDs                []string          `json:"nodeIds"`
-	NodesConnectionTimeout aostypes.Duration `json:"nodesConnectionTimeout"`
-	UpdateTTL              aostypes.Duration `json:"updateTtl"`
+	FileServerURL           string            `json:"fileServerUrl"`
+	CMServerURL             string            `json:"cmServerUrl"`
+	NodeIDs                 []string          `json:"nodeIds"`
+	NodesConnectionTimeout  aostypes.Duration `json:"nodesConnectionTimeout"`
+	UpdateTTL               aostypes.Duration `json:"updateTtl"`
+	StateCleanupGracePeriod aostypes.Duration `json:"stateCleanupGracePeriod,omitempty"`
+	InstanceStopTimeout     aostypes.Duration `json:"instanceStopTimeout,omitempty"`
+	MoveStopTimeout         aostypes.Duration `json:"moveStopTimeout,omitempty"`
+	NodeCircuitBreaker      CircuitBreaker    `json:"nodeCircuitBreaker,omitempty"`
+	DeferOverCapacity       bool              `json:"deferOverCapacity,omitempty"`
+	MinNodesToBalance       int               `json:"minNodesToBalance,omitempty"`
+	ExpectedNodeTypes       map[string]string `json:"expectedNodeTypes,omitempty"`
+	NodeTieBreaker          string            `json:"nodeTieBreaker,omitempty"`
+	StorageWriteMaxTry      int               `json:"storageWriteMaxTry,omitempty"`
+	StorageWriteRetryDelay  aostypes.Duration `json:"storageWriteRetryDelay,omitempty"`
+	UnitConfigRetryPeriod   aostypes.Duration `json:"unitConfigRetryPeriod,omitempty"`
+	UIDPoolLimit            int               `json:"uidPoolLimit,omitempty"`
+	UIDPoolWarningThreshold int               `json:"uidPoolWarningThreshold,omitempty"`
+	RemoteLayerDelivery     string            `json:"remoteLayerDelivery,omitempty"`
+	RepeatedRunStatus       string            `json:"repeatedRunStatus,omitempty"`
+}
+
+// CircuitBreaker circuit breaker configuration for nodes failing instances.
+type CircuitBreaker struct {
+	MaxFailures   int               `json:"maxFailures"`
+	FailureWindow aostypes.Duration `json:"failureWindow"`
+	Cooldown      aostypes.Duration `json:"cooldown"`
+	EmitEvents    bool              `json:"emitEvents,omitempty"`
+}
+
+// CachePolicy cache policy for removed services and layers.
+// Cached items age is limited by serviceTtlDays and layerTtlDays.
+type CachePolicy struct {
+	MaxItems int    `json:"maxItems"`
+	MaxSize  uint64 `json:"maxSize"`
+}
+
+// UpdatePolicy enterprise policy applied to desired status items before update.
+type UpdatePolicy struct {
+	BlockedServices   []string `json:"blockedServices,omitempty"`
+	BlockedLayers     []string `json:"blockedLayers,omitempty"`
+	BlockedComponents []string `json:"blockedComponents,omitempty"`
+	DenyDowngrade     bool     `json:"denyDowngrade,omitempty"`
+}
+
+// RetryBudget retry budget for the whole software update.
+type RetryBudget struct {
+	MaxAttempts   int               `json:"maxAttempts"`
+	MaxTime       aostypes.Duration `json:"maxTime"`
+	RetryDelay    aostypes.Duration `json:"retryDelay"`
+	MaxRetryDelay aostypes.Duration `json:"maxRetryDelay"`
 }
 
 // Config instance.
 type Config struct {
-	Crypt                 Crypt             `json:"fcrypt"`
-	CertStorage           string            `json:"certStorage"`
-	ServiceDiscoveryURL   string            `json:"serviceDiscoveryUrl"`
-	IAMProtectedServerURL string            `json:"iamProtectedServerUrl"`
-	IAMPublicServerURL    string            `json:"iamPublicServerUrl"`
-	CMServerURL           string            `json:"cmServerUrl"`
-	Downloader            Downloader        `json:"downloader"`
-	StorageDir            string            `json:"storageDir"`
-	StateDir              string            `json:"stateDir"`
-	WorkingDir            string            `json:"workingDir"`
-	ImageStoreDir         string            `json:"imageStoreDir"`
-	ComponentsDir         string            `json:"componentsDir"`
-	UnitConfigFile        string            `json:"unitConfigFile"`
-	ServiceTTLDays        uint64            `json:"serviceTtlDays"`
-	LayerTTLDays          uint64            `json:"layerTtlDays"`
-	UnitStatusSendTimeout aostypes.Duration `json:"unitStatusSendTimeout"`
-	Monitoring            Monitoring        `json:"monitoring"`
-	Alerts                Alerts            `json:"alerts"`
-	Migration             Migration         `json:"migration"`
-	SMController          SMController      `json:"smController"`
-	UMController          UMController      `json:"umController"`
+	Crypt                   Crypt                        `json:"fcrypt"`
+	CertStorage             string                       `json:"certStorage"`
+	ServiceDiscoveryURL     string                       `json:"serviceDiscoveryUrl"`
+	IAMProtectedServerURL   string                       `json:"iamProtectedServerUrl"`
+	IAMPublicServerURL      string                       `json:"iamPublicServerUrl"`
+	CMServerURL             string                       `json:"cmServerUrl"`
+	Downloader              Downloader                   `json:"downloader"`
+	StorageDir              string                       `json:"storageDir"`
+	StateDir                string                       `json:"stateDir"`
+	WorkingDir              string                       `json:"workingDir"`
+	ImageStoreDir           string                       `json:"imageStoreDir"`
+	ComponentsDir           string                       `json:"componentsDir"`
+	UnitConfigFile          string                       `json:"unitConfigFile"`
+	ServiceTTLDays          uint64                       `json:"serviceTtlDays"`
+	LayerTTLDays            uint64                       `json:"layerTtlDays"`
+	GIDPoolWarningThreshold int                          `json:"gidPoolWarningThreshold,omitempty"`
+	CachePolicy             CachePolicy                  `json:"cachePolicy"`
+	UpdateRetryBudget       RetryBudget                  `json:"updateRetryBudget"`
+	UpdatePolicy            UpdatePolicy                 `json:"updatePolicy"`
+	CompressState           bool                         `json:"compressState"`
+	VerifyStateIntegrity    bool                         `json:"verifyStateIntegrity,omitempty"`
+	UnitStatusSendTimeout   aostypes.Duration            `json:"unitStatusSendTimeout"`
+	UnitStatusSendJitter    uint                         `json:"unitStatusSendJitter"`
+	UnknownMessageLogLevel  string                       `json:"unknownMessageLogLevel,omitempty"`
+	ObserverMode            bool                         `json:"observerMode,omitempty"`
+	MaxClockSkew            aostypes.Duration            `json:"maxClockSkew,omitempty"`
+	RestartDuringUpdate     string                       `json:"restartDuringUpdate,omitempty"`
+	AMQPConsumer            AMQPConsumer                 `json:"amqpConsumer"`
+	PendingMessagesFile     string                       `json:"pendingMessagesFile,omitempty"`
+	UnitStatusChunkSize     int                          `json:"unitStatusChunkSize,omitempty"`
+	AMQPMessageEncoding     string                       `json:"amqpMessageEncoding,omitempty"`
+	AMQPMessageTTL          map[string]aostypes.Duration `json:"amqpMessageTtl,omitempty"`
+	MetricsListenAddress    string                       `json:"metricsListenAddress,omitempty"`
+	Monitoring              Monitoring                   `json:"monitoring"`
+	Alerts                  Alerts                       `json:"alerts"`
+	Migration               Migration                    `json:"migration"`
+	SMController            SMController                 `json:"smController"`
+	UMController            UMController                 `json:"umController"`
 }
 
 /***********************************************************************************************************************
@@ -145,18 +245,31 @@ func New(fileName string) (config *Config, err error) {
 			RetryDelay:             aostypes.Duration{Duration: 1 * time.Minute},
 			MaxRetryDelay:          aostypes.Duration{Duration: 30 * time.Minute},
 			DownloadPartLimit:      100,
+			ShutdownTimeout:        aostypes.Duration{Duration: 10 * time.Second},
 		},
 		SMController: SMController{
 			NodesConnectionTimeout: aostypes.Duration{Duration: 10 * time.Minute},
 			UpdateTTL:              aostypes.Duration{Duration: 30 * 24 * time.Hour},
+			NodeCircuitBreaker: CircuitBreaker{
+				FailureWindow: aostypes.Duration{Duration: 1 * time.Minute},
+				Cooldown:      aostypes.Duration{Duration: 5 * time.Minute},
+			},
 		},
 		UMController: UMController{UpdateTTL: aostypes.Duration{Duration: 30 * 24 * time.Hour}},
+		UpdateRetryBudget: RetryBudget{
+			RetryDelay:    aostypes.Duration{Duration: 1 * time.Minute},
+			MaxRetryDelay: aostypes.Duration{Duration: 30 * time.Minute},
+		},
 	}
 
 	if err = json.Unmarshal(raw, &config); err != nil {
 		return config, aoserrors.Wrap(err)
 	}
 
+	if err = applyEnvOverrides(config); err != nil {
+		return config, aoserrors.Wrap(err)
+	}
+
 	if config.CertStorage == "" {
 		config.CertStorage = "/var/aos/crypt/cm/"
 	}
@@ -193,5 +306,126 @@ func New(fileName string) (config *Config, err error) {
 		config.Migration.MergedMigrationPath = path.Join(config.WorkingDir, "migration")
 	}
 
+	if err = validate(config); err != nil {
+		return config, aoserrors.Wrap(err)
+	}
+
 	return config, nil
 }
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+// validate checks configuration values which can't be detected on unmarshal.
+func validate(config *Config) error {
+	switch config.SMController.RemoteLayerDelivery {
+	case "", "pull", "push":
+
+	default:
+		return aoserrors.Errorf("invalid remote layer delivery: %s", config.SMController.RemoteLayerDelivery)
+	}
+
+	switch config.SMController.NodeTieBreaker {
+	case "", "lexicographic", "leastLoaded", "mostFreeRAM":
+
+	default:
+		return aoserrors.Errorf("invalid node tie-breaker: %s", config.SMController.NodeTieBreaker)
+	}
+
+	switch config.SMController.RepeatedRunStatus {
+	case "", "skip", "send":
+
+	default:
+		return aoserrors.Errorf("invalid repeated run status: %s", config.SMController.RepeatedRunStatus)
+	}
+
+	return nil
+}
+
+// getEnvOverrides returns configuration fields which can be overridden by environment variables. Variable name is
+// EnvPrefix followed by upper snake case JSON path of the field. Lists are comma separated, durations use the same
+// format as configuration file.
+func getEnvOverrides(config *Config) map[string]interface{} {
+	return map[string]interface{}{
+		"SERVICE_DISCOVERY_URL":                  &config.ServiceDiscoveryURL,
+		"IAM_PROTECTED_SERVER_URL":               &config.IAMProtectedServerURL,
+		"IAM_PUBLIC_SERVER_URL":                  &config.IAMPublicServerURL,
+		"CM_SERVER_URL":                          &config.CMServerURL,
+		"CERT_STORAGE":                           &config.CertStorage,
+		"WORKING_DIR":                            &config.WorkingDir,
+		"STORAGE_DIR":                            &config.StorageDir,
+		"STATE_DIR":                              &config.StateDir,
+		"UNIT_STATUS_SEND_TIMEOUT":               &config.UnitStatusSendTimeout,
+		"DOWNLOADER_DOWNLOAD_DIR":                &config.Downloader.DownloadDir,
+		"DOWNLOADER_MAX_CONCURRENT_DOWNLOADS":    &config.Downloader.MaxConcurrentDownloads,
+		"SM_CONTROLLER_FILE_SERVER_URL":          &config.SMController.FileServerURL,
+		"SM_CONTROLLER_CM_SERVER_URL":            &config.SMController.CMServerURL,
+		"SM_CONTROLLER_NODE_IDS":                 &config.SMController.NodeIDs,
+		"SM_CONTROLLER_NODES_CONNECTION_TIMEOUT": &config.SMController.NodesConnectionTimeout,
+		"SM_CONTROLLER_UPDATE_TTL":               &config.SMController.UpdateTTL,
+		"UM_CONTROLLER_FILE_SERVER_URL":          &config.UMController.FileServerURL,
+		"UM_CONTROLLER_CM_SERVER_URL":            &config.UMController.CMServerURL,
+		"UM_CONTROLLER_UPDATE_TTL":               &config.UMController.UpdateTTL,
+		"UM_CONTROLLER_CONNECTION_TIMEOUT":       &config.UMController.ConnectionTimeout,
+	}
+}
+
+func applyEnvOverrides(config *Config) error {
+	overrides := getEnvOverrides(config)
+
+	names := make([]string, 0, len(overrides))
+
+	for name := range overrides {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, ok := os.LookupEnv(EnvPrefix + name)
+		if !ok {
+			continue
+		}
+
+		if err := setEnvValue(overrides[name], value); err != nil {
+			return aoserrors.Errorf("invalid %s value: %v", EnvPrefix+name, err)
+		}
+	}
+
+	return nil
+}
+
+func setEnvValue(field interface{}, value string) error {
+	switch field := field.(type) {
+	case *string:
+		*field = value
+
+	case *int:
+		intValue, err := strconv.Atoi(value)
+		if err != nil {
+			return aoserrors.Wrap(err)
+		}
+
+		*field = intValue
+
+	case *[]string:
+		*field = []string{}
+
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*field = append(*field, item)
+			}
+		}
+
+	case *aostypes.Duration:
+		if err := json.Unmarshal([]byte(strconv.Quote(value)), field); err != nil {
+			return aoserrors.Wrap(err)
+		}
+
+	default:
+		return aoserrors.Errorf("unsupported field type %T", field)
+	}
+
+	return nil
+}
@@ -54,13 +54,50 @@ const testConfigContent = `{
 	"componentsDir": "componentDir",
 	"serviceTtlDays": 30,
 	"layerTtlDays": 40,
+	"gidPoolWarningThreshold": 80,
+	"cachePolicy": {
+		"maxItems": 10,
+		"maxSize": 1048576
+	},
+	"updateRetryBudget": {
+		"maxAttempts": 5,
+		"maxTime": "2h",
+		"retryDelay": "30s"
+	},
+	"updatePolicy": {
+		"blockedServices": ["service1"],
+		"blockedComponents": ["component1"],
+		"denyDowngrade": true
+	},
+	"unknownMessageLogLevel": "debug",
+	"amqpConsumer": {
+		"prefetchCount": 8,
+		"manualAck": true
+	},
+	"pendingMessagesFile": "/var/aos/pending.json",
+	"maxClockSkew": "5m",
+	"restartDuringUpdate": "wait",
+	"unitStatusChunkSize": 65536,
+	"amqpMessageEncoding": "cbor",
+	"amqpMessageTtl": {
+		"alerts": "10m",
+		"stateRequest": "1h"
+	},
+	"verifyStateIntegrity": true,
+	"metricsListenAddress": "localhost:9100",
 	"unitConfigFile" : "/var/aos/aos_unit.cfg",
 	"downloader": {
 		"downloadDir": "/path/to/download",
+		"firmwareDownloadDir": "/path/to/firmware",
+		"softwareDownloadDir": "/path/to/software",
+		"candidateDownloadDirs": ["/path/to/data1", "/path/to/data2"],
 		"maxConcurrentDownloads": 10,
 		"retryDelay": "10s",
 		"maxRetryDelay": "30s",
-		"downloadPartLimit": 57
+		"downloadPartLimit": 57,
+		"shutdownTimeout": "5s",
+		"maxDownloadRate": 1048576,
+		"downloadGroupWeights": {"firmware": 3, "software": 1}
 	},
 	"monitoring": {
 		"monitorConfig": {
@@ -85,6 +122,11 @@ const testConfigContent = `{
 		"maxOfflineMessages": 32,
 		"journalAlerts": {
 			"filter": ["(test)", "(regexp)"]
+		},
+		"aggregation": {
+			"window": "1m",
+			"maxAlertsPerTag": 10,
+			"exemptTags": ["systemAlert"]
 		}
 	},
 	"migration": {
@@ -96,7 +138,28 @@ const testConfigContent = `{
 		"cmServerUrl": "localhost:8093",
 		"nodeIds": [ "sm1", "sm2"],	
 		"nodesConnectionTimeout": "100s",
-		"updateTTL": "30h"
+		"updateTTL": "30h",
+		"stateCleanupGracePeriod": "5m",
+		"instanceStopTimeout": "20s",
+		"moveStopTimeout": "15s",
+		"nodeCircuitBreaker": {
+			"maxFailures": 3,
+			"cooldown": "2m",
+			"emitEvents": true
+		},
+		"deferOverCapacity": true,
+		"minNodesToBalance": 1,
+		"expectedNodeTypes": {
+			"sm1": "mainType"
+		},
+		"nodeTieBreaker": "leastLoaded",
+		"storageWriteMaxTry": 5,
+		"storageWriteRetryDelay": "200ms",
+		"unitConfigRetryPeriod": "15s",
+		"uidPoolLimit": 1000,
+		"uidPoolWarningThreshold": 90,
+		"remoteLayerDelivery": "push",
+		"repeatedRunStatus": "send"
 	},
 	"umController": {
 		"fileServerUrl":"localhost:8092",
@@ -104,9 +167,15 @@ const testConfigContent = `{
 		"umClients": [{
 			"umId": "um",
 			"priority": 0,
-			"isLocal": true
+			"isLocal": true,
+			"connectionTimeout": "20m"
 		}],
-		"updateTTL": "100h"
+		"updateTTL": "100h",
+		"connectionTimeout": "5m",
+		"disableAutoRevert": true,
+		"continueOnError": true,
+		"parallelApply": true,
+		"unitConfigOrder": "beforeComponents"
 	}
 }`
 
@@ -252,16 +321,31 @@ func TestGetAlertsConfig(t *testing.T) {
 	if !reflect.DeepEqual(testCfg.Alerts.JournalAlerts.Filter, filter) {
 		t.Errorf("Wrong filter value: %v", testCfg.Alerts.JournalAlerts.Filter)
 	}
+
+	aggregation := &config.AlertsAggregation{
+		Window: aostypes.Duration{Duration: time.Minute}, MaxAlertsPerTag: 10, ExemptTags: []string{"systemAlert"},
+	}
+
+	if !reflect.DeepEqual(testCfg.Alerts.Aggregation, aggregation) {
+		t.Errorf("Wrong aggregation value: %v", testCfg.Alerts.Aggregation)
+	}
 }
 
 func TestUMControllerConfig(t *testing.T) {
-	umClient := config.UMClientConfig{UMID: "um", Priority: 0, IsLocal: true}
+	umClient := config.UMClientConfig{
+		UMID: "um", Priority: 0, IsLocal: true, ConnectionTimeout: aostypes.Duration{Duration: 20 * time.Minute},
+	}
 
 	originalConfig := config.UMController{
-		FileServerURL: "localhost:8092",
-		CMServerURL:   "localhost:8091",
-		UMClients:     []config.UMClientConfig{umClient},
-		UpdateTTL:     aostypes.Duration{Duration: 100 * time.Hour},
+		FileServerURL:     "localhost:8092",
+		CMServerURL:       "localhost:8091",
+		UMClients:         []config.UMClientConfig{umClient},
+		UpdateTTL:         aostypes.Duration{Duration: 100 * time.Hour},
+		ConnectionTimeout: aostypes.Duration{Duration: 5 * time.Minute},
+		DisableAutoRevert: true,
+		ContinueOnError:   true,
+		ParallelApply:     true,
+		UnitConfigOrder:   "beforeComponents",
 	}
 
 	if !reflect.DeepEqual(originalConfig, testCfg.UMController) {
@@ -272,10 +356,16 @@ func TestUMControllerConfig(t *testing.T) {
 func TestDownloaderConfig(t *testing.T) {
 	originalConfig := config.Downloader{
 		DownloadDir:            "/path/to/download",
+		FirmwareDownloadDir:    "/path/to/firmware",
+		SoftwareDownloadDir:    "/path/to/software",
+		CandidateDownloadDirs:  []string{"/path/to/data1", "/path/to/data2"},
 		MaxConcurrentDownloads: 10,
 		RetryDelay:             aostypes.Duration{Duration: 10 * time.Second},
 		MaxRetryDelay:          aostypes.Duration{Duration: 30 * time.Second},
 		DownloadPartLimit:      57,
+		ShutdownTimeout:        aostypes.Duration{Duration: 5 * time.Second},
+		MaxDownloadRate:        1048576,
+		DownloadGroupWeights:   map[string]uint{"firmware": 3, "software": 1},
 	}
 
 	if !reflect.DeepEqual(originalConfig, testCfg.Downloader) {
@@ -285,11 +375,31 @@ func TestDownloaderConfig(t *testing.T) {
 
 func TestSMControllerConfig(t *testing.T) {
 	originalConfig := config.SMController{
-		FileServerURL:          "localhost:8094",
-		CMServerURL:            "localhost:8093",
-		NodeIDs:                []string{"sm1", "sm2"},
-		NodesConnectionTimeout: aostypes.Duration{Duration: 100 * time.Second},
-		UpdateTTL:              aostypes.Duration{Duration: 30 * time.Hour},
+		FileServerURL:           "localhost:8094",
+		CMServerURL:             "localhost:8093",
+		NodeIDs:                 []string{"sm1", "sm2"},
+		NodesConnectionTimeout:  aostypes.Duration{Duration: 100 * time.Second},
+		UpdateTTL:               aostypes.Duration{Duration: 30 * time.Hour},
+		StateCleanupGracePeriod: aostypes.Duration{Duration: 5 * time.Minute},
+		InstanceStopTimeout:     aostypes.Duration{Duration: 20 * time.Second},
+		MoveStopTimeout:         aostypes.Duration{Duration: 15 * time.Second},
+		NodeCircuitBreaker: config.CircuitBreaker{
+			MaxFailures:   3,
+			FailureWindow: aostypes.Duration{Duration: 1 * time.Minute},
+			Cooldown:      aostypes.Duration{Duration: 2 * time.Minute},
+			EmitEvents:    true,
+		},
+		DeferOverCapacity:       true,
+		MinNodesToBalance:       1,
+		ExpectedNodeTypes:       map[string]string{"sm1": "mainType"},
+		NodeTieBreaker:          "leastLoaded",
+		StorageWriteMaxTry:      5,
+		StorageWriteRetryDelay:  aostypes.Duration{Duration: 200 * time.Millisecond},
+		UnitConfigRetryPeriod:   aostypes.Duration{Duration: 15 * time.Second},
+		UIDPoolLimit:            1000,
+		UIDPoolWarningThreshold: 90,
+		RemoteLayerDelivery:     "push",
+		RepeatedRunStatus:       "send",
 	}
 
 	if !reflect.DeepEqual(originalConfig, testCfg.SMController) {
@@ -331,12 +441,201 @@ func TestGetServiceTTLDays(t *testing.T) {
 	}
 }
 
+func TestGIDPoolWarningThreshold(t *testing.T) {
+	if testCfg.GIDPoolWarningThreshold != 80 {
+		t.Errorf("Wrong GID pool warning threshold value: %d", testCfg.GIDPoolWarningThreshold)
+	}
+}
+
+func TestCachePolicyConfig(t *testing.T) {
+	originalConfig := config.CachePolicy{
+		MaxItems: 10,
+		MaxSize:  1048576,
+	}
+
+	if !reflect.DeepEqual(originalConfig, testCfg.CachePolicy) {
+		t.Errorf("Wrong cache policy value: %v", testCfg.CachePolicy)
+	}
+}
+
+func TestUpdateRetryBudgetConfig(t *testing.T) {
+	originalConfig := config.RetryBudget{
+		MaxAttempts:   5,
+		MaxTime:       aostypes.Duration{Duration: 2 * time.Hour},
+		RetryDelay:    aostypes.Duration{Duration: 30 * time.Second},
+		MaxRetryDelay: aostypes.Duration{Duration: 30 * time.Minute},
+	}
+
+	if !reflect.DeepEqual(originalConfig, testCfg.UpdateRetryBudget) {
+		t.Errorf("Wrong update retry budget value: %v", testCfg.UpdateRetryBudget)
+	}
+}
+
+func TestUpdatePolicyConfig(t *testing.T) {
+	originalConfig := config.UpdatePolicy{
+		BlockedServices:   []string{"service1"},
+		BlockedComponents: []string{"component1"},
+		DenyDowngrade:     true,
+	}
+
+	if !reflect.DeepEqual(originalConfig, testCfg.UpdatePolicy) {
+		t.Errorf("Wrong update policy value: %v", testCfg.UpdatePolicy)
+	}
+}
+
+func TestUnknownMessageLogLevel(t *testing.T) {
+	if testCfg.UnknownMessageLogLevel != "debug" {
+		t.Errorf("Wrong unknown message log level value: %s", testCfg.UnknownMessageLogLevel)
+	}
+}
+
+func TestAMQPConsumerConfig(t *testing.T) {
+	originalConfig := config.AMQPConsumer{PrefetchCount: 8, ManualAck: true}
+
+	if !reflect.DeepEqual(originalConfig, testCfg.AMQPConsumer) {
+		t.Errorf("Wrong AMQP consumer value: %v", testCfg.AMQPConsumer)
+	}
+}
+
+func TestPendingMessagesFile(t *testing.T) {
+	if testCfg.PendingMessagesFile != "/var/aos/pending.json" {
+		t.Errorf("Wrong pending messages file value: %s", testCfg.PendingMessagesFile)
+	}
+}
+
+func TestAMQPMessageEncoding(t *testing.T) {
+	if testCfg.AMQPMessageEncoding != "cbor" {
+		t.Errorf("Wrong AMQP message encoding value: %s", testCfg.AMQPMessageEncoding)
+	}
+}
+
+func TestAMQPMessageTTL(t *testing.T) {
+	expectedTTL := map[string]aostypes.Duration{
+		"alerts":       {Duration: 10 * time.Minute},
+		"stateRequest": {Duration: time.Hour},
+	}
+
+	if !reflect.DeepEqual(testCfg.AMQPMessageTTL, expectedTTL) {
+		t.Errorf("Wrong AMQP message TTL value: %v", testCfg.AMQPMessageTTL)
+	}
+}
+
+func TestVerifyStateIntegrity(t *testing.T) {
+	if !testCfg.VerifyStateIntegrity {
+		t.Error("Wrong verify state integrity value")
+	}
+}
+
+func TestUnitStatusChunkSize(t *testing.T) {
+	if testCfg.UnitStatusChunkSize != 65536 {
+		t.Errorf("Wrong unit status chunk size value: %d", testCfg.UnitStatusChunkSize)
+	}
+}
+
+func TestMaxClockSkew(t *testing.T) {
+	if testCfg.MaxClockSkew.Duration != 5*time.Minute {
+		t.Errorf("Wrong max clock skew value: %v", testCfg.MaxClockSkew)
+	}
+}
+
+func TestMetricsListenAddress(t *testing.T) {
+	if testCfg.MetricsListenAddress != "localhost:9100" {
+		t.Errorf("Wrong metrics listen address value: %s", testCfg.MetricsListenAddress)
+	}
+}
+
+func TestRestartDuringUpdate(t *testing.T) {
+	if testCfg.RestartDuringUpdate != "wait" {
+		t.Errorf("Wrong restart during update value: %s", testCfg.RestartDuringUpdate)
+	}
+}
+
 func TestComponentStoreDir(t *testing.T) {
 	if testCfg.ComponentsDir != "componentDir" {
 		t.Errorf("Wrong components directory value: %s", testCfg.ComponentsDir)
 	}
 }
 
+func TestEnvOverrides(t *testing.T) {
+	t.Setenv(config.EnvPrefix+"SERVICE_DISCOVERY_URL", "www.override.com")
+	t.Setenv(config.EnvPrefix+"SM_CONTROLLER_NODE_IDS", "node1, node2,node3")
+	t.Setenv(config.EnvPrefix+"SM_CONTROLLER_NODES_CONNECTION_TIMEOUT", "15s")
+	t.Setenv(config.EnvPrefix+"DOWNLOADER_MAX_CONCURRENT_DOWNLOADS", "7")
+
+	cfg, err := config.New(path.Join(tmpDir, "aos_communicationmanager.cfg"))
+	if err != nil {
+		t.Fatalf("Can't create config: %v", err)
+	}
+
+	if cfg.ServiceDiscoveryURL != "www.override.com" {
+		t.Errorf("Wrong service discovery URL value: %s", cfg.ServiceDiscoveryURL)
+	}
+
+	if !reflect.DeepEqual(cfg.SMController.NodeIDs, []string{"node1", "node2", "node3"}) {
+		t.Errorf("Wrong node IDs value: %v", cfg.SMController.NodeIDs)
+	}
+
+	if cfg.SMController.NodesConnectionTimeout.Duration != 15*time.Second {
+		t.Errorf("Wrong nodes connection timeout value: %v", cfg.SMController.NodesConnectionTimeout)
+	}
+
+	if cfg.Downloader.MaxConcurrentDownloads != 7 {
+		t.Errorf("Wrong max concurrent downloads value: %d", cfg.Downloader.MaxConcurrentDownloads)
+	}
+
+	// Not overridden fields keep file values
+
+	if cfg.IAMPublicServerURL != testCfg.IAMPublicServerURL {
+		t.Errorf("Wrong IAM public server value: %s", cfg.IAMPublicServerURL)
+	}
+
+	if cfg.SMController.UpdateTTL != testCfg.SMController.UpdateTTL {
+		t.Errorf("Wrong SM controller update TTL value: %v", cfg.SMController.UpdateTTL)
+	}
+
+	t.Setenv(config.EnvPrefix+"SM_CONTROLLER_UPDATE_TTL", "invalid")
+
+	if _, err = config.New(path.Join(tmpDir, "aos_communicationmanager.cfg")); err == nil {
+		t.Error("Error expected on invalid duration override")
+	}
+}
+
+func TestInvalidRemoteLayerDelivery(t *testing.T) {
+	fileName := path.Join(tmpDir, "invalid_delivery.cfg")
+
+	if err := os.WriteFile(fileName, []byte(`{"smController": {"remoteLayerDelivery": "invalid"}}`), 0o600); err != nil {
+		t.Fatalf("Can't create config file: %v", err)
+	}
+
+	if _, err := config.New(fileName); err == nil {
+		t.Error("Error expected on invalid remote layer delivery")
+	}
+}
+
+func TestInvalidNodeTieBreaker(t *testing.T) {
+	fileName := path.Join(tmpDir, "invalid_tie_breaker.cfg")
+
+	if err := os.WriteFile(fileName, []byte(`{"smController": {"nodeTieBreaker": "invalid"}}`), 0o600); err != nil {
+		t.Fatalf("Can't create config file: %v", err)
+	}
+
+	if _, err := config.New(fileName); err == nil {
+		t.Error("Error expected on invalid node tie-breaker")
+	}
+}
+
+func TestInvalidRepeatedRunStatus(t *testing.T) {
+	fileName := path.Join(tmpDir, "invalid_repeated_run_status.cfg")
+
+	if err := os.WriteFile(fileName, []byte(`{"smController": {"repeatedRunStatus": "invalid"}}`), 0o600); err != nil {
+		t.Fatalf("Can't create config file: %v", err)
+	}
+
+	if _, err := config.New(fileName); err == nil {
+		t.Error("Error expected on invalid repeated run status")
+	}
+}
+
 /***********************************************************************************************************************
  * Private
  **********************************************************************************************************************/
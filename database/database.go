@@ -18,10 +18,13 @@
 package database
 
 import (
+	"bytes"
+	"compress/gzip"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -61,13 +64,17 @@ const dbFileName = "communicationmanager.db"
 
 var errNotExist = errors.New("entry does not exist")
 
+//nolint:gochecknoglobals // gzip header magic bytes
+var gzipMagic = []byte{0x1f, 0x8b}
+
 /***********************************************************************************************************************
  * Types
  **********************************************************************************************************************/
 
 // Database structure with database information.
 type Database struct {
-	sql *sql.DB
+	sql           *sql.DB
+	compressState bool
 }
 
 /***********************************************************************************************************************
@@ -95,7 +102,7 @@ func New(config *config.Config) (db *Database, err error) {
 		return db, aoserrors.Wrap(err)
 	}
 
-	db = &Database{sqlite}
+	db = &Database{sql: sqlite, compressState: config.CompressState}
 
 	defer func() {
 		if err != nil {
@@ -151,6 +158,10 @@ func New(config *config.Config) (db *Database, err error) {
 		return db, err
 	}
 
+	if err := db.createUpdateHistoryTable(); err != nil {
+		return db, err
+	}
+
 	return db, nil
 }
 
@@ -183,6 +194,7 @@ func (db *Database) SetComponentsUpdateInfo(updateInfo []umcontroller.SystemComp
 		return aoserrors.Wrap(err)
 	}
 
+	// Single statement update is performed atomically by SQLite transaction
 	if err = db.executeQuery(`UPDATE config SET componentsUpdateInfo = ?`, dataJSON); err != nil {
 		return err
 	}
@@ -217,7 +229,10 @@ func (db *Database) GetComponentsUpdateInfo() (updateInfo []umcontroller.SystemC
 	}
 
 	if err = json.Unmarshal(dataJSON, &updateInfo); err != nil {
-		return updateInfo, aoserrors.Wrap(err)
+		// Don't apply corrupted update info: start from clean state
+		log.Errorf("Components update info is corrupted and will be ignored: %v", err)
+
+		return nil, nil
 	}
 
 	return updateInfo, nil
@@ -225,7 +240,12 @@ func (db *Database) GetComponentsUpdateInfo() (updateInfo []umcontroller.SystemC
 
 // SetFirmwareUpdateState sets FOTA update state.
 func (db *Database) SetFirmwareUpdateState(state json.RawMessage) (err error) {
-	if err = db.executeQuery(`UPDATE config SET fotaUpdateState = ?`, state); err != nil {
+	data, err := db.packState(state)
+	if err != nil {
+		return err
+	}
+
+	if err = db.executeQuery(`UPDATE config SET fotaUpdateState = ?`, data); err != nil {
 		return err
 	}
 
@@ -240,14 +260,21 @@ func (db *Database) GetFirmwareUpdateState() (state json.RawMessage, err error)
 		if errors.Is(err, errNotExist) {
 			return state, downloader.ErrNotExist
 		}
+
+		return state, err
 	}
 
-	return state, err
+	return unpackState(state)
 }
 
 // SetSoftwareUpdateState sets SOTA update state.
 func (db *Database) SetSoftwareUpdateState(state json.RawMessage) (err error) {
-	if err = db.executeQuery(`UPDATE config SET sotaUpdateState = ?`, state); err != nil {
+	data, err := db.packState(state)
+	if err != nil {
+		return err
+	}
+
+	if err = db.executeQuery(`UPDATE config SET sotaUpdateState = ?`, data); err != nil {
 		return err
 	}
 
@@ -262,14 +289,44 @@ func (db *Database) GetSoftwareUpdateState() (state json.RawMessage, err error)
 		if errors.Is(err, errNotExist) {
 			return state, downloader.ErrNotExist
 		}
+
+		return state, err
 	}
 
-	return state, err
+	return unpackState(state)
+}
+
+// SetUpdateHistory sets update history.
+func (db *Database) SetUpdateHistory(history json.RawMessage) (err error) {
+	if err = db.executeQuery("UPDATE updatehistory SET history = ?", history); errors.Is(err, errNotExist) {
+		return db.executeQuery("INSERT INTO updatehistory values(?)", history)
+	}
+
+	return err
+}
+
+// GetUpdateHistory returns update history.
+func (db *Database) GetUpdateHistory() (history json.RawMessage, err error) {
+	if err = db.getDataFromQuery(
+		"SELECT history FROM updatehistory", []any{}, &history); err != nil {
+		if errors.Is(err, errNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return history, nil
 }
 
 // SetDesiredInstances sets desired instances status.
 func (db *Database) SetDesiredInstances(instances json.RawMessage) (err error) {
-	if err = db.executeQuery(`UPDATE config SET desiredInstances = ?`, instances); err != nil {
+	data, err := db.packState(instances)
+	if err != nil {
+		return err
+	}
+
+	if err = db.executeQuery(`UPDATE config SET desiredInstances = ?`, data); err != nil {
 		return err
 	}
 
@@ -284,9 +341,11 @@ func (db *Database) GetDesiredInstances() (instances json.RawMessage, err error)
 		if errors.Is(err, errNotExist) {
 			return instances, launcher.ErrNotExist
 		}
+
+		return instances, err
 	}
 
-	return instances, err
+	return unpackState(instances)
 }
 
 func (db *Database) GetDownloadInfo(filePath string) (downloadInfo downloader.DownloadInfo, err error) {
@@ -795,6 +854,46 @@ func (db *Database) Close() {
  * Private
  **********************************************************************************************************************/
 
+func (db *Database) packState(state json.RawMessage) ([]byte, error) {
+	if !db.compressState {
+		return state, nil
+	}
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	if _, err := writer.Write(state); err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func unpackState(data []byte) (json.RawMessage, error) {
+	// Uncompressed state is stored as is
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+	defer reader.Close()
+
+	state, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	return state, nil
+}
+
 func (db *Database) getDataFromQuery(query string, queryParams []interface{}, result ...interface{}) error {
 	stmt, err := db.sql.Prepare(query)
 	if err != nil {
@@ -955,6 +1054,14 @@ func (db *Database) createNodeStateTable() (err error) {
 	return aoserrors.Wrap(err)
 }
 
+func (db *Database) createUpdateHistoryTable() (err error) {
+	log.Info("Create update history table")
+
+	_, err = db.sql.Exec(`CREATE TABLE IF NOT EXISTS updatehistory (history BLOB)`)
+
+	return aoserrors.Wrap(err)
+}
+
 func (db *Database) isTableExist(name string) (result bool, err error) {
 	rows, err := db.sql.Query("SELECT * FROM sqlite_master WHERE name = ? and type='table'", name)
 	if err != nil {
@@ -170,6 +170,39 @@ func TestComponentsUpdateInfo(t *testing.T) {
 	}
 }
 
+func TestCorruptedComponentsUpdateInfo(t *testing.T) {
+	testData := []umcontroller.SystemComponent{
+		{ID: "component1", VendorVersion: "v1", AosVersion: 1, URL: "url12", Sha512: []byte{1, 3, 90, 42}},
+	}
+
+	if err := testDB.SetComponentsUpdateInfo(testData); err != nil {
+		t.Fatalf("Can't set update manager's update info: %v", err)
+	}
+
+	var dataJSON []byte
+
+	if err := testDB.sql.QueryRow("SELECT componentsUpdateInfo FROM config").Scan(&dataJSON); err != nil {
+		t.Fatalf("Can't get raw update info: %v", err)
+	}
+
+	corruptedData := [][]byte{dataJSON[:len(dataJSON)/2], dataJSON[:len(dataJSON)-1]}
+
+	for _, data := range corruptedData {
+		if err := testDB.executeQuery("UPDATE config SET componentsUpdateInfo = ?", data); err != nil {
+			t.Fatalf("Can't set raw update info: %v", err)
+		}
+
+		updateInfo, err := testDB.GetComponentsUpdateInfo()
+		if err != nil {
+			t.Errorf("Can't get update manager's update info: %v", err)
+		}
+
+		if len(updateInfo) != 0 {
+			t.Errorf("Corrupted update info applied: %v", updateInfo)
+		}
+	}
+}
+
 func TestSotaFotaInstancesFields(t *testing.T) {
 	fotaState := json.RawMessage("fotaState")
 	sotaState := json.RawMessage("sotaState")
@@ -215,6 +248,92 @@ func TestSotaFotaInstancesFields(t *testing.T) {
 	}
 }
 
+func TestCompressedState(t *testing.T) {
+	testDB.compressState = true
+	defer func() { testDB.compressState = false }()
+
+	desiredInstances := make([]cloudprotocol.InstanceInfo, 0, 5000)
+
+	for i := 0; i < 5000; i++ {
+		desiredInstances = append(desiredInstances, cloudprotocol.InstanceInfo{
+			ServiceID: servicePrefix + strconv.Itoa(i), SubjectID: subjectPrefix + strconv.Itoa(i),
+			Priority: uint64(i), NumInstances: 2, Labels: []string{"label1", "label2"},
+		})
+	}
+
+	rawInstances, err := json.Marshal(desiredInstances)
+	if err != nil {
+		t.Fatalf("Can't marshal desired instances: %v", err)
+	}
+
+	if err := testDB.SetDesiredInstances(rawInstances); err != nil {
+		t.Fatalf("Can't set desired instances: %v", err)
+	}
+
+	var storedInstances []byte
+
+	if err := testDB.getDataFromQuery("SELECT desiredInstances FROM config", []any{}, &storedInstances); err != nil {
+		t.Fatalf("Can't get stored desired instances: %v", err)
+	}
+
+	if len(storedInstances) >= len(rawInstances) || storedInstances[0] != gzipMagic[0] ||
+		storedInstances[1] != gzipMagic[1] {
+		t.Error("Desired instances are not compressed")
+	}
+
+	retInstances, err := testDB.GetDesiredInstances()
+	if err != nil {
+		t.Fatalf("Can't get desired instances: %v", err)
+	}
+
+	var unmarshaledInstances []cloudprotocol.InstanceInfo
+
+	if err = json.Unmarshal(retInstances, &unmarshaledInstances); err != nil {
+		t.Fatalf("Can't unmarshal desired instances: %v", err)
+	}
+
+	if !reflect.DeepEqual(unmarshaledInstances, desiredInstances) {
+		t.Error("Incorrect desired instances")
+	}
+
+	sotaState := json.RawMessage(`{"currentState":"noUpdate"}`)
+
+	if err := testDB.SetSoftwareUpdateState(sotaState); err != nil {
+		t.Fatalf("Can't set SOTA state: %v", err)
+	}
+
+	retSota, err := testDB.GetSoftwareUpdateState()
+	if err != nil {
+		t.Fatalf("Can't get SOTA state: %v", err)
+	}
+
+	if string(retSota) != string(sotaState) {
+		t.Errorf("Incorrect SOTA state: %s", string(retSota))
+	}
+}
+
+func TestUncompressedStateCompatibility(t *testing.T) {
+	desiredInstances := json.RawMessage(`[{"serviceId":"serv0","subjectId":"subj0","priority":0,"numInstances":1}]`)
+
+	// Store uncompressed blob and read it with compression enabled
+
+	if err := testDB.SetDesiredInstances(desiredInstances); err != nil {
+		t.Fatalf("Can't set desired instances: %v", err)
+	}
+
+	testDB.compressState = true
+	defer func() { testDB.compressState = false }()
+
+	retInstances, err := testDB.GetDesiredInstances()
+	if err != nil {
+		t.Fatalf("Can't get desired instances: %v", err)
+	}
+
+	if string(retInstances) != string(desiredInstances) {
+		t.Errorf("Incorrect desired instances: %s", string(retInstances))
+	}
+}
+
 func TestMultiThread(t *testing.T) {
 	const numIterations = 1000
 
@@ -1069,6 +1188,25 @@ func TestNodeState(t *testing.T) {
 	}
 }
 
+func TestUpdateHistory(t *testing.T) {
+	for _, setHistory := range []json.RawMessage{
+		json.RawMessage(`[{"type":"sota"}]`), json.RawMessage(`[{"type":"sota"},{"type":"fota"}]`),
+	} {
+		if err := testDB.SetUpdateHistory(setHistory); err != nil {
+			t.Fatalf("Can't set update history: %v", err)
+		}
+
+		getHistory, err := testDB.GetUpdateHistory()
+		if err != nil {
+			t.Errorf("Can't get update history: %v", err)
+		}
+
+		if string(setHistory) != string(getHistory) {
+			t.Errorf("Wrong update history: %s", string(getHistory))
+		}
+	}
+}
+
 func TestMigration(t *testing.T) {
 	migrationDBName := filepath.Join(tmpDir, "test_migration.db")
 	mergedMigrationDir := filepath.Join(tmpDir, "mergedMigration")
@@ -26,7 +26,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -52,6 +54,9 @@ const updateDownloadsTime = 30 * time.Second
 
 const encryptedFileExt = ".enc"
 
+// Packages are downloaded into tmp dir and moved to download dir after verification.
+const tmpDirName = "tmp"
+
 /***********************************************************************************************************************
  * Types
  **********************************************************************************************************************/
@@ -65,8 +70,18 @@ type Downloader struct {
 	sender           AlertSender
 	currentDownloads map[string]*downloadResult
 	waitQueue        *list.List
-	allocator        spaceallocator.Allocator
+	downloadDirs     []*downloadDir
+	rateLimiter      *rateLimiter
 	storage          Storage
+	statsHistory     []DownloadStat
+	downloadsWG      sync.WaitGroup
+	shuttingDown     bool
+}
+
+// Download dir with own tmp dir and space allocator.
+type downloadDir struct {
+	path      string
+	allocator spaceallocator.Allocator
 }
 
 // PackageInfo struct contains download info data.
@@ -87,6 +102,7 @@ type PackageInfo struct {
 	TargetID            string
 	TargetAosVersion    uint64
 	TargetVendorVersion string
+	ForceDownload       bool
 }
 
 // Storage provides API to add, remove, update or access download info data.
@@ -107,9 +123,14 @@ var (
 	//nolint:gochecknoglobals // used for unit test mock
 	NewSpaceAllocator = spaceallocator.New
 
+	// GetAvailableSpace returns available space of file system containing the path.
+	//nolint:gochecknoglobals // used for unit test mock
+	GetAvailableSpace = getAvailableSpace
+
 	// ErrNotExist not exist download info error.
 	ErrNotExist         = errors.New("download info not exist")
 	ErrPartlyDownloaded = errors.New("file not fully downloaded")
+	ErrShuttingDown     = errors.New("downloader is shutting down")
 )
 
 /***********************************************************************************************************************
@@ -131,18 +152,35 @@ func New(moduleID string, cfg *config.Config, sender AlertSender, storage Storag
 		storage:          storage,
 	}
 
-	if err = os.MkdirAll(downloader.config.DownloadDir, 0o755); err != nil {
-		return nil, aoserrors.Wrap(err)
+	if downloader.config.MaxDownloadRate > 0 {
+		downloader.rateLimiter = newRateLimiter(
+			downloader.config.MaxDownloadRate, downloader.config.DownloadGroupWeights)
 	}
 
-	downloader.allocator, err = NewSpaceAllocator(
-		downloader.config.DownloadDir, uint(downloader.config.DownloadPartLimit), downloader.removeOutdatedItem)
-	if err != nil {
-		return nil, aoserrors.Wrap(err)
+	for _, dirPath := range append([]string{
+		downloader.config.DownloadDir, downloader.config.FirmwareDownloadDir, downloader.config.SoftwareDownloadDir,
+	}, downloader.config.CandidateDownloadDirs...) {
+		if dirPath == "" || downloader.findDownloadDir(dirPath) != nil {
+			continue
+		}
+
+		if err = downloader.addDownloadDir(dirPath); err != nil {
+			if closeErr := downloader.Close(); closeErr != nil {
+				log.Errorf("Can't close downloader: %v", closeErr)
+			}
+
+			return nil, err
+		}
 	}
 
-	if err = downloader.setDownloadDirOutdated(); err != nil {
-		log.Errorf("Can't set download dir outdated: %v", err)
+	for _, dir := range downloader.downloadDirs {
+		if err = downloader.removeOrphanedTmpFiles(dir); err != nil {
+			log.WithField("dir", dir.path).Errorf("Can't remove orphaned tmp files: %v", err)
+		}
+
+		if err = downloader.setDownloadDirOutdated(dir); err != nil {
+			log.WithField("dir", dir.path).Errorf("Can't set download dir outdated: %v", err)
+		}
 	}
 
 	return downloader, nil
@@ -150,13 +188,72 @@ func New(moduleID string, cfg *config.Config, sender AlertSender, storage Storag
 
 // Close closes downloader.
 func (downloader *Downloader) Close() (err error) {
-	if downloadAllocatorErr := downloader.allocator.Close(); downloadAllocatorErr != nil && err == nil {
-		err = aoserrors.Wrap(downloadAllocatorErr)
+	for _, dir := range downloader.downloadDirs {
+		if downloadAllocatorErr := dir.allocator.Close(); downloadAllocatorErr != nil && err == nil {
+			err = aoserrors.Wrap(downloadAllocatorErr)
+		}
 	}
 
 	return err
 }
 
+// Shutdown gracefully stops downloader: queued downloads are canceled and in-flight downloads are given shutdown
+// timeout to finish. Downloads not finished in time are interrupted: their tmp files and download infos are kept,
+// so the downloads are resumed from the same offset after restart.
+func (downloader *Downloader) Shutdown() error {
+	downloader.Lock()
+
+	log.WithField("timeout", downloader.config.ShutdownTimeout.Duration).Debug("Shutdown downloader")
+
+	downloader.shuttingDown = true
+
+	for element := downloader.waitQueue.Front(); element != nil; element = downloader.waitQueue.Front() {
+		if result, ok := element.Value.(*downloadResult); ok {
+			log.WithField("id", result.id).Debug("Cancel queued download on shutdown")
+
+			result.cancelFunc()
+			result.statusChannel <- ErrShuttingDown
+		}
+
+		downloader.waitQueue.Remove(element)
+	}
+
+	downloader.Unlock()
+
+	downloadsDone := make(chan struct{})
+
+	go func() {
+		downloader.downloadsWG.Wait()
+		close(downloadsDone)
+	}()
+
+	select {
+	case <-downloadsDone:
+
+	case <-time.After(downloader.config.ShutdownTimeout.Duration):
+		downloader.Lock()
+
+		for _, result := range downloader.currentDownloads {
+			downloadedSize, err := getDownloadedSize(result)
+			if err != nil {
+				log.WithField("id", result.id).Errorf("Can't get downloaded size: %v", err)
+			}
+
+			log.WithFields(log.Fields{
+				"id": result.id, "resumeOffset": downloadedSize,
+			}).Warn("Interrupt download on shutdown")
+
+			result.cancelFunc()
+		}
+
+		downloader.Unlock()
+
+		<-downloadsDone
+	}
+
+	return downloader.Close()
+}
+
 // Download downloads, decrypts and verifies package.
 func (downloader *Downloader) Download(
 	ctx context.Context, packageInfo PackageInfo,
@@ -164,25 +261,87 @@ func (downloader *Downloader) Download(
 	downloader.Lock()
 	defer downloader.Unlock()
 
+	if downloader.shuttingDown {
+		return nil, aoserrors.Wrap(ErrShuttingDown)
+	}
+
 	id := base64.URLEncoding.EncodeToString(packageInfo.Sha256)
+	dir := downloader.selectDownloadDir(packageInfo, id)
+	downloadFileName := getDownloadFileName(dir, packageInfo, id)
+
+	downloadCtx, cancelFunc := context.WithCancel(ctx)
 
 	downloadResult := &downloadResult{
 		id:               id,
-		ctx:              ctx,
+		ctx:              downloadCtx,
+		cancelFunc:       cancelFunc,
 		packageInfo:      packageInfo,
 		statusChannel:    make(chan error, 1),
-		downloadFileName: path.Join(downloader.config.DownloadDir, id+encryptedFileExt),
+		downloadDir:      dir,
+		downloadFileName: downloadFileName,
+		tmpFileName:      dir.getTmpFileName(downloadFileName),
 	}
 
 	log.WithField("id", id).Debug("Download")
 
 	if err = downloader.addToQueue(downloadResult); err != nil {
+		cancelFunc()
+
 		return nil, aoserrors.Wrap(err)
 	}
 
 	return downloadResult, nil
 }
 
+// CancelDownload cancels in progress or queued downloads of specified target ID.
+func (downloader *Downloader) CancelDownload(id string) {
+	downloader.Lock()
+	defer downloader.Unlock()
+
+	for _, result := range downloader.currentDownloads {
+		if result.packageInfo.TargetID == id {
+			log.WithFields(log.Fields{"id": result.id, "targetID": id}).Debug("Cancel download")
+
+			result.cancelFunc()
+		}
+	}
+
+	for element := downloader.waitQueue.Front(); element != nil; {
+		next := element.Next()
+
+		if result, ok := element.Value.(*downloadResult); ok && result.packageInfo.TargetID == id {
+			log.WithFields(log.Fields{"id": result.id, "targetID": id}).Debug("Cancel queued download")
+
+			downloader.waitQueue.Remove(element)
+
+			result.cancelFunc()
+			result.statusChannel <- result.ctx.Err()
+		}
+
+		element = next
+	}
+}
+
+// GetDownloadStats returns throughput stats of recently finished and in progress downloads.
+func (downloader *Downloader) GetDownloadStats() []DownloadStat {
+	downloader.Lock()
+	defer downloader.Unlock()
+
+	stats := make([]DownloadStat, 0, len(downloader.statsHistory)+len(downloader.currentDownloads))
+
+	stats = append(stats, downloader.statsHistory...)
+
+	currentStats := make([]DownloadStat, 0, len(downloader.currentDownloads))
+
+	for _, result := range downloader.currentDownloads {
+		currentStats = append(currentStats, result.stat.get(result))
+	}
+
+	sort.Slice(currentStats, func(i, j int) bool { return currentStats[i].StartTime.Before(currentStats[j].StartTime) })
+
+	return append(stats, currentStats...)
+}
+
 func (downloader *Downloader) Release(filePath string) error {
 	downloadInfo, err := downloader.storage.GetDownloadInfo(filePath)
 	if err != nil {
@@ -193,7 +352,7 @@ func (downloader *Downloader) Release(filePath string) error {
 		return ErrPartlyDownloaded
 	}
 
-	if err := downloader.releaseDownload(downloadInfo.Path); err != nil {
+	if err := downloader.releaseDownload(downloadInfo); err != nil {
 		return err
 	}
 
@@ -215,7 +374,7 @@ func (downloader *Downloader) ReleaseByType(targetType string) error {
 			err = ErrPartlyDownloaded
 		}
 
-		if errDB := downloader.releaseDownload(downloadInfo.Path); errDB != nil && err == nil {
+		if errDB := downloader.releaseDownload(downloadInfo); errDB != nil && err == nil {
 			err = errDB
 		}
 	}
@@ -223,16 +382,169 @@ func (downloader *Downloader) ReleaseByType(targetType string) error {
 	return nil
 }
 
+// ReleaseByID releases downloads of specified target ID.
+func (downloader *Downloader) ReleaseByID(id string) error {
+	downloadInfos, err := downloader.storage.GetDownloadInfos()
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	for _, downloadInfo := range downloadInfos {
+		// Download path has the following format: <download dir>/<target type>/<target ID>/<file name>
+		if filepath.Base(filepath.Dir(downloadInfo.Path)) != id {
+			continue
+		}
+
+		if err = downloader.releaseDownload(downloadInfo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 /***********************************************************************************************************************
  * Private
  **********************************************************************************************************************/
 
-func (downloader *Downloader) releaseDownload(filePath string) error {
-	if err := downloader.setItemOutdated(filePath); err != nil {
+func (downloader *Downloader) addDownloadDir(dirPath string) (err error) {
+	if err = os.MkdirAll(dirPath, 0o755); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	dir := &downloadDir{path: dirPath}
+
+	if dir.allocator, err = NewSpaceAllocator(
+		dirPath, uint(downloader.config.DownloadPartLimit), downloader.removeOutdatedItem); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	downloader.downloadDirs = append(downloader.downloadDirs, dir)
+
+	return nil
+}
+
+func (downloader *Downloader) findDownloadDir(dirPath string) *downloadDir {
+	for _, dir := range downloader.downloadDirs {
+		if dir.path == dirPath {
+			return dir
+		}
+	}
+
+	return nil
+}
+
+func (downloader *Downloader) getDownloadDir(targetType string) *downloadDir {
+	if dir := downloader.getTargetTypeDownloadDir(targetType); dir != nil {
+		return dir
+	}
+
+	return downloader.findDownloadDir(downloader.config.DownloadDir)
+}
+
+// getTargetTypeDownloadDir returns download dir dedicated to the target type or nil if it is not configured.
+func (downloader *Downloader) getTargetTypeDownloadDir(targetType string) *downloadDir {
+	dirPath := downloader.config.SoftwareDownloadDir
+
+	if targetType == cloudprotocol.DownloadTargetComponent {
+		dirPath = downloader.config.FirmwareDownloadDir
+	}
+
+	if dirPath == "" {
+		return nil
+	}
+
+	return downloader.findDownloadDir(dirPath)
+}
+
+// selectDownloadDir selects download dir for the package. Target type dedicated download dir is always used if
+// configured. Otherwise, download dir is selected among candidate dirs able to allocate the package size: the one
+// with the most available space is selected. Download dir of previously started download of the same package is kept
+// to resume it. On equal available space or if no candidate is able to allocate the package, the first configured
+// candidate is selected.
+func (downloader *Downloader) selectDownloadDir(packageInfo PackageInfo, id string) *downloadDir {
+	if len(downloader.config.CandidateDownloadDirs) == 0 ||
+		downloader.getTargetTypeDownloadDir(packageInfo.TargetType) != nil {
+		return downloader.getDownloadDir(packageInfo.TargetType)
+	}
+
+	type candidateDir struct {
+		dir            *downloadDir
+		availableSpace uint64
+	}
+
+	candidates := make([]candidateDir, 0, len(downloader.config.CandidateDownloadDirs))
+
+	for _, dirPath := range downloader.config.CandidateDownloadDirs {
+		dir := downloader.findDownloadDir(dirPath)
+		if dir == nil {
+			continue
+		}
+
+		if _, err := downloader.storage.GetDownloadInfo(getDownloadFileName(dir, packageInfo, id)); err == nil {
+			return dir
+		}
+
+		availableSpace, err := GetAvailableSpace(dir.path)
+		if err != nil {
+			log.WithField("dir", dir.path).Errorf("Can't get available space: %v", err)
+
+			continue
+		}
+
+		candidates = append(candidates, candidateDir{dir: dir, availableSpace: availableSpace})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].availableSpace > candidates[j].availableSpace
+	})
+
+	for _, candidate := range candidates {
+		if !candidate.dir.canAllocateSpace(packageInfo.Size) {
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"dir": candidate.dir.path, "availableSpace": candidate.availableSpace,
+		}).Debug("Select download dir")
+
+		return candidate.dir
+	}
+
+	return downloader.findDownloadDir(downloader.config.CandidateDownloadDirs[0])
+}
+
+// getFileDownloadDir returns download dir containing the file. The most nested dir is returned as download dirs may
+// be nested.
+func (downloader *Downloader) getFileDownloadDir(filePath, targetType string) *downloadDir {
+	var fileDir *downloadDir
+
+	for _, dir := range downloader.downloadDirs {
+		if strings.HasPrefix(filePath, dir.path+string(os.PathSeparator)) &&
+			(fileDir == nil || len(dir.path) > len(fileDir.path)) {
+			fileDir = dir
+		}
+	}
+
+	if fileDir == nil {
+		return downloader.getDownloadDir(targetType)
+	}
+
+	return fileDir
+}
+
+func (downloader *Downloader) releaseDownload(downloadInfo DownloadInfo) error {
+	dir := downloader.getFileDownloadDir(downloadInfo.Path, downloadInfo.TargetType)
+
+	if err := downloader.setItemOutdated(dir, downloadInfo.Path); err != nil {
+		return err
+	}
+
+	if err := downloader.setItemOutdated(dir, dir.getTmpFileName(downloadInfo.Path)); err != nil {
 		return err
 	}
 
-	if err := downloader.storage.RemoveDownloadInfo(filePath); err != nil {
+	if err := downloader.storage.RemoveDownloadInfo(downloadInfo.Path); err != nil {
 		return aoserrors.Wrap(err)
 	}
 
@@ -273,9 +585,18 @@ func (downloader *Downloader) addToQueue(result *downloadResult) error {
 
 	downloader.currentDownloads[result.id] = result
 
+	result.stat.start()
+
+	downloader.downloadsWG.Add(1)
+
 	go func() {
+		defer downloader.downloadsWG.Done()
+
 		processErr := downloader.process(result)
 
+		result.cancelFunc()
+		result.stat.finish(processErr)
+
 		if err := downloader.acceptSpace(result); err != nil {
 			log.Errorf("Error accepting space: %v", err)
 		}
@@ -285,6 +606,8 @@ func (downloader *Downloader) addToQueue(result *downloadResult) error {
 
 		delete(downloader.currentDownloads, result.id)
 
+		downloader.addStatsHistory(result.stat.get(result))
+
 		result.statusChannel <- processErr
 
 		downloader.handleWaitQueue()
@@ -293,6 +616,14 @@ func (downloader *Downloader) addToQueue(result *downloadResult) error {
 	return nil
 }
 
+func (downloader *Downloader) addStatsHistory(stat DownloadStat) {
+	if len(downloader.statsHistory) >= maxDownloadStats {
+		downloader.statsHistory = downloader.statsHistory[1:]
+	}
+
+	downloader.statsHistory = append(downloader.statsHistory, stat)
+}
+
 func (downloader *Downloader) isResultInQueue(result *downloadResult) (present bool) {
 	// check current downloads
 	if _, ok := downloader.currentDownloads[result.id]; ok {
@@ -325,14 +656,15 @@ func (downloader *Downloader) tryAllocateSpace(result *downloadResult) (err erro
 		}
 	}()
 
-	downloader.allocator.RestoreOutdatedItem(result.downloadFileName)
+	result.downloadDir.allocator.RestoreOutdatedItem(result.downloadFileName)
+	result.downloadDir.allocator.RestoreOutdatedItem(result.tmpFileName)
 
-	requiredDownloadSize, err := downloader.getRequiredSize(result.downloadFileName, result.packageInfo.Size)
+	requiredDownloadSize, err := downloader.getRequiredSize(result, result.packageInfo.Size)
 	if err != nil {
 		return aoserrors.Wrap(err)
 	}
 
-	if result.downloadSpace, err = downloader.allocator.AllocateSpace(requiredDownloadSize); err != nil {
+	if result.downloadSpace, err = result.downloadDir.allocator.AllocateSpace(requiredDownloadSize); err != nil {
 		return aoserrors.Wrap(err)
 	}
 
@@ -344,49 +676,98 @@ func (downloader *Downloader) acceptSpace(result *downloadResult) (err error) {
 		err = downloadErr
 	}
 
-	downloadSize, downloadErr := getFileSize(result.downloadFileName)
+	downloadSize, downloadErr := getDownloadedSize(result)
 	if downloadErr != nil && err == nil {
 		err = downloadErr
 	}
 
 	// free space if file is not fully downloaded
 	if downloadSize < result.packageInfo.Size {
-		downloader.allocator.FreeSpace(result.packageInfo.Size - downloadSize)
+		result.downloadDir.allocator.FreeSpace(result.packageInfo.Size - downloadSize)
 	}
 
 	return err
 }
 
-func (downloader *Downloader) setDownloadDirOutdated() error {
+func (downloader *Downloader) removeOrphanedTmpFiles(dir *downloadDir) error {
 	downloadInfos, err := downloader.storage.GetDownloadInfos()
 	if err != nil {
 		return aoserrors.Wrap(err)
 	}
 
-	entries, err := os.ReadDir(downloader.config.DownloadDir)
+	if err = filepath.WalkDir(dir.getTmpDir(), func(tmpFilePath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+
+			return aoserrors.Wrap(err)
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		for _, downloadInfo := range downloadInfos {
+			// Referenced tmp file is partially downloaded or not promoted yet: keep it to resume download
+			if downloader.getFileDownloadDir(downloadInfo.Path, downloadInfo.TargetType) == dir &&
+				dir.getTmpFileName(downloadInfo.Path) == tmpFilePath {
+				return downloader.setItemOutdated(dir, tmpFilePath)
+			}
+		}
+
+		log.WithField("file", tmpFilePath).Debug("Remove orphaned tmp file")
+
+		if err := os.Remove(tmpFilePath); err != nil {
+			return aoserrors.Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (downloader *Downloader) setDownloadDirOutdated(dir *downloadDir) error {
+	downloadInfos, err := downloader.storage.GetDownloadInfos()
 	if err != nil {
 		return aoserrors.Wrap(err)
 	}
 
-nextEntry:
-	for _, entry := range entries {
-		downloadFilePath := filepath.Join(downloader.config.DownloadDir, entry.Name())
+	if err = filepath.WalkDir(dir.path, func(
+		downloadFilePath string, entry os.DirEntry, err error,
+	) error {
+		if err != nil {
+			return aoserrors.Wrap(err)
+		}
+
+		if entry.IsDir() {
+			// Skip tmp dir and nested download dirs: they are processed separately
+			if downloadFilePath == dir.getTmpDir() ||
+				(downloadFilePath != dir.path && downloader.findDownloadDir(downloadFilePath) != nil) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
 
 		for _, downloadInfo := range downloadInfos {
 			if downloadFilePath == downloadInfo.Path && downloadInfo.Downloaded {
-				continue nextEntry
+				return nil
 			}
 		}
 
-		if err := downloader.setItemOutdated(downloadFilePath); err != nil {
-			return aoserrors.Wrap(err)
-		}
+		return downloader.setItemOutdated(dir, downloadFilePath)
+	}); err != nil {
+		return aoserrors.Wrap(err)
 	}
 
 	return nil
 }
 
-func (downloader *Downloader) setItemOutdated(itemPath string) error {
+func (downloader *Downloader) setItemOutdated(dir *downloadDir, itemPath string) error {
 	var (
 		size      uint64
 		timestamp time.Time
@@ -409,7 +790,7 @@ func (downloader *Downloader) setItemOutdated(itemPath string) error {
 		}
 	}
 
-	if err := downloader.allocator.AddOutdatedItem(itemPath, size, timestamp); err != nil {
+	if err := dir.allocator.AddOutdatedItem(itemPath, size, timestamp); err != nil {
 		return aoserrors.Wrap(err)
 	}
 
@@ -430,15 +811,15 @@ func (downloader *Downloader) removeOutdatedItem(itemPath string) error {
 	return nil
 }
 
-func (downloader *Downloader) getRequiredSize(fileName string, totalSize uint64) (uint64, error) {
-	currentSize, err := getFileSize(fileName)
+func (downloader *Downloader) getRequiredSize(result *downloadResult, totalSize uint64) (uint64, error) {
+	currentSize, err := getDownloadedSize(result)
 	if err != nil {
 		return 0, aoserrors.Wrap(err)
 	}
 
 	if totalSize < currentSize {
 		log.WithFields(log.Fields{
-			"name":         fileName,
+			"name":         result.downloadFileName,
 			"expectedSize": totalSize,
 			"currentSize":  currentSize,
 		}).Warnf("File size is larger than expected")
@@ -488,7 +869,9 @@ func (downloader *Downloader) handleWaitQueue() {
 		}
 
 		if err != nil {
+			result.cancelFunc()
 			result.statusChannel <- err
+
 			continue
 		}
 
@@ -499,9 +882,25 @@ func (downloader *Downloader) handleWaitQueue() {
 }
 
 func (downloader *Downloader) downloadPackage(result *downloadResult) (err error) {
+	if result.packageInfo.ForceDownload {
+		// Downloaded file is replaced on promotion only after the new file is verified
+		log.WithFields(log.Fields{"id": result.id}).Debug("Force package download")
+
+		if err = os.RemoveAll(result.tmpFileName); err != nil {
+			return aoserrors.Wrap(err)
+		}
+	} else if _, err = os.Stat(result.downloadFileName); err == nil {
+		// Package could be already downloaded and promoted
+		if err = downloader.checkFile(result, result.downloadFileName); err == nil {
+			return nil
+		}
+
+		log.WithFields(log.Fields{"id": result.id}).Warnf("Downloaded file is invalid: %v", err)
+	}
+
 	if err = retryhelper.Retry(result.ctx,
 		func() (err error) {
-			fileSize, err := getFileSize(result.downloadFileName)
+			fileSize, err := getFileSize(result.tmpFileName)
 			if err != nil {
 				return aoserrors.Wrap(err)
 			}
@@ -512,30 +911,84 @@ func (downloader *Downloader) downloadPackage(result *downloadResult) (err error
 				}
 			}
 
-			if err = image.CheckFileInfo(result.ctx, result.downloadFileName, image.FileInfo{
-				Sha256: result.packageInfo.Sha256,
-				Sha512: result.packageInfo.Sha512,
-				Size:   result.packageInfo.Size,
-			}); err != nil {
-				if removeErr := os.RemoveAll(result.downloadFileName); removeErr != nil {
-					log.Errorf("Can't delete file %s: %s", result.downloadFileName, aoserrors.Wrap(removeErr))
-				}
-
+			if err = downloader.checkFile(result, result.tmpFileName); err != nil {
 				return aoserrors.Wrap(err)
 			}
 
-			return nil
+			return downloader.promoteFile(result)
 		},
 		func(retryCount int, delay time.Duration, err error) {
 			log.WithFields(log.Fields{"id": result.id}).Debugf("Retry download in %s", delay)
 		},
 		0, downloader.config.RetryDelay.Duration, downloader.config.MaxRetryDelay.Duration); err != nil {
+		if result.ctx.Err() != nil {
+			return aoserrors.Wrap(result.ctx.Err())
+		}
+
 		return aoserrors.New("can't download file from any source")
 	}
 
 	return nil
 }
 
+func (downloader *Downloader) checkFile(result *downloadResult, fileName string) error {
+	if err := image.CheckFileInfo(result.ctx, fileName, image.FileInfo{
+		Sha256: result.packageInfo.Sha256,
+		Sha512: result.packageInfo.Sha512,
+		Size:   result.packageInfo.Size,
+	}); err != nil {
+		if removeErr := os.RemoveAll(fileName); removeErr != nil {
+			log.Errorf("Can't delete file %s: %s", fileName, aoserrors.Wrap(removeErr))
+		}
+
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (downloader *Downloader) promoteFile(result *downloadResult) error {
+	if err := os.MkdirAll(filepath.Dir(result.downloadFileName), 0o755); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if err := os.Rename(result.tmpFileName, result.downloadFileName); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (dir *downloadDir) getTmpDir() string {
+	return filepath.Join(dir.path, tmpDirName)
+}
+
+func (dir *downloadDir) getTmpFileName(fileName string) string {
+	relPath, err := filepath.Rel(dir.path, fileName)
+	if err != nil {
+		relPath = filepath.Base(fileName)
+	}
+
+	return filepath.Join(dir.getTmpDir(), relPath)
+}
+
+// canAllocateSpace checks that the download dir space allocator is able to allocate the size. Allocated space is
+// released right away and allocated again when download is started.
+func (dir *downloadDir) canAllocateSpace(size uint64) bool {
+	space, err := dir.allocator.AllocateSpace(size)
+	if err != nil {
+		log.WithField("dir", dir.path).Debugf("Can't allocate space in download dir: %v", err)
+
+		return false
+	}
+
+	if err := space.Release(); err != nil {
+		log.WithField("dir", dir.path).Errorf("Can't release space: %v", err)
+	}
+
+	return true
+}
+
 func (downloader *Downloader) downloadURLs(result *downloadResult) (err error) {
 	fileDownloaded := false
 
@@ -562,7 +1015,19 @@ func (downloader *Downloader) download(url string, result *downloadResult) (err
 	timer := time.NewTicker(updateDownloadsTime)
 	defer timer.Stop()
 
-	req, err := grab.NewRequest(result.downloadFileName, url)
+	sampleTimer := time.NewTicker(throughputSampleTime)
+	defer sampleTimer.Stop()
+
+	if err = os.MkdirAll(filepath.Dir(result.tmpFileName), 0o755); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	resumedSize, err := getFileSize(result.tmpFileName)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	req, err := grab.NewRequest(result.tmpFileName, url)
 	if err != nil {
 		return aoserrors.Wrap(err)
 	}
@@ -570,26 +1035,29 @@ func (downloader *Downloader) download(url string, result *downloadResult) (err
 	req = req.WithContext(result.ctx)
 	req.Size = int64(result.packageInfo.Size)
 
+	if downloader.rateLimiter != nil {
+		limiter := downloader.rateLimiter.acquire(getDownloadGroup(result.packageInfo.TargetType))
+		defer limiter.release()
+
+		req.RateLimiter = limiter
+	}
+
 	resp := grab.DefaultClient.Do(req)
 
 	if !resp.DidResume {
-		log.WithFields(log.Fields{"url": url, "id": result.id}).Debug("Download started")
+		resumedSize = 0
+	}
 
-		downloader.sender.SendAlert(downloader.prepareDownloadAlert(resp, result, "Download started"))
-	} else {
-		downloadInfo, err := downloader.storage.GetDownloadInfo(result.downloadFileName)
-		if err != nil && !errors.Is(err, ErrNotExist) {
-			return aoserrors.Wrap(err)
+	defer func() {
+		if bytesComplete := uint64(resp.BytesComplete()); bytesComplete > resumedSize {
+			result.stat.addBytes(bytesComplete - resumedSize)
 		}
 
-		if !errors.Is(err, ErrNotExist) {
-			log.WithFields(log.Fields{
-				"url": url, "id": result.id, "reason": downloadInfo.InterruptReason,
-			}).Debug("Download resumed")
+		result.stat.updatePeak(uint64(resp.BytesPerSecond()))
+	}()
 
-			downloader.sender.SendAlert(downloader.prepareDownloadAlert(
-				resp, result, "Download resumed reason: "+downloadInfo.InterruptReason))
-		}
+	if err = downloader.notifyDownloadStarted(url, resp, result); err != nil {
+		return err
 	}
 
 	downloadInfo := DownloadInfo{
@@ -605,42 +1073,79 @@ func (downloader *Downloader) download(url string, result *downloadResult) (err
 
 	for {
 		select {
+		case <-sampleTimer.C:
+			result.stat.updatePeak(uint64(resp.BytesPerSecond()))
+
 		case <-timer.C:
 			downloader.sender.SendAlert(downloader.prepareDownloadAlert(resp, result, "Download status"))
 
 			log.WithFields(log.Fields{"complete": resp.BytesComplete(), "total": resp.Size}).Debug("Download progress")
 
 		case <-resp.Done:
-			if err = resp.Err(); err != nil {
-				log.WithFields(log.Fields{
-					"id":         result.id,
-					"file":       resp.Filename,
-					"downloaded": resp.BytesComplete(), "reason": err,
-				}).Warn("Download interrupted")
+			return downloader.finishDownload(resp, result, &downloadInfo)
+		}
+	}
+}
 
-				downloadInfo.InterruptReason = err.Error()
+func (downloader *Downloader) notifyDownloadStarted(url string, resp *grab.Response, result *downloadResult) error {
+	if !resp.DidResume {
+		log.WithFields(log.Fields{"url": url, "id": result.id}).Debug("Download started")
 
-				downloader.sender.SendAlert(downloader.prepareDownloadAlert(
-					resp, result, "Download interrupted reason: "+err.Error()))
+		downloader.sender.SendAlert(downloader.prepareDownloadAlert(resp, result, "Download started"))
 
-				return aoserrors.Wrap(err)
-			}
+		return nil
+	}
 
-			log.WithFields(log.Fields{
-				"id":         result.id,
-				"file":       resp.Filename,
-				"downloaded": resp.BytesComplete(),
-			}).Debug("Download completed")
+	downloadInfo, err := downloader.storage.GetDownloadInfo(result.downloadFileName)
+	if err != nil {
+		if errors.Is(err, ErrNotExist) {
+			return nil
+		}
+
+		return aoserrors.Wrap(err)
+	}
 
-			downloadInfo.Downloaded = true
+	log.WithFields(log.Fields{
+		"url": url, "id": result.id, "reason": downloadInfo.InterruptReason,
+	}).Debug("Download resumed")
 
-			downloader.sender.SendAlert(
-				downloader.prepareDownloadAlert(
-					resp, result, "Download finished code: "+strconv.Itoa(resp.HTTPResponse.StatusCode)))
+	downloader.sender.SendAlert(downloader.prepareDownloadAlert(
+		resp, result, "Download resumed reason: "+downloadInfo.InterruptReason))
 
-			return nil
-		}
+	return nil
+}
+
+func (downloader *Downloader) finishDownload(
+	resp *grab.Response, result *downloadResult, downloadInfo *DownloadInfo,
+) error {
+	if err := resp.Err(); err != nil {
+		log.WithFields(log.Fields{
+			"id":         result.id,
+			"file":       resp.Filename,
+			"downloaded": resp.BytesComplete(), "reason": err,
+		}).Warn("Download interrupted")
+
+		downloadInfo.InterruptReason = err.Error()
+
+		downloader.sender.SendAlert(downloader.prepareDownloadAlert(
+			resp, result, "Download interrupted reason: "+err.Error()))
+
+		return aoserrors.Wrap(err)
 	}
+
+	log.WithFields(log.Fields{
+		"id":         result.id,
+		"file":       resp.Filename,
+		"downloaded": resp.BytesComplete(),
+	}).Debug("Download completed")
+
+	downloadInfo.Downloaded = true
+
+	downloader.sender.SendAlert(
+		downloader.prepareDownloadAlert(
+			resp, result, "Download finished code: "+strconv.Itoa(resp.HTTPResponse.StatusCode)))
+
+	return nil
 }
 
 func (downloader *Downloader) prepareDownloadAlert(
@@ -662,6 +1167,28 @@ func (downloader *Downloader) prepareDownloadAlert(
 	}
 }
 
+func getDownloadFileName(dir *downloadDir, packageInfo PackageInfo, id string) string {
+	return path.Join(dir.path, packageInfo.TargetType, packageInfo.TargetID, id+encryptedFileExt)
+}
+
+func getAvailableSpace(dirPath string) (uint64, error) {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(dirPath, &stat); err != nil {
+		return 0, aoserrors.Wrap(err)
+	}
+
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
+func getDownloadedSize(result *downloadResult) (size uint64, err error) {
+	if size, err = getFileSize(result.downloadFileName); err != nil || size != 0 {
+		return size, err
+	}
+
+	return getFileSize(result.tmpFileName)
+}
+
 func getFileSize(fileName string) (size uint64, err error) {
 	var stat syscall.Stat_t
 
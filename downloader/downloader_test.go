@@ -24,7 +24,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path"
@@ -36,6 +38,7 @@ import (
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/aosedge/aos_common/aostypes"
 	"github.com/aosedge/aos_common/api/cloudprotocol"
 	"github.com/aosedge/aos_common/image"
 	"github.com/aosedge/aos_common/spaceallocator"
@@ -103,6 +106,7 @@ var (
 	downloadDir string
 
 	downloadAllocator = &testAllocator{}
+	dirAllocators     = make(map[string]*testAllocator)
 )
 
 /***********************************************************************************************************************
@@ -594,7 +598,8 @@ func TestConcurrentLimitSpaceDownloads(t *testing.T) {
 				t.Errorf("error CreateFileInfo: %v", err)
 			}
 
-			fileName := path.Join(downloadDir, base64.URLEncoding.EncodeToString(imageFileInfo.Sha256)+".enc")
+			fileName := path.Join(downloadDir, cloudprotocol.DownloadTargetLayer, "targetID",
+				base64.URLEncoding.EncodeToString(imageFileInfo.Sha256)+".enc")
 
 			if err := downloadInstance.Release(fileName); err != nil {
 				t.Errorf("Can't remove download file: %v", err)
@@ -753,6 +758,713 @@ func TestReleaseByType(t *testing.T) {
 	}
 }
 
+func TestReleaseByID(t *testing.T) {
+	sender := testAlertSender{}
+	downloadAllocator = &testAllocator{
+		totalSize: 3 * Megabyte,
+	}
+	testStorage := &testStorage{
+		data: make(map[string]downloader.DownloadInfo),
+	}
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			MaxConcurrentDownloads: 3,
+			DownloadPartLimit:      100,
+		},
+	}, &sender, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+	defer downloadInstance.Close()
+
+	targetIDs := []string{"service1", "service2", "service3"}
+
+	defer func() {
+		for _, targetID := range targetIDs {
+			os.RemoveAll(path.Join(serverDir, targetID+".txt"))
+		}
+	}()
+
+	for _, targetID := range targetIDs {
+		if err := generateFile(path.Join(serverDir, targetID+".txt"), 1*Megabyte); err != nil {
+			t.Fatalf("Can't generate file: %s", err)
+		}
+
+		packageInfo := preparePackageInfo(
+			"http://localhost:8001/", targetID+".txt", cloudprotocol.DownloadTargetService)
+		packageInfo.TargetID = targetID
+
+		result, err := downloadInstance.Download(context.Background(), packageInfo)
+		if err != nil {
+			t.Fatalf("Can't download package: %s", err)
+		}
+
+		if err = result.Wait(); err != nil {
+			t.Errorf("Download error: %v", err)
+		}
+	}
+
+	if err := downloadInstance.ReleaseByID("service2"); err != nil {
+		t.Errorf("Can't release download: %v", err)
+	}
+
+	if len(testStorage.data) != len(targetIDs)-1 {
+		t.Errorf("Wrong download info count: %d", len(testStorage.data))
+	}
+
+	for filePath := range testStorage.data {
+		if path.Base(path.Dir(filePath)) == "service2" {
+			t.Errorf("Download %s should be released", filePath)
+		}
+	}
+}
+
+func TestRemoveOrphanedTmpFiles(t *testing.T) {
+	downloadAllocator = &testAllocator{}
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	targetDir := filepath.Join(cloudprotocol.DownloadTargetLayer, "targetID")
+	referencedFile := filepath.Join(downloadDir, targetDir, "referenced.enc")
+	promotedFile := filepath.Join(downloadDir, targetDir, "promoted.enc")
+	referencedTmpFile := filepath.Join(downloadDir, "tmp", targetDir, "referenced.enc")
+	orphanedTmpFile := filepath.Join(downloadDir, "tmp", targetDir, "orphaned.enc")
+
+	for _, fileName := range []string{promotedFile, referencedTmpFile, orphanedTmpFile} {
+		if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+			t.Fatalf("Can't create dir: %v", err)
+		}
+
+		if err := os.WriteFile(fileName, []byte("partial data"), 0o600); err != nil {
+			t.Fatalf("Can't create file: %v", err)
+		}
+	}
+
+	testStorage := &testStorage{
+		data: map[string]downloader.DownloadInfo{
+			referencedFile: {Path: referencedFile, TargetType: cloudprotocol.DownloadTargetLayer},
+			promotedFile:   {Path: promotedFile, TargetType: cloudprotocol.DownloadTargetLayer, Downloaded: true},
+		},
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			MaxConcurrentDownloads: 1,
+			DownloadPartLimit:      100,
+		},
+	}, &testAlertSender{}, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+	defer downloadInstance.Close()
+
+	if _, err := os.Stat(orphanedTmpFile); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Orphaned tmp file should be removed")
+	}
+
+	for _, fileName := range []string{promotedFile, referencedTmpFile} {
+		if _, err := os.Stat(fileName); err != nil {
+			t.Errorf("File %s should not be removed: %v", fileName, err)
+		}
+	}
+}
+
+func TestDownloadDirPerUpdateType(t *testing.T) {
+	downloadAllocator = &testAllocator{}
+
+	firmwareDir := filepath.Join(tmpDir, "firmware")
+	softwareDir := filepath.Join(tmpDir, "software")
+
+	defer func() {
+		os.RemoveAll(firmwareDir)
+		os.RemoveAll(softwareDir)
+	}()
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	testStorage := &testStorage{
+		data: make(map[string]downloader.DownloadInfo),
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			FirmwareDownloadDir:    firmwareDir,
+			SoftwareDownloadDir:    softwareDir,
+			MaxConcurrentDownloads: 2,
+			DownloadPartLimit:      100,
+		},
+	}, &testAlertSender{}, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+	defer downloadInstance.Close()
+
+	cases := []struct {
+		fileName    string
+		targetType  string
+		expectedDir string
+	}{
+		{
+			fileName:    "component.txt",
+			targetType:  cloudprotocol.DownloadTargetComponent,
+			expectedDir: firmwareDir,
+		},
+		{
+			fileName:    "layer.txt",
+			targetType:  cloudprotocol.DownloadTargetLayer,
+			expectedDir: softwareDir,
+		},
+		{
+			fileName:    "service.txt",
+			targetType:  cloudprotocol.DownloadTargetService,
+			expectedDir: softwareDir,
+		},
+	}
+
+	for _, tCase := range cases {
+		if err := generateFile(path.Join(serverDir, tCase.fileName), 1*Kilobyte); err != nil {
+			t.Fatalf("Can't generate file: %s", err)
+		}
+
+		packageInfo := preparePackageInfo("http://localhost:8001/", tCase.fileName, tCase.targetType)
+
+		result, err := downloadInstance.Download(context.Background(), packageInfo)
+		if err != nil {
+			t.Fatalf("Can't download package: %s", err)
+		}
+
+		if err = result.Wait(); err != nil {
+			t.Fatalf("Download error: %v", err)
+		}
+
+		if !strings.HasPrefix(result.GetFileName(), tCase.expectedDir+string(os.PathSeparator)) {
+			t.Errorf("File %s is not in %s", result.GetFileName(), tCase.expectedDir)
+		}
+	}
+
+	if err := downloadInstance.ReleaseByType(cloudprotocol.DownloadTargetComponent); err != nil {
+		t.Fatalf("Can't release downloads: %v", err)
+	}
+
+	for _, downloadInfo := range testStorage.data {
+		if downloadInfo.TargetType == cloudprotocol.DownloadTargetComponent {
+			t.Error("Component download should be released")
+		}
+	}
+
+	if len(testStorage.data) != 2 {
+		t.Errorf("Unexpected download info count: %d", len(testStorage.data))
+	}
+}
+
+func TestSelectDownloadDirByAvailableSpace(t *testing.T) {
+	downloadAllocator = &testAllocator{}
+
+	smallDir := filepath.Join(tmpDir, "small")
+	largeDir := filepath.Join(tmpDir, "large")
+
+	defer func() {
+		os.RemoveAll(smallDir)
+		os.RemoveAll(largeDir)
+	}()
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	availableSpace := map[string]uint64{smallDir: 1 * Megabyte, largeDir: 10 * Megabyte}
+
+	originalGetAvailableSpace := downloader.GetAvailableSpace
+
+	downloader.GetAvailableSpace = func(dirPath string) (uint64, error) {
+		return availableSpace[dirPath], nil
+	}
+
+	defer func() { downloader.GetAvailableSpace = originalGetAvailableSpace }()
+
+	testStorage := &testStorage{
+		data: make(map[string]downloader.DownloadInfo),
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			CandidateDownloadDirs:  []string{smallDir, largeDir},
+			MaxConcurrentDownloads: 1,
+			DownloadPartLimit:      100,
+		},
+	}, &testAlertSender{}, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+	defer downloadInstance.Close()
+
+	fileName := "service.txt"
+
+	if err := generateFile(path.Join(serverDir, fileName), 1*Kilobyte); err != nil {
+		t.Fatalf("Can't generate file: %s", err)
+	}
+
+	defer os.RemoveAll(path.Join(serverDir, fileName))
+
+	result, err := downloadInstance.Download(context.Background(),
+		preparePackageInfo("http://localhost:8001/", fileName, cloudprotocol.DownloadTargetService))
+	if err != nil {
+		t.Fatalf("Can't download package: %s", err)
+	}
+
+	if err = result.Wait(); err != nil {
+		t.Fatalf("Download error: %v", err)
+	}
+
+	if !strings.HasPrefix(result.GetFileName(), largeDir+string(os.PathSeparator)) {
+		t.Errorf("File %s is not in %s", result.GetFileName(), largeDir)
+	}
+
+	if err := downloadInstance.Release(result.GetFileName()); err != nil {
+		t.Fatalf("Can't release download: %v", err)
+	}
+
+	if len(testStorage.data) != 0 {
+		t.Errorf("Unexpected download info count: %d", len(testStorage.data))
+	}
+}
+
+func TestSelectDownloadDirPerUpdateType(t *testing.T) {
+	downloadAllocator = &testAllocator{}
+
+	firmwareDir := filepath.Join(tmpDir, "firmware")
+	smallDir := filepath.Join(tmpDir, "small")
+	largeDir := filepath.Join(tmpDir, "large")
+
+	defer func() {
+		os.RemoveAll(firmwareDir)
+		os.RemoveAll(smallDir)
+		os.RemoveAll(largeDir)
+	}()
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	availableSpace := map[string]uint64{firmwareDir: 1 * Megabyte, smallDir: 5 * Megabyte, largeDir: 10 * Megabyte}
+
+	originalGetAvailableSpace := downloader.GetAvailableSpace
+
+	downloader.GetAvailableSpace = func(dirPath string) (uint64, error) {
+		return availableSpace[dirPath], nil
+	}
+
+	defer func() { downloader.GetAvailableSpace = originalGetAvailableSpace }()
+
+	// Large dir allocator can't allocate layer size and layer should be placed to small dir
+	dirAllocators[largeDir] = &testAllocator{totalSize: 3 * Kilobyte}
+
+	defer delete(dirAllocators, largeDir)
+
+	testStorage := &testStorage{
+		data: make(map[string]downloader.DownloadInfo),
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			FirmwareDownloadDir:    firmwareDir,
+			CandidateDownloadDirs:  []string{largeDir, smallDir},
+			MaxConcurrentDownloads: 1,
+			DownloadPartLimit:      100,
+		},
+	}, &testAlertSender{}, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+	defer downloadInstance.Close()
+
+	cases := []struct {
+		fileName    string
+		fileSize    uint64
+		targetType  string
+		expectedDir string
+	}{
+		{
+			fileName:    "component.txt",
+			fileSize:    1 * Kilobyte,
+			targetType:  cloudprotocol.DownloadTargetComponent,
+			expectedDir: firmwareDir,
+		},
+		{
+			fileName:    "service.txt",
+			fileSize:    1 * Kilobyte,
+			targetType:  cloudprotocol.DownloadTargetService,
+			expectedDir: largeDir,
+		},
+		{
+			fileName:    "layer.txt",
+			fileSize:    4 * Kilobyte,
+			targetType:  cloudprotocol.DownloadTargetLayer,
+			expectedDir: smallDir,
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Logf("Test item: %s", tCase.fileName)
+
+		if err := generateFile(path.Join(serverDir, tCase.fileName), tCase.fileSize); err != nil {
+			t.Fatalf("Can't generate file: %s", err)
+		}
+
+		defer os.RemoveAll(path.Join(serverDir, tCase.fileName))
+
+		result, err := downloadInstance.Download(context.Background(),
+			preparePackageInfo("http://localhost:8001/", tCase.fileName, tCase.targetType))
+		if err != nil {
+			t.Fatalf("Can't download package: %s", err)
+		}
+
+		if err = result.Wait(); err != nil {
+			t.Fatalf("Download error: %v", err)
+		}
+
+		if !strings.HasPrefix(result.GetFileName(), tCase.expectedDir+string(os.PathSeparator)) {
+			t.Errorf("File %s is not in %s", result.GetFileName(), tCase.expectedDir)
+		}
+	}
+
+	if err := downloadInstance.ReleaseByType(cloudprotocol.DownloadTargetComponent); err != nil {
+		t.Fatalf("Can't release downloads: %v", err)
+	}
+
+	for _, downloadInfo := range testStorage.data {
+		if downloadInfo.TargetType == cloudprotocol.DownloadTargetComponent {
+			t.Error("Component download should be released")
+		}
+	}
+
+	if len(testStorage.data) != 2 {
+		t.Errorf("Unexpected download info count: %d", len(testStorage.data))
+	}
+}
+
+func TestDownloadStats(t *testing.T) {
+	sender := testAlertSender{}
+	downloadAllocator = &testAllocator{}
+	testStorage := &testStorage{
+		data: make(map[string]downloader.DownloadInfo),
+	}
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			MaxConcurrentDownloads: 1,
+			DownloadPartLimit:      100,
+		},
+	}, &sender, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+	defer downloadInstance.Close()
+
+	fileSizes := []uint64{32 * 1024, 64 * 1024}
+
+	for i, size := range fileSizes {
+		fileName := path.Join(serverDir, fmt.Sprintf("package%d.txt", i))
+
+		if err := generateFile(fileName, size); err != nil {
+			t.Fatalf("Can't generate file: %v", err)
+		}
+		defer os.RemoveAll(fileName)
+
+		packageInfo := preparePackageInfo("http://localhost:8001/", fileName, cloudprotocol.DownloadTargetService)
+		packageInfo.TargetID = fmt.Sprintf("service%d", i)
+
+		result, err := downloadInstance.Download(context.Background(), packageInfo)
+		if err != nil {
+			t.Fatalf("Can't download package: %v", err)
+		}
+
+		if err = result.Wait(); err != nil {
+			t.Errorf("Download error: %v", err)
+		}
+	}
+
+	stats := downloadInstance.GetDownloadStats()
+
+	if len(stats) != len(fileSizes) {
+		t.Fatalf("Wrong download stats count: %d", len(stats))
+	}
+
+	for i, stat := range stats {
+		if stat.TargetID != fmt.Sprintf("service%d", i) {
+			t.Errorf("Wrong stat target ID: %s", stat.TargetID)
+		}
+
+		if stat.InProgress || stat.Error != "" {
+			t.Errorf("Wrong download state: in progress %v, error %s", stat.InProgress, stat.Error)
+		}
+
+		if stat.Bytes != fileSizes[i] {
+			t.Errorf("Wrong downloaded bytes: %d", stat.Bytes)
+		}
+
+		if stat.Duration <= 0 || stat.Duration > 10*time.Second {
+			t.Errorf("Wrong download duration: %v", stat.Duration)
+		}
+
+		expectedBps := float64(stat.Bytes) / stat.Duration.Seconds()
+
+		if math.Abs(float64(stat.AverageBps)-expectedBps) > 1 {
+			t.Errorf("Wrong average throughput: %d, expected: %.0f", stat.AverageBps, expectedBps)
+		}
+
+		if stat.PeakBps < stat.AverageBps {
+			t.Errorf("Peak throughput %d is less than average %d", stat.PeakBps, stat.AverageBps)
+		}
+	}
+}
+
+func TestWeightedDownloadRateSharing(t *testing.T) {
+	const (
+		fileSize        = Megabyte
+		maxDownloadRate = 2 * Megabyte
+		firmwareWeight  = 3
+		softwareWeight  = 1
+	)
+
+	sender := testAlertSender{}
+	downloadAllocator = &testAllocator{}
+	testStorage := &testStorage{
+		data: make(map[string]downloader.DownloadInfo),
+	}
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			MaxConcurrentDownloads: 2,
+			DownloadPartLimit:      100,
+			MaxDownloadRate:        maxDownloadRate,
+			DownloadGroupWeights: map[string]uint{
+				downloader.DownloadGroupFirmware: firmwareWeight,
+				downloader.DownloadGroupSoftware: softwareWeight,
+			},
+		},
+	}, &sender, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+	defer downloadInstance.Close()
+
+	fileIndex := 0
+
+	download := func(targetTypes ...string) {
+		packageInfos := make([]downloader.PackageInfo, 0, len(targetTypes))
+
+		for _, targetType := range targetTypes {
+			fileName := path.Join(serverDir, fmt.Sprintf("package%d.txt", fileIndex))
+			fileIndex++
+
+			if err := generateFile(fileName, fileSize); err != nil {
+				t.Fatalf("Can't generate file: %v", err)
+			}
+
+			packageInfo := preparePackageInfo("http://localhost:8001/", fileName, targetType)
+			packageInfo.TargetID = targetType
+
+			packageInfos = append(packageInfos, packageInfo)
+		}
+
+		results := make([]downloader.Result, 0, len(packageInfos))
+
+		for _, packageInfo := range packageInfos {
+			result, err := downloadInstance.Download(context.Background(), packageInfo)
+			if err != nil {
+				t.Fatalf("Can't download package: %v", err)
+			}
+
+			results = append(results, result)
+		}
+
+		for _, result := range results {
+			if err := result.Wait(); err != nil {
+				t.Errorf("Download error: %v", err)
+			}
+		}
+	}
+
+	// Single active group uses full capacity
+
+	download(cloudprotocol.DownloadTargetComponent)
+
+	stats := downloadInstance.GetDownloadStats()
+	if len(stats) != 1 {
+		t.Fatalf("Wrong download stats count: %d", len(stats))
+	}
+
+	checkDuration(t, stats[0].Duration, time.Duration(float64(fileSize)/float64(maxDownloadRate)*float64(time.Second)))
+
+	// Both groups active: bandwidth is shared according to weights
+
+	download(cloudprotocol.DownloadTargetComponent, cloudprotocol.DownloadTargetService)
+
+	stats = downloadInstance.GetDownloadStats()
+	if len(stats) != 3 {
+		t.Fatalf("Wrong download stats count: %d", len(stats))
+	}
+
+	// Firmware downloads with its share while software is active. Software downloads the rest with full capacity
+	// after firmware is done.
+	firmwareRate := float64(maxDownloadRate) * firmwareWeight / (firmwareWeight + softwareWeight)
+	firmwareTime := float64(fileSize) / firmwareRate
+	softwareTime := firmwareTime + (float64(fileSize)-(float64(maxDownloadRate)-firmwareRate)*firmwareTime)/
+		float64(maxDownloadRate)
+
+	for _, stat := range stats[1:] {
+		switch stat.TargetID {
+		case cloudprotocol.DownloadTargetComponent:
+			checkDuration(t, stat.Duration, time.Duration(firmwareTime*float64(time.Second)))
+
+		case cloudprotocol.DownloadTargetService:
+			checkDuration(t, stat.Duration, time.Duration(softwareTime*float64(time.Second)))
+
+		default:
+			t.Errorf("Unexpected stat target ID: %s", stat.TargetID)
+		}
+	}
+}
+
+func TestShutdownWithInFlightDownloads(t *testing.T) {
+	sender := testAlertSender{}
+	downloadAllocator = &testAllocator{}
+	testStorage := &testStorage{
+		data: make(map[string]downloader.DownloadInfo),
+	}
+
+	if err := clearDirs(); err != nil {
+		t.Fatalf("Can't clear dirs: %v", err)
+	}
+
+	downloadInstance, err := downloader.New("testModule", &config.Config{
+		Downloader: config.Downloader{
+			DownloadDir:            downloadDir,
+			MaxConcurrentDownloads: 2,
+			DownloadPartLimit:      100,
+			ShutdownTimeout:        aostypes.Duration{Duration: time.Second},
+		},
+	}, &sender, testStorage)
+	if err != nil {
+		t.Fatalf("Can't create downloader: %s", err)
+	}
+
+	// Near complete download is released before shutdown timeout, stalled download is never released
+
+	nearCompleteRelease := make(chan struct{})
+	stalledRelease := make(chan struct{})
+
+	defer close(stalledRelease)
+
+	type testDownload struct {
+		release  chan struct{}
+		fileName string
+		result   downloader.Result
+	}
+
+	downloads := []*testDownload{
+		{release: nearCompleteRelease, fileName: path.Join(serverDir, "nearComplete.txt")},
+		{release: stalledRelease, fileName: path.Join(serverDir, "stalled.txt")},
+	}
+
+	for i, download := range downloads {
+		if err := generateFile(download.fileName, 64*Kilobyte); err != nil {
+			t.Fatalf("Can't generate file: %v", err)
+		}
+		defer os.RemoveAll(download.fileName)
+
+		data, err := os.ReadFile(download.fileName)
+		if err != nil {
+			t.Fatalf("Can't read file: %v", err)
+		}
+
+		server := httptest.NewServer(newStallingHandler(data, len(data)/2, download.release))
+		defer server.Close()
+
+		packageInfo := preparePackageInfo(server.URL+"/", download.fileName, cloudprotocol.DownloadTargetService)
+		packageInfo.TargetID = fmt.Sprintf("service%d", i)
+
+		if download.result, err = downloadInstance.Download(context.Background(), packageInfo); err != nil {
+			t.Fatalf("Can't download package: %v", err)
+		}
+	}
+
+	time.AfterFunc(100*time.Millisecond, func() { close(nearCompleteRelease) })
+
+	if err = downloadInstance.Shutdown(); err != nil {
+		t.Errorf("Can't shutdown downloader: %v", err)
+	}
+
+	if _, err = downloadInstance.Download(context.Background(), preparePackageInfo(
+		"http://localhost:8001/", downloads[0].fileName, cloudprotocol.DownloadTargetService)); err == nil {
+		t.Error("Download should fail after shutdown")
+	}
+
+	// Near complete download should be finished
+
+	if err = downloads[0].result.Wait(); err != nil {
+		t.Errorf("Download error: %v", err)
+	}
+
+	if _, err = os.Stat(downloads[0].result.GetFileName()); err != nil {
+		t.Errorf("Can't get downloaded file stat: %v", err)
+	}
+
+	// Stalled download should be interrupted with recorded resume offset
+
+	if err = downloads[1].result.Wait(); err == nil {
+		t.Error("Stalled download should be interrupted")
+	}
+
+	downloadInfo, err := testStorage.GetDownloadInfo(downloads[1].result.GetFileName())
+	if err != nil {
+		t.Fatalf("Can't get download info: %v", err)
+	}
+
+	if downloadInfo.Downloaded || downloadInfo.InterruptReason == "" {
+		t.Errorf("Wrong download info: %v", downloadInfo)
+	}
+
+	relPath, err := filepath.Rel(downloadDir, downloads[1].result.GetFileName())
+	if err != nil {
+		t.Fatalf("Can't get relative path: %v", err)
+	}
+
+	fileInfo, err := os.Stat(filepath.Join(downloadDir, "tmp", relPath))
+	if err != nil {
+		t.Fatalf("Can't get tmp file stat: %v", err)
+	}
+
+	if fileInfo.Size() != int64(32*Kilobyte) {
+		t.Errorf("Wrong resume offset: %d", fileInfo.Size())
+	}
+}
+
 /***********************************************************************************************************************
  * Interfaces
  **********************************************************************************************************************/
@@ -832,6 +1544,11 @@ func newSpaceAllocator(
 		return downloadAllocator, nil
 
 	default:
+		if allocator, ok := dirAllocators[path]; ok {
+			allocator.remover = remover
+			return allocator, nil
+		}
+
 		return &testAllocator{remover: remover}, nil
 	}
 }
@@ -970,6 +1687,32 @@ func killConnectionIn(host string, port int16, delay time.Duration) {
 	}()
 }
 
+// newStallingHandler serves first part of data and stalls till release channel is closed or request is canceled.
+func newStallingHandler(data []byte, stallOffset int, release <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		if _, err := w.Write(data[:stallOffset]); err != nil {
+			return
+		}
+
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		select {
+		case <-release:
+			_, _ = w.Write(data[stallOffset:])
+
+		case <-r.Context().Done():
+		}
+	}
+}
+
 func preparePackageInfo(host, fileName, targetType string) (packageInfo downloader.PackageInfo) {
 	fileName = path.Base(fileName)
 
@@ -1035,3 +1778,13 @@ func clearDirs() error {
 
 	return nil
 }
+
+func checkDuration(t *testing.T, duration, expected time.Duration) {
+	t.Helper()
+
+	const tolerance = 0.2
+
+	if math.Abs(float64(duration-expected)) > tolerance*float64(expected) {
+		t.Errorf("Wrong download duration: %v, expected: %v", duration, expected)
+	}
+}
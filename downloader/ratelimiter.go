@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downloader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/aosedge/aos_common/api/cloudprotocol"
+)
+
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+// Download groups sharing constrained bandwidth.
+const (
+	DownloadGroupFirmware = "firmware"
+	DownloadGroupSoftware = "software"
+)
+
+// Weight of download group which is not configured.
+const defaultGroupWeight = 1
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// rateLimiter limits total download rate and shares it between active download groups according to their weights.
+// Group is active while it has at least one download in progress, idle group share is used by active groups.
+type rateLimiter struct {
+	sync.Mutex
+
+	rate    float64
+	weights map[string]uint
+	groups  map[string]*rateGroup
+}
+
+type rateGroup struct {
+	active   int
+	nextTime time.Time
+}
+
+// groupLimiter rate limiter of single download.
+type groupLimiter struct {
+	limiter *rateLimiter
+	group   string
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func newRateLimiter(rate uint64, weights map[string]uint) *rateLimiter {
+	return &rateLimiter{rate: float64(rate), weights: weights, groups: make(map[string]*rateGroup)}
+}
+
+func getDownloadGroup(targetType string) string {
+	if targetType == cloudprotocol.DownloadTargetComponent {
+		return DownloadGroupFirmware
+	}
+
+	return DownloadGroupSoftware
+}
+
+func (limiter *rateLimiter) acquire(group string) *groupLimiter {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	state, ok := limiter.groups[group]
+	if !ok {
+		state = &rateGroup{}
+		limiter.groups[group] = state
+	}
+
+	state.active++
+
+	return &groupLimiter{limiter: limiter, group: group}
+}
+
+func (limiter *rateLimiter) release(group string) {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	state, ok := limiter.groups[group]
+	if !ok {
+		return
+	}
+
+	if state.active--; state.active <= 0 {
+		delete(limiter.groups, group)
+	}
+}
+
+func (limiter *rateLimiter) getWeight(group string) uint {
+	if weight, ok := limiter.weights[group]; ok && weight > 0 {
+		return weight
+	}
+
+	return defaultGroupWeight
+}
+
+// reserve reserves transfer time of n bytes for the group and returns time when transfer is allowed to continue.
+func (limiter *rateLimiter) reserve(group string, n int) time.Time {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	now := time.Now()
+
+	state, ok := limiter.groups[group]
+	if !ok {
+		return now
+	}
+
+	var totalWeight uint
+
+	for name := range limiter.groups {
+		totalWeight += limiter.getWeight(name)
+	}
+
+	groupRate := limiter.rate * float64(limiter.getWeight(group)) / float64(totalWeight)
+
+	if state.nextTime.Before(now) {
+		state.nextTime = now
+	}
+
+	state.nextTime = state.nextTime.Add(time.Duration(float64(n) / groupRate * float64(time.Second)))
+
+	return state.nextTime
+}
+
+func (limiter *groupLimiter) release() {
+	limiter.limiter.release(limiter.group)
+}
+
+// WaitN implements grab rate limiter interface.
+func (limiter *groupLimiter) WaitN(ctx context.Context, n int) error {
+	delay := time.Until(limiter.limiter.reserve(limiter.group, n))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+
+	case <-ctx.Done():
+		return aoserrors.Wrap(ctx.Err())
+	}
+}
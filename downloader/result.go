@@ -38,12 +38,17 @@ type downloadResult struct {
 	id string
 
 	ctx         context.Context //nolint:containedctx
+	cancelFunc  context.CancelFunc
 	packageInfo PackageInfo
 
 	statusChannel chan error
 
+	downloadDir      *downloadDir
 	downloadFileName string
+	tmpFileName      string
 	downloadSpace    spaceallocator.Space
+
+	stat downloadStat
 }
 
 /***********************************************************************************************************************
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downloader
+
+import (
+	"sync"
+	"time"
+)
+
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+// Max number of finished downloads kept in stats history.
+const maxDownloadStats = 32
+
+// Throughput sampling period used to detect download peak rate.
+const throughputSampleTime = time.Second
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// DownloadStat package download throughput statistics.
+type DownloadStat struct {
+	ID         string
+	TargetType string
+	TargetID   string
+	StartTime  time.Time
+	Duration   time.Duration
+	Bytes      uint64
+	AverageBps uint64
+	PeakBps    uint64
+	InProgress bool
+	Error      string
+}
+
+type downloadStat struct {
+	sync.Mutex
+
+	startTime time.Time
+	endTime   time.Time
+	bytes     uint64
+	peakBps   uint64
+	err       error
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func (stat *downloadStat) start() {
+	stat.Lock()
+	defer stat.Unlock()
+
+	stat.startTime = time.Now()
+}
+
+func (stat *downloadStat) finish(err error) {
+	stat.Lock()
+	defer stat.Unlock()
+
+	stat.endTime = time.Now()
+	stat.err = err
+}
+
+func (stat *downloadStat) addBytes(bytes uint64) {
+	stat.Lock()
+	defer stat.Unlock()
+
+	stat.bytes += bytes
+}
+
+func (stat *downloadStat) updatePeak(bps uint64) {
+	stat.Lock()
+	defer stat.Unlock()
+
+	if bps > stat.peakBps {
+		stat.peakBps = bps
+	}
+}
+
+func (stat *downloadStat) get(result *downloadResult) DownloadStat {
+	stat.Lock()
+	defer stat.Unlock()
+
+	downloadStat := DownloadStat{
+		ID:         result.id,
+		TargetType: result.packageInfo.TargetType,
+		TargetID:   result.packageInfo.TargetID,
+		StartTime:  stat.startTime,
+		Bytes:      stat.bytes,
+		PeakBps:    stat.peakBps,
+		InProgress: stat.endTime.IsZero(),
+	}
+
+	if downloadStat.InProgress {
+		downloadStat.Duration = time.Since(stat.startTime)
+	} else {
+		downloadStat.Duration = stat.endTime.Sub(stat.startTime)
+	}
+
+	if stat.err != nil {
+		downloadStat.Error = stat.err.Error()
+	}
+
+	if seconds := downloadStat.Duration.Seconds(); seconds > 0 {
+		downloadStat.AverageBps = uint64(float64(stat.bytes) / seconds)
+	}
+
+	// Average rate could exceed sampled peak for short downloads finished between samples
+	if downloadStat.AverageBps > downloadStat.PeakBps {
+		downloadStat.PeakBps = downloadStat.AverageBps
+	}
+
+	return downloadStat
+}
@@ -6,6 +6,10 @@ replace github.com/ThalesIgnite/crypto11 => github.com/aosedge/crypto11 v1.0.3-0
 
 replace github.com/anexia-it/fsquota => github.com/aosedge/fsquota v0.0.0-20231127111317-842d831105a7
 
+// Fork of aos_common with not yet upstreamed API additions. Generated API code is built from the proto sources in
+// third_party/aos_common/proto: remove once they are released upstream
+replace github.com/aosedge/aos_common => ./third_party/aos_common
+
 require (
 	code.cloudfoundry.org/bytefmt v0.0.0-20231017140541-3b893ed0421b
 	github.com/aosedge/aos_common v0.0.0-20240701123742-84e62a5773fc
@@ -13,6 +17,7 @@ require (
 	github.com/cavaliergopher/grab/v3 v3.0.1
 	github.com/coreos/go-systemd v0.0.0-20191104093116-d3cd4ed1dbcf
 	github.com/fsnotify/fsnotify v1.7.0
+	github.com/fxamacker/cbor/v2 v2.9.4
 	github.com/golang/protobuf v1.5.3
 	github.com/google/go-tpm v0.9.0
 	github.com/google/uuid v1.4.0
@@ -55,6 +60,7 @@ require (
 	github.com/tklauser/go-sysconf v0.3.12 // indirect
 	github.com/tklauser/numcpus v0.6.1 // indirect
 	github.com/vishvananda/netns v0.0.0-20191106174202-0a2b9b5464df // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	github.com/yusufpapurcu/wmi v1.2.3 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	golang.org/x/net v0.23.0 // indirect
@@ -25,6 +25,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
@@ -48,6 +49,7 @@ import (
 
 const (
 	decryptedFileExt = ".dec"
+	tmpFileExt       = ".tmp"
 
 	fileScheme = "file"
 
@@ -96,6 +98,8 @@ type Imagemanager struct {
 	validateTTLStopChannel chan struct{}
 	removeServiceChannel   chan string
 	fileServer             *fileserver.FileServer
+	installingLayersMutex  sync.Mutex
+	installingLayers       map[string]struct{}
 }
 
 // ServiceInfo service information.
@@ -153,6 +157,7 @@ func New(
 		gidPool:                uidgidpool.NewGroupIDPool(),
 		validateTTLStopChannel: make(chan struct{}),
 		removeServiceChannel:   make(chan string, 1),
+		installingLayers:       make(map[string]struct{}),
 	}
 
 	if err := os.MkdirAll(imagemanager.layersDir, 0o755); err != nil {
@@ -247,7 +252,7 @@ func (imagemanager *Imagemanager) GetServicesStatus() ([]unitstatushandler.Servi
 				ID:         service.ID,
 				AosVersion: service.AosVersion,
 				Status:     cloudprotocol.InstalledStatus,
-			}, Cached: service.Cached,
+			}, Cached: service.Cached, Size: service.Size, Timestamp: service.Timestamp,
 		}
 	}
 
@@ -272,7 +277,7 @@ func (imagemanager *Imagemanager) GetLayersStatus() ([]unitstatushandler.LayerSt
 				AosVersion: layer.AosVersion,
 				Digest:     layer.Digest,
 				Status:     cloudprotocol.InstalledStatus,
-			}, Cached: layer.Cached,
+			}, Cached: layer.Cached, Size: layer.Size, Timestamp: layer.Timestamp,
 		}
 	}
 
@@ -283,6 +288,11 @@ func (imagemanager *Imagemanager) GetRemoveServiceChannel() (channel <-chan stri
 	return imagemanager.removeServiceChannel
 }
 
+// GetGIDPoolUtilization returns services GID pool utilization.
+func (imagemanager *Imagemanager) GetGIDPoolUtilization() uidgidpool.Utilization {
+	return imagemanager.gidPool.GetUtilization()
+}
+
 // InstallService installs service to the image store dir.
 func (imagemanager *Imagemanager) InstallService(serviceInfo cloudprotocol.ServiceInfo,
 	chains []cloudprotocol.CertificateChain, certs []cloudprotocol.Certificate,
@@ -299,6 +309,11 @@ func (imagemanager *Imagemanager) InstallService(serviceInfo cloudprotocol.Servi
 	if err == nil {
 		isServiceExist = true
 
+		// Forced download of the same version replaces possibly corrupted image
+		if serviceInfo.ForceDownload && serviceInfo.AosVersion == serviceFromStorage.AosVersion {
+			return imagemanager.reinstallService(serviceInfo, serviceFromStorage, chains, certs)
+		}
+
 		if serviceInfo.AosVersion <= serviceFromStorage.AosVersion {
 			return ErrVersionMismatch
 		}
@@ -407,6 +422,55 @@ func (imagemanager *Imagemanager) addService(
 	return nil
 }
 
+func (imagemanager *Imagemanager) reinstallService(serviceInfo cloudprotocol.ServiceInfo,
+	serviceFromStorage ServiceInfo, chains []cloudprotocol.CertificateChain, certs []cloudprotocol.Certificate,
+) error {
+	log.WithFields(log.Fields{"id": serviceInfo.ID, "aosVersion": serviceInfo.AosVersion}).Debug("Reinstall service")
+
+	if err := imagemanager.replaceImage(serviceFromStorage.Path, serviceInfo.URLs, fcrypt.DecryptParams{
+		Chains:         chains,
+		Certs:          certs,
+		DecryptionInfo: serviceInfo.DecryptionInfo,
+		Signs:          serviceInfo.Signs,
+	}); err != nil {
+		return err
+	}
+
+	if serviceFromStorage.Cached {
+		if err := imagemanager.setServiceCached(serviceFromStorage, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceImage decrypts and validates image into temporary file and replaces existing image with it.
+func (imagemanager *Imagemanager) replaceImage(
+	imagePath string, urls []string, decryptParams fcrypt.DecryptParams,
+) error {
+	encryptedFile, err := getFilePath(urls)
+	if err != nil {
+		return err
+	}
+
+	tmpFile := imagePath + tmpFileExt
+
+	if err = imagemanager.decrypter.DecryptAndValidate(encryptedFile, tmpFile, decryptParams); err != nil {
+		if removeErr := os.RemoveAll(tmpFile); removeErr != nil {
+			log.Errorf("Can't remove temporary file: %v", removeErr)
+		}
+
+		return aoserrors.Wrap(err)
+	}
+
+	if err = os.Rename(tmpFile, imagePath); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
 // RestoreService restores service from a cache.
 func (imagemanager *Imagemanager) RestoreService(serviceID string) error {
 	log.WithFields(log.Fields{"serviceID": serviceID}).Debug("Restore service")
@@ -473,9 +537,24 @@ func (imagemanager *Imagemanager) InstallLayer(layerInfo cloudprotocol.LayerInfo
 ) error {
 	log.WithFields(log.Fields{"id": layerInfo.ID, "digest": layerInfo.Digest}).Debug("Install layer")
 
-	if layerInfo, err := imagemanager.storage.GetLayerInfo(layerInfo.Digest); err == nil {
-		if layerInfo.Cached {
-			if err := imagemanager.storage.SetLayerCached(layerInfo.Digest, false); err != nil {
+	imagemanager.setLayerInstalling(layerInfo.Digest, true)
+	defer imagemanager.setLayerInstalling(layerInfo.Digest, false)
+
+	if storedLayer, err := imagemanager.storage.GetLayerInfo(layerInfo.Digest); err == nil {
+		// Forced download replaces possibly corrupted image
+		if layerInfo.ForceDownload {
+			if err := imagemanager.replaceImage(storedLayer.Path, layerInfo.URLs, fcrypt.DecryptParams{
+				Chains:         chains,
+				Certs:          certs,
+				DecryptionInfo: layerInfo.DecryptionInfo,
+				Signs:          layerInfo.Signs,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if storedLayer.Cached {
+			if err := imagemanager.storage.SetLayerCached(storedLayer.Digest, false); err != nil {
 				return aoserrors.Wrap(err)
 			}
 		}
@@ -555,6 +634,16 @@ func (imagemanager *Imagemanager) InstallLayer(layerInfo cloudprotocol.LayerInfo
 	return nil
 }
 
+// IsLayerInstalling checks if layer is being installed right now.
+func (imagemanager *Imagemanager) IsLayerInstalling(digest string) bool {
+	imagemanager.installingLayersMutex.Lock()
+	defer imagemanager.installingLayersMutex.Unlock()
+
+	_, ok := imagemanager.installingLayers[digest]
+
+	return ok
+}
+
 // RemoveLayer makes layer cached.
 func (imagemanager *Imagemanager) RemoveLayer(digest string) error {
 	log.WithFields(log.Fields{"digest": digest}).Debug("Remove layer")
@@ -599,6 +688,56 @@ func (imagemanager *Imagemanager) RestoreLayer(digest string) error {
 	return nil
 }
 
+// PurgeService removes cached service completely.
+func (imagemanager *Imagemanager) PurgeService(serviceID string) error {
+	log.WithFields(log.Fields{"serviceID": serviceID}).Debug("Purge service")
+
+	services, err := imagemanager.storage.GetServiceVersions(serviceID)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if len(services) == 0 {
+		return nil
+	}
+
+	for _, service := range services {
+		if !service.Cached {
+			return aoserrors.Errorf("service %s is not cached", serviceID)
+		}
+	}
+
+	imagemanager.removeServiceChannel <- serviceID
+
+	for _, service := range services {
+		if err = imagemanager.removeService(service); err != nil {
+			return err
+		}
+	}
+
+	if err = imagemanager.gidPool.RemoveID(int(services[0].GID)); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// PurgeLayer removes cached layer completely.
+func (imagemanager *Imagemanager) PurgeLayer(digest string) error {
+	log.WithFields(log.Fields{"digest": digest}).Debug("Purge layer")
+
+	layer, err := imagemanager.storage.GetLayerInfo(digest)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if !layer.Cached {
+		return aoserrors.Errorf("layer %s is not cached", digest)
+	}
+
+	return imagemanager.removeLayer(layer)
+}
+
 // GetServiceInfo gets service information by id.
 func (imagemanager *Imagemanager) GetServiceInfo(serviceID string) (ServiceInfo, error) {
 	serviceInfo, err := imagemanager.storage.GetServiceInfo(serviceID)
@@ -662,6 +801,19 @@ func (imagemanager *Imagemanager) setServiceCached(service ServiceInfo, cached b
 	return nil
 }
 
+func (imagemanager *Imagemanager) setLayerInstalling(digest string, installing bool) {
+	imagemanager.installingLayersMutex.Lock()
+	defer imagemanager.installingLayersMutex.Unlock()
+
+	if installing {
+		imagemanager.installingLayers[digest] = struct{}{}
+
+		return
+	}
+
+	delete(imagemanager.installingLayers, digest)
+}
+
 func (imagemanager *Imagemanager) setLayerCached(layer LayerInfo, cached bool) error {
 	if err := imagemanager.storage.SetLayerCached(layer.Digest, cached); err != nil {
 		return aoserrors.Wrap(err)
@@ -267,6 +267,11 @@ func TestInstallService(t *testing.T) {
 			t.Error("Unexpected count services status")
 		}
 
+		if utilization := imagemanagerInstance.GetGIDPoolUtilization(); utilization.Allocated !=
+			tCase.expectedCountService {
+			t.Errorf("Unexpected GID pool utilization: %v", utilization)
+		}
+
 		for _, service := range services {
 			if service.Status != cloudprotocol.InstalledStatus {
 				t.Error("Unexpected service status")
@@ -513,6 +518,75 @@ func TestRemoveService(t *testing.T) {
 	}
 }
 
+func TestPurgeService(t *testing.T) {
+	storage := &testStorageProvider{
+		services: make(map[string][]imagemanager.ServiceInfo),
+	}
+
+	serviceAllocator = &testAllocator{
+		totalSize: 2 * megabyte,
+	}
+
+	imagemanagerInstance, err := imagemanager.New(&config.Config{
+		ImageStoreDir: tmpDir,
+		WorkingDir:    tmpDir,
+	}, storage, &testCryptoContext{})
+	if err != nil {
+		t.Fatalf("Can't create image manager instance: %v", err)
+	}
+	defer imagemanagerInstance.Close()
+
+	defer func() {
+		if err = clearServicesDir(); err != nil {
+			t.Errorf("Can't clear services dir: %v", err)
+		}
+	}()
+
+	servicePath, _, err := prepareService(1*megabyte, []byte("{}"))
+	if err != nil {
+		t.Fatalf("Can't prepare service file: %v", err)
+	}
+
+	serviceInfo, err := prepareServiceInfo(servicePath, "service1", 1)
+	if err != nil {
+		t.Fatalf("Can't prepare service info: %v", err)
+	}
+
+	if err := imagemanagerInstance.InstallService(serviceInfo, nil, nil); err != nil {
+		t.Fatalf("Can't install service: %v", err)
+	}
+
+	if err := imagemanagerInstance.PurgeService("service1"); err == nil {
+		t.Error("Error expected on purging not cached service")
+	}
+
+	if err := imagemanagerInstance.RemoveService("service1"); err != nil {
+		t.Fatalf("Can't remove service: %v", err)
+	}
+
+	if err := imagemanagerInstance.PurgeService("service1"); err != nil {
+		t.Fatalf("Can't purge service: %v", err)
+	}
+
+	select {
+	case serviceID := <-imagemanagerInstance.GetRemoveServiceChannel():
+		if serviceID != "service1" {
+			t.Errorf("Unexpected removed service: %s", serviceID)
+		}
+
+	case <-time.After(time.Second):
+		t.Error("Wait removed service timeout")
+	}
+
+	if _, err = imagemanagerInstance.GetServiceInfo("service1"); err == nil {
+		t.Error("Service should be removed")
+	}
+
+	if utilization := imagemanagerInstance.GetGIDPoolUtilization(); utilization.Allocated != 0 {
+		t.Errorf("Service GID is not released: %v", utilization)
+	}
+}
+
 func TestRestoreService(t *testing.T) {
 	storage := &testStorageProvider{
 		services: make(map[string][]imagemanager.ServiceInfo),
@@ -64,6 +64,8 @@ func newInstanceManager(config *config.Config, storage Storage, storageStateProv
 		uidPool:              uidgidpool.NewUserIDPool(),
 	}
 
+	im.uidPool.SetCapacity(config.SMController.UIDPoolLimit)
+
 	if err := im.fillUIDPool(); err != nil {
 		log.Errorf("Can't fill UID pool: %v", err)
 
@@ -21,14 +21,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
 	"github.com/aosedge/aos_common/aostypes"
 	"github.com/aosedge/aos_common/api/cloudprotocol"
+	"github.com/aosedge/aos_common/utils/retryhelper"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/exp/slices"
 
@@ -37,6 +40,7 @@ import (
 	"github.com/aosedge/aos_communicationmanager/networkmanager"
 	"github.com/aosedge/aos_communicationmanager/storagestate"
 	"github.com/aosedge/aos_communicationmanager/unitstatushandler"
+	"github.com/aosedge/aos_communicationmanager/utils/uidgidpool"
 )
 
 /**********************************************************************************************************************
@@ -45,8 +49,110 @@ import (
 
 var ErrNotExist = errors.New("entry not exist")
 
+var errLayerPending = errors.New("layer is being installed")
+
+var errDependencyCycle = errors.New("service dependency cycle detected")
+
+var errServiceDeleted = errors.New("service deleted")
+
 const defaultRunner = "crun"
 
+const (
+	instanceEventsChannelSize = 100
+	nodeEventsChannelSize     = 10
+)
+
+const pendingInstancesRetryPeriod = 10 * time.Second
+
+const rebalancingAlertsWindow = 500 * time.Millisecond
+
+const (
+	defaultRestartBackoff = time.Second
+	maxRestartBackoff     = 5 * time.Minute
+)
+
+const (
+	defaultReadinessProbePeriod  = time.Second
+	defaultReadinessProbeTimeout = 5 * time.Second
+)
+
+const defaultUnitConfigRetryPeriod = 10 * time.Second
+
+const defaultMoveStopTimeout = 30 * time.Second
+
+const (
+	defaultStorageWriteMaxTry     = 3
+	defaultStorageWriteRetryDelay = 100 * time.Millisecond
+	maxStorageWriteRetryDelay     = time.Second
+)
+
+// Node circuit breaker states.
+const (
+	breakerStateClosed   = "closed"
+	breakerStateOpen     = "open"
+	breakerStateHalfOpen = "halfOpen"
+)
+
+// Tolerance for comparing fractional device allocations.
+const deviceShareEpsilon = 1e-9
+
+// CPU capacity of one core in service CPU quota units (percents).
+const cpuCapacityPerCore = 100
+
+// Instance lifecycle event types.
+const (
+	InstanceEventStarted = "started"
+	InstanceEventStopped = "stopped"
+	InstanceEventMoved   = "moved"
+)
+
+// Node event types.
+const (
+	NodeEventBreakerTripped = "breakerTripped"
+	NodeEventBreakerReset   = "breakerReset"
+	NodeEventTypeMismatch   = "typeMismatch"
+)
+
+// Instance stop confirmation event types.
+const (
+	InstanceEventStopTimeout = "stopTimeout"
+)
+
+// Balancing filter categories used to classify scheduling failures.
+const (
+	BalancingFilterRunner     = "runner"
+	BalancingFilterLocal      = "local"
+	BalancingFilterLabels     = "labels"
+	BalancingFilterResources  = "resources"
+	BalancingFilterDevices    = "devices"
+	BalancingFilterCPUs       = "cpus"
+	BalancingFilterQuantities = "quantities"
+	BalancingFilterCapacity   = "capacity"
+	BalancingFilterBreaker    = "breaker"
+	BalancingFilterNodeType   = "nodeType"
+	BalancingFilterConfig     = "unitConfig"
+	BalancingFilterOther      = "other"
+)
+
+// Tie-breakers for nodes with equal priority.
+const (
+	NodeTieBreakerLexicographic = "lexicographic"
+	NodeTieBreakerLeastLoaded   = "leastLoaded"
+	NodeTieBreakerMostFreeRAM   = "mostFreeRAM"
+)
+
+// Layers delivery methods for remote nodes.
+const (
+	LayerDeliveryPull = "pull"
+	LayerDeliveryPush = "push"
+)
+
+// Behavior on unchanged run status re-sent by node.
+const (
+	RepeatedRunStatusSkip = "skip"
+	RepeatedRunStatusSend = "send"
+)
+
 //nolint:gochecknoglobals
 var defaultRunnerFeatures = []string{"crun", "runc"}
 
@@ -61,6 +167,20 @@ type NodeRunInstanceStatus struct {
 	Instances []cloudprotocol.InstanceStatus
 }
 
+// InstanceEvent instance lifecycle event.
+type InstanceEvent struct {
+	aostypes.InstanceIdent
+	EventType  string
+	NodeID     string
+	PrevNodeID string
+}
+
+// NodeEvent node level event.
+type NodeEvent struct {
+	EventType string
+	NodeID    string
+}
+
 // NodeConfiguration node static configuration.
 type NodeInfo struct {
 	cloudprotocol.NodeInfo
@@ -68,6 +188,22 @@ type NodeInfo struct {
 	RunnerFeature []string
 }
 
+// BalancingMetrics balancing decisions and outcomes counters.
+type BalancingMetrics struct {
+	ScheduledInstances   map[string]uint64 // node ID -> number of scheduled instances
+	SchedulingFailures   map[string]uint64 // balancing filter -> number of failed instances
+	RebalancingEvents    uint64
+	AveragePlacementTime time.Duration
+}
+
+// pausedRunInstances latest run instances request received while launcher is paused.
+type pausedRunInstances struct {
+	instances      []cloudprotocol.InstanceInfo
+	newServices    []string
+	correlationID  string
+	preferredNodes map[aostypes.InstanceIdent]string
+}
+
 // Launcher service instances launcher.
 type Launcher struct {
 	sync.Mutex
@@ -80,14 +216,38 @@ type Launcher struct {
 	storageStateProvider    StorageStateProvider
 	networkManager          NetworkManager
 	runStatusChannel        chan unitstatushandler.RunInstancesStatus
+	instanceEventsChannel   chan InstanceEvent
+	nodeEventsChannel       chan NodeEvent
 	nodes                   []*nodeStatus
 	currentDesiredInstances []cloudprotocol.InstanceInfo
 	currentRunStatus        []cloudprotocol.InstanceStatus
 	currentErrorStatus      []cloudprotocol.InstanceStatus
 	pendingNewServices      []string
+	pendingLayerServices    []string
+	deferredServices        []string
+	quarantinedStates       map[aostypes.InstanceIdent]*time.Timer
+	pendingStops            map[aostypes.InstanceIdent]*pendingStop
+	instanceRestarts        map[aostypes.InstanceIdent]*instanceRestart
+	instanceProbes          map[aostypes.InstanceIdent]*instanceProbe
+	readinessProber         ReadinessProber
+	nodeBreakers            map[string]*nodeBreaker
+	allNodesConnected       chan struct{}
+	balancingMetrics        balancingMetrics
+	preferredNodes          map[aostypes.InstanceIdent]string
+	paused                  bool
+	pausedRun               *pausedRunInstances
+	correlationID           string
+	pausedRestart           bool
+	pausedRebalancing       bool
+	pendingMoves            []*pendingMove
+	pendingBalancing        bool
 
 	cancelFunc      context.CancelFunc
+	storageWriter   *storageWriter
+	statusSender    *statusSender
 	connectionTimer *time.Timer
+	retryTimer      *time.Timer
+	unitConfigTimer *time.Timer
 
 	instanceManager *instanceManager
 }
@@ -130,6 +290,7 @@ type ImageProvider interface {
 	GetLayerInfo(digest string) (imagemanager.LayerInfo, error)
 	RevertService(serviceID string) error
 	GetRemoveServiceChannel() (channel <-chan string)
+	IsLayerInstalling(digest string) bool
 }
 
 // NodeManager nodes controller.
@@ -144,9 +305,16 @@ type NodeManager interface {
 	GetNodeMonitoringData(nodeID string) (data cloudprotocol.NodeMonitoringData, err error)
 }
 
+// ReadinessProber checks service instances readiness. Node manager may optionally implement it.
+type ReadinessProber interface {
+	CheckInstanceReadiness(
+		ctx context.Context, nodeID string, instanceIdent aostypes.InstanceIdent, probe aostypes.ServiceReadinessProbe,
+	) (ready bool, err error)
+}
+
 // ResourceManager provides node resources.
 type ResourceManager interface {
-	GetUnitConfiguration(nodeType string) aostypes.NodeUnitConfig
+	GetUnitConfiguration(nodeType string) (aostypes.NodeUnitConfig, error)
 }
 
 // StorageStateProvider instances storage state provider.
@@ -157,21 +325,136 @@ type StorageStateProvider interface {
 	GetInstanceCheckSum(instance aostypes.InstanceIdent) string
 }
 
+// StateIntegrityChecker verifies instances state integrity. Storage state provider may optionally implement it.
+type StateIntegrityChecker interface {
+	CheckStateIntegrity(instance aostypes.InstanceIdent) error
+}
+
 type nodeStatus struct {
 	NodeInfo
 	availableResources   []string
 	availableLabels      []string
 	availableDevices     []nodeDevice
+	availableQuantities  []nodeQuantitativeResource
+	availableRunners     []string
+	defaultRunner        string
+	allocatedCPUs        []bool
+	capacityLimited      bool
+	availableRAM         uint64
+	availableCPU         uint64
+	allocatedRAM         uint64
+	allocatedCPU         uint64
 	priority             uint32
 	receivedRunInstances []cloudprotocol.InstanceStatus
 	currentRunRequest    *runRequestInfo
 	waitStatus           bool
+	runRequested         bool
+	typeMismatch         bool
+	configPending        bool
+	restartingInstances  []aostypes.InstanceIdent
+	heldInstances        []aostypes.InstanceIdent
 }
 
 type nodeDevice struct {
-	name           string
-	sharedCount    int
-	allocatedCount int
+	name        string
+	sharedCount int
+	allocated   float64
+}
+
+type nodeQuantitativeResource struct {
+	name      string
+	capacity  uint64
+	allocated uint64
+}
+
+type balancingMetrics struct {
+	scheduledInstances map[string]uint64
+	schedulingFailures map[string]uint64
+	rebalancingEvents  uint64
+	placementTime      time.Duration
+	placementCount     uint64
+}
+
+type pendingMove struct {
+	instanceIdent aostypes.InstanceIdent
+	sourceNodeID  string
+	destNodeID    string
+	timer         *time.Timer
+}
+
+type instanceRestart struct {
+	retries    uint64
+	timer      *time.Timer
+	resetTimer *time.Timer
+}
+
+// storageWriter writes launcher data to the storage in background. Only the latest data of each key is written.
+type storageWriter struct {
+	sync.Mutex
+
+	maxTry  int
+	delay   time.Duration
+	pending map[string]func() error
+	errors  map[string]error
+	wakeup  chan struct{}
+	done    chan struct{}
+}
+
+// statusSender sends run statuses in background. Instances state integrity is checked before sending as it requires
+// state files hashing which should not be done under launcher lock.
+type statusSender struct {
+	sync.Mutex
+
+	statusChannel    chan<- unitstatushandler.RunInstancesStatus
+	isStateCorrupted func(instanceIdent aostypes.InstanceIdent) bool
+	pending          []pendingRunStatus
+	wakeup           chan struct{}
+	done             chan struct{}
+}
+
+type pendingRunStatus struct {
+	runStatus         unitstatushandler.RunInstancesStatus
+	checkStateIndexes []int
+}
+
+type pendingStop struct {
+	nodeID   string
+	timer    *time.Timer
+	timedOut bool
+}
+
+type instanceProbe struct {
+	nodeID   string
+	ready    bool
+	failed   bool
+	failures int
+	timer    *time.Timer
+}
+
+type nodeBreaker struct {
+	state    string
+	failures []time.Time
+	openTime time.Time
+}
+
+// placementPlanner places instances on nodes. Preview planner works on cloned nodes and node breakers, so placement
+// preview doesn't change launcher state.
+type placementPlanner struct {
+	launcher     *Launcher
+	nodes        []*nodeStatus
+	nodeBreakers map[string]*nodeBreaker
+	metrics      *balancingMetrics
+	preview      bool
+}
+
+type filterError struct {
+	filter  string
+	message string
+}
+
+type codedError struct {
+	code string
+	err  error
 }
 
 type runRequestInfo struct {
@@ -180,6 +463,13 @@ type runRequestInfo struct {
 	Instances []aostypes.InstanceInfo `json:"instances"`
 }
 
+// balancingInstances desired instances balanced with the same priority: instances with priority override are
+// balanced separately from other instances of the service.
+type balancingInstances struct {
+	cloudprotocol.InstanceInfo
+	indexes []uint64
+}
+
 /***********************************************************************************************************************
  * Public
  **********************************************************************************************************************/
@@ -194,9 +484,50 @@ func New(
 	launcher = &Launcher{
 		config: config, storage: storage, nodeManager: nodeManager, imageProvider: imageProvider,
 		resourceManager: resourceManager, storageStateProvider: storageStateProvider,
-		networkManager:   networkManager,
-		runStatusChannel: make(chan unitstatushandler.RunInstancesStatus, 10),
-		nodes:            []*nodeStatus{},
+		networkManager:        networkManager,
+		runStatusChannel:      make(chan unitstatushandler.RunInstancesStatus, 10),
+		instanceEventsChannel: make(chan InstanceEvent, instanceEventsChannelSize),
+		nodeEventsChannel:     make(chan NodeEvent, nodeEventsChannelSize),
+		nodes:                 []*nodeStatus{},
+		quarantinedStates:     make(map[aostypes.InstanceIdent]*time.Timer),
+		pendingStops:          make(map[aostypes.InstanceIdent]*pendingStop),
+		instanceRestarts:      make(map[aostypes.InstanceIdent]*instanceRestart),
+		instanceProbes:        make(map[aostypes.InstanceIdent]*instanceProbe),
+		nodeBreakers:          make(map[string]*nodeBreaker),
+		allNodesConnected:     make(chan struct{}),
+		storageWriter:         newStorageWriter(config),
+		balancingMetrics: balancingMetrics{
+			scheduledInstances: make(map[string]uint64),
+			schedulingFailures: make(map[string]uint64),
+		},
+	}
+
+	launcher.statusSender = newStatusSender(launcher.runStatusChannel, launcher.isStateCorrupted)
+
+	if prober, ok := nodeManager.(ReadinessProber); ok {
+		launcher.readinessProber = prober
+	}
+
+	switch config.SMController.RemoteLayerDelivery {
+	case "", LayerDeliveryPull:
+
+	case LayerDeliveryPush:
+		// Layers transfer to remote nodes is not implemented: remote nodes would wait for layers forever
+		return nil, aoserrors.Errorf("remote layer delivery %s is not supported", LayerDeliveryPush)
+
+	default:
+		return nil, aoserrors.Errorf("unknown remote layer delivery: %s", config.SMController.RemoteLayerDelivery)
+	}
+
+	switch config.SMController.RepeatedRunStatus {
+	case "", RepeatedRunStatusSkip, RepeatedRunStatusSend:
+
+	default:
+		return nil, aoserrors.Errorf("unknown repeated run status behavior: %s", config.SMController.RepeatedRunStatus)
+	}
+
+	if len(config.SMController.NodeIDs) == 0 {
+		close(launcher.allNodesConnected)
 	}
 
 	if launcher.instanceManager, err = newInstanceManager(config, storage, storageStateProvider,
@@ -216,9 +547,11 @@ func New(
 
 	launcher.cancelFunc = cancelFunction
 	launcher.connectionTimer = time.AfterFunc(
-		config.SMController.NodesConnectionTimeout.Duration, launcher.sendCurrentStatus)
+		config.SMController.NodesConnectionTimeout.Duration, launcher.sendStatusOnTimeout)
 
 	go launcher.processChannels(ctx)
+	go launcher.storageWriter.run(ctx)
+	go launcher.statusSender.run(ctx)
 
 	return launcher, nil
 }
@@ -231,23 +564,129 @@ func (launcher *Launcher) Close() {
 		launcher.cancelFunc()
 	}
 
+	launcher.Lock()
+
+	if launcher.retryTimer != nil {
+		launcher.retryTimer.Stop()
+	}
+
+	if launcher.unitConfigTimer != nil {
+		launcher.unitConfigTimer.Stop()
+	}
+
+	for _, cleanupTimer := range launcher.quarantinedStates {
+		cleanupTimer.Stop()
+	}
+
+	for _, stop := range launcher.pendingStops {
+		if stop.timer != nil {
+			stop.timer.Stop()
+		}
+	}
+
+	launcher.resetInstanceRestarts()
+	launcher.resetInstanceProbes()
+	launcher.resetPendingMoves()
+
+	launcher.Unlock()
+
+	launcher.storageWriter.wait()
+	launcher.statusSender.wait()
 	launcher.instanceManager.close()
 }
 
-// RunInstances performs run service instances.
-func (launcher *Launcher) RunInstances(instances []cloudprotocol.InstanceInfo, newServices []string) error {
+// WaitForAllNodes waits until all configured nodes are connected and have reported their run status.
+func (launcher *Launcher) WaitForAllNodes(ctx context.Context) error {
+	select {
+	case <-launcher.allNodesConnected:
+		return nil
+
+	case <-ctx.Done():
+		return aoserrors.Wrap(ctx.Err())
+	}
+}
+
+// RunInstances performs run service instances. Correlation ID is reported in the resulting run instances status.
+func (launcher *Launcher) RunInstances(
+	instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
 	launcher.Lock()
 	defer launcher.Unlock()
 
-	log.Debug("Run instances")
+	log.WithField("correlationID", correlationID).Debug("Run instances")
 
-	launcher.connectionTimer = time.AfterFunc(
-		launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.sendCurrentStatus)
+	return launcher.runInstances(instances, newServices, correlationID)
+}
 
-	if rawDesiredInstances, err := json.Marshal(instances); err != nil {
-		log.Errorf("Can't marshall desired instances: %v", err)
-	} else {
-		if err := launcher.storage.SetDesiredInstances(rawDesiredInstances); err != nil {
+// RunSubjectInstances performs run service instances of specified subject. Desired instances of other subjects are
+// kept and their instances stay on current nodes if possible. Correlation ID is reported in the resulting run
+// instances status.
+func (launcher *Launcher) RunSubjectInstances(
+	subjectID string, instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	log.WithFields(log.Fields{"subjectID": subjectID, "correlationID": correlationID}).Debug("Run subject instances")
+
+	for _, instance := range instances {
+		if instance.SubjectID != subjectID {
+			return aoserrors.Errorf("instance %s belongs to wrong subject %s", instance.ServiceID, instance.SubjectID)
+		}
+	}
+
+	currentInstances := launcher.currentDesiredInstances
+
+	if launcher.pausedRun != nil {
+		currentInstances = launcher.pausedRun.instances
+	}
+
+	desiredInstances := make([]cloudprotocol.InstanceInfo, 0, len(currentInstances)+len(instances))
+
+	for _, instance := range currentInstances {
+		if instance.SubjectID != subjectID {
+			desiredInstances = append(desiredInstances, instance)
+		}
+	}
+
+	desiredInstances = append(desiredInstances, instances...)
+
+	launcher.preferredNodes = make(map[aostypes.InstanceIdent]string)
+	defer func() { launcher.preferredNodes = nil }()
+
+	for _, node := range launcher.nodes {
+		for _, instance := range node.currentRunRequest.Instances {
+			if instance.SubjectID != subjectID {
+				launcher.preferredNodes[instance.InstanceIdent] = node.NodeID
+			}
+		}
+	}
+
+	return launcher.runInstances(desiredInstances, newServices, correlationID)
+}
+
+func (launcher *Launcher) runInstances(
+	instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	if launcher.paused {
+		log.Debug("Launcher paused, queue run instances")
+
+		// Preferred nodes are reset by caller: keep them to apply on resume
+		launcher.pausedRun = &pausedRunInstances{
+			instances: instances, newServices: newServices, correlationID: correlationID,
+			preferredNodes: launcher.preferredNodes,
+		}
+
+		return nil
+	}
+
+	launcher.correlationID = correlationID
+
+	// Desired instances are stored in background: store failure is reported by launcher health error and doesn't
+	// fail run instances. Observer mode is read-only: desired instances are not stored to not run them after
+	// observer mode is off.
+	if !launcher.config.ObserverMode {
+		if err := launcher.storeDesiredInstances(instances); err != nil {
 			log.Errorf("Can't store desired instances: %v", err)
 		}
 	}
@@ -258,6 +697,27 @@ func (launcher *Launcher) RunInstances(instances []cloudprotocol.InstanceInfo, n
 
 	launcher.currentDesiredInstances = instances
 	launcher.pendingNewServices = newServices
+
+	launcher.resetInstanceRestarts()
+
+	// Balancing without enough connected nodes fails all instances: defer it till nodes connect or timeout expires
+	if !launcher.isBalancingAllowed() {
+		log.WithField("connectedNodes", len(launcher.nodes)).Debug("Defer balancing till nodes connect")
+
+		launcher.pendingBalancing = true
+
+		launcher.connectionTimer.Stop()
+		launcher.connectionTimer = time.AfterFunc(
+			launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.performPendingBalancing)
+
+		return nil
+	}
+
+	launcher.pendingBalancing = false
+
+	launcher.connectionTimer = time.AfterFunc(
+		launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.sendStatusOnTimeout)
+
 	launcher.currentErrorStatus = launcher.performNodeBalancing(instances)
 
 	if err := launcher.networkManager.RestartDNSServer(); err != nil {
@@ -267,28 +727,197 @@ func (launcher *Launcher) RunInstances(instances []cloudprotocol.InstanceInfo, n
 	return launcher.sendRunInstances(false)
 }
 
+func (launcher *Launcher) storeDesiredInstances(instances []cloudprotocol.InstanceInfo) error {
+	rawDesiredInstances, err := json.Marshal(instances)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	launcher.storageWriter.write("desired instances", func() error {
+		return aoserrors.Wrap(launcher.storage.SetDesiredInstances(rawDesiredInstances))
+	})
+
+	return nil
+}
+
+// GetHealthError returns error of failed background storage writes.
+func (launcher *Launcher) GetHealthError() error {
+	return launcher.storageWriter.getError()
+}
+
 // RestartInstances performs restart service instances.
 func (launcher *Launcher) RestartInstances() error {
 	launcher.Lock()
 	defer launcher.Unlock()
 
+	if launcher.paused {
+		log.Debug("Launcher paused, postpone restart instances")
+
+		launcher.pausedRestart = true
+
+		return nil
+	}
+
+	return launcher.restartInstances()
+}
+
+// Pause stops scheduling: run, restart and rebalancing requests are postponed till resume. Existing instances are
+// kept untouched and instances status is reported as usual.
+func (launcher *Launcher) Pause() {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	log.Info("Pause launcher")
+
+	launcher.paused = true
+}
+
+// Resume resumes scheduling and applies the latest request received while launcher was paused.
+func (launcher *Launcher) Resume() error {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	if !launcher.paused {
+		return nil
+	}
+
+	log.Info("Resume launcher")
+
+	launcher.paused = false
+
+	pausedRun, pausedRestart, pausedRebalancing := launcher.pausedRun, launcher.pausedRestart,
+		launcher.pausedRebalancing
+
+	launcher.pausedRun, launcher.pausedRestart, launcher.pausedRebalancing = nil, false, false
+
+	switch {
+	case pausedRun != nil:
+		launcher.preferredNodes = pausedRun.preferredNodes
+		defer func() { launcher.preferredNodes = nil }()
+
+		return launcher.runInstances(pausedRun.instances, pausedRun.newServices, pausedRun.correlationID)
+
+	case pausedRestart:
+		return launcher.restartInstances()
+
+	case pausedRebalancing:
+		launcher.rebalanceDesiredInstances()
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) restartInstances() error {
 	launcher.connectionTimer = time.AfterFunc(
-		launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.sendCurrentStatus)
+		launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.sendStatusOnTimeout)
 
 	for _, node := range launcher.nodes {
 		launcher.initNodeUnitConfiguration(node, node.NodeType)
 	}
 
+	// Instances are kept on their nodes unless node labels don't match instance labels anymore
+	launcher.preferredNodes = launcher.getLabelsCompliantPlacement()
+	defer func() { launcher.preferredNodes = nil }()
+
 	launcher.currentErrorStatus = launcher.performNodeBalancing(launcher.currentDesiredInstances)
 
 	return launcher.sendRunInstances(true)
 }
 
+// PreviewPlacement returns instances placement and failures which would be performed for desired instances without
+// applying it.
+func (launcher *Launcher) PreviewPlacement(instances []cloudprotocol.InstanceInfo) (
+	placement map[string][]aostypes.InstanceIdent, errStatus []cloudprotocol.InstanceStatus, err error,
+) {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	if len(launcher.nodes) == 0 {
+		return nil, nil, aoserrors.New("no nodes available")
+	}
+
+	planner := launcher.newPreviewPlanner()
+
+	errStatus = planner.placeInstances(getBalancingInstances(instances))
+	errStatus = append(errStatus, planner.orderRunRequestInstances()...)
+
+	placement = make(map[string][]aostypes.InstanceIdent)
+
+	for _, node := range planner.nodes {
+		for _, instance := range node.currentRunRequest.Instances {
+			placement[node.NodeID] = append(placement[node.NodeID], instance.InstanceIdent)
+		}
+	}
+
+	return placement, errStatus, nil
+}
+
+// GetUIDPoolUtilization returns instances UID pool utilization.
+func (launcher *Launcher) GetUIDPoolUtilization() uidgidpool.Utilization {
+	return launcher.instanceManager.uidPool.GetUtilization()
+}
+
+// GetBalancingMetrics returns snapshot of balancing metrics.
+func (launcher *Launcher) GetBalancingMetrics() BalancingMetrics {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	metrics := BalancingMetrics{
+		ScheduledInstances: make(map[string]uint64),
+		SchedulingFailures: make(map[string]uint64),
+		RebalancingEvents:  launcher.balancingMetrics.rebalancingEvents,
+	}
+
+	for nodeID, count := range launcher.balancingMetrics.scheduledInstances {
+		metrics.ScheduledInstances[nodeID] = count
+	}
+
+	for filter, count := range launcher.balancingMetrics.schedulingFailures {
+		metrics.SchedulingFailures[filter] = count
+	}
+
+	if launcher.balancingMetrics.placementCount != 0 {
+		metrics.AveragePlacementTime = launcher.balancingMetrics.placementTime /
+			time.Duration(launcher.balancingMetrics.placementCount)
+	}
+
+	return metrics
+}
+
+// ProcessUpdateInstanceStatus applies service restart policy and readiness probe to runtime instances status and
+// returns status to report.
+func (launcher *Launcher) ProcessUpdateInstanceStatus(
+	status []cloudprotocol.InstanceStatus,
+) []cloudprotocol.InstanceStatus {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	result := make([]cloudprotocol.InstanceStatus, 0, len(status))
+
+	for _, instanceStatus := range status {
+		launcher.updateNodeBreaker(instanceStatus.NodeID, []cloudprotocol.InstanceStatus{instanceStatus}, nil)
+
+		result = append(result, launcher.applyReadinessProbe(launcher.applyRestartPolicy(instanceStatus)))
+	}
+
+	return result
+}
+
 // GetRunStatusesChannel gets channel with run status instances status.
 func (launcher *Launcher) GetRunStatusesChannel() <-chan unitstatushandler.RunInstancesStatus {
 	return launcher.runStatusChannel
 }
 
+// GetInstanceEventsChannel gets channel with instance lifecycle events.
+func (launcher *Launcher) GetInstanceEventsChannel() <-chan InstanceEvent {
+	return launcher.instanceEventsChannel
+}
+
+// GetNodeEventsChannel gets channel with node events.
+func (launcher *Launcher) GetNodeEventsChannel() <-chan NodeEvent {
+	return launcher.nodeEventsChannel
+}
+
 // GetNodesConfiguration gets nodes configuration.
 func (launcher *Launcher) GetNodesConfiguration() []cloudprotocol.NodeInfo {
 	nodes := make([]cloudprotocol.NodeInfo, len(launcher.nodes))
@@ -308,91 +937,390 @@ func (launcher *Launcher) GetNodesConfiguration() []cloudprotocol.NodeInfo {
  **********************************************************************************************************************/
 
 func (launcher *Launcher) processChannels(ctx context.Context) {
+	var (
+		pendingAlerts    []cloudprotocol.SystemQuotaAlert
+		rebalancingTimer *time.Timer
+		rebalancingChan  <-chan time.Time
+	)
+
 	for {
 		select {
 		case instances := <-launcher.nodeManager.GetRunInstancesStatusChannel():
 			launcher.processRunInstanceStatus(instances)
 
 		case alert := <-launcher.nodeManager.GetSystemLimitAlertChannel():
-			launcher.performRebalancing(alert)
+			pendingAlerts = addPendingAlert(pendingAlerts, alert)
+
+			// Collect alerts during the window to perform single rebalancing
+			if rebalancingChan == nil {
+				rebalancingTimer = time.NewTimer(rebalancingAlertsWindow)
+				rebalancingChan = rebalancingTimer.C
+			}
+
+		case <-rebalancingChan:
+			rebalancingChan = nil
+
+			launcher.performRebalancing(getMostPressuredAlert(pendingAlerts))
+
+			pendingAlerts = nil
 
 		case <-ctx.Done():
+			if rebalancingTimer != nil {
+				rebalancingTimer.Stop()
+			}
+
 			return
 		}
 	}
 }
 
 func (launcher *Launcher) sendRunInstances(forceRestart bool) (err error) {
-	for _, node := range launcher.nodes {
-		node.waitStatus = true
+	// In observer mode instances are balanced as usual but run requests are never dispatched to nodes
+	if launcher.config.ObserverMode {
+		log.Debug("Observer mode: skip run instances")
 
-		if err := launcher.saveNodeRunRequest(node); err != nil {
-			log.WithFields(log.Fields{"nodeID": node.NodeID}).Errorf("Can't save node run request: %v", err)
-		}
+		return nil
+	}
 
-		if runErr := launcher.nodeManager.RunInstances(
-			node.NodeID, node.currentRunRequest.Services, node.currentRunRequest.Layers,
-			node.currentRunRequest.Instances, forceRestart); runErr != nil {
-			log.WithField("nodeID", node.NodeID).Errorf("Can't run instances %v", runErr)
+	for _, node := range launcher.nodes {
+		node.waitStatus = true
 
-			if err == nil {
-				err = runErr
-			}
+		if runErr := launcher.sendNodeRunInstances(node, forceRestart); runErr != nil && err == nil {
+			err = runErr
 		}
 	}
 
 	return err
 }
 
-func (launcher *Launcher) processRunInstanceStatus(runStatus NodeRunInstanceStatus) {
-	launcher.Lock()
-	defer launcher.Unlock()
+func (launcher *Launcher) sendNodeRunInstances(node *nodeStatus, forceRestart bool) error {
+	if launcher.paused {
+		log.WithField("nodeID", node.NodeID).Debug("Launcher paused, postpone node run instances")
 
-	log.Debugf("Received run status from nodeID: %s", runStatus.NodeID)
+		launcher.pausedRebalancing = true
 
-	currentStatus := launcher.getNode(runStatus.NodeID)
-	if currentStatus == nil {
-		if !slices.Contains(launcher.config.SMController.NodeIDs, runStatus.NodeID) {
-			log.Errorf("Received status for unknown nodeID  %s", runStatus.NodeID)
+		return nil
+	}
 
-			return
+	if launcher.config.ObserverMode {
+		log.WithField("nodeID", node.NodeID).Debug("Observer mode: skip node run instances")
+
+		return nil
+	}
+
+	if err := launcher.saveNodeRunRequest(node); err != nil {
+		log.WithFields(log.Fields{"nodeID": node.NodeID}).Errorf("Can't save node run request: %v", err)
+	}
+
+	// Full run request starts instances stopped for restart as well
+	node.restartingInstances = nil
+
+	runInstances := launcher.getNodeRunInstances(node)
+
+	node.heldInstances = nil
+
+	for _, instance := range node.currentRunRequest.Instances {
+		if !slices.ContainsFunc(runInstances, func(runInstance aostypes.InstanceInfo) bool {
+			return runInstance.InstanceIdent == instance.InstanceIdent
+		}) {
+			node.heldInstances = append(node.heldInstances, instance.InstanceIdent)
 		}
+	}
 
-		var err error
+	if err := launcher.nodeManager.RunInstances(
+		node.NodeID, node.currentRunRequest.Services, node.currentRunRequest.Layers,
+		runInstances, forceRestart); err != nil {
+		log.WithField("nodeID", node.NodeID).Errorf("Can't run instances %v", err)
 
-		currentStatus, err = launcher.initNodeStatus(runStatus.NodeID, runStatus.NodeType)
-		if err != nil {
-			log.Errorf("Can't init node: %v", err)
+		return aoserrors.Wrap(err)
+	}
 
-			return
+	node.runRequested = true
+
+	return nil
+}
+
+func (launcher *Launcher) isPendingMoveDestination(nodeID string) bool {
+	for _, move := range launcher.pendingMoves {
+		if move.destNodeID == nodeID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getNodeRunInstances returns node instances to run. Moved instances are held back till source node confirms their
+// stop. Instances of services depending on services with readiness probe are held back till dependencies are ready.
+func (launcher *Launcher) getNodeRunInstances(node *nodeStatus) []aostypes.InstanceInfo {
+	instances := make([]aostypes.InstanceInfo, 0, len(node.currentRunRequest.Instances))
+	readyServices := make(map[string]bool)
+
+	for _, instance := range node.currentRunRequest.Instances {
+		if slices.ContainsFunc(launcher.pendingMoves, func(move *pendingMove) bool {
+			return move.destNodeID == node.NodeID && move.instanceIdent == instance.InstanceIdent
+		}) {
+			continue
+		}
+
+		if !launcher.areDependenciesReady(instance.ServiceID, readyServices) {
+			log.WithFields(instanceIdentLogFields(instance.InstanceIdent, log.Fields{"nodeID": node.NodeID})).Debug(
+				"Hold instance till dependencies are ready")
+
+			continue
 		}
 
-		launcher.nodes = append(launcher.nodes, currentStatus)
+		instances = append(instances, instance)
+	}
+
+	return instances
+}
+
+// areDependenciesReady checks that service dependencies with readiness probe are ready. Dependency is ready when all
+// its scheduled instances pass readiness probe or fail.
+func (launcher *Launcher) areDependenciesReady(serviceID string, readyServices map[string]bool) bool {
+	if launcher.readinessProber == nil {
+		return true
+	}
+
+	serviceInfo, err := launcher.imageProvider.GetServiceInfo(serviceID)
+	if err != nil {
+		return true
+	}
 
-		if len(launcher.nodes) == len(launcher.config.SMController.NodeIDs) {
-			log.Debug("All clients connected")
+	for _, dependency := range serviceInfo.Config.Dependencies {
+		ready, ok := readyServices[dependency]
+		if !ok {
+			ready = launcher.isServiceReady(dependency)
+			readyServices[dependency] = ready
 		}
 
-		slices.SortFunc(launcher.nodes, func(a, b *nodeStatus) bool {
-			if a.priority == b.priority {
-				return a.NodeID < b.NodeID
+		if !ready {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (launcher *Launcher) isServiceReady(serviceID string) bool {
+	serviceInfo, err := launcher.imageProvider.GetServiceInfo(serviceID)
+	if err != nil || serviceInfo.Config.ReadinessProbe == nil {
+		return true
+	}
+
+	for _, node := range launcher.nodes {
+		for _, instance := range node.currentRunRequest.Instances {
+			if instance.ServiceID != serviceID {
+				continue
 			}
 
-			return a.priority > b.priority
-		})
+			if probeState, ok := launcher.instanceProbes[instance.InstanceIdent]; ok &&
+				(probeState.ready || probeState.failed) {
+				continue
+			}
+
+			if isInstanceFailed(node.receivedRunInstances, instance.InstanceIdent) {
+				continue
+			}
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// sendHeldInstances sends run instances to nodes with held instances which are ready to run.
+func (launcher *Launcher) sendHeldInstances() {
+	for _, node := range launcher.nodes {
+		if len(node.heldInstances) == 0 || node.waitStatus {
+			continue
+		}
+
+		if len(launcher.getNodeRunInstances(node)) == len(node.currentRunRequest.Instances)-len(node.heldInstances) {
+			continue
+		}
+
+		if err := launcher.sendNodeRunInstances(node, false); err != nil {
+			log.WithField("nodeID", node.NodeID).Errorf("Can't send held run instances: %v", err)
+
+			continue
+		}
+
+		node.waitStatus = true
+	}
+}
+
+func (launcher *Launcher) addPendingMove(instanceIdent aostypes.InstanceIdent, sourceNodeID, destNodeID string) {
+	moveTimeout := launcher.config.SMController.MoveStopTimeout.Duration
+	if moveTimeout <= 0 {
+		moveTimeout = defaultMoveStopTimeout
+	}
+
+	move := &pendingMove{instanceIdent: instanceIdent, sourceNodeID: sourceNodeID, destNodeID: destNodeID}
+
+	move.timer = time.AfterFunc(moveTimeout, func() {
+		launcher.Lock()
+		defer launcher.Unlock()
+
+		if !slices.Contains(launcher.pendingMoves, move) {
+			return
+		}
+
+		log.WithFields(instanceIdentLogFields(instanceIdent, log.Fields{
+			"sourceNodeID": sourceNodeID,
+			"destNodeID":   destNodeID,
+		})).Warn("Moved instance stop is not confirmed in time, start it on destination node")
+
+		move.timer = nil
+
+		launcher.completePendingMoves([]*pendingMove{move})
+	})
+
+	launcher.pendingMoves = append(launcher.pendingMoves, move)
+}
+
+func (launcher *Launcher) resetPendingMoves() {
+	for _, move := range launcher.pendingMoves {
+		if move.timer != nil {
+			move.timer.Stop()
+		}
+	}
+
+	launcher.pendingMoves = nil
+}
+
+func (launcher *Launcher) processPendingMoves(runStatus NodeRunInstanceStatus) {
+	var completedMoves []*pendingMove
+
+	for _, move := range launcher.pendingMoves {
+		if move.sourceNodeID != runStatus.NodeID || isInstanceRunning(runStatus.Instances, move.instanceIdent) {
+			continue
+		}
+
+		log.WithFields(instanceIdentLogFields(move.instanceIdent, log.Fields{
+			"sourceNodeID": move.sourceNodeID,
+			"destNodeID":   move.destNodeID,
+		})).Debug("Moved instance stopped on source node")
+
+		completedMoves = append(completedMoves, move)
+	}
+
+	launcher.completePendingMoves(completedMoves)
+}
+
+// completePendingMoves removes moves and sends run instances with moved instances to destination nodes.
+func (launcher *Launcher) completePendingMoves(moves []*pendingMove) {
+	var destNodes []string
+
+	for _, move := range moves {
+		if move.timer != nil {
+			move.timer.Stop()
+		}
+
+		if i := slices.Index(launcher.pendingMoves, move); i >= 0 {
+			launcher.pendingMoves = slices.Delete(launcher.pendingMoves, i, i+1)
+		}
+
+		if !slices.Contains(destNodes, move.destNodeID) {
+			destNodes = append(destNodes, move.destNodeID)
+		}
+	}
+
+	for _, nodeID := range destNodes {
+		node := launcher.getNode(nodeID)
+		if node == nil {
+			continue
+		}
+
+		if err := launcher.sendNodeRunInstances(node, false); err != nil {
+			log.Errorf("Can't send postponed run instances: %v", err)
+
+			continue
+		}
+
+		node.waitStatus = true
+	}
+}
+
+// resyncNodeInstances re-sends node portion of the current run request. Returns true if run request is sent and
+// node status is expected.
+func (launcher *Launcher) resyncNodeInstances(node *nodeStatus) bool {
+	if launcher.paused || launcher.config.ObserverMode {
+		return false
+	}
+
+	log.WithField("nodeID", node.NodeID).Warn("Node lost assigned instances, resend run instances")
+
+	if err := launcher.sendNodeRunInstances(node, false); err != nil {
+		log.WithField("nodeID", node.NodeID).Errorf("Can't resend run instances: %v", err)
+
+		return false
+	}
+
+	node.waitStatus = true
+
+	return true
+}
+
+func (launcher *Launcher) processRunInstanceStatus(runStatus NodeRunInstanceStatus) {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	log.Debugf("Received run status from nodeID: %s", runStatus.NodeID)
+
+	newNode := false
+
+	currentStatus := launcher.getNode(runStatus.NodeID)
+	if currentStatus == nil {
+		if currentStatus = launcher.addNode(runStatus.NodeID, runStatus.NodeType); currentStatus == nil {
+			return
+		}
+
+		newNode = true
 	}
 
+	// Unsolicited status without assigned instances means node was restarted and lost its instances
+	nodeRestarted := !newNode && !currentStatus.waitStatus &&
+		hasMissingInstances(launcher.getNodeRunInstances(currentStatus), runStatus.Instances)
+
+	// Node re-sent unsolicited status without changes
+	statusRepeated := !newNode && !currentStatus.waitStatus && !currentStatus.runRequested &&
+		reflect.DeepEqual(currentStatus.receivedRunInstances, runStatus.Instances)
+
+	launcher.validateNodeType(currentStatus, runStatus.NodeType)
+	launcher.updateNodeBreaker(runStatus.NodeID, runStatus.Instances, currentStatus.receivedRunInstances)
+
 	currentStatus.receivedRunInstances = runStatus.Instances
 	currentStatus.waitStatus = false
+	currentStatus.runRequested = false
 
-	if len(launcher.nodes) != len(launcher.config.SMController.NodeIDs) {
+	launcher.processPendingMoves(runStatus)
+	launcher.processPendingStops()
+	launcher.processActiveInstances(runStatus.Instances)
+	launcher.sendHeldInstances()
+
+	if launcher.startRestartingInstances(currentStatus) {
 		return
 	}
 
-	for _, node := range launcher.nodes {
-		if node.waitStatus {
-			return
-		}
+	if nodeRestarted && launcher.resyncNodeInstances(currentStatus) {
+		return
+	}
+
+	if newNode && launcher.processNewNode(runStatus.NodeID) {
+		return
+	}
+
+	if !launcher.isAllRunStatusesReceived() {
+		return
+	}
+
+	if statusRepeated && launcher.config.SMController.RepeatedRunStatus != RepeatedRunStatusSend {
+		log.WithField("nodeID", runStatus.NodeID).Debug("Skip sending unchanged run status")
+
+		return
 	}
 
 	log.Info("All SM statuses received")
@@ -402,11 +1330,128 @@ func (launcher *Launcher) processRunInstanceStatus(runStatus NodeRunInstanceStat
 	launcher.sendCurrentStatus()
 }
 
+// addNode adds node which sent its first run status. Returns nil if node can't be added.
+func (launcher *Launcher) addNode(nodeID, nodeType string) *nodeStatus {
+	if !slices.Contains(launcher.config.SMController.NodeIDs, nodeID) {
+		log.Errorf("Received status for unknown nodeID  %s", nodeID)
+
+		return nil
+	}
+
+	node, err := launcher.initNodeStatus(nodeID, nodeType)
+	if err != nil {
+		log.Errorf("Can't init node: %v", err)
+
+		return nil
+	}
+
+	launcher.nodes = append(launcher.nodes, node)
+
+	if len(launcher.nodes) == len(launcher.config.SMController.NodeIDs) {
+		log.Debug("All clients connected")
+
+		close(launcher.allNodesConnected)
+	}
+
+	slices.SortFunc(launcher.nodes, func(a, b *nodeStatus) bool {
+		if a.priority == b.priority {
+			return a.NodeID < b.NodeID
+		}
+
+		return a.priority > b.priority
+	})
+
+	return node
+}
+
+// processNewNode performs pending placement when new node is connected. Returns true if instances are rescheduled.
+func (launcher *Launcher) processNewNode(nodeID string) bool {
+	if launcher.pendingBalancing && launcher.isBalancingAllowed() {
+		log.Debug("Perform pending balancing")
+
+		launcher.connectionTimer.Stop()
+		launcher.balancePendingInstances()
+
+		return true
+	}
+
+	// New node adds capacity: re-evaluate deferred instances
+	if len(launcher.deferredServices) != 0 {
+		log.WithField("nodeID", nodeID).Debug("Re-evaluate deferred instances")
+
+		launcher.rebalanceDesiredInstances()
+
+		return true
+	}
+
+	return false
+}
+
+func (launcher *Launcher) isAllRunStatusesReceived() bool {
+	if len(launcher.nodes) != len(launcher.config.SMController.NodeIDs) {
+		return false
+	}
+
+	// Run status is sent when moved instances are started on destination nodes
+	for _, node := range launcher.nodes {
+		if node.waitStatus || launcher.isPendingMoveDestination(node.NodeID) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func addPendingAlert(
+	alerts []cloudprotocol.SystemQuotaAlert, alert cloudprotocol.SystemQuotaAlert,
+) []cloudprotocol.SystemQuotaAlert {
+	for i, pendingAlert := range alerts {
+		if pendingAlert.NodeID == alert.NodeID && pendingAlert.Parameter == alert.Parameter {
+			alerts[i] = alert
+
+			return alerts
+		}
+	}
+
+	return append(alerts, alert)
+}
+
+func getMostPressuredAlert(alerts []cloudprotocol.SystemQuotaAlert) (alert cloudprotocol.SystemQuotaAlert) {
+	nodeAlerts := make(map[string]int)
+	maxAlerts := 0
+
+	// The most pressured node is the node with the most alerted parameters, first alerted node wins on tie
+	for _, pendingAlert := range alerts {
+		nodeAlerts[pendingAlert.NodeID]++
+	}
+
+	for _, pendingAlert := range alerts {
+		if nodeAlerts[pendingAlert.NodeID] > maxAlerts {
+			maxAlerts = nodeAlerts[pendingAlert.NodeID]
+			alert = pendingAlert
+
+			continue
+		}
+
+		if pendingAlert.NodeID == alert.NodeID && pendingAlert.Value > alert.Value {
+			alert = pendingAlert
+		}
+	}
+
+	return alert
+}
+
 //nolint:funlen
 func (launcher *Launcher) performRebalancing(alert cloudprotocol.SystemQuotaAlert) {
 	launcher.Lock()
 	defer launcher.Unlock()
 
+	if launcher.paused {
+		log.WithField("nodeID", alert.NodeID).Debug("Launcher paused, skip rebalancing")
+
+		return
+	}
+
 	log.Debug("Perform rebalancing")
 
 	nodeWithIssue := launcher.getNode(alert.NodeID)
@@ -443,7 +1488,7 @@ func (launcher *Launcher) performRebalancing(alert cloudprotocol.SystemQuotaAler
 			log.Errorf("Can't get labels for instance %v", err)
 		}
 
-		nodes, err := launcher.getNodesByStaticResources(nodes, serviceInfo, cloudprotocol.InstanceInfo{
+		nodes, err := launcher.newPlacementPlanner().getNodesByStaticResources(nodes, serviceInfo, cloudprotocol.InstanceInfo{
 			ServiceID: currentInstance.ServiceID, SubjectID: currentInstance.SubjectID, Labels: labels,
 		})
 		if err != nil {
@@ -455,6 +1500,21 @@ func (launcher *Launcher) performRebalancing(alert cloudprotocol.SystemQuotaAler
 			continue
 		}
 
+		nodes, err = launcher.getNodesByExclusiveCPUs(nodes, serviceInfo.Config.ExclusiveCPUs)
+		if err != nil {
+			continue
+		}
+
+		nodes, err = launcher.getNodesByQuotas(nodes, serviceInfo.Config.Quotas)
+		if err != nil {
+			continue
+		}
+
+		nodes, err = launcher.getNodesByQuantitativeResources(nodes, serviceInfo.Config.QuantitativeResources)
+		if err != nil {
+			continue
+		}
+
 		nodes = launcher.getNodeByMonitoringData(nodes, alert.Parameter)
 
 		layersForService, err := launcher.getLayersForService(serviceInfo.Layers)
@@ -470,8 +1530,37 @@ func (launcher *Launcher) performRebalancing(alert cloudprotocol.SystemQuotaAler
 			continue
 		}
 
+		previousCPUs := currentInstance.CPUs
+
+		if currentInstance.CPUs, err = launcher.allocateExclusiveCPUs(
+			nodes[0], serviceInfo.Config.ExclusiveCPUs); err != nil {
+			log.Errorf("Can't allocate exclusive CPUs: %v", err)
+
+			if err := launcher.releaseDevices(nodes[0], serviceInfo.Config.Devices); err != nil {
+				log.Errorf("Can't release devices: %v", err)
+			}
+
+			continue
+		}
+
+		launcher.releaseExclusiveCPUs(nodeWithIssue, previousCPUs)
+		launcher.releaseQuotas(nodeWithIssue, serviceInfo.Config.Quotas)
+		launcher.allocateQuotas(nodes[0], serviceInfo.Config.Quotas)
+		launcher.releaseQuantitativeResources(nodeWithIssue, serviceInfo.Config.QuantitativeResources)
+		launcher.allocateQuantitativeResources(nodes[0], serviceInfo.Config.QuantitativeResources)
+
+		log.WithFields(log.Fields{
+			"serviceID":  currentInstance.ServiceID,
+			"subjectID":  currentInstance.SubjectID,
+			"instance":   currentInstance.Instance,
+			"fromNodeID": nodeWithIssue.NodeID,
+			"toNodeID":   nodes[0].NodeID,
+		}).Debug("Move instance")
+
 		launcher.addRunRequest(currentInstance, serviceInfo, layersForService, nodes[0])
 
+		launcher.balancingMetrics.rebalancingEvents++
+
 		if err := launcher.releaseDevices(nodeWithIssue, serviceInfo.Config.Devices); err != nil {
 			log.Errorf("Can't release devices: %v", err)
 
@@ -491,8 +1580,15 @@ func (launcher *Launcher) performRebalancing(alert cloudprotocol.SystemQuotaAler
 
 		launcher.removeRunRequest(currentInstance, nodeWithIssue)
 
+		if serviceInfo.Config.StopBeforeMove {
+			launcher.addPendingMove(currentInstance.InstanceIdent, nodeWithIssue.NodeID, nodes[0].NodeID)
+		}
+
+		launcher.currentErrorStatus = append(launcher.currentErrorStatus,
+			launcher.newPlacementPlanner().orderRunRequestInstances()...)
+
 		launcher.connectionTimer = time.AfterFunc(
-			launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.sendCurrentStatus)
+			launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.sendStatusOnTimeout)
 
 		if err := launcher.sendRunInstances(false); err != nil {
 			log.Errorf("Can't send run instance while rebalancing: %v", err)
@@ -526,12 +1622,25 @@ func (launcher *Launcher) initNodeStatus(nodeID, nodeType string) (*nodeStatus,
 }
 
 func (launcher *Launcher) initNodeUnitConfiguration(nodeStatus *nodeStatus, nodeType string) {
-	nodeUnitConfig := launcher.resourceManager.GetUnitConfiguration(nodeType)
+	// Resource manager error means unit config is not ready: node doesn't participate in balancing till valid
+	// config is received. Empty config is valid: there is no unit config or node type is not listed in it.
+	nodeUnitConfig, err := launcher.resourceManager.GetUnitConfiguration(nodeType)
 
-	nodeStatus.priority = nodeUnitConfig.Priority
-	nodeStatus.availableLabels = nodeUnitConfig.Labels
+	nodeStatus.configPending = err != nil
+
+	if nodeStatus.configPending {
+		log.WithFields(log.Fields{
+			"nodeID": nodeStatus.NodeID, "nodeType": nodeType,
+		}).Warnf("Node unit config is not available, defer node balancing: %v", err)
+
+		launcher.scheduleUnitConfigRetry()
+	}
+
+	nodeStatus.priority = nodeUnitConfig.Priority
+	nodeStatus.availableLabels = nodeUnitConfig.Labels
 	nodeStatus.availableResources = make([]string, len(nodeUnitConfig.Resources))
 	nodeStatus.availableDevices = make([]nodeDevice, len(nodeUnitConfig.Devices))
+	nodeStatus.availableQuantities = make([]nodeQuantitativeResource, len(nodeUnitConfig.QuantitativeResources))
 
 	for i, resource := range nodeUnitConfig.Resources {
 		nodeStatus.availableResources[i] = resource.Name
@@ -539,11 +1648,43 @@ func (launcher *Launcher) initNodeUnitConfiguration(nodeStatus *nodeStatus, node
 
 	for i, device := range nodeUnitConfig.Devices {
 		nodeStatus.availableDevices[i] = nodeDevice{
-			name: device.Name, sharedCount: device.SharedCount, allocatedCount: 0,
+			name: device.Name, sharedCount: device.SharedCount, allocated: 0,
 		}
 	}
 
+	for i, resource := range nodeUnitConfig.QuantitativeResources {
+		nodeStatus.availableQuantities[i] = nodeQuantitativeResource{name: resource.Name, capacity: resource.Capacity}
+	}
+
+	nodeStatus.availableRunners = getAvailableRunners(
+		nodeStatus.RunnerFeature, nodeUnitConfig.RunnerFeatures, nodeUnitConfig.DefaultRunnerFeatures)
+	nodeStatus.defaultRunner = nodeUnitConfig.DefaultRunner
+
+	if nodeStatus.defaultRunner == "" {
+		nodeStatus.defaultRunner = defaultRunner
+	}
+	nodeStatus.allocatedCPUs = make([]bool, nodeStatus.NumCPUs)
+
+	// Reserve is kept free on the node: balancing never packs node capacity to 100%. Quotas are fit to node capacity
+	// only if reserve is configured or over capacity instances are deferred, otherwise quotas overcommit is allowed.
+	var reserve aostypes.NodeResourceReserve
+
+	if nodeUnitConfig.ResourceReserve != nil {
+		reserve = *nodeUnitConfig.ResourceReserve
+	}
+
+	nodeStatus.capacityLimited = nodeUnitConfig.ResourceReserve != nil || launcher.config.SMController.DeferOverCapacity
+
+	nodeStatus.availableRAM = getReservedCapacity(nodeStatus.TotalRAM, reserve.RAM)
+	nodeStatus.availableCPU = getReservedCapacity(nodeStatus.NumCPUs*cpuCapacityPerCore, reserve.CPU)
+	nodeStatus.allocatedRAM, nodeStatus.allocatedCPU = 0, 0
+
 	for _, instance := range nodeStatus.currentRunRequest.Instances {
+		if err := launcher.reserveExclusiveCPUs(nodeStatus, instance.CPUs); err != nil {
+			log.WithFields(
+				instanceIdentLogFields(instance.InstanceIdent, nil)).Errorf("Can't reserve exclusive CPUs: %v", err)
+		}
+
 		serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
 		if err != nil {
 			log.WithFields(log.Fields{"serviceID": instance.ServiceID}).Errorf("Can't get service info: %v", err)
@@ -554,54 +1695,139 @@ func (launcher *Launcher) initNodeUnitConfiguration(nodeStatus *nodeStatus, node
 			log.WithFields(
 				instanceIdentLogFields(instance.InstanceIdent, nil)).Errorf("Can't allocate devices: %v", err)
 		}
+
+		launcher.allocateQuotas(nodeStatus, serviceInfo.Config.Quotas)
+		launcher.allocateQuantitativeResources(nodeStatus, serviceInfo.Config.QuantitativeResources)
 	}
 }
 
 func (launcher *Launcher) resetDeviceAllocation() {
 	for _, node := range launcher.nodes {
 		for i := range node.availableDevices {
-			node.availableDevices[i].allocatedCount = 0
+			node.availableDevices[i].allocated = 0
+		}
+
+		for i := range node.availableQuantities {
+			node.availableQuantities[i].allocated = 0
+		}
+	}
+}
+
+func (launcher *Launcher) resetCPUAllocation() {
+	for _, node := range launcher.nodes {
+		for i := range node.allocatedCPUs {
+			node.allocatedCPUs[i] = false
 		}
 	}
 }
 
+func (launcher *Launcher) resetQuotasAllocation() {
+	for _, node := range launcher.nodes {
+		node.allocatedRAM, node.allocatedCPU = 0, 0
+	}
+}
+
+// sendStatusOnTimeout sends current run status on nodes connection timeout. It is called from timer goroutine.
+func (launcher *Launcher) sendStatusOnTimeout() {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	launcher.sendCurrentStatus()
+}
+
 func (launcher *Launcher) sendCurrentStatus() {
 	runStatusToSend := unitstatushandler.RunInstancesStatus{
-		UnitSubjects: []string{}, Instances: []cloudprotocol.InstanceStatus{},
+		UnitSubjects: []string{}, Instances: launcher.getNodesRunStatus(), CorrelationID: launcher.correlationID,
+	}
+
+	// Correlation ID is reported only in the first run status of the request
+	launcher.correlationID = ""
+
+	errorInstances := []aostypes.InstanceIdent{}
+	checkStateIndexes := []int{}
+
+	for i := range runStatusToSend.Instances {
+		if runStatusToSend.Instances[i].ErrorInfo != nil {
+			errorInstances = append(errorInstances, runStatusToSend.Instances[i].InstanceIdent)
+
+			continue
+		}
+
+		runStatusToSend.Instances[i].StateChecksum = launcher.storageStateProvider.GetInstanceCheckSum(
+			runStatusToSend.Instances[i].InstanceIdent)
+		checkStateIndexes = append(checkStateIndexes, i)
+		runStatusToSend.Instances[i].AppliedQuotas = launcher.getAppliedQuotas(runStatusToSend.Instances[i])
+		runStatusToSend.Instances[i] = launcher.applyReadinessProbe(runStatusToSend.Instances[i])
 	}
 
+	launcher.cleanupInstanceProbes(runStatusToSend.Instances)
+
+	runStatusToSend.ErrorServices = launcher.processPendingNewServices(runStatusToSend.Instances)
+
+	launcher.processStoppedInstances(runStatusToSend.Instances, errorInstances)
+	launcher.sendInstanceEvents(runStatusToSend.Instances)
+
+	runStatusToSend.Instances = append(runStatusToSend.Instances, launcher.currentErrorStatus...)
+
+	launcher.statusSender.send(runStatusToSend, checkStateIndexes)
+
+	launcher.currentRunStatus = runStatusToSend.Instances
+	launcher.currentErrorStatus = []cloudprotocol.InstanceStatus{}
+}
+
+func (launcher *Launcher) getNodesRunStatus() (instances []cloudprotocol.InstanceStatus) {
+	instances = []cloudprotocol.InstanceStatus{}
+
 	for _, node := range launcher.nodes {
 		if node.waitStatus {
 			node.waitStatus = false
 
 			for _, errInstance := range node.currentRunRequest.Instances {
-				runStatusToSend.Instances = append(runStatusToSend.Instances, cloudprotocol.InstanceStatus{
+				instances = append(instances, cloudprotocol.InstanceStatus{
 					InstanceIdent: errInstance.InstanceIdent,
 					NodeID:        node.NodeID, RunState: cloudprotocol.InstanceStateFailed,
-					ErrorInfo: &cloudprotocol.ErrorInfo{Message: "wait run status timeout"},
+					ErrorInfo: &cloudprotocol.ErrorInfo{
+						ErrorCode: cloudprotocol.ErrorCodeRunTimeout, Message: "wait run status timeout",
+					},
 				})
 			}
 		} else {
-			runStatusToSend.Instances = append(runStatusToSend.Instances, node.receivedRunInstances...)
+			instances = append(instances, node.receivedRunInstances...)
+
+			// Held instances are not started yet
+			for _, instanceIdent := range node.heldInstances {
+				if !slices.ContainsFunc(node.receivedRunInstances, func(status cloudprotocol.InstanceStatus) bool {
+					return status.InstanceIdent == instanceIdent
+				}) {
+					instances = append(instances, cloudprotocol.InstanceStatus{
+						InstanceIdent: instanceIdent, NodeID: node.NodeID,
+						RunState: cloudprotocol.InstanceStateActivating,
+					})
+				}
+			}
 		}
 	}
 
-	errorInstances := []aostypes.InstanceIdent{}
+	return instances
+}
 
-	for i := range runStatusToSend.Instances {
-		if runStatusToSend.Instances[i].ErrorInfo != nil {
-			errorInstances = append(errorInstances, runStatusToSend.Instances[i].InstanceIdent)
+// processPendingNewServices reverts new services without running instances and returns their error statuses.
+func (launcher *Launcher) processPendingNewServices(
+	instances []cloudprotocol.InstanceStatus,
+) (errorServices []cloudprotocol.ServiceStatus) {
+	// Keep new services with instances waiting for layers: they will be checked on retry
+	pendingNewServices := []string{}
+
+newServicesLoop:
+	for _, newService := range launcher.pendingNewServices {
+		if slices.Contains(launcher.pendingLayerServices, newService) ||
+			slices.Contains(launcher.deferredServices, newService) {
+			pendingNewServices = append(pendingNewServices, newService)
 
 			continue
 		}
 
-		runStatusToSend.Instances[i].StateChecksum = launcher.storageStateProvider.GetInstanceCheckSum(
-			runStatusToSend.Instances[i].InstanceIdent)
-	}
-
-newServicesLoop:
-	for _, newService := range launcher.pendingNewServices {
-		for _, instance := range runStatusToSend.Instances {
+		for _, instance := range instances {
 			if instance.ServiceID == newService && instance.ErrorInfo == nil {
 				continue newServicesLoop
 			}
@@ -613,29 +1839,114 @@ newServicesLoop:
 
 		service, err := launcher.imageProvider.GetServiceInfo(newService)
 		if err != nil {
+			errorService.ErrorInfo.ErrorCode = cloudprotocol.ErrorCodeServiceNotFound
 			errorService.ErrorInfo.Message = err.Error()
 		} else {
 			errorService.AosVersion = service.AosVersion
+			errorService.ErrorInfo.ErrorCode = cloudprotocol.ErrorCodeFailed
 			errorService.ErrorInfo.Message = "can't run any instances"
 		}
 
-		runStatusToSend.ErrorServices = append(runStatusToSend.ErrorServices, errorService)
+		errorServices = append(errorServices, errorService)
 
 		if err := launcher.imageProvider.RevertService(newService); err != nil {
 			log.WithField("serviceID:", newService).Errorf("Can't revert service: %v", err)
 		}
 	}
 
-	launcher.pendingNewServices = []string{}
+	launcher.pendingNewServices = pendingNewServices
 
-	launcher.processStoppedInstances(runStatusToSend.Instances, errorInstances)
+	return errorServices
+}
 
-	runStatusToSend.Instances = append(runStatusToSend.Instances, launcher.currentErrorStatus...)
+func (launcher *Launcher) isStateCorrupted(instanceIdent aostypes.InstanceIdent) bool {
+	checker, ok := launcher.storageStateProvider.(StateIntegrityChecker)
+	if !ok {
+		return false
+	}
 
-	launcher.runStatusChannel <- runStatusToSend
+	if err := checker.CheckStateIntegrity(instanceIdent); err != nil {
+		log.WithFields(instanceIdentLogFields(instanceIdent, nil)).Errorf("State integrity check failed: %v", err)
 
-	launcher.currentRunStatus = runStatusToSend.Instances
-	launcher.currentErrorStatus = []cloudprotocol.InstanceStatus{}
+		return true
+	}
+
+	return false
+}
+
+func (launcher *Launcher) sendInstanceEvents(newStatus []cloudprotocol.InstanceStatus) {
+	currentNodes := make(map[aostypes.InstanceIdent]string)
+
+	for _, status := range launcher.currentRunStatus {
+		if status.ErrorInfo == nil {
+			currentNodes[status.InstanceIdent] = status.NodeID
+		}
+	}
+
+	newNodes := make(map[aostypes.InstanceIdent]string)
+
+	for _, status := range newStatus {
+		if status.ErrorInfo != nil {
+			continue
+		}
+
+		newNodes[status.InstanceIdent] = status.NodeID
+
+		currentNodeID, ok := currentNodes[status.InstanceIdent]
+
+		switch {
+		case !ok:
+			launcher.sendInstanceEvent(InstanceEvent{
+				InstanceIdent: status.InstanceIdent, EventType: InstanceEventStarted, NodeID: status.NodeID,
+			})
+
+		case currentNodeID != status.NodeID:
+			launcher.sendInstanceEvent(InstanceEvent{
+				InstanceIdent: status.InstanceIdent, EventType: InstanceEventMoved,
+				NodeID: status.NodeID, PrevNodeID: currentNodeID,
+			})
+		}
+	}
+
+	for _, status := range launcher.currentRunStatus {
+		if status.ErrorInfo != nil {
+			continue
+		}
+
+		if _, ok := newNodes[status.InstanceIdent]; !ok {
+			launcher.sendInstanceEvent(InstanceEvent{
+				InstanceIdent: status.InstanceIdent, EventType: InstanceEventStopped, PrevNodeID: status.NodeID,
+			})
+		}
+	}
+}
+
+func (launcher *Launcher) sendInstanceEvent(event InstanceEvent) {
+	log.WithFields(log.Fields{
+		"serviceID":  event.ServiceID,
+		"subjectID":  event.SubjectID,
+		"instance":   event.Instance,
+		"nodeID":     event.NodeID,
+		"prevNodeID": event.PrevNodeID,
+	}).Debugf("Instance %s", event.EventType)
+
+	select {
+	case launcher.instanceEventsChannel <- event:
+
+	default:
+		log.Warn("Instance events channel is full")
+	}
+}
+
+func (launcher *Launcher) sendNodeEvent(event NodeEvent) {
+	log.WithField("nodeID", event.NodeID).Debugf("Node %s", event.EventType)
+
+	select {
+	case launcher.nodeEventsChannel <- event:
+
+	default:
+		log.Warn("Node events channel is full")
+	}
 }
 
 func (launcher *Launcher) processStoppedInstances(
@@ -663,491 +1974,1889 @@ currentInstancesLoop:
 	}
 
 	for _, stopIdent := range stoppedInstances {
-		if err := launcher.storageStateProvider.Cleanup(stopIdent); err != nil {
-			log.Errorf("Can't cleanup state storage for instance: %v", err)
-		}
+		launcher.waitInstanceStop(stopIdent)
 	}
 }
 
-func (launcher *Launcher) updateNetworks(instances []cloudprotocol.InstanceInfo) error {
-	providers := make([]string, len(instances))
+// waitInstanceStop cleans up state of stopped instance when its stop is confirmed: instance is not reported as
+// running by any node. If instance is not confirmed as stopped within configured timeout, it is flagged and its
+// state is kept till confirmation.
+func (launcher *Launcher) waitInstanceStop(instanceIdent aostypes.InstanceIdent) {
+	stopTimeout := launcher.config.SMController.InstanceStopTimeout.Duration
 
-	for i, instance := range instances {
-		serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
-		if err != nil {
-			return aoserrors.Wrap(err)
-		}
+	nodeID, running := launcher.getRunningInstanceNode(instanceIdent)
+	if stopTimeout <= 0 || !running {
+		launcher.quarantineInstanceState(instanceIdent)
 
-		providers[i] = serviceInfo.ProviderID
+		return
 	}
 
-	for _, node := range launcher.nodes {
-		if err := launcher.networkManager.UpdateProviderNetwork(providers, node.NodeID); err != nil {
-			return aoserrors.Wrap(err)
-		}
+	if _, ok := launcher.pendingStops[instanceIdent]; ok {
+		return
 	}
 
-	return nil
-}
+	log.WithFields(instanceIdentLogFields(instanceIdent, log.Fields{"nodeID": nodeID})).Debug(
+		"Wait instance stop confirmation")
 
-//nolint:funlen
-func (launcher *Launcher) performNodeBalancing(instances []cloudprotocol.InstanceInfo,
-) (errStatus []cloudprotocol.InstanceStatus) {
-	for _, node := range launcher.nodes {
-		node.currentRunRequest = &runRequestInfo{}
-	}
+	stop := &pendingStop{nodeID: nodeID}
 
-	launcher.resetDeviceAllocation()
+	stop.timer = time.AfterFunc(stopTimeout, func() {
+		launcher.Lock()
+		defer launcher.Unlock()
 
-	sort.Slice(instances, func(i, j int) bool {
-		if instances[i].Priority == instances[j].Priority {
-			return instances[i].ServiceID < instances[j].ServiceID
+		if launcher.pendingStops[instanceIdent] != stop {
+			return
 		}
 
-		return instances[i].Priority > instances[j].Priority
-	})
+		stop.timer, stop.timedOut = nil, true
 
-	launcher.cacheInstances(instances)
-	launcher.removeInstanceNetworkParameters(instances)
+		log.WithFields(instanceIdentLogFields(instanceIdent, log.Fields{"nodeID": stop.nodeID})).Error(
+			"Instance stop is not confirmed in time")
 
-	for _, instance := range instances {
-		log.WithFields(log.Fields{
-			"serviceID":    instance.ServiceID,
-			"subjectID":    instance.SubjectID,
-			"numInstances": instance.NumInstances,
-			"priority":     instance.Priority,
-		}).Debug("Balance instances")
+		launcher.sendInstanceEvent(InstanceEvent{
+			InstanceIdent: instanceIdent, EventType: InstanceEventStopTimeout, NodeID: stop.nodeID,
+		})
+	})
 
-		serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
-		if err != nil {
-			errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID, 0, 0,
-				cloudprotocol.InstanceStateFailed, err.Error()))
+	launcher.pendingStops[instanceIdent] = stop
+}
 
+func (launcher *Launcher) processPendingStops() {
+	for instanceIdent, stop := range launcher.pendingStops {
+		if _, running := launcher.getRunningInstanceNode(instanceIdent); running {
 			continue
 		}
 
-		if serviceInfo.Cached {
-			errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID, 0, 0,
-				cloudprotocol.InstanceStateFailed, "service deleted"))
+		log.WithFields(instanceIdentLogFields(instanceIdent, log.Fields{"timedOut": stop.timedOut})).Debug(
+			"Instance stop confirmed")
 
-			continue
-		}
+		launcher.cancelPendingStop(instanceIdent)
+		launcher.quarantineInstanceState(instanceIdent)
+	}
+}
 
-		layers, err := launcher.getLayersForService(serviceInfo.Layers)
-		if err != nil {
-			for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
-				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
-					instanceIndex, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed, err.Error()))
-			}
+func (launcher *Launcher) cancelPendingStop(instanceIdent aostypes.InstanceIdent) {
+	stop, ok := launcher.pendingStops[instanceIdent]
+	if !ok {
+		return
+	}
 
-			continue
-		}
+	if stop.timer != nil {
+		stop.timer.Stop()
+	}
 
-		nodes, err := launcher.getNodesByStaticResources(launcher.nodes, serviceInfo, instance)
-		if err != nil {
-			for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
-				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
-					instanceIndex, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed, err.Error()))
-			}
+	delete(launcher.pendingStops, instanceIdent)
+}
 
-			continue
+func (launcher *Launcher) getRunningInstanceNode(instanceIdent aostypes.InstanceIdent) (nodeID string, running bool) {
+	for _, node := range launcher.nodes {
+		for _, status := range node.receivedRunInstances {
+			if status.InstanceIdent == instanceIdent && status.ErrorInfo == nil &&
+				status.RunState != cloudprotocol.InstanceStateFailed {
+				return node.NodeID, true
+			}
 		}
+	}
 
-		// createInstanceStatusFromInfo
+	return "", false
+}
 
-		for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
-			nodeForInstance, err := launcher.getNodesByDevices(nodes, serviceInfo.Config.Devices)
-			if err != nil {
-				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
-					instanceIndex, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed, err.Error()))
+func (launcher *Launcher) quarantineInstanceState(instanceIdent aostypes.InstanceIdent) {
+	gracePeriod := launcher.config.SMController.StateCleanupGracePeriod.Duration
 
-				continue
-			}
+	if gracePeriod <= 0 {
+		launcher.cleanupInstanceState(instanceIdent)
 
-			instanceInfo, err := launcher.prepareInstanceStartInfo(serviceInfo, instance, instanceIndex)
-			if err != nil {
-				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
-					instanceIndex, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed, err.Error()))
-			}
+		return
+	}
 
-			node := launcher.getMostPriorityNode(nodeForInstance, serviceInfo)
+	if _, ok := launcher.quarantinedStates[instanceIdent]; ok {
+		return
+	}
 
-			if err = launcher.allocateDevices(node, serviceInfo.Config.Devices); err != nil {
-				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
-					instanceIndex, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed, err.Error()))
+	log.WithFields(instanceIdentLogFields(instanceIdent, log.Fields{"gracePeriod": gracePeriod})).Debug(
+		"Quarantine instance state")
 
-				continue
-			}
+	launcher.quarantinedStates[instanceIdent] = time.AfterFunc(gracePeriod, func() {
+		launcher.Lock()
+		defer launcher.Unlock()
 
-			launcher.addRunRequest(instanceInfo, serviceInfo, layers, node)
+		if _, ok := launcher.quarantinedStates[instanceIdent]; !ok {
+			return
 		}
-	}
-
-	// first prepare network for instance which have exposed ports
-	errNetworkStatus := launcher.prepareNetworkForInstances(true)
-	errStatus = append(errStatus, errNetworkStatus...)
 
-	// then prepare network for rest of instances
-	errNetworkStatus = launcher.prepareNetworkForInstances(false)
-	errStatus = append(errStatus, errNetworkStatus...)
+		delete(launcher.quarantinedStates, instanceIdent)
 
-	return errStatus
+		launcher.cleanupInstanceState(instanceIdent)
+	})
 }
 
-func (launcher *Launcher) prepareNetworkForInstances(onlyExposedPorts bool) (errStatus []cloudprotocol.InstanceStatus) {
-	for _, node := range launcher.nodes {
-		for i, instance := range node.currentRunRequest.Instances {
-			serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
-			if err != nil {
-				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID, 0, 0,
-					cloudprotocol.InstanceStateFailed, err.Error()))
+func (launcher *Launcher) releaseQuarantinedState(instanceIdent aostypes.InstanceIdent) {
+	cleanupTimer, ok := launcher.quarantinedStates[instanceIdent]
+	if !ok {
+		return
+	}
 
-				continue
-			}
+	log.WithFields(instanceIdentLogFields(instanceIdent, nil)).Debug("Reuse quarantined instance state")
 
-			if onlyExposedPorts && len(serviceInfo.ExposedPorts) == 0 {
-				continue
-			}
+	cleanupTimer.Stop()
+	delete(launcher.quarantinedStates, instanceIdent)
+}
 
-			if instance.NetworkParameters, err = launcher.networkManager.PrepareInstanceNetworkParameters(
-				instance.InstanceIdent, serviceInfo.ProviderID,
-				prepareNetworkParameters(instance, serviceInfo)); err != nil {
-				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
-					instance.Instance, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed, err.Error()))
-			}
+func (launcher *Launcher) applyRestartPolicy(status cloudprotocol.InstanceStatus) cloudprotocol.InstanceStatus {
+	// Instances are never restarted in observer mode: report status as is
+	if launcher.config.ObserverMode {
+		return status
+	}
 
-			node.currentRunRequest.Instances[i] = instance
-		}
+	node := launcher.getNode(status.NodeID)
+	if node == nil || !containsInstance(node.currentRunRequest.Instances, status.InstanceIdent) {
+		return status
 	}
 
-	return errStatus
-}
+	serviceInfo, err := launcher.imageProvider.GetServiceInfo(status.ServiceID)
+	if err != nil {
+		return status
+	}
 
-func prepareNetworkParameters(
-	instance aostypes.InstanceInfo, serviceInfo imagemanager.ServiceInfo,
-) networkmanager.NetworkParameters {
-	var hosts []string
-	if serviceInfo.Config.Hostname != nil {
-		hosts = append(hosts, *serviceInfo.Config.Hostname)
+	policy := serviceInfo.Config.RestartPolicy
+	if policy == nil {
+		return status
 	}
 
-	params := networkmanager.NetworkParameters{
-		Hosts:       hosts,
-		ExposePorts: serviceInfo.ExposedPorts,
+	if !isRestartRequired(policy.Policy, status.RunState) {
+		launcher.processActiveInstances([]cloudprotocol.InstanceStatus{status})
+
+		return status
 	}
 
-	params.AllowConnections = make([]string, 0, len(serviceInfo.Config.AllowedConnections))
+	restart, ok := launcher.instanceRestarts[status.InstanceIdent]
+	if !ok {
+		restart = &instanceRestart{}
+		launcher.instanceRestarts[status.InstanceIdent] = restart
+	}
 
-	for key := range serviceInfo.Config.AllowedConnections {
-		params.AllowConnections = append(params.AllowConnections, key)
+	if restart.resetTimer != nil {
+		restart.resetTimer.Stop()
+		restart.resetTimer = nil
 	}
 
-	return params
-}
+	if restart.timer != nil {
+		status.RunState, status.ErrorInfo = cloudprotocol.InstanceStateActivating, nil
 
-func (launcher *Launcher) removeInstanceNetworkParameters(instances []cloudprotocol.InstanceInfo) {
-	networkInstances := launcher.networkManager.GetInstances()
+		return status
+	}
 
-nextNetInstance:
-	for _, netInstance := range networkInstances {
-		for _, instance := range instances {
-			for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
-				instanceIdent := aostypes.InstanceIdent{
-					ServiceID: instance.ServiceID, SubjectID: instance.SubjectID,
-					Instance: instanceIndex,
-				}
+	if policy.MaxRetries != 0 && restart.retries >= policy.MaxRetries {
+		log.WithFields(instanceIdentLogFields(status.InstanceIdent, log.Fields{"retries": restart.retries})).Error(
+			"Instance restart retries exhausted")
 
-				if netInstance == instanceIdent {
-					continue nextNetInstance
-				}
-			}
+		errorInfo := &cloudprotocol.ErrorInfo{
+			ErrorCode: cloudprotocol.ErrorCodeRestartExhausted,
+			Message:   fmt.Sprintf("restart retries exhausted after %d attempts", restart.retries),
 		}
 
-		serviceInfo, err := launcher.imageProvider.GetServiceInfo(netInstance.ServiceID)
-		if err != nil {
-			log.WithField("serviceID", netInstance.ServiceID).Errorf("Can't get service info: %v", err)
-			continue
+		if status.ErrorInfo != nil {
+			errorInfo.AosCode, errorInfo.ExitCode = status.ErrorInfo.AosCode, status.ErrorInfo.ExitCode
+			errorInfo.Message += ": " + status.ErrorInfo.Message
 		}
 
-		launcher.networkManager.RemoveInstanceNetworkParameters(netInstance, serviceInfo.ProviderID)
+		status.RunState, status.ErrorInfo = cloudprotocol.InstanceStateFailed, errorInfo
+
+		return status
 	}
+
+	delay := getRestartBackoff(policy.Backoff.Duration, restart.retries)
+	restart.retries++
+
+	log.WithFields(instanceIdentLogFields(status.InstanceIdent, log.Fields{
+		"retry": restart.retries, "delay": delay,
+	})).Debug("Schedule instance restart")
+
+	instanceIdent := status.InstanceIdent
+
+	restart.timer = time.AfterFunc(delay, func() {
+		launcher.Lock()
+		defer launcher.Unlock()
+
+		if launcher.instanceRestarts[instanceIdent] != restart {
+			return
+		}
+
+		restart.timer = nil
+
+		launcher.restartInstance(instanceIdent)
+	})
+
+	status.RunState, status.ErrorInfo = cloudprotocol.InstanceStateActivating, nil
+
+	return status
 }
 
-func (launcher *Launcher) cacheInstances(instances []cloudprotocol.InstanceInfo) {
-	storeInstances, err := launcher.storage.GetInstances()
-	if err != nil {
-		log.Errorf("Can't get instances from storage: %v", err)
+// restartInstance stops failed instance by sending node run request without it. The instance is started again when
+// the node reports its status. Other instances of the node are not affected.
+func (launcher *Launcher) restartInstance(instanceIdent aostypes.InstanceIdent) {
+	if launcher.paused {
+		log.WithFields(instanceIdentLogFields(instanceIdent, nil)).Debug("Launcher paused, skip instance restart")
+
 		return
 	}
 
-nextStoreInstance:
-	for _, storeInstance := range storeInstances {
-		for _, instance := range instances {
-			for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
-				instanceIdent := aostypes.InstanceIdent{
-					ServiceID: instance.ServiceID, SubjectID: instance.SubjectID,
-					Instance: instanceIndex,
-				}
+	for _, node := range launcher.nodes {
+		if !containsInstance(node.currentRunRequest.Instances, instanceIdent) {
+			continue
+		}
 
-				if storeInstance.InstanceIdent == instanceIdent {
-					continue nextStoreInstance
-				}
+		log.WithFields(instanceIdentLogFields(instanceIdent, log.Fields{"nodeID": node.NodeID})).Debug(
+			"Restart instance")
+
+		instances := make([]aostypes.InstanceInfo, 0, len(node.currentRunRequest.Instances))
+
+		for _, instance := range node.currentRunRequest.Instances {
+			if instance.InstanceIdent != instanceIdent {
+				instances = append(instances, instance)
 			}
 		}
 
-		log.WithFields(instanceIdentLogFields(storeInstance.InstanceIdent, nil)).Debug("Cache instance")
+		if err := launcher.nodeManager.RunInstances(node.NodeID, node.currentRunRequest.Services,
+			node.currentRunRequest.Layers, instances, false); err != nil {
+			log.WithFields(instanceIdentLogFields(instanceIdent, nil)).Errorf("Can't restart instance: %v", err)
 
-		if err := launcher.storage.SetInstanceCached(storeInstance.InstanceIdent, true); err != nil {
-			log.WithFields(
-				instanceIdentLogFields(storeInstance.InstanceIdent, nil)).Errorf("Can't mark instance as cached: %v", err)
+			return
 		}
+
+		node.restartingInstances = append(node.restartingInstances, instanceIdent)
+		node.waitStatus = true
+		node.runRequested = true
+
+		return
 	}
 }
 
-func (launcher *Launcher) prepareInstanceStartInfo(service imagemanager.ServiceInfo,
-	instance cloudprotocol.InstanceInfo, index uint64,
-) (aostypes.InstanceInfo, error) {
-	instanceInfo := aostypes.InstanceInfo{InstanceIdent: aostypes.InstanceIdent{
-		ServiceID: instance.ServiceID, SubjectID: instance.SubjectID,
-		Instance: index,
-	}, Priority: instance.Priority}
+// startRestartingInstances sends node run request with instances stopped for restart. Returns true if run request
+// is sent and node status is expected.
+func (launcher *Launcher) startRestartingInstances(node *nodeStatus) bool {
+	if len(node.restartingInstances) == 0 {
+		return false
+	}
 
-	uid, err := launcher.storage.GetInstanceUID(instanceInfo.InstanceIdent)
-	if err != nil {
-		if !errors.Is(err, ErrNotExist) {
-			return instanceInfo, aoserrors.Wrap(err)
-		}
+	log.WithField("nodeID", node.NodeID).Debug("Start restarting instances")
 
-		uid, err = launcher.instanceManager.acquireUID()
-		if err != nil {
-			return instanceInfo, aoserrors.Wrap(err)
-		}
+	if err := launcher.sendNodeRunInstances(node, false); err != nil {
+		log.WithField("nodeID", node.NodeID).Errorf("Can't start restarting instances: %v", err)
 
-		if err := launcher.storage.AddInstance(InstanceInfo{
-			InstanceIdent: instanceInfo.InstanceIdent,
-			UID:           uid,
-			Timestamp:     time.Now(),
-		}); err != nil {
-			log.Errorf("Can't store uid: %v", err)
-		}
+		return false
 	}
 
-	instanceInfo.UID = uint32(uid)
+	node.waitStatus = true
 
-	stateStorageParams := storagestate.SetupParams{
-		InstanceIdent: instanceInfo.InstanceIdent,
-		UID:           uid, GID: int(service.GID),
-	}
+	return true
+}
 
-	if service.Config.Quotas.StateLimit != nil {
-		stateStorageParams.StateQuota = *service.Config.Quotas.StateLimit
-	}
+// processActiveInstances resets restart retries of instance which stays active for the next restart backoff period.
+func (launcher *Launcher) processActiveInstances(instances []cloudprotocol.InstanceStatus) {
+	for _, status := range instances {
+		if status.RunState != cloudprotocol.InstanceStateActive || status.ErrorInfo != nil {
+			continue
+		}
 
-	if service.Config.Quotas.StorageLimit != nil {
-		stateStorageParams.StorageQuota = *service.Config.Quotas.StorageLimit
-	}
+		restart, ok := launcher.instanceRestarts[status.InstanceIdent]
+		if !ok || restart.timer != nil || restart.resetTimer != nil {
+			continue
+		}
 
-	instanceInfo.StoragePath, instanceInfo.StatePath, err = launcher.storageStateProvider.Setup(stateStorageParams)
-	if err != nil {
-		_ = launcher.instanceManager.releaseUID(uid)
+		var backoff time.Duration
 
-		return instanceInfo, aoserrors.Wrap(err)
-	}
+		if serviceInfo, err := launcher.imageProvider.GetServiceInfo(status.ServiceID); err == nil &&
+			serviceInfo.Config.RestartPolicy != nil {
+			backoff = serviceInfo.Config.RestartPolicy.Backoff.Duration
+		}
 
-	// make sure that instance is not cached
-	if err := launcher.storage.SetInstanceCached(instanceInfo.InstanceIdent, false); err != nil {
-		log.WithFields(instanceIdentLogFields(instanceInfo.InstanceIdent,
-			nil)).Errorf("Can't mark instance as not cached: %v", err)
+		instanceIdent := status.InstanceIdent
 
-		return instanceInfo, aoserrors.Wrap(err)
-	}
+		restart.resetTimer = time.AfterFunc(getRestartBackoff(backoff, restart.retries), func() {
+			launcher.Lock()
+			defer launcher.Unlock()
 
-	return instanceInfo, nil
-}
+			if launcher.instanceRestarts[instanceIdent] != restart || restart.resetTimer == nil {
+				return
+			}
 
-func (launcher *Launcher) getNodesByStaticResources(allNodes []*nodeStatus,
-	serviceInfo imagemanager.ServiceInfo, instanceInfo cloudprotocol.InstanceInfo,
-) ([]*nodeStatus, error) {
-	nodes := launcher.getNodeByRunner(allNodes, serviceInfo.Config.Runner)
-	if len(nodes) == 0 {
-		return nodes, aoserrors.Errorf("no node with runner: %s", serviceInfo.Config.Runner)
-	}
+			log.WithFields(instanceIdentLogFields(instanceIdent, nil)).Debug("Reset instance restart retries")
 
-	nodes = launcher.getNodesByLabels(nodes, instanceInfo.Labels)
-	if len(nodes) == 0 {
-		return nodes, aoserrors.Errorf("no node with labels %v", instanceInfo.Labels)
+			delete(launcher.instanceRestarts, instanceIdent)
+		})
 	}
+}
 
-	nodes = launcher.getNodesByResources(nodes, serviceInfo.Config.Resources)
-	if len(nodes) == 0 {
-		return nodes, aoserrors.Errorf("no node with resources %v", serviceInfo.Config.Resources)
+func (launcher *Launcher) resetInstanceRestarts() {
+	for _, restart := range launcher.instanceRestarts {
+		if restart.timer != nil {
+			restart.timer.Stop()
+		}
+
+		if restart.resetTimer != nil {
+			restart.resetTimer.Stop()
+		}
 	}
 
-	return nodes, nil
+	launcher.instanceRestarts = make(map[aostypes.InstanceIdent]*instanceRestart)
 }
 
-func (launcher *Launcher) getNodesByDevices(
-	availableNodes []*nodeStatus, desiredDevices []aostypes.ServiceDevice,
-) ([]*nodeStatus, error) {
-	if len(desiredDevices) == 0 {
-		return slices.Clone(availableNodes), nil
+// applyReadinessProbe reports active instance of service with readiness probe as activating till the probe passes.
+func (launcher *Launcher) applyReadinessProbe(status cloudprotocol.InstanceStatus) cloudprotocol.InstanceStatus {
+	if launcher.readinessProber == nil {
+		return status
 	}
 
-	nodes := make([]*nodeStatus, 0)
+	if status.RunState != cloudprotocol.InstanceStateActive || status.ErrorInfo != nil {
+		launcher.removeInstanceProbe(status.InstanceIdent)
 
-	for _, node := range availableNodes {
-		if !launcher.nodeHasDesiredDevices(node, desiredDevices) {
-			continue
+		return status
+	}
+
+	if probeState, ok := launcher.instanceProbes[status.InstanceIdent]; ok && probeState.nodeID == status.NodeID {
+		switch {
+		case probeState.failed:
+			status.RunState = cloudprotocol.InstanceStateFailed
+			status.ErrorInfo = &cloudprotocol.ErrorInfo{
+				ErrorCode: cloudprotocol.ErrorCodeFailed, Message: "readiness probe failed",
+			}
+
+		case !probeState.ready:
+			status.RunState = cloudprotocol.InstanceStateActivating
 		}
 
-		nodes = append(nodes, node)
+		return status
 	}
 
-	if len(nodes) == 0 {
-		return nodes, aoserrors.New("no available device found")
+	// Instance is started or moved to another node: probe it from the beginning
+	launcher.removeInstanceProbe(status.InstanceIdent)
+
+	serviceInfo, err := launcher.imageProvider.GetServiceInfo(status.ServiceID)
+	if err != nil || serviceInfo.Config.ReadinessProbe == nil {
+		return status
 	}
 
-	return nodes, nil
+	probeState := &instanceProbe{nodeID: status.NodeID}
+	launcher.instanceProbes[status.InstanceIdent] = probeState
+
+	launcher.scheduleReadinessProbe(status.InstanceIdent, probeState, *serviceInfo.Config.ReadinessProbe)
+
+	status.RunState = cloudprotocol.InstanceStateActivating
+
+	return status
 }
 
-func (launcher *Launcher) getNodeByMonitoringData(nodes []*nodeStatus, alertType string) (newNodes []*nodeStatus) {
-	if len(nodes) == 1 {
-		return nodes
+func (launcher *Launcher) scheduleReadinessProbe(
+	instanceIdent aostypes.InstanceIdent, probeState *instanceProbe, probe aostypes.ServiceReadinessProbe,
+) {
+	period := probe.Period.Duration
+	if period <= 0 {
+		period = defaultReadinessProbePeriod
 	}
 
-	type freeNodeResources struct {
-		node    *nodeStatus
-		freeRAM uint64
-		freeCPU uint64
-	}
+	probeState.timer = time.AfterFunc(period, func() {
+		launcher.Lock()
 
-	nodesResources := []freeNodeResources{}
+		if launcher.instanceProbes[instanceIdent] != probeState {
+			launcher.Unlock()
 
-	for _, node := range nodes {
-		monitoringData, err := launcher.nodeManager.GetNodeMonitoringData(node.NodeID)
-		if err != nil {
-			log.Errorf("Can't get node monitoring data: %v", err)
+			return
 		}
 
-		nodesResources = append(nodesResources, freeNodeResources{
-			node:    node,
-			freeRAM: node.TotalRAM - monitoringData.RAM,
-			freeCPU: node.NumCPUs*100 - monitoringData.CPU,
-		})
-	}
+		nodeID := probeState.nodeID
 
-	if alertType == "cpu" {
-		slices.SortFunc(nodesResources, func(a, b freeNodeResources) bool {
-			return a.freeCPU > b.freeCPU
-		})
-	} else {
-		slices.SortFunc(nodesResources, func(a, b freeNodeResources) bool {
-			return a.freeRAM > b.freeRAM
-		})
-	}
+		launcher.Unlock()
 
-	for _, sortedResources := range nodesResources {
-		newNodes = append(newNodes, sortedResources.node)
-	}
+		// Probe is performed outside launcher lock as it waits for node response
+		ready, err := launcher.probeInstanceReadiness(nodeID, instanceIdent, probe)
 
-	return newNodes
-}
+		launcher.Lock()
+		defer launcher.Unlock()
 
-func (launcher *Launcher) nodeHasDesiredDevices(node *nodeStatus, desiredDevices []aostypes.ServiceDevice) bool {
-devicesLoop:
-	for _, desiredDevice := range desiredDevices {
-		for _, nodeDevice := range node.availableDevices {
-			if desiredDevice.Name != nodeDevice.name {
-				continue
-			}
+		if launcher.instanceProbes[instanceIdent] != probeState {
+			return
+		}
 
-			if nodeDevice.sharedCount == 0 || nodeDevice.allocatedCount != nodeDevice.sharedCount {
-				continue devicesLoop
-			}
+		if err != nil {
+			log.WithFields(instanceIdentLogFields(instanceIdent, nil)).Warnf("Readiness probe error: %v", err)
 		}
 
-		return false
-	}
+		if !ready {
+			probeState.failures++
 
-	return true
-}
+			if probe.FailureThreshold <= 0 || probeState.failures < probe.FailureThreshold {
+				launcher.scheduleReadinessProbe(instanceIdent, probeState, probe)
 
-func (launcher *Launcher) allocateDevices(node *nodeStatus, serviceDevices []aostypes.ServiceDevice) error {
-serviceDeviceLoop:
-	for _, serviceDevice := range serviceDevices {
-		for i := range node.availableDevices {
-			if node.availableDevices[i].name != serviceDevice.Name {
-				continue
+				return
 			}
 
-			if node.availableDevices[i].sharedCount != 0 {
-				if node.availableDevices[i].allocatedCount == node.availableDevices[i].sharedCount {
-					return aoserrors.Errorf("can't allocate device: %s", serviceDevice.Name)
-				}
+			log.WithFields(instanceIdentLogFields(instanceIdent, log.Fields{
+				"failures": probeState.failures,
+			})).Error("Instance readiness probe failed")
 
-				node.availableDevices[i].allocatedCount++
+			probeState.failed = true
+		} else {
+			log.WithFields(instanceIdentLogFields(instanceIdent, nil)).Debug("Instance is ready")
 
-				continue serviceDeviceLoop
-			}
+			probeState.ready = true
 		}
 
-		return aoserrors.Errorf("can't allocate device: %s", serviceDevice.Name)
-	}
+		probeState.timer = nil
 
-	return nil
-}
+		launcher.sendHeldInstances()
 
-func (launcher *Launcher) releaseDevices(node *nodeStatus, serviceDevices []aostypes.ServiceDevice) error {
-serviceDeviceLoop:
-	for _, serviceDevice := range serviceDevices {
-		for i := range node.availableDevices {
-			if node.availableDevices[i].name != serviceDevice.Name {
-				continue
+		for _, node := range launcher.nodes {
+			if node.waitStatus {
+				return
 			}
+		}
 
-			if node.availableDevices[i].sharedCount != 0 {
-				if node.availableDevices[i].allocatedCount == 0 {
-					return aoserrors.Errorf("can't release device: %s", serviceDevice.Name)
-				}
+		launcher.sendCurrentStatus()
+	})
+}
 
-				node.availableDevices[i].allocatedCount--
+func (launcher *Launcher) probeInstanceReadiness(
+	nodeID string, instanceIdent aostypes.InstanceIdent, probe aostypes.ServiceReadinessProbe,
+) (ready bool, err error) {
+	timeout := probe.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultReadinessProbeTimeout
+	}
 
-				continue serviceDeviceLoop
-			}
-		}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+	defer cancelFunc()
 
-		return aoserrors.Errorf("can't release device: %s", serviceDevice.Name)
+	if ready, err = launcher.readinessProber.CheckInstanceReadiness(ctx, nodeID, instanceIdent, probe); err != nil {
+		return false, aoserrors.Wrap(err)
 	}
 
-	return nil
+	return ready, nil
 }
 
-func (launcher *Launcher) getNodesByResources(nodes []*nodeStatus, desiredResources []string) (newNodes []*nodeStatus) {
-	if len(desiredResources) == 0 {
-		return nodes
+func (launcher *Launcher) removeInstanceProbe(instanceIdent aostypes.InstanceIdent) {
+	probeState, ok := launcher.instanceProbes[instanceIdent]
+	if !ok {
+		return
 	}
 
-nodeLoop:
-	for _, node := range nodes {
-		if len(node.availableResources) == 0 {
-			continue
-		}
+	if probeState.timer != nil {
+		probeState.timer.Stop()
+	}
 
-		for _, resource := range desiredResources {
-			if !slices.Contains(node.availableResources, resource) {
-				continue nodeLoop
-			}
-		}
+	delete(launcher.instanceProbes, instanceIdent)
+}
 
-		newNodes = append(newNodes, node)
+func (launcher *Launcher) cleanupInstanceProbes(instances []cloudprotocol.InstanceStatus) {
+	for instanceIdent := range launcher.instanceProbes {
+		if !slices.ContainsFunc(instances, func(status cloudprotocol.InstanceStatus) bool {
+			return status.InstanceIdent == instanceIdent
+		}) {
+			launcher.removeInstanceProbe(instanceIdent)
+		}
 	}
-
-	return newNodes
 }
 
-func (launcher *Launcher) getNodesByLabels(nodes []*nodeStatus, desiredLabels []string) (newNodes []*nodeStatus) {
-	if len(desiredLabels) == 0 {
-		return nodes
+func (launcher *Launcher) resetInstanceProbes() {
+	for instanceIdent := range launcher.instanceProbes {
+		launcher.removeInstanceProbe(instanceIdent)
+	}
+}
+
+// updateNodeBreaker counts new instance failures on the node and trips or resets node circuit breaker.
+func (launcher *Launcher) updateNodeBreaker(nodeID string, newStatus, prevStatus []cloudprotocol.InstanceStatus) {
+	breakerConfig := launcher.config.SMController.NodeCircuitBreaker
+
+	if breakerConfig.MaxFailures <= 0 || nodeID == "" {
+		return
+	}
+
+	breaker, ok := launcher.nodeBreakers[nodeID]
+	if !ok {
+		breaker = &nodeBreaker{state: breakerStateClosed}
+		launcher.nodeBreakers[nodeID] = breaker
+	}
+
+	var (
+		failures int
+		active   bool
+	)
+
+	for _, status := range newStatus {
+		switch status.RunState {
+		case cloudprotocol.InstanceStateFailed:
+			if !isInstanceFailed(prevStatus, status.InstanceIdent) {
+				failures++
+			}
+
+		case cloudprotocol.InstanceStateActive:
+			active = true
+		}
+	}
+
+	now := time.Now()
+
+	switch breaker.state {
+	case breakerStateOpen:
+		return
+
+	case breakerStateHalfOpen:
+		if failures > 0 {
+			launcher.tripNodeBreaker(nodeID, breaker, now)
+
+			return
+		}
+
+		if active {
+			launcher.resetNodeBreaker(nodeID, breaker)
+		}
+
+	default:
+		for i := 0; i < failures; i++ {
+			breaker.failures = append(breaker.failures, now)
+		}
+
+		// Drop failures outside of the window
+		if breakerConfig.FailureWindow.Duration > 0 {
+			for len(breaker.failures) > 0 && now.Sub(breaker.failures[0]) > breakerConfig.FailureWindow.Duration {
+				breaker.failures = breaker.failures[1:]
+			}
+		}
+
+		if len(breaker.failures) >= breakerConfig.MaxFailures {
+			launcher.tripNodeBreaker(nodeID, breaker, now)
+		}
+	}
+}
+
+func (launcher *Launcher) tripNodeBreaker(nodeID string, breaker *nodeBreaker, now time.Time) {
+	log.WithFields(log.Fields{
+		"nodeID": nodeID, "failures": len(breaker.failures),
+		"cooldown": launcher.config.SMController.NodeCircuitBreaker.Cooldown.Duration,
+	}).Warn("Node circuit breaker tripped")
+
+	breaker.state, breaker.openTime, breaker.failures = breakerStateOpen, now, nil
+
+	if launcher.config.SMController.NodeCircuitBreaker.EmitEvents {
+		launcher.sendNodeEvent(NodeEvent{EventType: NodeEventBreakerTripped, NodeID: nodeID})
+	}
+}
+
+func (launcher *Launcher) resetNodeBreaker(nodeID string, breaker *nodeBreaker) {
+	log.WithField("nodeID", nodeID).Info("Node circuit breaker reset")
+
+	breaker.state, breaker.failures = breakerStateClosed, nil
+
+	if launcher.config.SMController.NodeCircuitBreaker.EmitEvents {
+		launcher.sendNodeEvent(NodeEvent{EventType: NodeEventBreakerReset, NodeID: nodeID})
+	}
+}
+
+// validateNodeType checks node reported type against expected one. Node with unexpected type is excluded from
+// balancing as its resource profile can't be trusted.
+func (launcher *Launcher) validateNodeType(node *nodeStatus, reportedType string) {
+	expectedType, ok := launcher.config.SMController.ExpectedNodeTypes[node.NodeID]
+	if !ok {
+		return
+	}
+
+	typeMismatch := reportedType != expectedType
+
+	if typeMismatch && !node.typeMismatch {
+		log.WithFields(log.Fields{
+			"nodeID": node.NodeID, "reportedType": reportedType, "expectedType": expectedType,
+		}).Error("Node reports unexpected node type")
+
+		launcher.sendNodeEvent(NodeEvent{EventType: NodeEventTypeMismatch, NodeID: node.NodeID})
+	}
+
+	if !typeMismatch && node.typeMismatch {
+		log.WithFields(log.Fields{"nodeID": node.NodeID, "nodeType": reportedType}).Info("Node type is valid")
+	}
+
+	node.typeMismatch = typeMismatch
+}
+
+// isNodeBreakerOpen checks if node is excluded from balancing. After cooldown breaker becomes half-open and node is
+// allowed to host instances again till next failure.
+func (planner *placementPlanner) isNodeBreakerOpen(nodeID string) bool {
+	breaker, ok := planner.nodeBreakers[nodeID]
+	if !ok || breaker.state != breakerStateOpen {
+		return false
+	}
+
+	if time.Since(breaker.openTime) < planner.launcher.config.SMController.NodeCircuitBreaker.Cooldown.Duration {
+		return true
+	}
+
+	log.WithField("nodeID", nodeID).Debug("Node circuit breaker half-opened")
+
+	breaker.state = breakerStateHalfOpen
+
+	return false
+}
+
+func (launcher *Launcher) cleanupInstanceState(instanceIdent aostypes.InstanceIdent) {
+	if err := launcher.storageStateProvider.Cleanup(instanceIdent); err != nil {
+		log.Errorf("Can't cleanup state storage for instance: %v", err)
+	}
+}
+
+func (launcher *Launcher) updateNetworks(instances []cloudprotocol.InstanceInfo) error {
+	providers := make([]string, len(instances))
+
+	for i, instance := range instances {
+		serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
+		if err != nil {
+			return aoserrors.Wrap(err)
+		}
+
+		providers[i] = serviceInfo.ProviderID
+	}
+
+	for _, node := range launcher.nodes {
+		if err := launcher.networkManager.UpdateProviderNetwork(providers, node.NodeID); err != nil {
+			return aoserrors.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+//nolint:funlen
+func (launcher *Launcher) performNodeBalancing(instances []cloudprotocol.InstanceInfo,
+) (errStatus []cloudprotocol.InstanceStatus) {
+	for _, node := range launcher.nodes {
+		node.currentRunRequest = &runRequestInfo{}
+	}
+
+	launcher.resetDeviceAllocation()
+	launcher.resetCPUAllocation()
+	launcher.resetQuotasAllocation()
+
+	launcher.pendingLayerServices = []string{}
+	launcher.deferredServices = []string{}
+	launcher.resetPendingMoves()
+
+	launcher.cacheInstances(instances)
+	launcher.removeInstanceNetworkParameters(instances)
+
+	planner := launcher.newPlacementPlanner()
+
+	errStatus = planner.placeInstances(getBalancingInstances(instances))
+
+	// order instances by service dependencies before allocating network
+	errStatus = append(errStatus, planner.orderRunRequestInstances()...)
+
+	preparedNetworks := make(map[aostypes.InstanceIdent]string)
+
+	// first prepare network for instance which have exposed ports
+	errNetworkStatus := launcher.prepareNetworkForInstances(true, preparedNetworks)
+	errStatus = append(errStatus, errNetworkStatus...)
+
+	// then prepare network for rest of instances
+	errNetworkStatus = launcher.prepareNetworkForInstances(false, preparedNetworks)
+	errStatus = append(errStatus, errNetworkStatus...)
+
+	launcher.releaseFailedInstanceNetworks(preparedNetworks, errStatus)
+
+	launcher.schedulePendingInstancesRetry()
+
+	return errStatus
+}
+
+func (launcher *Launcher) newPlacementPlanner() *placementPlanner {
+	return &placementPlanner{
+		launcher: launcher, nodes: launcher.nodes, nodeBreakers: launcher.nodeBreakers,
+		metrics: &launcher.balancingMetrics,
+	}
+}
+
+// newPreviewPlanner creates planner on copies of nodes and node breakers: balancing allocates node resources and
+// half-opens node breakers after cooldown.
+func (launcher *Launcher) newPreviewPlanner() *placementPlanner {
+	return &placementPlanner{
+		launcher: launcher, nodes: cloneNodesForPreview(launcher.nodes),
+		nodeBreakers: cloneNodeBreakersForPreview(launcher.nodeBreakers),
+		metrics: &balancingMetrics{
+			scheduledInstances: make(map[string]uint64),
+			schedulingFailures: make(map[string]uint64),
+		},
+		preview: true,
+	}
+}
+
+// placeInstances schedules instances on nodes. In preview mode instances are only placed on nodes without instance
+// storage setup and placement filters update.
+func (planner *placementPlanner) placeInstances(
+	balancing []balancingInstances,
+) (errStatus []cloudprotocol.InstanceStatus) {
+	for _, item := range balancing {
+		errStatus = append(errStatus, planner.placeServiceInstances(item)...)
+	}
+
+	return errStatus
+}
+
+func (planner *placementPlanner) placeServiceInstances(
+	item balancingInstances,
+) (errStatus []cloudprotocol.InstanceStatus) {
+	launcher := planner.launcher
+	instance := item.InstanceInfo
+
+	log.WithFields(log.Fields{
+		"serviceID":    instance.ServiceID,
+		"subjectID":    instance.SubjectID,
+		"numInstances": len(item.indexes),
+		"priority":     instance.Priority,
+		"nodeID":       instance.NodeID,
+	}).Debug("Balance instances")
+
+	serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
+	if err != nil {
+		return []cloudprotocol.InstanceStatus{createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID, 0, 0,
+			cloudprotocol.InstanceStateFailed, withErrorCode(err, cloudprotocol.ErrorCodeServiceNotFound))}
+	}
+
+	if serviceInfo.Cached {
+		return []cloudprotocol.InstanceStatus{createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID, 0, 0,
+			cloudprotocol.InstanceStateFailed, withErrorCode(errServiceDeleted, cloudprotocol.ErrorCodeServiceDeleted))}
+	}
+
+	layers, err := launcher.getLayersForService(serviceInfo.Layers)
+	if err != nil {
+		runState, layerErr := cloudprotocol.InstanceStateFailed, withErrorCode(err, cloudprotocol.ErrorCodeLayerNotFound)
+
+		// Layer is not installed yet: keep instances pending and retry later
+		if errors.Is(err, errLayerPending) {
+			runState, layerErr = cloudprotocol.InstanceStateActivating, nil
+
+			if !planner.preview {
+				launcher.pendingLayerServices = append(launcher.pendingLayerServices, instance.ServiceID)
+			}
+		}
+
+		for _, instanceIndex := range item.indexes {
+			errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
+				instanceIndex, serviceInfo.AosVersion, runState, layerErr))
+		}
+
+		return errStatus
+	}
+
+	nodes, err := planner.getNodesForInstance(serviceInfo, instance)
+	if err != nil {
+		planner.metrics.addFailure(getBalancingFilter(err), uint64(len(item.indexes)))
+
+		for _, instanceIndex := range item.indexes {
+			errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
+				instanceIndex, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed, err))
+		}
+
+		return errStatus
+	}
+
+	for _, instanceIndex := range item.indexes {
+		if status := planner.placeInstance(instance, instanceIndex, serviceInfo, layers, nodes); status != nil {
+			errStatus = append(errStatus, *status)
+		}
+	}
+
+	return errStatus
+}
+
+// placeInstance places instance on the most suitable node. Returns instance error status if instance can't be placed.
+func (planner *placementPlanner) placeInstance(
+	instance cloudprotocol.InstanceInfo, instanceIndex uint64, serviceInfo imagemanager.ServiceInfo,
+	layers []imagemanager.LayerInfo, nodes []*nodeStatus,
+) *cloudprotocol.InstanceStatus {
+	launcher := planner.launcher
+	placementStart := time.Now()
+
+	nodeForInstance, err := planner.getNodesByDynamicResources(nodes, serviceInfo)
+	if err != nil {
+		if instance.NodeID != "" {
+			err = aoserrors.Errorf("pinned node can't host instance: %w", err)
+		}
+
+		planner.metrics.addFailure(getBalancingFilter(err), 1)
+
+		runState := cloudprotocol.InstanceStateFailed
+
+		// Instances which don't fit available capacity are deferred till capacity is changed
+		if launcher.config.SMController.DeferOverCapacity && isCapacityError(err) {
+			runState = cloudprotocol.InstanceStateDeferred
+			err = withErrorCode(aoserrors.Errorf("deferred due to capacity: %w", err),
+				cloudprotocol.ErrorCodeCapacityDeferred)
+
+			if !planner.preview && !slices.Contains(launcher.deferredServices, instance.ServiceID) {
+				launcher.deferredServices = append(launcher.deferredServices, instance.ServiceID)
+			}
+		}
+
+		return newInstanceErrorStatus(instance, instanceIndex, serviceInfo, runState, err)
+	}
+
+	instanceInfo, err := planner.getInstanceStartInfo(serviceInfo, instance, instanceIndex)
+	if err != nil {
+		return newInstanceErrorStatus(instance, instanceIndex, serviceInfo, cloudprotocol.InstanceStateFailed, err)
+	}
+
+	node := launcher.getPreferredNode(nodeForInstance, aostypes.InstanceIdent{
+		ServiceID: instance.ServiceID, SubjectID: instance.SubjectID, Instance: instanceIndex,
+	})
+	if node == nil {
+		node = launcher.getMostPriorityNode(nodeForInstance, serviceInfo, instance.SubjectID)
+	}
+
+	if err = launcher.allocateDevices(node, serviceInfo.Config.Devices); err != nil {
+		planner.metrics.addFailure(BalancingFilterDevices, 1)
+
+		return newInstanceErrorStatus(instance, instanceIndex, serviceInfo, cloudprotocol.InstanceStateFailed,
+			withErrorCode(err, cloudprotocol.ErrorCodeNoDevice))
+	}
+
+	if instanceInfo.CPUs, err = launcher.allocateExclusiveCPUs(node, serviceInfo.Config.ExclusiveCPUs); err != nil {
+		planner.metrics.addFailure(BalancingFilterCPUs, 1)
+
+		if releaseErr := launcher.releaseDevices(node, serviceInfo.Config.Devices); releaseErr != nil {
+			log.Errorf("Can't release devices: %v", releaseErr)
+		}
+
+		return newInstanceErrorStatus(instance, instanceIndex, serviceInfo, cloudprotocol.InstanceStateFailed,
+			withErrorCode(err, cloudprotocol.ErrorCodeNoResources))
+	}
+
+	launcher.allocateQuotas(node, serviceInfo.Config.Quotas)
+	launcher.allocateQuantitativeResources(node, serviceInfo.Config.QuantitativeResources)
+	launcher.addRunRequest(instanceInfo, serviceInfo, layers, node)
+
+	planner.metrics.addPlacement(node.NodeID, time.Since(placementStart))
+
+	return nil
+}
+
+func (planner *placementPlanner) getNodesByDynamicResources(
+	nodes []*nodeStatus, serviceInfo imagemanager.ServiceInfo,
+) ([]*nodeStatus, error) {
+	launcher := planner.launcher
+
+	nodes, err := launcher.getNodesByDevices(nodes, serviceInfo.Config.Devices)
+	if err != nil {
+		return nil, err
+	}
+
+	if nodes, err = launcher.getNodesByExclusiveCPUs(nodes, serviceInfo.Config.ExclusiveCPUs); err != nil {
+		return nil, err
+	}
+
+	if nodes, err = launcher.getNodesByQuotas(nodes, serviceInfo.Config.Quotas); err != nil {
+		return nil, err
+	}
+
+	return launcher.getNodesByQuantitativeResources(nodes, serviceInfo.Config.QuantitativeResources)
+}
+
+func (planner *placementPlanner) getInstanceStartInfo(service imagemanager.ServiceInfo,
+	instance cloudprotocol.InstanceInfo, index uint64,
+) (aostypes.InstanceInfo, error) {
+	if planner.preview {
+		return aostypes.InstanceInfo{InstanceIdent: aostypes.InstanceIdent{
+			ServiceID: instance.ServiceID, SubjectID: instance.SubjectID,
+			Instance: index,
+		}, Priority: instance.Priority}, nil
+	}
+
+	return planner.launcher.prepareInstanceStartInfo(service, instance, index)
+}
+
+func (launcher *Launcher) schedulePendingInstancesRetry() {
+	if launcher.retryTimer != nil {
+		launcher.retryTimer.Stop()
+		launcher.retryTimer = nil
+	}
+
+	if len(launcher.pendingLayerServices) == 0 {
+		return
+	}
+
+	log.WithField("services", launcher.pendingLayerServices).Debug("Schedule pending instances retry")
+
+	launcher.retryTimer = time.AfterFunc(pendingInstancesRetryPeriod, launcher.retryPendingInstances)
+}
+
+func (launcher *Launcher) scheduleUnitConfigRetry() {
+	if launcher.unitConfigTimer != nil {
+		return
+	}
+
+	retryPeriod := launcher.config.SMController.UnitConfigRetryPeriod.Duration
+	if retryPeriod <= 0 {
+		retryPeriod = defaultUnitConfigRetryPeriod
+	}
+
+	launcher.unitConfigTimer = time.AfterFunc(retryPeriod, launcher.retryUnitConfig)
+}
+
+func (launcher *Launcher) retryUnitConfig() {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	launcher.unitConfigTimer = nil
+
+	configReceived := false
+
+	for _, node := range launcher.nodes {
+		if !node.configPending {
+			continue
+		}
+
+		if launcher.initNodeUnitConfiguration(node, node.NodeType); !node.configPending {
+			log.WithField("nodeID", node.NodeID).Info("Node unit config received")
+
+			configReceived = true
+		}
+	}
+
+	if configReceived {
+		launcher.rebalanceDesiredInstances()
+	}
+}
+
+func (launcher *Launcher) retryPendingInstances() {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	log.Debug("Retry pending instances")
+
+	launcher.rebalanceDesiredInstances()
+}
+
+func (launcher *Launcher) isBalancingAllowed() bool {
+	minNodes := launcher.config.SMController.MinNodesToBalance
+	if minNodes <= 0 || minNodes > len(launcher.config.SMController.NodeIDs) {
+		minNodes = len(launcher.config.SMController.NodeIDs)
+	}
+
+	return len(launcher.nodes) >= minNodes
+}
+
+func (launcher *Launcher) performPendingBalancing() {
+	launcher.Lock()
+	defer launcher.Unlock()
+
+	if !launcher.pendingBalancing {
+		return
+	}
+
+	log.WithField("connectedNodes", len(launcher.nodes)).Warn("Nodes connection timeout, perform pending balancing")
+
+	launcher.balancePendingInstances()
+}
+
+func (launcher *Launcher) balancePendingInstances() {
+	launcher.pendingBalancing = false
+
+	launcher.rebalanceDesiredInstances()
+
+	if err := launcher.networkManager.RestartDNSServer(); err != nil {
+		log.Errorf("Can't restart DNS server: %v", err)
+	}
+}
+
+func (launcher *Launcher) rebalanceDesiredInstances() {
+	if launcher.paused {
+		log.Debug("Launcher paused, postpone rebalancing")
+
+		launcher.pausedRebalancing = true
+
+		return
+	}
+
+	launcher.connectionTimer.Stop()
+	launcher.connectionTimer = time.AfterFunc(
+		launcher.config.SMController.NodesConnectionTimeout.Duration, launcher.sendStatusOnTimeout)
+
+	launcher.currentErrorStatus = launcher.performNodeBalancing(launcher.currentDesiredInstances)
+
+	if err := launcher.sendRunInstances(false); err != nil {
+		log.Errorf("Can't send run instances: %v", err)
+	}
+}
+
+func (planner *placementPlanner) orderRunRequestInstances() (errStatus []cloudprotocol.InstanceStatus) {
+	launcher := planner.launcher
+
+	for _, node := range planner.nodes {
+		orderedInstances, cycledInstances := launcher.sortInstancesByDependencies(node.currentRunRequest.Instances)
+
+		for _, instance := range cycledInstances {
+			log.WithFields(instanceIdentLogFields(
+				instance.InstanceIdent, log.Fields{"node": node.NodeID})).Error("Service dependency cycle detected")
+
+			var (
+				aosVersion uint64
+				devices    []aostypes.ServiceDevice
+				quotas     aostypes.ServiceQuotas
+				quantities []aostypes.ServiceQuantitativeResource
+			)
+
+			if serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID); err == nil {
+				aosVersion, devices, quotas = serviceInfo.AosVersion, serviceInfo.Config.Devices, serviceInfo.Config.Quotas
+				quantities = serviceInfo.Config.QuantitativeResources
+			}
+
+			if err := launcher.releaseDevices(node, devices); err != nil {
+				log.Errorf("Can't release devices: %v", err)
+			}
+
+			launcher.releaseExclusiveCPUs(node, instance.CPUs)
+			launcher.releaseQuotas(node, quotas)
+			launcher.releaseQuantitativeResources(node, quantities)
+
+			errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
+				instance.Instance, aosVersion, cloudprotocol.InstanceStateFailed,
+				withErrorCode(errDependencyCycle, cloudprotocol.ErrorCodeDependencyCycle)))
+
+			launcher.removeRunRequest(instance, node)
+		}
+
+		node.currentRunRequest.Instances = orderedInstances
+	}
+
+	return errStatus
+}
+
+// sortInstancesByDependencies orders instances topologically by service dependencies. The original order is kept for
+// services without dependencies between each other. Dependencies on services which are not scheduled on the same node
+// are ignored. Instances of services involved in a dependency cycle are returned separately.
+func (launcher *Launcher) sortInstancesByDependencies(
+	instances []aostypes.InstanceInfo,
+) (ordered, cycled []aostypes.InstanceInfo) {
+	serviceIDs := make([]string, 0, len(instances))
+
+	for _, instance := range instances {
+		if !slices.Contains(serviceIDs, instance.ServiceID) {
+			serviceIDs = append(serviceIDs, instance.ServiceID)
+		}
+	}
+
+	dependencies := make(map[string][]string)
+
+	for _, serviceID := range serviceIDs {
+		serviceInfo, err := launcher.imageProvider.GetServiceInfo(serviceID)
+		if err != nil {
+			continue
+		}
+
+		for _, dependency := range serviceInfo.Config.Dependencies {
+			if dependency != serviceID && slices.Contains(serviceIDs, dependency) {
+				dependencies[serviceID] = append(dependencies[serviceID], dependency)
+			}
+		}
+	}
+
+	sortedServices := make([]string, 0, len(serviceIDs))
+
+serviceLoop:
+	for len(sortedServices) < len(serviceIDs) {
+		for _, serviceID := range serviceIDs {
+			if slices.Contains(sortedServices, serviceID) {
+				continue
+			}
+
+			if !containsAll(sortedServices, dependencies[serviceID]) {
+				continue
+			}
+
+			sortedServices = append(sortedServices, serviceID)
+
+			continue serviceLoop
+		}
+
+		break
+	}
+
+	ordered = make([]aostypes.InstanceInfo, 0, len(instances))
+
+	for _, serviceID := range sortedServices {
+		for _, instance := range instances {
+			if instance.ServiceID == serviceID {
+				ordered = append(ordered, instance)
+			}
+		}
+	}
+
+	for _, instance := range instances {
+		if !slices.Contains(sortedServices, instance.ServiceID) {
+			cycled = append(cycled, instance)
+		}
+	}
+
+	return ordered, cycled
+}
+
+// prepareNetworkForInstances prepares network for scheduled instances. Network provider of each instance for which
+// network preparation is attempted is stored in preparedNetworks to release it if the instance fails.
+func (launcher *Launcher) prepareNetworkForInstances(
+	onlyExposedPorts bool, preparedNetworks map[aostypes.InstanceIdent]string,
+) (errStatus []cloudprotocol.InstanceStatus) {
+	for _, node := range launcher.nodes {
+		for i, instance := range node.currentRunRequest.Instances {
+			serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
+			if err != nil {
+				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID, 0, 0,
+					cloudprotocol.InstanceStateFailed, withErrorCode(err, cloudprotocol.ErrorCodeServiceNotFound)))
+
+				continue
+			}
+
+			if onlyExposedPorts && len(serviceInfo.ExposedPorts) == 0 {
+				continue
+			}
+
+			preparedNetworks[instance.InstanceIdent] = serviceInfo.ProviderID
+
+			if instance.NetworkParameters, err = launcher.networkManager.PrepareInstanceNetworkParameters(
+				instance.InstanceIdent, serviceInfo.ProviderID,
+				prepareNetworkParameters(instance, serviceInfo)); err != nil {
+				errStatus = append(errStatus, createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID,
+					instance.Instance, serviceInfo.AosVersion, cloudprotocol.InstanceStateFailed,
+					withErrorCode(err, cloudprotocol.ErrorCodeNetworkFailed)))
+			}
+
+			node.currentRunRequest.Instances[i] = instance
+		}
+	}
+
+	return errStatus
+}
+
+// releaseFailedInstanceNetworks releases network parameters allocated in the current balancing pass for failed
+// instances and removes these instances from run requests to avoid leaking their network allocations.
+func (launcher *Launcher) releaseFailedInstanceNetworks(
+	preparedNetworks map[aostypes.InstanceIdent]string, errStatus []cloudprotocol.InstanceStatus,
+) {
+	for _, status := range errStatus {
+		if status.RunState != cloudprotocol.InstanceStateFailed {
+			continue
+		}
+
+		providerID, ok := preparedNetworks[status.InstanceIdent]
+		if !ok {
+			continue
+		}
+
+		log.WithFields(instanceIdentLogFields(status.InstanceIdent, nil)).Debug("Release network of failed instance")
+
+		launcher.networkManager.RemoveInstanceNetworkParameters(status.InstanceIdent, providerID)
+
+		delete(preparedNetworks, status.InstanceIdent)
+
+		for _, node := range launcher.nodes {
+			index := slices.IndexFunc(node.currentRunRequest.Instances, func(instance aostypes.InstanceInfo) bool {
+				return instance.InstanceIdent == status.InstanceIdent
+			})
+			if index < 0 {
+				continue
+			}
+
+			instance := node.currentRunRequest.Instances[index]
+
+			if serviceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID); err == nil {
+				if err := launcher.releaseDevices(node, serviceInfo.Config.Devices); err != nil {
+					log.Errorf("Can't release devices: %v", err)
+				}
+
+				launcher.releaseQuotas(node, serviceInfo.Config.Quotas)
+				launcher.releaseQuantitativeResources(node, serviceInfo.Config.QuantitativeResources)
+			}
+
+			launcher.releaseExclusiveCPUs(node, instance.CPUs)
+			launcher.removeRunRequest(instance, node)
+
+			break
+		}
+	}
+}
+
+func prepareNetworkParameters(
+	instance aostypes.InstanceInfo, serviceInfo imagemanager.ServiceInfo,
+) networkmanager.NetworkParameters {
+	var hosts []string
+	if serviceInfo.Config.Hostname != nil {
+		hosts = append(hosts, *serviceInfo.Config.Hostname)
+	}
+
+	params := networkmanager.NetworkParameters{
+		Hosts:       hosts,
+		ExposePorts: serviceInfo.ExposedPorts,
+	}
+
+	params.AllowConnections = make([]string, 0, len(serviceInfo.Config.AllowedConnections))
+
+	for key := range serviceInfo.Config.AllowedConnections {
+		params.AllowConnections = append(params.AllowConnections, key)
+	}
+
+	return params
+}
+
+func (launcher *Launcher) removeInstanceNetworkParameters(instances []cloudprotocol.InstanceInfo) {
+	networkInstances := launcher.networkManager.GetInstances()
+
+nextNetInstance:
+	for _, netInstance := range networkInstances {
+		for _, instance := range instances {
+			for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
+				instanceIdent := aostypes.InstanceIdent{
+					ServiceID: instance.ServiceID, SubjectID: instance.SubjectID,
+					Instance: instanceIndex,
+				}
+
+				if netInstance == instanceIdent {
+					continue nextNetInstance
+				}
+			}
+		}
+
+		serviceInfo, err := launcher.imageProvider.GetServiceInfo(netInstance.ServiceID)
+		if err != nil {
+			log.WithField("serviceID", netInstance.ServiceID).Errorf("Can't get service info: %v", err)
+			continue
+		}
+
+		launcher.networkManager.RemoveInstanceNetworkParameters(netInstance, serviceInfo.ProviderID)
+	}
+}
+
+func (launcher *Launcher) cacheInstances(instances []cloudprotocol.InstanceInfo) {
+	storeInstances, err := launcher.storage.GetInstances()
+	if err != nil {
+		log.Errorf("Can't get instances from storage: %v", err)
+		return
+	}
+
+nextStoreInstance:
+	for _, storeInstance := range storeInstances {
+		for _, instance := range instances {
+			for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
+				instanceIdent := aostypes.InstanceIdent{
+					ServiceID: instance.ServiceID, SubjectID: instance.SubjectID,
+					Instance: instanceIndex,
+				}
+
+				if storeInstance.InstanceIdent == instanceIdent {
+					continue nextStoreInstance
+				}
+			}
+		}
+
+		log.WithFields(instanceIdentLogFields(storeInstance.InstanceIdent, nil)).Debug("Cache instance")
+
+		if err := launcher.storage.SetInstanceCached(storeInstance.InstanceIdent, true); err != nil {
+			log.WithFields(
+				instanceIdentLogFields(storeInstance.InstanceIdent, nil)).Errorf("Can't mark instance as cached: %v", err)
+		}
+	}
+}
+
+func (launcher *Launcher) prepareInstanceStartInfo(service imagemanager.ServiceInfo,
+	instance cloudprotocol.InstanceInfo, index uint64,
+) (aostypes.InstanceInfo, error) {
+	instanceInfo := aostypes.InstanceInfo{InstanceIdent: aostypes.InstanceIdent{
+		ServiceID: instance.ServiceID, SubjectID: instance.SubjectID,
+		Instance: index,
+	}, Priority: instance.Priority}
+
+	instanceInfo.Env = mergeEnvVars(service.Config.Env, instance.Env)
+	instanceInfo.Args = service.Config.Args
+
+	// Instance args replace service default args
+	if len(instance.Args) != 0 {
+		instanceInfo.Args = instance.Args
+	}
+
+	uid, err := launcher.storage.GetInstanceUID(instanceInfo.InstanceIdent)
+	if err != nil {
+		if !errors.Is(err, ErrNotExist) {
+			return instanceInfo, aoserrors.Wrap(err)
+		}
+
+		uid, err = launcher.instanceManager.acquireUID()
+		if err != nil {
+			return instanceInfo, aoserrors.Wrap(err)
+		}
+
+		if err := launcher.storage.AddInstance(InstanceInfo{
+			InstanceIdent: instanceInfo.InstanceIdent,
+			UID:           uid,
+			Timestamp:     time.Now(),
+		}); err != nil {
+			log.Errorf("Can't store uid: %v", err)
+		}
+	}
+
+	instanceInfo.UID = uint32(uid)
+
+	launcher.cancelPendingStop(instanceInfo.InstanceIdent)
+	launcher.releaseQuarantinedState(instanceInfo.InstanceIdent)
+
+	stateStorageParams := storagestate.SetupParams{
+		InstanceIdent: instanceInfo.InstanceIdent,
+		UID:           uid, GID: int(service.GID),
+	}
+
+	if service.Config.Quotas.StateLimit != nil {
+		stateStorageParams.StateQuota = *service.Config.Quotas.StateLimit
+	}
+
+	if service.Config.Quotas.StorageLimit != nil {
+		stateStorageParams.StorageQuota = *service.Config.Quotas.StorageLimit
+	}
+
+	instanceInfo.StoragePath, instanceInfo.StatePath, err = launcher.storageStateProvider.Setup(stateStorageParams)
+	if err != nil {
+		_ = launcher.instanceManager.releaseUID(uid)
+
+		return instanceInfo, aoserrors.Wrap(err)
+	}
+
+	// make sure that instance is not cached
+	if err := launcher.storage.SetInstanceCached(instanceInfo.InstanceIdent, false); err != nil {
+		log.WithFields(instanceIdentLogFields(instanceInfo.InstanceIdent,
+			nil)).Errorf("Can't mark instance as not cached: %v", err)
+
+		return instanceInfo, aoserrors.Wrap(err)
+	}
+
+	return instanceInfo, nil
+}
+
+func (planner *placementPlanner) getNodesForInstance(
+	serviceInfo imagemanager.ServiceInfo, instanceInfo cloudprotocol.InstanceInfo,
+) ([]*nodeStatus, error) {
+	if instanceInfo.NodeID == "" {
+		return planner.getNodesByStaticResources(planner.nodes, serviceInfo, instanceInfo)
+	}
+
+	launcher := planner.launcher
+
+	// Instance pinned by cloud: labels are ignored, but node should satisfy runner and resources
+	node := planner.getNode(instanceInfo.NodeID)
+	if node == nil {
+		return nil, aoserrors.Errorf("pinned node can't host instance: node %s not found", instanceInfo.NodeID)
+	}
+
+	if node.typeMismatch {
+		return nil, newFilterError(BalancingFilterNodeType, "pinned node can't host instance: unexpected node type %s",
+			node.NodeType)
+	}
+
+	if node.configPending {
+		return nil, newFilterError(BalancingFilterConfig, "pinned node can't host instance: no unit config")
+	}
+
+	if planner.isNodeBreakerOpen(node.NodeID) {
+		return nil, newFilterError(BalancingFilterBreaker,
+			"pinned node can't host instance: node is excluded by circuit breaker")
+	}
+
+	if serviceInfo.Config.LocalOnly && node.RemoteNode {
+		return nil, newFilterError(BalancingFilterLocal, "pinned node can't host instance: node is remote")
+	}
+
+	nodes := launcher.getNodeByRunner([]*nodeStatus{node}, serviceInfo.Config.Runner)
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterRunner, "pinned node can't host instance: no runner %s",
+			serviceInfo.Config.Runner)
+	}
+
+	nodes = launcher.getNodesByResources(nodes, serviceInfo.Config.Resources)
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterResources, "pinned node can't host instance: no resources %v",
+			serviceInfo.Config.Resources)
+	}
+
+	return nodes, nil
+}
+
+func (planner *placementPlanner) getNodesByStaticResources(allNodes []*nodeStatus,
+	serviceInfo imagemanager.ServiceInfo, instanceInfo cloudprotocol.InstanceInfo,
+) ([]*nodeStatus, error) {
+	launcher := planner.launcher
+
+	nodes := launcher.getNodeByRunner(allNodes, serviceInfo.Config.Runner)
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterRunner, "no node with runner: %s", serviceInfo.Config.Runner)
+	}
+
+	if nodes = getNodesWithUnitConfig(nodes); len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterConfig, "no node with valid unit config")
+	}
+
+	if serviceInfo.Config.LocalOnly {
+		if nodes = getLocalNodes(nodes); len(nodes) == 0 {
+			return nodes, newFilterError(BalancingFilterLocal, "no local node available")
+		}
+	}
+
+	nodes = launcher.getNodesByLabels(nodes, instanceInfo.Labels)
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterLabels, "no node with labels %v", instanceInfo.Labels)
+	}
+
+	nodes = launcher.getNodesByResources(nodes, serviceInfo.Config.Resources)
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterResources, "no node with resources %v",
+			serviceInfo.Config.Resources)
+	}
+
+	nodes = planner.getNodesByBreaker(nodes)
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterBreaker, "all suitable nodes are excluded by circuit breaker")
+	}
+
+	nodes = getNodesByValidType(nodes)
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterNodeType, "all suitable nodes report unexpected node type")
+	}
+
+	return nodes, nil
+}
+
+func (launcher *Launcher) getNodesByDevices(
+	availableNodes []*nodeStatus, desiredDevices []aostypes.ServiceDevice,
+) ([]*nodeStatus, error) {
+	if len(desiredDevices) == 0 {
+		return slices.Clone(availableNodes), nil
+	}
+
+	nodes := make([]*nodeStatus, 0)
+
+	for _, node := range availableNodes {
+		if !launcher.nodeHasDesiredDevices(node, desiredDevices) {
+			continue
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterDevices, "no available device found")
+	}
+
+	return nodes, nil
+}
+
+func (launcher *Launcher) getNodeByMonitoringData(nodes []*nodeStatus, alertType string) (newNodes []*nodeStatus) {
+	if len(nodes) == 1 {
+		return nodes
+	}
+
+	type freeNodeResources struct {
+		node    *nodeStatus
+		freeRAM uint64
+		freeCPU uint64
+	}
+
+	nodesResources := []freeNodeResources{}
+
+	for _, node := range nodes {
+		monitoringData, err := launcher.nodeManager.GetNodeMonitoringData(node.NodeID)
+		if err != nil {
+			log.Errorf("Can't get node monitoring data: %v", err)
+		}
+
+		nodesResources = append(nodesResources, freeNodeResources{
+			node:    node,
+			freeRAM: node.TotalRAM - monitoringData.RAM,
+			freeCPU: node.NumCPUs*100 - monitoringData.CPU,
+		})
+	}
+
+	if alertType == "cpu" {
+		slices.SortFunc(nodesResources, func(a, b freeNodeResources) bool {
+			return a.freeCPU > b.freeCPU
+		})
+	} else {
+		slices.SortFunc(nodesResources, func(a, b freeNodeResources) bool {
+			return a.freeRAM > b.freeRAM
+		})
+	}
+
+	for _, sortedResources := range nodesResources {
+		newNodes = append(newNodes, sortedResources.node)
+	}
+
+	return newNodes
+}
+
+func (launcher *Launcher) nodeHasDesiredDevices(node *nodeStatus, desiredDevices []aostypes.ServiceDevice) bool {
+devicesLoop:
+	for _, desiredDevice := range desiredDevices {
+		for _, nodeDevice := range node.availableDevices {
+			if desiredDevice.Name != nodeDevice.name {
+				continue
+			}
+
+			if nodeDevice.sharedCount == 0 || nodeDevice.hasCapacity(getDeviceShare(desiredDevice)) {
+				continue devicesLoop
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func (launcher *Launcher) allocateDevices(node *nodeStatus, serviceDevices []aostypes.ServiceDevice) error {
+serviceDeviceLoop:
+	for _, serviceDevice := range serviceDevices {
+		for i := range node.availableDevices {
+			if node.availableDevices[i].name != serviceDevice.Name {
+				continue
+			}
+
+			if node.availableDevices[i].sharedCount != 0 {
+				share := getDeviceShare(serviceDevice)
+
+				if !node.availableDevices[i].hasCapacity(share) {
+					return aoserrors.Errorf("can't allocate device: %s", serviceDevice.Name)
+				}
+
+				node.availableDevices[i].allocated += share
+
+				continue serviceDeviceLoop
+			}
+		}
+
+		return aoserrors.Errorf("can't allocate device: %s", serviceDevice.Name)
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) releaseDevices(node *nodeStatus, serviceDevices []aostypes.ServiceDevice) error {
+serviceDeviceLoop:
+	for _, serviceDevice := range serviceDevices {
+		for i := range node.availableDevices {
+			if node.availableDevices[i].name != serviceDevice.Name {
+				continue
+			}
+
+			if node.availableDevices[i].sharedCount != 0 {
+				share := getDeviceShare(serviceDevice)
+
+				if node.availableDevices[i].allocated < share-deviceShareEpsilon {
+					return aoserrors.Errorf("can't release device: %s", serviceDevice.Name)
+				}
+
+				node.availableDevices[i].allocated -= share
+
+				if node.availableDevices[i].allocated < deviceShareEpsilon {
+					node.availableDevices[i].allocated = 0
+				}
+
+				continue serviceDeviceLoop
+			}
+		}
+
+		return aoserrors.Errorf("can't release device: %s", serviceDevice.Name)
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) getNodesByExclusiveCPUs(
+	availableNodes []*nodeStatus, desiredCPUs uint64,
+) ([]*nodeStatus, error) {
+	if desiredCPUs == 0 {
+		return availableNodes, nil
+	}
+
+	nodes := make([]*nodeStatus, 0)
+
+	for _, node := range availableNodes {
+		if getFreeCPUCount(node) < desiredCPUs {
+			continue
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterCPUs, "no node with %d free exclusive CPUs", desiredCPUs)
+	}
+
+	return nodes, nil
+}
+
+func (launcher *Launcher) allocateExclusiveCPUs(node *nodeStatus, desiredCPUs uint64) (cpus []int, err error) {
+	if desiredCPUs == 0 {
+		return nil, nil
+	}
+
+	if getFreeCPUCount(node) < desiredCPUs {
+		return nil, aoserrors.Errorf("can't allocate %d exclusive CPUs", desiredCPUs)
+	}
+
+	for i := range node.allocatedCPUs {
+		if uint64(len(cpus)) == desiredCPUs {
+			break
+		}
+
+		if !node.allocatedCPUs[i] {
+			node.allocatedCPUs[i] = true
+			cpus = append(cpus, i)
+		}
+	}
+
+	return cpus, nil
+}
+
+func (launcher *Launcher) reserveExclusiveCPUs(node *nodeStatus, cpus []int) error {
+	for _, cpu := range cpus {
+		if cpu < 0 || cpu >= len(node.allocatedCPUs) || node.allocatedCPUs[cpu] {
+			return aoserrors.Errorf("can't reserve CPU: %d", cpu)
+		}
+
+		node.allocatedCPUs[cpu] = true
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) releaseExclusiveCPUs(node *nodeStatus, cpus []int) {
+	for _, cpu := range cpus {
+		if cpu >= 0 && cpu < len(node.allocatedCPUs) {
+			node.allocatedCPUs[cpu] = false
+		}
+	}
+}
+
+func (launcher *Launcher) getNodesByQuotas(
+	availableNodes []*nodeStatus, serviceQuotas aostypes.ServiceQuotas,
+) ([]*nodeStatus, error) {
+	if serviceQuotas.RAMLimit == nil && serviceQuotas.CPULimit == nil {
+		return availableNodes, nil
+	}
+
+	nodes := make([]*nodeStatus, 0)
+
+	for _, node := range availableNodes {
+		if !node.capacityLimited {
+			nodes = append(nodes, node)
+
+			continue
+		}
+
+		// Requested quotas are checked as is: quota exceeding node capacity doesn't fit it
+		if !isQuotaFit(node.TotalRAM, node.availableRAM, node.allocatedRAM, serviceQuotas.RAMLimit) ||
+			!isQuotaFit(node.NumCPUs, node.availableCPU, node.allocatedCPU, serviceQuotas.CPULimit) {
+			continue
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterCapacity, "no node with enough free capacity")
+	}
+
+	return nodes, nil
+}
+
+func (launcher *Launcher) allocateQuotas(node *nodeStatus, serviceQuotas aostypes.ServiceQuotas) {
+	quotas := getNodeQuotas(node, serviceQuotas)
+
+	if quotas.RAMLimit != nil {
+		node.allocatedRAM += *quotas.RAMLimit
+	}
+
+	if quotas.CPULimit != nil {
+		node.allocatedCPU += *quotas.CPULimit
+	}
+}
+
+func (launcher *Launcher) releaseQuotas(node *nodeStatus, serviceQuotas aostypes.ServiceQuotas) {
+	quotas := getNodeQuotas(node, serviceQuotas)
+
+	if quotas.RAMLimit != nil {
+		node.allocatedRAM -= min(node.allocatedRAM, *quotas.RAMLimit)
+	}
+
+	if quotas.CPULimit != nil {
+		node.allocatedCPU -= min(node.allocatedCPU, *quotas.CPULimit)
+	}
+}
+
+func (launcher *Launcher) getNodesByQuantitativeResources(
+	availableNodes []*nodeStatus, desiredResources []aostypes.ServiceQuantitativeResource,
+) ([]*nodeStatus, error) {
+	if len(desiredResources) == 0 {
+		return availableNodes, nil
+	}
+
+	nodes := make([]*nodeStatus, 0)
+
+nodesLoop:
+	for _, node := range availableNodes {
+		for _, desiredResource := range desiredResources {
+			resource := node.getQuantitativeResource(desiredResource.Name)
+			if resource == nil || resource.allocated+desiredResource.Amount > resource.capacity {
+				continue nodesLoop
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nodes, newFilterError(BalancingFilterQuantities, "no node with enough quantitative resources")
+	}
+
+	return nodes, nil
+}
+
+func (launcher *Launcher) allocateQuantitativeResources(
+	node *nodeStatus, serviceResources []aostypes.ServiceQuantitativeResource,
+) {
+	for _, serviceResource := range serviceResources {
+		if resource := node.getQuantitativeResource(serviceResource.Name); resource != nil {
+			resource.allocated += serviceResource.Amount
+		}
+	}
+}
+
+func (launcher *Launcher) releaseQuantitativeResources(
+	node *nodeStatus, serviceResources []aostypes.ServiceQuantitativeResource,
+) {
+	for _, serviceResource := range serviceResources {
+		if resource := node.getQuantitativeResource(serviceResource.Name); resource != nil {
+			resource.allocated -= min(resource.allocated, serviceResource.Amount)
+		}
+	}
+}
+
+// getAppliedQuotas returns effective quotas applied to the running instance on its node: service quotas with CPU
+// and RAM limits sent with the instance run request.
+func (launcher *Launcher) getAppliedQuotas(status cloudprotocol.InstanceStatus) *aostypes.ServiceQuotas {
+	if status.RunState == cloudprotocol.InstanceStateFailed {
+		return nil
+	}
+
+	node := launcher.getNode(status.NodeID)
+	if node == nil {
+		return nil
+	}
+
+	serviceInfo, err := launcher.imageProvider.GetServiceInfo(status.ServiceID)
+	if err != nil || serviceInfo.Config.Quotas == (aostypes.ServiceQuotas{}) {
+		return nil
+	}
+
+	quotas := serviceInfo.Config.Quotas
+
+	for _, instance := range node.currentRunRequest.Instances {
+		if instance.InstanceIdent != status.InstanceIdent {
+			continue
+		}
+
+		if instance.CPULimit != nil {
+			quotas.CPULimit = instance.CPULimit
+		}
+
+		if instance.RAMLimit != nil {
+			quotas.RAMLimit = instance.RAMLimit
+		}
+	}
+
+	return &quotas
+}
+
+func (launcher *Launcher) getNodesByResources(nodes []*nodeStatus, desiredResources []string) (newNodes []*nodeStatus) {
+	if len(desiredResources) == 0 {
+		return nodes
+	}
+
+nodeLoop:
+	for _, node := range nodes {
+		if len(node.availableResources) == 0 {
+			continue
+		}
+
+		for _, resource := range desiredResources {
+			if !slices.Contains(node.availableResources, resource) {
+				continue nodeLoop
+			}
+		}
+
+		newNodes = append(newNodes, node)
+	}
+
+	return newNodes
+}
+
+func getNodesWithUnitConfig(nodes []*nodeStatus) (newNodes []*nodeStatus) {
+	for _, node := range nodes {
+		if node.configPending {
+			log.WithField("nodeID", node.NodeID).Debug("Node excluded due to pending unit config")
+
+			continue
+		}
+
+		newNodes = append(newNodes, node)
+	}
+
+	return newNodes
+}
+
+func getLocalNodes(nodes []*nodeStatus) (newNodes []*nodeStatus) {
+	for _, node := range nodes {
+		if node.RemoteNode {
+			continue
+		}
+
+		newNodes = append(newNodes, node)
+	}
+
+	return newNodes
+}
+
+func getNodesByValidType(nodes []*nodeStatus) (newNodes []*nodeStatus) {
+	for _, node := range nodes {
+		if node.typeMismatch {
+			log.WithField("nodeID", node.NodeID).Debug("Node excluded due to unexpected node type")
+
+			continue
+		}
+
+		newNodes = append(newNodes, node)
+	}
+
+	return newNodes
+}
+
+func (planner *placementPlanner) getNodesByBreaker(nodes []*nodeStatus) (newNodes []*nodeStatus) {
+	for _, node := range nodes {
+		if planner.isNodeBreakerOpen(node.NodeID) {
+			log.WithField("nodeID", node.NodeID).Debug("Node excluded by circuit breaker")
+
+			continue
+		}
+
+		newNodes = append(newNodes, node)
+	}
+
+	return newNodes
+}
+
+func (launcher *Launcher) getNodesByLabels(nodes []*nodeStatus, desiredLabels []string) (newNodes []*nodeStatus) {
+	if len(desiredLabels) == 0 {
+		return nodes
 	}
 
 nodeLoop:
@@ -1156,291 +3865,995 @@ nodeLoop:
 			continue
 		}
 
-		for _, label := range desiredLabels {
-			if !slices.Contains(node.availableLabels, label) {
-				continue nodeLoop
+		for _, label := range desiredLabels {
+			if !slices.Contains(node.availableLabels, label) {
+				continue nodeLoop
+			}
+		}
+
+		newNodes = append(newNodes, node)
+	}
+
+	return newNodes
+}
+
+func (launcher *Launcher) getNodeByRunner(allNodes []*nodeStatus, runner string) (nodes []*nodeStatus) {
+	for _, node := range allNodes {
+		// Service without runner uses default runner of the node type
+		nodeRunner := runner
+		if nodeRunner == "" {
+			nodeRunner = node.defaultRunner
+		}
+
+		if slices.Contains(node.availableRunners, nodeRunner) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// getLabelsCompliantPlacement returns current instances placement excluding instances which required labels are not
+// available on their nodes anymore.
+func (launcher *Launcher) getLabelsCompliantPlacement() map[aostypes.InstanceIdent]string {
+	placement := make(map[aostypes.InstanceIdent]string)
+
+	for _, node := range launcher.nodes {
+		for _, instance := range node.currentRunRequest.Instances {
+			labels, err := launcher.getLabelsForInstance(instance.InstanceIdent)
+			if err == nil && len(launcher.getNodesByLabels([]*nodeStatus{node}, labels)) == 0 {
+				log.WithFields(instanceIdentLogFields(instance.InstanceIdent, log.Fields{
+					"nodeID": node.NodeID, "labels": labels,
+				})).Info("Node labels mismatch, move instance")
+
+				continue
+			}
+
+			placement[instance.InstanceIdent] = node.NodeID
+		}
+	}
+
+	return placement
+}
+
+// getPreferredNode returns node the instance is kept on during subject update if this node is able to host it.
+func (launcher *Launcher) getPreferredNode(nodes []*nodeStatus, instanceIdent aostypes.InstanceIdent) *nodeStatus {
+	nodeID, ok := launcher.preferredNodes[instanceIdent]
+	if !ok {
+		return nil
+	}
+
+	for _, node := range nodes {
+		if node.NodeID == nodeID {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) getMostPriorityNode(
+	nodes []*nodeStatus, serviceInfo imagemanager.ServiceInfo, subjectID string,
+) *nodeStatus {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	// Affinity is soft preference: use all nodes if no node hosts affine service
+	if affineNodes := launcher.getNodesByAffinity(nodes, serviceInfo, subjectID); len(affineNodes) != 0 {
+		nodes = affineNodes
+	}
+
+	maxNodePriorityIndex := 0
+
+	for i := 1; i < len(nodes); i++ {
+		if nodes[maxNodePriorityIndex].priority < nodes[i].priority ||
+			(nodes[maxNodePriorityIndex].priority == nodes[i].priority &&
+				launcher.isPreferredNode(nodes[i], nodes[maxNodePriorityIndex])) {
+			maxNodePriorityIndex = i
+		}
+	}
+
+	return nodes[maxNodePriorityIndex]
+}
+
+// isPreferredNode checks if node is preferred over current node with equal priority according to configured
+// tie-breaker. Nodes are sorted by node ID, so keeping current node means lexicographic tie-break.
+func (launcher *Launcher) isPreferredNode(node, currentNode *nodeStatus) bool {
+	switch launcher.config.SMController.NodeTieBreaker {
+	case NodeTieBreakerLeastLoaded:
+		return len(node.currentRunRequest.Instances) < len(currentNode.currentRunRequest.Instances)
+
+	case NodeTieBreakerMostFreeRAM:
+		return getFreeRAM(node) > getFreeRAM(currentNode)
+
+	default:
+		return false
+	}
+}
+
+// getNodesByAffinity returns nodes hosting affine services. Affinity works in both directions: node is affine if it
+// hosts service the instance declares affinity to or service which declares affinity to the instance.
+func (launcher *Launcher) getNodesByAffinity(
+	nodes []*nodeStatus, serviceInfo imagemanager.ServiceInfo, subjectID string,
+) (affineNodes []*nodeStatus) {
+	partnerAffinities := make(map[string][]aostypes.ServiceAffinity)
+
+	for _, node := range nodes {
+		for _, instance := range node.currentRunRequest.Instances {
+			if isAffineInstance(instance.InstanceIdent, serviceInfo.Config.Affinity) {
+				affineNodes = append(affineNodes, node)
+
+				break
+			}
+
+			affinity, ok := partnerAffinities[instance.ServiceID]
+			if !ok {
+				if partnerInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID); err == nil {
+					affinity = partnerInfo.Config.Affinity
+				}
+
+				partnerAffinities[instance.ServiceID] = affinity
+			}
+
+			if isAffineInstance(aostypes.InstanceIdent{ServiceID: serviceInfo.ID, SubjectID: subjectID}, affinity) {
+				affineNodes = append(affineNodes, node)
+
+				break
+			}
+		}
+	}
+
+	return affineNodes
+}
+
+func isAffineInstance(instanceIdent aostypes.InstanceIdent, affinity []aostypes.ServiceAffinity) bool {
+	for _, rule := range affinity {
+		if instanceIdent.ServiceID == rule.ServiceID && (rule.SubjectID == "" || instanceIdent.SubjectID == rule.SubjectID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (launcher *Launcher) addRunRequest(instance aostypes.InstanceInfo, service imagemanager.ServiceInfo,
+	layers []imagemanager.LayerInfo, node *nodeStatus,
+) {
+	log.WithFields(instanceIdentLogFields(
+		instance.InstanceIdent, log.Fields{"node": node.NodeID})).Debug("Schedule instance on node")
+
+	// Service CPU and RAM quotas clamped to the node capacity are sent with the instance to override service ones
+	quotas := getNodeQuotas(node, service.Config.Quotas)
+	instance.CPULimit = getClampedQuota(service.Config.Quotas.CPULimit, quotas.CPULimit)
+	instance.RAMLimit = getClampedQuota(service.Config.Quotas.RAMLimit, quotas.RAMLimit)
+
+	node.currentRunRequest.Instances = append(node.currentRunRequest.Instances, instance)
+
+	serviceInfo := service.ServiceInfo
+
+	if node.RemoteNode {
+		serviceInfo.URL = service.RemoteURL
+	}
+
+	isNewService := true
+
+	for _, oldService := range node.currentRunRequest.Services {
+		if reflect.DeepEqual(oldService, serviceInfo) {
+			isNewService = false
+			break
+		}
+	}
+
+	if isNewService {
+		log.WithFields(log.Fields{"serviceID": serviceInfo.ID, "node": node.NodeID}).Debug("Schedule service on node")
+
+		node.currentRunRequest.Services = append(node.currentRunRequest.Services, serviceInfo)
+	}
+
+layerLoopLabel:
+	for _, layer := range layers {
+		newLayer := layer.LayerInfo
+
+		if node.RemoteNode {
+			newLayer.URL = layer.RemoteURL
+		}
+
+		for _, oldLayer := range node.currentRunRequest.Layers {
+			if reflect.DeepEqual(newLayer, oldLayer) {
+				continue layerLoopLabel
+			}
+		}
+
+		log.WithFields(log.Fields{"digest": newLayer.Digest, "node": node.NodeID}).Debug("Schedule layer on node")
+
+		node.currentRunRequest.Layers = append(node.currentRunRequest.Layers, newLayer)
+	}
+}
+
+func (launcher *Launcher) removeInstanceFromNode(ident aostypes.InstanceIdent, node *nodeStatus) {
+	log.WithFields(log.Fields{"ident": ident, "node": node.NodeID}).Debug("Remove instance from node")
+
+	i := 0
+
+	for _, instance := range node.currentRunRequest.Instances {
+		if instance.InstanceIdent != ident {
+			node.currentRunRequest.Instances[i] = instance
+			i++
+		}
+	}
+
+	node.currentRunRequest.Instances = node.currentRunRequest.Instances[:i]
+}
+
+func (launcher *Launcher) removeServiceFromNode(serviceID string, node *nodeStatus) {
+	log.WithFields(log.Fields{"serviceID": serviceID, "node": node.NodeID}).Debug("Remove service from node")
+
+	i := 0
+
+	for _, service := range node.currentRunRequest.Services {
+		if service.ID != serviceID {
+			node.currentRunRequest.Services[i] = service
+			i++
+		}
+	}
+
+	node.currentRunRequest.Services = node.currentRunRequest.Services[:i]
+}
+
+func (launcher *Launcher) removeLayerFromNode(digest string, node *nodeStatus) {
+	log.WithFields(log.Fields{"digest": digest, "node": node.NodeID}).Debug("Remove layer from node")
+
+	i := 0
+
+	for _, layer := range node.currentRunRequest.Layers {
+		if layer.Digest != digest {
+			node.currentRunRequest.Layers[i] = layer
+			i++
+		}
+	}
+
+	node.currentRunRequest.Layers = node.currentRunRequest.Layers[:i]
+}
+
+func (launcher *Launcher) removeRunRequest(instance aostypes.InstanceInfo, node *nodeStatus) {
+	launcher.removeInstanceFromNode(instance.InstanceIdent, node)
+
+	// check if we need to remove service
+	removeService := true
+
+	for _, currentInstance := range node.currentRunRequest.Instances {
+		if currentInstance.ServiceID == instance.ServiceID {
+			removeService = false
+		}
+	}
+
+	if removeService {
+		launcher.removeServiceFromNode(instance.ServiceID, node)
+
+		currentServiceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
+		if err != nil {
+			log.Errorf("Can't get service info: %v", err)
+
+			return
+		}
+
+	layerLoop:
+		for _, currentDigest := range currentServiceInfo.Layers {
+			for _, service := range node.currentRunRequest.Services {
+				serviceInfo, err := launcher.imageProvider.GetServiceInfo(service.ID)
+				if err != nil {
+					log.Errorf("Can't get service info: %v", err)
+
+					continue layerLoop
+				}
+
+				for _, digest := range serviceInfo.Layers {
+					if currentDigest == digest {
+						continue layerLoop
+					}
+				}
+			}
+
+			launcher.removeLayerFromNode(currentDigest, node)
+		}
+	}
+}
+
+func newInstanceErrorStatus(
+	instance cloudprotocol.InstanceInfo, instanceIndex uint64, serviceInfo imagemanager.ServiceInfo, runState string,
+	err error,
+) *cloudprotocol.InstanceStatus {
+	status := createInstanceStatusFromInfo(instance.ServiceID, instance.SubjectID, instanceIndex,
+		serviceInfo.AosVersion, runState, err)
+
+	return &status
+}
+
+func createInstanceStatusFromInfo(
+	serviceID, subjectID string, instanceIndex, serviceVersion uint64, runState string, err error,
+) cloudprotocol.InstanceStatus {
+	ident := aostypes.InstanceIdent{
+		ServiceID: serviceID, SubjectID: subjectID, Instance: instanceIndex,
+	}
+
+	instanceStatus := cloudprotocol.InstanceStatus{
+		InstanceIdent: ident,
+		AosVersion:    serviceVersion, RunState: runState,
+	}
+
+	if err != nil {
+		log.WithFields(instanceIdentLogFields(ident, nil)).Errorf("Can't schedule instance: %v", err)
+
+		instanceStatus.ErrorInfo = &cloudprotocol.ErrorInfo{ErrorCode: getErrorCode(err), Message: err.Error()}
+	}
+
+	return instanceStatus
+}
+
+func (planner *placementPlanner) getNode(nodeID string) *nodeStatus {
+	for _, node := range planner.nodes {
+		if node.NodeID == nodeID {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) getNode(nodeID string) *nodeStatus {
+	for _, node := range launcher.nodes {
+		if node.NodeID == nodeID {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) getLowerPriorityNodes(node *nodeStatus) (nodes []*nodeStatus) {
+	for _, currentNode := range launcher.nodes {
+		if currentNode.priority > node.priority || currentNode.NodeID == node.NodeID {
+			continue
+		}
+
+		nodes = append(nodes, currentNode)
+	}
+
+	return nodes
+}
+
+func (launcher *Launcher) getLabelsForInstance(ident aostypes.InstanceIdent) ([]string, error) {
+	for _, instance := range launcher.currentDesiredInstances {
+		if instance.ServiceID == ident.ServiceID && instance.SubjectID == ident.SubjectID {
+			return instance.Labels, nil
+		}
+	}
+
+	return nil, aoserrors.New("no labels for instance")
+}
+
+func (launcher *Launcher) getLayersForService(digests []string) ([]imagemanager.LayerInfo, error) {
+	layers := make([]imagemanager.LayerInfo, len(digests))
+
+	for i, digest := range digests {
+		layer, err := launcher.imageProvider.GetLayerInfo(digest)
+		if err != nil {
+			if launcher.imageProvider.IsLayerInstalling(digest) {
+				return layers, aoserrors.Wrap(errLayerPending)
+			}
+
+			return layers, aoserrors.Wrap(err)
+		}
+
+		layers[i] = layer
+	}
+
+	return layers, nil
+}
+
+func (launcher *Launcher) loadNodeRunRequest(node *nodeStatus) error {
+	currentRunRequestJSON, err := launcher.storage.GetNodeState(node.NodeID)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if err = json.Unmarshal(currentRunRequestJSON, &node.currentRunRequest); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (launcher *Launcher) saveNodeRunRequest(node *nodeStatus) error {
+	runRequestJSON, err := json.Marshal(node.currentRunRequest)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	launcher.storageWriter.write("node state "+node.NodeID, func() error {
+		return aoserrors.Wrap(launcher.storage.SetNodeState(node.NodeID, runRequestJSON))
+	})
+
+	return nil
+}
+
+// getBalancingInstances splits desired instances by priority overrides and sorts them by priority.
+func getBalancingInstances(instances []cloudprotocol.InstanceInfo) (balancing []balancingInstances) {
+	for _, instance := range instances {
+		overrides := make(map[uint64]uint64)
+
+		for _, override := range instance.PriorityOverrides {
+			if override.Instance >= instance.NumInstances {
+				log.WithFields(log.Fields{
+					"serviceID": instance.ServiceID, "subjectID": instance.SubjectID, "instance": override.Instance,
+				}).Warn("Skip priority override of nonexistent instance")
+
+				continue
+			}
+
+			overrides[override.Instance] = override.Priority
+		}
+
+		item := balancingInstances{InstanceInfo: instance}
+
+		for instanceIndex := uint64(0); instanceIndex < instance.NumInstances; instanceIndex++ {
+			priority, ok := overrides[instanceIndex]
+			if !ok || priority == instance.Priority {
+				item.indexes = append(item.indexes, instanceIndex)
+				continue
 			}
+
+			overridden := balancingInstances{InstanceInfo: instance, indexes: []uint64{instanceIndex}}
+			overridden.Priority = priority
+
+			balancing = append(balancing, overridden)
 		}
 
-		newNodes = append(newNodes, node)
+		if len(item.indexes) != 0 || instance.NumInstances == 0 {
+			balancing = append(balancing, item)
+		}
+	}
+
+	sortInstancesByPriority(balancing)
+
+	return balancing
+}
+
+func sortInstancesByPriority(instances []balancingInstances) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		if instances[i].Priority == instances[j].Priority {
+			return instances[i].ServiceID < instances[j].ServiceID
+		}
+
+		return instances[i].Priority > instances[j].Priority
+	})
+}
+
+func cloneNodesForPreview(nodes []*nodeStatus) []*nodeStatus {
+	clonedNodes := make([]*nodeStatus, 0, len(nodes))
+
+	for _, node := range nodes {
+		clonedNode := *node
+
+		clonedNode.currentRunRequest = &runRequestInfo{}
+		clonedNode.availableDevices = make([]nodeDevice, len(node.availableDevices))
+		clonedNode.availableQuantities = make([]nodeQuantitativeResource, len(node.availableQuantities))
+		clonedNode.allocatedCPUs = make([]bool, len(node.allocatedCPUs))
+		clonedNode.allocatedRAM, clonedNode.allocatedCPU = 0, 0
+
+		for i, device := range node.availableDevices {
+			clonedNode.availableDevices[i] = nodeDevice{name: device.name, sharedCount: device.sharedCount}
+		}
+
+		for i, resource := range node.availableQuantities {
+			clonedNode.availableQuantities[i] = nodeQuantitativeResource{name: resource.name, capacity: resource.capacity}
+		}
+
+		clonedNodes = append(clonedNodes, &clonedNode)
+	}
+
+	return clonedNodes
+}
+
+func cloneNodeBreakersForPreview(breakers map[string]*nodeBreaker) map[string]*nodeBreaker {
+	clonedBreakers := make(map[string]*nodeBreaker, len(breakers))
+
+	for nodeID, breaker := range breakers {
+		clonedBreaker := *breaker
+
+		clonedBreakers[nodeID] = &clonedBreaker
+	}
+
+	return clonedBreakers
+}
+
+// mergeEnvVars merges service default env vars with instance ones. Instance env var overrides default one with the
+// same name.
+func mergeEnvVars(defaultEnv, instanceEnv []string) (env []string) {
+	if len(instanceEnv) == 0 {
+		return defaultEnv
+	}
+
+	for _, defaultVar := range defaultEnv {
+		name, _, _ := strings.Cut(defaultVar, "=")
+
+		if slices.ContainsFunc(instanceEnv, func(instanceVar string) bool {
+			instanceName, _, _ := strings.Cut(instanceVar, "=")
+
+			return instanceName == name
+		}) {
+			continue
+		}
+
+		env = append(env, defaultVar)
+	}
+
+	return append(env, instanceEnv...)
+}
+
+func getAvailableRunners(reportedRunners, allowedRunners, defaultRunners []string) (runners []string) {
+	if len(reportedRunners) == 0 {
+		reportedRunners = defaultRunners
+	}
+
+	if len(reportedRunners) == 0 {
+		reportedRunners = defaultRunnerFeatures
+	}
+
+	// Node unit config may restrict runners reported by node
+	if len(allowedRunners) == 0 {
+		return slices.Clone(reportedRunners)
+	}
+
+	for _, runner := range reportedRunners {
+		if slices.Contains(allowedRunners, runner) {
+			runners = append(runners, runner)
+		}
+	}
+
+	return runners
+}
+
+func getReservedCapacity(capacity, reservePercent uint64) uint64 {
+	if reservePercent >= 100 {
+		return 0
+	}
+
+	return capacity * (100 - reservePercent) / 100
+}
+
+// isQuotaFit checks if quota fits available capacity. Node without reported capacity is considered unlimited.
+func isQuotaFit(totalCapacity, availableCapacity, allocated uint64, quota *uint64) bool {
+	if quota == nil || totalCapacity == 0 {
+		return true
+	}
+
+	return allocated+*quota <= availableCapacity
+}
+
+// getNodeQuotas returns service quotas clamped to the node capacity.
+func getNodeQuotas(node *nodeStatus, quotas aostypes.ServiceQuotas) aostypes.ServiceQuotas {
+	if node.TotalRAM != 0 {
+		quotas.RAMLimit = clampQuota(quotas.RAMLimit, node.availableRAM)
+	}
+
+	if node.NumCPUs != 0 {
+		quotas.CPULimit = clampQuota(quotas.CPULimit, node.availableCPU)
 	}
 
-	return newNodes
+	return quotas
 }
 
-func (launcher *Launcher) getNodeByRunner(allNodes []*nodeStatus, runner string) (nodes []*nodeStatus) {
-	if runner == "" {
-		runner = defaultRunner
+func clampQuota(quota *uint64, capacity uint64) *uint64 {
+	if quota == nil || *quota <= capacity {
+		return quota
 	}
 
-	for _, node := range allNodes {
-		if (len(node.RunnerFeature) == 0 && slices.Contains(defaultRunnerFeatures, runner)) ||
-			slices.Contains(node.RunnerFeature, runner) {
-			nodes = append(nodes, node)
-		}
+	return &capacity
+}
+
+// getClampedQuota returns node quota if it differs from the requested one.
+func getClampedQuota(requested, nodeQuota *uint64) *uint64 {
+	if requested == nil || nodeQuota == nil || *requested == *nodeQuota {
+		return nil
 	}
 
-	return nodes
+	return nodeQuota
 }
 
-func (launcher *Launcher) getMostPriorityNode(nodes []*nodeStatus, serviceInfo imagemanager.ServiceInfo) *nodeStatus {
-	if len(nodes) == 1 {
-		return nodes[0]
+func getFreeRAM(node *nodeStatus) uint64 {
+	if node.allocatedRAM >= node.availableRAM {
+		return 0
 	}
 
-	maxNodePriorityIndex := 0
+	return node.availableRAM - node.allocatedRAM
+}
 
-	for i := 1; i < len(nodes); i++ {
-		if nodes[maxNodePriorityIndex].priority < nodes[i].priority {
-			maxNodePriorityIndex = i
+func getFreeCPUCount(node *nodeStatus) (count uint64) {
+	for _, allocated := range node.allocatedCPUs {
+		if !allocated {
+			count++
 		}
 	}
 
-	return nodes[maxNodePriorityIndex]
+	return count
 }
 
-func (launcher *Launcher) addRunRequest(instance aostypes.InstanceInfo, service imagemanager.ServiceInfo,
-	layers []imagemanager.LayerInfo, node *nodeStatus,
-) {
-	log.WithFields(instanceIdentLogFields(
-		instance.InstanceIdent, log.Fields{"node": node.NodeID})).Debug("Schedule instance on node")
-
-	node.currentRunRequest.Instances = append(node.currentRunRequest.Instances, instance)
-
-	serviceInfo := service.ServiceInfo
-
-	if node.RemoteNode {
-		serviceInfo.URL = service.RemoteURL
+// getDeviceShare returns device share requested by service. Full device slot is requested by default.
+func getDeviceShare(serviceDevice aostypes.ServiceDevice) float64 {
+	if serviceDevice.Share <= 0 {
+		return 1
 	}
 
-	isNewService := true
+	return serviceDevice.Share
+}
 
-	for _, oldService := range node.currentRunRequest.Services {
-		if reflect.DeepEqual(oldService, serviceInfo) {
-			isNewService = false
-			break
+func (node *nodeStatus) getQuantitativeResource(name string) *nodeQuantitativeResource {
+	for i := range node.availableQuantities {
+		if node.availableQuantities[i].name == name {
+			return &node.availableQuantities[i]
 		}
 	}
 
-	if isNewService {
-		log.WithFields(log.Fields{"serviceID": serviceInfo.ID, "node": node.NodeID}).Debug("Schedule service on node")
+	return nil
+}
 
-		node.currentRunRequest.Services = append(node.currentRunRequest.Services, serviceInfo)
+func (device *nodeDevice) hasCapacity(share float64) bool {
+	return device.allocated+share <= float64(device.sharedCount)+deviceShareEpsilon
+}
+
+func isInstanceRunning(instances []cloudprotocol.InstanceStatus, instanceIdent aostypes.InstanceIdent) bool {
+	for _, instance := range instances {
+		if instance.InstanceIdent == instanceIdent && instance.RunState != cloudprotocol.InstanceStateFailed {
+			return true
+		}
 	}
 
-layerLoopLabel:
-	for _, layer := range layers {
-		newLayer := layer.LayerInfo
+	return false
+}
 
-		if node.RemoteNode {
-			newLayer.URL = layer.RemoteURL
+func isInstanceFailed(instances []cloudprotocol.InstanceStatus, instanceIdent aostypes.InstanceIdent) bool {
+	for _, instance := range instances {
+		if instance.InstanceIdent == instanceIdent && instance.RunState == cloudprotocol.InstanceStateFailed {
+			return true
 		}
+	}
 
-		for _, oldLayer := range node.currentRunRequest.Layers {
-			if reflect.DeepEqual(newLayer, oldLayer) {
-				continue layerLoopLabel
-			}
+	return false
+}
+
+func containsInstance(instances []aostypes.InstanceInfo, instanceIdent aostypes.InstanceIdent) bool {
+	for _, instance := range instances {
+		if instance.InstanceIdent == instanceIdent {
+			return true
 		}
+	}
 
-		log.WithFields(log.Fields{"digest": newLayer.Digest, "node": node.NodeID}).Debug("Schedule layer on node")
+	return false
+}
 
-		node.currentRunRequest.Layers = append(node.currentRunRequest.Layers, newLayer)
+func hasMissingInstances(instances []aostypes.InstanceInfo, statuses []cloudprotocol.InstanceStatus) bool {
+	for _, instance := range instances {
+		if !slices.ContainsFunc(statuses, func(status cloudprotocol.InstanceStatus) bool {
+			return status.InstanceIdent == instance.InstanceIdent
+		}) {
+			return true
+		}
 	}
+
+	return false
 }
 
-func (launcher *Launcher) removeInstanceFromNode(ident aostypes.InstanceIdent, node *nodeStatus) {
-	log.WithFields(log.Fields{"ident": ident, "node": node.NodeID}).Debug("Remove instance from node")
+func isRestartRequired(policy, runState string) bool {
+	switch policy {
+	case aostypes.RestartPolicyOnFailure:
+		return runState == cloudprotocol.InstanceStateFailed
 
-	i := 0
+	case aostypes.RestartPolicyAlways:
+		return runState == cloudprotocol.InstanceStateFailed || runState == cloudprotocol.InstanceStateInactive
 
-	for _, instance := range node.currentRunRequest.Instances {
-		if instance.InstanceIdent != ident {
-			node.currentRunRequest.Instances[i] = instance
-			i++
-		}
+	default:
+		return false
 	}
-
-	node.currentRunRequest.Instances = node.currentRunRequest.Instances[:i]
 }
 
-func (launcher *Launcher) removeServiceFromNode(serviceID string, node *nodeStatus) {
-	log.WithFields(log.Fields{"serviceID": serviceID, "node": node.NodeID}).Debug("Remove service from node")
+func getRestartBackoff(backoff time.Duration, retries uint64) time.Duration {
+	if backoff <= 0 {
+		backoff = defaultRestartBackoff
+	}
 
-	i := 0
+	// Backoff is doubled on each retry
+	for i := uint64(0); i < retries && backoff < maxRestartBackoff; i++ {
+		backoff *= 2
+	}
 
-	for _, service := range node.currentRunRequest.Services {
-		if service.ID != serviceID {
-			node.currentRunRequest.Services[i] = service
-			i++
+	return min(backoff, maxRestartBackoff)
+}
+
+func containsAll(items, values []string) bool {
+	for _, value := range values {
+		if !slices.Contains(items, value) {
+			return false
 		}
 	}
 
-	node.currentRunRequest.Services = node.currentRunRequest.Services[:i]
+	return true
 }
 
-func (launcher *Launcher) removeLayerFromNode(digest string, node *nodeStatus) {
-	log.WithFields(log.Fields{"digest": digest, "node": node.NodeID}).Debug("Remove layer from node")
+func newFilterError(filter string, format string, args ...interface{}) error {
+	return aoserrors.Wrap(&filterError{filter: filter, message: fmt.Sprintf(format, args...)})
+}
 
-	i := 0
+func (err *filterError) Error() string {
+	return err.message
+}
 
-	for _, layer := range node.currentRunRequest.Layers {
-		if layer.Digest != digest {
-			node.currentRunRequest.Layers[i] = layer
-			i++
-		}
-	}
+func withErrorCode(err error, code string) error {
+	return &codedError{code: code, err: err}
+}
 
-	node.currentRunRequest.Layers = node.currentRunRequest.Layers[:i]
+func (err *codedError) Error() string {
+	return err.err.Error()
 }
 
-func (launcher *Launcher) removeRunRequest(instance aostypes.InstanceInfo, node *nodeStatus) {
-	launcher.removeInstanceFromNode(instance.InstanceIdent, node)
+func (err *codedError) Unwrap() error {
+	return err.err
+}
 
-	// check if we need to remove service
-	removeService := true
+func getErrorCode(err error) string {
+	var (
+		codedErr  *codedError
+		filterErr *filterError
+	)
 
-	for _, currentInstance := range node.currentRunRequest.Instances {
-		if currentInstance.ServiceID == instance.ServiceID {
-			removeService = false
-		}
+	if errors.As(err, &codedErr) {
+		return codedErr.code
 	}
 
-	if removeService {
-		launcher.removeServiceFromNode(instance.ServiceID, node)
+	if errors.As(err, &filterErr) {
+		switch filterErr.filter {
+		case BalancingFilterRunner, BalancingFilterLocal, BalancingFilterLabels, BalancingFilterResources,
+			BalancingFilterBreaker, BalancingFilterNodeType, BalancingFilterConfig:
+			return cloudprotocol.ErrorCodeNoNode
 
-		currentServiceInfo, err := launcher.imageProvider.GetServiceInfo(instance.ServiceID)
-		if err != nil {
-			log.Errorf("Can't get service info: %v", err)
+		case BalancingFilterDevices:
+			return cloudprotocol.ErrorCodeNoDevice
 
-			return
+		case BalancingFilterCPUs, BalancingFilterCapacity, BalancingFilterQuantities:
+			return cloudprotocol.ErrorCodeNoResources
 		}
+	}
 
-	layerLoop:
-		for _, currentDigest := range currentServiceInfo.Layers {
-			for _, service := range node.currentRunRequest.Services {
-				serviceInfo, err := launcher.imageProvider.GetServiceInfo(service.ID)
-				if err != nil {
-					log.Errorf("Can't get service info: %v", err)
+	return cloudprotocol.ErrorCodeFailed
+}
 
-					continue layerLoop
-				}
+func isCapacityError(err error) bool {
+	switch getBalancingFilter(err) {
+	case BalancingFilterCapacity, BalancingFilterCPUs, BalancingFilterQuantities:
+		return true
 
-				for _, digest := range serviceInfo.Layers {
-					if currentDigest == digest {
-						continue layerLoop
-					}
-				}
-			}
+	default:
+		return false
+	}
+}
 
-			launcher.removeLayerFromNode(currentDigest, node)
-		}
+func getBalancingFilter(err error) string {
+	var filterErr *filterError
+
+	if errors.As(err, &filterErr) {
+		return filterErr.filter
 	}
+
+	return BalancingFilterOther
 }
 
-func createInstanceStatusFromInfo(
-	serviceID, subjectID string, instanceIndex, serviceVersion uint64, runState, errorMsg string,
-) cloudprotocol.InstanceStatus {
-	ident := aostypes.InstanceIdent{
-		ServiceID: serviceID, SubjectID: subjectID, Instance: instanceIndex,
+func (metrics *balancingMetrics) addFailure(filter string, count uint64) {
+	metrics.schedulingFailures[filter] += count
+}
+
+func (metrics *balancingMetrics) addPlacement(nodeID string, placementTime time.Duration) {
+	metrics.scheduledInstances[nodeID]++
+	metrics.placementTime += placementTime
+	metrics.placementCount++
+}
+
+func instanceIdentLogFields(instance aostypes.InstanceIdent, extraFields log.Fields) log.Fields {
+	logFields := log.Fields{
+		"serviceID": instance.ServiceID,
+		"subjectID": instance.SubjectID,
+		"instance":  instance.Instance,
 	}
 
-	instanceStatus := cloudprotocol.InstanceStatus{
-		InstanceIdent: ident,
-		AosVersion:    serviceVersion, RunState: runState,
+	for k, v := range extraFields {
+		logFields[k] = v
 	}
 
-	if errorMsg != "" {
-		log.WithFields(instanceIdentLogFields(ident, nil)).Errorf("Can't schedule instance: %s", errorMsg)
+	return logFields
+}
 
-		instanceStatus.ErrorInfo = &cloudprotocol.ErrorInfo{Message: errorMsg}
+func newStorageWriter(config *config.Config) (writer *storageWriter) {
+	writer = &storageWriter{
+		maxTry:  config.SMController.StorageWriteMaxTry,
+		delay:   config.SMController.StorageWriteRetryDelay.Duration,
+		pending: make(map[string]func() error),
+		errors:  make(map[string]error),
+		wakeup:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
 	}
 
-	return instanceStatus
-}
+	if writer.maxTry <= 0 {
+		writer.maxTry = defaultStorageWriteMaxTry
+	}
 
-func (launcher *Launcher) getNode(nodeID string) *nodeStatus {
-	for _, node := range launcher.nodes {
-		if node.NodeID == nodeID {
-			return node
-		}
+	if writer.delay <= 0 {
+		writer.delay = defaultStorageWriteRetryDelay
 	}
 
-	return nil
+	return writer
 }
 
-func (launcher *Launcher) getLowerPriorityNodes(node *nodeStatus) (nodes []*nodeStatus) {
-	for _, currentNode := range launcher.nodes {
-		if currentNode.priority > node.priority || currentNode.NodeID == node.NodeID {
-			continue
-		}
+// write schedules storage write. Not yet written data of the same key is replaced.
+func (writer *storageWriter) write(name string, writeFunc func() error) {
+	writer.Lock()
+	writer.pending[name] = writeFunc
+	writer.Unlock()
 
-		nodes = append(nodes, currentNode)
-	}
+	select {
+	case writer.wakeup <- struct{}{}:
 
-	return nodes
+	default:
+	}
 }
 
-func (launcher *Launcher) getLabelsForInstance(ident aostypes.InstanceIdent) ([]string, error) {
-	for _, instance := range launcher.currentDesiredInstances {
-		if instance.ServiceID == ident.ServiceID && instance.SubjectID == ident.SubjectID {
-			return instance.Labels, nil
+// run writes scheduled data with retries till context is canceled. Pending data is written once on exit.
+func (writer *storageWriter) run(ctx context.Context) {
+	defer close(writer.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			writer.writePending(context.Background(), 1)
+
+			return
+
+		case <-writer.wakeup:
+			writer.writePending(ctx, writer.maxTry)
 		}
 	}
-
-	return nil, aoserrors.New("no labels for instance")
 }
 
-func (launcher *Launcher) getLayersForService(digests []string) ([]imagemanager.LayerInfo, error) {
-	layers := make([]imagemanager.LayerInfo, len(digests))
+func (writer *storageWriter) writePending(ctx context.Context, maxTry int) {
+	writer.Lock()
+	pending := writer.pending
+	writer.pending = make(map[string]func() error)
+	writer.Unlock()
+
+	for name, writeFunc := range pending {
+		err := retryhelper.Retry(ctx, writeFunc,
+			func(retryCount int, delay time.Duration, err error) {
+				log.WithField("data", name).Warnf("Can't write storage: %v, retry in %s", err, delay)
+			}, maxTry, writer.delay, maxStorageWriteRetryDelay)
+
+		writer.Lock()
+
+		// Write interrupted by close is repeated on exit unless newer data is scheduled
+		if err != nil && ctx.Err() != nil {
+			if _, ok := writer.pending[name]; !ok {
+				writer.pending[name] = writeFunc
+			}
+
+			writer.Unlock()
+
+			continue
+		}
 
-	for i, digest := range digests {
-		layer, err := launcher.imageProvider.GetLayerInfo(digest)
 		if err != nil {
-			return layers, aoserrors.Wrap(err)
+			log.WithField("data", name).Errorf("Can't write storage: %v", err)
+
+			writer.errors[name] = aoserrors.Wrap(err)
+		} else {
+			delete(writer.errors, name)
 		}
 
-		layers[i] = layer
+		writer.Unlock()
 	}
+}
 
-	return layers, nil
+// wait waits till run is finished.
+func (writer *storageWriter) wait() {
+	<-writer.done
 }
 
-func (launcher *Launcher) loadNodeRunRequest(node *nodeStatus) error {
-	currentRunRequestJSON, err := launcher.storage.GetNodeState(node.NodeID)
-	if err != nil {
-		return aoserrors.Wrap(err)
+// getError returns error of failed storage writes.
+func (writer *storageWriter) getError() error {
+	writer.Lock()
+	defer writer.Unlock()
+
+	if len(writer.errors) == 0 {
+		return nil
 	}
 
-	if err = json.Unmarshal(currentRunRequestJSON, &node.currentRunRequest); err != nil {
-		return aoserrors.Wrap(err)
+	names := make([]string, 0, len(writer.errors))
+
+	for name := range writer.errors {
+		names = append(names, name)
 	}
 
-	return nil
+	sort.Strings(names)
+
+	return aoserrors.Errorf("can't write %s: %v", strings.Join(names, ", "), writer.errors[names[0]])
 }
 
-func (launcher *Launcher) saveNodeRunRequest(node *nodeStatus) error {
-	runRequestJSON, err := json.Marshal(node.currentRunRequest)
-	if err != nil {
-		return aoserrors.Wrap(err)
+func newStatusSender(
+	statusChannel chan<- unitstatushandler.RunInstancesStatus,
+	isStateCorrupted func(instanceIdent aostypes.InstanceIdent) bool,
+) (sender *statusSender) {
+	return &statusSender{
+		statusChannel:    statusChannel,
+		isStateCorrupted: isStateCorrupted,
+		wakeup:           make(chan struct{}, 1),
+		done:             make(chan struct{}),
 	}
+}
 
-	if err := launcher.storage.SetNodeState(node.NodeID, runRequestJSON); err != nil {
-		log.Errorf("Can't store desired instances: %v", err)
-	}
+// send schedules run status sending. State integrity of instances at checkStateIndexes is checked before sending.
+func (sender *statusSender) send(runStatus unitstatushandler.RunInstancesStatus, checkStateIndexes []int) {
+	// Instances are cloned as state integrity result is set in background
+	runStatus.Instances = slices.Clone(runStatus.Instances)
 
-	return nil
+	sender.Lock()
+	sender.pending = append(sender.pending, pendingRunStatus{runStatus: runStatus, checkStateIndexes: checkStateIndexes})
+	sender.Unlock()
+
+	select {
+	case sender.wakeup <- struct{}{}:
+
+	default:
+	}
 }
 
-func instanceIdentLogFields(instance aostypes.InstanceIdent, extraFields log.Fields) log.Fields {
-	logFields := log.Fields{
-		"serviceID": instance.ServiceID,
-		"subjectID": instance.SubjectID,
-		"instance":  instance.Instance,
+// run sends scheduled run statuses in order till context is canceled.
+func (sender *statusSender) run(ctx context.Context) {
+	defer close(sender.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-sender.wakeup:
+			if !sender.sendPending(ctx) {
+				return
+			}
+		}
 	}
+}
 
-	for k, v := range extraFields {
-		logFields[k] = v
+func (sender *statusSender) sendPending(ctx context.Context) bool {
+	sender.Lock()
+	pending := sender.pending
+	sender.pending = nil
+	sender.Unlock()
+
+	for _, item := range pending {
+		for _, i := range item.checkStateIndexes {
+			item.runStatus.Instances[i].StateCorrupted = sender.isStateCorrupted(
+				item.runStatus.Instances[i].InstanceIdent)
+		}
+
+		select {
+		case sender.statusChannel <- item.runStatus:
+
+		case <-ctx.Done():
+			return false
+		}
 	}
 
-	return logFields
+	return true
+}
+
+// wait waits till run is finished.
+func (sender *statusSender) wait() {
+	<-sender.done
 }
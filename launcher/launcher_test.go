@@ -18,6 +18,7 @@
 package launcher_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net"
@@ -25,6 +26,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/aosedge/aos_common/api/cloudprotocol"
 	"github.com/apparentlymart/go-cidr/cidr"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
 
 	"github.com/aosedge/aos_communicationmanager/config"
 	"github.com/aosedge/aos_communicationmanager/imagemanager"
@@ -40,6 +43,7 @@ import (
 	"github.com/aosedge/aos_communicationmanager/networkmanager"
 	"github.com/aosedge/aos_communicationmanager/storagestate"
 	"github.com/aosedge/aos_communicationmanager/unitstatushandler"
+	"github.com/aosedge/aos_communicationmanager/utils/uidgidpool"
 )
 
 /***********************************************************************************************************************
@@ -64,6 +68,7 @@ const (
 
 const (
 	subject1          = "subject1"
+	subject2          = "subject2"
 	service1          = "service1"
 	service1LocalURL  = "service1LocalURL"
 	service1RemoteURL = "service1RemoteURL"
@@ -93,39 +98,62 @@ type runRequest struct {
 }
 
 type testNodeManager struct {
-	runStatusChan   chan launcher.NodeRunInstanceStatus
-	alertsChannel   chan cloudprotocol.SystemQuotaAlert
-	nodeInformation map[string]launcher.NodeInfo
-	runRequest      map[string]runRequest
+	sync.Mutex
+	runStatusChan    chan launcher.NodeRunInstanceStatus
+	heldStatusChan   chan launcher.NodeRunInstanceStatus
+	alertsChannel    chan cloudprotocol.SystemQuotaAlert
+	nodeInformation  map[string]launcher.NodeInfo
+	runRequest       map[string]runRequest
+	runRequestCount  int
+	holdStatusNodeID string
+}
+
+type testProbeNodeManager struct {
+	*testNodeManager
+	sync.Mutex
+	ready bool
 }
 
 type testImageProvider struct {
 	services                      map[string]imagemanager.ServiceInfo
 	layers                        map[string]imagemanager.LayerInfo
 	revertedServices              []string
+	installingLayers              []string
 	removeServiceInstancesChannel chan string
 }
 
 type testResourceManager struct {
+	sync.Mutex
 	nodeResources map[string]aostypes.NodeUnitConfig
+	unavailable   bool
 }
 
 type testStorage struct {
-	instanceInfo     []launcher.InstanceInfo
-	desiredInstances json.RawMessage
-	nodeState        map[string]json.RawMessage
-	services         map[string][]imagemanager.ServiceInfo
+	sync.Mutex
+	instanceInfo             []launcher.InstanceInfo
+	desiredInstances         json.RawMessage
+	desiredInstancesFailures int
+	nodeState                map[string]json.RawMessage
+	services                 map[string][]imagemanager.ServiceInfo
 }
 
 type testStateStorage struct {
-	cleanedInstances []aostypes.InstanceIdent
-	removedInstances []aostypes.InstanceIdent
+	sync.Mutex
+	cleanedInstances   []aostypes.InstanceIdent
+	removedInstances   []aostypes.InstanceIdent
+	corruptedInstances []aostypes.InstanceIdent
 }
 
 type testNetworkManager struct {
 	currentIP   net.IP
 	subnet      net.IPNet
 	networkInfo map[string]map[aostypes.InstanceIdent]struct{}
+	failPrepare map[aostypes.InstanceIdent]int // number of successful preparations before failure
+}
+
+type testCodedError struct {
+	code    string
+	message string
 }
 
 /***********************************************************************************************************************
@@ -308,14 +336,16 @@ func TestInstancesAreRemovedViaChannel(t *testing.T) {
 			continue
 		}
 
-		if removedLen := len(testStateStorage.removedInstances); removedLen != 1 {
+		removedInstances := testStateStorage.getRemovedInstances()
+
+		if removedLen := len(removedInstances); removedLen != 1 {
 			t.Logf("Expected exactly 1 instance to be removed, but got %v", removedLen)
 
 			continue
 		}
 
-		if testStateStorage.removedInstances[0].ServiceID != service1 {
-			t.Logf("Unexpected service ID: %v", testStateStorage.removedInstances[0].ServiceID)
+		if removedInstances[0].ServiceID != service1 {
+			t.Logf("Unexpected service ID: %v", removedInstances[0].ServiceID)
 
 			continue
 		}
@@ -342,6 +372,17 @@ func TestInitialStatus(t *testing.T) {
 		imageManager      = &testImageProvider{}
 	)
 
+	for _, id := range cfg.SMController.NodeIDs {
+		nodeManager.nodeInformation[id] = launcher.NodeInfo{NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: id, NodeType: "nodeType", SystemInfo: cloudprotocol.SystemInfo{
+				NumCPUs: 1, TotalRAM: 100,
+				Partitions: []cloudprotocol.PartitionInfo{
+					{Name: "id", TotalSize: 200},
+				},
+			},
+		}}
+	}
+
 	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, &testResourceManager{},
 		&testStateStorage{}, newTestNetworkManager(""))
 	if err != nil {
@@ -356,18 +397,7 @@ func TestInitialStatus(t *testing.T) {
 			NodeID: id,
 		}}
 
-		nodeInfo := cloudprotocol.NodeInfo{
-			NodeID: id, NodeType: "nodeType", SystemInfo: cloudprotocol.SystemInfo{
-				NumCPUs: 1, TotalRAM: 100,
-				Partitions: []cloudprotocol.PartitionInfo{
-					{Name: "id", TotalSize: 200},
-				},
-			},
-		}
-
-		nodeManager.nodeInformation[id] = launcher.NodeInfo{NodeInfo: nodeInfo}
-
-		expectedNodeInfo = append(expectedNodeInfo, nodeInfo)
+		expectedNodeInfo = append(expectedNodeInfo, nodeManager.nodeInformation[id].NodeInfo)
 		expectedRunStatus.Instances = append(expectedRunStatus.Instances, instances...)
 
 		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{NodeID: id, Instances: instances}
@@ -384,6 +414,56 @@ func TestInitialStatus(t *testing.T) {
 	}
 }
 
+func TestWaitForAllNodes(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{"localSM", "remoteSM1", "remoteSM2"},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager  = newTestNodeManager()
+		imageManager = &testImageProvider{}
+	)
+
+	for _, id := range cfg.SMController.NodeIDs {
+		nodeManager.nodeInformation[id] = launcher.NodeInfo{
+			NodeInfo: cloudprotocol.NodeInfo{NodeID: id, NodeType: "nodeType"},
+		}
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, newTestResourceManager(),
+		&testStateStorage{}, newTestNetworkManager(""))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for i, id := range cfg.SMController.NodeIDs {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: id, NodeType: "nodeType", Instances: []cloudprotocol.InstanceStatus{},
+		}
+
+		ctx, cancelFunc := context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+		err := launcherInstance.WaitForAllNodes(ctx)
+
+		cancelFunc()
+
+		if i < len(cfg.SMController.NodeIDs)-1 {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Wait for all nodes should fail with timeout: %v", err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Wait for all nodes error: %v", err)
+		}
+	}
+}
+
 func TestBalancing(t *testing.T) {
 	var (
 		cfg = &config.Config{
@@ -614,10 +694,10 @@ func TestBalancing(t *testing.T) {
 					}, nodeIDLocalSM, nil),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service3, SubjectID: subject1, Instance: 0,
-					}, "", errors.New("no node with labels [label1]")), //nolint:goerr113
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoNode, "no node with labels [label1]")),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service3, SubjectID: subject1, Instance: 1,
-					}, "", errors.New("no node with labels [label1]")), //nolint:goerr113
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoNode, "no node with labels [label1]")),
 				},
 			},
 		},
@@ -702,10 +782,10 @@ func TestBalancing(t *testing.T) {
 					}, nodeIDLocalSM, nil),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service3, SubjectID: subject1, Instance: 0,
-					}, "", errors.New("no node with resources [resource3]")), //nolint:goerr113
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoNode, "no node with resources [resource3]")),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service3, SubjectID: subject1, Instance: 1,
-					}, "", errors.New("no node with resources [resource3]")), //nolint:goerr113
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoNode, "no node with resources [resource3]")),
 				},
 			},
 		},
@@ -807,7 +887,7 @@ func TestBalancing(t *testing.T) {
 					}, nodeIDRemoteSM2, nil),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service1, SubjectID: subject1, Instance: 3,
-					}, "", errors.New("no available device found")), //nolint:goerr113
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoDevice, "no available device found")),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service2, SubjectID: subject1, Instance: 0,
 					}, nodeIDLocalSM, nil),
@@ -819,10 +899,152 @@ func TestBalancing(t *testing.T) {
 					}, nodeIDRemoteSM1, nil),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service3, SubjectID: subject1, Instance: 0,
-					}, "", errors.New("no available device found")), //nolint:goerr113
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoDevice, "no available device found")),
 					createInstanceStatus(aostypes.InstanceIdent{
 						ServiceID: service3, SubjectID: subject1, Instance: 1,
-					}, "", errors.New("no available device found")), //nolint:goerr113
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoDevice, "no available device found")),
+				},
+			},
+		},
+		// Check weighted device sharing: two half shares fit single device slot, third one is rejected
+		{
+			nodeResources: map[string]aostypes.NodeUnitConfig{
+				nodeTypeLocalSM: {NodeType: nodeTypeLocalSM, Priority: 100, Devices: []aostypes.DeviceInfo{
+					{Name: "dev1", SharedCount: 1},
+				}},
+				nodeTypeRemoteSM: {NodeType: nodeTypeRemoteSM, Priority: 50},
+				nodeTypeRunxSM:   {NodeType: nodeTypeRunxSM, Priority: 0},
+			},
+			serviceConfigs: map[string]aostypes.ServiceConfig{
+				service1: {Runner: runnerRunc, Devices: []aostypes.ServiceDevice{{Name: "dev1", Share: 0.5}}},
+				service2: {Runner: runnerRunc},
+				service3: {Runner: runnerRunc},
+			},
+			desiredInstances: []cloudprotocol.InstanceInfo{
+				{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 3},
+			},
+			expectedRunRequests: map[string]runRequest{
+				nodeIDLocalSM: {
+					services: []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1LocalURL)},
+					layers: []aostypes.LayerInfo{
+						createLayerInfo(layer1, layer1LocalURL),
+						createLayerInfo(layer2, layer2LocalURL),
+					},
+					instances: []aostypes.InstanceInfo{
+						createInstanceInfo(5000, 2, aostypes.InstanceIdent{
+							ServiceID: service1, SubjectID: subject1, Instance: 0,
+						}, 100),
+						createInstanceInfo(5001, 3, aostypes.InstanceIdent{
+							ServiceID: service1, SubjectID: subject1, Instance: 1,
+						}, 100),
+					},
+				},
+				nodeIDRemoteSM1: {
+					services:  []aostypes.ServiceInfo{},
+					layers:    []aostypes.LayerInfo{},
+					instances: []aostypes.InstanceInfo{},
+				},
+				nodeIDRemoteSM2: {
+					services:  []aostypes.ServiceInfo{},
+					layers:    []aostypes.LayerInfo{},
+					instances: []aostypes.InstanceInfo{},
+				},
+				nodeIDRunxSM: {
+					services:  []aostypes.ServiceInfo{},
+					layers:    []aostypes.LayerInfo{},
+					instances: []aostypes.InstanceInfo{},
+				},
+			},
+			expectedRunStatus: unitstatushandler.RunInstancesStatus{
+				Instances: []cloudprotocol.InstanceStatus{
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service1, SubjectID: subject1, Instance: 0,
+					}, nodeIDLocalSM, nil),
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service1, SubjectID: subject1, Instance: 1,
+					}, nodeIDLocalSM, nil),
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service1, SubjectID: subject1, Instance: 2,
+					}, "", newCodedError(cloudprotocol.ErrorCodeNoDevice, "no available device found")),
+				},
+			},
+		},
+		// Check pinned node: service1 instances should be started on pinned node regardless of node priority,
+		// service3 should fail as pinned node doesn't have required device
+		{
+			nodeResources: map[string]aostypes.NodeUnitConfig{
+				nodeTypeLocalSM: {NodeType: nodeTypeLocalSM, Priority: 100, Devices: []aostypes.DeviceInfo{
+					{Name: "dev3"},
+				}},
+				nodeTypeRemoteSM: {NodeType: nodeTypeRemoteSM, Priority: 50},
+				nodeTypeRunxSM:   {NodeType: nodeTypeRunxSM, Priority: 0},
+			},
+			serviceConfigs: map[string]aostypes.ServiceConfig{
+				service1: {Runner: runnerRunc},
+				service2: {Runner: runnerRunc},
+				service3: {Runner: runnerRunc, Devices: []aostypes.ServiceDevice{{Name: "dev3"}}},
+			},
+			desiredInstances: []cloudprotocol.InstanceInfo{
+				{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2, NodeID: nodeIDRemoteSM2},
+				{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 2},
+				{ServiceID: service3, SubjectID: subject1, Priority: 0, NumInstances: 1, NodeID: nodeIDRemoteSM1},
+			},
+			expectedRunRequests: map[string]runRequest{
+				nodeIDLocalSM: {
+					services: []aostypes.ServiceInfo{createServiceInfo(service2, 5001, service2LocalURL)},
+					layers:   []aostypes.LayerInfo{createLayerInfo(layer1, layer1LocalURL)},
+					instances: []aostypes.InstanceInfo{
+						createInstanceInfo(5002, 2, aostypes.InstanceIdent{
+							ServiceID: service2, SubjectID: subject1, Instance: 0,
+						}, 50),
+						createInstanceInfo(5003, 3, aostypes.InstanceIdent{
+							ServiceID: service2, SubjectID: subject1, Instance: 1,
+						}, 50),
+					},
+				},
+				nodeIDRemoteSM1: {
+					services:  []aostypes.ServiceInfo{},
+					layers:    []aostypes.LayerInfo{},
+					instances: []aostypes.InstanceInfo{},
+				},
+				nodeIDRemoteSM2: {
+					services: []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1RemoteURL)},
+					layers: []aostypes.LayerInfo{
+						createLayerInfo(layer1, layer1RemoteURL),
+						createLayerInfo(layer2, layer2RemoteURL),
+					},
+					instances: []aostypes.InstanceInfo{
+						createInstanceInfo(5000, 4, aostypes.InstanceIdent{
+							ServiceID: service1, SubjectID: subject1, Instance: 0,
+						}, 100),
+						createInstanceInfo(5001, 5, aostypes.InstanceIdent{
+							ServiceID: service1, SubjectID: subject1, Instance: 1,
+						}, 100),
+					},
+				},
+				nodeIDRunxSM: {
+					services:  []aostypes.ServiceInfo{},
+					layers:    []aostypes.LayerInfo{},
+					instances: []aostypes.InstanceInfo{},
+				},
+			},
+			expectedRunStatus: unitstatushandler.RunInstancesStatus{
+				Instances: []cloudprotocol.InstanceStatus{
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service1, SubjectID: subject1, Instance: 0,
+					}, nodeIDRemoteSM2, nil),
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service1, SubjectID: subject1, Instance: 1,
+					}, nodeIDRemoteSM2, nil),
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service2, SubjectID: subject1, Instance: 0,
+					}, nodeIDLocalSM, nil),
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service2, SubjectID: subject1, Instance: 1,
+					}, nodeIDLocalSM, nil),
+					createInstanceStatus(aostypes.InstanceIdent{
+						ServiceID: service3, SubjectID: subject1, Instance: 0,
+					}, "", errors.New("pinned node can't host instance")), //nolint:goerr113
 				},
 			},
 		},
@@ -858,7 +1080,7 @@ func TestBalancing(t *testing.T) {
 
 		// Run instances
 
-		if err := launcherInstance.RunInstances(testItem.desiredInstances, nil); err != nil {
+		if err := launcherInstance.RunInstances(testItem.desiredInstances, nil, ""); err != nil {
 			t.Fatalf("Can't run instances %v", err)
 		}
 
@@ -875,7 +1097,7 @@ func TestBalancing(t *testing.T) {
 	}
 }
 
-func TestServiceRevert(t *testing.T) {
+func TestPendingLayer(t *testing.T) {
 	var (
 		cfg = &config.Config{
 			SMController: config.SMController{
@@ -884,48 +1106,38 @@ func TestServiceRevert(t *testing.T) {
 			},
 		}
 		nodeManager     = newTestNodeManager()
-		imageManager    = &testImageProvider{}
 		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
 	)
 
 	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
 		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
-		RemoteNode: false,
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
 	}
-	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{NodeType: nodeTypeLocalSM, Priority: 100}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
 
 	imageManager.services = map[string]imagemanager.ServiceInfo{
 		service1: {
 			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
 			RemoteURL:   service1RemoteURL,
 			Layers:      []string{layer1},
-		},
-		service2: {
-			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
-			RemoteURL:   service2RemoteURL,
-			Layers:      []string{layer2},
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
 		},
 	}
 
-	imageManager.layers = map[string]imagemanager.LayerInfo{
-		layer1: {
-			LayerInfo: createLayerInfo(layer1, layer1LocalURL),
-			RemoteURL: layer1RemoteURL,
-		},
-	}
+	// Layer is not available yet as it is being installed by SOTA
+	imageManager.installingLayers = []string{layer1}
 
 	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
 		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
 	if err != nil {
 		t.Fatalf("Can't create launcher %v", err)
 	}
+	defer launcherInstance.Close()
 
-	// Wait initial run status
-
-	for nodeID, info := range nodeManager.nodeInformation {
-		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
-			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
-		}
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
 	}
 
 	if err := waitRunInstancesStatus(
@@ -933,34 +1145,19 @@ func TestServiceRevert(t *testing.T) {
 		t.Errorf("Incorrect run status: %v", err)
 	}
 
-	// Run instances
-
-	desiredInstances := []cloudprotocol.InstanceInfo{
-		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
-		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 2},
-	}
-
-	if err := launcherInstance.RunInstances(desiredInstances, []string{service2}); err != nil {
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{service1}, ""); err != nil {
 		t.Fatalf("Can't run instances %v", err)
 	}
 
 	expectedRunStatus := unitstatushandler.RunInstancesStatus{
 		Instances: []cloudprotocol.InstanceStatus{
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service1, SubjectID: subject1, Instance: 0,
-			}, nodeIDLocalSM, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service1, SubjectID: subject1, Instance: 1,
-			}, nodeIDLocalSM, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service2, SubjectID: subject1, Instance: 0,
-			}, "", errors.New("layer does't exist")), //nolint:goerr113
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service2, SubjectID: subject1, Instance: 1,
-			}, "", errors.New("layer does't exist")), //nolint:goerr113
-		},
-		ErrorServices: []cloudprotocol.ServiceStatus{
-			{ID: service2, AosVersion: 1, Status: cloudprotocol.ErrorStatus},
+			{
+				InstanceIdent: aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+				AosVersion:    1,
+				RunState:      cloudprotocol.InstanceStateActivating,
+			},
 		},
 	}
 
@@ -969,64 +1166,164 @@ func TestServiceRevert(t *testing.T) {
 		t.Errorf("Incorrect run status: %v", err)
 	}
 
-	if !reflect.DeepEqual([]string{service2}, imageManager.revertedServices) {
-		t.Errorf("Incorrect reverted services: %v", imageManager.revertedServices)
+	if len(imageManager.revertedServices) != 0 {
+		t.Errorf("Unexpected reverted services: %v", imageManager.revertedServices)
 	}
 }
 
-func TestStorageCleanup(t *testing.T) {
-	var (
-		cfg = &config.Config{
-			SMController: config.SMController{
-				NodeIDs:                []string{nodeIDLocalSM, nodeIDRunxSM},
-				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
-			},
-		}
-		nodeManager          = newTestNodeManager()
-		resourceManager      = newTestResourceManager()
-		imageManager         = &testImageProvider{}
-		stateStorageProvider = &testStateStorage{}
-	)
+func TestRemoteLayerDelivery(t *testing.T) {
+	type testData struct {
+		delivery      string
+		expectedLayer aostypes.LayerInfo
+		createError   bool
+	}
 
-	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
-		NodeInfo:      cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
-		RemoteNode:    false,
-		RunnerFeature: []string{runnerRunc},
+	testItems := []testData{
+		{delivery: "", expectedLayer: createLayerInfo(layer1, layer1RemoteURL)},
+		{delivery: launcher.LayerDeliveryPull, expectedLayer: createLayerInfo(layer1, layer1RemoteURL)},
+		{delivery: launcher.LayerDeliveryPush, createError: true},
+		{delivery: "unknown", createError: true},
 	}
-	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100}
 
-	nodeManager.nodeInformation[nodeIDRunxSM] = launcher.NodeInfo{
-		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeRunxSM},
-		RemoteNode: true, RunnerFeature: []string{runnerRunx},
+	for _, item := range testItems {
+		t.Logf("Remote layer delivery: %s", item.delivery)
+
+		var (
+			cfg = &config.Config{
+				SMController: config.SMController{
+					NodeIDs:                []string{nodeIDRemoteSM1},
+					NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+					RemoteLayerDelivery:    item.delivery,
+				},
+			}
+			nodeManager     = newTestNodeManager()
+			resourceManager = newTestResourceManager()
+			imageManager    = &testImageProvider{}
+		)
+
+		nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+			NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+			RemoteNode: true, RunnerFeature: []string{runnerRunc},
+		}
+
+		resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+			Priority: 100, NodeType: nodeTypeRemoteSM,
+		}
+
+		imageManager.services = map[string]imagemanager.ServiceInfo{
+			service1: {
+				ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+				RemoteURL:   service1RemoteURL,
+				Layers:      []string{layer1},
+				Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+			},
+		}
+
+		imageManager.layers = map[string]imagemanager.LayerInfo{
+			layer1: {
+				LayerInfo: createLayerInfo(layer1, layer1LocalURL),
+				RemoteURL: layer1RemoteURL,
+			},
+		}
+
+		launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+			&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+		if item.createError {
+			if err == nil {
+				launcherInstance.Close()
+				t.Error("Error expected on unsupported remote layer delivery")
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Can't create launcher %v", err)
+		}
+
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM, Instances: []cloudprotocol.InstanceStatus{},
+		}
+
+		if err := waitRunInstancesStatus(
+			launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+			t.Errorf("Incorrect run status: %v", err)
+		}
+
+		if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+			{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		}, []string{}, ""); err != nil {
+			t.Fatalf("Can't run instances %v", err)
+		}
+
+		if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+			Instances: []cloudprotocol.InstanceStatus{
+				createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+					nodeIDRemoteSM1, nil),
+			},
+		}, time.Second); err != nil {
+			t.Errorf("Incorrect run status: %v", err)
+		}
+
+		if err := nodeManager.compareRunRequests(map[string]runRequest{
+			nodeIDRemoteSM1: {
+				services: []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1RemoteURL)},
+				layers:   []aostypes.LayerInfo{item.expectedLayer},
+				instances: []aostypes.InstanceInfo{createInstanceInfo(5000, 2, aostypes.InstanceIdent{
+					ServiceID: service1, SubjectID: subject1, Instance: 0,
+				}, 100)},
+			},
+		}); err != nil {
+			t.Errorf("Incorrect run request: %v", err)
+		}
+
+		launcherInstance.Close()
+	}
+}
+
+func TestServiceDependencies(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
 	}
-	resourceManager.nodeResources[nodeTypeRunxSM] = aostypes.NodeUnitConfig{Priority: 0}
 
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	// service1 has higher priority but depends on service2
 	imageManager.services = map[string]imagemanager.ServiceInfo{
 		service1: {
 			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
-			RemoteURL:   service1RemoteURL, Config: aostypes.ServiceConfig{Runner: runnerRunc},
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, Dependencies: []string{service2}},
 		},
 		service2: {
 			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
-			RemoteURL:   service2RemoteURL, Config: aostypes.ServiceConfig{Runner: runnerRunc},
-		},
-		service3: {
-			ServiceInfo: createServiceInfo(service3, 5002, service3LocalURL),
-			RemoteURL:   service3RemoteURL, Config: aostypes.ServiceConfig{Runner: runnerRunx},
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
 		},
 	}
 
 	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
-		stateStorageProvider, newTestNetworkManager("172.17.0.1/16"))
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
 	if err != nil {
 		t.Fatalf("Can't create launcher %v", err)
 	}
 	defer launcherInstance.Close()
 
-	for nodeID, info := range nodeManager.nodeInformation {
-		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
-			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
-		}
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
 	}
 
 	if err := waitRunInstancesStatus(
@@ -1035,60 +1332,127 @@ func TestStorageCleanup(t *testing.T) {
 	}
 
 	desiredInstances := []cloudprotocol.InstanceInfo{
-		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+		{ServiceID: service1, SubjectID: subject1, Priority: 200, NumInstances: 1},
 		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 1},
-		{ServiceID: service3, SubjectID: subject1, Priority: 100, NumInstances: 1},
 	}
 
-	if err := launcherInstance.RunInstances(desiredInstances, nil); err != nil {
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
 		t.Fatalf("Can't run instances %v", err)
 	}
 
-	expectedRunRequests := map[string]runRequest{
-		nodeIDLocalSM: {
-			services: []aostypes.ServiceInfo{
-				createServiceInfo(service1, 5000, service1LocalURL),
-				createServiceInfo(service2, 5001, service2LocalURL),
-			},
-			instances: []aostypes.InstanceInfo{
-				createInstanceInfo(5000, 2, aostypes.InstanceIdent{
-					ServiceID: service1, SubjectID: subject1, Instance: 0,
-				}, 100),
-				createInstanceInfo(5001, 3, aostypes.InstanceIdent{
-					ServiceID: service1, SubjectID: subject1, Instance: 1,
-				}, 100),
-				createInstanceInfo(5002, 4, aostypes.InstanceIdent{
-					ServiceID: service2, SubjectID: subject1, Instance: 0,
-				}, 100),
-			},
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0},
+				nodeIDLocalSM, nil),
 		},
-		nodeIDRunxSM: {
-			services: []aostypes.ServiceInfo{
-				createServiceInfo(service3, 5002, service3RemoteURL),
-			},
-			layers: []aostypes.LayerInfo{},
-			instances: []aostypes.InstanceInfo{
-				createInstanceInfo(5003, 5, aostypes.InstanceIdent{
-					ServiceID: service3, SubjectID: subject1, Instance: 0,
-				}, 100),
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	instances := nodeManager.runRequest[nodeIDLocalSM].instances
+
+	if len(instances) != 2 || instances[0].ServiceID != service2 || instances[1].ServiceID != service1 {
+		t.Errorf("Incorrect instances order: %v", instances)
+	}
+
+	// Make dependency cycle
+
+	service2Info := imageManager.services[service2]
+	service2Info.Config.Dependencies = []string{service1}
+	imageManager.services[service2] = service2Info
+
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	cycleErr := newCodedError(cloudprotocol.ErrorCodeDependencyCycle, "service dependency cycle detected")
+
+	expectedRunStatus = unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+				"", cycleErr),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0},
+				"", cycleErr),
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if len(nodeManager.runRequest[nodeIDLocalSM].instances) != 0 {
+		t.Errorf("Unexpected instances: %v", nodeManager.runRequest[nodeIDLocalSM].instances)
+	}
+}
+
+func TestExclusiveCPUs(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
 			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, SystemInfo: cloudprotocol.SystemInfo{NumCPUs: 4},
+		},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, ExclusiveCPUs: 2},
 		},
 	}
 
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Node has 4 CPUs: third instance requesting 2 exclusive CPUs should be rejected
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 3},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
 	expectedRunStatus := unitstatushandler.RunInstancesStatus{
 		Instances: []cloudprotocol.InstanceStatus{
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service1, SubjectID: subject1, Instance: 0,
-			}, nodeIDLocalSM, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service1, SubjectID: subject1, Instance: 1,
-			}, nodeIDLocalSM, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service2, SubjectID: subject1, Instance: 0,
-			}, nodeIDLocalSM, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service3, SubjectID: subject1, Instance: 0,
-			}, nodeIDRunxSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 2},
+				"", newCodedError(cloudprotocol.ErrorCodeNoResources, "no node with 2 free exclusive CPUs")),
 		},
 	}
 
@@ -1097,37 +1461,3761 @@ func TestStorageCleanup(t *testing.T) {
 		t.Errorf("Incorrect run status: %v", err)
 	}
 
-	if err := nodeManager.compareRunRequests(expectedRunRequests); err != nil {
-		t.Errorf("incorrect run request: %v", err)
+	instance0 := createInstanceInfo(5000, 2, aostypes.InstanceIdent{
+		ServiceID: service1, SubjectID: subject1, Instance: 0,
+	}, 100)
+	instance0.CPUs = []int{0, 1}
+
+	instance1 := createInstanceInfo(5001, 3, aostypes.InstanceIdent{
+		ServiceID: service1, SubjectID: subject1, Instance: 1,
+	}, 100)
+	instance1.CPUs = []int{2, 3}
+
+	if err := nodeManager.compareRunRequests(map[string]runRequest{
+		nodeIDLocalSM: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1LocalURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{instance0, instance1},
+		},
+	}); err != nil {
+		t.Errorf("Incorrect run request: %v", err)
 	}
+}
 
-	desiredInstances = []cloudprotocol.InstanceInfo{
-		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+func TestQuantitativeResources(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
 	}
 
-	expectedRunStatus.Instances = []cloudprotocol.InstanceStatus{
-		createInstanceStatus(aostypes.InstanceIdent{
-			ServiceID: service1, SubjectID: subject1, Instance: 0,
-		}, nodeIDLocalSM, nil),
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM,
+		QuantitativeResources: []aostypes.QuantitativeResourceInfo{{Name: "hugepages", Capacity: 4}},
 	}
 
-	expectedCleanInstances := []aostypes.InstanceIdent{
-		{ServiceID: service1, SubjectID: subject1, Instance: 1},
-		{ServiceID: service2, SubjectID: subject1, Instance: 0},
-		{ServiceID: service3, SubjectID: subject1, Instance: 0},
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner:                runnerRunc,
+				QuantitativeResources: []aostypes.ServiceQuantitativeResource{{Name: "hugepages", Amount: 2}},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Node has 4 hugepages: third instance requesting 2 hugepages should be rejected
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 3},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 2},
+				"", newCodedError(cloudprotocol.ErrorCodeNoResources, "no node with enough quantitative resources")),
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Removing one instance releases its resources for the next placement
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	expectedRunStatus = unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1},
+				nodeIDLocalSM, nil),
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestInstancePriorityOverride(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM,
+		QuantitativeResources: []aostypes.QuantitativeResourceInfo{{Name: "hugepages", Capacity: 4}},
+	}
+
+	serviceConfig := aostypes.ServiceConfig{
+		Runner:                runnerRunc,
+		QuantitativeResources: []aostypes.ServiceQuantitativeResource{{Name: "hugepages", Amount: 2}},
+	}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      serviceConfig,
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      serviceConfig,
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Node has room for two instances only: service1 instance 1 overrides priority above service2 and is scheduled
+	// first, service1 instance 0 keeps service priority and is rejected
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{
+			ServiceID: service1, SubjectID: subject1, Priority: 50, NumInstances: 2,
+			PriorityOverrides: []cloudprotocol.InstancePriority{{Instance: 1, Priority: 200}},
+		},
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0},
+				nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0},
+				"", newCodedError(cloudprotocol.ErrorCodeNoResources, "no node with enough quantitative resources")),
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	priorities := make(map[aostypes.InstanceIdent]uint64)
+
+	for _, instance := range nodeManager.runRequest[nodeIDLocalSM].instances {
+		priorities[instance.InstanceIdent] = instance.Priority
+	}
+
+	if !reflect.DeepEqual(priorities, map[aostypes.InstanceIdent]uint64{
+		{ServiceID: service1, SubjectID: subject1, Instance: 1}: 200,
+		{ServiceID: service2, SubjectID: subject1, Instance: 0}: 100,
+	}) {
+		t.Errorf("Wrong run request priorities: %v", priorities)
+	}
+}
+
+func TestUIDPoolUtilization(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                 []string{nodeIDLocalSM},
+				NodesConnectionTimeout:  aostypes.Duration{Duration: time.Second},
+				UIDPoolLimit:            4,
+				UIDPoolWarningThreshold: 75,
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// UID pool is limited to 4 IDs: fifth instance can't get UID
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 5},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{Instances: []cloudprotocol.InstanceStatus{}}
+
+	for i := uint64(0); i < 4; i++ {
+		expectedRunStatus.Instances = append(expectedRunStatus.Instances, createInstanceStatus(
+			aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: i}, nodeIDLocalSM, nil))
+	}
+
+	expectedRunStatus.Instances = append(expectedRunStatus.Instances, createInstanceStatus(
+		aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 4}, "",
+		errors.New("ID pool is exhausted")))
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	utilization := launcherInstance.GetUIDPoolUtilization()
+
+	if utilization != (uidgidpool.Utilization{Allocated: 4, Capacity: 4}) {
+		t.Errorf("Incorrect UID pool utilization: %v", utilization)
+	}
+
+	if !utilization.IsAboveThreshold(cfg.SMController.UIDPoolWarningThreshold) {
+		t.Error("UID pool utilization should be above warning threshold")
+	}
+}
+
+func TestNetworkRollback(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		networkManager  = newTestNetworkManager("172.17.0.1/16")
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo:  createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:    service1RemoteURL,
+			ExposedPorts: []string{"8080/tcp"},
+			Config:       aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	failedIdent := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	runningIdent := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+
+	// Network of instance with exposed ports is prepared in the first pass and fails in the second one
+	networkManager.failPrepare = map[aostypes.InstanceIdent]int{failedIdent: 1}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, networkManager)
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(runningIdent, nodeIDLocalSM, nil),
+			createInstanceStatus(failedIdent, "",
+				newCodedError(cloudprotocol.ErrorCodeNetworkFailed, "can't prepare network")),
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if instances := networkManager.GetInstances(); !reflect.DeepEqual(instances,
+		[]aostypes.InstanceIdent{runningIdent}) {
+		t.Errorf("Wrong instances with network: %v", instances)
+	}
+
+	runInstances := nodeManager.runRequest[nodeIDLocalSM].instances
+
+	if len(runInstances) != 1 || runInstances[0].InstanceIdent != runningIdent {
+		t.Errorf("Wrong run request instances: %v", runInstances)
+	}
+}
+
+func TestInstanceEnvAndArgs(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Env: []string{"LOG=info", "MODE=default"}, Args: []string{"--default"},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1, Env: []string{"MODE=first"}},
+		{
+			ServiceID: service1, SubjectID: "subject2", Priority: 100, NumInstances: 1, Env: []string{"MODE=second"},
+			Args: []string{"--second"},
+		},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	ident1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	ident2 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: "subject2", Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(ident1, nodeIDLocalSM, nil), createInstanceStatus(ident2, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Instances of the same service get own env values merged with service defaults
+
+	instance1 := createInstanceInfo(5000, 2, ident1, 100)
+	instance1.Env = []string{"LOG=info", "MODE=first"}
+	instance1.Args = []string{"--default"}
+
+	instance2 := createInstanceInfo(5001, 3, ident2, 100)
+	instance2.Env = []string{"LOG=info", "MODE=second"}
+	instance2.Args = []string{"--second"}
+
+	if err := nodeManager.compareRunRequests(map[string]runRequest{
+		nodeIDLocalSM: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1LocalURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{instance1, instance2},
+		},
+	}); err != nil {
+		t.Errorf("Incorrect run request: %v", err)
+	}
+}
+
+func TestBalancingMetrics(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, SystemInfo: cloudprotocol.SystemInfo{NumCPUs: 4},
+		},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, ExclusiveCPUs: 2},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunx},
+		},
+		service3: {
+			ServiceInfo: createServiceInfo(service3, 5002, service3LocalURL),
+			RemoteURL:   service3RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, Resources: []string{"resource1"}},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 3},
+		{ServiceID: service1, SubjectID: "subject2", Priority: 100, NumInstances: 1, Labels: []string{"label1"}},
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 2},
+		{ServiceID: service3, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	metrics := launcherInstance.GetBalancingMetrics()
+
+	if !reflect.DeepEqual(metrics.ScheduledInstances, map[string]uint64{nodeIDLocalSM: 2}) {
+		t.Errorf("Wrong scheduled instances: %v", metrics.ScheduledInstances)
+	}
+
+	if !reflect.DeepEqual(metrics.SchedulingFailures, map[string]uint64{
+		launcher.BalancingFilterCPUs: 1, launcher.BalancingFilterLabels: 1,
+		launcher.BalancingFilterRunner: 2, launcher.BalancingFilterResources: 1,
+	}) {
+		t.Errorf("Wrong scheduling failures: %v", metrics.SchedulingFailures)
+	}
+
+	if metrics.RebalancingEvents != 0 {
+		t.Errorf("Wrong rebalancing events count: %d", metrics.RebalancingEvents)
+	}
+}
+
+func TestNodeRunnerFeaturesOverride(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	// Local node reports runc and crun but its node type permits only crun
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc, "crun"},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, RunnerFeatures: []string{"crun"},
+	}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: "crun"},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance2 := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDRemoteSM1, nil),
+			createInstanceStatus(instance2, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := nodeManager.compareRunRequests(map[string]runRequest{
+		nodeIDLocalSM: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service2, 5001, service2LocalURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{createInstanceInfo(5001, 2, instance2, 50)},
+		},
+		nodeIDRemoteSM1: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1RemoteURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{createInstanceInfo(5000, 3, instance1, 100)},
+		},
+	}); err != nil {
+		t.Errorf("Incorrect run request: %v", err)
+	}
+}
+
+func TestNodeTypeDefaultRunner(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	// Local node type defaults to runc, remote node uses global default runner
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, DefaultRunner: runnerRunc,
+	}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true,
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 50, NodeType: nodeTypeRemoteSM, DefaultRunnerFeatures: []string{"crun"},
+	}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: "crun"},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance2 := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDLocalSM, nil),
+			createInstanceStatus(instance2, nodeIDRemoteSM1, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestServiceAffinity(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 50, NodeType: nodeTypeRemoteSM, Resources: []aostypes.ResourceInfo{{Name: "resource1"}},
+	}
+
+	// service1 can run only on remote node, service2 has affinity to service1 and should be co-located with it,
+	// service3 has affinity to not running subject and should be placed on the most priority node
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, Resources: []string{"resource1"}},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Affinity: []aostypes.ServiceAffinity{{ServiceID: service1}},
+			},
+		},
+		service3: {
+			ServiceInfo: createServiceInfo(service3, 5002, service3LocalURL),
+			RemoteURL:   service3RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Affinity: []aostypes.ServiceAffinity{{ServiceID: service1, SubjectID: "subject2"}},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 1},
+		{ServiceID: service3, SubjectID: subject1, Priority: 0, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance2 := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+	instance3 := aostypes.InstanceIdent{ServiceID: service3, SubjectID: subject1, Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDRemoteSM1, nil),
+			createInstanceStatus(instance2, nodeIDRemoteSM1, nil),
+			createInstanceStatus(instance3, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestServiceAffinityToLowerPriorityService(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 50, NodeType: nodeTypeRemoteSM, Resources: []aostypes.ResourceInfo{{Name: "resource1"}},
+	}
+
+	// service2 can run only on remote node and has affinity to service1. service2 has higher priority and is placed
+	// first, so service1 should follow it to the remote node. service3 has no affinity and should be placed on the
+	// most priority node.
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Resources: []string{"resource1"},
+				Affinity: []aostypes.ServiceAffinity{{ServiceID: service1, SubjectID: subject1}},
+			},
+		},
+		service3: {
+			ServiceInfo: createServiceInfo(service3, 5002, service3LocalURL),
+			RemoteURL:   service3RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service1, SubjectID: subject1, Priority: 50, NumInstances: 1},
+		{ServiceID: service3, SubjectID: subject1, Priority: 0, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance2 := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+	instance3 := aostypes.InstanceIdent{ServiceID: service3, SubjectID: subject1, Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDRemoteSM1, nil),
+			createInstanceStatus(instance2, nodeIDRemoteSM1, nil),
+			createInstanceStatus(instance3, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestNodeTieBreaker(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+				NodeTieBreaker:         launcher.NodeTieBreakerLeastLoaded,
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, Resources: []aostypes.ResourceInfo{{Name: "resource1"}},
+	}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeRemoteSM,
+	}
+
+	// service1 can run only on local node, service2 should be placed on the least loaded remote node
+	// instead of lexicographically first local node
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, Resources: []string{"resource1"}},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance2 := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDLocalSM, nil),
+			createInstanceStatus(instance2, nodeIDRemoteSM1, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestRunSubjectInstances(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, Devices: []aostypes.DeviceInfo{{Name: "dev1", SharedCount: 1}},
+	}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 50, NodeType: nodeTypeRemoteSM, Devices: []aostypes.DeviceInfo{{Name: "dev1", SharedCount: 1}},
+	}
+
+	// Both services require exclusive device: subject1 instance occupies local node device, subject2 instance is
+	// placed on remote node
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, Devices: []aostypes.ServiceDevice{{Name: "dev1"}}},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, Devices: []aostypes.ServiceDevice{{Name: "dev1"}}},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject2, Priority: 50, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance2 := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject2, Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDLocalSM, nil),
+			createInstanceStatus(instance2, nodeIDRemoteSM1, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Remove subject1 instances: subject2 instance should stay on remote node
+
+	if err := launcherInstance.RunSubjectInstances(subject1, nil, []string{}, "correlation1"); err != nil {
+		t.Fatalf("Can't run subject instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		CorrelationID: "correlation1",
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance2, nodeIDRemoteSM1, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Subject instances request postponed by pause: subject2 instance should stay on remote node after resume
+
+	launcherInstance.Pause()
+
+	if err := launcherInstance.RunSubjectInstances(subject1, nil, []string{}, "correlation2"); err != nil {
+		t.Fatalf("Can't run subject instances %v", err)
+	}
+
+	if err := launcherInstance.Resume(); err != nil {
+		t.Fatalf("Can't resume launcher: %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		CorrelationID: "correlation2",
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance2, nodeIDRemoteSM1, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunSubjectInstances(subject1, []cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject2, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err == nil {
+		t.Error("Error expected for instance of wrong subject")
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	launcherInstance.Pause()
+
+	// Desired instances are queued while paused: only the latest one should be applied on resume
+
+	for _, numInstances := range []uint64{1, 2} {
+		if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+			{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: numInstances},
+		}, []string{}, ""); err != nil {
+			t.Fatalf("Can't run instances %v", err)
+		}
+	}
+
+	if err := launcherInstance.RestartInstances(); err != nil {
+		t.Fatalf("Can't restart instances %v", err)
+	}
+
+	select {
+	case status := <-launcherInstance.GetRunStatusesChannel():
+		t.Errorf("Unexpected run status: %v", status)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if nodeManager.runRequestCount != 0 {
+		t.Errorf("Unexpected run requests count: %d", nodeManager.runRequestCount)
+	}
+
+	if err := launcherInstance.Resume(); err != nil {
+		t.Fatalf("Can't resume launcher: %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}, nodeIDLocalSM, nil),
+			createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1}, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if nodeManager.runRequestCount != 1 {
+		t.Errorf("Wrong run requests count: %d", nodeManager.runRequestCount)
+	}
+}
+
+func TestMoveInstancesOnLabelsChange(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, Labels: []string{"label1", "label2"},
+	}
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 50, NodeType: nodeTypeRemoteSM, Labels: []string{"label1"},
+	}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for _, nodeID := range []string{nodeIDLocalSM, nodeIDRemoteSM1} {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: nodeManager.nodeInformation[nodeID].NodeType,
+			Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1, Labels: []string{"label1"}},
+		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance2 := aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDLocalSM, nil),
+			createInstanceStatus(instance2, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Unit config removes label required by instance: instance should be moved to node which still has the label
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, Labels: []string{"label2"},
+	}
+
+	if err := launcherInstance.RestartInstances(); err != nil {
+		t.Fatalf("Can't restart instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDRemoteSM1, nil),
+			createInstanceStatus(instance2, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := waitInstanceEvent(launcherInstance.GetInstanceEventsChannel(), launcher.InstanceEvent{
+		InstanceIdent: instance1, EventType: launcher.InstanceEventMoved,
+		NodeID: nodeIDRemoteSM1, PrevNodeID: nodeIDLocalSM,
+	}, time.Second); err != nil {
+		t.Errorf("Instance is not moved: %v", err)
+	}
+}
+
+func TestNodeResourceReserve(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		ramLimit        = uint64(500)
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM,
+			SystemInfo: cloudprotocol.SystemInfo{TotalRAM: 1000},
+		},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, ResourceReserve: &aostypes.NodeResourceReserve{RAM: 20},
+	}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM,
+			SystemInfo: cloudprotocol.SystemInfo{TotalRAM: 1000},
+		},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	// Two instances fit local node RAM only if 20% reserve is used, so the second one should go to remote node
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Quotas: aostypes.ServiceQuotas{RAMLimit: &ramLimit},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	appliedQuotas := &aostypes.ServiceQuotas{RAMLimit: &ramLimit}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			withAppliedQuotas(createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}, nodeIDLocalSM, nil),
+				appliedQuotas),
+			withAppliedQuotas(createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1}, nodeIDRemoteSM1, nil),
+				appliedQuotas),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestQuotasOvercommitWithoutReserve(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		ramLimit        = uint64(600)
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM,
+			SystemInfo: cloudprotocol.SystemInfo{TotalRAM: 1000},
+		},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM,
+			SystemInfo: cloudprotocol.SystemInfo{TotalRAM: 1000},
+		},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	// Node without reserve allows quotas overcommit: both instances should go to the local node
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Quotas: aostypes.ServiceQuotas{RAMLimit: &ramLimit},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	appliedQuotas := &aostypes.ServiceQuotas{RAMLimit: &ramLimit}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			withAppliedQuotas(createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}, nodeIDLocalSM, nil),
+				appliedQuotas),
+			withAppliedQuotas(createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1}, nodeIDLocalSM, nil),
+				appliedQuotas),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestAppliedQuotas(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		ramLimit        = uint64(2000)
+		cpuLimit        = uint64(50)
+		pidsLimit       = uint64(10)
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM,
+			SystemInfo: cloudprotocol.SystemInfo{TotalRAM: 1000, NumCPUs: 1},
+		},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	// Requested RAM exceeds node capacity and should be clamped, other quotas are applied as is
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc,
+				Quotas: aostypes.ServiceQuotas{RAMLimit: &ramLimit, CPULimit: &cpuLimit, PIDsLimit: &pidsLimit},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	clampedRAMLimit := uint64(1000)
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			withAppliedQuotas(createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}, nodeIDLocalSM, nil),
+				&aostypes.ServiceQuotas{RAMLimit: &clampedRAMLimit, CPULimit: &cpuLimit, PIDsLimit: &pidsLimit}),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Clamped quota is sent to the node with the instance
+
+	instances := nodeManager.runRequest[nodeIDLocalSM].instances
+	if len(instances) != 1 {
+		t.Fatalf("Wrong run request instances: %v", instances)
+	}
+
+	if instances[0].RAMLimit == nil || *instances[0].RAMLimit != clampedRAMLimit || instances[0].CPULimit != nil {
+		t.Errorf("Wrong instance quotas sent: RAM %v, CPU %v", instances[0].RAMLimit, instances[0].CPULimit)
+	}
+}
+
+func TestDeferOverCapacity(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+				DeferOverCapacity:      true,
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		ramLimit        = uint64(400)
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo: cloudprotocol.NodeInfo{
+			NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM,
+			SystemInfo: cloudprotocol.SystemInfo{TotalRAM: 1000},
+		},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	// Node RAM fits only two instances: low priority instance should be deferred
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Quotas: aostypes.ServiceQuotas{RAMLimit: &ramLimit},
+			},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Quotas: aostypes.ServiceQuotas{RAMLimit: &ramLimit},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 50, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 2},
+	}, []string{service1}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	deferredStatus := createInstanceStatus(
+		aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}, "",
+		newCodedError(cloudprotocol.ErrorCodeCapacityDeferred, "deferred due to capacity"))
+	deferredStatus.RunState = cloudprotocol.InstanceStateDeferred
+
+	appliedQuotas := &aostypes.ServiceQuotas{RAMLimit: &ramLimit}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			withAppliedQuotas(createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}, nodeIDLocalSM, nil),
+				appliedQuotas),
+			withAppliedQuotas(createInstanceStatus(
+				aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 1}, nodeIDLocalSM, nil),
+				appliedQuotas),
+			deferredStatus,
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestRunInstancesBeforeNodesConnected(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: 5 * time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instance        = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	select {
+	case runStatus := <-launcherInstance.GetRunStatusesChannel():
+		t.Errorf("Unexpected run status before nodes connected: %v", runStatus)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestRunInstancesCorrelationID(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		correlationID   = "desiredStatus1"
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, correlationID); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	select {
+	case runStatus := <-launcherInstance.GetRunStatusesChannel():
+		if runStatus.CorrelationID != correlationID {
+			t.Errorf("Wrong correlation ID: %s", runStatus.CorrelationID)
+		}
+
+	case <-time.After(time.Second):
+		t.Error("Wait run status timeout")
+	}
+
+	// Following run status is not caused by the request
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	select {
+	case runStatus := <-launcherInstance.GetRunStatusesChannel():
+		if runStatus.CorrelationID != "" {
+			t.Errorf("Unexpected correlation ID: %s", runStatus.CorrelationID)
+		}
+
+	case <-time.After(time.Second):
+		t.Error("Wait run status timeout")
+	}
+}
+
+func TestRepeatedNodeRunStatus(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instance        = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Node re-sends unchanged status: it should not be sent to the cloud
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{{
+			InstanceIdent: instance, AosVersion: 1, RunState: cloudprotocol.InstanceStateActive, NodeID: nodeIDLocalSM,
+		}},
+	}
+
+	select {
+	case runStatus := <-launcherInstance.GetRunStatusesChannel():
+		t.Errorf("Unexpected run status: %v", runStatus)
+
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// Changed status should be sent
+
+	failedStatus := createInstanceStatus(instance, nodeIDLocalSM, aoserrors.New("crash"))
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{failedStatus},
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{failedStatus},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestInstanceRestartPolicy(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		maxRetries      = 2
+		instance        = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc,
+				RestartPolicy: &aostypes.ServiceRestartPolicy{
+					Policy: aostypes.RestartPolicyOnFailure, MaxRetries: uint64(maxRetries),
+					Backoff: aostypes.Duration{Duration: 10 * time.Millisecond},
+				},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	failedStatus := []cloudprotocol.InstanceStatus{{
+		InstanceIdent: instance, NodeID: nodeIDLocalSM, AosVersion: 1, RunState: cloudprotocol.InstanceStateFailed,
+		ErrorInfo: &cloudprotocol.ErrorInfo{ExitCode: 1, Message: "crash"},
+	}}
+
+	// Instance should be restarted up to max retries
+
+	for i := 0; i < maxRetries; i++ {
+		runRequestCount := nodeManager.runRequestCount
+
+		status := launcherInstance.ProcessUpdateInstanceStatus(failedStatus)
+
+		if len(status) != 1 || status[0].RunState != cloudprotocol.InstanceStateActivating || status[0].ErrorInfo != nil {
+			t.Fatalf("Unexpected restarting instance status: %v", status)
+		}
+
+		if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+			Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)},
+		}, time.Second); err != nil {
+			t.Errorf("Instance is not restarted: %v", err)
+		}
+
+		// Instance is stopped by run request without it and started by the next one: node restart is not forced
+		if nodeManager.runRequestCount != runRequestCount+2 {
+			t.Errorf("Unexpected run request count: %d", nodeManager.runRequestCount-runRequestCount)
+		}
+
+		if request := nodeManager.runRequest[nodeIDLocalSM]; request.forceRestart ||
+			len(request.instances) != 1 || request.instances[0].InstanceIdent != instance {
+			t.Errorf("Unexpected instance restart request: %v", request)
+		}
+	}
+
+	// Retries are exhausted: instance should be reported as failed
+
+	status := launcherInstance.ProcessUpdateInstanceStatus(failedStatus)
+
+	if len(status) != 1 || status[0].RunState != cloudprotocol.InstanceStateFailed || status[0].ErrorInfo == nil ||
+		status[0].ErrorInfo.ErrorCode != cloudprotocol.ErrorCodeRestartExhausted ||
+		status[0].ErrorInfo.ExitCode != 1 || !strings.Contains(status[0].ErrorInfo.Message, "crash") {
+		t.Errorf("Unexpected failed instance status: %v", status)
+	}
+
+	select {
+	case runStatus := <-launcherInstance.GetRunStatusesChannel():
+		t.Errorf("Unexpected instance restart: %v", runStatus)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Restart retries are reset when instance stays active
+
+	launcherInstance.ProcessUpdateInstanceStatus([]cloudprotocol.InstanceStatus{
+		createInstanceStatus(instance, nodeIDLocalSM, nil),
+	})
+
+	time.Sleep(200 * time.Millisecond)
+
+	status = launcherInstance.ProcessUpdateInstanceStatus(failedStatus)
+
+	if len(status) != 1 || status[0].RunState != cloudprotocol.InstanceStateActivating || status[0].ErrorInfo != nil {
+		t.Fatalf("Unexpected restarting instance status: %v", status)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Instance is not restarted: %v", err)
+	}
+}
+
+func TestInstanceReadinessProbe(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = &testProbeNodeManager{testNodeManager: newTestNodeManager()}
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instance        = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc,
+				ReadinessProbe: &aostypes.ServiceReadinessProbe{
+					HTTPGet: "http://localhost:8080/ready", Period: aostypes.Duration{Duration: 10 * time.Millisecond},
+				},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	// Instance should stay activating till readiness probe passes
+
+	startingStatus := createInstanceStatus(instance, nodeIDLocalSM, nil)
+	startingStatus.RunState = cloudprotocol.InstanceStateActivating
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{startingStatus},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	status := launcherInstance.ProcessUpdateInstanceStatus(
+		[]cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)})
+
+	if len(status) != 1 || status[0].RunState != cloudprotocol.InstanceStateActivating {
+		t.Errorf("Unexpected not ready instance status: %v", status)
+	}
+
+	select {
+	case runStatus := <-launcherInstance.GetRunStatusesChannel():
+		t.Errorf("Unexpected run status: %v", runStatus)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Probe passes: instance should be reported as active
+
+	nodeManager.setReady(true)
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	status = launcherInstance.ProcessUpdateInstanceStatus(
+		[]cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDLocalSM, nil)})
+
+	if len(status) != 1 || status[0].RunState != cloudprotocol.InstanceStateActive {
+		t.Errorf("Unexpected ready instance status: %v", status)
+	}
+}
+
+func TestReadinessProbeGatesDependents(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = &testProbeNodeManager{testNodeManager: newTestNodeManager()}
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instance1       = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+		instance2       = aostypes.InstanceIdent{ServiceID: service2, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc,
+				ReadinessProbe: &aostypes.ServiceReadinessProbe{
+					HTTPGet: "http://localhost:8080/ready", Period: aostypes.Duration{Duration: 10 * time.Millisecond},
+				},
+			},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, Dependencies: []string{service1}},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	// Dependent instance should be held back till dependency is ready
+
+	activatingStatus := createInstanceStatus(instance1, nodeIDLocalSM, nil)
+	activatingStatus.RunState = cloudprotocol.InstanceStateActivating
+
+	heldStatus := cloudprotocol.InstanceStatus{
+		InstanceIdent: instance2, NodeID: nodeIDLocalSM, RunState: cloudprotocol.InstanceStateActivating,
+		StateChecksum: magicSum,
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{activatingStatus, heldStatus},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	launcherInstance.Lock()
+	runInstances := nodeManager.runRequest[nodeIDLocalSM].instances
+	launcherInstance.Unlock()
+
+	if len(runInstances) != 1 || runInstances[0].InstanceIdent != instance1 {
+		t.Errorf("Unexpected run instances: %v", runInstances)
+	}
+
+	// Dependency is ready: dependent instance should be started
+
+	nodeManager.setReady(true)
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance1, nodeIDLocalSM, nil), createInstanceStatus(instance2, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestReadinessProbeFailureThreshold(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = &testProbeNodeManager{testNodeManager: newTestNodeManager()}
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instance        = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc,
+				ReadinessProbe: &aostypes.ServiceReadinessProbe{
+					HTTPGet: "http://localhost:8080/ready", Period: aostypes.Duration{Duration: 10 * time.Millisecond},
+					FailureThreshold: 3,
+				},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	activatingStatus := createInstanceStatus(instance, nodeIDLocalSM, nil)
+	activatingStatus.RunState = cloudprotocol.InstanceStateActivating
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{activatingStatus},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Probe never passes: instance should be reported as failed after threshold
+
+	failedStatus := createInstanceStatus(instance, nodeIDLocalSM, nil)
+	failedStatus.RunState = cloudprotocol.InstanceStateFailed
+	failedStatus.ErrorInfo = &cloudprotocol.ErrorInfo{
+		ErrorCode: cloudprotocol.ErrorCodeFailed, Message: "readiness probe failed",
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{failedStatus},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestNodeCircuitBreaker(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+				NodeCircuitBreaker: config.CircuitBreaker{
+					MaxFailures:   2,
+					FailureWindow: aostypes.Duration{Duration: time.Minute},
+					Cooldown:      aostypes.Duration{Duration: 500 * time.Millisecond},
+					EmitEvents:    true,
+				},
+			},
+		}
+		nodeManager      = newTestNodeManager()
+		resourceManager  = newTestResourceManager()
+		imageManager     = &testImageProvider{}
+		instance         = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+		desiredInstances = []cloudprotocol.InstanceInfo{
+			{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	runAndCheckNode := func(nodeID string) {
+		t.Helper()
+
+		if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+			t.Fatalf("Can't run instances %v", err)
+		}
+
+		if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+			Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeID, nil)},
+		}, time.Second); err != nil {
+			t.Errorf("Incorrect run status: %v", err)
+		}
+	}
+
+	runAndCheckNode(nodeIDLocalSM)
+
+	// Instance fails repeatedly on local node: breaker should trip and exclude the node
+
+	for i := 0; i < 2; i++ {
+		launcherInstance.ProcessUpdateInstanceStatus([]cloudprotocol.InstanceStatus{{
+			InstanceIdent: instance, NodeID: nodeIDLocalSM, AosVersion: 1, RunState: cloudprotocol.InstanceStateFailed,
+			ErrorInfo: &cloudprotocol.ErrorInfo{ExitCode: 1, Message: "crash"},
+		}})
+	}
+
+	if err := waitNodeEvent(launcherInstance.GetNodeEventsChannel(), launcher.NodeEvent{
+		EventType: launcher.NodeEventBreakerTripped, NodeID: nodeIDLocalSM,
+	}, time.Second); err != nil {
+		t.Errorf("Node breaker is not tripped: %v", err)
+	}
+
+	runAndCheckNode(nodeIDRemoteSM1)
+
+	// Instance pinned to the node excluded by breaker should not be placed
+
+	placement, errStatus, err := launcherInstance.PreviewPlacement([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1, NodeID: nodeIDLocalSM},
+	})
+	if err != nil {
+		t.Fatalf("Can't preview placement: %v", err)
+	}
+
+	if len(placement) != 0 {
+		t.Errorf("Pinned instance should not be placed: %v", placement)
+	}
+
+	if len(errStatus) != 1 || errStatus[0].ErrorInfo == nil ||
+		!strings.Contains(errStatus[0].ErrorInfo.Message, "circuit breaker") {
+		t.Errorf("Wrong pinned instance error status: %v", errStatus)
+	}
+
+	time.Sleep(cfg.SMController.NodeCircuitBreaker.Cooldown.Duration)
+
+	// Preview should not change breaker state: failure on open breaker is ignored while on half-opened one trips it
+
+	placement, _, err = launcherInstance.PreviewPlacement(desiredInstances)
+	if err != nil {
+		t.Fatalf("Can't preview placement: %v", err)
+	}
+
+	if !reflect.DeepEqual(placement, map[string][]aostypes.InstanceIdent{nodeIDLocalSM: {instance}}) {
+		t.Errorf("Wrong preview placement: %v", placement)
+	}
+
+	launcherInstance.ProcessUpdateInstanceStatus([]cloudprotocol.InstanceStatus{{
+		InstanceIdent: instance, NodeID: nodeIDLocalSM, AosVersion: 1, RunState: cloudprotocol.InstanceStateFailed,
+		ErrorInfo: &cloudprotocol.ErrorInfo{ExitCode: 1, Message: "crash"},
+	}})
+
+	if err := waitNodeEvent(launcherInstance.GetNodeEventsChannel(), launcher.NodeEvent{
+		EventType: launcher.NodeEventBreakerTripped, NodeID: nodeIDLocalSM,
+	}, 100*time.Millisecond); err == nil {
+		t.Error("Node breaker state is changed by preview")
+	}
+
+	// After cooldown node is half-opened and successful run resets the breaker
+
+	runAndCheckNode(nodeIDLocalSM)
+
+	if err := waitNodeEvent(launcherInstance.GetNodeEventsChannel(), launcher.NodeEvent{
+		EventType: launcher.NodeEventBreakerReset, NodeID: nodeIDLocalSM,
+	}, time.Second); err != nil {
+		t.Errorf("Node breaker is not reset: %v", err)
+	}
+}
+
+func TestNodeTypeMismatch(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+				ExpectedNodeTypes: map[string]string{
+					nodeIDLocalSM: "expectedType", nodeIDRemoteSM1: nodeTypeRemoteSM,
+				},
+			},
+		}
+		nodeManager      = newTestNodeManager()
+		resourceManager  = newTestResourceManager()
+		imageManager     = &testImageProvider{}
+		instance         = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+		desiredInstances = []cloudprotocol.InstanceInfo{
+			{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := waitNodeEvent(launcherInstance.GetNodeEventsChannel(), launcher.NodeEvent{
+		EventType: launcher.NodeEventTypeMismatch, NodeID: nodeIDLocalSM,
+	}, time.Second); err != nil {
+		t.Errorf("Node type mismatch is not flagged: %v", err)
+	}
+
+	// Local node has higher priority but is excluded from balancing due to unexpected type
+
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instance, nodeIDRemoteSM1, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestPreviewPlacement(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		storage         = newTestStorage()
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeLocalSM, Devices: []aostypes.DeviceInfo{{Name: "dev1", SharedCount: 1}},
+	}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 50, NodeType: nodeTypeRemoteSM, Devices: []aostypes.DeviceInfo{{Name: "dev1", SharedCount: 1}},
+	}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc, Devices: []aostypes.ServiceDevice{{Name: "dev1"}},
+			},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service3: {
+			ServiceInfo: createServiceInfo(service3, 5002, service3LocalURL),
+			RemoteURL:   service3RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunx},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, storage, nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	desiredInstances := []cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 3},
+		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 1},
+		{ServiceID: service3, SubjectID: subject1, Priority: 0, NumInstances: 1},
+	}
+
+	runRequests := nodeManager.runRequest
+
+	placement, errStatus, err := launcherInstance.PreviewPlacement(desiredInstances)
+	if err != nil {
+		t.Fatalf("Can't preview placement: %v", err)
+	}
+
+	// Preview should not be applied
+
+	if !reflect.DeepEqual(runRequests, nodeManager.runRequest) {
+		t.Error("Run request should not be sent on preview")
+	}
+
+	if len(storage.instanceInfo) != 0 {
+		t.Error("Instances should not be stored on preview")
+	}
+
+	expectedErrors := map[aostypes.InstanceIdent]string{
+		{ServiceID: service1, SubjectID: subject1, Instance: 2}: "no available device found",
+		{ServiceID: service3, SubjectID: subject1, Instance: 0}: "no node with runner: runx",
+	}
+
+	if len(errStatus) != len(expectedErrors) {
+		t.Errorf("Wrong preview error status count: %d", len(errStatus))
+	}
+
+	for _, status := range errStatus {
+		if status.ErrorInfo == nil || !strings.Contains(status.ErrorInfo.Message, expectedErrors[status.InstanceIdent]) {
+			t.Errorf("Wrong preview error status: %v", status)
+		}
+	}
+
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	// Preview should match actual placement
+
+	for _, nodeID := range []string{nodeIDLocalSM, nodeIDRemoteSM1} {
+		var actualPlacement []aostypes.InstanceIdent
+
+		for _, instance := range nodeManager.runRequest[nodeID].instances {
+			actualPlacement = append(actualPlacement, instance.InstanceIdent)
+		}
+
+		if !reflect.DeepEqual(placement[nodeID], actualPlacement) {
+			t.Errorf("Wrong preview placement for node %s: %v, expected %v", nodeID, placement[nodeID],
+				actualPlacement)
+		}
+	}
+}
+
+func TestServiceRevert(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		imageManager    = &testImageProvider{}
+		resourceManager = newTestResourceManager()
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false,
+	}
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{NodeType: nodeTypeLocalSM, Priority: 100}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Layers:      []string{layer1},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Layers:      []string{layer2},
+		},
+	}
+
+	imageManager.layers = map[string]imagemanager.LayerInfo{
+		layer1: {
+			LayerInfo: createLayerInfo(layer1, layer1LocalURL),
+			RemoteURL: layer1RemoteURL,
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+
+	// Wait initial run status
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Run instances
+
+	desiredInstances := []cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+		{ServiceID: service2, SubjectID: subject1, Priority: 50, NumInstances: 2},
+	}
+
+	if err := launcherInstance.RunInstances(desiredInstances, []string{service2}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service1, SubjectID: subject1, Instance: 0,
+			}, nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service1, SubjectID: subject1, Instance: 1,
+			}, nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service2, SubjectID: subject1, Instance: 0,
+			}, "", newCodedError(cloudprotocol.ErrorCodeLayerNotFound, "layer does't exist")),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service2, SubjectID: subject1, Instance: 1,
+			}, "", newCodedError(cloudprotocol.ErrorCodeLayerNotFound, "layer does't exist")),
+		},
+		ErrorServices: []cloudprotocol.ServiceStatus{
+			{ID: service2, AosVersion: 1, Status: cloudprotocol.ErrorStatus},
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if !reflect.DeepEqual([]string{service2}, imageManager.revertedServices) {
+		t.Errorf("Incorrect reverted services: %v", imageManager.revertedServices)
+	}
+}
+
+func TestStorageCleanup(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRunxSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager          = newTestNodeManager()
+		resourceManager      = newTestResourceManager()
+		imageManager         = &testImageProvider{}
+		stateStorageProvider = &testStateStorage{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:      cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode:    false,
+		RunnerFeature: []string{runnerRunc},
+	}
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100}
+
+	nodeManager.nodeInformation[nodeIDRunxSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeRunxSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunx},
+	}
+	resourceManager.nodeResources[nodeTypeRunxSM] = aostypes.NodeUnitConfig{Priority: 0}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL, Config: aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL, Config: aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service3: {
+			ServiceInfo: createServiceInfo(service3, 5002, service3LocalURL),
+			RemoteURL:   service3RemoteURL, Config: aostypes.ServiceConfig{Runner: runnerRunx},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		stateStorageProvider, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	desiredInstances := []cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service3, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}
+
+	if err := launcherInstance.RunInstances(desiredInstances, nil, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	expectedRunRequests := map[string]runRequest{
+		nodeIDLocalSM: {
+			services: []aostypes.ServiceInfo{
+				createServiceInfo(service1, 5000, service1LocalURL),
+				createServiceInfo(service2, 5001, service2LocalURL),
+			},
+			instances: []aostypes.InstanceInfo{
+				createInstanceInfo(5000, 2, aostypes.InstanceIdent{
+					ServiceID: service1, SubjectID: subject1, Instance: 0,
+				}, 100),
+				createInstanceInfo(5001, 3, aostypes.InstanceIdent{
+					ServiceID: service1, SubjectID: subject1, Instance: 1,
+				}, 100),
+				createInstanceInfo(5002, 4, aostypes.InstanceIdent{
+					ServiceID: service2, SubjectID: subject1, Instance: 0,
+				}, 100),
+			},
+		},
+		nodeIDRunxSM: {
+			services: []aostypes.ServiceInfo{
+				createServiceInfo(service3, 5002, service3RemoteURL),
+			},
+			layers: []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{
+				createInstanceInfo(5003, 5, aostypes.InstanceIdent{
+					ServiceID: service3, SubjectID: subject1, Instance: 0,
+				}, 100),
+			},
+		},
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service1, SubjectID: subject1, Instance: 0,
+			}, nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service1, SubjectID: subject1, Instance: 1,
+			}, nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service2, SubjectID: subject1, Instance: 0,
+			}, nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service3, SubjectID: subject1, Instance: 0,
+			}, nodeIDRunxSM, nil),
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := nodeManager.compareRunRequests(expectedRunRequests); err != nil {
+		t.Errorf("incorrect run request: %v", err)
+	}
+
+	desiredInstances = []cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}
+
+	expectedRunStatus.Instances = []cloudprotocol.InstanceStatus{
+		createInstanceStatus(aostypes.InstanceIdent{
+			ServiceID: service1, SubjectID: subject1, Instance: 0,
+		}, nodeIDLocalSM, nil),
+	}
+
+	expectedCleanInstances := []aostypes.InstanceIdent{
+		{ServiceID: service1, SubjectID: subject1, Instance: 1},
+		{ServiceID: service2, SubjectID: subject1, Instance: 0},
+		{ServiceID: service3, SubjectID: subject1, Instance: 0},
+	}
+
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedCleanInstances, stateStorageProvider.cleanedInstances) {
+		t.Errorf("Incorrect state storage cleanup: %v", stateStorageProvider.cleanedInstances)
+	}
+}
+
+func TestStateCleanupGracePeriod(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                 []string{nodeIDLocalSM},
+				NodesConnectionTimeout:  aostypes.Duration{Duration: time.Second},
+				StateCleanupGracePeriod: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager          = newTestNodeManager()
+		resourceManager      = newTestResourceManager()
+		imageManager         = &testImageProvider{}
+		stateStorageProvider = &testStateStorage{}
+		instanceIdent        = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		stateStorageProvider, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	desiredInstances := []cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, nodeIDLocalSM, nil)},
+	}
+
+	// Run, remove and quickly re-add instance
+
+	for _, runStep := range []struct {
+		instances []cloudprotocol.InstanceInfo
+		runStatus unitstatushandler.RunInstancesStatus
+	}{
+		{desiredInstances, expectedRunStatus},
+		{[]cloudprotocol.InstanceInfo{}, unitstatushandler.RunInstancesStatus{}},
+		{desiredInstances, expectedRunStatus},
+	} {
+		if err := launcherInstance.RunInstances(runStep.instances, []string{}, ""); err != nil {
+			t.Fatalf("Can't run instances %v", err)
+		}
+
+		if err := waitRunInstancesStatus(
+			launcherInstance.GetRunStatusesChannel(), runStep.runStatus, time.Second); err != nil {
+			t.Errorf("Incorrect run status: %v", err)
+		}
+	}
+
+	time.Sleep(2 * cfg.SMController.StateCleanupGracePeriod.Duration)
+
+	if cleanedInstances := stateStorageProvider.getCleanedInstances(); len(cleanedInstances) != 0 {
+		t.Errorf("Unexpected state storage cleanup: %v", cleanedInstances)
+	}
+
+	// Remove instance and wait grace period expiration
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if cleanedInstances := stateStorageProvider.getCleanedInstances(); len(cleanedInstances) != 0 {
+		t.Errorf("State storage cleaned up before grace period expiration: %v", cleanedInstances)
+	}
+
+	time.Sleep(2 * cfg.SMController.StateCleanupGracePeriod.Duration)
+
+	if cleanedInstances := stateStorageProvider.getCleanedInstances(); !reflect.DeepEqual(
+		cleanedInstances, []aostypes.InstanceIdent{instanceIdent}) {
+		t.Errorf("Incorrect state storage cleanup: %v", cleanedInstances)
+	}
+}
+
+func TestInstanceStopTimeout(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: 500 * time.Millisecond},
+				InstanceStopTimeout:    aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager          = newTestNodeManager()
+		resourceManager      = newTestResourceManager()
+		imageManager         = &testImageProvider{}
+		stateStorageProvider = &testStateStorage{}
+		instanceIdent        = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		stateStorageProvider, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	for i, stopConfirmed := range []bool{true, false} {
+		nodeManager.holdStatusNodeID = ""
+
+		if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+			{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		}, []string{}, ""); err != nil {
+			t.Fatalf("Can't run instances %v", err)
+		}
+
+		if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+			Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, nodeIDLocalSM, nil)},
+		}, time.Second); err != nil {
+			t.Errorf("Incorrect run status: %v", err)
+		}
+
+		// Hold node status to emulate instance which is not confirmed as stopped
+
+		nodeManager.holdStatusNodeID = nodeIDLocalSM
+
+		if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{}, []string{}, ""); err != nil {
+			t.Fatalf("Can't run instances %v", err)
+		}
+
+		if err := waitRunInstancesStatus(
+			launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+			t.Errorf("Incorrect run status: %v", err)
+		}
+
+		if cleanedInstances := stateStorageProvider.getCleanedInstances(); len(cleanedInstances) != i {
+			t.Errorf("State storage cleaned up before stop confirmation: %v", cleanedInstances)
+		}
+
+		if !stopConfirmed {
+			if err := waitInstanceEvent(launcherInstance.GetInstanceEventsChannel(), launcher.InstanceEvent{
+				InstanceIdent: instanceIdent, EventType: launcher.InstanceEventStopTimeout, NodeID: nodeIDLocalSM,
+			}, 2*cfg.SMController.InstanceStopTimeout.Duration); err != nil {
+				t.Errorf("Wait stop timeout event error: %v", err)
+			}
+
+			if cleanedInstances := stateStorageProvider.getCleanedInstances(); len(cleanedInstances) != i {
+				t.Errorf("State storage cleaned up before stop confirmation: %v", cleanedInstances)
+			}
+		}
+
+		// Node confirms instance stop: state should be cleaned up
+
+		select {
+		case runStatus := <-nodeManager.heldStatusChan:
+			nodeManager.runStatusChan <- runStatus
+
+		case <-time.After(time.Second):
+			t.Fatal("Wait held node status timeout")
+		}
+
+		if err := waitRunInstancesStatus(
+			launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+			t.Errorf("Incorrect run status: %v", err)
+		}
+
+		if cleanedInstances := stateStorageProvider.getCleanedInstances(); len(cleanedInstances) != i+1 ||
+			cleanedInstances[i] != instanceIdent {
+			t.Errorf("Incorrect state storage cleanup: %v", cleanedInstances)
+		}
+	}
+}
+
+func TestResyncRestartedNode(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: 500 * time.Millisecond},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instanceIdent   = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, nodeIDLocalSM, nil)},
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	runRequestCount := nodeManager.runRequestCount
+
+	delete(nodeManager.runRequest, nodeIDLocalSM)
+
+	// Node restarts and re-registers without instances
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if nodeManager.runRequestCount != runRequestCount+1 {
+		t.Errorf("Wrong run request count: %d", nodeManager.runRequestCount)
+	}
+
+	runRequest, ok := nodeManager.runRequest[nodeIDLocalSM]
+	if !ok {
+		t.Fatal("Run instances is not resent to restarted node")
+	}
+
+	if len(runRequest.instances) != 1 || runRequest.instances[0].InstanceIdent != instanceIdent {
+		t.Errorf("Wrong resent instances: %v", runRequest.instances)
+	}
+}
+
+func TestLocalOnlyService(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation = map[string]launcher.NodeInfo{
+		nodeIDLocalSM: {
+			NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+			RemoteNode: false, RunnerFeature: []string{runnerRunc},
+		},
+		nodeIDRemoteSM1: {
+			NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+			RemoteNode: true, RunnerFeature: []string{runnerRunc, runnerRunx},
+		},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeLocalSM}
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{
+		Priority: 100, NodeType: nodeTypeRemoteSM,
+	}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+		service2: {
+			ServiceInfo: createServiceInfo(service2, 5001, service2LocalURL),
+			RemoteURL:   service2RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, LocalOnly: true},
+		},
+		service3: {
+			ServiceInfo: createServiceInfo(service3, 5002, service3LocalURL),
+			RemoteURL:   service3RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunx, LocalOnly: true},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for _, nodeID := range cfg.SMController.NodeIDs {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: nodeManager.nodeInformation[nodeID].NodeType,
+			Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service2, SubjectID: subject1, Priority: 100, NumInstances: 1},
+		{ServiceID: service3, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	var runStatus unitstatushandler.RunInstancesStatus
+
+	select {
+	case runStatus = <-launcherInstance.GetRunStatusesChannel():
+
+	case <-time.After(time.Second):
+		t.Fatal("Wait run status timeout")
+	}
+
+	expectedNodes := map[string]string{service1: nodeIDRemoteSM1, service2: nodeIDLocalSM, service3: ""}
+
+	if len(runStatus.Instances) != len(expectedNodes) {
+		t.Fatalf("Wrong instances count: %d", len(runStatus.Instances))
+	}
+
+	for _, status := range runStatus.Instances {
+		if status.ServiceID != service3 {
+			if status.ErrorInfo != nil || status.NodeID != expectedNodes[status.ServiceID] {
+				t.Errorf("Wrong instance %s node: %s, error: %v", status.ServiceID, status.NodeID, status.ErrorInfo)
+			}
+
+			continue
+		}
+
+		if status.ErrorInfo == nil || status.ErrorInfo.ErrorCode != cloudprotocol.ErrorCodeNoNode ||
+			!strings.Contains(status.ErrorInfo.Message, "no local node available") {
+			t.Errorf("Wrong local only instance error: %v", status.ErrorInfo)
+		}
+	}
+
+	for _, instance := range nodeManager.runRequest[nodeIDRemoteSM1].instances {
+		if instance.ServiceID != service1 {
+			t.Errorf("Local only instance %v is scheduled on remote node", instance.InstanceIdent)
+		}
+	}
+}
+
+func TestStoreDesiredInstancesRetry(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: 500 * time.Millisecond},
+				StorageWriteMaxTry:     2,
+				StorageWriteRetryDelay: aostypes.Duration{Duration: 10 * time.Millisecond},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		testStorage     = newTestStorage()
+		instanceIdent   = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, testStorage, nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	desiredInstances := []cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}
+
+	// Transient storage failure: desired instances are stored on retry
+
+	testStorage.setDesiredInstancesFailures(1)
+
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := waitStoredDesiredInstances(testStorage, desiredInstances, time.Second); err != nil {
+		t.Errorf("Wrong stored desired instances: %v", err)
+	}
+
+	if err := launcherInstance.GetHealthError(); err != nil {
+		t.Errorf("Unexpected health error: %v", err)
+	}
+
+	// Persistent storage failure: instances are run and failure is reported by health error
+
+	testStorage.setDesiredInstancesFailures(cfg.SMController.StorageWriteMaxTry)
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := waitHealthError(launcherInstance, true, time.Second); err != nil {
+		t.Errorf("Wrong health error: %v", err)
+	}
+
+	// Next successful store clears health error
+
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := waitHealthError(launcherInstance, false, time.Second); err != nil {
+		t.Errorf("Wrong health error: %v", err)
+	}
+
+	if err := waitStoredDesiredInstances(testStorage, desiredInstances, time.Second); err != nil {
+		t.Errorf("Wrong stored desired instances: %v", err)
+	}
+}
+
+func TestDeferNodeWithoutUnitConfig(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+				UnitConfigRetryPeriod:  aostypes.Duration{Duration: 100 * time.Millisecond},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instanceIdent   = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+	resourceManager.setUnavailable(true)
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Node without unit config doesn't participate in balancing
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, "",
+			newCodedError(cloudprotocol.ErrorCodeNoNode, "no node with valid unit config"))},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if len(nodeManager.runRequest[nodeIDLocalSM].instances) != 0 {
+		t.Errorf("Instances scheduled on node without unit config: %v", nodeManager.runRequest[nodeIDLocalSM].instances)
+	}
+
+	// Node participates in balancing when unit config is available
+
+	resourceManager.setUnavailable(false)
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestNodeTypeNotInUnitConfig(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instanceIdent   = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	// Node type is not listed in unit config: node participates in balancing with default config
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{createInstanceStatus(instanceIdent, nodeIDLocalSM, nil)},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestObserverMode(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			ObserverMode: true,
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: 100 * time.Millisecond},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		storage         = newTestStorage()
+		instanceIdent   = aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config: aostypes.ServiceConfig{
+				Runner: runnerRunc,
+				RestartPolicy: &aostypes.ServiceRestartPolicy{
+					Policy: aostypes.RestartPolicyOnFailure, Backoff: aostypes.Duration{Duration: 10 * time.Millisecond},
+				},
+			},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, storage, nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Instances are balanced but not dispatched to nodes and not stored
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 1},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if storedInstances, _ := storage.GetDesiredInstances(); string(storedInstances) != "[]" {
+		t.Errorf("Unexpected stored desired instances: %s", storedInstances)
+	}
+
+	// Failed instance is not restarted
+
+	status := launcherInstance.ProcessUpdateInstanceStatus([]cloudprotocol.InstanceStatus{
+		createInstanceStatus(instanceIdent, nodeIDLocalSM, aoserrors.New("crash")),
+	})
+
+	if len(status) != 1 || status[0].RunState != cloudprotocol.InstanceStateFailed {
+		t.Errorf("Unexpected instance status: %v", status)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if nodeManager.runRequestCount != 0 {
+		t.Errorf("Unexpected run requests: %d", nodeManager.runRequestCount)
+	}
+}
+
+func TestStateIntegrityMismatch(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM},
+				NodesConnectionTimeout: aostypes.Duration{Duration: 500 * time.Millisecond},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+		instances       = []aostypes.InstanceIdent{
+			{ServiceID: service1, SubjectID: subject1, Instance: 0},
+			{ServiceID: service1, SubjectID: subject1, Instance: 1},
+		}
+		stateStorage = &testStateStorage{corruptedInstances: instances[1:]}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		stateStorage, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
 	}
+	defer launcherInstance.Close()
 
-	if err := launcherInstance.RunInstances(desiredInstances, []string{}); err != nil {
-		t.Fatalf("Can't run instances %v", err)
+	nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+		NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM, Instances: []cloudprotocol.InstanceStatus{},
 	}
 
 	if err := waitRunInstancesStatus(
-		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
 		t.Errorf("Incorrect run status: %v", err)
 	}
 
-	if !reflect.DeepEqual(expectedCleanInstances, stateStorageProvider.cleanedInstances) {
-		t.Errorf("Incorrect state storage cleanup: %v", stateStorageProvider.cleanedInstances)
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: uint64(len(instances))},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	corruptedStatus := createInstanceStatus(instances[1], nodeIDLocalSM, nil)
+	corruptedStatus.StateCorrupted = true
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instances[0], nodeIDLocalSM, nil),
+			corruptedStatus,
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
 	}
 }
 
@@ -1288,99 +5376,448 @@ func TestRebalancing(t *testing.T) {
 		},
 	}
 
-	if err := launcherInstance.RunInstances(desiredInstances, []string{}); err != nil {
-		t.Fatalf("Can't run instances %v", err)
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := nodeManager.compareRunRequests(expectedRunRequests); err != nil {
+		t.Errorf("Incorrect run request: %v", err)
+	}
+
+	nodeManager.alertsChannel <- cloudprotocol.SystemQuotaAlert{NodeID: nodeIDLocalSM, Parameter: "cpu"}
+
+	expectedRunRequests = map[string]runRequest{
+		nodeIDLocalSM: {
+			services: []aostypes.ServiceInfo{
+				createServiceInfo(service2, 5001, service1LocalURL),
+			},
+			layers: []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{
+				createInstanceInfo(5001, 2, aostypes.InstanceIdent{
+					ServiceID: service2, SubjectID: subject1, Instance: 0,
+				}, 50),
+			},
+		},
+		nodeIDRemoteSM1: {
+			services: []aostypes.ServiceInfo{
+				createServiceInfo(service1, 5000, service1RemoteURL),
+				createServiceInfo(service3, 5002, service3RemoteURL),
+			},
+			layers: []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{
+				createInstanceInfo(5000, 4, aostypes.InstanceIdent{
+					ServiceID: service1, SubjectID: subject1, Instance: 0,
+				}, 100),
+				createInstanceInfo(5003, 5, aostypes.InstanceIdent{
+					ServiceID: service3, SubjectID: subject1, Instance: 1,
+				}, 0),
+				createInstanceInfo(5004, 6, aostypes.InstanceIdent{
+					ServiceID: service3, SubjectID: subject1, Instance: 2,
+				}, 0),
+			},
+		},
+		nodeIDRemoteSM2: {
+			services: []aostypes.ServiceInfo{
+				createServiceInfo(service3, 5002, service3RemoteURL),
+			},
+			layers: []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{
+				createInstanceInfo(5002, 3, aostypes.InstanceIdent{
+					ServiceID: service3, SubjectID: subject1, Instance: 0,
+				}, 0),
+			},
+		},
+	}
+
+	expectedRunStatus = unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service1, SubjectID: subject1, Instance: 0,
+			}, nodeIDRemoteSM1, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service2, SubjectID: subject1, Instance: 0,
+			}, nodeIDLocalSM, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service3, SubjectID: subject1, Instance: 1,
+			}, nodeIDRemoteSM1, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service3, SubjectID: subject1, Instance: 2,
+			}, nodeIDRemoteSM1, nil),
+			createInstanceStatus(aostypes.InstanceIdent{
+				ServiceID: service3, SubjectID: subject1, Instance: 0,
+			}, nodeIDRemoteSM2, nil),
+		},
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := nodeManager.compareRunRequests(expectedRunRequests); err != nil {
+		t.Errorf("incorrect run request: %v", err)
+	}
+
+	if err := waitInstanceEvent(launcherInstance.GetInstanceEventsChannel(), launcher.InstanceEvent{
+		InstanceIdent: aostypes.InstanceIdent{ServiceID: service3, SubjectID: subject1, Instance: 0},
+		EventType:     launcher.InstanceEventMoved, NodeID: nodeIDRemoteSM2, PrevNodeID: nodeIDLocalSM,
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect instance event: %v", err)
+	}
+
+	nodeManager.alertsChannel <- cloudprotocol.SystemQuotaAlert{NodeID: nodeIDRemoteSM2, Parameter: "cpu"}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err == nil ||
+		!strings.Contains(err.Error(), "message timeout") {
+		t.Error("Timeout expected")
+	}
+
+	if metrics := launcherInstance.GetBalancingMetrics(); metrics.RebalancingEvents != 1 {
+		t.Errorf("Wrong rebalancing events count: %d", metrics.RebalancingEvents)
+	}
+}
+
+func TestRebalancingStopBeforeMove(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, StopBeforeMove: true},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance0 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance0, nodeIDLocalSM, nil),
+			createInstanceStatus(instance1, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Hold source node status to check that destination node is not started before stop confirmation
+
+	nodeManager.holdStatusNodeID = nodeIDLocalSM
+
+	nodeManager.alertsChannel <- cloudprotocol.SystemQuotaAlert{NodeID: nodeIDLocalSM, Parameter: "cpu"}
+
+	var sourceStatus launcher.NodeRunInstanceStatus
+
+	select {
+	case sourceStatus = <-nodeManager.heldStatusChan:
+
+	case <-time.After(time.Second):
+		t.Fatal("Wait source node run request timeout")
+	}
+
+	if len(nodeManager.getRunRequest(nodeIDRemoteSM1).instances) != 0 {
+		t.Error("Destination node should not be started before source node stops instance")
+	}
+
+	nodeManager.runStatusChan <- sourceStatus
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance0, nodeIDLocalSM, nil),
+			createInstanceStatus(instance1, nodeIDRemoteSM1, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := nodeManager.compareRunRequests(map[string]runRequest{
+		nodeIDLocalSM: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1LocalURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{createInstanceInfo(5000, 2, instance0, 100)},
+		},
+		nodeIDRemoteSM1: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1RemoteURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{createInstanceInfo(5001, 3, instance1, 100)},
+		},
+	}); err != nil {
+		t.Errorf("Incorrect run request: %v", err)
+	}
+}
+
+func TestRebalancingStopBeforeMoveTimeout(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
+				MoveStopTimeout:        aostypes.Duration{Duration: 200 * time.Millisecond},
+			},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc, StopBeforeMove: true},
+		},
+	}
+
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
+	if err := waitRunInstancesStatus(
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 2},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
+	}
+
+	instance0 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 0}
+	instance1 := aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: 1}
+
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance0, nodeIDLocalSM, nil),
+			createInstanceStatus(instance1, nodeIDLocalSM, nil),
+		},
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Source node doesn't confirm instance stop: moved instance is started on destination node after timeout
+
+	nodeManager.holdStatusNodeID = nodeIDLocalSM
+
+	nodeManager.alertsChannel <- cloudprotocol.SystemQuotaAlert{NodeID: nodeIDLocalSM, Parameter: "cpu"}
+
+	var sourceStatus launcher.NodeRunInstanceStatus
+
+	select {
+	case sourceStatus = <-nodeManager.heldStatusChan:
+
+	case <-time.After(time.Second):
+		t.Fatal("Wait source node run request timeout")
 	}
 
-	if err := waitRunInstancesStatus(
-		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
-		t.Errorf("Incorrect run status: %v", err)
+	expectedRunRequests := map[string]runRequest{
+		nodeIDLocalSM: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1LocalURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{createInstanceInfo(5000, 2, instance0, 100)},
+		},
+		nodeIDRemoteSM1: {
+			services:  []aostypes.ServiceInfo{createServiceInfo(service1, 5000, service1RemoteURL)},
+			layers:    []aostypes.LayerInfo{},
+			instances: []aostypes.InstanceInfo{createInstanceInfo(5001, 3, instance1, 100)},
+		},
 	}
 
+	if err := nodeManager.compareRunRequests(expectedRunRequests); err == nil {
+		t.Error("Destination node should not be started before move timeout")
+	}
+
+	time.Sleep(400 * time.Millisecond)
+
 	if err := nodeManager.compareRunRequests(expectedRunRequests); err != nil {
 		t.Errorf("Incorrect run request: %v", err)
 	}
 
-	nodeManager.alertsChannel <- cloudprotocol.SystemQuotaAlert{NodeID: nodeIDLocalSM, Parameter: "cpu"}
+	nodeManager.runStatusChan <- sourceStatus
 
-	expectedRunRequests = map[string]runRequest{
-		nodeIDLocalSM: {
-			services: []aostypes.ServiceInfo{
-				createServiceInfo(service2, 5001, service1LocalURL),
-			},
-			layers: []aostypes.LayerInfo{},
-			instances: []aostypes.InstanceInfo{
-				createInstanceInfo(5001, 2, aostypes.InstanceIdent{
-					ServiceID: service2, SubjectID: subject1, Instance: 0,
-				}, 50),
-			},
-		},
-		nodeIDRemoteSM1: {
-			services: []aostypes.ServiceInfo{
-				createServiceInfo(service1, 5000, service1RemoteURL),
-				createServiceInfo(service3, 5002, service3RemoteURL),
-			},
-			layers: []aostypes.LayerInfo{},
-			instances: []aostypes.InstanceInfo{
-				createInstanceInfo(5000, 4, aostypes.InstanceIdent{
-					ServiceID: service1, SubjectID: subject1, Instance: 0,
-				}, 100),
-				createInstanceInfo(5003, 5, aostypes.InstanceIdent{
-					ServiceID: service3, SubjectID: subject1, Instance: 1,
-				}, 0),
-				createInstanceInfo(5004, 6, aostypes.InstanceIdent{
-					ServiceID: service3, SubjectID: subject1, Instance: 2,
-				}, 0),
-			},
+	if err := waitRunInstancesStatus(launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{
+			createInstanceStatus(instance0, nodeIDLocalSM, nil),
+			createInstanceStatus(instance1, nodeIDRemoteSM1, nil),
 		},
-		nodeIDRemoteSM2: {
-			services: []aostypes.ServiceInfo{
-				createServiceInfo(service3, 5002, service3RemoteURL),
-			},
-			layers: []aostypes.LayerInfo{},
-			instances: []aostypes.InstanceInfo{
-				createInstanceInfo(5002, 3, aostypes.InstanceIdent{
-					ServiceID: service3, SubjectID: subject1, Instance: 0,
-				}, 0),
+	}, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+}
+
+func TestRebalancingAlertsBatch(t *testing.T) {
+	var (
+		cfg = &config.Config{
+			SMController: config.SMController{
+				NodeIDs:                []string{nodeIDLocalSM, nodeIDRemoteSM1},
+				NodesConnectionTimeout: aostypes.Duration{Duration: time.Second},
 			},
-		},
+		}
+		nodeManager     = newTestNodeManager()
+		resourceManager = newTestResourceManager()
+		imageManager    = &testImageProvider{}
+	)
+
+	nodeManager.nodeInformation[nodeIDLocalSM] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDLocalSM, NodeType: nodeTypeLocalSM},
+		RemoteNode: false, RunnerFeature: []string{runnerRunc},
 	}
 
-	expectedRunStatus = unitstatushandler.RunInstancesStatus{
-		Instances: []cloudprotocol.InstanceStatus{
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service1, SubjectID: subject1, Instance: 0,
-			}, nodeIDRemoteSM1, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service2, SubjectID: subject1, Instance: 0,
-			}, nodeIDLocalSM, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service3, SubjectID: subject1, Instance: 1,
-			}, nodeIDRemoteSM1, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service3, SubjectID: subject1, Instance: 2,
-			}, nodeIDRemoteSM1, nil),
-			createInstanceStatus(aostypes.InstanceIdent{
-				ServiceID: service3, SubjectID: subject1, Instance: 0,
-			}, nodeIDRemoteSM2, nil),
+	nodeManager.nodeInformation[nodeIDRemoteSM1] = launcher.NodeInfo{
+		NodeInfo:   cloudprotocol.NodeInfo{NodeID: nodeIDRemoteSM1, NodeType: nodeTypeRemoteSM},
+		RemoteNode: true, RunnerFeature: []string{runnerRunc},
+	}
+
+	resourceManager.nodeResources[nodeTypeLocalSM] = aostypes.NodeUnitConfig{Priority: 100, NodeType: nodeTypeLocalSM}
+	resourceManager.nodeResources[nodeTypeRemoteSM] = aostypes.NodeUnitConfig{Priority: 50, NodeType: nodeTypeRemoteSM}
+
+	imageManager.services = map[string]imagemanager.ServiceInfo{
+		service1: {
+			ServiceInfo: createServiceInfo(service1, 5000, service1LocalURL),
+			RemoteURL:   service1RemoteURL,
+			Config:      aostypes.ServiceConfig{Runner: runnerRunc},
 		},
 	}
 
+	launcherInstance, err := launcher.New(cfg, newTestStorage(), nodeManager, imageManager, resourceManager,
+		&testStateStorage{}, newTestNetworkManager("172.17.0.1/16"))
+	if err != nil {
+		t.Fatalf("Can't create launcher %v", err)
+	}
+	defer launcherInstance.Close()
+
+	for nodeID, info := range nodeManager.nodeInformation {
+		nodeManager.runStatusChan <- launcher.NodeRunInstanceStatus{
+			NodeID: nodeID, NodeType: info.NodeType, Instances: []cloudprotocol.InstanceStatus{},
+		}
+	}
+
 	if err := waitRunInstancesStatus(
-		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		launcherInstance.GetRunStatusesChannel(), unitstatushandler.RunInstancesStatus{}, time.Second); err != nil {
 		t.Errorf("Incorrect run status: %v", err)
 	}
 
-	if err := nodeManager.compareRunRequests(expectedRunRequests); err != nil {
-		t.Errorf("incorrect run request: %v", err)
+	if err := launcherInstance.RunInstances([]cloudprotocol.InstanceInfo{
+		{ServiceID: service1, SubjectID: subject1, Priority: 100, NumInstances: 4},
+	}, []string{}, ""); err != nil {
+		t.Fatalf("Can't run instances %v", err)
 	}
 
-	nodeManager.alertsChannel <- cloudprotocol.SystemQuotaAlert{NodeID: nodeIDRemoteSM2, Parameter: "cpu"}
+	expectedRunStatus := unitstatushandler.RunInstancesStatus{}
+
+	for i := uint64(0); i < 4; i++ {
+		expectedRunStatus.Instances = append(expectedRunStatus.Instances, createInstanceStatus(
+			aostypes.InstanceIdent{ServiceID: service1, SubjectID: subject1, Instance: i}, nodeIDLocalSM, nil))
+	}
 
 	if err := waitRunInstancesStatus(
-		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err == nil ||
-		!strings.Contains(err.Error(), "message timeout") {
-		t.Error("Timeout expected")
+		launcherInstance.GetRunStatusesChannel(), expectedRunStatus, time.Second); err != nil {
+		t.Errorf("Incorrect run status: %v", err)
+	}
+
+	// Alerts storm should result in single rebalancing
+
+	for i := 0; i < 10; i++ {
+		nodeManager.alertsChannel <- cloudprotocol.SystemQuotaAlert{
+			NodeID: nodeIDLocalSM, Parameter: "cpu", Value: uint64(90 + i),
+		}
+	}
+
+	movedInstances := 0
+	timeout := time.After(2 * time.Second)
+
+eventLoop:
+	for {
+		select {
+		case event := <-launcherInstance.GetInstanceEventsChannel():
+			if event.EventType == launcher.InstanceEventMoved {
+				movedInstances++
+			}
+
+		case <-timeout:
+			break eventLoop
+		}
+	}
+
+	if movedInstances != 1 {
+		t.Errorf("Wrong moved instances count: %d", movedInstances)
 	}
 }
 
@@ -1498,7 +5935,7 @@ func TestRebalancingSameNodePriority(t *testing.T) {
 		},
 	}
 
-	if err := launcherInstance.RunInstances(desiredInstances, []string{}); err != nil {
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
 		t.Fatalf("Can't run instances %v", err)
 	}
 
@@ -1733,7 +6170,7 @@ func TestRebalancingAfterRestart(t *testing.T) {
 		},
 	}
 
-	if err := launcherInstance.RunInstances(desiredInstances, []string{}); err != nil {
+	if err := launcherInstance.RunInstances(desiredInstances, []string{}, ""); err != nil {
 		t.Fatalf("Can't run instances %v", err)
 	}
 
@@ -1831,6 +6268,7 @@ func TestRebalancingAfterRestart(t *testing.T) {
 func newTestNodeManager() *testNodeManager {
 	nodeManager := &testNodeManager{
 		runStatusChan:   make(chan launcher.NodeRunInstanceStatus, 10),
+		heldStatusChan:  make(chan launcher.NodeRunInstanceStatus, 10),
 		nodeInformation: make(map[string]launcher.NodeInfo),
 		runRequest:      make(map[string]runRequest),
 		alertsChannel:   make(chan cloudprotocol.SystemQuotaAlert, 10),
@@ -1853,10 +6291,15 @@ func (nodeManager *testNodeManager) GetNodeConfiguration(nodeID string) (launche
 func (nodeManager *testNodeManager) RunInstances(nodeID string,
 	services []aostypes.ServiceInfo, layers []aostypes.LayerInfo, instances []aostypes.InstanceInfo, forceRestart bool,
 ) error {
+	nodeManager.Lock()
+
 	nodeManager.runRequest[nodeID] = runRequest{
 		services: services, layers: layers, instances: instances,
 		forceRestart: forceRestart,
 	}
+	nodeManager.runRequestCount++
+
+	nodeManager.Unlock()
 
 	successStatus := launcher.NodeRunInstanceStatus{
 		NodeID:    nodeID,
@@ -1871,6 +6314,13 @@ func (nodeManager *testNodeManager) RunInstances(nodeID string,
 		}
 	}
 
+	// Status of held node is released by test
+	if nodeID == nodeManager.holdStatusNodeID {
+		nodeManager.heldStatusChan <- successStatus
+
+		return nil
+	}
+
 	nodeManager.runStatusChan <- successStatus
 
 	return nil
@@ -1892,7 +6342,33 @@ func (nodeManager *testNodeManager) GetNodeMonitoringData(nodeID string) (cloudp
 	return cloudprotocol.NodeMonitoringData{}, nil
 }
 
+func (nodeManager *testProbeNodeManager) CheckInstanceReadiness(
+	ctx context.Context, nodeID string, instanceIdent aostypes.InstanceIdent, probe aostypes.ServiceReadinessProbe,
+) (bool, error) {
+	nodeManager.Lock()
+	defer nodeManager.Unlock()
+
+	return nodeManager.ready, nil
+}
+
+func (nodeManager *testProbeNodeManager) setReady(ready bool) {
+	nodeManager.Lock()
+	defer nodeManager.Unlock()
+
+	nodeManager.ready = ready
+}
+
+func (nodeManager *testNodeManager) getRunRequest(nodeID string) runRequest {
+	nodeManager.Lock()
+	defer nodeManager.Unlock()
+
+	return nodeManager.runRequest[nodeID]
+}
+
 func (nodeManager *testNodeManager) compareRunRequests(expectedRunRequests map[string]runRequest) error {
+	nodeManager.Lock()
+	defer nodeManager.Unlock()
+
 	for nodeID, runRequest := range nodeManager.runRequest {
 		if err := deepSlicesCompare(expectedRunRequests[nodeID].services, runRequest.services); err != nil {
 			return aoserrors.Errorf("incorrect services for node %s: %v", nodeID, err)
@@ -1924,11 +6400,31 @@ func newTestResourceManager() *testResourceManager {
 	return resourceManager
 }
 
-func (resourceManager *testResourceManager) GetUnitConfiguration(nodeType string) aostypes.NodeUnitConfig {
-	resource := resourceManager.nodeResources[nodeType]
+func (resourceManager *testResourceManager) setUnavailable(unavailable bool) {
+	resourceManager.Lock()
+	defer resourceManager.Unlock()
+
+	resourceManager.unavailable = unavailable
+}
+
+func (resourceManager *testResourceManager) GetUnitConfiguration(
+	nodeType string,
+) (aostypes.NodeUnitConfig, error) {
+	resourceManager.Lock()
+	defer resourceManager.Unlock()
+
+	if resourceManager.unavailable {
+		return aostypes.NodeUnitConfig{}, aoserrors.New("unit config is not available")
+	}
+
+	resource, ok := resourceManager.nodeResources[nodeType]
+	if !ok {
+		return aostypes.NodeUnitConfig{}, nil
+	}
+
 	resource.NodeType = nodeType
 
-	return resource
+	return resource, nil
 }
 
 // testStorage
@@ -1942,6 +6438,9 @@ func newTestStorage() *testStorage {
 }
 
 func (storage *testStorage) AddInstance(instanceInfo launcher.InstanceInfo) error {
+	storage.Lock()
+	defer storage.Unlock()
+
 	for _, uid := range storage.instanceInfo {
 		if uid.InstanceIdent == instanceInfo.InstanceIdent {
 			return aoserrors.New("uid for instance already exist")
@@ -1954,6 +6453,9 @@ func (storage *testStorage) AddInstance(instanceInfo launcher.InstanceInfo) erro
 }
 
 func (storage *testStorage) GetInstanceUID(instance aostypes.InstanceIdent) (int, error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	for _, instanceInfo := range storage.instanceInfo {
 		if instanceInfo.InstanceIdent == instance {
 			return instanceInfo.UID, nil
@@ -1964,6 +6466,9 @@ func (storage *testStorage) GetInstanceUID(instance aostypes.InstanceIdent) (int
 }
 
 func (storage *testStorage) GetInstances() ([]launcher.InstanceInfo, error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	instances := make([]launcher.InstanceInfo, len(storage.instanceInfo))
 	copy(instances, storage.instanceInfo)
 
@@ -1971,6 +6476,9 @@ func (storage *testStorage) GetInstances() ([]launcher.InstanceInfo, error) {
 }
 
 func (storage *testStorage) RemoveInstance(instanceIdent aostypes.InstanceIdent) error {
+	storage.Lock()
+	defer storage.Unlock()
+
 	for i, instanceInfo := range storage.instanceInfo {
 		if instanceInfo.InstanceIdent == instanceIdent {
 			storage.instanceInfo = append(storage.instanceInfo[:i], storage.instanceInfo[i+1:]...)
@@ -1983,11 +6491,31 @@ func (storage *testStorage) RemoveInstance(instanceIdent aostypes.InstanceIdent)
 }
 
 func (storage *testStorage) SetDesiredInstances(instances json.RawMessage) error {
+	storage.Lock()
+	defer storage.Unlock()
+
+	if storage.desiredInstancesFailures > 0 {
+		storage.desiredInstancesFailures--
+
+		return errors.New("storage is not available")
+	}
+
 	storage.desiredInstances = instances
+
 	return nil
 }
 
+func (storage *testStorage) setDesiredInstancesFailures(failures int) {
+	storage.Lock()
+	defer storage.Unlock()
+
+	storage.desiredInstancesFailures = failures
+}
+
 func (storage *testStorage) SetInstanceCached(instance aostypes.InstanceIdent, cached bool) error {
+	storage.Lock()
+	defer storage.Unlock()
+
 	for i, instanceInfo := range storage.instanceInfo {
 		if instanceInfo.InstanceIdent == instance {
 			storage.instanceInfo[i].Cached = cached
@@ -2000,16 +6528,25 @@ func (storage *testStorage) SetInstanceCached(instance aostypes.InstanceIdent, c
 }
 
 func (storage *testStorage) GetDesiredInstances() (instances json.RawMessage, err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	return storage.desiredInstances, nil
 }
 
 func (storage *testStorage) SetNodeState(nodeID string, runRequest json.RawMessage) error {
+	storage.Lock()
+	defer storage.Unlock()
+
 	storage.nodeState[nodeID] = runRequest
 
 	return nil
 }
 
 func (storage *testStorage) GetNodeState(nodeID string) (json.RawMessage, error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	runRequestJSON, ok := storage.nodeState[nodeID]
 	if !ok {
 		return nil, launcher.ErrNotExist
@@ -2036,21 +6573,52 @@ func (provider *testStateStorage) Setup(
 }
 
 func (provider *testStateStorage) Cleanup(instanceIdent aostypes.InstanceIdent) error {
+	provider.Lock()
+	defer provider.Unlock()
+
 	provider.cleanedInstances = append(provider.cleanedInstances, instanceIdent)
 
 	return nil
 }
 
+func (provider *testStateStorage) getCleanedInstances() []aostypes.InstanceIdent {
+	provider.Lock()
+	defer provider.Unlock()
+
+	return provider.cleanedInstances
+}
+
 func (provider *testStateStorage) GetInstanceCheckSum(instance aostypes.InstanceIdent) string {
 	return magicSum
 }
 
+func (provider *testStateStorage) CheckStateIntegrity(instance aostypes.InstanceIdent) error {
+	provider.Lock()
+	defer provider.Unlock()
+
+	if slices.Contains(provider.corruptedInstances, instance) {
+		return aoserrors.Wrap(storagestate.ErrStateCorrupted)
+	}
+
+	return nil
+}
+
 func (provider *testStateStorage) RemoveServiceInstance(instanceIdent aostypes.InstanceIdent) error {
+	provider.Lock()
+	defer provider.Unlock()
+
 	provider.removedInstances = append(provider.removedInstances, instanceIdent)
 
 	return nil
 }
 
+func (provider *testStateStorage) getRemovedInstances() []aostypes.InstanceIdent {
+	provider.Lock()
+	defer provider.Unlock()
+
+	return provider.removedInstances
+}
+
 // testImageProvider
 
 func newTestImageProvider() *testImageProvider {
@@ -2085,6 +6653,16 @@ func (testProvider *testImageProvider) GetRemoveServiceChannel() (channel <-chan
 	return testProvider.removeServiceInstancesChannel
 }
 
+func (testProvider *testImageProvider) IsLayerInstalling(digest string) bool {
+	for _, installingDigest := range testProvider.installingLayers {
+		if installingDigest == digest {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (testProvider *testImageProvider) RevertService(serviceID string) error {
 	testProvider.revertedServices = append(testProvider.revertedServices, serviceID)
 
@@ -2119,6 +6697,14 @@ func (network *testNetworkManager) PrepareInstanceNetworkParameters(
 	instanceIdent aostypes.InstanceIdent, networkID string,
 	params networkmanager.NetworkParameters,
 ) (aostypes.NetworkParameters, error) {
+	if count, ok := network.failPrepare[instanceIdent]; ok {
+		if count == 0 {
+			return aostypes.NetworkParameters{}, errors.New("can't prepare network")
+		}
+
+		network.failPrepare[instanceIdent] = count - 1
+	}
+
 	if len(network.networkInfo[networkID]) == 0 {
 		network.networkInfo[networkID] = make(map[aostypes.InstanceIdent]struct{})
 	}
@@ -2188,6 +6774,12 @@ func createInstanceStatus(ident aostypes.InstanceIdent, nodeID string, err error
 		status.ErrorInfo = &cloudprotocol.ErrorInfo{
 			Message: err.Error(),
 		}
+
+		var codedErr *testCodedError
+
+		if errors.As(err, &codedErr) {
+			status.ErrorInfo.ErrorCode = codedErr.code
+		}
 	} else {
 		status.StateChecksum = magicSum
 	}
@@ -2195,6 +6787,22 @@ func createInstanceStatus(ident aostypes.InstanceIdent, nodeID string, err error
 	return status
 }
 
+func withAppliedQuotas(
+	status cloudprotocol.InstanceStatus, quotas *aostypes.ServiceQuotas,
+) cloudprotocol.InstanceStatus {
+	status.AppliedQuotas = quotas
+
+	return status
+}
+
+func newCodedError(code, message string) error {
+	return &testCodedError{code: code, message: message}
+}
+
+func (err *testCodedError) Error() string {
+	return err.message
+}
+
 func createInstanceInfo(uid uint32, ip int, ident aostypes.InstanceIdent, priority uint64) aostypes.InstanceInfo {
 	return aostypes.InstanceInfo{
 		InstanceIdent: ident,
@@ -2208,6 +6816,36 @@ func createInstanceInfo(uid uint32, ip int, ident aostypes.InstanceIdent, priori
 	}
 }
 
+func waitStoredDesiredInstances(
+	storage *testStorage, expectedInstances []cloudprotocol.InstanceInfo, timeout time.Duration,
+) (err error) {
+	var storedInstances []cloudprotocol.InstanceInfo
+
+	for start := time.Now(); time.Since(start) < timeout; time.Sleep(10 * time.Millisecond) {
+		rawInstances, _ := storage.GetDesiredInstances()
+
+		if err = json.Unmarshal(rawInstances, &storedInstances); err != nil {
+			return aoserrors.Wrap(err)
+		}
+
+		if reflect.DeepEqual(storedInstances, expectedInstances) {
+			return nil
+		}
+	}
+
+	return aoserrors.Errorf("wrong stored instances: %v", storedInstances)
+}
+
+func waitHealthError(launcherInstance *launcher.Launcher, expectError bool, timeout time.Duration) (err error) {
+	for start := time.Now(); time.Since(start) < timeout; time.Sleep(10 * time.Millisecond) {
+		if err = launcherInstance.GetHealthError(); (err != nil) == expectError {
+			return nil
+		}
+	}
+
+	return aoserrors.Errorf("unexpected health error: %v", err)
+}
+
 func waitRunInstancesStatus(
 	messageChannel <-chan unitstatushandler.RunInstancesStatus, expectedMsg unitstatushandler.RunInstancesStatus,
 	timeout time.Duration,
@@ -2237,6 +6875,8 @@ func waitRunInstancesStatus(
 				if receivedEl.ErrorInfo != nil && expectedEl.ErrorInfo != nil {
 					if receivedEl.ErrorInfo.AosCode != expectedEl.ErrorInfo.AosCode ||
 						receivedEl.ErrorInfo.ExitCode != expectedEl.ErrorInfo.ExitCode ||
+						(expectedEl.ErrorInfo.ErrorCode != "" &&
+							receivedEl.ErrorInfo.ErrorCode != expectedEl.ErrorInfo.ErrorCode) ||
 						!strings.Contains(receivedEl.ErrorInfo.Message, expectedEl.ErrorInfo.Message) {
 						continue
 					}
@@ -2259,6 +6899,10 @@ func waitRunInstancesStatus(
 			return aoserrors.New("incorrect subjects in run status")
 		}
 
+		if expectedMsg.CorrelationID != "" && message.CorrelationID != expectedMsg.CorrelationID {
+			return aoserrors.New("incorrect correlation ID in run status")
+		}
+
 		for i := range message.ErrorServices {
 			message.ErrorServices[i].ErrorInfo = nil
 		}
@@ -2271,6 +6915,44 @@ func waitRunInstancesStatus(
 	}
 }
 
+func waitInstanceEvent(
+	eventChannel <-chan launcher.InstanceEvent, expectedEvent launcher.InstanceEvent, timeout time.Duration,
+) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return aoserrors.New("wait event timeout")
+
+		case event := <-eventChannel:
+			if event == expectedEvent {
+				return nil
+			}
+		}
+	}
+}
+
+func waitNodeEvent(
+	eventChannel <-chan launcher.NodeEvent, expectedEvent launcher.NodeEvent, timeout time.Duration,
+) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return aoserrors.New("wait event timeout")
+
+		case event := <-eventChannel:
+			if event == expectedEvent {
+				return nil
+			}
+		}
+	}
+}
+
 func deepSlicesCompare[T any](sliceA, sliceB []T) error {
 	if len(sliceA) != len(sliceB) {
 		return aoserrors.New("incorrect length")
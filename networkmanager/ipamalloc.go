@@ -219,13 +219,17 @@ func (ipam *ipSubnet) removeAllocatedSubnets(networks []NetworkInfo, networkInst
 	}
 }
 
+func getSubnetIPCount(ipNet *net.IPNet) uint64 {
+	// Three is subtracted because the first address is zero, the second address is reserved for the network address,
+	// and the last address is reserved for the broadcast address.
+	return cidr.AddressCount(ipNet) - 3
+}
+
 func generateSubnetIPs(ipNet *net.IPNet) []net.IP {
 	var (
 		minIPRange, _ = cidr.AddressRange(ipNet)
 
-		// Three is subtracted because the first address is zero, the second address is reserved for the network address,
-		// and the last address is reserved for the broadcast address.
-		addressCount = cidr.AddressCount(ipNet) - 3
+		addressCount = getSubnetIPCount(ipNet)
 
 		ips = make([]net.IP, addressCount)
 
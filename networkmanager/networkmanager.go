@@ -95,6 +95,13 @@ type InstanceNetworkInfo struct {
 	Rules []FirewallRule
 }
 
+// SubnetStat represents IP allocation statistic of network subnet.
+type SubnetStat struct {
+	Total     uint64
+	Allocated uint64
+	Free      uint64
+}
+
 // NetworkParameters represents network parameters.
 type NetworkParameters struct {
 	Hosts            []string
@@ -216,6 +223,46 @@ func (manager *NetworkManager) GetInstances() []aostypes.InstanceIdent {
 	return instances
 }
 
+// GetSubnetStats returns IP allocation statistic per network.
+func (manager *NetworkManager) GetSubnetStats() map[string]SubnetStat {
+	manager.RLock()
+	defer manager.RUnlock()
+
+	stats := make(map[string]SubnetStat)
+
+	for networkID, networkParameters := range manager.providerNetworks {
+		stats[networkID] = SubnetStat{Total: getSubnetSize(networkParameters.Subnet)}
+	}
+
+	for networkID, instances := range manager.instancesData {
+		if len(instances) == 0 {
+			continue
+		}
+
+		stat := stats[networkID]
+
+		for _, instanceNetworkInfo := range instances {
+			if stat.Total == 0 {
+				stat.Total = getSubnetSize(instanceNetworkInfo.Subnet)
+			}
+
+			stat.Allocated++
+		}
+
+		stats[networkID] = stat
+	}
+
+	for networkID, stat := range stats {
+		if stat.Total > stat.Allocated {
+			stat.Free = stat.Total - stat.Allocated
+		}
+
+		stats[networkID] = stat
+	}
+
+	return stats
+}
+
 // UpdateProviderNetwork updates provider network.
 func (manager *NetworkManager) UpdateProviderNetwork(providers []string, nodeID string) error {
 	manager.Lock()
@@ -506,6 +553,17 @@ func (manager *NetworkManager) getVlanID(networkID string) (uint64, error) {
 	return vlanID.Uint64() + 1, nil
 }
 
+func getSubnetSize(subnet string) uint64 {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		log.Errorf("Failed to parse subnet %s: %v", subnet, err)
+
+		return 0
+	}
+
+	return getSubnetIPCount(ipNet)
+}
+
 func parseAllowConnection(connection string) (serviceID, port, protocol string, err error) {
 	connConf := strings.Split(connection, "/")
 	if len(connConf) > allowedConnectionsExpectedLen || len(connConf) < 2 {
@@ -504,6 +504,68 @@ func TestNetworkUpdates(t *testing.T) {
 	}
 }
 
+func TestSubnetStats(t *testing.T) {
+	ipam, err := newIpam()
+	if err != nil {
+		t.Fatalf("Can't init ipam management: %v", err)
+	}
+
+	networkmanager.GetIPSubnet = ipam.getIPSubnet
+	networkmanager.LookPath = lookPath
+	networkmanager.DiscoverInterface = discoverInterface
+	networkmanager.ExecContext = newTestShellCommander
+
+	storage := &testStore{
+		networkInfos: make(map[aostypes.InstanceIdent]networkmanager.InstanceNetworkInfo),
+	}
+
+	manager, err := networkmanager.New(storage, nil, &config.Config{
+		WorkingDir: tmpDir,
+	})
+	if err != nil {
+		t.Fatalf("Can't create network manager: %v", err)
+	}
+
+	instances := map[string][]aostypes.InstanceIdent{
+		"network1": {
+			{ServiceID: "service1", SubjectID: "subject1", Instance: 0},
+			{ServiceID: "service1", SubjectID: "subject1", Instance: 1},
+			{ServiceID: "service2", SubjectID: "subject1", Instance: 0},
+		},
+		"network2": {
+			{ServiceID: "service3", SubjectID: "subject1", Instance: 0},
+			{ServiceID: "service3", SubjectID: "subject1", Instance: 1},
+		},
+	}
+
+	for networkID, instanceIdents := range instances {
+		for _, instanceIdent := range instanceIdents {
+			if _, err := manager.PrepareInstanceNetworkParameters(
+				instanceIdent, networkID, networkmanager.NetworkParameters{}); err != nil {
+				t.Fatalf("Can't prepare instance network configuration: %v", err)
+			}
+		}
+	}
+
+	// 172.17.0.0/16 and 172.18.0.0/16 subnets without zero, network and broadcast addresses
+	expectedStats := map[string]networkmanager.SubnetStat{
+		"network1": {Total: 65533, Allocated: 3, Free: 65530},
+		"network2": {Total: 65533, Allocated: 2, Free: 65531},
+	}
+
+	if stats := manager.GetSubnetStats(); !reflect.DeepEqual(stats, expectedStats) {
+		t.Errorf("Unexpected subnet stats: %v", stats)
+	}
+
+	manager.RemoveInstanceNetworkParameters(instances["network1"][0], "network1")
+
+	expectedStats["network1"] = networkmanager.SubnetStat{Total: 65533, Allocated: 2, Free: 65531}
+
+	if stats := manager.GetSubnetStats(); !reflect.DeepEqual(stats, expectedStats) {
+		t.Errorf("Unexpected subnet stats: %v", stats)
+	}
+}
+
 /***********************************************************************************************************************
  * Interfaces
  **********************************************************************************************************************/
@@ -18,6 +18,7 @@
 package smcontroller
 
 import (
+	"context"
 	"net"
 	"sync"
 
@@ -61,6 +62,7 @@ type Controller struct {
 	systemLimitAlertChan      chan cloudprotocol.SystemQuotaAlert
 
 	isCloudConnected bool
+	observerMode     bool
 	grpcServer       *grpc.Server
 	listener         net.Listener
 	pb.UnimplementedSMServiceServer
@@ -109,6 +111,7 @@ func New(
 		updateInstancesStatusChan: make(chan []cloudprotocol.InstanceStatus, statusChanSize),
 		systemLimitAlertChan:      make(chan cloudprotocol.SystemQuotaAlert, statusChanSize),
 		nodes:                     make(map[string]*smHandler),
+		observerMode:              cfg.ObserverMode,
 	}
 
 	if controller.messageSender != nil {
@@ -237,6 +240,10 @@ func (controller *Controller) SetUnitConfig(unitConfig aostypes.UnitConfig) erro
 func (controller *Controller) RunInstances(nodeID string,
 	services []aostypes.ServiceInfo, layers []aostypes.LayerInfo, instances []aostypes.InstanceInfo, forceRestart bool,
 ) error {
+	if controller.observerMode {
+		return aoserrors.New("run instances is not allowed in observer mode")
+	}
+
 	handler, err := controller.getNodeHandlerByID(nodeID)
 	if err != nil {
 		return err
@@ -289,6 +296,18 @@ func (controller *Controller) GetNodeMonitoringData(nodeID string) (data cloudpr
 	return handler.getNodeMonitoring()
 }
 
+// CheckInstanceReadiness requests SM to perform instance readiness probe.
+func (controller *Controller) CheckInstanceReadiness(
+	ctx context.Context, nodeID string, instanceIdent aostypes.InstanceIdent, probe aostypes.ServiceReadinessProbe,
+) (ready bool, err error) {
+	handler, err := controller.getNodeHandlerByID(nodeID)
+	if err != nil {
+		return false, err
+	}
+
+	return handler.checkInstanceReadiness(ctx, instanceIdent, probe)
+}
+
 // GetUpdateInstancesStatusChannel returns channel with update instances status.
 func (controller *Controller) GetUpdateInstancesStatusChannel() <-chan []cloudprotocol.InstanceStatus {
 	return controller.updateInstancesStatusChan
@@ -772,6 +772,22 @@ func TestLogMessages(t *testing.T) {
 				},
 			}},
 		},
+		{
+			sendLogRequest: cloudprotocol.RequestLog{
+				LogID:   "serviceLogID3",
+				LogType: cloudprotocol.ServiceLog,
+				Filter: cloudprotocol.LogFilter{
+					InstanceFilter: cloudprotocol.NewInstanceFilter("ser1", "s1", 2),
+					From:           &currentTime,
+				},
+			},
+			expectedLogRequest: &pb.SMIncomingMessages{SMIncomingMessage: &pb.SMIncomingMessages_InstanceLogRequest{
+				InstanceLogRequest: &pb.InstanceLogRequest{
+					Instance: &pb.InstanceIdent{ServiceId: "ser1", SubjectId: "s1", Instance: 2},
+					LogId:    "serviceLogID3", From: timestamppb.New(currentTime),
+				},
+			}},
+		},
 		{
 			sendLogRequest: cloudprotocol.RequestLog{
 				LogID:   "serviceLogID1",
@@ -820,6 +836,22 @@ func TestLogMessages(t *testing.T) {
 				},
 			}},
 		},
+		{
+			sendLogRequest: cloudprotocol.RequestLog{
+				LogID:   "serviceLogID3",
+				LogType: cloudprotocol.CrashLog,
+				Filter: cloudprotocol.LogFilter{
+					InstanceFilter: cloudprotocol.NewInstanceFilter("ser2", "s2", 0),
+					Till:           &currentTime,
+				},
+			},
+			expectedLogRequest: &pb.SMIncomingMessages{SMIncomingMessage: &pb.SMIncomingMessages_InstanceCrashLogRequest{
+				InstanceCrashLogRequest: &pb.InstanceCrashLogRequest{
+					Instance: &pb.InstanceIdent{ServiceId: "ser2", SubjectId: "s2", Instance: 0},
+					LogId:    "serviceLogID3", Till: timestamppb.New(currentTime),
+				},
+			}},
+		},
 	}
 
 	for _, request := range testRequests {
@@ -966,7 +998,7 @@ func TestRunInstances(t *testing.T) {
 					{
 						VersionInfo: &pb.VersionInfo{AosVersion: 2, VendorVersion: "3", Description: "desc2"},
 						Url:         "url2", LayerId: "l1", Digest: "digest1", Sha256: []byte{0, 0, 0, byte(100)},
-						Sha512: []byte{byte(200), 0, 0, 0}, Size: uint64(500),
+						Sha512: []byte{byte(200), 0, 0, 0}, Size: uint64(500), Delivery: "push",
 					},
 				},
 				Instances: []*pb.InstanceInfo{
@@ -974,6 +1006,7 @@ func TestRunInstances(t *testing.T) {
 						Instance:          &pb.InstanceIdent{ServiceId: "s1", SubjectId: "subj1", Instance: 1},
 						NetworkParameters: &pb.NetworkParameters{Ip: "172.17.0.3", Subnet: "172.17.0.0/16", VlanId: 1},
 						Uid:               500, Priority: 1, StoragePath: "storage1", StatePath: "state1",
+						Cpus: []uint32{1, 2}, Env: []string{"VAR1=value1"}, Args: []string{"--arg1"},
 					},
 				},
 			},
@@ -986,12 +1019,13 @@ func TestRunInstances(t *testing.T) {
 		sendLayers = []aostypes.LayerInfo{{
 			VersionInfo: aostypes.VersionInfo{AosVersion: 2, VendorVersion: "3", Description: "desc2"},
 			URL:         "url2", ID: "l1", Digest: "digest1", Sha256: []byte{0, 0, 0, byte(100)},
-			Sha512: []byte{byte(200), 0, 0, 0}, Size: uint64(500),
+			Sha512: []byte{byte(200), 0, 0, 0}, Size: uint64(500), Delivery: "push",
 		}}
 		sendInstances = []aostypes.InstanceInfo{{
 			InstanceIdent:     aostypes.InstanceIdent{ServiceID: "s1", SubjectID: "subj1", Instance: 1},
 			NetworkParameters: aostypes.NetworkParameters{IP: "172.17.0.3", Subnet: "172.17.0.0/16", VlanID: 1},
 			UID:               500, Priority: 1, StoragePath: "storage1", StatePath: "state1",
+			CPUs: []int{1, 2}, Env: []string{"VAR1=value1"}, Args: []string{"--arg1"},
 		}}
 	)
 
@@ -1289,6 +1323,91 @@ func TestGetNodeMonitoringData(t *testing.T) {
 	<-testWaitChan
 }
 
+func TestCheckInstanceReadiness(t *testing.T) {
+	var (
+		nodeID        = "mainSM"
+		messageSender = newTestMessageSender()
+		nodeConfig    = &pb.NodeConfiguration{NodeId: nodeID, RemoteNode: true, RunnerFeatures: []string{"runc"}}
+		config        = config.Config{
+			SMController: config.SMController{
+				CMServerURL: cmServerURL,
+				NodeIDs:     []string{nodeID},
+			},
+		}
+		testWaitChan  = make(chan struct{})
+		instanceIdent = aostypes.InstanceIdent{ServiceID: "service1", SubjectID: "s1", Instance: 1}
+		probe         = aostypes.ServiceReadinessProbe{HTTPGet: "http://localhost:8080/ready"}
+	)
+
+	controller, err := smcontroller.New(&config, messageSender, nil, nil, nil, nil, true)
+	if err != nil {
+		t.Fatalf("Can't create SM controller: %v", err)
+	}
+	defer controller.Close()
+
+	smClient, err := newTestSMClient(cmServerURL, nodeConfig, &pb.RunInstancesStatus{})
+	if err != nil {
+		t.Fatalf("Can't create test SM: %v", err)
+	}
+
+	defer smClient.close()
+
+	if err := waitMessage(controller.GetRunInstancesStatusChannel(), launcher.NodeRunInstanceStatus{
+		NodeID: nodeID, Instances: make([]cloudprotocol.InstanceStatus, 0),
+	}, messageTimeout); err != nil {
+		t.Fatalf("Wait message error: %v", err)
+	}
+
+	if err := smClient.waitMessage(&pb.SMIncomingMessages{SMIncomingMessage: &pb.SMIncomingMessages_ConnectionStatus{
+		ConnectionStatus: &pb.ConnectionStatus{},
+	}}, messageTimeout); err != nil {
+		t.Fatalf("Wait message error: %v", err)
+	}
+
+	go func() {
+		ready, err := controller.CheckInstanceReadiness(context.Background(), nodeID, instanceIdent, probe)
+		if err != nil {
+			t.Errorf("Can't check instance readiness: %v", err)
+		}
+
+		if !ready {
+			t.Error("Instance should be ready")
+		}
+
+		testWaitChan <- struct{}{}
+	}()
+
+	if err := smClient.waitMessage(&pb.SMIncomingMessages{
+		SMIncomingMessage: &pb.SMIncomingMessages_CheckInstanceReadiness{
+			CheckInstanceReadiness: &pb.CheckInstanceReadiness{
+				Instance: &pb.InstanceIdent{ServiceId: "service1", SubjectId: "s1", Instance: 1},
+				HttpGet:  "http://localhost:8080/ready",
+			},
+		},
+	}, messageTimeout); err != nil {
+		t.Fatalf("Wait message error: %v", err)
+	}
+
+	if err := smClient.stream.Send(&pb.SMOutgoingMessages{
+		SMOutgoingMessage: &pb.SMOutgoingMessages_InstanceReadiness{InstanceReadiness: &pb.InstanceReadiness{
+			Instance: &pb.InstanceIdent{ServiceId: "service1", SubjectId: "s1", Instance: 1}, Ready: true,
+		}},
+	}); err != nil {
+		t.Errorf("Can't send instance readiness: %v", err)
+	}
+
+	<-testWaitChan
+
+	// Probe is not answered: check should fail on context timeout
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelFunc()
+
+	if _, err := controller.CheckInstanceReadiness(ctx, nodeID, instanceIdent, probe); err == nil {
+		t.Error("Error expected")
+	}
+}
+
 func TestConnectionStatus(t *testing.T) {
 	nodeID := "mainSM"
 	messageSender := newTestMessageSender()
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
@@ -59,6 +60,7 @@ type smHandler struct {
 	runStatusCh            chan<- launcher.NodeRunInstanceStatus
 	updateInstanceStatusCh chan<- []cloudprotocol.InstanceStatus
 	systemLimitAlertCh     chan<- cloudprotocol.SystemQuotaAlert
+	readinessMutex         sync.Mutex
 }
 
 /***********************************************************************************************************************
@@ -219,12 +221,13 @@ func (handler *smHandler) runInstances(
 				VendorVersion: layerInfo.VendorVersion,
 				Description:   layerInfo.Description,
 			},
-			Url:     layerInfo.URL,
-			LayerId: layerInfo.ID,
-			Digest:  layerInfo.Digest,
-			Sha256:  layerInfo.Sha256,
-			Sha512:  layerInfo.Sha512,
-			Size:    layerInfo.Size,
+			Url:      layerInfo.URL,
+			LayerId:  layerInfo.ID,
+			Digest:   layerInfo.Digest,
+			Sha256:   layerInfo.Sha256,
+			Sha512:   layerInfo.Sha512,
+			Size:     layerInfo.Size,
+			Delivery: layerInfo.Delivery,
 		}
 	}
 
@@ -236,6 +239,11 @@ func (handler *smHandler) runInstances(
 			StoragePath:       instanceInfo.StoragePath,
 			StatePath:         instanceInfo.StatePath,
 			NetworkParameters: pbconvert.NetworkParametersToPB(instanceInfo.NetworkParameters),
+			Cpus:              cpusToPB(instanceInfo.CPUs),
+			Env:               instanceInfo.Env,
+			Args:              instanceInfo.Args,
+			CpuLimit:          instanceInfo.CPULimit,
+			RamLimit:          instanceInfo.RAMLimit,
 		}
 	}
 
@@ -393,6 +401,40 @@ func (handler *smHandler) getNodeMonitoring() (data cloudprotocol.NodeMonitoring
 	return data, nil
 }
 
+// checkInstanceReadiness performs instance readiness probe on SM. Probes are serialized as SM response is matched by
+// message type.
+func (handler *smHandler) checkInstanceReadiness(
+	ctx context.Context, instanceIdent aostypes.InstanceIdent, probe aostypes.ServiceReadinessProbe,
+) (ready bool, err error) {
+	handler.readinessMutex.Lock()
+	defer handler.readinessMutex.Unlock()
+
+	ctx, cancelFunc := context.WithTimeout(ctx, waitMessageTimeout)
+	defer cancelFunc()
+
+	response, err := handler.syncstream.Send(ctx, func() error {
+		return handler.sendCheckInstanceReadiness(instanceIdent, probe)
+	}, reflect.TypeOf(&pb.SMOutgoingMessages_InstanceReadiness{}))
+	if err != nil {
+		return false, aoserrors.Wrap(err)
+	}
+
+	pbReadiness, ok := response.(*pb.SMOutgoingMessages_InstanceReadiness)
+	if !ok {
+		return false, aoserrors.New("incorrect type")
+	}
+
+	if pbconvert.NewInstanceIdentFromPB(pbReadiness.InstanceReadiness.GetInstance()) != instanceIdent {
+		return false, aoserrors.New("readiness of unexpected instance received")
+	}
+
+	if pbReadiness.InstanceReadiness.GetError() != "" {
+		return false, aoserrors.New(pbReadiness.InstanceReadiness.GetError())
+	}
+
+	return pbReadiness.InstanceReadiness.GetReady(), nil
+}
+
 /***********************************************************************************************************************
  * Private
  **********************************************************************************************************************/
@@ -647,6 +689,23 @@ func (handler *smHandler) sendSetUnitConfig(cfg aostypes.NodeUnitConfig, vendorV
 	return nil
 }
 
+func (handler *smHandler) sendCheckInstanceReadiness(
+	instanceIdent aostypes.InstanceIdent, probe aostypes.ServiceReadinessProbe,
+) error {
+	if err := handler.stream.Send(
+		&pb.SMIncomingMessages{SMIncomingMessage: &pb.SMIncomingMessages_CheckInstanceReadiness{
+			CheckInstanceReadiness: &pb.CheckInstanceReadiness{
+				Instance: pbconvert.InstanceIdentToPB(instanceIdent),
+				Command:  probe.Command,
+				HttpGet:  probe.HTTPGet,
+			},
+		}}); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
 func (handler *smHandler) sendGetNodeMonitoring() error {
 	if err := handler.stream.Send(
 		&pb.SMIncomingMessages{SMIncomingMessage: &pb.SMIncomingMessages_GetNodeMonitoring{}}); err != nil {
@@ -732,3 +791,17 @@ func monitoringDataFromPb(pbMonitoring *pb.MonitoringData) cloudprotocol.Monitor
 
 	return monitoringData
 }
+
+func cpusToPB(cpus []int) []uint32 {
+	if len(cpus) == 0 {
+		return nil
+	}
+
+	pbCPUs := make([]uint32, len(cpus))
+
+	for i, cpu := range cpus {
+		pbCPUs[i] = uint32(cpu)
+	}
+
+	return pbCPUs
+}
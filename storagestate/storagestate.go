@@ -65,8 +65,9 @@ var (
 
 // ErrNotExist is returned when requested entry not exist in DB.
 var (
-	ErrNotExist = errors.New("entry does not exist")
-	ErrNotFound = errors.New("instance not found")
+	ErrNotExist       = errors.New("entry does not exist")
+	ErrNotFound       = errors.New("instance not found")
+	ErrStateCorrupted = errors.New("state checksum mismatch")
 )
 
 /***********************************************************************************************************************
@@ -125,6 +126,7 @@ type StorageState struct {
 	newStateChannel     chan cloudprotocol.NewState
 	stateRequestChannel chan cloudprotocol.StateRequest
 	isSamePartition     bool
+	verifyIntegrity     bool
 }
 
 type stateParams struct {
@@ -134,6 +136,14 @@ type stateParams struct {
 	checksum           []byte
 	changeTimer        *time.Timer
 	changeTimerChannel chan bool
+	verified           verifiedState
+}
+
+// verifiedState state file attributes at the moment of the last successful integrity check.
+type verifiedState struct {
+	checksum []byte
+	modTime  time.Time
+	size     int64
 }
 
 /***********************************************************************************************************************
@@ -152,6 +162,7 @@ func New(cfg *config.Config, messageSender MessageSender, storage Storage) (stor
 		statesMap:           make(map[aostypes.InstanceIdent]*stateParams),
 		newStateChannel:     make(chan cloudprotocol.NewState, stateChannelSize),
 		stateRequestChannel: make(chan cloudprotocol.StateRequest, stateChannelSize),
+		verifyIntegrity:     cfg.VerifyStateIntegrity,
 	}
 
 	if err = os.MkdirAll(storageState.storageDir, 0o755); err != nil {
@@ -394,10 +405,74 @@ func (storageState *StorageState) GetInstanceCheckSum(instanceIdent aostypes.Ins
 	return string(state.checksum)
 }
 
+// CheckStateIntegrity verifies that instance state file matches its known checksum. State file is rehashed only if
+// it is modified since the last successful check.
+func (storageState *StorageState) CheckStateIntegrity(instanceIdent aostypes.InstanceIdent) error {
+	stateFilePath, expected, ok := storageState.getStateToVerify(instanceIdent)
+	if !ok {
+		return nil
+	}
+
+	// State file is hashed without lock to not block state handling
+	_, checksum, err := getFileDataChecksum(stateFilePath)
+	if err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if !bytes.Equal(checksum, expected.checksum) {
+		return aoserrors.Wrap(ErrStateCorrupted)
+	}
+
+	storageState.Lock()
+	defer storageState.Unlock()
+
+	// Don't cache result if state is changed while hashing
+	if state, ok := storageState.statesMap[instanceIdent]; ok && bytes.Equal(state.checksum, expected.checksum) {
+		state.verified = expected
+	}
+
+	return nil
+}
+
 /***********************************************************************************************************************
  * Private
  **********************************************************************************************************************/
 
+// getStateToVerify returns state file path and its current attributes if the state should be verified.
+func (storageState *StorageState) getStateToVerify(
+	instanceIdent aostypes.InstanceIdent,
+) (stateFilePath string, current verifiedState, ok bool) {
+	storageState.Lock()
+	defer storageState.Unlock()
+
+	if !storageState.verifyIntegrity {
+		return "", current, false
+	}
+
+	state, ok := storageState.statesMap[instanceIdent]
+	if !ok || state.stateFilePath == "" || len(state.checksum) == 0 {
+		return "", current, false
+	}
+
+	// State is being changed by the instance, new checksum is not calculated yet
+	if state.changeTimer != nil {
+		return "", current, false
+	}
+
+	current.checksum = state.checksum
+
+	if fileInfo, err := os.Stat(state.stateFilePath); err == nil {
+		current.modTime, current.size = fileInfo.ModTime(), fileInfo.Size()
+
+		if bytes.Equal(state.verified.checksum, current.checksum) && state.verified.modTime.Equal(current.modTime) &&
+			state.verified.size == current.size {
+			return "", current, false
+		}
+	}
+
+	return state.stateFilePath, current, true
+}
+
 func (storageState *StorageState) prepareState(
 	instanceID string, params SetupParams, checksum []byte,
 ) (stateFilePath string, err error) {
@@ -20,6 +20,7 @@ package storagestate_test
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -1005,6 +1006,76 @@ func TestStateAcceptance(t *testing.T) {
 	}
 }
 
+func TestCheckStateIntegrity(t *testing.T) {
+	storage := testStorageInterface{
+		data: make(map[aostypes.InstanceIdent]storagestate.StorageStateInstanceInfo),
+	}
+
+	messageSender := &testMessageSender{
+		chanNewState:     make(chan cloudprotocol.NewState, 1),
+		chanStateRequest: make(chan cloudprotocol.StateRequest, 2),
+	}
+
+	cfg := &config.Config{StorageDir: storageDir, StateDir: stateDir, VerifyStateIntegrity: true}
+
+	setupParams := storagestate.SetupParams{
+		InstanceIdent: aostypes.InstanceIdent{ServiceID: "service2", SubjectID: "subject1", Instance: 0},
+		UID:           1005, GID: 1005, StateQuota: 2000,
+	}
+
+	instance, err := storagestate.New(cfg, messageSender, &storage)
+	if err != nil {
+		t.Fatalf("Can't create storagestate instance: %v", err)
+	}
+
+	if _, _, err = instance.Setup(setupParams); err != nil {
+		t.Fatalf("Can't setup instance: %v", err)
+	}
+
+	stateData := "valid state"
+	calcSum := sha3.Sum224([]byte(stateData))
+
+	if err = instance.UpdateState(cloudprotocol.UpdateState{
+		InstanceIdent: setupParams.InstanceIdent, State: stateData, Checksum: hex.EncodeToString(calcSum[:]),
+	}); err != nil {
+		t.Fatalf("Can't update state: %v", err)
+	}
+
+	// Wait state file change is processed
+
+	time.Sleep(storagestate.StateChangeTimeout * 2)
+
+	for i := 0; i < 2; i++ {
+		if err = instance.CheckStateIntegrity(setupParams.InstanceIdent); err != nil {
+			t.Errorf("State integrity check failed: %v", err)
+		}
+	}
+
+	instance.Close()
+
+	// State file is changed while CM is not running
+
+	pathToStateFile := path.Join(stateDir, fmt.Sprintf("%s_state.dat",
+		storage.data[setupParams.InstanceIdent].InstanceID))
+
+	if err = os.WriteFile(pathToStateFile, []byte("corrupted state"), 0o600); err != nil {
+		t.Fatalf("Can't write state file: %v", err)
+	}
+
+	if instance, err = storagestate.New(cfg, messageSender, &storage); err != nil {
+		t.Fatalf("Can't create storagestate instance: %v", err)
+	}
+	defer instance.Close()
+
+	if _, _, err = instance.Setup(setupParams); err != nil {
+		t.Fatalf("Can't setup instance: %v", err)
+	}
+
+	if err = instance.CheckStateIntegrity(setupParams.InstanceIdent); !errors.Is(err, storagestate.ErrStateCorrupted) {
+		t.Errorf("State corruption is not detected: %v", err)
+	}
+}
+
 /***********************************************************************************************************************
  * Interfaces
  **********************************************************************************************************************/
@@ -0,0 +1,1071 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: communicationmanager/v2/updatescheduler.proto
+
+package communicationmanager
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UpdateState int32
+
+const (
+	UpdateState_NO_UPDATE       UpdateState = 0
+	UpdateState_DOWNLOADING     UpdateState = 1
+	UpdateState_READY_TO_UPDATE UpdateState = 2
+	UpdateState_UPDATING        UpdateState = 3
+)
+
+// Enum value maps for UpdateState.
+var (
+	UpdateState_name = map[int32]string{
+		0: "NO_UPDATE",
+		1: "DOWNLOADING",
+		2: "READY_TO_UPDATE",
+		3: "UPDATING",
+	}
+	UpdateState_value = map[string]int32{
+		"NO_UPDATE":       0,
+		"DOWNLOADING":     1,
+		"READY_TO_UPDATE": 2,
+		"UPDATING":        3,
+	}
+)
+
+func (x UpdateState) Enum() *UpdateState {
+	p := new(UpdateState)
+	*p = x
+	return p
+}
+
+func (x UpdateState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UpdateState) Descriptor() protoreflect.EnumDescriptor {
+	return file_communicationmanager_v2_updatescheduler_proto_enumTypes[0].Descriptor()
+}
+
+func (UpdateState) Type() protoreflect.EnumType {
+	return &file_communicationmanager_v2_updatescheduler_proto_enumTypes[0]
+}
+
+func (x UpdateState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UpdateState.Descriptor instead.
+func (UpdateState) EnumDescriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{0}
+}
+
+type SchedulerNotifications struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to SchedulerNotification:
+	//	*SchedulerNotifications_SotaStatus
+	//	*SchedulerNotifications_FotaStatus
+	SchedulerNotification isSchedulerNotifications_SchedulerNotification `protobuf_oneof:"SchedulerNotification"`
+}
+
+func (x *SchedulerNotifications) Reset() {
+	*x = SchedulerNotifications{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SchedulerNotifications) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerNotifications) ProtoMessage() {}
+
+func (x *SchedulerNotifications) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerNotifications.ProtoReflect.Descriptor instead.
+func (*SchedulerNotifications) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *SchedulerNotifications) GetSchedulerNotification() isSchedulerNotifications_SchedulerNotification {
+	if m != nil {
+		return m.SchedulerNotification
+	}
+	return nil
+}
+
+func (x *SchedulerNotifications) GetSotaStatus() *UpdateSOTAStatus {
+	if x, ok := x.GetSchedulerNotification().(*SchedulerNotifications_SotaStatus); ok {
+		return x.SotaStatus
+	}
+	return nil
+}
+
+func (x *SchedulerNotifications) GetFotaStatus() *UpdateFOTAStatus {
+	if x, ok := x.GetSchedulerNotification().(*SchedulerNotifications_FotaStatus); ok {
+		return x.FotaStatus
+	}
+	return nil
+}
+
+type isSchedulerNotifications_SchedulerNotification interface {
+	isSchedulerNotifications_SchedulerNotification()
+}
+
+type SchedulerNotifications_SotaStatus struct {
+	SotaStatus *UpdateSOTAStatus `protobuf:"bytes,1,opt,name=sota_status,json=sotaStatus,proto3,oneof"`
+}
+
+type SchedulerNotifications_FotaStatus struct {
+	FotaStatus *UpdateFOTAStatus `protobuf:"bytes,2,opt,name=fota_status,json=fotaStatus,proto3,oneof"`
+}
+
+func (*SchedulerNotifications_SotaStatus) isSchedulerNotifications_SchedulerNotification() {}
+
+func (*SchedulerNotifications_FotaStatus) isSchedulerNotifications_SchedulerNotification() {}
+
+type UpdateFOTAStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State      UpdateState            `protobuf:"varint,1,opt,name=state,proto3,enum=communicationmanager.v2.UpdateState" json:"state,omitempty"`
+	Components []*ComponentInfo       `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
+	UnitConfig *UnitConfigInfo        `protobuf:"bytes,3,opt,name=unit_config,json=unitConfig,proto3" json:"unit_config,omitempty"`
+	Error      string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	NextWindow *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=next_window,json=nextWindow,proto3" json:"next_window,omitempty"`
+	TtlDate    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=ttl_date,json=ttlDate,proto3" json:"ttl_date,omitempty"`
+	Emergency  bool                   `protobuf:"varint,7,opt,name=emergency,proto3" json:"emergency,omitempty"`
+}
+
+func (x *UpdateFOTAStatus) Reset() {
+	*x = UpdateFOTAStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFOTAStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFOTAStatus) ProtoMessage() {}
+
+func (x *UpdateFOTAStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFOTAStatus.ProtoReflect.Descriptor instead.
+func (*UpdateFOTAStatus) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateFOTAStatus) GetState() UpdateState {
+	if x != nil {
+		return x.State
+	}
+	return UpdateState_NO_UPDATE
+}
+
+func (x *UpdateFOTAStatus) GetComponents() []*ComponentInfo {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *UpdateFOTAStatus) GetUnitConfig() *UnitConfigInfo {
+	if x != nil {
+		return x.UnitConfig
+	}
+	return nil
+}
+
+func (x *UpdateFOTAStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *UpdateFOTAStatus) GetNextWindow() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextWindow
+	}
+	return nil
+}
+
+func (x *UpdateFOTAStatus) GetTtlDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TtlDate
+	}
+	return nil
+}
+
+func (x *UpdateFOTAStatus) GetEmergency() bool {
+	if x != nil {
+		return x.Emergency
+	}
+	return false
+}
+
+type UpdateSOTAStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State           UpdateState            `protobuf:"varint,1,opt,name=state,proto3,enum=communicationmanager.v2.UpdateState" json:"state,omitempty"`
+	InstallServices []*ServiceInfo         `protobuf:"bytes,2,rep,name=install_services,json=installServices,proto3" json:"install_services,omitempty"`
+	RemoveServices  []*ServiceInfo         `protobuf:"bytes,3,rep,name=remove_services,json=removeServices,proto3" json:"remove_services,omitempty"`
+	InstallLayers   []*LayerInfo           `protobuf:"bytes,4,rep,name=install_layers,json=installLayers,proto3" json:"install_layers,omitempty"`
+	RemoveLayers    []*LayerInfo           `protobuf:"bytes,5,rep,name=remove_layers,json=removeLayers,proto3" json:"remove_layers,omitempty"`
+	Error           string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	NextWindow      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_window,json=nextWindow,proto3" json:"next_window,omitempty"`
+	TtlDate         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=ttl_date,json=ttlDate,proto3" json:"ttl_date,omitempty"`
+	Emergency       bool                   `protobuf:"varint,9,opt,name=emergency,proto3" json:"emergency,omitempty"`
+}
+
+func (x *UpdateSOTAStatus) Reset() {
+	*x = UpdateSOTAStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateSOTAStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSOTAStatus) ProtoMessage() {}
+
+func (x *UpdateSOTAStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSOTAStatus.ProtoReflect.Descriptor instead.
+func (*UpdateSOTAStatus) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateSOTAStatus) GetState() UpdateState {
+	if x != nil {
+		return x.State
+	}
+	return UpdateState_NO_UPDATE
+}
+
+func (x *UpdateSOTAStatus) GetInstallServices() []*ServiceInfo {
+	if x != nil {
+		return x.InstallServices
+	}
+	return nil
+}
+
+func (x *UpdateSOTAStatus) GetRemoveServices() []*ServiceInfo {
+	if x != nil {
+		return x.RemoveServices
+	}
+	return nil
+}
+
+func (x *UpdateSOTAStatus) GetInstallLayers() []*LayerInfo {
+	if x != nil {
+		return x.InstallLayers
+	}
+	return nil
+}
+
+func (x *UpdateSOTAStatus) GetRemoveLayers() []*LayerInfo {
+	if x != nil {
+		return x.RemoveLayers
+	}
+	return nil
+}
+
+func (x *UpdateSOTAStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *UpdateSOTAStatus) GetNextWindow() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextWindow
+	}
+	return nil
+}
+
+func (x *UpdateSOTAStatus) GetTtlDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TtlDate
+	}
+	return nil
+}
+
+func (x *UpdateSOTAStatus) GetEmergency() bool {
+	if x != nil {
+		return x.Emergency
+	}
+	return false
+}
+
+type ComponentInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AosVersion    uint64 `protobuf:"varint,2,opt,name=aos_version,json=aosVersion,proto3" json:"aos_version,omitempty"`
+	VendorVersion string `protobuf:"bytes,3,opt,name=vendor_version,json=vendorVersion,proto3" json:"vendor_version,omitempty"`
+}
+
+func (x *ComponentInfo) Reset() {
+	*x = ComponentInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComponentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentInfo) ProtoMessage() {}
+
+func (x *ComponentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentInfo.ProtoReflect.Descriptor instead.
+func (*ComponentInfo) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ComponentInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ComponentInfo) GetAosVersion() uint64 {
+	if x != nil {
+		return x.AosVersion
+	}
+	return 0
+}
+
+func (x *ComponentInfo) GetVendorVersion() string {
+	if x != nil {
+		return x.VendorVersion
+	}
+	return ""
+}
+
+type UnitConfigInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VendorVersion string `protobuf:"bytes,1,opt,name=vendor_version,json=vendorVersion,proto3" json:"vendor_version,omitempty"`
+}
+
+func (x *UnitConfigInfo) Reset() {
+	*x = UnitConfigInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnitConfigInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnitConfigInfo) ProtoMessage() {}
+
+func (x *UnitConfigInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnitConfigInfo.ProtoReflect.Descriptor instead.
+func (*UnitConfigInfo) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UnitConfigInfo) GetVendorVersion() string {
+	if x != nil {
+		return x.VendorVersion
+	}
+	return ""
+}
+
+type ServiceInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AosVersion uint64 `protobuf:"varint,2,opt,name=aos_version,json=aosVersion,proto3" json:"aos_version,omitempty"`
+}
+
+func (x *ServiceInfo) Reset() {
+	*x = ServiceInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceInfo) ProtoMessage() {}
+
+func (x *ServiceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceInfo.ProtoReflect.Descriptor instead.
+func (*ServiceInfo) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ServiceInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServiceInfo) GetAosVersion() uint64 {
+	if x != nil {
+		return x.AosVersion
+	}
+	return 0
+}
+
+type LayerInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AosVersion uint64 `protobuf:"varint,2,opt,name=aos_version,json=aosVersion,proto3" json:"aos_version,omitempty"`
+	Digest     string `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *LayerInfo) Reset() {
+	*x = LayerInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LayerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LayerInfo) ProtoMessage() {}
+
+func (x *LayerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LayerInfo.ProtoReflect.Descriptor instead.
+func (*LayerInfo) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LayerInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LayerInfo) GetAosVersion() uint64 {
+	if x != nil {
+		return x.AosVersion
+	}
+	return 0
+}
+
+func (x *LayerInfo) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+type ExtendTTLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Extension *durationpb.Duration `protobuf:"bytes,1,opt,name=extension,proto3" json:"extension,omitempty"`
+}
+
+func (x *ExtendTTLRequest) Reset() {
+	*x = ExtendTTLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendTTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendTTLRequest) ProtoMessage() {}
+
+func (x *ExtendTTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendTTLRequest.ProtoReflect.Descriptor instead.
+func (*ExtendTTLRequest) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExtendTTLRequest) GetExtension() *durationpb.Duration {
+	if x != nil {
+		return x.Extension
+	}
+	return nil
+}
+
+type HealthStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Insecure           bool              `protobuf:"varint,1,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	InsecureComponents []string          `protobuf:"bytes,2,rep,name=insecure_components,json=insecureComponents,proto3" json:"insecure_components,omitempty"`
+	Errors             map[string]string `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *HealthStatus) Reset() {
+	*x = HealthStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthStatus) ProtoMessage() {}
+
+func (x *HealthStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_communicationmanager_v2_updatescheduler_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthStatus.ProtoReflect.Descriptor instead.
+func (*HealthStatus) Descriptor() ([]byte, []int) {
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HealthStatus) GetInsecure() bool {
+	if x != nil {
+		return x.Insecure
+	}
+	return false
+}
+
+func (x *HealthStatus) GetInsecureComponents() []string {
+	if x != nil {
+		return x.InsecureComponents
+	}
+	return nil
+}
+
+func (x *HealthStatus) GetErrors() map[string]string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+var File_communicationmanager_v2_updatescheduler_proto protoreflect.FileDescriptor
+
+var file_communicationmanager_v2_updatescheduler_proto_rawDesc = []byte{
+	0x0a, 0x2d, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2f, 0x76, 0x32, 0x2f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x17, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xcd, 0x01, 0x0a, 0x16, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x72, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x4c, 0x0a, 0x0b, 0x73, 0x6f, 0x74, 0x61, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x4f, 0x54, 0x41, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x48, 0x00, 0x52, 0x0a, 0x73, 0x6f, 0x74, 0x61, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x4c, 0x0a, 0x0b, 0x66, 0x6f, 0x74, 0x61, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x4f, 0x54, 0x41, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48,
+	0x00, 0x52, 0x0a, 0x66, 0x6f, 0x74, 0x61, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x17, 0x0a,
+	0x15, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x88, 0x03, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x46, 0x4f, 0x54, 0x41, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3a, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x24, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65,
+	0x72, 0x2e, 0x76, 0x32, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x46, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f,
+	0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x48, 0x0a, 0x0b, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x55,
+	0x6e, 0x69, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x75,
+	0x6e, 0x69, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x3b, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x35, 0x0a, 0x08,
+	0x74, 0x74, 0x6c, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x74, 0x74, 0x6c, 0x44,
+	0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63,
+	0x79, 0x22, 0xaa, 0x04, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x4f, 0x54, 0x41,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3a, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x24, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x4f, 0x0a, 0x10, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x0f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x12, 0x4d, 0x0a, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x0e, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x12, 0x49, 0x0a, 0x0e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x5f, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65,
+	0x72, 0x2e, 0x76, 0x32, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0d,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x47, 0x0a,
+	0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x4c,
+	0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x4c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x3b, 0x0a, 0x0b,
+	0x6e, 0x65, 0x78, 0x74, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6e,
+	0x65, 0x78, 0x74, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x35, 0x0a, 0x08, 0x74, 0x74, 0x6c,
+	0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x74, 0x74, 0x6c, 0x44, 0x61, 0x74, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x67,
+	0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x61, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x25, 0x0a, 0x0e, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x37, 0x0a, 0x0e, 0x55, 0x6e, 0x69, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x25, 0x0a, 0x0e, 0x76, 0x65, 0x6e,
+	0x64, 0x6f, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x3e, 0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x61, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x54, 0x0a, 0x09, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1f, 0x0a,
+	0x0b, 0x61, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x61, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x4b, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64,
+	0x54, 0x54, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x09, 0x65, 0x78,
+	0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0xe1, 0x01, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65,
+	0x12, 0x2f, 0x0a, 0x13, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x6f, 0x6d,
+	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x69,
+	0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x49, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x31, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x48, 0x65, 0x61, 0x6c,
+	0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x1a, 0x39, 0x0a, 0x0b,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x50, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x5f, 0x55, 0x50, 0x44,
+	0x41, 0x54, 0x45, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x44, 0x4f, 0x57, 0x4e, 0x4c, 0x4f, 0x41,
+	0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f,
+	0x54, 0x4f, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x55,
+	0x50, 0x44, 0x41, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x32, 0x89, 0x04, 0x0a, 0x16, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x43, 0x0a, 0x0f, 0x53, 0x74, 0x61, 0x72, 0x74, 0x46, 0x4f, 0x54,
+	0x41, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0f, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x53, 0x4f, 0x54, 0x41, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x54,
+	0x0a, 0x0d, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x46, 0x4f, 0x54, 0x41, 0x54, 0x54, 0x4c, 0x12,
+	0x29, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64,
+	0x54, 0x54, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x0d, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x53, 0x4f,
+	0x54, 0x41, 0x54, 0x54, 0x4c, 0x12, 0x29, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x65, 0x0a, 0x16, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x2f, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x52, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x25, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x2e, 0x76, 0x32, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_communicationmanager_v2_updatescheduler_proto_rawDescOnce sync.Once
+	file_communicationmanager_v2_updatescheduler_proto_rawDescData = file_communicationmanager_v2_updatescheduler_proto_rawDesc
+)
+
+func file_communicationmanager_v2_updatescheduler_proto_rawDescGZIP() []byte {
+	file_communicationmanager_v2_updatescheduler_proto_rawDescOnce.Do(func() {
+		file_communicationmanager_v2_updatescheduler_proto_rawDescData = protoimpl.X.CompressGZIP(file_communicationmanager_v2_updatescheduler_proto_rawDescData)
+	})
+	return file_communicationmanager_v2_updatescheduler_proto_rawDescData
+}
+
+var file_communicationmanager_v2_updatescheduler_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_communicationmanager_v2_updatescheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_communicationmanager_v2_updatescheduler_proto_goTypes = []interface{}{
+	(UpdateState)(0),               // 0: communicationmanager.v2.UpdateState
+	(*SchedulerNotifications)(nil), // 1: communicationmanager.v2.SchedulerNotifications
+	(*UpdateFOTAStatus)(nil),       // 2: communicationmanager.v2.UpdateFOTAStatus
+	(*UpdateSOTAStatus)(nil),       // 3: communicationmanager.v2.UpdateSOTAStatus
+	(*ComponentInfo)(nil),          // 4: communicationmanager.v2.ComponentInfo
+	(*UnitConfigInfo)(nil),         // 5: communicationmanager.v2.UnitConfigInfo
+	(*ServiceInfo)(nil),            // 6: communicationmanager.v2.ServiceInfo
+	(*LayerInfo)(nil),              // 7: communicationmanager.v2.LayerInfo
+	(*ExtendTTLRequest)(nil),       // 8: communicationmanager.v2.ExtendTTLRequest
+	(*HealthStatus)(nil),           // 9: communicationmanager.v2.HealthStatus
+	nil,                            // 10: communicationmanager.v2.HealthStatus.ErrorsEntry
+	(*timestamppb.Timestamp)(nil),  // 11: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 12: google.protobuf.Duration
+	(*emptypb.Empty)(nil),          // 13: google.protobuf.Empty
+}
+var file_communicationmanager_v2_updatescheduler_proto_depIdxs = []int32{
+	3,  // 0: communicationmanager.v2.SchedulerNotifications.sota_status:type_name -> communicationmanager.v2.UpdateSOTAStatus
+	2,  // 1: communicationmanager.v2.SchedulerNotifications.fota_status:type_name -> communicationmanager.v2.UpdateFOTAStatus
+	0,  // 2: communicationmanager.v2.UpdateFOTAStatus.state:type_name -> communicationmanager.v2.UpdateState
+	4,  // 3: communicationmanager.v2.UpdateFOTAStatus.components:type_name -> communicationmanager.v2.ComponentInfo
+	5,  // 4: communicationmanager.v2.UpdateFOTAStatus.unit_config:type_name -> communicationmanager.v2.UnitConfigInfo
+	11, // 5: communicationmanager.v2.UpdateFOTAStatus.next_window:type_name -> google.protobuf.Timestamp
+	11, // 6: communicationmanager.v2.UpdateFOTAStatus.ttl_date:type_name -> google.protobuf.Timestamp
+	0,  // 7: communicationmanager.v2.UpdateSOTAStatus.state:type_name -> communicationmanager.v2.UpdateState
+	6,  // 8: communicationmanager.v2.UpdateSOTAStatus.install_services:type_name -> communicationmanager.v2.ServiceInfo
+	6,  // 9: communicationmanager.v2.UpdateSOTAStatus.remove_services:type_name -> communicationmanager.v2.ServiceInfo
+	7,  // 10: communicationmanager.v2.UpdateSOTAStatus.install_layers:type_name -> communicationmanager.v2.LayerInfo
+	7,  // 11: communicationmanager.v2.UpdateSOTAStatus.remove_layers:type_name -> communicationmanager.v2.LayerInfo
+	11, // 12: communicationmanager.v2.UpdateSOTAStatus.next_window:type_name -> google.protobuf.Timestamp
+	11, // 13: communicationmanager.v2.UpdateSOTAStatus.ttl_date:type_name -> google.protobuf.Timestamp
+	12, // 14: communicationmanager.v2.ExtendTTLRequest.extension:type_name -> google.protobuf.Duration
+	10, // 15: communicationmanager.v2.HealthStatus.errors:type_name -> communicationmanager.v2.HealthStatus.ErrorsEntry
+	13, // 16: communicationmanager.v2.UpdateSchedulerService.StartFOTAUpdate:input_type -> google.protobuf.Empty
+	13, // 17: communicationmanager.v2.UpdateSchedulerService.StartSOTAUpdate:input_type -> google.protobuf.Empty
+	8,  // 18: communicationmanager.v2.UpdateSchedulerService.ExtendFOTATTL:input_type -> communicationmanager.v2.ExtendTTLRequest
+	8,  // 19: communicationmanager.v2.UpdateSchedulerService.ExtendSOTATTL:input_type -> communicationmanager.v2.ExtendTTLRequest
+	13, // 20: communicationmanager.v2.UpdateSchedulerService.SubscribeNotifications:input_type -> google.protobuf.Empty
+	13, // 21: communicationmanager.v2.UpdateSchedulerService.GetHealthStatus:input_type -> google.protobuf.Empty
+	13, // 22: communicationmanager.v2.UpdateSchedulerService.StartFOTAUpdate:output_type -> google.protobuf.Empty
+	13, // 23: communicationmanager.v2.UpdateSchedulerService.StartSOTAUpdate:output_type -> google.protobuf.Empty
+	13, // 24: communicationmanager.v2.UpdateSchedulerService.ExtendFOTATTL:output_type -> google.protobuf.Empty
+	13, // 25: communicationmanager.v2.UpdateSchedulerService.ExtendSOTATTL:output_type -> google.protobuf.Empty
+	1,  // 26: communicationmanager.v2.UpdateSchedulerService.SubscribeNotifications:output_type -> communicationmanager.v2.SchedulerNotifications
+	9,  // 27: communicationmanager.v2.UpdateSchedulerService.GetHealthStatus:output_type -> communicationmanager.v2.HealthStatus
+	22, // [22:28] is the sub-list for method output_type
+	16, // [16:22] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_communicationmanager_v2_updatescheduler_proto_init() }
+func file_communicationmanager_v2_updatescheduler_proto_init() {
+	if File_communicationmanager_v2_updatescheduler_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SchedulerNotifications); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFOTAStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateSOTAStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComponentInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnitConfigInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LayerInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendTTLRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_communicationmanager_v2_updatescheduler_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_communicationmanager_v2_updatescheduler_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*SchedulerNotifications_SotaStatus)(nil),
+		(*SchedulerNotifications_FotaStatus)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_communicationmanager_v2_updatescheduler_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_communicationmanager_v2_updatescheduler_proto_goTypes,
+		DependencyIndexes: file_communicationmanager_v2_updatescheduler_proto_depIdxs,
+		EnumInfos:         file_communicationmanager_v2_updatescheduler_proto_enumTypes,
+		MessageInfos:      file_communicationmanager_v2_updatescheduler_proto_msgTypes,
+	}.Build()
+	File_communicationmanager_v2_updatescheduler_proto = out.File
+	file_communicationmanager_v2_updatescheduler_proto_rawDesc = nil
+	file_communicationmanager_v2_updatescheduler_proto_goTypes = nil
+	file_communicationmanager_v2_updatescheduler_proto_depIdxs = nil
+}
@@ -0,0 +1,133 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package updatemanager
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// UMServiceClient is the client API for UMService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UMServiceClient interface {
+	RegisterUM(ctx context.Context, opts ...grpc.CallOption) (UMService_RegisterUMClient, error)
+}
+
+type uMServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUMServiceClient(cc grpc.ClientConnInterface) UMServiceClient {
+	return &uMServiceClient{cc}
+}
+
+func (c *uMServiceClient) RegisterUM(ctx context.Context, opts ...grpc.CallOption) (UMService_RegisterUMClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UMService_ServiceDesc.Streams[0], "/updatemanager.v1.UMService/RegisterUM", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &uMServiceRegisterUMClient{stream}
+	return x, nil
+}
+
+type UMService_RegisterUMClient interface {
+	Send(*UpdateStatus) error
+	Recv() (*CMMessages, error)
+	grpc.ClientStream
+}
+
+type uMServiceRegisterUMClient struct {
+	grpc.ClientStream
+}
+
+func (x *uMServiceRegisterUMClient) Send(m *UpdateStatus) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *uMServiceRegisterUMClient) Recv() (*CMMessages, error) {
+	m := new(CMMessages)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UMServiceServer is the server API for UMService service.
+// All implementations must embed UnimplementedUMServiceServer
+// for forward compatibility
+type UMServiceServer interface {
+	RegisterUM(UMService_RegisterUMServer) error
+	mustEmbedUnimplementedUMServiceServer()
+}
+
+// UnimplementedUMServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedUMServiceServer struct {
+}
+
+func (UnimplementedUMServiceServer) RegisterUM(UMService_RegisterUMServer) error {
+	return status.Errorf(codes.Unimplemented, "method RegisterUM not implemented")
+}
+func (UnimplementedUMServiceServer) mustEmbedUnimplementedUMServiceServer() {}
+
+// UnsafeUMServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UMServiceServer will
+// result in compilation errors.
+type UnsafeUMServiceServer interface {
+	mustEmbedUnimplementedUMServiceServer()
+}
+
+func RegisterUMServiceServer(s grpc.ServiceRegistrar, srv UMServiceServer) {
+	s.RegisterService(&UMService_ServiceDesc, srv)
+}
+
+func _UMService_RegisterUM_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UMServiceServer).RegisterUM(&uMServiceRegisterUMServer{stream})
+}
+
+type UMService_RegisterUMServer interface {
+	Send(*CMMessages) error
+	Recv() (*UpdateStatus, error)
+	grpc.ServerStream
+}
+
+type uMServiceRegisterUMServer struct {
+	grpc.ServerStream
+}
+
+func (x *uMServiceRegisterUMServer) Send(m *CMMessages) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *uMServiceRegisterUMServer) Recv() (*UpdateStatus, error) {
+	m := new(UpdateStatus)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UMService_ServiceDesc is the grpc.ServiceDesc for UMService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UMService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "updatemanager.v1.UMService",
+	HandlerType: (*UMServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RegisterUM",
+			Handler:       _UMService_RegisterUM_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "updatemanager/v1/updatemanager.proto",
+}
@@ -27,6 +27,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
@@ -35,6 +36,7 @@ import (
 	"github.com/aosedge/aos_common/utils/cryptutils"
 	"github.com/looplab/fsm"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
 
 	"github.com/aosedge/aos_common/api/cloudprotocol"
 	"github.com/aosedge/aos_communicationmanager/config"
@@ -56,13 +58,19 @@ type Controller struct {
 
 	decrypter Decrypter
 
+	disableAutoRevert bool
+	parallelApply     bool
+	insecure          bool
+	observerMode      bool
+
 	allocator spaceallocator.Allocator
 
 	connections       []umConnection
+	componentsMutex   sync.Mutex
 	currentComponents []cloudprotocol.ComponentStatus
 	fsm               *fsm.FSM
 	connectionMonitor allConnectionMonitor
-	operable          bool
+	operable          atomic.Bool
 	updateFinishCond  *sync.Cond
 
 	updateError error
@@ -72,24 +80,27 @@ type Controller struct {
 
 // SystemComponent information about system component update.
 type SystemComponent struct {
-	ID            string `json:"id"`
-	VendorVersion string `json:"vendorVersion"`
-	AosVersion    uint64 `json:"aosVersion"`
-	Annotations   string `json:"annotations,omitempty"`
-	URL           string `json:"url"`
-	Sha256        []byte `json:"sha256"`
-	Sha512        []byte `json:"sha512"`
-	Size          uint64 `json:"size"`
+	ID            string   `json:"id"`
+	VendorVersion string   `json:"vendorVersion"`
+	AosVersion    uint64   `json:"aosVersion"`
+	Annotations   string   `json:"annotations,omitempty"`
+	URL           string   `json:"url"`
+	Sha256        []byte   `json:"sha256"`
+	Sha512        []byte   `json:"sha512"`
+	Size          uint64   `json:"size"`
+	UMIDs         []string `json:"umIds,omitempty"`
 }
 
 type umConnection struct {
-	umID           string
-	isLocalClient  bool
-	handler        *umHandler
-	updatePriority uint32
-	state          string
-	components     []string
-	updatePackages []SystemComponent
+	umID              string
+	isLocalClient     bool
+	handler           *umHandler
+	updatePriority    uint32
+	state             string
+	components        []string
+	updatePackages    []SystemComponent
+	connectionTimeout time.Duration
+	connectDeadline   time.Time
 }
 
 type umCtrlInternalMsg struct {
@@ -193,7 +204,7 @@ const (
 	beforePrefix = "before_"
 )
 
-const connectionTimeout = 600 * time.Second
+const defaultConnectionTimeout = 600 * time.Second
 
 const fileScheme = "file"
 
@@ -212,9 +223,20 @@ func New(config *config.Config, storage storage, certProvider CertificateProvide
 		stopChannel:       make(chan bool),
 		componentDir:      config.ComponentsDir,
 		connectionMonitor: allConnectionMonitor{stopTimerChan: make(chan bool, 1), timeoutChan: make(chan bool, 1)},
-		operable:          true,
 		updateFinishCond:  sync.NewCond(&sync.Mutex{}),
 		decrypter:         decrypter,
+		disableAutoRevert: config.UMController.DisableAutoRevert,
+		parallelApply:     config.UMController.ParallelApply,
+		insecure:          insecure,
+		observerMode:      config.ObserverMode,
+	}
+
+	umCtrl.operable.Store(true)
+
+	if insecure {
+		log.WithFields(log.Fields{
+			"component": "umcontroller", "insecure": true, "url": config.UMController.CMServerURL,
+		}).Warn("UM controller runs in insecure mode, TLS is disabled")
 	}
 
 	if err := os.MkdirAll(umCtrl.componentDir, 0o755); err != nil {
@@ -226,10 +248,21 @@ func New(config *config.Config, storage storage, certProvider CertificateProvide
 		return nil, aoserrors.Wrap(err)
 	}
 
+	defaultTimeout := config.UMController.ConnectionTimeout.Duration
+	if defaultTimeout == 0 {
+		defaultTimeout = defaultConnectionTimeout
+	}
+
 	for _, client := range config.UMController.UMClients {
+		timeout := client.ConnectionTimeout.Duration
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+
 		umCtrl.connections = append(umCtrl.connections, umConnection{
 			umID:          client.UMID,
 			isLocalClient: client.IsLocal, updatePriority: client.Priority, handler: nil,
+			connectionTimeout: timeout, connectDeadline: time.Now().Add(timeout),
 		})
 	}
 
@@ -252,7 +285,7 @@ func New(config *config.Config, storage storage, certProvider CertificateProvide
 
 	umCtrl.connectionMonitor.wg.Add(1)
 
-	go umCtrl.connectionMonitor.startConnectionTimer(len(umCtrl.connections))
+	go umCtrl.connectionMonitor.startConnectionTimer(len(umCtrl.connections), umCtrl.getConnectionTimeout())
 	go func() {
 		if err := umCtrl.server.Start(); err != nil {
 			log.Errorf("Can't start UM controller server: %s", err)
@@ -270,124 +303,221 @@ func (umCtrl *Controller) Close() {
 		}
 	}
 
-	umCtrl.operable = false
+	umCtrl.operable.Store(false)
 	umCtrl.stopChannel <- true
 }
 
-// GetStatus returns list of system components information.
+// IsInsecure returns true if UM controller server runs in insecure mode.
+func (umCtrl *Controller) IsInsecure() bool {
+	return umCtrl.insecure
+}
+
+// GetStatus returns list of system components information sorted by UM ID and component ID.
 func (umCtrl *Controller) GetStatus() ([]cloudprotocol.ComponentStatus, error) {
 	currentState := umCtrl.fsm.Current()
-	if currentState != stateInit {
-		return umCtrl.currentComponents, nil
+	if currentState == stateInit {
+		umCtrl.connectionMonitor.wg.Wait()
 	}
 
-	umCtrl.connectionMonitor.wg.Wait()
-
-	return umCtrl.currentComponents, nil
+	return umCtrl.getSortedComponents(), nil
 }
 
-// UpdateComponents updates components.
+// UpdateComponents updates components. Components are updated in groups ordered by their dependencies: a component
+// is prepared and applied only after all components it depends on are updated. Failure of a group fails the whole
+// group and skips all following groups. Component with UM IDs specified is updated only on these UMs (canary
+// update), otherwise it is updated on the first UM which holds it.
 func (umCtrl *Controller) UpdateComponents(
 	components []cloudprotocol.ComponentInfo, chains []cloudprotocol.CertificateChain,
 	certs []cloudprotocol.Certificate,
 ) ([]cloudprotocol.ComponentStatus, error) {
 	log.Debug("Update components")
 
-	if umCtrl.fsm.Current() == stateIdle {
-		umCtrl.updateError = nil
+	if umCtrl.observerMode {
+		return umCtrl.getComponentsStatus(), aoserrors.New("update components is not allowed in observer mode")
+	}
+
+	if umCtrl.fsm.Current() != stateIdle {
+		return umCtrl.waitUpdateFinished()
+	}
+
+	umCtrl.updateError = nil
+
+	if len(components) == 0 {
+		return umCtrl.getComponentsStatus(), nil
+	}
+
+	groups, err := getUpdateGroups(components)
+	if err != nil {
+		return umCtrl.getComponentsStatus(), err
+	}
 
-		if len(components) == 0 {
-			return umCtrl.currentComponents, nil
+	for i, group := range groups {
+		if len(groups) > 1 {
+			log.WithFields(log.Fields{"group": i, "components": getComponentIDs(group)}).Debug("Update components group")
 		}
 
-		componentsUpdateInfo := []SystemComponent{}
+		spaces, err := umCtrl.startGroupUpdate(group, chains, certs)
+		if err != nil {
+			acceptAllocatedSpaces(spaces)
+			umCtrl.skipGroups(groups[i+1:], err)
 
-		for _, component := range components {
-			componentStatus := systemComponentStatus{
-				id: component.ID, vendorVersion: component.VendorVersion,
-				aosVersion: component.AosVersion, status: cloudprotocol.DownloadedStatus,
-			}
+			return umCtrl.getComponentsStatus(), err
+		}
 
-			encryptedFile, err := getFilePath(component.URLs[0])
-			if err != nil {
-				return umCtrl.currentComponents, aoserrors.Wrap(err)
-			}
+		_, err = umCtrl.waitUpdateFinished()
 
-			decryptedFile := path.Join(umCtrl.componentDir, base64.URLEncoding.EncodeToString(component.Sha256))
+		acceptAllocatedSpaces(spaces)
 
-			space, err := umCtrl.allocator.AllocateSpace(component.Size)
-			if err != nil {
-				return umCtrl.currentComponents, aoserrors.Wrap(err)
-			}
+		if err != nil {
+			umCtrl.skipGroups(groups[i+1:], err)
 
-			defer func() {
-				if err != nil {
-					releaseAllocatedSpace(decryptedFile, space)
+			return umCtrl.getComponentsStatus(), err
+		}
+	}
 
-					return
-				}
+	return umCtrl.getComponentsStatus(), nil
+}
 
-				if err := space.Accept(); err != nil {
-					log.Errorf("Can't accept memory: %v", err)
-				}
-			}()
-
-			if err = umCtrl.decrypter.DecryptAndValidate(encryptedFile, decryptedFile,
-				fcrypt.DecryptParams{
-					Chains:         chains,
-					Certs:          certs,
-					DecryptionInfo: component.DecryptionInfo,
-					Signs:          component.Signs,
-				}); err != nil {
-				return umCtrl.currentComponents, aoserrors.Wrap(err)
-			}
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
 
-			fileInfo, err := image.CreateFileInfo(context.Background(), decryptedFile)
-			if err != nil {
-				return umCtrl.currentComponents, aoserrors.Wrap(err)
-			}
+// startGroupUpdate prepares components of the group and starts its update. It returns space allocated for prepared
+// components which should be accepted when the group update is finished.
+func (umCtrl *Controller) startGroupUpdate(
+	components []cloudprotocol.ComponentInfo, chains []cloudprotocol.CertificateChain,
+	certs []cloudprotocol.Certificate,
+) ([]spaceallocator.Space, error) {
+	componentsUpdateInfo := []SystemComponent{}
+	spaces := []spaceallocator.Space{}
 
-			url := url.URL{
-				Scheme: fileScheme,
-				Path:   decryptedFile,
-			}
+	for _, component := range components {
+		componentInfo, space, err := umCtrl.prepareComponent(component, chains, certs)
+		if err != nil {
+			return spaces, err
+		}
 
-			componentInfo := SystemComponent{
-				ID: component.ID, VendorVersion: component.VendorVersion,
-				AosVersion: component.AosVersion, Annotations: string(component.Annotations),
-				Sha256: fileInfo.Sha256, Sha512: fileInfo.Sha512, Size: fileInfo.Size,
-				URL: url.String(),
-			}
+		spaces = append(spaces, space)
 
-			if err = umCtrl.addComponentForUpdateToUm(componentInfo); err != nil {
-				return umCtrl.currentComponents, aoserrors.Wrap(err)
-			}
+		umCtrl.componentsMutex.Lock()
 
-			componentsUpdateInfo = append(componentsUpdateInfo, componentInfo)
+		if err = umCtrl.addComponentForUpdateToUm(componentInfo); err != nil {
+			umCtrl.componentsMutex.Unlock()
 
-			umCtrl.updateComponentElement(componentStatus)
+			return spaces, aoserrors.Wrap(err)
 		}
 
-		if err := umCtrl.storage.SetComponentsUpdateInfo(componentsUpdateInfo); err != nil {
-			go umCtrl.generateFSMEvent(evUpdateFailed, aoserrors.Wrap(err))
+		umCtrl.updateComponentElement("", systemComponentStatus{
+			id: component.ID, vendorVersion: component.VendorVersion,
+			aosVersion: component.AosVersion, status: cloudprotocol.DownloadedStatus,
+		})
+
+		umCtrl.componentsMutex.Unlock()
+
+		componentsUpdateInfo = append(componentsUpdateInfo, componentInfo)
+	}
+
+	if err := umCtrl.storage.SetComponentsUpdateInfo(componentsUpdateInfo); err != nil {
+		go umCtrl.generateFSMEvent(evUpdateFailed, aoserrors.Wrap(err))
+
+		return spaces, aoserrors.Wrap(err)
+	}
+
+	umCtrl.generateFSMEvent(evUpdateRequest, nil)
 
-			return umCtrl.currentComponents, aoserrors.Wrap(err)
+	return spaces, nil
+}
+
+// prepareComponent allocates space for the component and decrypts it. Allocated space is released on error.
+func (umCtrl *Controller) prepareComponent(
+	component cloudprotocol.ComponentInfo, chains []cloudprotocol.CertificateChain,
+	certs []cloudprotocol.Certificate,
+) (componentInfo SystemComponent, space spaceallocator.Space, err error) {
+	encryptedFile, err := getFilePath(component.URLs[0])
+	if err != nil {
+		return componentInfo, nil, aoserrors.Wrap(err)
+	}
+
+	decryptedFile := path.Join(umCtrl.componentDir, base64.URLEncoding.EncodeToString(component.Sha256))
+
+	if space, err = umCtrl.allocator.AllocateSpace(component.Size); err != nil {
+		return componentInfo, nil, aoserrors.Wrap(err)
+	}
+
+	defer func() {
+		if err != nil {
+			releaseAllocatedSpace(decryptedFile, space)
 		}
+	}()
+
+	if err = umCtrl.decrypter.DecryptAndValidate(encryptedFile, decryptedFile,
+		fcrypt.DecryptParams{
+			Chains:         chains,
+			Certs:          certs,
+			DecryptionInfo: component.DecryptionInfo,
+			Signs:          component.Signs,
+		}); err != nil {
+		return componentInfo, nil, aoserrors.Wrap(err)
+	}
 
-		umCtrl.generateFSMEvent(evUpdateRequest, nil)
+	fileInfo, err := image.CreateFileInfo(context.Background(), decryptedFile)
+	if err != nil {
+		return componentInfo, nil, aoserrors.Wrap(err)
+	}
+
+	url := url.URL{
+		Scheme: fileScheme,
+		Path:   decryptedFile,
 	}
 
+	return SystemComponent{
+		ID: component.ID, VendorVersion: component.VendorVersion,
+		AosVersion: component.AosVersion, Annotations: string(component.Annotations),
+		Sha256: fileInfo.Sha256, Sha512: fileInfo.Sha512, Size: fileInfo.Size,
+		URL: url.String(), UMIDs: component.UMIDs,
+	}, space, nil
+}
+
+func (umCtrl *Controller) waitUpdateFinished() ([]cloudprotocol.ComponentStatus, error) {
 	umCtrl.updateFinishCond.L.Lock()
 	defer umCtrl.updateFinishCond.L.Unlock()
 
 	umCtrl.updateFinishCond.Wait()
 
-	return umCtrl.currentComponents, umCtrl.updateError
+	return umCtrl.getComponentsStatus(), umCtrl.updateError
 }
 
-/***********************************************************************************************************************
- * Private
- **********************************************************************************************************************/
+// broadcastUpdateFinished wakes up update waiters. It is done under the condition lock to make update finish
+// actions visible to the waiters.
+func (umCtrl *Controller) broadcastUpdateFinished() {
+	umCtrl.updateFinishCond.L.Lock()
+	defer umCtrl.updateFinishCond.L.Unlock()
+
+	umCtrl.updateFinishCond.Broadcast()
+}
+
+// skipGroups reports components of not started groups as failed due to failure of the previous group.
+func (umCtrl *Controller) skipGroups(groups [][]cloudprotocol.ComponentInfo, groupErr error) {
+	umCtrl.componentsMutex.Lock()
+	defer umCtrl.componentsMutex.Unlock()
+
+	for _, group := range groups {
+		for _, component := range group {
+			log.WithFields(log.Fields{
+				"id": component.ID, "vendorVersion": component.VendorVersion,
+			}).Warn("Component skipped due to failed update group")
+
+			umCtrl.currentComponents = append(umCtrl.currentComponents, cloudprotocol.ComponentStatus{
+				ID: component.ID, VendorVersion: component.VendorVersion, AosVersion: component.AosVersion,
+				Status: cloudprotocol.ErrorStatus,
+				ErrorInfo: &cloudprotocol.ErrorInfo{
+					ErrorCode: cloudprotocol.ErrorCodeDependencyFailed,
+					Message:   fmt.Sprintf("component skipped: previous update group failed: %v", groupErr),
+				},
+			})
+		}
+	}
+}
 
 func (umCtrl *Controller) processInternalMessages() {
 	for {
@@ -396,7 +526,12 @@ func (umCtrl *Controller) processInternalMessages() {
 			if len(umCtrl.connections) == 0 {
 				umCtrl.generateFSMEvent(evAllClientsConnected)
 			} else {
-				log.Error("Ums connection timeout")
+				for _, connection := range umCtrl.connections {
+					if connection.handler == nil && !time.Now().Before(connection.connectDeadline) {
+						log.WithField("umID", connection.umID).Error("UM connection timeout")
+					}
+				}
+
 				umCtrl.generateFSMEvent(evConnectionTimeout)
 			}
 
@@ -408,7 +543,7 @@ func (umCtrl *Controller) processInternalMessages() {
 				umCtrl.handleNewConnection(internalMsg.umID, internalMsg.handler, internalMsg.status)
 
 			case closeConnection:
-				umCtrl.handleCloseConnection(internalMsg.umID)
+				umCtrl.handleCloseConnection(internalMsg.umID, internalMsg.handler)
 
 			case umStatusUpdate:
 				umCtrl.generateFSMEvent(evUmStateUpdated, internalMsg.umID, internalMsg.status)
@@ -421,7 +556,7 @@ func (umCtrl *Controller) processInternalMessages() {
 			log.Debug("Close all connections")
 
 			umCtrl.server.Stop()
-			umCtrl.updateFinishCond.Broadcast()
+			umCtrl.broadcastUpdateFinished()
 
 			return
 		}
@@ -441,35 +576,26 @@ func (umCtrl *Controller) handleNewConnection(umID string, handler *umHandler, s
 			continue
 		}
 
-		umCtrl.updateCurrentComponentsStatus(status.componsStatus)
-
 		umIDfound = true
 
+		umCtrl.componentsMutex.Lock()
+
+		// Duplicate registration replaces the active connection: components reported by the stale connection are
+		// dropped to not mix them with the new ones.
 		if value.handler != nil {
-			log.Warn("Connection already available umID = ", umID)
+			log.WithField("umID", umID).Warn("UM connection already available, replace it")
+
 			value.handler.Close()
+			umCtrl.removeComponentsStatus(umID, value.components)
 		}
 
+		umCtrl.updateCurrentComponentsStatus(umID, status.componsStatus)
+
 		umCtrl.connections[i].handler = handler
 		umCtrl.connections[i].state = handler.GetInitialState()
-		umCtrl.connections[i].components = []string{}
+		umCtrl.connections[i].components = getComponentStatusIDs(status.componsStatus)
 
-		for _, newComponent := range status.componsStatus {
-			idExist := false
-
-			for _, value := range umCtrl.connections[i].components {
-				if value == newComponent.id {
-					idExist = true
-					break
-				}
-			}
-
-			if idExist {
-				continue
-			}
-
-			umCtrl.connections[i].components = append(umCtrl.connections[i].components, newComponent.id)
-		}
+		umCtrl.componentsMutex.Unlock()
 
 		break
 	}
@@ -483,6 +609,8 @@ func (umCtrl *Controller) handleNewConnection(umID string, handler *umHandler, s
 
 	for _, value := range umCtrl.connections {
 		if value.handler == nil {
+			umCtrl.connectionMonitor.resetConnectionTimer(umCtrl.getConnectionTimeout())
+
 			return
 		}
 	}
@@ -498,32 +626,54 @@ func (umCtrl *Controller) handleNewConnection(umID string, handler *umHandler, s
 	umCtrl.generateFSMEvent(evAllClientsConnected)
 }
 
-func (umCtrl *Controller) handleCloseConnection(umID string) {
+func getComponentStatusIDs(componsStatus []systemComponentStatus) (ids []string) {
+	ids = []string{}
+
+	for _, component := range componsStatus {
+		if slices.Contains(ids, component.id) {
+			continue
+		}
+
+		ids = append(ids, component.id)
+	}
+
+	return ids
+}
+
+func (umCtrl *Controller) handleCloseConnection(umID string, handler *umHandler) {
 	log.Debug("Close UM connection umid = ", umID)
 
 	for i, value := range umCtrl.connections {
 		if value.umID == umID {
+			if value.handler != handler {
+				log.WithField("umID", umID).Debug("Skip close of replaced UM connection")
+
+				return
+			}
+
 			umCtrl.connections[i].handler = nil
+			umCtrl.connections[i].connectDeadline = time.Now().Add(umCtrl.connections[i].connectionTimeout)
 
 			umCtrl.fsm.SetState(stateInit)
 			umCtrl.connectionMonitor.wg.Add(1)
 
-			go umCtrl.connectionMonitor.startConnectionTimer(len(umCtrl.connections))
+			go umCtrl.connectionMonitor.startConnectionTimer(len(umCtrl.connections), umCtrl.getConnectionTimeout())
 
 			return
 		}
 	}
 }
 
-func (umCtrl *Controller) updateCurrentComponentsStatus(componsStatus []systemComponentStatus) {
-	log.Debug("Receive components: ", componsStatus)
+func (umCtrl *Controller) updateCurrentComponentsStatus(umID string, componsStatus []systemComponentStatus) {
+	log.WithField("umID", umID).Debug("Receive components: ", componsStatus)
 
 	for _, value := range componsStatus {
 		if value.status == cloudprotocol.InstalledStatus {
 			toRemove := []int{}
 
 			for i, curStatus := range umCtrl.currentComponents {
-				if value.id == curStatus.ID {
+				// Version installed on other UM (e.g. not updated yet by canary update) is kept
+				if value.id == curStatus.ID && isComponentOfUM(curStatus, umID) {
 					if curStatus.Status != cloudprotocol.InstalledStatus {
 						continue
 					}
@@ -543,22 +693,42 @@ func (umCtrl *Controller) updateCurrentComponentsStatus(componsStatus []systemCo
 			}
 		}
 
-		umCtrl.updateComponentElement(value)
+		umCtrl.updateComponentElement(umID, value)
 	}
 }
 
-func (umCtrl *Controller) updateComponentElement(component systemComponentStatus) {
+func (umCtrl *Controller) removeComponentsStatus(umID string, ids []string) {
+	i := 0
+
+	for _, component := range umCtrl.currentComponents {
+		if !slices.Contains(ids, component.ID) || !isComponentOfUM(component, umID) {
+			umCtrl.currentComponents[i] = component
+			i++
+		}
+	}
+
+	umCtrl.currentComponents = umCtrl.currentComponents[:i]
+}
+
+// updateComponentElement updates component status reported by UM. Status not attributed to UM yet (e.g. downloaded
+// component) is assigned to the first UM which reports it.
+func (umCtrl *Controller) updateComponentElement(umID string, component systemComponentStatus) {
 	for i, curElement := range umCtrl.currentComponents {
-		if curElement.ID == component.id && curElement.VendorVersion == component.vendorVersion {
+		if curElement.ID == component.id && curElement.VendorVersion == component.vendorVersion &&
+			isComponentOfUM(curElement, umID) {
 			if curElement.Status == cloudprotocol.InstalledStatus && component.status != cloudprotocol.InstalledStatus {
 				break
 			}
 
+			umCtrl.currentComponents[i].UMID = umID
+
 			if curElement.Status != component.status {
 				umCtrl.currentComponents[i].Status = component.status
 
 				if component.err != "" {
-					umCtrl.currentComponents[i].ErrorInfo = &cloudprotocol.ErrorInfo{Message: component.err}
+					umCtrl.currentComponents[i].ErrorInfo = &cloudprotocol.ErrorInfo{
+						ErrorCode: cloudprotocol.ErrorCodeInstallFailed, Message: component.err,
+					}
 				}
 			}
 
@@ -571,16 +741,86 @@ func (umCtrl *Controller) updateComponentElement(component systemComponentStatus
 		VendorVersion: component.vendorVersion,
 		AosVersion:    component.aosVersion,
 		Status:        component.status,
+		UMID:          umID,
 	}
 
 	if component.err != "" {
-		newComponentStatus.ErrorInfo = &cloudprotocol.ErrorInfo{Message: component.err}
+		newComponentStatus.ErrorInfo = &cloudprotocol.ErrorInfo{
+			ErrorCode: cloudprotocol.ErrorCodeInstallFailed, Message: component.err,
+		}
 	}
 
 	umCtrl.currentComponents = append(umCtrl.currentComponents, newComponentStatus)
 }
 
+// getSortedComponents returns copy of current components sorted by UM ID and component ID. Different versions of the
+// same component keep their order.
+func (umCtrl *Controller) getSortedComponents() []cloudprotocol.ComponentStatus {
+	components := umCtrl.getComponentsStatus()
+
+	umCtrl.componentsMutex.Lock()
+	componentUMs := umCtrl.getComponentUMs()
+	umCtrl.componentsMutex.Unlock()
+
+	getUMID := func(component cloudprotocol.ComponentStatus) string {
+		if component.UMID != "" {
+			return component.UMID
+		}
+
+		if umIDs := componentUMs[component.ID]; len(umIDs) != 0 {
+			return umIDs[0]
+		}
+
+		return ""
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		if getUMID(components[i]) != getUMID(components[j]) {
+			return getUMID(components[i]) < getUMID(components[j])
+		}
+
+		return components[i].ID < components[j].ID
+	})
+
+	return components
+}
+
+// getComponentsStatus returns copy of current components. UM ID is reported only for components held by several
+// UMs to distinguish their statuses.
+func (umCtrl *Controller) getComponentsStatus() []cloudprotocol.ComponentStatus {
+	umCtrl.componentsMutex.Lock()
+	defer umCtrl.componentsMutex.Unlock()
+
+	componentUMs := umCtrl.getComponentUMs()
+	components := make([]cloudprotocol.ComponentStatus, len(umCtrl.currentComponents))
+
+	copy(components, umCtrl.currentComponents)
+
+	for i := range components {
+		if len(componentUMs[components[i].ID]) < 2 {
+			components[i].UMID = ""
+		}
+	}
+
+	return components
+}
+
+func (umCtrl *Controller) getComponentUMs() map[string][]string {
+	componentUMs := make(map[string][]string)
+
+	for i := range umCtrl.connections {
+		for _, id := range umCtrl.connections[i].components {
+			componentUMs[id] = append(componentUMs[id], umCtrl.connections[i].umID)
+		}
+	}
+
+	return componentUMs
+}
+
 func (umCtrl *Controller) cleanupCurrentComponentStatus() {
+	umCtrl.componentsMutex.Lock()
+	defer umCtrl.componentsMutex.Unlock()
+
 	i := 0
 
 	for _, component := range umCtrl.currentComponents {
@@ -644,36 +884,65 @@ func (umCtrl *Controller) getUpdateComponentsFromStorage() (err error) {
 	return aoserrors.Wrap(err)
 }
 
+// addComponentForUpdateToUm adds component to the first UM which holds it. If UMs are specified, component is added
+// to all specified UMs which hold it.
 func (umCtrl *Controller) addComponentForUpdateToUm(componentInfo SystemComponent) (err error) {
+	found := false
+
 	for i := range umCtrl.connections {
-		for _, id := range umCtrl.connections[i].components {
-			if id == componentInfo.ID {
-				newURL, err := umCtrl.fileServer.TranslateURL(umCtrl.connections[i].isLocalClient, componentInfo.URL)
-				if err != nil {
-					return aoserrors.Wrap(err)
-				}
+		if !slices.Contains(umCtrl.connections[i].components, componentInfo.ID) {
+			continue
+		}
 
-				componentInfo.URL = newURL
+		if len(componentInfo.UMIDs) != 0 && !slices.Contains(componentInfo.UMIDs, umCtrl.connections[i].umID) {
+			continue
+		}
 
-				umCtrl.connections[i].updatePackages = append(umCtrl.connections[i].updatePackages, componentInfo)
+		umComponentInfo := componentInfo
 
-				return nil
-			}
+		if umComponentInfo.URL, err = umCtrl.fileServer.TranslateURL(
+			umCtrl.connections[i].isLocalClient, componentInfo.URL); err != nil {
+			return aoserrors.Wrap(err)
 		}
+
+		umCtrl.connections[i].updatePackages = append(umCtrl.connections[i].updatePackages, umComponentInfo)
+
+		if len(componentInfo.UMIDs) == 0 {
+			return nil
+		}
+
+		found = true
 	}
 
-	return aoserrors.Errorf("component id %s not found", componentInfo.ID)
+	if !found {
+		return aoserrors.Errorf("component id %s not found", componentInfo.ID)
+	}
+
+	return nil
 }
 
 func (umCtrl *Controller) cleanupUpdateData() {
+	// The same package may be sent to several UMs but its space is allocated once
+	freedPackages := make(map[string]struct{})
+
+	umCtrl.componentsMutex.Lock()
+
 	for i := range umCtrl.connections {
 		for _, updatePackage := range umCtrl.connections[i].updatePackages {
+			if _, ok := freedPackages[updatePackage.ID+updatePackage.VendorVersion]; ok {
+				continue
+			}
+
+			freedPackages[updatePackage.ID+updatePackage.VendorVersion] = struct{}{}
+
 			umCtrl.allocator.FreeSpace(updatePackage.Size)
 		}
 
 		umCtrl.connections[i].updatePackages = []SystemComponent{}
 	}
 
+	umCtrl.componentsMutex.Unlock()
+
 	entries, err := os.ReadDir(umCtrl.componentDir)
 	if err != nil {
 		log.Errorf("Can't read component directory: %v", err)
@@ -712,7 +981,7 @@ func (umCtrl *Controller) cleanupUpdateData() {
 }
 
 func (umCtrl *Controller) generateFSMEvent(event string, args ...interface{}) {
-	if !umCtrl.operable {
+	if !umCtrl.operable.Load() {
 		log.Error("Update controller in shutdown state")
 		return
 	}
@@ -723,6 +992,13 @@ func (umCtrl *Controller) generateFSMEvent(event string, args ...interface{}) {
 }
 
 func (umCtrl *Controller) createStateMachine() (string, []fsm.EventDesc, map[string]fsm.Callback) {
+	updateFailedState := stateStartRevert
+
+	// Failed components are left as is and update is finished
+	if umCtrl.disableAutoRevert {
+		updateFailedState = stateIdle
+	}
+
 	return stateInit,
 		fsm.Events{
 			// process Idle state
@@ -745,9 +1021,9 @@ func (umCtrl *Controller) createStateMachine() (string, []fsm.EventDesc, map[str
 			{Name: evContinue, Src: []string{stateUpdateUmStatusOnStartApply}, Dst: stateStartApply},
 			{Name: evApplyComplete, Src: []string{stateStartApply}, Dst: stateIdle},
 			// process revert
-			{Name: evUpdateFailed, Src: []string{statePrepareUpdate}, Dst: stateStartRevert},
-			{Name: evUpdateFailed, Src: []string{stateStartUpdate}, Dst: stateStartRevert},
-			{Name: evUpdateFailed, Src: []string{stateStartApply}, Dst: stateStartRevert},
+			{Name: evUpdateFailed, Src: []string{statePrepareUpdate}, Dst: updateFailedState},
+			{Name: evUpdateFailed, Src: []string{stateStartUpdate}, Dst: updateFailedState},
+			{Name: evUpdateFailed, Src: []string{stateStartApply}, Dst: updateFailedState},
 			{Name: evUmStateUpdated, Src: []string{stateStartRevert}, Dst: stateUpdateUmStatusOnRevert},
 			{Name: evContinue, Src: []string{stateUpdateUmStatusOnRevert}, Dst: stateStartRevert},
 			{Name: evSystemReverted, Src: []string{stateStartRevert}, Dst: stateIdle},
@@ -773,7 +1049,33 @@ func (umCtrl *Controller) createStateMachine() (string, []fsm.EventDesc, map[str
 		}
 }
 
-func (monitor *allConnectionMonitor) startConnectionTimer(connectionsCount int) {
+func (umCtrl *Controller) getConnectionTimeout() (timeout time.Duration) {
+	timeout = -1
+
+	// Use the nearest deadline of disconnected UMs, so each UM is checked against its own timeout
+	for _, connection := range umCtrl.connections {
+		if connection.handler != nil {
+			continue
+		}
+
+		remaining := time.Until(connection.connectDeadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		if timeout < 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	if timeout < 0 {
+		timeout = 0
+	}
+
+	return timeout
+}
+
+func (monitor *allConnectionMonitor) startConnectionTimer(connectionsCount int, timeout time.Duration) {
 	monitor.Lock()
 	defer monitor.Unlock()
 
@@ -786,10 +1088,13 @@ func (monitor *allConnectionMonitor) startConnectionTimer(connectionsCount int)
 
 	if monitor.connTimer != nil {
 		log.Debug("Timer already started")
+
+		monitor.resetTimer(timeout)
+
 		return
 	}
 
-	monitor.connTimer = time.NewTimer(connectionTimeout)
+	monitor.connTimer = time.NewTimer(timeout)
 
 	monitor.Unlock()
 
@@ -806,10 +1111,38 @@ func (monitor *allConnectionMonitor) startConnectionTimer(connectionsCount int)
 	monitor.connTimer = nil
 }
 
+func (monitor *allConnectionMonitor) resetConnectionTimer(timeout time.Duration) {
+	monitor.Lock()
+	defer monitor.Unlock()
+
+	if monitor.connTimer == nil {
+		return
+	}
+
+	monitor.resetTimer(timeout)
+}
+
+func (monitor *allConnectionMonitor) resetTimer(timeout time.Duration) {
+	// Timer is already expired and timeout will be handled
+	if !monitor.connTimer.Stop() {
+		return
+	}
+
+	monitor.connTimer.Reset(timeout)
+}
+
 func (monitor *allConnectionMonitor) stopConnectionTimer() {
 	monitor.stopTimerChan <- true
 }
 
+func acceptAllocatedSpaces(spaces []spaceallocator.Space) {
+	for _, space := range spaces {
+		if err := space.Accept(); err != nil {
+			log.Errorf("Can't accept memory: %v", err)
+		}
+	}
+}
+
 func releaseAllocatedSpace(filePath string, space spaceallocator.Space) {
 	if err := os.RemoveAll(filePath); err != nil {
 		log.Errorf("Can't remove decrypted file: %v", err)
@@ -820,6 +1153,66 @@ func releaseAllocatedSpace(filePath string, space spaceallocator.Space) {
 	}
 }
 
+// getUpdateGroups splits components into groups ordered by dependencies. Dependencies on components which are not
+// updated are ignored. Cyclic dependencies are treated as conflicting constraints.
+func getUpdateGroups(components []cloudprotocol.ComponentInfo) (groups [][]cloudprotocol.ComponentInfo, err error) {
+	requested := make(map[string]bool)
+
+	for _, component := range components {
+		requested[component.ID] = true
+	}
+
+	updated := make(map[string]bool)
+
+	for pending := components; len(pending) > 0; {
+		var group, rest []cloudprotocol.ComponentInfo
+
+		for _, component := range pending {
+			if dependenciesUpdated(component, requested, updated) {
+				group = append(group, component)
+			} else {
+				rest = append(rest, component)
+			}
+		}
+
+		if len(group) == 0 {
+			return nil, aoserrors.Errorf("conflicting update order constraints: %v", getComponentIDs(rest))
+		}
+
+		for _, component := range group {
+			updated[component.ID] = true
+		}
+
+		groups = append(groups, group)
+		pending = rest
+	}
+
+	return groups, nil
+}
+
+func dependenciesUpdated(component cloudprotocol.ComponentInfo, requested, updated map[string]bool) bool {
+	for _, dependency := range component.Dependencies {
+		if requested[dependency] && !updated[dependency] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isComponentOfUM returns true if component status belongs to UM or is not attributed to any UM yet.
+func isComponentOfUM(component cloudprotocol.ComponentStatus, umID string) bool {
+	return component.UMID == "" || component.UMID == umID
+}
+
+func getComponentIDs(components []cloudprotocol.ComponentInfo) (ids []string) {
+	for _, component := range components {
+		ids = append(ids, component.ID)
+	}
+
+	return ids
+}
+
 func getFilePath(fileURL string) (string, error) {
 	urlData, err := url.Parse(fileURL)
 	if err != nil {
@@ -846,7 +1239,13 @@ func (umCtrl *Controller) processIdleState(ctx context.Context, e *fsm.Event) {
 
 	switch umState {
 	case stateFaultState:
+		if umCtrl.disableAutoRevert {
+			log.Warn("Automatic revert is disabled, failed components are left as is")
+			break
+		}
+
 		go umCtrl.generateFSMEvent(evContinueRevert)
+
 		return
 
 	case statePrepareUpdate:
@@ -860,7 +1259,7 @@ func (umCtrl *Controller) processIdleState(ctx context.Context, e *fsm.Event) {
 
 	umCtrl.cleanupUpdateData()
 
-	umCtrl.updateFinishCond.Broadcast()
+	umCtrl.broadcastUpdateFinished()
 }
 
 func (umCtrl *Controller) processFaultState(ctx context.Context, e *fsm.Event) {
@@ -947,6 +1346,11 @@ func (umCtrl *Controller) processStartRevertState(ctx context.Context, e *fsm.Ev
 }
 
 func (umCtrl *Controller) processStartApplyState(ctx context.Context, e *fsm.Event) {
+	if umCtrl.parallelApply {
+		umCtrl.startParallelApply()
+		return
+	}
+
 	for i := range umCtrl.connections {
 		if len(umCtrl.connections[i].updatePackages) > 0 {
 			if umCtrl.connections[i].state == umFailed {
@@ -968,6 +1372,41 @@ func (umCtrl *Controller) processStartApplyState(ctx context.Context, e *fsm.Eve
 	go umCtrl.generateFSMEvent(evApplyComplete)
 }
 
+// startParallelApply signals all UMs to apply at once, so they can reboot in one combined window.
+func (umCtrl *Controller) startParallelApply() {
+	for i := range umCtrl.connections {
+		if len(umCtrl.connections[i].updatePackages) > 0 && umCtrl.connections[i].state == umFailed {
+			go umCtrl.generateFSMEvent(evUpdateFailed, aoserrors.New("apply failure umID = "+umCtrl.connections[i].umID))
+			return
+		}
+
+		if umCtrl.connections[i].handler == nil {
+			log.Warnf("Connection to um %s closed", umCtrl.connections[i].umID)
+			return
+		}
+	}
+
+	applyPending := false
+
+	for i := range umCtrl.connections {
+		if umCtrl.connections[i].handler.FSM.Current() == hStateWaitForApplyStatus {
+			applyPending = true
+			continue
+		}
+
+		if err := umCtrl.connections[i].handler.StartApply(); err == nil {
+			applyPending = true
+		}
+	}
+
+	if applyPending {
+		log.Debug("Wait for apply status from all UMs")
+		return
+	}
+
+	go umCtrl.generateFSMEvent(evApplyComplete)
+}
+
 func (umCtrl *Controller) processUpdateUmState(ctx context.Context, e *fsm.Event) {
 	log.Debug("processUpdateUmState")
 
@@ -992,7 +1431,9 @@ func (umCtrl *Controller) processUpdateUmState(ctx context.Context, e *fsm.Event
 		}
 	}
 
-	umCtrl.updateCurrentComponentsStatus(status.componsStatus)
+	umCtrl.componentsMutex.Lock()
+	umCtrl.updateCurrentComponentsStatus(umID, status.componsStatus)
+	umCtrl.componentsMutex.Unlock()
 
 	go umCtrl.generateFSMEvent(evContinue)
 }
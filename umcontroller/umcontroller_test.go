@@ -27,6 +27,7 @@ import (
 	"path"
 	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -62,9 +63,11 @@ const (
 	revertStep  = "revert"
 	finishStep  = "finish"
 	rebootStep  = "reboot"
+	idleStep    = "idle"
 )
 
 type testStorage struct {
+	sync.Mutex
 	updateInfo []umcontroller.SystemComponent
 }
 
@@ -206,6 +209,224 @@ func TestConnection(t *testing.T) {
 	time.Sleep(1 * time.Second)
 }
 
+func TestDuplicateRegistration(t *testing.T) {
+	umCtrlConfig := config.UMController{
+		CMServerURL:   "localhost:8091",
+		FileServerURL: "localhost:8092",
+		UMClients: []config.UMClientConfig{
+			{UMID: "dupUM1", Priority: 10},
+			{UMID: "dupUM2", Priority: 0},
+		},
+	}
+	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
+
+	umCtrl, err := umcontroller.New(
+		&smConfig, &testStorage{}, nil, nil, &testCryptoContext{}, true)
+	if err != nil {
+		t.Fatalf("Can't create: UM controller %s", err)
+	}
+
+	staleStream, staleConn, err := createClientConnection("dupUM1", pb.UmState_IDLE, []*pb.SystemComponent{
+		{Id: "dupC1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		{Id: "dupC2", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	})
+	if err != nil {
+		t.Fatalf("Error connect %s", err)
+	}
+
+	defer staleConn.Close()
+
+	streamUM2, connUM2, err := createClientConnection("dupUM2", pb.UmState_IDLE, []*pb.SystemComponent{
+		{Id: "dupC3", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	})
+	if err != nil {
+		t.Fatalf("Error connect %s", err)
+	}
+
+	defer connUM2.Close()
+
+	// Second registration of the same UM replaces the first one
+
+	um1Components := []*pb.SystemComponent{
+		{Id: "dupC1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		{Id: "dupC4", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um1 := newTestUM(t, "dupUM1", pb.UmState_IDLE, "init", um1Components)
+	go um1.processMessages()
+
+	currentComponents, err := umCtrl.GetStatus()
+	if err != nil {
+		t.Fatalf("Can't get system components %s", err)
+	}
+
+	etalonComponents := []cloudprotocol.ComponentStatus{
+		{ID: "dupC1", VendorVersion: "1", Status: "installed"},
+		{ID: "dupC4", VendorVersion: "1", Status: "installed"},
+		{ID: "dupC3", VendorVersion: "1", Status: "installed"},
+	}
+
+	if !reflect.DeepEqual(etalonComponents, currentComponents) {
+		t.Errorf("Incorrect components: %v", currentComponents)
+	}
+
+	// Stale connection should be closed
+
+	staleClosed := make(chan error, 1)
+
+	go func() {
+		_, err := staleStream.Recv()
+		staleClosed <- err
+	}()
+
+	select {
+	case err := <-staleClosed:
+		if err == nil {
+			t.Error("Stale connection is not closed")
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Error("Wait stale connection close timeout")
+	}
+
+	time.Sleep(time.Second)
+
+	// Latest connection should be active and receive update requests
+
+	componentDir, err := os.MkdirTemp("", "aosComponent_")
+	if err != nil {
+		t.Fatalf("Can't create component dir: %v", componentDir)
+	}
+
+	defer os.RemoveAll(componentDir)
+
+	finishChannel := make(chan bool)
+
+	go func() {
+		if _, err := umCtrl.UpdateComponents([]cloudprotocol.ComponentInfo{
+			{
+				ID: "dupC4", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+				DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile"), kilobyte*2),
+			},
+		}, nil, nil); err != nil {
+			t.Errorf("Can't update components: %s", err)
+		}
+
+		finishChannel <- true
+	}()
+
+	um1.setComponents(append(um1Components, &pb.SystemComponent{
+		Id: "dupC4", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING,
+	}))
+
+	um1.step = prepareStep
+	um1.continueChan <- true
+	<-um1.notifyTestChan
+	um1.sendState(pb.UmState_PREPARED)
+
+	um1.step = updateStep
+	um1.continueChan <- true
+	<-um1.notifyTestChan
+	um1.sendState(pb.UmState_UPDATED)
+
+	um1.setComponents([]*pb.SystemComponent{
+		{Id: "dupC1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		{Id: "dupC4", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLED},
+	})
+
+	um1.step = applyStep
+	um1.continueChan <- true
+	<-um1.notifyTestChan
+	um1.sendState(pb.UmState_IDLE)
+
+	time.Sleep(time.Second)
+
+	um1.step = finishStep
+
+	<-finishChannel
+
+	um1.closeConnection()
+	<-um1.notifyTestChan
+
+	_ = streamUM2.CloseSend()
+
+	umCtrl.Close()
+
+	time.Sleep(time.Second)
+}
+
+func TestStatusOrder(t *testing.T) {
+	umCtrlConfig := config.UMController{
+		CMServerURL:   "localhost:8091",
+		FileServerURL: "localhost:8092",
+		UMClients: []config.UMClientConfig{
+			{UMID: "testUM23", Priority: 10},
+			{UMID: "testUM24", Priority: 0},
+		},
+	}
+	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
+
+	umComponents := map[string][]*pb.SystemComponent{
+		"testUM23": {
+			{Id: "z1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+			{Id: "a1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		},
+		"testUM24": {
+			{Id: "b1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		},
+	}
+
+	etalonComponents := []cloudprotocol.ComponentStatus{
+		{ID: "a1", VendorVersion: "1", Status: "installed"},
+		{ID: "z1", VendorVersion: "1", Status: "installed"},
+		{ID: "b1", VendorVersion: "1", Status: "installed"},
+	}
+
+	for _, umOrder := range [][]string{{"testUM23", "testUM24"}, {"testUM24", "testUM23"}} {
+		umCtrl, err := umcontroller.New(
+			&smConfig, &testStorage{}, nil, nil, &testCryptoContext{}, true)
+		if err != nil {
+			t.Fatalf("Can't create: UM controller %s", err)
+		}
+
+		var (
+			streams []pb.UMService_RegisterUMClient
+			conns   []*grpc.ClientConn
+		)
+
+		for _, umID := range umOrder {
+			stream, conn, err := createClientConnection(umID, pb.UmState_IDLE, umComponents[umID])
+			if err != nil {
+				t.Errorf("Error connect %s", err)
+
+				continue
+			}
+
+			streams = append(streams, stream)
+			conns = append(conns, conn)
+		}
+
+		currentComponents, err := umCtrl.GetStatus()
+		if err != nil {
+			t.Errorf("Can't get system components %s", err)
+		}
+
+		if !reflect.DeepEqual(etalonComponents, currentComponents) {
+			t.Errorf("Wrong components order for UM connection order %v: %v", umOrder, currentComponents)
+		}
+
+		umCtrl.Close()
+
+		for i := range streams {
+			_ = streams[i].CloseSend()
+
+			conns[i].Close()
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+}
+
 /***********************************************************************************************************************
  * Private
  **********************************************************************************************************************/
@@ -396,6 +617,155 @@ func TestFullUpdate(t *testing.T) {
 	time.Sleep(time.Second)
 }
 
+func TestCanaryUpdate(t *testing.T) {
+	umCtrlConfig := config.UMController{
+		CMServerURL:   "localhost:8091",
+		FileServerURL: "localhost:8093",
+		UMClients: []config.UMClientConfig{
+			{UMID: "testUM1", Priority: 1},
+			{UMID: "testUM2", Priority: 10},
+		},
+	}
+
+	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
+
+	var updateStorage testStorage
+
+	umCtrl, err := umcontroller.New(
+		&smConfig, &updateStorage, nil, nil, &testCryptoContext{}, true)
+	if err != nil {
+		t.Errorf("Can't create: UM controller %s", err)
+	}
+
+	um1Components := []*pb.SystemComponent{
+		{Id: "comp1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um1 := newTestUM(t, "testUM1", pb.UmState_IDLE, "init", um1Components)
+	go um1.processMessages()
+
+	um2Components := []*pb.SystemComponent{
+		{Id: "comp1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um2 := newTestUM(t, "testUM2", pb.UmState_IDLE, "init", um2Components)
+	go um2.processMessages()
+
+	componentDir, err := os.MkdirTemp("", "aosComponent_")
+	if err != nil {
+		t.Fatalf("Can't create component dir: %v", componentDir)
+	}
+
+	defer os.RemoveAll(componentDir)
+
+	updateComponents := []cloudprotocol.ComponentInfo{
+		{
+			ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile1"), kilobyte*2),
+		},
+	}
+
+	updateUM := func(um *testUmConnection, umComponents []*pb.SystemComponent) {
+		um.setComponents(append(umComponents, &pb.SystemComponent{
+			Id: "comp1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING,
+		}))
+
+		um.step = prepareStep
+		um.continueChan <- true
+		<-um.notifyTestChan
+		um.sendState(pb.UmState_PREPARED)
+
+		um.step = updateStep
+		um.continueChan <- true
+		<-um.notifyTestChan
+		um.sendState(pb.UmState_UPDATED)
+
+		um.setComponents([]*pb.SystemComponent{
+			{Id: "comp1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLED},
+		})
+
+		um.step = applyStep
+		um.continueChan <- true
+		<-um.notifyTestChan
+		um.sendState(pb.UmState_IDLE)
+	}
+
+	waitFinished := func(finishChannel <-chan bool) {
+		select {
+		case <-finishChannel:
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("Wait update finished timeout")
+		}
+	}
+
+	// Canary update: only UM1 is updated
+
+	finishChannel := make(chan bool)
+
+	updateComponents[0].UMIDs = []string{"testUM1"}
+
+	go func() {
+		if _, err := umCtrl.UpdateComponents(updateComponents, nil, nil); err != nil {
+			t.Errorf("Can't update components: %s", err)
+		}
+		finishChannel <- true
+	}()
+
+	updateUM(um1, um1Components)
+	waitFinished(finishChannel)
+
+	currentComponents, err := umCtrl.GetStatus()
+	if err != nil {
+		t.Fatalf("Can't get components info: %s", err)
+	}
+
+	if !reflect.DeepEqual(currentComponents, []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "2", Status: "installed", UMID: "testUM1"},
+		{ID: "comp1", VendorVersion: "1", Status: "installed", UMID: "testUM2"},
+	}) {
+		t.Errorf("Incorrect components status: %v", currentComponents)
+	}
+
+	// Follow-up update completes rollout: only UM2 is updated
+
+	updateComponents[0].UMIDs = []string{"testUM2"}
+
+	go func() {
+		if _, err := umCtrl.UpdateComponents(updateComponents, nil, nil); err != nil {
+			t.Errorf("Can't update components: %s", err)
+		}
+		finishChannel <- true
+	}()
+
+	updateUM(um2, um2Components)
+	waitFinished(finishChannel)
+
+	if currentComponents, err = umCtrl.GetStatus(); err != nil {
+		t.Fatalf("Can't get components info: %s", err)
+	}
+
+	if !reflect.DeepEqual(currentComponents, []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "2", Status: "installed", UMID: "testUM1"},
+		{ID: "comp1", VendorVersion: "2", Status: "installed", UMID: "testUM2"},
+	}) {
+		t.Errorf("Incorrect components status: %v", currentComponents)
+	}
+
+	um1.step = finishStep
+	um2.step = finishStep
+
+	um1.closeConnection()
+	um2.closeConnection()
+
+	<-um1.notifyTestChan
+	<-um2.notifyTestChan
+
+	umCtrl.Close()
+
+	time.Sleep(time.Second)
+}
+
 func TestFullUpdateWithDisconnect(t *testing.T) {
 	// fix the test on CI
 	if os.Getenv("CI") != "" {
@@ -780,14 +1150,15 @@ func TestFullUpdateWithReboot(t *testing.T) {
 	time.Sleep(time.Second)
 }
 
-func TestRevertOnPrepare(t *testing.T) {
+func TestParallelApplyWithReboot(t *testing.T) {
 	umCtrlConfig := config.UMController{
 		CMServerURL:   "localhost:8091",
 		FileServerURL: "localhost:8093",
 		UMClients: []config.UMClientConfig{
-			{UMID: "testUM7", Priority: 1},
-			{UMID: "testUM8", Priority: 10},
+			{UMID: "testUM19", Priority: 1},
+			{UMID: "testUM20", Priority: 10},
 		},
+		ParallelApply: true,
 	}
 
 	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
@@ -800,21 +1171,19 @@ func TestRevertOnPrepare(t *testing.T) {
 		t.Errorf("Can't create: UM controller %s", err)
 	}
 
-	um7Components := []*pb.SystemComponent{
-		{Id: "um7C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
-		{Id: "um7C2", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	um19Components := []*pb.SystemComponent{
+		{Id: "um19C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
 	}
 
-	um7 := newTestUM(t, "testUM7", pb.UmState_IDLE, "init", um7Components)
-	go um7.processMessages()
+	um19 := newTestUM(t, "testUM19", pb.UmState_IDLE, "init", um19Components)
+	go um19.processMessages()
 
-	um8Components := []*pb.SystemComponent{
-		{Id: "um8C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
-		{Id: "um8C2", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	um20Components := []*pb.SystemComponent{
+		{Id: "um20C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
 	}
 
-	um8 := newTestUM(t, "testUM8", pb.UmState_IDLE, "init", um8Components)
-	go um8.processMessages()
+	um20 := newTestUM(t, "testUM20", pb.UmState_IDLE, "init", um20Components)
+	go um20.processMessages()
 
 	componentDir, err := os.MkdirTemp("", "aosComponent_")
 	if err != nil {
@@ -825,16 +1194,186 @@ func TestRevertOnPrepare(t *testing.T) {
 
 	updateComponents := []cloudprotocol.ComponentInfo{
 		{
-			ID: "um7C2", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			ID: "um19C1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
 			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile1"), kilobyte*2),
 		},
 		{
-			ID: "um8C1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			ID: "um20C1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
 			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile2"), kilobyte*2),
 		},
-		{
-			ID: "um8C2", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
-			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile3"), kilobyte*2),
+	}
+
+	finishChannel := make(chan bool)
+
+	go func() {
+		if _, err := umCtrl.UpdateComponents(updateComponents, nil, nil); err != nil {
+			t.Errorf("Can't update components: %s", err)
+		}
+
+		close(finishChannel)
+	}()
+
+	um19Components = append(um19Components,
+		&pb.SystemComponent{Id: "um19C1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING})
+	um19.setComponents(um19Components)
+
+	um19.step = prepareStep
+	um19.continueChan <- true
+	<-um19.notifyTestChan
+	um19.sendState(pb.UmState_PREPARED)
+
+	um20Components = append(um20Components,
+		&pb.SystemComponent{Id: "um20C1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING})
+	um20.setComponents(um20Components)
+
+	um20.step = prepareStep
+	um20.continueChan <- true
+	<-um20.notifyTestChan
+	um20.sendState(pb.UmState_PREPARED)
+
+	um19.step = updateStep
+	um19.continueChan <- true
+	<-um19.notifyTestChan
+	um19.sendState(pb.UmState_UPDATED)
+
+	um20.step = updateStep
+	um20.continueChan <- true
+	<-um20.notifyTestChan
+	um20.sendState(pb.UmState_UPDATED)
+
+	// both UMs receive apply without waiting for each other
+
+	um19.step = applyStep
+	um19.continueChan <- true
+
+	um20.step = applyStep
+	um20.continueChan <- true
+
+	for _, um := range []*testUmConnection{um19, um20} {
+		select {
+		case <-um.notifyTestChan:
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("UM %s didn't receive apply request", um.umID)
+		}
+	}
+
+	// combined reboot
+
+	um19.step = rebootStep
+	um20.step = rebootStep
+
+	um19.closeConnection()
+	um20.closeConnection()
+
+	<-um19.notifyTestChan
+	<-um20.notifyTestChan
+
+	um19Components = []*pb.SystemComponent{
+		{Id: "um19C1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um19 = newTestUM(t, "testUM19", pb.UmState_IDLE, "init", um19Components)
+	go um19.processMessages()
+
+	um20Components = []*pb.SystemComponent{
+		{Id: "um20C1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um20 = newTestUM(t, "testUM20", pb.UmState_IDLE, "init", um20Components)
+	go um20.processMessages()
+
+	select {
+	case <-finishChannel:
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("Update is not finished after reboot")
+	}
+
+	etalonComponents := []cloudprotocol.ComponentStatus{
+		{ID: "um19C1", VendorVersion: "2", Status: "installed"},
+		{ID: "um20C1", VendorVersion: "2", Status: "installed"},
+	}
+
+	currentComponents, err := umCtrl.GetStatus()
+	if err != nil {
+		t.Fatalf("Can't get components info: %s", err)
+	}
+
+	if !reflect.DeepEqual(etalonComponents, currentComponents) {
+		log.Debug(currentComponents)
+		t.Error("incorrect result component list")
+	}
+
+	um19.step = finishStep
+	um20.step = finishStep
+
+	um19.closeConnection()
+	um20.closeConnection()
+
+	<-um19.notifyTestChan
+	<-um20.notifyTestChan
+
+	umCtrl.Close()
+
+	time.Sleep(time.Second)
+}
+
+func TestRevertOnPrepare(t *testing.T) {
+	umCtrlConfig := config.UMController{
+		CMServerURL:   "localhost:8091",
+		FileServerURL: "localhost:8093",
+		UMClients: []config.UMClientConfig{
+			{UMID: "testUM7", Priority: 1},
+			{UMID: "testUM8", Priority: 10},
+		},
+	}
+
+	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
+
+	var updateStorage testStorage
+
+	umCtrl, err := umcontroller.New(
+		&smConfig, &updateStorage, nil, nil, &testCryptoContext{}, true)
+	if err != nil {
+		t.Errorf("Can't create: UM controller %s", err)
+	}
+
+	um7Components := []*pb.SystemComponent{
+		{Id: "um7C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		{Id: "um7C2", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um7 := newTestUM(t, "testUM7", pb.UmState_IDLE, "init", um7Components)
+	go um7.processMessages()
+
+	um8Components := []*pb.SystemComponent{
+		{Id: "um8C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		{Id: "um8C2", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um8 := newTestUM(t, "testUM8", pb.UmState_IDLE, "init", um8Components)
+	go um8.processMessages()
+
+	componentDir, err := os.MkdirTemp("", "aosComponent_")
+	if err != nil {
+		t.Fatalf("Can't create component dir: %v", componentDir)
+	}
+
+	defer os.RemoveAll(componentDir)
+
+	updateComponents := []cloudprotocol.ComponentInfo{
+		{
+			ID: "um7C2", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile1"), kilobyte*2),
+		},
+		{
+			ID: "um8C1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile2"), kilobyte*2),
+		},
+		{
+			ID: "um8C2", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile3"), kilobyte*2),
 		},
 	}
 
@@ -913,6 +1452,223 @@ func TestRevertOnPrepare(t *testing.T) {
 	time.Sleep(time.Second)
 }
 
+func TestOrderedGroupUpdate(t *testing.T) {
+	umCtrlConfig := config.UMController{
+		CMServerURL:   "localhost:8091",
+		FileServerURL: "localhost:8093",
+		UMClients: []config.UMClientConfig{
+			{UMID: "testUM21", Priority: 1},
+			{UMID: "testUM22", Priority: 10},
+		},
+	}
+
+	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
+
+	var updateStorage testStorage
+
+	umCtrl, err := umcontroller.New(
+		&smConfig, &updateStorage, nil, nil, &testCryptoContext{}, true)
+	if err != nil {
+		t.Errorf("Can't create: UM controller %s", err)
+	}
+
+	um21Components := []*pb.SystemComponent{
+		{Id: "kernel", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um21 := newTestUM(t, "testUM21", pb.UmState_IDLE, "init", um21Components)
+	go um21.processMessages()
+
+	um22Components := []*pb.SystemComponent{
+		{Id: "bootloader", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um22 := newTestUM(t, "testUM22", pb.UmState_IDLE, "init", um22Components)
+	go um22.processMessages()
+
+	componentDir, err := os.MkdirTemp("", "aosComponent_")
+	if err != nil {
+		t.Fatalf("Can't create component dir: %v", componentDir)
+	}
+
+	defer os.RemoveAll(componentDir)
+
+	// Conflicting constraints
+
+	if _, err := umCtrl.UpdateComponents([]cloudprotocol.ComponentInfo{
+		{ID: "kernel", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"}, Dependencies: []string{"bootloader"}},
+		{ID: "bootloader", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"}, Dependencies: []string{"kernel"}},
+	}, nil, nil); err == nil {
+		t.Error("Conflicting constraints should fail")
+	}
+
+	// Bootloader is updated before kernel though its UM has lower priority
+
+	updateComponents := []cloudprotocol.ComponentInfo{
+		{
+			ID: "kernel", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"}, Dependencies: []string{"bootloader"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile1"), kilobyte*2),
+		},
+		{
+			ID: "bootloader", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile2"), kilobyte*2),
+		},
+	}
+
+	finishChannel := make(chan bool)
+
+	go func() {
+		if _, err := umCtrl.UpdateComponents(updateComponents, nil, nil); err != nil {
+			t.Errorf("Can't update components: %s", err)
+		}
+
+		close(finishChannel)
+	}()
+
+	um22.setComponents(append(um22Components,
+		&pb.SystemComponent{Id: "bootloader", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING}))
+
+	um22.step = prepareStep
+	um22.continueChan <- true
+	<-um22.notifyTestChan
+
+	if len(updateStorage.updateInfo) != 1 || updateStorage.updateInfo[0].ID != "bootloader" {
+		t.Errorf("Wrong first update group: %v", updateStorage.updateInfo)
+	}
+
+	um22.sendState(pb.UmState_PREPARED)
+
+	um22.step = updateStep
+	um22.continueChan <- true
+	<-um22.notifyTestChan
+	um22.sendState(pb.UmState_UPDATED)
+
+	um22Components = []*pb.SystemComponent{
+		{Id: "bootloader", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLED},
+	}
+	um22.setComponents(um22Components)
+
+	um22.step = applyStep
+	um22.continueChan <- true
+	<-um22.notifyTestChan
+	um22.sendState(pb.UmState_IDLE)
+
+	um21.setComponents(append(um21Components,
+		&pb.SystemComponent{Id: "kernel", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING}))
+
+	um21.step = prepareStep
+	um21.continueChan <- true
+	<-um21.notifyTestChan
+
+	if len(updateStorage.updateInfo) != 1 || updateStorage.updateInfo[0].ID != "kernel" {
+		t.Errorf("Wrong second update group: %v", updateStorage.updateInfo)
+	}
+
+	um21.sendState(pb.UmState_PREPARED)
+
+	um21.step = updateStep
+	um21.continueChan <- true
+	<-um21.notifyTestChan
+	um21.sendState(pb.UmState_UPDATED)
+
+	um21Components = []*pb.SystemComponent{
+		{Id: "kernel", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLED},
+	}
+	um21.setComponents(um21Components)
+
+	um21.step = applyStep
+	um21.continueChan <- true
+	<-um21.notifyTestChan
+	um21.sendState(pb.UmState_IDLE)
+
+	<-finishChannel
+
+	etalonComponents := []cloudprotocol.ComponentStatus{
+		{ID: "kernel", VendorVersion: "2", Status: "installed"},
+		{ID: "bootloader", VendorVersion: "2", Status: "installed"},
+	}
+
+	currentComponents, err := umCtrl.GetStatus()
+	if err != nil {
+		t.Fatalf("Can't get components info: %s", err)
+	}
+
+	if !reflect.DeepEqual(etalonComponents, currentComponents) {
+		t.Errorf("Incorrect result component list: %v", currentComponents)
+	}
+
+	// Bootloader failure skips kernel update
+
+	updateComponents = []cloudprotocol.ComponentInfo{
+		{
+			ID: "kernel", VersionInfo: aostypes.VersionInfo{VendorVersion: "3"}, Dependencies: []string{"bootloader"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile3"), kilobyte*2),
+		},
+		{
+			ID: "bootloader", VersionInfo: aostypes.VersionInfo{VendorVersion: "3"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile4"), kilobyte*2),
+		},
+	}
+
+	finishChannel = make(chan bool)
+
+	go func() {
+		if _, err := umCtrl.UpdateComponents(updateComponents, nil, nil); err == nil {
+			t.Error("Should fail")
+		}
+
+		close(finishChannel)
+	}()
+
+	um22.setComponents(append(um22Components,
+		&pb.SystemComponent{Id: "bootloader", VendorVersion: "3", Status: pb.ComponentStatus_ERROR}))
+
+	um22.step = prepareStep
+	um22.continueChan <- true
+	<-um22.notifyTestChan
+	um22.sendState(pb.UmState_FAILED)
+
+	um22.setComponents(um22Components)
+
+	um22.step = revertStep
+	um22.continueChan <- true
+	<-um22.notifyTestChan
+	um22.sendState(pb.UmState_IDLE)
+
+	<-finishChannel
+
+	currentComponents, err = umCtrl.GetStatus()
+	if err != nil {
+		t.Fatalf("Can't get components info: %s", err)
+	}
+
+	kernelSkipped := false
+
+	for _, component := range currentComponents {
+		if component.ID == "kernel" && component.VendorVersion == "3" {
+			kernelSkipped = component.Status == cloudprotocol.ErrorStatus && component.ErrorInfo != nil &&
+				component.ErrorInfo.ErrorCode == cloudprotocol.ErrorCodeDependencyFailed
+		}
+	}
+
+	if !kernelSkipped {
+		t.Errorf("Kernel update should be skipped: %v", currentComponents)
+	}
+
+	um21.step = finishStep
+	um22.step = finishStep
+
+	um21.closeConnection()
+	um22.closeConnection()
+
+	<-um21.notifyTestChan
+	<-um22.notifyTestChan
+
+	umCtrl.Close()
+
+	time.Sleep(time.Second)
+}
+
 func TestRevertOnUpdate(t *testing.T) {
 	umCtrlConfig := config.UMController{
 		CMServerURL:   "localhost:8091",
@@ -1041,11 +1797,11 @@ func TestRevertOnUpdate(t *testing.T) {
 	<-finishChannel
 
 	etalonComponents := []cloudprotocol.ComponentStatus{
-		{ID: "um9C1", VendorVersion: "1", Status: "installed"},
-		{ID: "um9C2", VendorVersion: "1", Status: "installed"},
 		{ID: "um10C1", VendorVersion: "1", Status: "installed"},
 		{ID: "um10C2", VendorVersion: "1", Status: "installed"},
 		{ID: "um10C2", VendorVersion: "2", Status: "error"},
+		{ID: "um9C1", VendorVersion: "1", Status: "installed"},
+		{ID: "um9C2", VendorVersion: "1", Status: "installed"},
 	}
 
 	currentComponents, err := umCtrl.GetStatus()
@@ -1070,6 +1826,140 @@ func TestRevertOnUpdate(t *testing.T) {
 	time.Sleep(time.Second)
 }
 
+func TestNoRevertOnUpdate(t *testing.T) {
+	umCtrlConfig := config.UMController{
+		CMServerURL:   "localhost:8091",
+		FileServerURL: "localhost:8093",
+		UMClients: []config.UMClientConfig{
+			{UMID: "testUM17", Priority: 1},
+			{UMID: "testUM18", Priority: 10},
+		},
+		DisableAutoRevert: true,
+	}
+
+	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
+
+	var updateStorage testStorage
+
+	umCtrl, err := umcontroller.New(
+		&smConfig, &updateStorage, nil, nil, &testCryptoContext{}, true)
+	if err != nil {
+		t.Errorf("Can't create: UM controller %s", err)
+	}
+
+	um17Components := []*pb.SystemComponent{
+		{Id: "um17C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um17 := newTestUM(t, "testUM17", pb.UmState_IDLE, "init", um17Components)
+	go um17.processMessages()
+
+	um18Components := []*pb.SystemComponent{
+		{Id: "um18C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	}
+
+	um18 := newTestUM(t, "testUM18", pb.UmState_IDLE, "init", um18Components)
+	go um18.processMessages()
+
+	componentDir, err := os.MkdirTemp("", "aosComponent_")
+	if err != nil {
+		t.Fatalf("Can't create component dir: %v", componentDir)
+	}
+
+	defer os.RemoveAll(componentDir)
+
+	updateComponents := []cloudprotocol.ComponentInfo{
+		{
+			ID: "um17C1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile1"), kilobyte*2),
+		},
+		{
+			ID: "um18C1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"},
+			DecryptDataStruct: prepareDecryptDataStruct(path.Join(componentDir, "someFile2"), kilobyte*2),
+		},
+	}
+
+	finishChannel := make(chan []cloudprotocol.ComponentStatus)
+
+	go func() {
+		status, err := umCtrl.UpdateComponents(updateComponents, nil, nil)
+		if err == nil {
+			t.Errorf("Should fail")
+		}
+
+		finishChannel <- status
+	}()
+
+	um17Components = append(um17Components,
+		&pb.SystemComponent{Id: "um17C1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING})
+	um17.setComponents(um17Components)
+
+	um17.step = prepareStep
+	um17.continueChan <- true
+	<-um17.notifyTestChan // receive prepare
+	um17.sendState(pb.UmState_PREPARED)
+
+	um18Components = append(um18Components,
+		&pb.SystemComponent{Id: "um18C1", VendorVersion: "2", Status: pb.ComponentStatus_INSTALLING})
+	um18.setComponents(um18Components)
+
+	um18.step = prepareStep
+	um18.continueChan <- true
+	<-um18.notifyTestChan
+	um18.sendState(pb.UmState_PREPARED)
+
+	um17.step = updateStep
+	um17.continueChan <- true
+	<-um17.notifyTestChan // um17 updated
+	um17.sendState(pb.UmState_UPDATED)
+
+	um18Components = []*pb.SystemComponent{
+		{Id: "um18C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+		{Id: "um18C1", VendorVersion: "2", Status: pb.ComponentStatus_ERROR},
+	}
+	um18.setComponents(um18Components)
+
+	um18.step = updateStep
+	um18.continueChan <- true
+	<-um18.notifyTestChan // um18 updated
+	um18.sendState(pb.UmState_FAILED)
+
+	// no revert requests are expected, any message received by UMs is an error
+	um17.step = idleStep
+	um18.step = idleStep
+
+	var currentComponents []cloudprotocol.ComponentStatus
+
+	select {
+	case currentComponents = <-finishChannel:
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait update components timeout")
+	}
+
+	errorFound := false
+
+	for _, component := range currentComponents {
+		if component.ID == "um18C1" && component.VendorVersion == "2" && component.Status == "error" {
+			errorFound = true
+		}
+	}
+
+	if !errorFound {
+		t.Errorf("Failed component should be left in error state: %v", currentComponents)
+	}
+
+	um17.closeConnection()
+	um18.closeConnection()
+
+	<-um17.notifyTestChan
+	<-um18.notifyTestChan
+
+	umCtrl.Close()
+
+	time.Sleep(time.Second)
+}
+
 func TestRevertOnUpdateWithDisconnect(t *testing.T) {
 	umCtrlConfig := config.UMController{
 		CMServerURL:   "localhost:8091",
@@ -1412,15 +2302,133 @@ func TestRevertOnUpdateWithReboot(t *testing.T) {
 	time.Sleep(time.Second)
 }
 
+func TestClientConnectionTimeout(t *testing.T) {
+	const um16Timeout = 5 * time.Second
+
+	umCtrlConfig := config.UMController{
+		CMServerURL:   "localhost:8091",
+		FileServerURL: "localhost:8093",
+		UMClients: []config.UMClientConfig{
+			{UMID: "testUM15", Priority: 1},
+			{UMID: "testUM16", Priority: 10, ConnectionTimeout: aostypes.Duration{Duration: um16Timeout}},
+		},
+		ConnectionTimeout: aostypes.Duration{Duration: 2 * time.Second},
+	}
+
+	smConfig := config.Config{UMController: umCtrlConfig, ComponentsDir: tmpDir}
+
+	// testUM16 doesn't connect: components status is reported after its own timeout
+
+	startTime := time.Now()
+
+	umCtrl, err := umcontroller.New(
+		&smConfig, &testStorage{}, nil, nil, &testCryptoContext{}, true)
+	if err != nil {
+		t.Fatalf("Can't create: UM controller %s", err)
+	}
+
+	statusChannel := make(chan []cloudprotocol.ComponentStatus, 1)
+
+	getStatus := func() {
+		currentComponents, err := umCtrl.GetStatus()
+		if err != nil {
+			t.Errorf("Can't get components info: %s", err)
+		}
+
+		statusChannel <- currentComponents
+	}
+
+	go getStatus()
+
+	um15 := newTestUM(t, "testUM15", pb.UmState_IDLE, "init", []*pb.SystemComponent{
+		{Id: "um15C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	})
+	go um15.processMessages()
+
+	select {
+	case currentComponents := <-statusChannel:
+		if elapsed := time.Since(startTime); elapsed < um16Timeout {
+			t.Errorf("Components status reported before UM connection timeout: %v", elapsed)
+		}
+
+		if len(currentComponents) != 1 || currentComponents[0].ID != "um15C1" {
+			t.Errorf("Incorrect components: %v", currentComponents)
+		}
+
+	case <-time.After(2 * um16Timeout):
+		t.Error("Wait components status timeout")
+	}
+
+	um15.closeConnection()
+
+	<-um15.notifyTestChan
+
+	umCtrl.Close()
+
+	time.Sleep(time.Second)
+
+	// testUM16 connects after the default timeout but within its own timeout
+
+	if umCtrl, err = umcontroller.New(
+		&smConfig, &testStorage{}, nil, nil, &testCryptoContext{}, true); err != nil {
+		t.Fatalf("Can't create: UM controller %s", err)
+	}
+
+	go getStatus()
+
+	um15 = newTestUM(t, "testUM15", pb.UmState_IDLE, "init", []*pb.SystemComponent{
+		{Id: "um15C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	})
+	go um15.processMessages()
+
+	select {
+	case <-statusChannel:
+		t.Error("Components status reported before all UMs connected")
+
+	case <-time.After(1500 * time.Millisecond):
+	}
+
+	um16 := newTestUM(t, "testUM16", pb.UmState_IDLE, "init", []*pb.SystemComponent{
+		{Id: "um16C1", VendorVersion: "1", Status: pb.ComponentStatus_INSTALLED},
+	})
+	go um16.processMessages()
+
+	select {
+	case currentComponents := <-statusChannel:
+		if len(currentComponents) != 2 {
+			t.Errorf("Incorrect count of components %d", len(currentComponents))
+		}
+
+	case <-time.After(um16Timeout):
+		t.Error("Wait components status timeout")
+	}
+
+	um15.closeConnection()
+	um16.closeConnection()
+
+	<-um15.notifyTestChan
+	<-um16.notifyTestChan
+
+	umCtrl.Close()
+
+	time.Sleep(time.Second)
+}
+
 /***********************************************************************************************************************
  * Interfaces
  **********************************************************************************************************************/
 
 func (storage *testStorage) GetComponentsUpdateInfo() (updateInfo []umcontroller.SystemComponent, err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	return storage.updateInfo, err
 }
 
 func (storage *testStorage) SetComponentsUpdateInfo(updateInfo []umcontroller.SystemComponent) (err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	storage.updateInfo = updateInfo
 	return aoserrors.Wrap(err)
 }
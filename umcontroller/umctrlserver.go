@@ -147,6 +147,7 @@ func (server *umCtrlServer) RegisterUM(stream pb.UMService_RegisterUMServer) (er
 
 	closeConnectionMsg := umCtrlInternalMsg{
 		umID:        statusMsg.GetUmId(),
+		handler:     handler,
 		requestType: closeConnection,
 	}
 	server.controllerCh <- closeConnectionMsg
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
 
 	"github.com/looplab/fsm"
 	log "github.com/sirupsen/logrus"
@@ -38,6 +39,7 @@ type umHandler struct {
 	stream         pb.UMService_RegisterUMServer
 	messageChannel chan umCtrlInternalMsg
 	closeChannel   chan bool
+	closeOnce      sync.Once
 	FSM            *fsm.FSM
 	initialUmState string
 }
@@ -140,10 +142,12 @@ func newUmHandler(id string, umStream pb.UMService_RegisterUMServer,
 	return handler, handler.closeChannel, aoserrors.Wrap(err)
 }
 
-// Close close connection.
+// Close close connection. It may be called few times: by controller and on stream end.
 func (handler *umHandler) Close() {
-	log.Debug("Close umhandler with UMID = ", handler.umID)
-	handler.closeChannel <- true
+	handler.closeOnce.Do(func() {
+		log.Debug("Close umhandler with UMID = ", handler.umID)
+		close(handler.closeChannel)
+	})
 }
 
 func (handler *umHandler) GetInitialState() (state string) {
@@ -181,7 +185,7 @@ func (handler *umHandler) StartRevert() (err error) {
  **********************************************************************************************************************/
 
 func (handler *umHandler) receiveData() {
-	defer func() { handler.closeChannel <- true }()
+	defer handler.Close()
 
 	for {
 		statusMsg, err := handler.stream.Recv()
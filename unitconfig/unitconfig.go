@@ -79,7 +79,9 @@ func (instance *Instance) GetStatus() (unitConfigInfo cloudprotocol.UnitConfigSt
 
 	if instance.unitConfigError != nil {
 		unitConfigInfo.Status = cloudprotocol.ErrorStatus
-		unitConfigInfo.ErrorInfo = &cloudprotocol.ErrorInfo{Message: instance.unitConfigError.Error()}
+		unitConfigInfo.ErrorInfo = &cloudprotocol.ErrorInfo{
+			ErrorCode: cloudprotocol.ErrorCodeUnitConfigFailed, Message: instance.unitConfigError.Error(),
+		}
 	}
 
 	return unitConfigInfo, nil
@@ -114,14 +116,23 @@ func (instance *Instance) CheckUnitConfig(configJSON json.RawMessage) (vendorVer
 	return vendorVersion, nil
 }
 
-func (instance *Instance) GetUnitConfiguration(nodeType string) aostypes.NodeUnitConfig {
+// GetUnitConfiguration returns node unit config. Empty node unit config is returned if there is no unit config or
+// node type is not listed in it. Error is returned if existing unit config can't be loaded.
+func (instance *Instance) GetUnitConfiguration(nodeType string) (aostypes.NodeUnitConfig, error) {
+	instance.Lock()
+	defer instance.Unlock()
+
+	if instance.unitConfigError != nil && !errors.Is(instance.unitConfigError, os.ErrNotExist) {
+		return aostypes.NodeUnitConfig{}, aoserrors.Wrap(instance.unitConfigError)
+	}
+
 	for _, node := range instance.unitConfig.Nodes {
 		if node.NodeType == nodeType {
-			return node
+			return node, nil
 		}
 	}
 
-	return aostypes.NodeUnitConfig{}
+	return aostypes.NodeUnitConfig{}, nil
 }
 
 // UpdateUnitConfig updates unit config.
@@ -119,11 +119,22 @@ func TestValidGetStatus(t *testing.T) {
 		t.Errorf("Wrong unit config version: %s", info.VendorVersion)
 	}
 
-	nodeUnitConfig := unitConfig.GetUnitConfiguration("type1")
+	nodeUnitConfig, err := unitConfig.GetUnitConfiguration("type1")
+	if err != nil {
+		t.Fatalf("Can't get node unit config: %v", err)
+	}
 
 	if nodeUnitConfig.NodeType != "type1" {
 		t.Error("Unexpected node type")
 	}
+
+	if nodeUnitConfig, err = unitConfig.GetUnitConfiguration("unknownType"); err != nil {
+		t.Fatalf("Can't get node unit config: %v", err)
+	}
+
+	if nodeUnitConfig.NodeType != "" {
+		t.Error("Empty node unit config expected")
+	}
 }
 
 func TestInvalidGetStatus(t *testing.T) {
@@ -151,6 +162,27 @@ func TestInvalidGetStatus(t *testing.T) {
 	if info.Status != cloudprotocol.ErrorStatus {
 		t.Errorf("Wrong unit config status: %s", info.Status)
 	}
+
+	if _, err = unitConfig.GetUnitConfiguration("type1"); err == nil {
+		t.Error("Error expected")
+	}
+}
+
+func TestNoUnitConfig(t *testing.T) {
+	unitConfig, err := unitconfig.New(
+		&config.Config{UnitConfigFile: path.Join(tmpDir, "not_existing_unit.cfg")}, &testClient{})
+	if err != nil {
+		t.Fatalf("Can't create unit config instance: %s", err)
+	}
+
+	nodeUnitConfig, err := unitConfig.GetUnitConfiguration("type1")
+	if err != nil {
+		t.Fatalf("Can't get node unit config: %v", err)
+	}
+
+	if nodeUnitConfig.NodeType != "" {
+		t.Error("Empty node unit config expected")
+	}
 }
 
 func TestCheckUnitConfig(t *testing.T) {
@@ -32,6 +32,7 @@ import (
 	"github.com/aosedge/aos_common/api/cloudprotocol"
 	"github.com/looplab/fsm"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
 
 	"github.com/aosedge/aos_communicationmanager/cmserver"
 	"github.com/aosedge/aos_communicationmanager/downloader"
@@ -42,6 +43,12 @@ import (
  * Consts
  **********************************************************************************************************************/
 
+// Order of unit config update relative to components update.
+const (
+	unitConfigOrderAfterComponents  = "afterComponents"
+	unitConfigOrderBeforeComponents = "beforeComponents"
+)
+
 /***********************************************************************************************************************
  * Types
  **********************************************************************************************************************/
@@ -55,6 +62,7 @@ type firmwareDownloader interface {
 type firmwareStatusHandler interface {
 	updateComponentStatus(componentInfo cloudprotocol.ComponentStatus)
 	updateUnitConfigStatus(unitConfigInfo cloudprotocol.UnitConfigStatus)
+	addUpdateHistoryEntry(entry UpdateHistoryEntry)
 }
 
 type firmwareUpdate struct {
@@ -76,10 +84,14 @@ type firmwareManager struct {
 	unitConfigUpdater UnitConfigUpdater
 	storage           Storage
 	runner            InstanceRunner
+	policyChecker     PolicyChecker
 
-	stateMachine  *updateStateMachine
-	statusMutex   sync.RWMutex
-	pendingUpdate *firmwareUpdate
+	stateMachine      *updateStateMachine
+	statusMutex       sync.RWMutex
+	pendingUpdate     *firmwareUpdate
+	updateInterrupted bool
+	continueOnError   bool
+	unitConfigFirst   bool
 
 	ComponentStatuses map[string]*cloudprotocol.ComponentStatus `json:"componentStatuses,omitempty"`
 	UnitConfigStatus  cloudprotocol.UnitConfigStatus            `json:"unitConfigStatus,omitempty"`
@@ -88,6 +100,8 @@ type firmwareManager struct {
 	CurrentState      string                                    `json:"currentState,omitempty"`
 	UpdateErr         string                                    `json:"updateErr,omitempty"`
 	TTLDate           time.Time                                 `json:"ttlDate,omitempty"`
+	UpdateStartTime   time.Time                                 `json:"updateStartTime,omitempty"`
+	DroppedComponents []string                                  `json:"droppedComponents,omitempty"`
 }
 
 /***********************************************************************************************************************
@@ -96,7 +110,7 @@ type firmwareManager struct {
 
 func newFirmwareManager(statusHandler firmwareStatusHandler, downloader firmwareDownloader,
 	firmwareUpdater FirmwareUpdater, unitConfigUpdater UnitConfigUpdater,
-	storage Storage, runner InstanceRunner, defaultTTL time.Duration,
+	storage Storage, runner InstanceRunner, defaultTTL time.Duration, continueOnError bool,
 ) (manager *firmwareManager, err error) {
 	manager = &firmwareManager{
 		statusChannel:     make(chan cmserver.UpdateFOTAStatus, 1),
@@ -106,6 +120,8 @@ func newFirmwareManager(statusHandler firmwareStatusHandler, downloader firmware
 		unitConfigUpdater: unitConfigUpdater,
 		storage:           storage,
 		runner:            runner,
+		policyChecker:     allowAllPolicy{},
+		continueOnError:   continueOnError,
 		CurrentState:      stateNoUpdate,
 	}
 
@@ -113,6 +129,9 @@ func newFirmwareManager(statusHandler firmwareStatusHandler, downloader firmware
 		return nil, aoserrors.Wrap(err)
 	}
 
+	// Update could be interrupted by unit restart: check UM status before continue it
+	manager.updateInterrupted = manager.CurrentState == stateUpdating
+
 	log.WithFields(log.Fields{"state": manager.CurrentState, "error": manager.UpdateErr}).Debug("New firmware manager")
 
 	manager.stateMachine = newUpdateStateMachine(manager.CurrentState, fsm.Events{
@@ -128,6 +147,10 @@ func newFirmwareManager(statusHandler firmwareStatusHandler, downloader firmware
 		{Name: eventFinishUpdate, Src: []string{stateUpdating}, Dst: stateNoUpdate},
 	}, manager, defaultTTL)
 
+	// Resumed update may finish before init is done: init under lock as other state machine transitions
+	manager.Lock()
+	defer manager.Unlock()
+
 	if err = manager.stateMachine.init(manager.TTLDate); err != nil {
 		return nil, aoserrors.Wrap(err)
 	}
@@ -150,6 +173,36 @@ func (manager *firmwareManager) close() (err error) {
 	return nil
 }
 
+func (manager *firmwareManager) setUnitConfigOrder(order string) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	switch order {
+	case "", unitConfigOrderAfterComponents:
+		manager.unitConfigFirst = false
+
+	case unitConfigOrderBeforeComponents:
+		manager.unitConfigFirst = true
+
+	default:
+		log.Warnf("Unknown unit config order %s, use %s", order, unitConfigOrderAfterComponents)
+	}
+}
+
+func (manager *firmwareManager) setPolicyChecker(checker PolicyChecker) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	manager.policyChecker = checker
+}
+
+func (manager *firmwareManager) setClock(clock Clock) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	manager.stateMachine.setClock(clock)
+}
+
 func (manager *firmwareManager) getCurrentStatus() (status cmserver.UpdateFOTAStatus) {
 	status.State = convertState(manager.CurrentState)
 	status.Error = manager.UpdateErr
@@ -158,6 +211,13 @@ func (manager *firmwareManager) getCurrentStatus() (status cmserver.UpdateFOTASt
 		return status
 	}
 
+	status.TTLDate = manager.TTLDate
+	status.Emergency = manager.CurrentUpdate.Schedule.Emergency
+
+	if status.State == cmserver.ReadyToUpdate {
+		status.NextWindow = manager.stateMachine.getNextWindow()
+	}
+
 	for _, component := range manager.CurrentUpdate.Components {
 		status.Components = append(status.Components, cloudprotocol.ComponentStatus{
 			ID: component.ID, AosVersion: component.AosVersion, VendorVersion: component.VendorVersion,
@@ -176,6 +236,12 @@ func (manager *firmwareManager) processDesiredStatus(desiredStatus cloudprotocol
 	manager.Lock()
 	defer manager.Unlock()
 
+	if desiredStatus.FOTASchedule.Type == cloudprotocol.TimetableUpdate {
+		if err := ValidateTimetable(desiredStatus.FOTASchedule.Timetable); err != nil {
+			return aoserrors.Wrap(err)
+		}
+	}
+
 	update := &firmwareUpdate{
 		Schedule:   desiredStatus.FOTASchedule,
 		UnitConfig: desiredStatus.UnitConfig,
@@ -191,15 +257,21 @@ func (manager *firmwareManager) processDesiredStatus(desiredStatus cloudprotocol
 
 desiredLoop:
 	for _, desiredComponent := range desiredStatus.Components {
+		if !desiredComponent.ForceDownload && isComponentUpToDate(desiredComponent, installedComponents) {
+			continue
+		}
+
 		for _, installedComponent := range installedComponents {
-			if desiredComponent.ID == installedComponent.ID {
-				if desiredComponent.VendorVersion == installedComponent.VendorVersion &&
-					installedComponent.Status == cloudprotocol.InstalledStatus {
-					continue desiredLoop
-				} else {
-					update.Components = append(update.Components, desiredComponent)
+			if desiredComponent.ID == installedComponent.ID && isComponentTarget(desiredComponent, installedComponent) {
+				installed := installedComponent
+
+				if err := manager.policyChecker.CheckComponent(desiredComponent, &installed); err != nil {
+					manager.rejectComponent(desiredComponent, err)
 					continue desiredLoop
 				}
+
+				update.Components = append(update.Components, desiredComponent)
+				continue desiredLoop
 			}
 		}
 
@@ -213,6 +285,8 @@ desiredLoop:
 		if err = manager.newUpdate(update); err != nil {
 			return aoserrors.Wrap(err)
 		}
+	} else {
+		manager.updateSchedule(update.Schedule)
 	}
 
 	return nil
@@ -231,6 +305,29 @@ func (manager *firmwareManager) startUpdate() (err error) {
 	return nil
 }
 
+func (manager *firmwareManager) extendTTL(extension time.Duration) (err error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	log.WithField("extension", extension).Debug("Extend firmware update TTL")
+
+	if manager.CurrentUpdate == nil || manager.CurrentUpdate.Schedule.Type != cloudprotocol.TriggerUpdate {
+		return aoserrors.New("no trigger update to extend")
+	}
+
+	if manager.TTLDate, err = manager.stateMachine.extendTTL(manager.TTLDate, extension); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if err = manager.saveState(); err != nil {
+		log.Errorf("Can't save current firmware manager state: %s", err)
+	}
+
+	manager.sendCurrentStatus()
+
+	return nil
+}
+
 func (manager *firmwareManager) getComponentStatuses() (status []cloudprotocol.ComponentStatus, err error) {
 	manager.Lock()
 	defer manager.Unlock()
@@ -297,17 +394,26 @@ func (manager *firmwareManager) stateChanged(event, state string, updateErr stri
 	if event == eventCancel {
 		for id, status := range manager.ComponentStatuses {
 			if status.Status != cloudprotocol.ErrorStatus {
-				manager.updateComponentStatusByID(id, cloudprotocol.ErrorStatus, updateErr)
+				manager.updateComponentStatusByID(id, cloudprotocol.ErrorStatus,
+					newErrorInfo(getCancelErrorCode(updateErr), updateErr))
 			}
 		}
 
 		if len(manager.CurrentUpdate.UnitConfig) != 0 {
 			if manager.UnitConfigStatus.Status != cloudprotocol.ErrorStatus {
-				manager.updateUnitConfigStatus(cloudprotocol.ErrorStatus, updateErr)
+				manager.updateUnitConfigStatus(cloudprotocol.ErrorStatus, newErrorInfo(getCancelErrorCode(updateErr), updateErr))
 			}
 		}
 	}
 
+	if event == eventStartDownload {
+		manager.UpdateStartTime = manager.stateMachine.clock.Now()
+	}
+
+	if state == stateNoUpdate && manager.CurrentState != stateNoUpdate {
+		manager.addUpdateHistoryEntry(event, updateErr)
+	}
+
 	manager.CurrentState = state
 	manager.UpdateErr = updateErr
 
@@ -371,32 +477,84 @@ func (manager *firmwareManager) download(ctx context.Context) {
 	}()
 
 	manager.DownloadResult = nil
+	manager.DroppedComponents = nil
 
 	if len(manager.CurrentUpdate.UnitConfig) != 0 {
-		manager.UnitConfigStatus.VendorVersion = ""
+		if downloadErr = manager.checkUnitConfigVersion(); downloadErr != "" {
+			return
+		}
+	}
+
+	request := manager.prepareDownloadRequest()
 
-		version, err := manager.unitConfigUpdater.GetUnitConfigVersion(manager.CurrentUpdate.UnitConfig)
+	// Nothing to download
+	if len(request) == 0 {
+		return
+	}
 
-		manager.UnitConfigStatus.VendorVersion = version
+	manager.DownloadResult = manager.downloader.download(
+		ctx, request, manager.continueOnError, manager.updateComponentStatusByID)
 
-		if err != nil {
-			log.Errorf("Error getting unit config version: %s", err)
+	downloadErr = getDownloadError(manager.DownloadResult)
 
-			downloadErr = aoserrors.Wrap(err).Error()
-			manager.updateUnitConfigStatus(cloudprotocol.ErrorStatus, downloadErr)
+	if manager.continueOnError && downloadErr != "" {
+		manager.dropFailedComponents()
 
-			return
+		// Continue if there is something to update
+		if len(manager.DroppedComponents) < len(manager.CurrentUpdate.Components) ||
+			len(manager.CurrentUpdate.UnitConfig) != 0 {
+			downloadErr = ""
 		}
+	}
 
-		log.WithFields(log.Fields{"version": version}).Debug("Get unit config version")
+	for id, item := range manager.ComponentStatuses {
+		if item.ErrorInfo != nil {
+			log.WithFields(log.Fields{
+				"id":      item.ID,
+				"version": item.VendorVersion,
+			}).Errorf("Error downloading component: %s", item.ErrorInfo.Message)
+
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"id":      item.ID,
+			"version": item.VendorVersion,
+		}).Debug("Component successfully downloaded")
+
+		manager.updateComponentStatusByID(id, cloudprotocol.PendingStatus, nil)
+	}
+}
+
+func (manager *firmwareManager) checkUnitConfigVersion() (downloadErr string) {
+	manager.UnitConfigStatus.VendorVersion = ""
+
+	version, err := manager.unitConfigUpdater.GetUnitConfigVersion(manager.CurrentUpdate.UnitConfig)
 
-		manager.updateUnitConfigStatus(cloudprotocol.PendingStatus, "")
+	manager.UnitConfigStatus.VendorVersion = version
+
+	if err != nil {
+		log.Errorf("Error getting unit config version: %s", err)
+
+		downloadErr = aoserrors.Wrap(err).Error()
+		manager.updateUnitConfigStatus(cloudprotocol.ErrorStatus,
+			newErrorInfo(cloudprotocol.ErrorCodeUnitConfigFailed, downloadErr))
+
+		return downloadErr
 	}
 
+	log.WithFields(log.Fields{"version": version}).Debug("Get unit config version")
+
+	manager.updateUnitConfigStatus(cloudprotocol.PendingStatus, nil)
+
+	return ""
+}
+
+func (manager *firmwareManager) prepareDownloadRequest() (request map[string]downloader.PackageInfo) {
 	manager.statusMutex.Lock()
 
 	manager.ComponentStatuses = make(map[string]*cloudprotocol.ComponentStatus)
-	request := make(map[string]downloader.PackageInfo)
+	request = make(map[string]downloader.PackageInfo)
 
 	for _, component := range manager.CurrentUpdate.Components {
 		log.WithFields(log.Fields{
@@ -413,6 +571,7 @@ func (manager *firmwareManager) download(ctx context.Context) {
 			TargetID:            component.ID,
 			TargetAosVersion:    component.AosVersion,
 			TargetVendorVersion: component.VendorVersion,
+			ForceDownload:       component.ForceDownload,
 		}
 		manager.ComponentStatuses[component.ID] = &cloudprotocol.ComponentStatus{
 			ID:            component.ID,
@@ -424,35 +583,34 @@ func (manager *firmwareManager) download(ctx context.Context) {
 
 	manager.statusMutex.Unlock()
 
-	// Nothing to download
-	if len(request) == 0 {
-		return
-	}
+	return request
+}
 
-	manager.DownloadResult = manager.downloader.download(ctx, request, false, manager.updateComponentStatusByID)
+func (manager *firmwareManager) readyToUpdate() {
+	manager.stateMachine.scheduleUpdate(manager.CurrentUpdate.Schedule)
+}
 
-	downloadErr = getDownloadError(manager.DownloadResult)
+func (manager *firmwareManager) clockNotSynchronized(reason string) {
+	status := manager.getCurrentStatus()
+	status.Error = reason
 
-	for id, item := range manager.ComponentStatuses {
-		if item.ErrorInfo != nil {
-			log.WithFields(log.Fields{
-				"id":      item.ID,
-				"version": item.VendorVersion,
-			}).Errorf("Error downloading component: %s", item.ErrorInfo.Message)
+	manager.statusChannel <- status
+}
 
-			continue
-		}
+func (manager *firmwareManager) updateDeferred() {
+	manager.sendCurrentStatus()
+}
 
-		log.WithFields(log.Fields{
-			"id":      item.ID,
-			"version": item.VendorVersion,
-		}).Debug("Component successfully downloaded")
+func (manager *firmwareManager) retrySchedule() {
+	manager.Lock()
+	defer manager.Unlock()
 
-		manager.updateComponentStatusByID(id, cloudprotocol.PendingStatus, "")
+	if manager.CurrentState != stateReadyToUpdate {
+		return
 	}
-}
 
-func (manager *firmwareManager) readyToUpdate() {
+	log.Debug("Retry firmware update schedule")
+
 	manager.stateMachine.scheduleUpdate(manager.CurrentUpdate.Schedule)
 }
 
@@ -468,21 +626,32 @@ func (manager *firmwareManager) update(ctx context.Context) {
 		}()
 	}()
 
-	if len(manager.CurrentUpdate.Components) != 0 {
-		if err := manager.updateComponents(ctx); err != "" {
-			updateErr = err
+	componentsApplied := false
+
+	if manager.updateInterrupted {
+		manager.updateInterrupted = false
+
+		if componentsApplied, updateErr = manager.checkInterruptedUpdate(); updateErr != "" {
 			return
 		}
 	}
 
-	if len(manager.CurrentUpdate.UnitConfig) != 0 {
-		if err := manager.updateUnitConfig(ctx); err != "" {
+	// Unit config is applied before or after components depending on configured order
+	if manager.unitConfigFirst {
+		if updateErr = manager.applyUnitConfig(ctx); updateErr != "" {
+			return
+		}
+	}
+
+	if len(manager.CurrentUpdate.Components) != 0 && !componentsApplied {
+		if err := manager.updateComponents(ctx); err != "" {
 			updateErr = err
 			return
 		}
+	}
 
-		if err := manager.runner.RestartInstances(); err != nil {
-			updateErr = err.Error()
+	if !manager.unitConfigFirst {
+		if updateErr = manager.applyUnitConfig(ctx); updateErr != "" {
 			return
 		}
 	}
@@ -493,7 +662,7 @@ func (manager *firmwareManager) updateTimeout() {
 	defer manager.Unlock()
 
 	if manager.stateMachine.canTransit(eventCancel) {
-		if err := manager.stateMachine.sendEvent(eventCancel, aoserrors.New("update timeout").Error()); err != nil {
+		if err := manager.stateMachine.sendEvent(eventCancel, aoserrors.Wrap(errUpdateTimeout).Error()); err != nil {
 			log.Errorf("Can't cancel update: %s", err)
 		}
 	}
@@ -511,12 +680,7 @@ func (manager *firmwareManager) newUpdate(update *firmwareUpdate) (err error) {
 	case "":
 		update.Schedule.Type = cloudprotocol.ForceUpdate
 
-	case cloudprotocol.TimetableUpdate:
-		if err = validateTimetable(update.Schedule.Timetable); err != nil {
-			return aoserrors.Wrap(err)
-		}
-
-	case cloudprotocol.ForceUpdate, cloudprotocol.TriggerUpdate:
+	case cloudprotocol.ForceUpdate, cloudprotocol.TriggerUpdate, cloudprotocol.TimetableUpdate:
 
 	default:
 		return aoserrors.New("wrong update type")
@@ -564,6 +728,29 @@ func (manager *firmwareManager) newUpdate(update *firmwareUpdate) (err error) {
 	return nil
 }
 
+// updateSchedule stores new schedule when desired status doesn't require any firmware update.
+func (manager *firmwareManager) updateSchedule(schedule cloudprotocol.ScheduleRule) {
+	if manager.CurrentState != stateNoUpdate {
+		return
+	}
+
+	if manager.CurrentUpdate == nil {
+		manager.CurrentUpdate = &firmwareUpdate{}
+	}
+
+	if reflect.DeepEqual(manager.CurrentUpdate.Schedule, schedule) {
+		return
+	}
+
+	log.WithField("type", schedule.Type).Debug("Firmware update schedule changed")
+
+	manager.CurrentUpdate.Schedule = schedule
+
+	if err := manager.saveState(); err != nil {
+		log.Errorf("Can't save current firmware manager state: %s", err)
+	}
+}
+
 func (manager *firmwareManager) updateComponents(ctx context.Context) (componentsErr string) {
 	defer func() {
 		switch {
@@ -571,6 +758,10 @@ func (manager *firmwareManager) updateComponents(ctx context.Context) (component
 
 		case componentsErr == "":
 			for _, status := range manager.ComponentStatuses {
+				if slices.Contains(manager.DroppedComponents, status.ID) {
+					continue
+				}
+
 				log.WithFields(log.Fields{
 					"id":      status.ID,
 					"version": status.VendorVersion,
@@ -580,8 +771,8 @@ func (manager *firmwareManager) updateComponents(ctx context.Context) (component
 		default:
 			for id, status := range manager.ComponentStatuses {
 				if status.Status != cloudprotocol.ErrorStatus {
-					manager.updateComponentStatusByID(id, cloudprotocol.ErrorStatus,
-						fmt.Sprintf("update aborted due to error: %s", componentsErr))
+					manager.updateComponentStatusByID(id, cloudprotocol.ErrorStatus, newErrorInfo(
+						cloudprotocol.ErrorCodeInstallFailed, fmt.Sprintf("update aborted due to error: %s", componentsErr)))
 				}
 
 				log.WithFields(log.Fields{
@@ -592,18 +783,20 @@ func (manager *firmwareManager) updateComponents(ctx context.Context) (component
 		}
 	}()
 
-	updateComponents := make([]cloudprotocol.ComponentInfo, 0, len(manager.CurrentUpdate.Components))
+	components := manager.getComponentsToUpdate()
+	updateComponents := make([]cloudprotocol.ComponentInfo, 0, len(components))
 
-	for _, component := range manager.CurrentUpdate.Components {
+	for _, component := range components {
 		log.WithFields(log.Fields{"id": component.ID, "version": component.VendorVersion}).Debug("Update component")
 
-		manager.updateComponentStatusByID(component.ID, cloudprotocol.InstallingStatus, "")
+		manager.updateComponentStatusByID(component.ID, cloudprotocol.InstallingStatus, nil)
 
 		downloadInfo, ok := manager.DownloadResult[component.ID]
 		if !ok {
 			err := aoserrors.New("update ID not found").Error()
 
-			manager.updateComponentStatusByID(component.ID, cloudprotocol.ErrorStatus, err)
+			manager.updateComponentStatusByID(component.ID, cloudprotocol.ErrorStatus,
+				newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, err))
 
 			return err
 		}
@@ -618,6 +811,10 @@ func (manager *firmwareManager) updateComponents(ctx context.Context) (component
 		updateComponents = append(updateComponents, component)
 	}
 
+	if len(updateComponents) == 0 {
+		return ""
+	}
+
 	select {
 	case errStr := <-manager.asyncUpdate(updateComponents):
 		return errStr
@@ -633,11 +830,162 @@ func (manager *firmwareManager) updateComponents(ctx context.Context) (component
 	}
 }
 
+func (manager *firmwareManager) checkInterruptedUpdate() (applied bool, updateErr string) {
+	if len(manager.CurrentUpdate.Components) == 0 {
+		return false, ""
+	}
+
+	log.Debug("Check interrupted firmware update")
+
+	defer func() {
+		if updateErr == "" {
+			return
+		}
+
+		for id, status := range manager.ComponentStatuses {
+			if status.Status != cloudprotocol.ErrorStatus {
+				manager.updateComponentStatusByID(id, cloudprotocol.ErrorStatus,
+					newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, updateErr))
+			}
+		}
+	}()
+
+	installedComponents, err := manager.firmwareUpdater.GetStatus()
+	if err != nil {
+		return false, aoserrors.Wrap(err).Error()
+	}
+
+	applied = true
+
+	for _, component := range manager.getComponentsToUpdate() {
+		status := findComponentStatus(installedComponents, component.ID, component.VendorVersion)
+		if status == nil {
+			applied = false
+			continue
+		}
+
+		switch status.Status {
+		case cloudprotocol.InstalledStatus:
+
+		case cloudprotocol.ErrorStatus:
+			applied = false
+
+		default:
+			// UM is still processing the update: continue waiting for the result
+			log.WithFields(log.Fields{
+				"id": component.ID, "version": component.VendorVersion, "status": status.Status,
+			}).Debug("Component update in progress")
+
+			return false, ""
+		}
+	}
+
+	if applied {
+		log.Debug("Interrupted firmware update already applied")
+
+		for id := range manager.ComponentStatuses {
+			if !slices.Contains(manager.DroppedComponents, id) {
+				manager.updateComponentStatusByID(id, cloudprotocol.InstalledStatus, nil)
+			}
+		}
+
+		return true, ""
+	}
+
+	// UM is idle at previous versions: retry the update if downloaded firmware is still available
+	for _, component := range manager.getComponentsToUpdate() {
+		if result, ok := manager.DownloadResult[component.ID]; !ok || result.Error != "" {
+			return false, aoserrors.New("update interrupted").Error()
+		}
+	}
+
+	log.Debug("Retry interrupted firmware update")
+
+	return false, ""
+}
+
+// dropFailedComponents drops components failed to download and components depending on them from the current
+// update. Dropped components are reported as skipped.
+func (manager *firmwareManager) dropFailedComponents() {
+	for _, component := range manager.CurrentUpdate.Components {
+		if result, ok := manager.DownloadResult[component.ID]; ok && result.Error != "" {
+			manager.DroppedComponents = append(manager.DroppedComponents, component.ID)
+			manager.updateComponentStatusByID(component.ID, cloudprotocol.ErrorStatus,
+				newErrorInfo(cloudprotocol.ErrorCodeDownloadFailed, fmt.Sprintf("component skipped: %s", result.Error)))
+		}
+	}
+
+	for dropped := true; dropped; {
+		dropped = false
+
+		for _, component := range manager.CurrentUpdate.Components {
+			if slices.Contains(manager.DroppedComponents, component.ID) {
+				continue
+			}
+
+			for _, dependency := range component.Dependencies {
+				if !slices.Contains(manager.DroppedComponents, dependency) {
+					continue
+				}
+
+				manager.DroppedComponents = append(manager.DroppedComponents, component.ID)
+				manager.updateComponentStatusByID(component.ID, cloudprotocol.ErrorStatus, newErrorInfo(
+					cloudprotocol.ErrorCodeDependencyFailed,
+					fmt.Sprintf("component skipped: depends on failed component %s", dependency)))
+
+				dropped = true
+
+				break
+			}
+		}
+	}
+
+	for _, id := range manager.DroppedComponents {
+		log.WithField("id", id).Warn("Component skipped")
+	}
+}
+
+func (manager *firmwareManager) getComponentsToUpdate() (components []cloudprotocol.ComponentInfo) {
+	for _, component := range manager.CurrentUpdate.Components {
+		if !slices.Contains(manager.DroppedComponents, component.ID) {
+			components = append(components, component)
+		}
+	}
+
+	return components
+}
+
+func (manager *firmwareManager) addUpdateHistoryEntry(event, updateErr string) {
+	entry := UpdateHistoryEntry{
+		Type:      UpdateTypeFOTA,
+		StartTime: manager.UpdateStartTime,
+		EndTime:   manager.stateMachine.clock.Now(),
+		Result:    getUpdateResult(event, updateErr),
+		Error:     updateErr,
+	}
+
+	if manager.CurrentUpdate != nil {
+		entry.Emergency = manager.CurrentUpdate.Schedule.Emergency
+
+		if len(manager.CurrentUpdate.UnitConfig) != 0 {
+			entry.Targets = append(entry.Targets, UpdateTarget{
+				ID: "unitConfig", Version: manager.UnitConfigStatus.VendorVersion,
+			})
+		}
+
+		for _, component := range manager.CurrentUpdate.Components {
+			entry.Targets = append(entry.Targets, UpdateTarget{ID: component.ID, Version: component.VendorVersion})
+		}
+	}
+
+	manager.statusHandler.addUpdateHistoryEntry(entry)
+}
+
 func (manager *firmwareManager) sendCurrentStatus() {
 	manager.statusChannel <- manager.getCurrentStatus()
 }
 
-func (manager *firmwareManager) updateComponentStatusByID(id, status, componentErr string) {
+func (manager *firmwareManager) updateComponentStatusByID(id, status string, errorInfo *cloudprotocol.ErrorInfo) {
 	manager.statusMutex.Lock()
 	defer manager.statusMutex.Unlock()
 
@@ -649,13 +997,55 @@ func (manager *firmwareManager) updateComponentStatusByID(id, status, componentE
 
 	info.Status = status
 
-	if componentErr != "" {
-		info.ErrorInfo = &cloudprotocol.ErrorInfo{Message: componentErr}
+	if errorInfo != nil {
+		info.ErrorInfo = errorInfo
 	}
 
 	manager.statusHandler.updateComponentStatus(*info)
 }
 
+func (manager *firmwareManager) rejectComponent(component cloudprotocol.ComponentInfo, err error) {
+	log.WithFields(log.Fields{
+		"id":            component.ID,
+		"vendorVersion": component.VendorVersion,
+	}).Warnf("Component rejected by policy: %v", err)
+
+	manager.statusHandler.updateComponentStatus(cloudprotocol.ComponentStatus{
+		ID: component.ID, AosVersion: component.AosVersion, VendorVersion: component.VendorVersion,
+		Status: cloudprotocol.ErrorStatus, ErrorInfo: policyErrorInfo(err),
+	})
+}
+
+// rejectDesiredStatus reports desired components which require update as failed.
+func (manager *firmwareManager) rejectDesiredStatus(desiredStatus cloudprotocol.DesiredStatus, err error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	log.Errorf("Firmware desired status rejected: %v", err)
+
+	installedComponents, getErr := manager.firmwareUpdater.GetStatus()
+	if getErr != nil {
+		log.Errorf("Can't get components status: %v", getErr)
+	}
+
+desiredLoop:
+	for _, desiredComponent := range desiredStatus.Components {
+		for _, installedComponent := range installedComponents {
+			if desiredComponent.ID == installedComponent.ID &&
+				desiredComponent.VendorVersion == installedComponent.VendorVersion &&
+				installedComponent.Status == cloudprotocol.InstalledStatus {
+				continue desiredLoop
+			}
+		}
+
+		manager.statusHandler.updateComponentStatus(cloudprotocol.ComponentStatus{
+			ID: desiredComponent.ID, AosVersion: desiredComponent.AosVersion,
+			VendorVersion: desiredComponent.VendorVersion, Status: cloudprotocol.ErrorStatus,
+			ErrorInfo: &cloudprotocol.ErrorInfo{ErrorCode: cloudprotocol.ErrorCodeFailed, Message: err.Error()},
+		})
+	}
+}
+
 func (manager *firmwareManager) loadState() (err error) {
 	stateJSON, err := manager.storage.GetFirmwareUpdateState()
 	if err != nil {
@@ -686,12 +1076,29 @@ func (manager *firmwareManager) saveState() (err error) {
 	return nil
 }
 
+func (manager *firmwareManager) applyUnitConfig(ctx context.Context) (updateErr string) {
+	if len(manager.CurrentUpdate.UnitConfig) == 0 {
+		return ""
+	}
+
+	if updateErr = manager.updateUnitConfig(ctx); updateErr != "" {
+		return updateErr
+	}
+
+	if err := manager.runner.RestartInstances(); err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
+
 func (manager *firmwareManager) updateUnitConfig(ctx context.Context) (unitConfigErr string) {
 	log.Debug("Update unit config")
 
 	defer func() {
 		if unitConfigErr != "" {
-			manager.updateUnitConfigStatus(cloudprotocol.ErrorStatus, unitConfigErr)
+			manager.updateUnitConfigStatus(cloudprotocol.ErrorStatus,
+				newErrorInfo(cloudprotocol.ErrorCodeUnitConfigFailed, unitConfigErr))
 		}
 	}()
 
@@ -699,7 +1106,7 @@ func (manager *firmwareManager) updateUnitConfig(ctx context.Context) (unitConfi
 		if errors.Is(err, unitconfig.ErrAlreadyInstalled) {
 			log.Error("Unit config already installed")
 
-			manager.updateUnitConfigStatus(cloudprotocol.InstalledStatus, "")
+			manager.updateUnitConfigStatus(cloudprotocol.InstalledStatus, nil)
 
 			return ""
 		}
@@ -707,13 +1114,13 @@ func (manager *firmwareManager) updateUnitConfig(ctx context.Context) (unitConfi
 		return aoserrors.Wrap(err).Error()
 	}
 
-	manager.updateUnitConfigStatus(cloudprotocol.InstallingStatus, "")
+	manager.updateUnitConfigStatus(cloudprotocol.InstallingStatus, nil)
 
 	if err := manager.unitConfigUpdater.UpdateUnitConfig(manager.CurrentUpdate.UnitConfig); err != nil {
 		return aoserrors.Wrap(err).Error()
 	}
 
-	manager.updateUnitConfigStatus(cloudprotocol.InstalledStatus, "")
+	manager.updateUnitConfigStatus(cloudprotocol.InstalledStatus, nil)
 
 	return ""
 }
@@ -741,7 +1148,8 @@ func (manager *firmwareManager) asyncUpdate(
 						}
 					}
 
-					manager.updateComponentStatusByID(id, item.Status, errorStr)
+					manager.updateComponentStatusByID(id, item.Status,
+						newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, errorStr))
 				}
 			}
 		}
@@ -752,19 +1160,60 @@ func (manager *firmwareManager) asyncUpdate(
 	return finishChannel
 }
 
-func (manager *firmwareManager) updateUnitConfigStatus(status, errorStr string) {
+func (manager *firmwareManager) updateUnitConfigStatus(status string, errorInfo *cloudprotocol.ErrorInfo) {
 	manager.statusMutex.Lock()
 	defer manager.statusMutex.Unlock()
 
 	manager.UnitConfigStatus.Status = status
 
-	if errorStr != "" {
-		manager.UnitConfigStatus.ErrorInfo = &cloudprotocol.ErrorInfo{Message: errorStr}
+	if errorInfo != nil {
+		manager.UnitConfigStatus.ErrorInfo = errorInfo
 	}
 
 	manager.statusHandler.updateUnitConfigStatus(manager.UnitConfigStatus)
 }
 
+func findComponentStatus(
+	statuses []cloudprotocol.ComponentStatus, id, vendorVersion string,
+) (status *cloudprotocol.ComponentStatus) {
+	for i := range statuses {
+		if statuses[i].ID == id && statuses[i].VendorVersion == vendorVersion {
+			return &statuses[i]
+		}
+	}
+
+	return nil
+}
+
+// isComponentTarget returns true if component status belongs to UM targeted by desired component. Status without UM
+// ID belongs to the only UM which holds the component.
+func isComponentTarget(desiredComponent cloudprotocol.ComponentInfo, component cloudprotocol.ComponentStatus) bool {
+	return len(desiredComponent.UMIDs) == 0 || component.UMID == "" ||
+		slices.Contains(desiredComponent.UMIDs, component.UMID)
+}
+
+// isComponentUpToDate returns true if desired component version is installed on all targeted UMs.
+func isComponentUpToDate(
+	desiredComponent cloudprotocol.ComponentInfo, installedComponents []cloudprotocol.ComponentStatus,
+) bool {
+	found := false
+
+	for _, component := range installedComponents {
+		if component.ID != desiredComponent.ID || component.Status != cloudprotocol.InstalledStatus ||
+			!isComponentTarget(desiredComponent, component) {
+			continue
+		}
+
+		if component.VendorVersion != desiredComponent.VendorVersion {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
+
 func unitConfigsEqual(config1, config2 json.RawMessage) (equal bool) {
 	var configData1, configData2 interface{}
 
@@ -37,10 +37,14 @@ type downloadResult struct {
 	Error    string `json:"error"`
 }
 
-type statusNotifier func(id string, status string, componentErr string)
+type statusNotifier func(id string, status string, errorInfo *cloudprotocol.ErrorInfo)
 
 type groupDownloader struct {
 	Downloader
+
+	sync.Mutex
+	// IDs of current download request mapped to canceled flag
+	downloads map[string]bool
 }
 
 /***********************************************************************************************************************
@@ -54,13 +58,13 @@ func newGroupDownloader(fileDownloader Downloader) *groupDownloader {
 func (downloader *groupDownloader) download(ctx context.Context, request map[string]downloader.PackageInfo,
 	continueOnError bool, updateStatus statusNotifier,
 ) (result map[string]*downloadResult) {
-	result = make(map[string]*downloadResult)
-
-	for id := range request {
-		result[id] = &downloadResult{}
+	result = downloader.startDownloads(request, updateStatus)
 
-		updateStatus(id, cloudprotocol.DownloadingStatus, "")
-	}
+	defer func() {
+		downloader.Lock()
+		downloader.downloads = nil
+		downloader.Unlock()
+	}()
 
 	downloadCtx, cancelFunc := context.WithCancel(ctx)
 	defer cancelFunc()
@@ -68,17 +72,17 @@ func (downloader *groupDownloader) download(ctx context.Context, request map[str
 	var wg sync.WaitGroup
 
 	handleError := func(id string, err error) {
-		if errorStr := aoserrors.Wrap(err).Error(); !isCancelError(errorStr) {
-			result[id].Error = errorStr
-			updateStatus(id, cloudprotocol.ErrorStatus, errorStr)
-		}
-
-		if !continueOnError {
+		if canceled := downloader.setDownloadError(id, err, result[id], updateStatus); !canceled && !continueOnError {
 			cancelFunc()
 		}
 	}
 
 	for id, item := range request {
+		if downloader.isCanceled(id) {
+			downloader.setDownloadCanceled(id, result[id], updateStatus)
+			continue
+		}
+
 		itemResult, err := downloader.Download(downloadCtx, item)
 		if err != nil {
 			handleError(id, err)
@@ -102,7 +106,12 @@ func (downloader *groupDownloader) download(ctx context.Context, request map[str
 				return
 			}
 
-			updateStatus(id, cloudprotocol.DownloadedStatus, "")
+			if downloader.isCanceled(id) {
+				downloader.setDownloadCanceled(id, result[id], updateStatus)
+				return
+			}
+
+			updateStatus(id, cloudprotocol.DownloadedStatus, nil)
 		}(id)
 	}
 
@@ -112,17 +121,38 @@ func (downloader *groupDownloader) download(ctx context.Context, request map[str
 		// Download canceled: set cancel state for already downloaded or partially downloaded items
 		log.Debug("Download canceled")
 
-		for id, item := range result {
-			if item.Error == "" {
-				item.Error = aoserrors.Wrap(downloadCtx.Err()).Error()
-				updateStatus(id, cloudprotocol.ErrorStatus, item.Error)
-			}
-		}
+		setResultsCanceled(result, downloadCtx.Err(), updateStatus)
 	}
 
 	return result
 }
 
+func (downloader *groupDownloader) cancelDownload(id string) error {
+	downloader.Lock()
+
+	_, inProgress := downloader.downloads[id]
+	if inProgress {
+		downloader.downloads[id] = true
+	}
+
+	downloader.Unlock()
+
+	log.WithFields(log.Fields{"id": id, "inProgress": inProgress}).Debug("Cancel download")
+
+	// In progress download is released by download routine when it is finished
+	if inProgress {
+		downloader.CancelDownload(id)
+
+		return nil
+	}
+
+	if err := downloader.ReleaseByID(id); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
 func (downloader *groupDownloader) releaseDownloadedFirmware() error {
 	if err := downloader.ReleaseByType(cloudprotocol.DownloadTargetComponent); err != nil {
 		return aoserrors.Wrap(err)
@@ -143,6 +173,81 @@ func (downloader *groupDownloader) releaseDownloadedSoftware() error {
 	return nil
 }
 
+func (downloader *groupDownloader) isCanceled(id string) bool {
+	downloader.Lock()
+	defer downloader.Unlock()
+
+	return downloader.downloads[id]
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func (downloader *groupDownloader) startDownloads(
+	request map[string]downloader.PackageInfo, updateStatus statusNotifier,
+) (result map[string]*downloadResult) {
+	downloader.Lock()
+
+	downloader.downloads = make(map[string]bool)
+
+	for id := range request {
+		downloader.downloads[id] = false
+	}
+
+	downloader.Unlock()
+
+	result = make(map[string]*downloadResult)
+
+	for id := range request {
+		result[id] = &downloadResult{}
+
+		updateStatus(id, cloudprotocol.DownloadingStatus, nil)
+	}
+
+	return result
+}
+
+// setDownloadCanceled sets canceled state for individually canceled item. Such item doesn't affect other items and is
+// released once its download is finished.
+func (downloader *groupDownloader) setDownloadCanceled(
+	id string, result *downloadResult, updateStatus statusNotifier,
+) {
+	result.Error = aoserrors.Wrap(context.Canceled).Error()
+	updateStatus(id, cloudprotocol.ErrorStatus, newErrorInfo(cloudprotocol.ErrorCodeUpdateCanceled, result.Error))
+
+	if err := downloader.ReleaseByID(id); err != nil {
+		log.WithField("id", id).Errorf("Can't release canceled download: %v", err)
+	}
+}
+
+// setDownloadError sets item download error. Returns true if the item was individually canceled.
+func (downloader *groupDownloader) setDownloadError(
+	id string, err error, result *downloadResult, updateStatus statusNotifier,
+) (canceled bool) {
+	if downloader.isCanceled(id) {
+		downloader.setDownloadCanceled(id, result, updateStatus)
+
+		return true
+	}
+
+	if errorStr := aoserrors.Wrap(err).Error(); !isCancelError(errorStr) {
+		result.Error = errorStr
+		updateStatus(id, cloudprotocol.ErrorStatus, newErrorInfo(cloudprotocol.ErrorCodeDownloadFailed, errorStr))
+	}
+
+	return false
+}
+
+func setResultsCanceled(result map[string]*downloadResult, err error, updateStatus statusNotifier) {
+	for id, item := range result {
+		if item.Error == "" {
+			item.Error = aoserrors.Wrap(err).Error()
+			updateStatus(id, cloudprotocol.ErrorStatus, newErrorInfo(cloudprotocol.ErrorCodeUpdateCanceled, item.Error))
+		}
+	}
+}
+
 func getDownloadError(result map[string]*downloadResult) (downloadErr string) {
 	for _, item := range result {
 		if item.Error != "" && !isCancelError(item.Error) {
@@ -156,3 +261,19 @@ func getDownloadError(result map[string]*downloadResult) (downloadErr string) {
 func isCancelError(errString string) (result bool) {
 	return strings.Contains(errString, context.Canceled.Error())
 }
+
+func getCancelErrorCode(errString string) string {
+	if strings.Contains(errString, errUpdateTimeout.Error()) {
+		return cloudprotocol.ErrorCodeUpdateTimeout
+	}
+
+	return cloudprotocol.ErrorCodeUpdateCanceled
+}
+
+func newErrorInfo(errorCode, errorStr string) *cloudprotocol.ErrorInfo {
+	if errorStr == "" {
+		return nil
+	}
+
+	return &cloudprotocol.ErrorInfo{ErrorCode: errorCode, Message: errorStr}
+}
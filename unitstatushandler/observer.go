@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unitstatushandler
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/aosedge/aos_common/aostypes"
+	"github.com/aosedge/aos_common/api/cloudprotocol"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aosedge/aos_communicationmanager/downloader"
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+type placementPreviewer interface {
+	PreviewPlacement(instances []cloudprotocol.InstanceInfo) (
+		placement map[string][]aostypes.InstanceIdent, errStatus []cloudprotocol.InstanceStatus, err error)
+}
+
+type observerDownloader struct{}
+
+type observerResult struct{}
+
+type observerUnitConfigUpdater struct {
+	UnitConfigUpdater
+}
+
+type observerFirmwareUpdater struct {
+	FirmwareUpdater
+}
+
+type observerSoftwareUpdater struct {
+	SoftwareUpdater
+}
+
+type observerInstanceRunner struct {
+	InstanceRunner
+	statusHandler *Instance
+	instances     []cloudprotocol.InstanceInfo
+}
+
+/***********************************************************************************************************************
+ * Interfaces
+ **********************************************************************************************************************/
+
+func (observer *observerDownloader) Download(
+	ctx context.Context, packageInfo downloader.PackageInfo,
+) (result downloader.Result, err error) {
+	log.WithField("urls", packageInfo.URLs).Debug("Observer: skip download")
+
+	return &observerResult{}, nil
+}
+
+func (observer *observerDownloader) Release(filePath string) error {
+	return nil
+}
+
+func (observer *observerDownloader) ReleaseByType(targetType string) error {
+	return nil
+}
+
+func (observer *observerDownloader) ReleaseByID(id string) error {
+	return nil
+}
+
+func (observer *observerDownloader) CancelDownload(id string) {}
+
+func (observer *observerDownloader) GetDownloadStats() []downloader.DownloadStat {
+	return nil
+}
+
+func (result *observerResult) GetFileName() (fileName string) {
+	return ""
+}
+
+func (result *observerResult) Wait() (err error) {
+	return nil
+}
+
+func (observer *observerUnitConfigUpdater) UpdateUnitConfig(configJSON json.RawMessage) (err error) {
+	log.Debug("Observer: skip unit config update")
+
+	return nil
+}
+
+func (observer *observerFirmwareUpdater) UpdateComponents(
+	components []cloudprotocol.ComponentInfo, chains []cloudprotocol.CertificateChain,
+	certs []cloudprotocol.Certificate,
+) (status []cloudprotocol.ComponentStatus, err error) {
+	// Components are not updated: observed status is reported, not yet installed components are pending
+	currentStatus, err := observer.FirmwareUpdater.GetStatus()
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	for _, component := range components {
+		log.WithFields(log.Fields{
+			"id": component.ID, "vendorVersion": component.VendorVersion,
+		}).Debug("Observer: skip component update")
+
+		componentStatus := cloudprotocol.ComponentStatus{
+			ID: component.ID, AosVersion: component.AosVersion, VendorVersion: component.VendorVersion,
+			Status: cloudprotocol.PendingStatus,
+		}
+
+		for _, current := range currentStatus {
+			if current.ID == component.ID && current.VendorVersion == component.VendorVersion {
+				componentStatus = current
+
+				break
+			}
+		}
+
+		status = append(status, componentStatus)
+	}
+
+	return status, nil
+}
+
+func (observer *observerSoftwareUpdater) InstallService(serviceInfo cloudprotocol.ServiceInfo,
+	chains []cloudprotocol.CertificateChain, certs []cloudprotocol.Certificate,
+) error {
+	log.WithField("id", serviceInfo.ID).Debug("Observer: skip service install")
+
+	return nil
+}
+
+func (observer *observerSoftwareUpdater) RestoreService(serviceID string) error {
+	log.WithField("id", serviceID).Debug("Observer: skip service restore")
+
+	return nil
+}
+
+func (observer *observerSoftwareUpdater) RemoveService(serviceID string) error {
+	log.WithField("id", serviceID).Debug("Observer: skip service remove")
+
+	return nil
+}
+
+func (observer *observerSoftwareUpdater) InstallLayer(layerInfo cloudprotocol.LayerInfo,
+	chains []cloudprotocol.CertificateChain, certs []cloudprotocol.Certificate,
+) error {
+	log.WithField("digest", layerInfo.Digest).Debug("Observer: skip layer install")
+
+	return nil
+}
+
+func (observer *observerSoftwareUpdater) RemoveLayer(digest string) error {
+	log.WithField("digest", digest).Debug("Observer: skip layer remove")
+
+	return nil
+}
+
+func (observer *observerSoftwareUpdater) RestoreLayer(digest string) error {
+	log.WithField("digest", digest).Debug("Observer: skip layer restore")
+
+	return nil
+}
+
+func (observer *observerSoftwareUpdater) PurgeService(serviceID string) error {
+	log.WithField("id", serviceID).Debug("Observer: skip service purge")
+
+	return nil
+}
+
+func (observer *observerSoftwareUpdater) PurgeLayer(digest string) error {
+	log.WithField("digest", digest).Debug("Observer: skip layer purge")
+
+	return nil
+}
+
+func (observer *observerInstanceRunner) RunInstances(
+	instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	log.Debug("Observer: skip run instances")
+
+	observer.instances = instances
+
+	runStatus := RunInstancesStatus{Instances: observer.previewInstances(instances), CorrelationID: correlationID}
+
+	// Run status is expected asynchronously as it would be received from the real runner
+	go func() {
+		if err := observer.statusHandler.ProcessRunStatus(runStatus); err != nil {
+			log.Errorf("Can't process observer run status: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (observer *observerInstanceRunner) RunSubjectInstances(
+	subjectID string, instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	desiredInstances := make([]cloudprotocol.InstanceInfo, 0, len(observer.instances)+len(instances))
+
+	for _, instance := range observer.instances {
+		if instance.SubjectID != subjectID {
+			desiredInstances = append(desiredInstances, instance)
+		}
+	}
+
+	return observer.RunInstances(append(desiredInstances, instances...), newServices, correlationID)
+}
+
+func (observer *observerInstanceRunner) RestartInstances() error {
+	log.Debug("Observer: skip restart instances")
+
+	return nil
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func (observer *observerInstanceRunner) previewInstances(
+	instances []cloudprotocol.InstanceInfo,
+) (status []cloudprotocol.InstanceStatus) {
+	if previewer, ok := observer.InstanceRunner.(placementPreviewer); ok {
+		placement, errStatus, err := previewer.PreviewPlacement(instances)
+		if err == nil {
+			for nodeID, idents := range placement {
+				for _, ident := range idents {
+					status = append(status, cloudprotocol.InstanceStatus{
+						InstanceIdent: ident, NodeID: nodeID, RunState: cloudprotocol.InstanceStateActivating,
+					})
+				}
+			}
+
+			return append(status, errStatus...)
+		}
+
+		log.Warnf("Can't preview instances placement: %v", err)
+	}
+
+	for _, instance := range instances {
+		for i := uint64(0); i < instance.NumInstances; i++ {
+			status = append(status, cloudprotocol.InstanceStatus{
+				InstanceIdent: aostypes.InstanceIdent{
+					ServiceID: instance.ServiceID, SubjectID: instance.SubjectID, Instance: i,
+				},
+				RunState: cloudprotocol.InstanceStateActivating,
+			})
+		}
+	}
+
+	return status
+}
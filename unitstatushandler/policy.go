@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unitstatushandler
+
+import (
+	"slices"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/aosedge/aos_common/api/cloudprotocol"
+
+	"github.com/aosedge/aos_communicationmanager/config"
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+type configPolicy struct {
+	policy config.UpdatePolicy
+}
+
+/***********************************************************************************************************************
+ * Public
+ **********************************************************************************************************************/
+
+// NewConfigPolicy creates policy checker which rejects blocked items and, if enabled, downgrades.
+func NewConfigPolicy(policy config.UpdatePolicy) PolicyChecker {
+	return &configPolicy{policy: policy}
+}
+
+/***********************************************************************************************************************
+ * Interface
+ **********************************************************************************************************************/
+
+// CheckComponent checks component against update policy.
+func (checker *configPolicy) CheckComponent(
+	component cloudprotocol.ComponentInfo, installed *cloudprotocol.ComponentStatus,
+) error {
+	if slices.Contains(checker.policy.BlockedComponents, component.ID) {
+		return aoserrors.Errorf("component %s is blocked", component.ID)
+	}
+
+	if installed != nil {
+		return checker.checkDowngrade(component.AosVersion, installed.AosVersion)
+	}
+
+	return nil
+}
+
+// CheckService checks service against update policy.
+func (checker *configPolicy) CheckService(
+	service cloudprotocol.ServiceInfo, installed *cloudprotocol.ServiceStatus,
+) error {
+	if slices.Contains(checker.policy.BlockedServices, service.ID) {
+		return aoserrors.Errorf("service %s is blocked", service.ID)
+	}
+
+	if installed != nil {
+		return checker.checkDowngrade(service.AosVersion, installed.AosVersion)
+	}
+
+	return nil
+}
+
+// CheckLayer checks layer against update policy.
+func (checker *configPolicy) CheckLayer(layer cloudprotocol.LayerInfo, installed *cloudprotocol.LayerStatus) error {
+	if slices.Contains(checker.policy.BlockedLayers, layer.ID) {
+		return aoserrors.Errorf("layer %s is blocked", layer.ID)
+	}
+
+	if installed != nil {
+		return checker.checkDowngrade(layer.AosVersion, installed.AosVersion)
+	}
+
+	return nil
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func (checker *configPolicy) checkDowngrade(desiredVersion, installedVersion uint64) error {
+	if checker.policy.DenyDowngrade && desiredVersion < installedVersion {
+		return aoserrors.Errorf("downgrade from version %d to %d is denied", installedVersion, desiredVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unitstatushandler
+
+import (
+	"sync"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	"github.com/aosedge/aos_common/api/cloudprotocol"
+	log "github.com/sirupsen/logrus"
+)
+
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+// Behavior of instances restart requested while update run phase is in progress.
+const (
+	restartDuringUpdateCoalesce = "coalesce"
+	restartDuringUpdateWait     = "wait"
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// runSerializer serializes instances restart with update run phase: run phase lasts from run instances request till
+// run status is received. Restart requested during run phase either waits for run phase end or is postponed and
+// coalesced with other restart requests.
+type runSerializer struct {
+	InstanceRunner
+
+	sync.Mutex
+	runDone        *sync.Cond
+	waitRun        bool
+	running        bool
+	restartPending bool
+	closed         bool
+}
+
+/***********************************************************************************************************************
+ * Interface
+ **********************************************************************************************************************/
+
+func newRunSerializer(runner InstanceRunner, restartDuringUpdate string) *runSerializer {
+	serializer := &runSerializer{InstanceRunner: runner}
+
+	serializer.runDone = sync.NewCond(&serializer.Mutex)
+
+	switch restartDuringUpdate {
+	case "", restartDuringUpdateCoalesce:
+
+	case restartDuringUpdateWait:
+		serializer.waitRun = true
+
+	default:
+		log.Warnf("Unknown restart during update behavior %s, use %s", restartDuringUpdate,
+			restartDuringUpdateCoalesce)
+	}
+
+	return serializer
+}
+
+func (serializer *runSerializer) close() {
+	serializer.Lock()
+	defer serializer.Unlock()
+
+	serializer.closed = true
+	serializer.restartPending = false
+
+	serializer.finishRun()
+}
+
+func (serializer *runSerializer) RunInstances(
+	instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	serializer.Lock()
+	defer serializer.Unlock()
+
+	serializer.running = true
+
+	if err := serializer.InstanceRunner.RunInstances(instances, newServices, correlationID); err != nil {
+		serializer.finishRun()
+
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (serializer *runSerializer) RunSubjectInstances(
+	subjectID string, instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	serializer.Lock()
+	defer serializer.Unlock()
+
+	serializer.running = true
+
+	if err := serializer.InstanceRunner.RunSubjectInstances(
+		subjectID, instances, newServices, correlationID); err != nil {
+		serializer.finishRun()
+
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (serializer *runSerializer) RestartInstances() error {
+	serializer.Lock()
+	defer serializer.Unlock()
+
+	if serializer.running && !serializer.waitRun {
+		log.Debug("Postpone instances restart till run phase finished")
+
+		serializer.restartPending = true
+
+		return nil
+	}
+
+	for serializer.running {
+		log.Debug("Wait run phase finished to restart instances")
+
+		serializer.runDone.Wait()
+	}
+
+	if serializer.closed {
+		return aoserrors.New("instances runner is closed")
+	}
+
+	return aoserrors.Wrap(serializer.InstanceRunner.RestartInstances())
+}
+
+func (serializer *runSerializer) processRunStatus() {
+	serializer.Lock()
+	defer serializer.Unlock()
+
+	if !serializer.running {
+		return
+	}
+
+	serializer.finishRun()
+
+	if !serializer.restartPending {
+		return
+	}
+
+	serializer.restartPending = false
+
+	log.Debug("Perform postponed instances restart")
+
+	// Restart is performed asynchronously as run status is reported from instance runner context
+	go func() {
+		if err := serializer.RestartInstances(); err != nil {
+			log.Errorf("Can't restart instances: %v", err)
+		}
+	}()
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func (serializer *runSerializer) finishRun() {
+	serializer.running = false
+	serializer.runDone.Broadcast()
+}
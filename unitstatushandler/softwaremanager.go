@@ -20,8 +20,11 @@ package unitstatushandler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/url"
 	"reflect"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -31,8 +34,10 @@ import (
 	"github.com/aosedge/aos_common/utils/action"
 	"github.com/looplab/fsm"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
 
 	"github.com/aosedge/aos_communicationmanager/cmserver"
+	"github.com/aosedge/aos_communicationmanager/config"
 	"github.com/aosedge/aos_communicationmanager/downloader"
 )
 
@@ -42,6 +47,12 @@ import (
 
 const maxConcurrentActions = 10
 
+/***********************************************************************************************************************
+ * Vars
+ **********************************************************************************************************************/
+
+var errRetryBudgetExceeded = errors.New("exceeded retry budget")
+
 /***********************************************************************************************************************
  * Types
  **********************************************************************************************************************/
@@ -55,6 +66,8 @@ type softwareStatusHandler interface {
 	updateLayerStatus(layerInfo cloudprotocol.LayerStatus)
 	updateServiceStatus(serviceInfo cloudprotocol.ServiceStatus)
 	setInstanceStatus(status []cloudprotocol.InstanceStatus)
+	addUpdateHistoryEntry(entry UpdateHistoryEntry)
+	sendNewServices(newServices cloudprotocol.NewServices)
 }
 
 type softwareUpdate struct {
@@ -68,6 +81,14 @@ type softwareUpdate struct {
 	RunInstances    []cloudprotocol.InstanceInfo     `json:"runInstances,omitempty"`
 	CertChains      []cloudprotocol.CertificateChain `json:"certChains,omitempty"`
 	Certs           []cloudprotocol.Certificate      `json:"certs,omitempty"`
+	CorrelationID   string                           `json:"correlationId,omitempty"`
+}
+
+type cachedItem struct {
+	id        string
+	isLayer   bool
+	size      uint64
+	timestamp time.Time
 }
 
 type softwareManager struct {
@@ -81,6 +102,9 @@ type softwareManager struct {
 	softwareUpdater SoftwareUpdater
 	instanceRunner  InstanceRunner
 	storage         Storage
+	cachePolicy     config.CachePolicy
+	retryBudget     config.RetryBudget
+	policyChecker   PolicyChecker
 
 	stateMachine  *updateStateMachine
 	actionHandler *action.Handler
@@ -90,11 +114,14 @@ type softwareManager struct {
 	LayerStatuses    map[string]*cloudprotocol.LayerStatus   `json:"layerStatuses,omitempty"`
 	ServiceStatuses  map[string]*cloudprotocol.ServiceStatus `json:"serviceStatuses,omitempty"`
 	InstanceStatuses []cloudprotocol.InstanceStatus          `json:"instanceStatuses,omitempty"`
+	CurrentInstances []cloudprotocol.InstanceInfo            `json:"currentInstances,omitempty"`
 	CurrentUpdate    *softwareUpdate                         `json:"currentUpdate,omitempty"`
 	DownloadResult   map[string]*downloadResult              `json:"downloadResult,omitempty"`
 	CurrentState     string                                  `json:"currentState,omitempty"`
 	UpdateErr        string                                  `json:"updateErr,omitempty"`
 	TTLDate          time.Time                               `json:"ttlDate,omitempty"`
+	UpdateAttempts   int                                     `json:"updateAttempts,omitempty"`
+	UpdateStartTime  time.Time                               `json:"updateStartTime,omitempty"`
 }
 
 /***********************************************************************************************************************
@@ -103,6 +130,7 @@ type softwareManager struct {
 
 func newSoftwareManager(statusHandler softwareStatusHandler, downloader softwareDownloader,
 	softwareUpdater SoftwareUpdater, instanceRunner InstanceRunner, storage Storage, defaultTTL time.Duration,
+	cachePolicy config.CachePolicy, retryBudget config.RetryBudget,
 ) (manager *softwareManager, err error) {
 	manager = &softwareManager{
 		statusChannel:   make(chan cmserver.UpdateSOTAStatus, 1),
@@ -112,6 +140,9 @@ func newSoftwareManager(statusHandler softwareStatusHandler, downloader software
 		instanceRunner:  instanceRunner,
 		actionHandler:   action.New(maxConcurrentActions),
 		storage:         storage,
+		cachePolicy:     cachePolicy,
+		retryBudget:     retryBudget,
+		policyChecker:   allowAllPolicy{},
 		CurrentState:    stateNoUpdate,
 	}
 
@@ -157,6 +188,20 @@ func (manager *softwareManager) close() (err error) {
 	return nil
 }
 
+func (manager *softwareManager) setPolicyChecker(checker PolicyChecker) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	manager.policyChecker = checker
+}
+
+func (manager *softwareManager) setClock(clock Clock) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	manager.stateMachine.setClock(clock)
+}
+
 func (manager *softwareManager) getCurrentStatus() (status cmserver.UpdateSOTAStatus) {
 	status.State = convertState(manager.CurrentState)
 	status.Error = manager.UpdateErr
@@ -165,6 +210,13 @@ func (manager *softwareManager) getCurrentStatus() (status cmserver.UpdateSOTASt
 		return status
 	}
 
+	status.TTLDate = manager.TTLDate
+	status.Emergency = manager.CurrentUpdate.Schedule.Emergency
+
+	if status.State == cmserver.ReadyToUpdate {
+		status.NextWindow = manager.stateMachine.getNextWindow()
+	}
+
 	for _, layer := range manager.CurrentUpdate.InstallLayers {
 		status.InstallLayers = append(status.InstallLayers, cloudprotocol.LayerStatus{
 			ID: layer.ID, Digest: layer.Digest, AosVersion: layer.AosVersion,
@@ -196,21 +248,19 @@ func (manager *softwareManager) processRunStatus(status RunInstancesStatus) {
 	manager.Lock()
 	defer manager.Unlock()
 
+	if manager.CurrentUpdate == nil {
+		log.WithField("instances", len(status.Instances)).Debug("Run status received before desired status")
+	}
+
 	manager.InstanceStatuses = status.Instances
 
 	for _, errStatus := range status.ErrorServices {
-		var errMsg string
-
-		if errStatus.ErrorInfo != nil {
-			errMsg = errStatus.ErrorInfo.Message
-		}
-
 		if _, ok := manager.ServiceStatuses[errStatus.ID]; !ok {
 			status := errStatus
 			manager.ServiceStatuses[errStatus.ID] = &status
 		}
 
-		manager.updateServiceStatusByID(errStatus.ID, errStatus.Status, errMsg)
+		manager.updateServiceStatusByID(errStatus.ID, errStatus.Status, errStatus.ErrorInfo)
 	}
 
 	manager.runCond.Broadcast()
@@ -220,6 +270,12 @@ func (manager *softwareManager) processDesiredStatus(desiredStatus cloudprotocol
 	manager.Lock()
 	defer manager.Unlock()
 
+	if desiredStatus.SOTASchedule.Type == cloudprotocol.TimetableUpdate {
+		if err := ValidateTimetable(desiredStatus.SOTASchedule.Timetable); err != nil {
+			return aoserrors.Wrap(err)
+		}
+	}
+
 	update := &softwareUpdate{
 		Schedule:        desiredStatus.SOTASchedule,
 		InstallServices: make([]cloudprotocol.ServiceInfo, 0),
@@ -228,6 +284,7 @@ func (manager *softwareManager) processDesiredStatus(desiredStatus cloudprotocol
 		RemoveLayers:    make([]cloudprotocol.LayerStatus, 0),
 		RunInstances:    desiredStatus.Instances,
 		CertChains:      desiredStatus.CertificateChains, Certs: desiredStatus.Certificates,
+		CorrelationID: desiredStatus.CorrelationID,
 	}
 
 	allServices, err := manager.softwareUpdater.GetServicesStatus()
@@ -251,6 +308,8 @@ func (manager *softwareManager) processDesiredStatus(desiredStatus cloudprotocol
 		}
 	} else {
 		log.Debug("No software update needed")
+
+		manager.updateSchedule(update.Schedule)
 	}
 
 	return nil
@@ -262,8 +321,9 @@ func (manager *softwareManager) processDesiredServices(
 downloadServiceLoop:
 	for _, desiredService := range desiredServices {
 		for _, service := range allServices {
+			// Forced service is downloaded and installed again even if it is installed or cached
 			if desiredService.ID == service.ID && desiredService.AosVersion == service.AosVersion &&
-				service.Status != cloudprotocol.ErrorStatus {
+				service.Status != cloudprotocol.ErrorStatus && !desiredService.ForceDownload {
 				if service.Cached {
 					update.RestoreServices = append(update.RestoreServices, desiredService)
 				}
@@ -272,6 +332,12 @@ downloadServiceLoop:
 			}
 		}
 
+		if err := manager.policyChecker.CheckService(
+			desiredService, findInstalledService(allServices, desiredService.ID)); err != nil {
+			manager.rejectService(desiredService, err)
+			continue
+		}
+
 		update.InstallServices = append(update.InstallServices, desiredService)
 	}
 
@@ -298,10 +364,13 @@ removeServiceLoop:
 func (manager *softwareManager) processDesiredLayers(
 	update *softwareUpdate, allLayers []LayerStatus, desiredLayers []cloudprotocol.LayerInfo,
 ) {
+	rejectedLayers := make(map[string]bool)
+
 downloadLayersLoop:
 	for _, desiredLayer := range desiredLayers {
 		for _, layer := range allLayers {
-			if desiredLayer.Digest == layer.Digest && layer.Status == cloudprotocol.InstalledStatus {
+			if desiredLayer.Digest == layer.Digest && layer.Status == cloudprotocol.InstalledStatus &&
+				!desiredLayer.ForceDownload {
 				if layer.Cached {
 					update.RestoreLayers = append(update.RestoreLayers, layer.LayerStatus)
 				}
@@ -310,6 +379,16 @@ downloadLayersLoop:
 			}
 		}
 
+		if err := manager.policyChecker.CheckLayer(
+			desiredLayer, findInstalledLayer(allLayers, desiredLayer.ID)); err != nil {
+			manager.rejectLayer(desiredLayer, err)
+
+			// Keep currently installed layer version if new one is rejected
+			rejectedLayers[desiredLayer.ID] = true
+
+			continue
+		}
+
 		update.InstallLayers = append(update.InstallLayers, desiredLayer)
 	}
 
@@ -319,7 +398,7 @@ removeLayersLoop:
 			continue
 		}
 
-		if installedLayer.Cached {
+		if installedLayer.Cached || rejectedLayers[installedLayer.ID] {
 			continue
 		}
 
@@ -333,12 +412,89 @@ removeLayersLoop:
 	}
 }
 
+func (manager *softwareManager) rejectService(service cloudprotocol.ServiceInfo, err error) {
+	log.WithFields(log.Fields{
+		"id":         service.ID,
+		"aosVersion": service.AosVersion,
+	}).Warnf("Service rejected by policy: %v", err)
+
+	manager.statusHandler.updateServiceStatus(cloudprotocol.ServiceStatus{
+		ID: service.ID, AosVersion: service.AosVersion, Status: cloudprotocol.ErrorStatus,
+		ErrorInfo: policyErrorInfo(err),
+	})
+}
+
+func (manager *softwareManager) rejectLayer(layer cloudprotocol.LayerInfo, err error) {
+	log.WithFields(log.Fields{
+		"id":         layer.ID,
+		"digest":     layer.Digest,
+		"aosVersion": layer.AosVersion,
+	}).Warnf("Layer rejected by policy: %v", err)
+
+	manager.statusHandler.updateLayerStatus(cloudprotocol.LayerStatus{
+		ID: layer.ID, Digest: layer.Digest, AosVersion: layer.AosVersion, Status: cloudprotocol.ErrorStatus,
+		ErrorInfo: policyErrorInfo(err),
+	})
+}
+
+// rejectDesiredStatus reports desired services and layers which require update as failed.
+func (manager *softwareManager) rejectDesiredStatus(desiredStatus cloudprotocol.DesiredStatus, err error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	log.Errorf("Software desired status rejected: %v", err)
+
+	errorInfo := &cloudprotocol.ErrorInfo{ErrorCode: cloudprotocol.ErrorCodeFailed, Message: err.Error()}
+
+	allServices, getErr := manager.softwareUpdater.GetServicesStatus()
+	if getErr != nil {
+		log.Errorf("Can't get services status: %v", getErr)
+	}
+
+desiredServicesLoop:
+	for _, desiredService := range desiredStatus.Services {
+		for _, service := range allServices {
+			if desiredService.ID == service.ID && desiredService.AosVersion == service.AosVersion &&
+				service.Status == cloudprotocol.InstalledStatus {
+				continue desiredServicesLoop
+			}
+		}
+
+		manager.statusHandler.updateServiceStatus(cloudprotocol.ServiceStatus{
+			ID: desiredService.ID, AosVersion: desiredService.AosVersion, Status: cloudprotocol.ErrorStatus,
+			ErrorInfo: errorInfo,
+		})
+	}
+
+	allLayers, getErr := manager.softwareUpdater.GetLayersStatus()
+	if getErr != nil {
+		log.Errorf("Can't get layers status: %v", getErr)
+	}
+
+desiredLayersLoop:
+	for _, desiredLayer := range desiredStatus.Layers {
+		for _, layer := range allLayers {
+			if desiredLayer.Digest == layer.Digest && layer.Status == cloudprotocol.InstalledStatus {
+				continue desiredLayersLoop
+			}
+		}
+
+		manager.statusHandler.updateLayerStatus(cloudprotocol.LayerStatus{
+			ID: desiredLayer.ID, Digest: desiredLayer.Digest, AosVersion: desiredLayer.AosVersion,
+			Status: cloudprotocol.ErrorStatus, ErrorInfo: errorInfo,
+		})
+	}
+}
+
 func (manager *softwareManager) needRunInstances(desiredInstances []cloudprotocol.InstanceInfo) bool {
-	currentIdents := make([]aostypes.InstanceIdent, len(manager.InstanceStatuses))
+	currentIdents := make([]aostypes.InstanceIdent, 0, len(manager.InstanceStatuses))
 	desiredIdents := []aostypes.InstanceIdent{}
 
-	for i, ident := range manager.InstanceStatuses {
-		currentIdents[i] = ident.InstanceIdent
+	// Instance may be reported several times e.g. failed and running on another node
+	for _, status := range manager.InstanceStatuses {
+		if !slices.Contains(currentIdents, status.InstanceIdent) {
+			currentIdents = append(currentIdents, status.InstanceIdent)
+		}
 	}
 
 	for _, instance := range desiredInstances {
@@ -384,6 +540,29 @@ func (manager *softwareManager) startUpdate() (err error) {
 	return nil
 }
 
+func (manager *softwareManager) extendTTL(extension time.Duration) (err error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	log.WithField("extension", extension).Debug("Extend software update TTL")
+
+	if manager.CurrentUpdate == nil || manager.CurrentUpdate.Schedule.Type != cloudprotocol.TriggerUpdate {
+		return aoserrors.New("no trigger update to extend")
+	}
+
+	if manager.TTLDate, err = manager.stateMachine.extendTTL(manager.TTLDate, extension); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	if err = manager.saveState(); err != nil {
+		log.Errorf("Can't save current software manager state: %s", err)
+	}
+
+	manager.sendCurrentStatus()
+
+	return nil
+}
+
 func (manager *softwareManager) getServiceStatus() (serviceStatuses []cloudprotocol.ServiceStatus, err error) {
 	manager.Lock()
 	defer manager.Unlock()
@@ -444,20 +623,35 @@ func (manager *softwareManager) stateChanged(event, state string, updateErr stri
 	if event == eventCancel {
 		for id, status := range manager.LayerStatuses {
 			if status.Status != cloudprotocol.ErrorStatus {
-				manager.updateLayerStatusByID(id, cloudprotocol.ErrorStatus, updateErr)
+				manager.updateLayerStatusByID(id, cloudprotocol.ErrorStatus,
+					newErrorInfo(getCancelErrorCode(updateErr), updateErr))
 			}
 		}
 
 		for id, status := range manager.ServiceStatuses {
 			if status.Status != cloudprotocol.ErrorStatus {
-				manager.updateServiceStatusByID(id, cloudprotocol.ErrorStatus, updateErr)
+				manager.updateServiceStatusByID(id, cloudprotocol.ErrorStatus,
+					newErrorInfo(getCancelErrorCode(updateErr), updateErr))
 			}
 		}
 	}
 
+	if event == eventStartDownload {
+		manager.UpdateStartTime = manager.stateMachine.clock.Now()
+	}
+
+	if state == stateNoUpdate && manager.CurrentState != stateNoUpdate {
+		manager.addUpdateHistoryEntry(event, updateErr)
+	}
+
 	manager.CurrentState = state
 	manager.UpdateErr = updateErr
 
+	if state == stateNoUpdate {
+		manager.UpdateAttempts = 0
+		manager.UpdateStartTime = time.Time{}
+	}
+
 	log.WithFields(log.Fields{
 		"state": state,
 		"event": event,
@@ -547,7 +741,7 @@ func (manager *softwareManager) download(ctx context.Context) {
 				"version": layerStatus.AosVersion,
 			}).Debug("Layer successfully downloaded")
 
-			manager.updateLayerStatusByID(id, cloudprotocol.PendingStatus, "")
+			manager.updateLayerStatusByID(id, cloudprotocol.PendingStatus, nil)
 		} else if serviceStatus, ok := manager.ServiceStatuses[id]; ok {
 			if serviceStatus.Status == cloudprotocol.ErrorStatus {
 				log.WithFields(log.Fields{
@@ -562,7 +756,7 @@ func (manager *softwareManager) download(ctx context.Context) {
 				"version": serviceStatus.AosVersion,
 			}).Debug("Service successfully downloaded")
 
-			manager.updateServiceStatusByID(id, cloudprotocol.PendingStatus, "")
+			manager.updateServiceStatusByID(id, cloudprotocol.PendingStatus, nil)
 		}
 	}
 
@@ -605,6 +799,7 @@ func (manager *softwareManager) prepareDownloadRequest() (request map[string]dow
 			TargetID:            service.ID,
 			TargetAosVersion:    service.AosVersion,
 			TargetVendorVersion: service.VendorVersion,
+			ForceDownload:       service.ForceDownload,
 		}
 		manager.ServiceStatuses[service.ID] = &cloudprotocol.ServiceStatus{
 			ID:         service.ID,
@@ -629,6 +824,7 @@ func (manager *softwareManager) prepareDownloadRequest() (request map[string]dow
 			TargetID:            layer.Digest,
 			TargetAosVersion:    layer.AosVersion,
 			TargetVendorVersion: layer.VendorVersion,
+			ForceDownload:       layer.ForceDownload,
 		}
 		manager.LayerStatuses[layer.Digest] = &cloudprotocol.LayerStatus{
 			ID:         layer.ID,
@@ -647,6 +843,30 @@ func (manager *softwareManager) readyToUpdate() {
 	manager.stateMachine.scheduleUpdate(manager.CurrentUpdate.Schedule)
 }
 
+func (manager *softwareManager) clockNotSynchronized(reason string) {
+	status := manager.getCurrentStatus()
+	status.Error = reason
+
+	manager.statusChannel <- status
+}
+
+func (manager *softwareManager) updateDeferred() {
+	manager.sendCurrentStatus()
+}
+
+func (manager *softwareManager) retrySchedule() {
+	manager.Lock()
+	defer manager.Unlock()
+
+	if manager.CurrentState != stateReadyToUpdate {
+		return
+	}
+
+	log.Debug("Retry software update schedule")
+
+	manager.stateMachine.scheduleUpdate(manager.CurrentUpdate.Schedule)
+}
+
 func (manager *softwareManager) update(ctx context.Context) {
 	manager.Lock()
 	defer manager.Unlock()
@@ -670,21 +890,25 @@ func (manager *softwareManager) update(ctx context.Context) {
 		}()
 	}()
 
-	if errorStr := manager.removeServices(); errorStr != "" && updateErr == "" {
-		updateErr = errorStr
+	if err := manager.newUpdateAttempt(); err != nil {
+		updateErr = aoserrors.Wrap(err).Error()
+
+		return
 	}
 
-	if errorStr := manager.installLayers(); errorStr != "" && updateErr == "" {
+	if errorStr := manager.removeServices(); errorStr != "" && updateErr == "" {
 		updateErr = errorStr
 	}
 
+	installErr := manager.installLayers()
+
 	if errorStr := manager.restoreServices(); errorStr != "" && updateErr == "" {
 		updateErr = errorStr
 	}
 
 	newServices, errorStr := manager.installServices()
-	if errorStr != "" && updateErr == "" {
-		updateErr = errorStr
+	if errorStr != "" && installErr == "" {
+		installErr = errorStr
 	}
 
 	if errorStr := manager.removeLayers(); errorStr != "" && updateErr == "" {
@@ -695,6 +919,17 @@ func (manager *softwareManager) update(ctx context.Context) {
 		updateErr = errorStr
 	}
 
+	// Only install failures are retried, other failures terminate the update immediately
+	if installErr != "" && updateErr == "" {
+		newServices, installErr = manager.retryInstall(ctx, newServices, installErr)
+	}
+
+	if installErr != "" && updateErr == "" {
+		updateErr = installErr
+	}
+
+	manager.cleanCache()
+
 	if errorStr := manager.runInstances(newServices); errorStr != "" && updateErr == "" {
 		updateErr = errorStr
 	}
@@ -706,12 +941,106 @@ func (manager *softwareManager) update(ctx context.Context) {
 	manager.runCond.Wait()
 }
 
+func (manager *softwareManager) retryInstall(
+	ctx context.Context, newServices []string, installErr string,
+) (retryServices []string, retryErr string) {
+	retryServices, retryErr = newServices, installErr
+
+	if manager.retryBudget.MaxAttempts == 0 && manager.retryBudget.MaxTime.Duration == 0 {
+		return retryServices, retryErr
+	}
+
+	for retry := 0; retryErr != "" && ctx.Err() == nil; retry++ {
+		if err := manager.newUpdateAttempt(); err != nil {
+			return retryServices, aoserrors.Errorf("%v: %s", err, retryErr).Error()
+		}
+
+		if !manager.waitRetryDelay(ctx, retry) {
+			return retryServices, retryErr
+		}
+
+		log.WithFields(log.Fields{
+			"attempt": manager.UpdateAttempts, "error": retryErr,
+		}).Warn("Retry software install")
+
+		retryErr = manager.installLayers()
+
+		services, errorStr := manager.installServices()
+		if errorStr != "" && retryErr == "" {
+			retryErr = errorStr
+		}
+
+		retryServices = append(retryServices, services...)
+	}
+
+	return retryServices, retryErr
+}
+
+// waitRetryDelay waits exponential delay before next install retry. Returns false if update is canceled.
+func (manager *softwareManager) waitRetryDelay(ctx context.Context, retry int) bool {
+	delay := manager.retryBudget.RetryDelay.Duration
+	if delay <= 0 {
+		return ctx.Err() == nil
+	}
+
+	// Delay is doubled on each retry up to max retry delay, if max retry delay is not set delay is constant
+	maxDelay := max(manager.retryBudget.MaxRetryDelay.Duration, delay)
+
+	for i := 0; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, maxDelay)
+
+	log.WithField("delay", delay).Debug("Wait software install retry")
+
+	// Manager is unlocked while waiting to not block update cancel
+	manager.Unlock()
+	defer manager.Lock()
+
+	select {
+	case <-ctx.Done():
+		return false
+
+	case <-manager.stateMachine.clock.After(delay):
+		return true
+	}
+}
+
+func (manager *softwareManager) newUpdateAttempt() error {
+	if manager.retryBudget.MaxAttempts == 0 && manager.retryBudget.MaxTime.Duration == 0 {
+		return nil
+	}
+
+	if manager.UpdateStartTime.IsZero() {
+		manager.UpdateStartTime = manager.stateMachine.clock.Now()
+	}
+
+	if manager.retryBudget.MaxAttempts != 0 && manager.UpdateAttempts >= manager.retryBudget.MaxAttempts {
+		return errRetryBudgetExceeded
+	}
+
+	if manager.retryBudget.MaxTime.Duration != 0 &&
+		manager.stateMachine.clock.Now().Sub(manager.UpdateStartTime) >= manager.retryBudget.MaxTime.Duration {
+		return errRetryBudgetExceeded
+	}
+
+	manager.UpdateAttempts++
+
+	// Save attempts to keep the budget across restarts
+	if err := manager.saveState(); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
 func (manager *softwareManager) updateTimeout() {
 	manager.Lock()
 	defer manager.Unlock()
 
 	if manager.stateMachine.canTransit(eventCancel) {
-		if err := manager.stateMachine.sendEvent(eventCancel, aoserrors.New("update timeout").Error()); err != nil {
+		if err := manager.stateMachine.sendEvent(eventCancel, aoserrors.Wrap(errUpdateTimeout).Error()); err != nil {
 			log.Errorf("Can't cancel update: %s", err)
 		}
 	}
@@ -731,12 +1060,7 @@ func (manager *softwareManager) newUpdate(update *softwareUpdate) (err error) {
 	case "":
 		update.Schedule.Type = cloudprotocol.ForceUpdate
 
-	case cloudprotocol.TimetableUpdate:
-		if err = validateTimetable(update.Schedule.Timetable); err != nil {
-			return aoserrors.Wrap(err)
-		}
-
-	case cloudprotocol.ForceUpdate, cloudprotocol.TriggerUpdate:
+	case cloudprotocol.ForceUpdate, cloudprotocol.TriggerUpdate, cloudprotocol.TimetableUpdate:
 
 	default:
 		return aoserrors.New("wrong update type")
@@ -759,6 +1083,9 @@ func (manager *softwareManager) newUpdate(update *softwareUpdate) (err error) {
 			reflect.DeepEqual(update.RunInstances, manager.CurrentUpdate.RunInstances) &&
 			reflect.DeepEqual(update.RestoreServices, manager.CurrentUpdate.RestoreServices) &&
 			reflect.DeepEqual(update.RestoreLayers, manager.CurrentUpdate.RestoreLayers) {
+			// The same update is requested again: report run status with the latest correlation ID
+			manager.CurrentUpdate.CorrelationID = update.CorrelationID
+
 			if reflect.DeepEqual(update.Schedule, manager.CurrentUpdate.Schedule) {
 				return nil
 			}
@@ -791,21 +1118,72 @@ func (manager *softwareManager) newUpdate(update *softwareUpdate) (err error) {
 	return nil
 }
 
+// updateSchedule stores new schedule when desired status doesn't require any software update.
+func (manager *softwareManager) updateSchedule(schedule cloudprotocol.ScheduleRule) {
+	if manager.CurrentState != stateNoUpdate {
+		return
+	}
+
+	if manager.CurrentUpdate == nil {
+		manager.CurrentUpdate = &softwareUpdate{}
+	}
+
+	if reflect.DeepEqual(manager.CurrentUpdate.Schedule, schedule) {
+		return
+	}
+
+	log.WithField("type", schedule.Type).Debug("Software update schedule changed")
+
+	manager.CurrentUpdate.Schedule = schedule
+
+	if err := manager.saveState(); err != nil {
+		log.Errorf("Can't save current software manager state: %s", err)
+	}
+}
+
+func (manager *softwareManager) addUpdateHistoryEntry(event, updateErr string) {
+	entry := UpdateHistoryEntry{
+		Type:      UpdateTypeSOTA,
+		StartTime: manager.UpdateStartTime,
+		EndTime:   manager.stateMachine.clock.Now(),
+		Result:    getUpdateResult(event, updateErr),
+		Error:     updateErr,
+	}
+
+	if manager.CurrentUpdate != nil {
+		entry.Emergency = manager.CurrentUpdate.Schedule.Emergency
+
+		for _, layer := range manager.CurrentUpdate.InstallLayers {
+			entry.Targets = append(entry.Targets, UpdateTarget{
+				ID: layer.Digest, Version: strconv.FormatUint(layer.AosVersion, 10),
+			})
+		}
+
+		for _, service := range manager.CurrentUpdate.InstallServices {
+			entry.Targets = append(entry.Targets, UpdateTarget{
+				ID: service.ID, Version: strconv.FormatUint(service.AosVersion, 10),
+			})
+		}
+	}
+
+	manager.statusHandler.addUpdateHistoryEntry(entry)
+}
+
 func (manager *softwareManager) sendCurrentStatus() {
 	manager.statusChannel <- manager.getCurrentStatus()
 }
 
-func (manager *softwareManager) updateStatusByID(id string, status string, errorStr string) {
+func (manager *softwareManager) updateStatusByID(id string, status string, errorInfo *cloudprotocol.ErrorInfo) {
 	if _, ok := manager.LayerStatuses[id]; ok {
-		manager.updateLayerStatusByID(id, status, errorStr)
+		manager.updateLayerStatusByID(id, status, errorInfo)
 	} else if _, ok := manager.ServiceStatuses[id]; ok {
-		manager.updateServiceStatusByID(id, status, errorStr)
+		manager.updateServiceStatusByID(id, status, errorInfo)
 	} else {
 		log.Errorf("Software update ID not found: %s", id)
 	}
 }
 
-func (manager *softwareManager) updateLayerStatusByID(id, status, layerErr string) {
+func (manager *softwareManager) updateLayerStatusByID(id, status string, errorInfo *cloudprotocol.ErrorInfo) {
 	manager.statusMutex.Lock()
 	defer manager.statusMutex.Unlock()
 
@@ -817,14 +1195,14 @@ func (manager *softwareManager) updateLayerStatusByID(id, status, layerErr strin
 
 	info.Status = status
 
-	if layerErr != "" {
-		info.ErrorInfo = &cloudprotocol.ErrorInfo{Message: layerErr}
+	if errorInfo != nil {
+		info.ErrorInfo = errorInfo
 	}
 
 	manager.statusHandler.updateLayerStatus(*info)
 }
 
-func (manager *softwareManager) updateServiceStatusByID(id, status, serviceErr string) {
+func (manager *softwareManager) updateServiceStatusByID(id, status string, errorInfo *cloudprotocol.ErrorInfo) {
 	manager.statusMutex.Lock()
 	defer manager.statusMutex.Unlock()
 
@@ -836,8 +1214,8 @@ func (manager *softwareManager) updateServiceStatusByID(id, status, serviceErr s
 
 	info.Status = status
 
-	if serviceErr != "" {
-		info.ErrorInfo = &cloudprotocol.ErrorInfo{Message: serviceErr}
+	if errorInfo != nil {
+		info.ErrorInfo = errorInfo
 	}
 
 	manager.statusHandler.updateServiceStatus(*info)
@@ -887,7 +1265,8 @@ func (manager *softwareManager) installLayers() (installErr string) {
 			return
 		}
 
-		manager.updateLayerStatusByID(layer.Digest, cloudprotocol.ErrorStatus, layerErr)
+		manager.updateLayerStatusByID(layer.Digest, cloudprotocol.ErrorStatus,
+			newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, layerErr))
 
 		mutex.Lock()
 		defer mutex.Unlock()
@@ -900,6 +1279,11 @@ func (manager *softwareManager) installLayers() (installErr string) {
 	installLayers := []cloudprotocol.LayerInfo{}
 
 	for _, layer := range manager.CurrentUpdate.InstallLayers {
+		// Skip layers installed by previous attempt
+		if status, ok := manager.LayerStatuses[layer.Digest]; ok && status.Status == cloudprotocol.InstalledStatus {
+			continue
+		}
+
 		downloadInfo, ok := manager.DownloadResult[layer.Digest]
 		if !ok {
 			handleError(layer, aoserrors.New("can't get download result").Error())
@@ -928,7 +1312,7 @@ func (manager *softwareManager) installLayers() (installErr string) {
 			"digest":     layer.Digest,
 		}).Debug("Install layer")
 
-		manager.updateLayerStatusByID(layer.Digest, cloudprotocol.InstallingStatus, "")
+		manager.updateLayerStatusByID(layer.Digest, cloudprotocol.InstallingStatus, nil)
 
 		// Create new variable to be captured by action function
 		layerInfo := layer
@@ -946,7 +1330,7 @@ func (manager *softwareManager) installLayers() (installErr string) {
 				"digest":     layerInfo.Digest,
 			}).Info("Layer successfully installed")
 
-			manager.updateLayerStatusByID(layerInfo.Digest, cloudprotocol.InstalledStatus, "")
+			manager.updateLayerStatusByID(layerInfo.Digest, cloudprotocol.InstalledStatus, nil)
 
 			return nil
 		})
@@ -983,7 +1367,8 @@ func (manager *softwareManager) processRemoveRestorLayers(
 			return
 		}
 
-		manager.updateLayerStatusByID(layer.Digest, cloudprotocol.ErrorStatus, layerErr)
+		manager.updateLayerStatusByID(layer.Digest, cloudprotocol.ErrorStatus,
+			newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, layerErr))
 
 		mutex.Lock()
 		defer mutex.Unlock()
@@ -1023,7 +1408,7 @@ func (manager *softwareManager) processRemoveRestorLayers(
 				"digest":     layerInfo.Digest,
 			}).Infof("Layer successfully %sd", operationStr)
 
-			manager.updateLayerStatusByID(layerInfo.Digest, successStatus, "")
+			manager.updateLayerStatusByID(layerInfo.Digest, successStatus, nil)
 
 			return nil
 		})
@@ -1047,7 +1432,8 @@ func (manager *softwareManager) installServices() (newServices []string, install
 			return
 		}
 
-		manager.updateStatusByID(service.ID, cloudprotocol.ErrorStatus, serviceErr)
+		manager.updateStatusByID(service.ID, cloudprotocol.ErrorStatus,
+			newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, serviceErr))
 
 		mutex.Lock()
 		defer mutex.Unlock()
@@ -1060,6 +1446,11 @@ func (manager *softwareManager) installServices() (newServices []string, install
 	installServices := []cloudprotocol.ServiceInfo{}
 
 	for _, service := range manager.CurrentUpdate.InstallServices {
+		// Skip services installed by previous attempt
+		if status, ok := manager.ServiceStatuses[service.ID]; ok && status.Status == cloudprotocol.InstalledStatus {
+			continue
+		}
+
 		downloadInfo, ok := manager.DownloadResult[service.ID]
 		if !ok {
 			handleError(service, aoserrors.New("can't get download result").Error())
@@ -1087,7 +1478,7 @@ func (manager *softwareManager) installServices() (newServices []string, install
 			"aosVersion": service.AosVersion,
 		}).Debug("Install service")
 
-		manager.updateServiceStatusByID(service.ID, cloudprotocol.InstallingStatus, "")
+		manager.updateServiceStatusByID(service.ID, cloudprotocol.InstallingStatus, nil)
 
 		// Create new variable to be captured by action function
 		serviceInfo := service
@@ -1107,7 +1498,7 @@ func (manager *softwareManager) installServices() (newServices []string, install
 
 			newServices = append(newServices, serviceInfo.ID)
 
-			manager.updateServiceStatusByID(serviceInfo.ID, cloudprotocol.InstalledStatus, "")
+			manager.updateServiceStatusByID(serviceInfo.ID, cloudprotocol.InstalledStatus, nil)
 
 			return nil
 		})
@@ -1131,7 +1522,8 @@ func (manager *softwareManager) restoreServices() (restoreErr string) {
 			return
 		}
 
-		manager.updateStatusByID(service.ID, cloudprotocol.ErrorStatus, serviceErr)
+		manager.updateStatusByID(service.ID, cloudprotocol.ErrorStatus,
+			newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, serviceErr))
 
 		mutex.Lock()
 		defer mutex.Unlock()
@@ -1168,7 +1560,7 @@ func (manager *softwareManager) restoreServices() (restoreErr string) {
 				"aosVersion": serviceInfo.AosVersion,
 			}).Info("Service successfully restored")
 
-			manager.updateServiceStatusByID(serviceInfo.ID, cloudprotocol.InstalledStatus, "")
+			manager.updateServiceStatusByID(serviceInfo.ID, cloudprotocol.InstalledStatus, nil)
 
 			return nil
 		})
@@ -1192,7 +1584,8 @@ func (manager *softwareManager) removeServices() (removeErr string) {
 			return
 		}
 
-		manager.updateStatusByID(service.ID, cloudprotocol.ErrorStatus, serviceErr)
+		manager.updateStatusByID(service.ID, cloudprotocol.ErrorStatus,
+			newErrorInfo(cloudprotocol.ErrorCodeInstallFailed, serviceErr))
 
 		mutex.Lock()
 		defer mutex.Unlock()
@@ -1217,7 +1610,7 @@ func (manager *softwareManager) removeServices() (removeErr string) {
 		}
 		manager.statusMutex.Unlock()
 
-		manager.updateServiceStatusByID(service.ID, cloudprotocol.RemovingStatus, "")
+		manager.updateServiceStatusByID(service.ID, cloudprotocol.RemovingStatus, nil)
 
 		// Create new variable to be captured by action function
 		serviceStatus := service
@@ -1233,7 +1626,7 @@ func (manager *softwareManager) removeServices() (removeErr string) {
 				"aosVersion": serviceStatus.AosVersion,
 			}).Info("Service successfully removed")
 
-			manager.updateServiceStatusByID(serviceStatus.ID, cloudprotocol.RemovedStatus, "")
+			manager.updateServiceStatusByID(serviceStatus.ID, cloudprotocol.RemovedStatus, nil)
 
 			return nil
 		})
@@ -1244,6 +1637,119 @@ func (manager *softwareManager) removeServices() (removeErr string) {
 	return removeErr
 }
 
+func (manager *softwareManager) cleanCache() {
+	policy := manager.cachePolicy
+
+	if policy.MaxItems == 0 && policy.MaxSize == 0 {
+		return
+	}
+
+	cachedItems, err := manager.getCachedItems()
+	if err != nil {
+		log.Errorf("Can't get cached items: %v", err)
+		return
+	}
+
+	// Oldest items go first to be evicted
+	sort.Slice(cachedItems, func(i, j int) bool {
+		return cachedItems[i].timestamp.Before(cachedItems[j].timestamp)
+	})
+
+	var totalSize uint64
+
+	for _, item := range cachedItems {
+		totalSize += item.size
+	}
+
+	numItems := len(cachedItems)
+
+	for _, item := range cachedItems {
+		if (policy.MaxItems == 0 || numItems <= policy.MaxItems) && (policy.MaxSize == 0 || totalSize <= policy.MaxSize) {
+			break
+		}
+
+		if err := manager.purgeCachedItem(item); err != nil {
+			log.WithFields(log.Fields{
+				"id": item.id, "isLayer": item.isLayer,
+			}).Errorf("Can't purge cached item: %v", err)
+
+			continue
+		}
+
+		log.WithFields(log.Fields{"id": item.id, "isLayer": item.isLayer}).Debug("Cached item purged")
+
+		numItems--
+		totalSize -= item.size
+	}
+}
+
+func (manager *softwareManager) getCachedItems() (cachedItems []cachedItem, err error) {
+	services, err := manager.softwareUpdater.GetServicesStatus()
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	layers, err := manager.softwareUpdater.GetLayersStatus()
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	// Items required by current desired status should never be evicted
+
+	requiredServices := make(map[string]struct{})
+	requiredLayers := make(map[string]struct{})
+
+	for _, service := range manager.CurrentUpdate.InstallServices {
+		requiredServices[service.ID] = struct{}{}
+	}
+
+	for _, service := range manager.CurrentUpdate.RestoreServices {
+		requiredServices[service.ID] = struct{}{}
+	}
+
+	for _, instance := range manager.CurrentUpdate.RunInstances {
+		requiredServices[instance.ServiceID] = struct{}{}
+	}
+
+	for _, layer := range manager.CurrentUpdate.InstallLayers {
+		requiredLayers[layer.Digest] = struct{}{}
+	}
+
+	for _, layer := range manager.CurrentUpdate.RestoreLayers {
+		requiredLayers[layer.Digest] = struct{}{}
+	}
+
+	for _, service := range services {
+		if _, ok := requiredServices[service.ID]; ok || !service.Cached {
+			continue
+		}
+
+		cachedItems = append(cachedItems, cachedItem{
+			id: service.ID, size: service.Size, timestamp: service.Timestamp,
+		})
+	}
+
+	for _, layer := range layers {
+		if _, ok := requiredLayers[layer.Digest]; ok || !layer.Cached {
+			continue
+		}
+
+		cachedItems = append(cachedItems, cachedItem{
+			id: layer.Digest, isLayer: true, size: layer.Size, timestamp: layer.Timestamp,
+		})
+	}
+
+	return cachedItems, nil
+}
+
+func (manager *softwareManager) purgeCachedItem(item cachedItem) error {
+	if item.isLayer {
+		return aoserrors.Wrap(manager.softwareUpdater.PurgeLayer(item.id))
+	}
+
+	return aoserrors.Wrap(manager.softwareUpdater.PurgeService(item.id))
+}
+
 func (manager *softwareManager) runInstances(newServices []string) (runErr string) {
 	manager.InstanceStatuses = []cloudprotocol.InstanceStatus{}
 
@@ -1275,9 +1781,128 @@ func (manager *softwareManager) runInstances(newServices []string) (runErr strin
 
 	manager.statusHandler.setInstanceStatus(manager.InstanceStatuses)
 
-	if err := manager.instanceRunner.RunInstances(manager.CurrentUpdate.RunInstances, newServices); err != nil {
+	// Cloud is notified about new services before their instances are started
+	if len(newServices) != 0 {
+		manager.statusHandler.sendNewServices(cloudprotocol.NewServices{
+			CorrelationID: manager.CurrentUpdate.CorrelationID,
+			Services:      getNewServices(manager.CurrentUpdate.RunInstances, newServices),
+		})
+	}
+
+	if err := manager.runDesiredInstances(newServices); err != nil {
 		return err.Error()
 	}
 
+	manager.CurrentInstances = manager.CurrentUpdate.RunInstances
+
 	return ""
 }
+
+// runDesiredInstances runs only instances of changed subject if instances of other subjects are not changed, so
+// instances of other subjects are kept on their nodes.
+func (manager *softwareManager) runDesiredInstances(newServices []string) error {
+	if subjectID, ok := getChangedSubject(manager.CurrentInstances, manager.CurrentUpdate.RunInstances); ok {
+		subjectInstances := make([]cloudprotocol.InstanceInfo, 0)
+
+		for _, instance := range manager.CurrentUpdate.RunInstances {
+			if instance.SubjectID == subjectID {
+				subjectInstances = append(subjectInstances, instance)
+			}
+		}
+
+		log.WithField("subjectID", subjectID).Debug("Run subject instances")
+
+		return aoserrors.Wrap(manager.instanceRunner.RunSubjectInstances(
+			subjectID, subjectInstances, newServices, manager.CurrentUpdate.CorrelationID))
+	}
+
+	return aoserrors.Wrap(manager.instanceRunner.RunInstances(
+		manager.CurrentUpdate.RunInstances, newServices, manager.CurrentUpdate.CorrelationID))
+}
+
+// getChangedSubject returns subject ID if instances of only this subject are changed. Current instances are unknown
+// before first run, in this case all instances should be run.
+func getChangedSubject(currentInstances, desiredInstances []cloudprotocol.InstanceInfo) (subjectID string, ok bool) {
+	if len(currentInstances) == 0 {
+		return "", false
+	}
+
+	currentSubjects := groupInstancesBySubject(currentInstances)
+	desiredSubjects := groupInstancesBySubject(desiredInstances)
+
+	var changedSubjects []string
+
+	for subjectID, instances := range desiredSubjects {
+		if !reflect.DeepEqual(instances, currentSubjects[subjectID]) {
+			changedSubjects = append(changedSubjects, subjectID)
+		}
+	}
+
+	for subjectID := range currentSubjects {
+		if _, ok := desiredSubjects[subjectID]; !ok {
+			changedSubjects = append(changedSubjects, subjectID)
+		}
+	}
+
+	if len(changedSubjects) != 1 {
+		return "", false
+	}
+
+	return changedSubjects[0], true
+}
+
+func groupInstancesBySubject(
+	instances []cloudprotocol.InstanceInfo,
+) (subjects map[string][]cloudprotocol.InstanceInfo) {
+	subjects = make(map[string][]cloudprotocol.InstanceInfo)
+
+	for _, instance := range instances {
+		subjects[instance.SubjectID] = append(subjects[instance.SubjectID], instance)
+	}
+
+	return subjects
+}
+
+func getNewServices(
+	instances []cloudprotocol.InstanceInfo, newServices []string,
+) (services []cloudprotocol.NewService) {
+	services = make([]cloudprotocol.NewService, 0, len(newServices))
+
+	for _, serviceID := range newServices {
+		newService := cloudprotocol.NewService{ServiceID: serviceID}
+
+		for _, instance := range instances {
+			if instance.ServiceID == serviceID {
+				newService.NumInstances += instance.NumInstances
+			}
+		}
+
+		services = append(services, newService)
+	}
+
+	return services
+}
+
+func findInstalledService(services []ServiceStatus, id string) *cloudprotocol.ServiceStatus {
+	for _, service := range services {
+		if service.ID == id && service.Status == cloudprotocol.InstalledStatus && !service.Cached {
+			status := service.ServiceStatus
+
+			return &status
+		}
+	}
+
+	return nil
+}
+
+func findInstalledLayer(layers []LayerStatus, id string) *cloudprotocol.LayerStatus {
+	for _, layer := range layers {
+		if layer.ID == id && layer.Status == cloudprotocol.InstalledStatus && !layer.Cached {
+			status := layer.LayerStatus
+
+			return &status
+		}
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@
 package unitstatushandler
 
 import (
+	"sort"
 	"time"
 
 	"github.com/aosedge/aos_common/aoserrors"
@@ -35,16 +36,25 @@ const maxAvailableTime = 1<<63 - 1
  * Types
  **********************************************************************************************************************/
 
-func validateTimetable(timetable []cloudprotocol.TimetableEntry) (err error) {
+// ValidateTimetable validates timetable entries.
+func ValidateTimetable(timetable []cloudprotocol.TimetableEntry) (err error) {
 	if len(timetable) == 0 {
 		return aoserrors.New("timetable is empty")
 	}
 
+	days := make(map[uint]struct{})
+
 	for _, entry := range timetable {
 		if entry.DayOfWeek > 7 || entry.DayOfWeek < 1 {
 			return aoserrors.New("invalid day of week value")
 		}
 
+		if _, ok := days[entry.DayOfWeek]; ok {
+			return aoserrors.Errorf("duplicate entry for day of week %d", entry.DayOfWeek)
+		}
+
+		days[entry.DayOfWeek] = struct{}{}
+
 		if len(entry.TimeSlots) == 0 {
 			return aoserrors.New("no time slots")
 		}
@@ -62,6 +72,17 @@ func validateTimetable(timetable []cloudprotocol.TimetableEntry) (err error) {
 				return aoserrors.New("start value should be before finish value")
 			}
 		}
+
+		slots := make([]cloudprotocol.TimeSlot, len(entry.TimeSlots))
+		copy(slots, entry.TimeSlots)
+
+		sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start.Time) })
+
+		for i := 1; i < len(slots); i++ {
+			if slots[i].Start.Before(slots[i-1].Finish.Time) {
+				return aoserrors.Errorf("overlapping time slots for day of week %d", entry.DayOfWeek)
+			}
+		}
 	}
 
 	return nil
@@ -77,7 +98,7 @@ func getAvailableTimetableTime(
 	// Set to maximum by default
 	availableTime = maxAvailableTime
 
-	if err = validateTimetable(timetable); err != nil {
+	if err = ValidateTimetable(timetable); err != nil {
 		return availableTime, err
 	}
 
@@ -126,3 +147,42 @@ func getAvailableTimetableTime(
 
 	return availableTime, nil
 }
+
+// validateSchedule validates update schedule. Default TTL is used if schedule TTL is not set. Timetable window is
+// checked against TTL only if check window is set.
+func validateSchedule(
+	schedule cloudprotocol.ScheduleRule, defaultTTL time.Duration, now time.Time, checkWindow bool,
+) error {
+	switch schedule.Type {
+	case "", cloudprotocol.ForceUpdate, cloudprotocol.TriggerUpdate:
+
+	case cloudprotocol.TimetableUpdate:
+		if err := ValidateTimetable(schedule.Timetable); err != nil {
+			return err
+		}
+
+	default:
+		return aoserrors.Errorf("wrong update type %s", schedule.Type)
+	}
+
+	ttl := time.Duration(schedule.TTL) * time.Second
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	// Zero TTL means update never expires. Emergency update doesn't wait for timetable window.
+	if !checkWindow || ttl == 0 || schedule.Emergency || schedule.Type != cloudprotocol.TimetableUpdate {
+		return nil
+	}
+
+	availableTime, err := getAvailableTimetableTime(now, schedule.Timetable)
+	if err != nil {
+		return err
+	}
+
+	if availableTime >= ttl {
+		return aoserrors.Errorf("update TTL %s expires before next timetable window in %s", ttl, availableTime)
+	}
+
+	return nil
+}
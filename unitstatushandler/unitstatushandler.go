@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/rand"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -36,6 +37,12 @@ import (
 	"github.com/aosedge/aos_communicationmanager/downloader"
 )
 
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+const maxSendStatusJitter = 100
+
 /***********************************************************************************************************************
  * Types
  **********************************************************************************************************************/
@@ -45,14 +52,32 @@ type Downloader interface {
 	Download(ctx context.Context, packageInfo downloader.PackageInfo) (result downloader.Result, err error)
 	Release(filePath string) error
 	ReleaseByType(targetType string) error
+	ReleaseByID(id string) error
+	CancelDownload(id string)
+	GetDownloadStats() []downloader.DownloadStat
 }
 
 // StatusSender sends unit status to cloud.
 type StatusSender interface {
 	SendUnitStatus(unitStatus cloudprotocol.UnitStatus) (err error)
+	SendNewServices(newServices cloudprotocol.NewServices) (err error)
 	SubscribeForConnectionEvents(consumer amqphandler.ConnectionEventsConsumer) error
 }
 
+// ClockSkewProvider provides local clock skew against trusted time source. Status sender may implement it to
+// enable clock synchronization check.
+type ClockSkewProvider interface {
+	GetClockSkew() (skew time.Duration, ok bool)
+}
+
+// PolicyChecker checks desired status items against enterprise policies. Check functions return error with the
+// rejection reason if item is not allowed. Installed is nil if item is not installed yet.
+type PolicyChecker interface {
+	CheckComponent(component cloudprotocol.ComponentInfo, installed *cloudprotocol.ComponentStatus) error
+	CheckService(service cloudprotocol.ServiceInfo, installed *cloudprotocol.ServiceStatus) error
+	CheckLayer(layer cloudprotocol.LayerInfo, installed *cloudprotocol.LayerStatus) error
+}
+
 // UnitConfigUpdater updates unit configuration.
 type UnitConfigUpdater interface {
 	GetStatus() (unitConfigInfo cloudprotocol.UnitConfigStatus, err error)
@@ -70,7 +95,9 @@ type FirmwareUpdater interface {
 
 // InstanceRunner instances runner.
 type InstanceRunner interface {
-	RunInstances(instances []cloudprotocol.InstanceInfo, newServices []string) error
+	RunInstances(instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string) error
+	RunSubjectInstances(
+		subjectID string, instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string) error
 	RestartInstances() error
 	GetNodesConfiguration() []cloudprotocol.NodeInfo
 }
@@ -87,6 +114,8 @@ type SoftwareUpdater interface {
 		chains []cloudprotocol.CertificateChain, certs []cloudprotocol.Certificate) error
 	RemoveLayer(digest string) error
 	RestoreLayer(digest string) error
+	PurgeService(serviceID string) error
+	PurgeLayer(digest string) error
 }
 
 // Storage used to store unit status handler states.
@@ -95,18 +124,24 @@ type Storage interface {
 	GetFirmwareUpdateState() (state json.RawMessage, err error)
 	SetSoftwareUpdateState(state json.RawMessage) (err error)
 	GetSoftwareUpdateState() (state json.RawMessage, err error)
+	SetUpdateHistory(history json.RawMessage) (err error)
+	GetUpdateHistory() (history json.RawMessage, err error)
 }
 
 // ServiceStatus represents service status.
 type ServiceStatus struct {
 	cloudprotocol.ServiceStatus
-	Cached bool
+	Cached    bool
+	Size      uint64
+	Timestamp time.Time
 }
 
 // LayerStatus represents layer status.
 type LayerStatus struct {
 	cloudprotocol.LayerStatus
-	Cached bool
+	Cached    bool
+	Size      uint64
+	Timestamp time.Time
 }
 
 // RunInstancesStatus run instances status.
@@ -114,6 +149,7 @@ type RunInstancesStatus struct {
 	UnitSubjects  []string
 	Instances     []cloudprotocol.InstanceStatus
 	ErrorServices []cloudprotocol.ServiceStatus
+	CorrelationID string
 }
 
 // Instance instance of unit status handler.
@@ -131,16 +167,25 @@ type Instance struct {
 	layerStatuses     map[string]*itemStatus
 	serviceStatuses   map[string]*itemStatus
 	instanceStatuses  []cloudprotocol.InstanceStatus
+	correlationID     string
 
 	sendStatusPeriod time.Duration
+	sendStatusJitter uint
 
 	firmwareManager *firmwareManager
 	softwareManager *softwareManager
+	updateHistory   *updateHistory
+	downloader      Downloader
+	clock           Clock
+	runSerializer   *runSerializer
 
-	initDone    bool
-	isConnected int32
+	initDone             bool
+	isConnected          int32
+	connectionStatusSent int32
 }
 
+type allowAllPolicy struct{}
+
 type statusDescriptor struct {
 	amqpStatus interface{}
 }
@@ -167,6 +212,8 @@ func New(
 	instance = &Instance{
 		statusSender:     statusSender,
 		sendStatusPeriod: cfg.UnitStatusSendTimeout.Duration,
+		sendStatusJitter: cfg.UnitStatusSendJitter,
+		clock:            systemClock{},
 	}
 
 	// Initialize maps of statuses for avoiding situation of adding values to uninitialized map on go routine
@@ -174,18 +221,47 @@ func New(
 	instance.layerStatuses = make(map[string]*itemStatus)
 	instance.serviceStatuses = make(map[string]*itemStatus)
 
+	if instance.updateHistory, err = newUpdateHistory(storage); err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	// In observer mode desired statuses are processed as usual but all side-effecting calls are stubbed
+	if cfg.ObserverMode {
+		log.Warn("Unit status handler runs in observer mode")
+
+		unitConfigUpdater = &observerUnitConfigUpdater{UnitConfigUpdater: unitConfigUpdater}
+		firmwareUpdater = &observerFirmwareUpdater{FirmwareUpdater: firmwareUpdater}
+		softwareUpdater = &observerSoftwareUpdater{SoftwareUpdater: softwareUpdater}
+		instanceRunner = &observerInstanceRunner{InstanceRunner: instanceRunner, statusHandler: instance}
+		downloader = &observerDownloader{}
+	}
+
+	instance.runSerializer = newRunSerializer(instanceRunner, cfg.RestartDuringUpdate)
+	instanceRunner = instance.runSerializer
+
 	groupDownloader := newGroupDownloader(downloader)
 
+	instance.downloader = groupDownloader
+
 	if instance.firmwareManager, err = newFirmwareManager(instance, groupDownloader, firmwareUpdater, unitConfigUpdater,
-		storage, instanceRunner, cfg.UMController.UpdateTTL.Duration); err != nil {
+		storage, instanceRunner, cfg.UMController.UpdateTTL.Duration, cfg.UMController.ContinueOnError); err != nil {
 		return nil, aoserrors.Wrap(err)
 	}
 
+	instance.firmwareManager.setUnitConfigOrder(cfg.UMController.UnitConfigOrder)
+
 	if instance.softwareManager, err = newSoftwareManager(instance, groupDownloader, softwareUpdater, instanceRunner,
-		storage, cfg.SMController.UpdateTTL.Duration); err != nil {
+		storage, cfg.SMController.UpdateTTL.Duration, cfg.CachePolicy, cfg.UpdateRetryBudget); err != nil {
 		return nil, aoserrors.Wrap(err)
 	}
 
+	if provider, ok := statusSender.(ClockSkewProvider); ok && cfg.MaxClockSkew.Duration != 0 {
+		clockChecker := newClockChecker(provider, cfg.MaxClockSkew.Duration)
+
+		instance.firmwareManager.stateMachine.setClockChecker(clockChecker)
+		instance.softwareManager.stateMachine.setClockChecker(clockChecker)
+	}
+
 	if err = instance.statusSender.SubscribeForConnectionEvents(instance); err != nil {
 		return nil, aoserrors.Wrap(err)
 	}
@@ -208,6 +284,8 @@ func (instance *Instance) Close() (err error) {
 
 	instance.statusMutex.Unlock()
 
+	instance.runSerializer.close()
+
 	if managerErr := instance.firmwareManager.close(); managerErr != nil {
 		if err == nil {
 			err = aoserrors.Wrap(managerErr)
@@ -228,6 +306,9 @@ func (instance *Instance) SendUnitStatus() error {
 	instance.Lock()
 	defer instance.Unlock()
 
+	instance.statusMutex.Lock()
+	defer instance.statusMutex.Unlock()
+
 	instance.sendCurrentStatus()
 
 	return nil
@@ -242,10 +323,32 @@ func (instance *Instance) ProcessRunStatus(status RunInstancesStatus) error {
 		return aoserrors.Wrap(err)
 	}
 
+	// Run status may arrive before any desired status: reported instances are used as current state
+	if status.UnitSubjects == nil {
+		status.UnitSubjects = []string{}
+	}
+
+	if status.Instances == nil {
+		status.Instances = []cloudprotocol.InstanceStatus{}
+	}
+
+	instance.statusMutex.Lock()
+
 	instance.unitSubjects = status.UnitSubjects
 	instance.instanceStatuses = status.Instances
+	// Correlation ID is kept until unit status with it is sent
+	if status.CorrelationID != "" {
+		instance.correlationID = status.CorrelationID
+	}
+
+	instance.statusMutex.Unlock()
 
+	instance.runSerializer.processRunStatus()
 	instance.softwareManager.processRunStatus(status)
+
+	instance.statusMutex.Lock()
+	defer instance.statusMutex.Unlock()
+
 	instance.sendCurrentStatus()
 
 	return nil
@@ -264,15 +367,61 @@ func (instance *Instance) ProcessDesiredStatus(desiredStatus cloudprotocol.Desir
 	instance.Lock()
 	defer instance.Unlock()
 
-	if err := instance.firmwareManager.processDesiredStatus(desiredStatus); err != nil {
+	now := instance.clock.Now()
+
+	// Timetable window can't be checked on unsynchronized clock: it is checked when update is scheduled
+	checkWindow := instance.firmwareManager.stateMachine.checkClock() == nil
+
+	if err := validateSchedule(desiredStatus.FOTASchedule, instance.firmwareManager.stateMachine.defaultTTL,
+		now, checkWindow); err != nil {
+		instance.firmwareManager.rejectDesiredStatus(desiredStatus, aoserrors.Errorf("invalid FOTA schedule: %v", err))
+	} else if err := instance.firmwareManager.processDesiredStatus(desiredStatus); err != nil {
 		log.Errorf("Error processing firmware desired status: %s", err)
 	}
 
-	if err := instance.softwareManager.processDesiredStatus(desiredStatus); err != nil {
+	if err := validateSchedule(desiredStatus.SOTASchedule, instance.softwareManager.stateMachine.defaultTTL,
+		now, checkWindow); err != nil {
+		instance.softwareManager.rejectDesiredStatus(desiredStatus, aoserrors.Errorf("invalid SOTA schedule: %v", err))
+	} else if err := instance.softwareManager.processDesiredStatus(desiredStatus); err != nil {
 		log.Errorf("Error processing software desired status: %s", err)
 	}
 }
 
+// SetPolicyChecker sets policy checker consulted on desired status processing.
+func (instance *Instance) SetPolicyChecker(checker PolicyChecker) {
+	if checker == nil {
+		checker = allowAllPolicy{}
+	}
+
+	instance.firmwareManager.setPolicyChecker(checker)
+	instance.softwareManager.setPolicyChecker(checker)
+}
+
+// SetClock sets time source used by update managers.
+func (instance *Instance) SetClock(clock Clock) {
+	instance.Lock()
+	defer instance.Unlock()
+
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	instance.clock = clock
+
+	instance.firmwareManager.setClock(clock)
+	instance.softwareManager.setClock(clock)
+}
+
+// GetDownloadStats returns throughput stats of recent downloads.
+func (instance *Instance) GetDownloadStats() []downloader.DownloadStat {
+	return instance.downloader.GetDownloadStats()
+}
+
+// GetUpdateHistory returns FOTA and SOTA update history.
+func (instance *Instance) GetUpdateHistory() []UpdateHistoryEntry {
+	return instance.updateHistory.getEntries()
+}
+
 // GetFOTAStatusChannel returns FOTA status channels.
 func (instance *Instance) GetFOTAStatusChannel() (channel <-chan cmserver.UpdateFOTAStatus) {
 	instance.Lock()
@@ -321,9 +470,48 @@ func (instance *Instance) StartSOTAUpdate() (err error) {
 	return instance.softwareManager.startUpdate()
 }
 
-// CloudConnected indicates unit connected to cloud.
+// ExtendFOTATTL extends TTL deadline of FOTA update awaiting trigger.
+func (instance *Instance) ExtendFOTATTL(extension time.Duration) (err error) {
+	instance.Lock()
+	defer instance.Unlock()
+
+	return instance.firmwareManager.extendTTL(extension)
+}
+
+// ExtendSOTATTL extends TTL deadline of SOTA update awaiting trigger.
+func (instance *Instance) ExtendSOTATTL(extension time.Duration) (err error) {
+	instance.Lock()
+	defer instance.Unlock()
+
+	return instance.softwareManager.extendTTL(extension)
+}
+
+// CloudConnected indicates unit connected to cloud. Current unit status is sent on each connection as cloud may miss
+// status changes while unit was disconnected.
 func (instance *Instance) CloudConnected() {
-	atomic.StoreInt32(&instance.isConnected, 1)
+	if !atomic.CompareAndSwapInt32(&instance.isConnected, 0, 1) {
+		return
+	}
+
+	atomic.StoreInt32(&instance.connectionStatusSent, 0)
+
+	// Send asynchronously as connection events are notified under sender lock
+	go func() {
+		instance.Lock()
+		defer instance.Unlock()
+
+		// Status is already sent on this connection by other event
+		if atomic.LoadInt32(&instance.connectionStatusSent) != 0 {
+			return
+		}
+
+		log.Debug("Send unit status on cloud connection")
+
+		instance.statusMutex.Lock()
+		defer instance.statusMutex.Unlock()
+
+		instance.sendCurrentStatus()
+	}()
 }
 
 // CloudDisconnected indicates unit disconnected from cloud.
@@ -462,6 +650,10 @@ func (descriptor *statusDescriptor) getVersion() (version string) {
 	}
 }
 
+func (instance *Instance) addUpdateHistoryEntry(entry UpdateHistoryEntry) {
+	instance.updateHistory.addEntry(entry)
+}
+
 func (instance *Instance) updateUnitConfigStatus(unitConfigInfo cloudprotocol.UnitConfigStatus) {
 	instance.statusMutex.Lock()
 	defer instance.statusMutex.Unlock()
@@ -581,6 +773,7 @@ foundloop:
 				}).Debug("Update instance status")
 
 				instance.instanceStatuses[i].StateChecksum = instanceStatus.StateChecksum
+				instance.instanceStatuses[i].StateCorrupted = instanceStatus.StateCorrupted
 				instance.instanceStatuses[i].RunState = instanceStatus.RunState
 				instance.instanceStatuses[i].ErrorInfo = instanceStatus.ErrorInfo
 
@@ -608,7 +801,7 @@ func (instance *Instance) statusChanged() {
 		return
 	}
 
-	instance.statusTimer = time.AfterFunc(instance.sendStatusPeriod, func() {
+	instance.statusTimer = time.AfterFunc(instance.getSendStatusPeriod(), func() {
 		instance.statusMutex.Lock()
 		defer instance.statusMutex.Unlock()
 
@@ -616,6 +809,27 @@ func (instance *Instance) statusChanged() {
 	})
 }
 
+func (instance *Instance) getSendStatusPeriod() time.Duration {
+	if instance.sendStatusJitter == 0 {
+		return instance.sendStatusPeriod
+	}
+
+	jitter := instance.sendStatusJitter
+	if jitter > maxSendStatusJitter {
+		jitter = maxSendStatusJitter
+	}
+
+	// Shift send time randomly within [-jitter%, +jitter%] of send period to spread sends of different units
+	maxDelta := int64(instance.sendStatusPeriod) * int64(jitter) / 100
+	if maxDelta == 0 {
+		return instance.sendStatusPeriod
+	}
+
+	delta := rand.Int63n(2*maxDelta+1) - maxDelta //nolint:gosec // secure random is not required for jitter
+
+	return instance.sendStatusPeriod + time.Duration(delta)
+}
+
 func (instance *Instance) updateStatus(status *itemStatus, descriptor statusDescriptor) {
 	if descriptor.getStatus() == cloudprotocol.InstalledStatus {
 		*status = itemStatus{descriptor}
@@ -632,6 +846,7 @@ func (instance *Instance) updateStatus(status *itemStatus, descriptor statusDesc
 	*status = append(*status, descriptor)
 }
 
+// sendCurrentStatus sends unit status. Should be called with status mutex locked.
 func (instance *Instance) sendCurrentStatus() {
 	if instance.statusTimer != nil {
 		instance.statusTimer.Stop()
@@ -646,13 +861,27 @@ func (instance *Instance) sendCurrentStatus() {
 		return
 	}
 
-	unitStatus := cloudprotocol.UnitStatus{
-		UnitSubjects: instance.unitSubjects,
-		Components:   make([]cloudprotocol.ComponentStatus, 0, len(instance.componentStatuses)),
-		Layers:       make([]cloudprotocol.LayerStatus, 0, len(instance.layerStatuses)),
-		Services:     make([]cloudprotocol.ServiceStatus, 0, len(instance.serviceStatuses)),
-		Instances:    instance.instanceStatuses,
-		Nodes:        instance.softwareManager.instanceRunner.GetNodesConfiguration(),
+	err := instance.statusSender.SendUnitStatus(instance.getUnitStatus())
+	if err != nil && !errors.Is(err, amqphandler.ErrNotConnected) {
+		log.Errorf("Can't send unit status: %s", err)
+	}
+
+	if err == nil {
+		instance.correlationID = ""
+	}
+
+	atomic.StoreInt32(&instance.connectionStatusSent, 1)
+}
+
+func (instance *Instance) getUnitStatus() (unitStatus cloudprotocol.UnitStatus) {
+	unitStatus = cloudprotocol.UnitStatus{
+		CorrelationID: instance.correlationID,
+		UnitSubjects:  instance.unitSubjects,
+		Components:    make([]cloudprotocol.ComponentStatus, 0, len(instance.componentStatuses)),
+		Layers:        make([]cloudprotocol.LayerStatus, 0, len(instance.layerStatuses)),
+		Services:      make([]cloudprotocol.ServiceStatus, 0, len(instance.serviceStatuses)),
+		Instances:     instance.instanceStatuses,
+		Nodes:         instance.softwareManager.instanceRunner.GetNodesConfiguration(),
 	}
 
 	for _, status := range instance.unitConfigStatus {
@@ -701,8 +930,48 @@ func (instance *Instance) sendCurrentStatus() {
 		}
 	}
 
-	if err := instance.statusSender.SendUnitStatus(
-		unitStatus); err != nil && !errors.Is(err, amqphandler.ErrNotConnected) {
-		log.Errorf("Can't send unit status: %s", err)
+	return unitStatus
+}
+
+// sendNewServices notifies cloud about new services before their instances are started. Notification is queued by
+// sender while unit is offline.
+func (instance *Instance) sendNewServices(newServices cloudprotocol.NewServices) {
+	log.WithField("services", newServices.Services).Info("Start new services")
+
+	if err := instance.statusSender.SendNewServices(newServices); err != nil {
+		log.Errorf("Can't send new services: %s", err)
+	}
+}
+
+func newClockChecker(provider ClockSkewProvider, maxSkew time.Duration) func() error {
+	return func() error {
+		skew, ok := provider.GetClockSkew()
+		if !ok {
+			return nil
+		}
+
+		if skew > maxSkew || skew < -maxSkew {
+			log.WithFields(log.Fields{"skew": skew, "maxSkew": maxSkew}).Warn("Local clock skew exceeds threshold")
+
+			return aoserrors.Wrap(errClockNotSynchronized)
+		}
+
+		return nil
 	}
 }
+
+func (allowAllPolicy) CheckComponent(cloudprotocol.ComponentInfo, *cloudprotocol.ComponentStatus) error {
+	return nil
+}
+
+func (allowAllPolicy) CheckService(cloudprotocol.ServiceInfo, *cloudprotocol.ServiceStatus) error {
+	return nil
+}
+
+func (allowAllPolicy) CheckLayer(cloudprotocol.LayerInfo, *cloudprotocol.LayerStatus) error {
+	return nil
+}
+
+func policyErrorInfo(err error) *cloudprotocol.ErrorInfo {
+	return &cloudprotocol.ErrorInfo{ErrorCode: cloudprotocol.ErrorCodePolicyRejected, Message: err.Error()}
+}
@@ -24,6 +24,8 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -34,6 +36,7 @@ import (
 
 	"github.com/aosedge/aos_communicationmanager/amqphandler"
 	"github.com/aosedge/aos_communicationmanager/cmserver"
+	"github.com/aosedge/aos_communicationmanager/config"
 	"github.com/aosedge/aos_communicationmanager/downloader"
 )
 
@@ -54,8 +57,9 @@ const waitStatusTimeout = 5 * time.Second
  **********************************************************************************************************************/
 
 type TestSender struct {
-	Consumer      amqphandler.ConnectionEventsConsumer
-	statusChannel chan cloudprotocol.UnitStatus
+	Consumer           amqphandler.ConnectionEventsConsumer
+	statusChannel      chan cloudprotocol.UnitStatus
+	newServicesChannel chan cloudprotocol.NewServices
 }
 
 type TestUnitConfigUpdater struct {
@@ -69,25 +73,38 @@ type TestFirmwareUpdater struct {
 	InitComponentsInfo   []cloudprotocol.ComponentStatus
 	UpdateComponentsInfo []cloudprotocol.ComponentStatus
 	UpdateError          error
+	UpdatedComponents    []string
 }
 
 type TestSoftwareUpdater struct {
-	AllServices []ServiceStatus
-	AllLayers   []LayerStatus
-	UpdateError error
+	AllServices       []ServiceStatus
+	AllLayers         []LayerStatus
+	UpdateError       error
+	PurgedServices    []string
+	PurgedLayers      []string
+	InstalledServices []string
 }
 
 type TestInstanceRunner struct {
 	runInstanceChan chan []cloudprotocol.InstanceInfo
+	instances       []cloudprotocol.InstanceInfo
+	subjectID       string
 	newServices     []string
+	correlationID   string
+	restartCount    int32
 }
 
 type TestDownloader struct {
+	sync.Mutex
+
 	DownloadTime   time.Duration
 	DownloadedURLs []string
+	ReleasedIDs    []string
+	Stats          []downloader.DownloadStat
 
 	errorURL    string
 	downloadErr error
+	cancelFuncs map[string]context.CancelFunc
 }
 
 type TestResult struct {
@@ -104,11 +121,36 @@ type testGroupDownloader struct {
 	fotaReleased bool
 }
 
-type testStatusHandler struct{}
+type testStatusHandler struct {
+	sync.Mutex
+	installingItems []string
+	historyEntries  []UpdateHistoryEntry
+	newServices     []cloudprotocol.NewServices
+}
+
+type testClockSkewProvider struct {
+	skew int64
+}
+
+type testClock struct {
+	sync.Mutex
+	now    time.Time
+	timers []*testTimer
+}
+
+type testTimer struct {
+	clock    *testClock
+	channel  chan time.Time
+	callback func()
+	deadline time.Time
+	active   bool
+}
 
 type TestStorage struct {
-	sotaState json.RawMessage
-	fotaState json.RawMessage
+	sync.Mutex
+	sotaState     json.RawMessage
+	fotaState     json.RawMessage
+	updateHistory json.RawMessage
 }
 
 /***********************************************************************************************************************
@@ -224,9 +266,9 @@ func TestGroupDownloader(t *testing.T) {
 		}
 
 		result := testGroupDownloader.download(ctx, item.request, item.continueOnError,
-			func(id string, status string, componentErr string) {
+			func(id string, status string, errorInfo *cloudprotocol.ErrorInfo) {
 				log.WithFields(log.Fields{
-					"id": id, "status": status, "error": componentErr,
+					"id": id, "status": status, "error": errorInfo,
 				}).Debug("Component download status")
 			})
 
@@ -238,6 +280,50 @@ func TestGroupDownloader(t *testing.T) {
 	}
 }
 
+func TestGroupDownloaderCancelByID(t *testing.T) {
+	testDownloader := NewTestDownloader()
+	testDownloader.DownloadTime = 2 * time.Second
+
+	testGroupDownloader := newGroupDownloader(testDownloader)
+
+	resultChannel := make(chan map[string]*downloadResult, 1)
+
+	go func() {
+		resultChannel <- testGroupDownloader.download(context.Background(), map[string]downloader.PackageInfo{
+			"0": {TargetID: "0"}, "1": {TargetID: "1"}, "2": {TargetID: "2"},
+		}, false, func(id string, status string, errorInfo *cloudprotocol.ErrorInfo) {
+			log.WithFields(log.Fields{
+				"id": id, "status": status, "error": errorInfo,
+			}).Debug("Service download status")
+		})
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+
+	if err := testGroupDownloader.cancelDownload("1"); err != nil {
+		t.Fatalf("Can't cancel download: %v", err)
+	}
+
+	select {
+	case result := <-resultChannel:
+		if err := checkDownloadResult(result, map[string]int{
+			"0": downloadSuccess, "1": downloadCanceled, "2": downloadSuccess,
+		}); err != nil {
+			t.Errorf("Check result failed: %s", err)
+		}
+
+	case <-time.After(waitStatusTimeout):
+		t.Fatal("Wait download result timeout")
+	}
+
+	testDownloader.Lock()
+	defer testDownloader.Unlock()
+
+	if !reflect.DeepEqual(testDownloader.ReleasedIDs, []string{"1"}) {
+		t.Errorf("Wrong released IDs: %v", testDownloader.ReleasedIDs)
+	}
+}
+
 func TestFirmwareManager(t *testing.T) {
 	type testData struct {
 		testID                  string
@@ -450,6 +536,64 @@ func TestFirmwareManager(t *testing.T) {
 			},
 			updateWaitStatuses: []cmserver.UpdateStatus{{State: cmserver.NoUpdate}},
 		},
+		{
+			testID: "interrupted update already applied on updating state",
+			initState: &firmwareManager{
+				CurrentState:  stateUpdating,
+				CurrentUpdate: &firmwareUpdate{Components: updateComponents},
+				ComponentStatuses: map[string]*cloudprotocol.ComponentStatus{
+					updateComponents[0].ID: {
+						ID:            updateComponents[0].ID,
+						VendorVersion: updateComponents[0].VendorVersion,
+						Status:        cloudprotocol.InstallingStatus,
+					},
+					updateComponents[1].ID: {
+						ID:            updateComponents[1].ID,
+						VendorVersion: updateComponents[1].VendorVersion,
+						Status:        cloudprotocol.InstallingStatus,
+					},
+				},
+			},
+			initComponentStatuses: []cloudprotocol.ComponentStatus{
+				{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+				{ID: "comp2", VendorVersion: "2.0", Status: cloudprotocol.InstalledStatus},
+			},
+			updateComponentStatuses: []cloudprotocol.ComponentStatus{
+				{
+					ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.ErrorStatus,
+					ErrorInfo: &cloudprotocol.ErrorInfo{Message: "update should not be called"},
+				},
+			},
+			updateWaitStatuses: []cmserver.UpdateStatus{{State: cmserver.NoUpdate}},
+		},
+		{
+			testID: "interrupted update on updating state",
+			initState: &firmwareManager{
+				CurrentState:  stateUpdating,
+				CurrentUpdate: &firmwareUpdate{Components: updateComponents},
+				ComponentStatuses: map[string]*cloudprotocol.ComponentStatus{
+					updateComponents[0].ID: {
+						ID:            updateComponents[0].ID,
+						VendorVersion: updateComponents[0].VendorVersion,
+						Status:        cloudprotocol.InstallingStatus,
+					},
+					updateComponents[1].ID: {
+						ID:            updateComponents[1].ID,
+						VendorVersion: updateComponents[1].VendorVersion,
+						Status:        cloudprotocol.InstallingStatus,
+					},
+				},
+			},
+			initComponentStatuses: []cloudprotocol.ComponentStatus{
+				{ID: "comp1", VendorVersion: "0.0", Status: cloudprotocol.InstalledStatus},
+				{ID: "comp2", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+			},
+			updateComponentStatuses: []cloudprotocol.ComponentStatus{
+				{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+				{ID: "comp2", VendorVersion: "2.0", Status: cloudprotocol.InstalledStatus},
+			},
+			updateWaitStatuses: []cmserver.UpdateStatus{{State: cmserver.NoUpdate, Error: "update interrupted"}},
+		},
 		{
 			testID: "same update on ready to update state",
 			initState: &firmwareManager{
@@ -711,7 +855,7 @@ func TestFirmwareManager(t *testing.T) {
 		// Create firmware manager
 
 		firmwareManager, err := newFirmwareManager(newTestStatusHandler(), firmwareDownloader,
-			firmwareUpdater, unitConfigUpdater, testStorage, &TestInstanceRunner{}, 30*time.Second)
+			firmwareUpdater, unitConfigUpdater, testStorage, &TestInstanceRunner{}, 30*time.Second, false)
 		if err != nil {
 			t.Errorf("Can't create firmware manager: %s", err)
 			continue
@@ -765,88 +909,822 @@ func TestFirmwareManager(t *testing.T) {
 	}
 }
 
-func TestSoftwareManager(t *testing.T) {
-	type testData struct {
-		testID             string
-		initState          *softwareManager
-		initStatus         *cmserver.UpdateStatus
-		initServices       []ServiceStatus
-		initLayers         []LayerStatus
-		desiredStatus      *cloudprotocol.DesiredStatus
-		downloadTime       time.Duration
-		downloadResult     map[string]*downloadResult
-		triggerUpdate      bool
-		updateError        error
-		updateWaitStatuses []cmserver.UpdateStatus
-		newServices        []string
+func TestFirmwareContinueOnError(t *testing.T) {
+	firmwareUpdater := NewTestFirmwareUpdater(nil)
+	firmwareDownloader := newTestGroupDownloader()
+
+	// comp1 download fails, comp3 depends on comp1 and should be skipped as well
+	firmwareDownloader.result = map[string]*downloadResult{
+		"comp1": {Error: "download error"},
+		"comp2": {},
+		"comp3": {},
 	}
 
-	updateLayers := []cloudprotocol.LayerInfo{
-		{
-			ID:          "layer1",
-			Digest:      "digest1",
-			VersionInfo: aostypes.VersionInfo{AosVersion: 1},
-		},
-		{
-			ID:          "layer2",
-			Digest:      "digest2",
-			VersionInfo: aostypes.VersionInfo{AosVersion: 2},
+	firmwareUpdater.InitComponentsInfo = []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "0.0", Status: cloudprotocol.InstalledStatus},
+		{ID: "comp2", VendorVersion: "0.0", Status: cloudprotocol.InstalledStatus},
+		{ID: "comp3", VendorVersion: "0.0", Status: cloudprotocol.InstalledStatus},
+	}
+	firmwareUpdater.UpdateComponentsInfo = []cloudprotocol.ComponentStatus{
+		{ID: "comp2", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	}
+
+	manager, err := newFirmwareManager(newTestStatusHandler(), firmwareDownloader, firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), NewTestStorage(), &TestInstanceRunner{},
+		30*time.Second, true)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		Components: []cloudprotocol.ComponentInfo{
+			{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "1.0"}},
+			{ID: "comp2", VersionInfo: aostypes.VersionInfo{VendorVersion: "1.0"}},
+			{ID: "comp3", VersionInfo: aostypes.VersionInfo{VendorVersion: "1.0"}, Dependencies: []string{"comp1"}},
 		},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
 	}
 
-	updateServices := []cloudprotocol.ServiceInfo{
-		{
-			ID:          "service1",
-			VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.Updating},
+		{State: cmserver.NoUpdate},
+	} {
+		if err = waitForFOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	if !reflect.DeepEqual(firmwareUpdater.UpdatedComponents, []string{"comp2"}) {
+		t.Errorf("Wrong updated components: %v", firmwareUpdater.UpdatedComponents)
+	}
+
+	manager.statusMutex.RLock()
+	defer manager.statusMutex.RUnlock()
+
+	for id, errorCode := range map[string]string{
+		"comp1": cloudprotocol.ErrorCodeDownloadFailed, "comp3": cloudprotocol.ErrorCodeDependencyFailed,
+	} {
+		status := manager.ComponentStatuses[id]
+
+		if status.Status != cloudprotocol.ErrorStatus || status.ErrorInfo == nil ||
+			!strings.Contains(status.ErrorInfo.Message, "component skipped") {
+			t.Errorf("Component %s should be skipped: %v", id, status)
+			continue
+		}
+
+		if status.ErrorInfo.ErrorCode != errorCode {
+			t.Errorf("Wrong component %s error code: %s", id, status.ErrorInfo.ErrorCode)
+		}
+	}
+}
+
+func TestFirmwareInterruptedUpdateRetry(t *testing.T) {
+	oldComponents := []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	}
+	newComponents := []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "2.0", Status: cloudprotocol.InstalledStatus},
+	}
+
+	firmwareUpdater := NewTestFirmwareUpdater(oldComponents)
+	firmwareUpdater.UpdateComponentsInfo = newComponents
+	firmwareUpdater.UpdateTime = 500 * time.Millisecond
+
+	firmwareDownloader := newTestGroupDownloader()
+	firmwareDownloader.result = map[string]*downloadResult{"comp1": {}}
+
+	testStorage := NewTestStorage()
+
+	manager, err := newFirmwareManager(newTestStatusHandler(), firmwareDownloader, firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), testStorage, &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		Components: []cloudprotocol.ComponentInfo{
+			{
+				ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{1}},
+			},
 		},
-		{
-			ID:          "service2",
-			VersionInfo: aostypes.VersionInfo{AosVersion: 2},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.Updating},
+	} {
+		if err = waitForFOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	// Unit restarts in the middle of the update: keep the state stored at this moment
+
+	interruptedState, err := testStorage.GetFirmwareUpdateState()
+	if err != nil {
+		t.Fatalf("Can't get saved state: %v", err)
+	}
+
+	if err = waitForFOTAUpdateStatus(manager.statusChannel, cmserver.UpdateStatus{State: cmserver.NoUpdate}); err != nil {
+		t.Errorf("Wait for update status error: %s", err)
+	}
+
+	if err = manager.close(); err != nil {
+		t.Errorf("Error closing firmware manager: %s", err)
+	}
+
+	// UM lost the update on restart and reports previous versions: update should be retried once
+
+	restartedUpdater := NewTestFirmwareUpdater(oldComponents)
+	restartedUpdater.UpdateComponentsInfo = newComponents
+
+	restartedStorage := NewTestStorage()
+
+	if err = restartedStorage.SetFirmwareUpdateState(interruptedState); err != nil {
+		t.Fatalf("Can't set firmware update state: %v", err)
+	}
+
+	statusHandler := newTestStatusHandler()
+
+	restartedManager, err := newFirmwareManager(statusHandler, newTestGroupDownloader(), restartedUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), restartedStorage, &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := restartedManager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	if err = waitForFOTAUpdateStatus(
+		restartedManager.statusChannel, cmserver.UpdateStatus{State: cmserver.NoUpdate}); err != nil {
+		t.Errorf("Wait for update status error: %s", err)
+	}
+
+	if !reflect.DeepEqual(restartedUpdater.UpdatedComponents, []string{"comp1"}) {
+		t.Errorf("Wrong updated components: %v", restartedUpdater.UpdatedComponents)
+	}
+
+	historyEntries := statusHandler.getHistoryEntries()
+
+	if len(historyEntries) != 1 || historyEntries[0].Result != UpdateResultSuccess {
+		t.Errorf("Wrong update history: %v", historyEntries)
+	}
+}
+
+func TestFirmwareScheduleOnlyUpdate(t *testing.T) {
+	firmwareUpdater := NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+	testStorage := NewTestStorage()
+
+	manager, err := newFirmwareManager(newTestStatusHandler(), newTestGroupDownloader(), firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), testStorage, &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	schedule := cloudprotocol.ScheduleRule{
+		Type: cloudprotocol.TimetableUpdate,
+		Timetable: []cloudprotocol.TimetableEntry{{DayOfWeek: 1, TimeSlots: []cloudprotocol.TimeSlot{{
+			Start:  aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.Local)},
+			Finish: aostypes.Time{Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.Local)},
+		}}}},
+	}
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		FOTASchedule: schedule,
+		Components: []cloudprotocol.ComponentInfo{
+			{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "1.0"}},
 		},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
 	}
 
-	updateTimeSlots := []cloudprotocol.TimeSlot{{
-		Start:  aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.Local)},
-		Finish: aostypes.Time{Time: time.Date(0, 1, 1, 23, 59, 59, 999999, time.Local)},
-	}}
+	select {
+	case status := <-manager.statusChannel:
+		t.Errorf("Unexpected update status: %v", status)
 
-	updateTimetable := []cloudprotocol.TimetableEntry{
-		{DayOfWeek: 1, TimeSlots: updateTimeSlots},
-		{DayOfWeek: 2, TimeSlots: updateTimeSlots},
-		{DayOfWeek: 3, TimeSlots: updateTimeSlots},
-		{DayOfWeek: 4, TimeSlots: updateTimeSlots},
-		{DayOfWeek: 5, TimeSlots: updateTimeSlots},
-		{DayOfWeek: 6, TimeSlots: updateTimeSlots},
-		{DayOfWeek: 7, TimeSlots: updateTimeSlots},
+	case <-time.After(time.Second):
 	}
 
-	data := []testData{
-		{
-			testID:     "success update",
-			initStatus: &cmserver.UpdateStatus{State: cmserver.NoUpdate},
-			desiredStatus: &cloudprotocol.DesiredStatus{
-				Layers: updateLayers, Services: updateServices,
-			},
-			downloadResult: map[string]*downloadResult{
-				updateLayers[0].Digest: {}, updateLayers[1].Digest: {},
-				updateServices[0].ID: {}, updateServices[1].ID: {},
-			},
-			updateWaitStatuses: []cmserver.UpdateStatus{
-				{State: cmserver.Downloading},
-				{State: cmserver.ReadyToUpdate},
-				{State: cmserver.Updating},
-				{State: cmserver.NoUpdate},
-			},
-			newServices: []string{"service1", "service2"},
+	if manager.CurrentState != stateNoUpdate {
+		t.Errorf("Wrong firmware manager state: %s", manager.CurrentState)
+	}
+
+	rawState, err := testStorage.GetFirmwareUpdateState()
+	if err != nil {
+		t.Fatalf("Can't get saved state: %v", err)
+	}
+
+	var savedState firmwareManager
+
+	if err = json.Unmarshal(rawState, &savedState); err != nil {
+		t.Fatalf("Can't parse saved state: %v", err)
+	}
+
+	if savedState.CurrentUpdate == nil || savedState.CurrentUpdate.Schedule.Type != schedule.Type ||
+		len(savedState.CurrentUpdate.Schedule.Timetable) != len(schedule.Timetable) {
+		t.Errorf("Wrong saved schedule: %v", savedState.CurrentUpdate)
+	}
+}
+
+func TestFirmwareEmergencyUpdate(t *testing.T) {
+	firmwareUpdater := NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+	firmwareUpdater.UpdateComponentsInfo = []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "2.0", Status: cloudprotocol.InstalledStatus},
+	}
+
+	firmwareDownloader := newTestGroupDownloader()
+	firmwareDownloader.result = map[string]*downloadResult{"comp1": {}}
+
+	statusHandler := newTestStatusHandler()
+
+	manager, err := newFirmwareManager(statusHandler, firmwareDownloader, firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), NewTestStorage(), &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	// Timetable window starts tomorrow: without emergency flag update would be deferred
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	schedule := cloudprotocol.ScheduleRule{
+		Type: cloudprotocol.TimetableUpdate,
+		Timetable: []cloudprotocol.TimetableEntry{{
+			DayOfWeek: (uint(tomorrow.Weekday())+6)%7 + 1, TimeSlots: []cloudprotocol.TimeSlot{{
+				Start:  aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.Local)},
+				Finish: aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 1, 0, time.Local)},
+			}},
+		}},
+		Emergency: true,
+	}
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		FOTASchedule: schedule,
+		Components: []cloudprotocol.ComponentInfo{
+			{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"}},
 		},
-		{
-			testID:     "new services",
-			initStatus: &cmserver.UpdateStatus{State: cmserver.NoUpdate},
-			initServices: []ServiceStatus{
-				{
-					ServiceStatus: cloudprotocol.ServiceStatus{
-						ID:         updateServices[0].ID,
-						AosVersion: updateServices[0].AosVersion,
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.Updating},
+	} {
+		select {
+		case status := <-manager.statusChannel:
+			if err = compareStatuses(expectedStatus, status.UpdateStatus); err != nil {
+				t.Fatalf("Wrong update status: %s", err)
+			}
+
+			if !status.Emergency {
+				t.Error("Update status should be marked as emergency")
+			}
+
+			if !status.NextWindow.IsZero() {
+				t.Errorf("Unexpected next window: %v", status.NextWindow)
+			}
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Wait for %s status timeout", expectedStatus.State)
+		}
+	}
+
+	if err = waitForFOTAUpdateStatus(manager.statusChannel, cmserver.UpdateStatus{State: cmserver.NoUpdate}); err != nil {
+		t.Fatalf("Wait for update status error: %s", err)
+	}
+
+	historyEntries := statusHandler.getHistoryEntries()
+
+	if len(historyEntries) != 1 || !historyEntries[0].Emergency || historyEntries[0].Result != UpdateResultSuccess {
+		t.Errorf("Wrong update history: %v", historyEntries)
+	}
+}
+
+func TestFirmwareClockNotSynchronized(t *testing.T) {
+	savedRetryPeriod := clockSyncRetryPeriod
+	clockSyncRetryPeriod = 100 * time.Millisecond
+
+	defer func() { clockSyncRetryPeriod = savedRetryPeriod }()
+
+	timetableSchedule := cloudprotocol.ScheduleRule{Type: cloudprotocol.TimetableUpdate}
+
+	for day := uint(1); day <= 7; day++ {
+		timetableSchedule.Timetable = append(timetableSchedule.Timetable, cloudprotocol.TimetableEntry{
+			DayOfWeek: day, TimeSlots: []cloudprotocol.TimeSlot{{
+				Start:  aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.Local)},
+				Finish: aostypes.Time{Time: time.Date(0, 1, 1, 23, 59, 59, 999999, time.Local)},
+			}},
+		})
+	}
+
+	testData := []struct {
+		testID   string
+		schedule cloudprotocol.ScheduleRule
+	}{
+		{testID: "timetable update", schedule: timetableSchedule},
+		{testID: "emergency update", schedule: cloudprotocol.ScheduleRule{
+			Type: cloudprotocol.TriggerUpdate, Emergency: true,
+		}},
+	}
+
+	for _, item := range testData {
+		t.Run(item.testID, func(t *testing.T) {
+			testFirmwareClockNotSynchronized(t, item.schedule)
+		})
+	}
+}
+
+func testFirmwareClockNotSynchronized(t *testing.T, schedule cloudprotocol.ScheduleRule) {
+	t.Helper()
+
+	firmwareUpdater := NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+	firmwareUpdater.UpdateComponentsInfo = []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "2.0", Status: cloudprotocol.InstalledStatus},
+	}
+
+	firmwareDownloader := newTestGroupDownloader()
+	firmwareDownloader.result = map[string]*downloadResult{"comp1": {}}
+
+	manager, err := newFirmwareManager(newTestStatusHandler(), firmwareDownloader, firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), NewTestStorage(), &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	clockProvider := &testClockSkewProvider{}
+	clockProvider.setSkew(2 * time.Hour)
+
+	manager.stateMachine.setClockChecker(newClockChecker(clockProvider, time.Minute))
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		FOTASchedule: schedule,
+		Components: []cloudprotocol.ComponentInfo{
+			{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"}},
+		},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	// Update should be deferred while clock is skewed
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.ReadyToUpdate, Error: errClockNotSynchronized.Error()},
+		{State: cmserver.ReadyToUpdate, Error: errClockNotSynchronized.Error()},
+	} {
+		if err = waitForFOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	if len(firmwareUpdater.UpdatedComponents) != 0 {
+		t.Errorf("Unexpected updated components: %v", firmwareUpdater.UpdatedComponents)
+	}
+
+	// Update should start once clock is synchronized
+
+	clockProvider.setSkew(0)
+
+	for {
+		status := <-manager.statusChannel
+		if status.Error == "" {
+			if err = compareStatuses(cmserver.UpdateStatus{State: cmserver.Updating}, status.UpdateStatus); err != nil {
+				t.Fatalf("Wrong update status: %s", err)
+			}
+
+			break
+		}
+	}
+
+	if err = waitForFOTAUpdateStatus(manager.statusChannel, cmserver.UpdateStatus{State: cmserver.NoUpdate}); err != nil {
+		t.Fatalf("Wait for update status error: %s", err)
+	}
+}
+
+func TestFirmwareTTLWithTestClock(t *testing.T) {
+	firmwareUpdater := NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+
+	firmwareDownloader := newTestGroupDownloader()
+	firmwareDownloader.result = map[string]*downloadResult{"comp1": {}}
+
+	manager, err := newFirmwareManager(newTestStatusHandler(), firmwareDownloader, firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), NewTestStorage(), &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	startTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)
+	clock := newTestClock(startTime)
+
+	manager.setClock(clock)
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		FOTASchedule: cloudprotocol.ScheduleRule{Type: cloudprotocol.TriggerUpdate, TTL: 3600},
+		Components: []cloudprotocol.ComponentInfo{
+			{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"}},
+		},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+	} {
+		if err = waitForFOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	if status := manager.getCurrentStatus(); !status.TTLDate.Equal(startTime.Add(time.Hour)) {
+		t.Errorf("Wrong TTL date: %v", status.TTLDate)
+	}
+
+	// TTL is not expired yet
+
+	clock.advance(time.Hour - time.Second)
+
+	if status := manager.getCurrentStatus(); status.State != cmserver.ReadyToUpdate {
+		t.Errorf("Wrong update state: %s", status.State)
+	}
+
+	// TTL is expired
+
+	clock.advance(time.Second)
+
+	if err = waitForFOTAUpdateStatus(manager.statusChannel, cmserver.UpdateStatus{
+		State: cmserver.NoUpdate, Error: errUpdateTimeout.Error(),
+	}); err != nil {
+		t.Fatalf("Wait for update status error: %s", err)
+	}
+
+	if len(firmwareUpdater.UpdatedComponents) != 0 {
+		t.Errorf("Unexpected updated components: %v", firmwareUpdater.UpdatedComponents)
+	}
+}
+
+func TestFirmwareDeferredUpdateNextWindow(t *testing.T) {
+	firmwareUpdater := NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+
+	firmwareDownloader := newTestGroupDownloader()
+	firmwareDownloader.result = map[string]*downloadResult{"comp1": {}}
+
+	manager, err := newFirmwareManager(newTestStatusHandler(), firmwareDownloader, firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), NewTestStorage(), &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	components := []cloudprotocol.ComponentInfo{
+		{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"}},
+	}
+
+	// Timetable allows update only on the specified day starting from midnight
+
+	createSchedule := func(day time.Time) cloudprotocol.ScheduleRule {
+		dayOfWeek := uint(day.Weekday())
+		if dayOfWeek == 0 {
+			dayOfWeek = 7
+		}
+
+		return cloudprotocol.ScheduleRule{
+			Type: cloudprotocol.TimetableUpdate,
+			Timetable: []cloudprotocol.TimetableEntry{{DayOfWeek: dayOfWeek, TimeSlots: []cloudprotocol.TimeSlot{{
+				Start:  aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.Local)},
+				Finish: aostypes.Time{Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.Local)},
+			}}}},
+		}
+	}
+
+	now := time.Now()
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		FOTASchedule: createSchedule(now.AddDate(0, 0, 1)), Components: components,
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+	} {
+		if err = waitForFOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	for _, days := range []int{1, 2} {
+		nextWindow := time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, time.Local)
+
+		if days != 1 {
+			// Timetable changed: next window should be recomputed
+
+			if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+				FOTASchedule: createSchedule(nextWindow), Components: components,
+			}); err != nil {
+				t.Fatalf("Process desired status failed: %s", err)
+			}
+		}
+
+		select {
+		case status := <-manager.statusChannel:
+			if status.State != cmserver.ReadyToUpdate || !status.NextWindow.Equal(nextWindow) {
+				t.Errorf("Wrong deferred update status: %v", status)
+			}
+
+		case <-time.After(waitStatusTimeout):
+			t.Fatal("Wait for deferred update status timeout")
+		}
+
+		if status := manager.getCurrentStatus(); !status.NextWindow.Equal(nextWindow) {
+			t.Errorf("Wrong current status next window: %v", status.NextWindow)
+		}
+	}
+
+	if len(firmwareUpdater.UpdatedComponents) != 0 {
+		t.Errorf("Unexpected updated components: %v", firmwareUpdater.UpdatedComponents)
+	}
+}
+
+func TestFirmwareExtendUpdateTTL(t *testing.T) {
+	firmwareUpdater := NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+
+	firmwareDownloader := newTestGroupDownloader()
+	firmwareDownloader.result = map[string]*downloadResult{"comp1": {}}
+
+	manager, err := newFirmwareManager(newTestStatusHandler(), firmwareDownloader, firmwareUpdater,
+		NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), NewTestStorage(), &TestInstanceRunner{},
+		30*time.Second, false)
+	if err != nil {
+		t.Fatalf("Can't create firmware manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}()
+
+	if err = manager.extendTTL(time.Second); err == nil {
+		t.Error("TTL extension without update should fail")
+	}
+
+	const ttl = 2 * time.Second
+
+	started := time.Now()
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		FOTASchedule: cloudprotocol.ScheduleRule{Type: cloudprotocol.TriggerUpdate, TTL: uint64(ttl.Seconds())},
+		Components:   []cloudprotocol.ComponentInfo{{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"}}},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+	} {
+		if err = waitForFOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	ttlDate := manager.getCurrentStatus().TTLDate
+
+	if err = manager.extendTTL(ttl); err != nil {
+		t.Fatalf("Can't extend update TTL: %s", err)
+	}
+
+	select {
+	case status := <-manager.statusChannel:
+		if status.State != cmserver.ReadyToUpdate || !status.TTLDate.Equal(ttlDate.Add(ttl)) {
+			t.Errorf("Wrong extended update status: %v", status)
+		}
+
+	case <-time.After(waitStatusTimeout):
+		t.Fatal("Wait for extended update status timeout")
+	}
+
+	if err = waitForFOTAUpdateStatus(manager.statusChannel, cmserver.UpdateStatus{
+		State: cmserver.NoUpdate, Error: "update timeout",
+	}); err != nil {
+		t.Fatalf("Wait for update status error: %s", err)
+	}
+
+	if elapsed := time.Since(started); elapsed < 2*ttl {
+		t.Errorf("Update timed out too early: %s", elapsed)
+	}
+
+	if err = manager.extendTTL(ttl); err == nil {
+		t.Error("TTL extension of expired update should fail")
+	}
+}
+
+func TestFirmwareUnitConfigOrder(t *testing.T) {
+	type testData struct {
+		order         string
+		expectedItems []string
+	}
+
+	data := []testData{
+		{order: "", expectedItems: []string{"comp1", "unitConfig"}},
+		{order: unitConfigOrderAfterComponents, expectedItems: []string{"comp1", "unitConfig"}},
+		{order: unitConfigOrderBeforeComponents, expectedItems: []string{"unitConfig", "comp1"}},
+	}
+
+	for _, item := range data {
+		t.Logf("Unit config order: %s", item.order)
+
+		firmwareUpdater := NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+			{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+		})
+
+		firmwareDownloader := newTestGroupDownloader()
+		firmwareDownloader.result = map[string]*downloadResult{"comp1": {}}
+
+		statusHandler := newTestStatusHandler()
+
+		manager, err := newFirmwareManager(statusHandler, firmwareDownloader, firmwareUpdater,
+			NewTestUnitConfigUpdater(cloudprotocol.UnitConfigStatus{}), NewTestStorage(), &TestInstanceRunner{},
+			30*time.Second, false)
+		if err != nil {
+			t.Fatalf("Can't create firmware manager: %s", err)
+		}
+
+		manager.setUnitConfigOrder(item.order)
+
+		if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+			UnitConfig: json.RawMessage("{}"),
+			Components: []cloudprotocol.ComponentInfo{
+				{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"}},
+			},
+		}); err != nil {
+			t.Fatalf("Process desired status failed: %s", err)
+		}
+
+		for _, expectedStatus := range []cmserver.UpdateStatus{
+			{State: cmserver.Downloading},
+			{State: cmserver.ReadyToUpdate},
+			{State: cmserver.Updating},
+			{State: cmserver.NoUpdate},
+		} {
+			if err = waitForFOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+				t.Fatalf("Wait for update status error: %s", err)
+			}
+		}
+
+		if items := statusHandler.getInstallingItems(); !reflect.DeepEqual(items, item.expectedItems) {
+			t.Errorf("Wrong update order: %v", items)
+		}
+
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+	}
+}
+
+func TestSoftwareManager(t *testing.T) {
+	type testData struct {
+		testID             string
+		initState          *softwareManager
+		initStatus         *cmserver.UpdateStatus
+		initServices       []ServiceStatus
+		initLayers         []LayerStatus
+		desiredStatus      *cloudprotocol.DesiredStatus
+		downloadTime       time.Duration
+		downloadResult     map[string]*downloadResult
+		triggerUpdate      bool
+		updateError        error
+		updateWaitStatuses []cmserver.UpdateStatus
+		newServices        []string
+	}
+
+	updateLayers := []cloudprotocol.LayerInfo{
+		{
+			ID:          "layer1",
+			Digest:      "digest1",
+			VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+		},
+		{
+			ID:          "layer2",
+			Digest:      "digest2",
+			VersionInfo: aostypes.VersionInfo{AosVersion: 2},
+		},
+	}
+
+	updateServices := []cloudprotocol.ServiceInfo{
+		{
+			ID:          "service1",
+			VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+		},
+		{
+			ID:          "service2",
+			VersionInfo: aostypes.VersionInfo{AosVersion: 2},
+		},
+	}
+
+	updateTimeSlots := []cloudprotocol.TimeSlot{{
+		Start:  aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.Local)},
+		Finish: aostypes.Time{Time: time.Date(0, 1, 1, 23, 59, 59, 999999, time.Local)},
+	}}
+
+	updateTimetable := []cloudprotocol.TimetableEntry{
+		{DayOfWeek: 1, TimeSlots: updateTimeSlots},
+		{DayOfWeek: 2, TimeSlots: updateTimeSlots},
+		{DayOfWeek: 3, TimeSlots: updateTimeSlots},
+		{DayOfWeek: 4, TimeSlots: updateTimeSlots},
+		{DayOfWeek: 5, TimeSlots: updateTimeSlots},
+		{DayOfWeek: 6, TimeSlots: updateTimeSlots},
+		{DayOfWeek: 7, TimeSlots: updateTimeSlots},
+	}
+
+	data := []testData{
+		{
+			testID:     "success update",
+			initStatus: &cmserver.UpdateStatus{State: cmserver.NoUpdate},
+			desiredStatus: &cloudprotocol.DesiredStatus{
+				Layers: updateLayers, Services: updateServices,
+			},
+			downloadResult: map[string]*downloadResult{
+				updateLayers[0].Digest: {}, updateLayers[1].Digest: {},
+				updateServices[0].ID: {}, updateServices[1].ID: {},
+			},
+			updateWaitStatuses: []cmserver.UpdateStatus{
+				{State: cmserver.Downloading},
+				{State: cmserver.ReadyToUpdate},
+				{State: cmserver.Updating},
+				{State: cmserver.NoUpdate},
+			},
+			newServices: []string{"service1", "service2"},
+		},
+		{
+			testID:     "new services",
+			initStatus: &cmserver.UpdateStatus{State: cmserver.NoUpdate},
+			initServices: []ServiceStatus{
+				{
+					ServiceStatus: cloudprotocol.ServiceStatus{
+						ID:         updateServices[0].ID,
+						AosVersion: updateServices[0].AosVersion,
 						Status:     cloudprotocol.InstalledStatus,
 					},
 				},
@@ -1049,99 +1927,379 @@ func TestSoftwareManager(t *testing.T) {
 	}
 
 	softwareUpdater := NewTestSoftwareUpdater(nil, nil)
-	instanceRunner := NewTestInstanceRunner()
+	instanceRunner := NewTestInstanceRunner()
+	softwareDownloader := newTestGroupDownloader()
+	testStorage := NewTestStorage()
+
+	for _, item := range data {
+		t.Logf("Test item: %s", item.testID)
+
+		softwareDownloader.result = item.downloadResult
+		softwareDownloader.sotaReleased = false
+		softwareDownloader.downloadTime = item.downloadTime
+		softwareUpdater.AllServices = item.initServices
+		softwareUpdater.AllLayers = item.initLayers
+		softwareUpdater.UpdateError = item.updateError
+
+		if err := testStorage.saveSoftwareState(item.initState); err != nil {
+			t.Errorf("Can't save init state: %s", err)
+			continue
+		}
+
+		// Create software manager
+
+		statusHandler := newTestStatusHandler()
+
+		softwareManager, err := newSoftwareManager(statusHandler, softwareDownloader, softwareUpdater,
+			instanceRunner, testStorage, 30*time.Second, config.CachePolicy{}, config.RetryBudget{})
+		if err != nil {
+			t.Errorf("Can't create software manager: %s", err)
+			continue
+		}
+
+		// Check init status
+
+		if item.initStatus != nil {
+			if err = compareStatuses(*item.initStatus, softwareManager.getCurrentStatus().UpdateStatus); err != nil {
+				t.Errorf("Wrong init status: %s", err)
+			}
+		}
+
+		// Process desired status
+
+		if item.desiredStatus != nil {
+			if err = softwareManager.processDesiredStatus(*item.desiredStatus); err != nil {
+				t.Errorf("Process desired status failed: %s", err)
+				goto closeSM
+			}
+		}
+
+		// Trigger update
+
+		if item.triggerUpdate {
+			if err = softwareManager.startUpdate(); err != nil {
+				t.Errorf("Start update failed: %s", err)
+			}
+		}
+
+		for _, expectedStatus := range item.updateWaitStatuses {
+			if expectedStatus.State == cmserver.Updating {
+				if _, err := instanceRunner.WaitForRunInstance(time.Second); err != nil {
+					t.Errorf("Wait run instances error: %v", err)
+				}
+
+				if item.newServices != nil {
+					sort.Slice(instanceRunner.newServices, func(i, j int) bool {
+						return instanceRunner.newServices[j] > instanceRunner.newServices[i]
+					})
+
+					if !reflect.DeepEqual(instanceRunner.newServices, item.newServices) {
+						t.Errorf("Wrong new services: %v", instanceRunner.newServices)
+					}
+
+					// New services notification should be sent before instances are run
+					if err := checkNewServicesNotification(statusHandler.getNewServices(), item.newServices); err != nil {
+						t.Errorf("Wrong new services notification: %v", err)
+					}
+				}
+
+				if item.updateError == nil {
+					softwareManager.processRunStatus(RunInstancesStatus{})
+				}
+			}
+
+			if err = waitForSOTAUpdateStatus(softwareManager.statusChannel, expectedStatus); err != nil {
+				t.Errorf("Wait for update status error: %s", err)
+
+				if strings.Contains(err.Error(), "status timeout") {
+					goto closeSM
+				}
+			}
+		}
+
+	closeSM:
+		// Close software manager
+
+		if err = softwareManager.close(); err != nil {
+			t.Errorf("Error closing firmware manager: %s", err)
+		}
+
+		if !softwareDownloader.sotaReleased {
+			t.Error("SOTA downloads should be released")
+		}
+	}
+}
+
+func TestSoftwareRetryBudget(t *testing.T) {
+	softwareUpdater := NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := NewTestInstanceRunner()
+	softwareDownloader := newTestGroupDownloader()
+	testStorage := NewTestStorage()
+
+	softwareUpdater.UpdateError = aoserrors.New("install error")
+	softwareDownloader.result = map[string]*downloadResult{"service0": {}}
+
+	manager, err := newSoftwareManager(newTestStatusHandler(), softwareDownloader, softwareUpdater,
+		instanceRunner, testStorage, 30*time.Second, config.CachePolicy{}, config.RetryBudget{MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("Can't create software manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing software manager: %s", err)
+		}
+	}()
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0}},
+		},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.Updating},
+		{State: cmserver.NoUpdate, Error: "exceeded retry budget: install error"},
+	} {
+		if err = waitForSOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	if len(softwareUpdater.InstalledServices) != 3 {
+		t.Errorf("Wrong install attempts count: %d", len(softwareUpdater.InstalledServices))
+	}
+
+	rawState, err := testStorage.GetSoftwareUpdateState()
+	if err != nil {
+		t.Fatalf("Can't get saved state: %v", err)
+	}
+
+	var savedState softwareManager
+
+	if err = json.Unmarshal(rawState, &savedState); err != nil {
+		t.Fatalf("Can't parse saved state: %v", err)
+	}
+
+	if savedState.UpdateAttempts != 0 || !savedState.UpdateStartTime.IsZero() {
+		t.Errorf("Retry counters should be cleared: %d, %v", savedState.UpdateAttempts, savedState.UpdateStartTime)
+	}
+}
+
+func TestSoftwareRetryDelay(t *testing.T) {
+	softwareUpdater := NewTestSoftwareUpdater(nil, nil)
+	softwareDownloader := newTestGroupDownloader()
+
+	softwareUpdater.UpdateError = aoserrors.New("install error")
+	softwareDownloader.result = map[string]*downloadResult{"service0": {}}
+
+	manager, err := newSoftwareManager(newTestStatusHandler(), softwareDownloader, softwareUpdater,
+		NewTestInstanceRunner(), NewTestStorage(), 30*time.Second, config.CachePolicy{}, config.RetryBudget{
+			MaxAttempts: 3, RetryDelay: aostypes.Duration{Duration: 100 * time.Millisecond},
+			MaxRetryDelay: aostypes.Duration{Duration: 150 * time.Millisecond},
+		})
+	if err != nil {
+		t.Fatalf("Can't create software manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing software manager: %s", err)
+		}
+	}()
+
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0}},
+		},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.Updating},
+	} {
+		if err = waitForSOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
+		}
+	}
+
+	startTime := time.Now()
+
+	if err = waitForSOTAUpdateStatus(manager.statusChannel, cmserver.UpdateStatus{
+		State: cmserver.NoUpdate, Error: "exceeded retry budget: install error",
+	}); err != nil {
+		t.Fatalf("Wait for update status error: %s", err)
+	}
+
+	// Retries should be delayed by 100ms and 150ms (doubled delay limited by max retry delay)
+
+	if elapsed := time.Since(startTime); elapsed < 250*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Wrong retry delay: %v", elapsed)
+	}
+
+	if len(softwareUpdater.InstalledServices) != 3 {
+		t.Errorf("Wrong install attempts count: %d", len(softwareUpdater.InstalledServices))
+	}
+}
+
+func TestSoftwareRetryDelayCancel(t *testing.T) {
+	softwareUpdater := NewTestSoftwareUpdater(nil, nil)
 	softwareDownloader := newTestGroupDownloader()
-	testStorage := NewTestStorage()
 
-	for _, item := range data {
-		t.Logf("Test item: %s", item.testID)
+	softwareUpdater.UpdateError = aoserrors.New("install error")
+	softwareDownloader.result = map[string]*downloadResult{"service0": {}}
 
-		softwareDownloader.result = item.downloadResult
-		softwareDownloader.sotaReleased = false
-		softwareDownloader.downloadTime = item.downloadTime
-		softwareUpdater.AllServices = item.initServices
-		softwareUpdater.AllLayers = item.initLayers
-		softwareUpdater.UpdateError = item.updateError
+	manager, err := newSoftwareManager(newTestStatusHandler(), softwareDownloader, softwareUpdater,
+		NewTestInstanceRunner(), NewTestStorage(), 30*time.Second, config.CachePolicy{}, config.RetryBudget{
+			MaxAttempts: 3, RetryDelay: aostypes.Duration{Duration: time.Hour},
+		})
+	if err != nil {
+		t.Fatalf("Can't create software manager: %s", err)
+	}
 
-		if err := testStorage.saveSoftwareState(item.initState); err != nil {
-			t.Errorf("Can't save init state: %s", err)
-			continue
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0}},
+		},
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.Updating},
+	} {
+		if err = waitForSOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
 		}
+	}
 
-		// Create software manager
+	// Closing manager should cancel waiting for retry delay
+
+	time.Sleep(100 * time.Millisecond)
 
-		softwareManager, err := newSoftwareManager(newTestStatusHandler(), softwareDownloader, softwareUpdater,
-			instanceRunner, testStorage, 30*time.Second)
+	closeChannel := make(chan error, 1)
+
+	go func() {
+		closeChannel <- manager.close()
+	}()
+
+	select {
+	case err := <-closeChannel:
 		if err != nil {
-			t.Errorf("Can't create software manager: %s", err)
-			continue
+			t.Errorf("Error closing software manager: %s", err)
 		}
 
-		// Check init status
+	case <-time.After(time.Second):
+		t.Fatal("Wait for software manager close timeout")
+	}
 
-		if item.initStatus != nil {
-			if err = compareStatuses(*item.initStatus, softwareManager.getCurrentStatus().UpdateStatus); err != nil {
-				t.Errorf("Wrong init status: %s", err)
-			}
+	time.Sleep(100 * time.Millisecond)
+
+	manager.Lock()
+	defer manager.Unlock()
+
+	if len(softwareUpdater.InstalledServices) != 1 {
+		t.Errorf("Wrong install attempts count: %d", len(softwareUpdater.InstalledServices))
+	}
+}
+
+func TestRestartDuringSoftwareRun(t *testing.T) {
+	softwareUpdater := NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := NewTestInstanceRunner()
+	softwareDownloader := newTestGroupDownloader()
+	serializer := newRunSerializer(instanceRunner, restartDuringUpdateCoalesce)
+
+	softwareDownloader.result = map[string]*downloadResult{"service0": {}}
+
+	manager, err := newSoftwareManager(newTestStatusHandler(), softwareDownloader, softwareUpdater,
+		serializer, NewTestStorage(), 30*time.Second, config.CachePolicy{}, config.RetryBudget{})
+	if err != nil {
+		t.Fatalf("Can't create software manager: %s", err)
+	}
+	defer func() {
+		if err := manager.close(); err != nil {
+			t.Errorf("Error closing software manager: %s", err)
 		}
+	}()
 
-		// Process desired status
+	desiredInstances := []cloudprotocol.InstanceInfo{{ServiceID: "service0", SubjectID: "subject0", NumInstances: 1}}
 
-		if item.desiredStatus != nil {
-			if err = softwareManager.processDesiredStatus(*item.desiredStatus); err != nil {
-				t.Errorf("Process desired status failed: %s", err)
-				goto closeSM
-			}
+	if err = manager.processDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0}},
+		},
+		Instances: desiredInstances,
+	}); err != nil {
+		t.Fatalf("Process desired status failed: %s", err)
+	}
+
+	for _, expectedStatus := range []cmserver.UpdateStatus{
+		{State: cmserver.Downloading},
+		{State: cmserver.ReadyToUpdate},
+		{State: cmserver.Updating},
+	} {
+		if err = waitForSOTAUpdateStatus(manager.statusChannel, expectedStatus); err != nil {
+			t.Fatalf("Wait for update status error: %s", err)
 		}
+	}
 
-		// Trigger update
+	runInstances, err := instanceRunner.WaitForRunInstance(5 * time.Second)
+	if err != nil {
+		t.Fatalf("Wait run instances error: %v", err)
+	}
 
-		if item.triggerUpdate {
-			if err = softwareManager.startUpdate(); err != nil {
-				t.Errorf("Start update failed: %s", err)
-			}
+	if !reflect.DeepEqual(runInstances, desiredInstances) {
+		t.Errorf("Wrong run instances: %v", runInstances)
+	}
+
+	// Restarts requested during run phase are postponed and coalesced
+
+	for i := 0; i < 2; i++ {
+		if err = serializer.RestartInstances(); err != nil {
+			t.Errorf("Can't restart instances: %v", err)
 		}
+	}
 
-		for _, expectedStatus := range item.updateWaitStatuses {
-			if expectedStatus.State == cmserver.Updating {
-				if _, err := instanceRunner.WaitForRunInstance(time.Second); err != nil {
-					t.Errorf("Wait run instances error: %v", err)
-				}
+	if count := atomic.LoadInt32(&instanceRunner.restartCount); count != 0 {
+		t.Errorf("Instances should not be restarted during run phase: %d", count)
+	}
 
-				if item.newServices != nil {
-					sort.Slice(instanceRunner.newServices, func(i, j int) bool {
-						return instanceRunner.newServices[j] > instanceRunner.newServices[i]
-					})
+	serializer.processRunStatus()
+	manager.processRunStatus(RunInstancesStatus{})
 
-					if !reflect.DeepEqual(instanceRunner.newServices, item.newServices) {
-						t.Errorf("Wrong new services: %v", instanceRunner.newServices)
-					}
-				}
+	if err = waitForSOTAUpdateStatus(manager.statusChannel, cmserver.UpdateStatus{State: cmserver.NoUpdate}); err != nil {
+		t.Fatalf("Wait for update status error: %s", err)
+	}
 
-				if item.updateError == nil {
-					softwareManager.processRunStatus(RunInstancesStatus{})
-				}
-			}
+	for i := 0; atomic.LoadInt32(&instanceRunner.restartCount) == 0 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
 
-			if err = waitForSOTAUpdateStatus(softwareManager.statusChannel, expectedStatus); err != nil {
-				t.Errorf("Wait for update status error: %s", err)
+	time.Sleep(100 * time.Millisecond)
 
-				if strings.Contains(err.Error(), "status timeout") {
-					goto closeSM
-				}
-			}
-		}
+	if count := atomic.LoadInt32(&instanceRunner.restartCount); count != 1 {
+		t.Errorf("Wrong instances restart count: %d", count)
+	}
 
-	closeSM:
-		// Close software manager
+	if _, err = instanceRunner.WaitForRunInstance(100 * time.Millisecond); err == nil {
+		t.Error("Unexpected run instances request")
+	}
 
-		if err = softwareManager.close(); err != nil {
-			t.Errorf("Error closing firmware manager: %s", err)
-		}
+	// Restart outside of run phase is performed immediately
 
-		if !softwareDownloader.sotaReleased {
-			t.Error("SOTA downloads should be released")
-		}
+	if err = serializer.RestartInstances(); err != nil {
+		t.Errorf("Can't restart instances: %v", err)
+	}
+
+	if count := atomic.LoadInt32(&instanceRunner.restartCount); count != 2 {
+		t.Errorf("Wrong instances restart count: %d", count)
 	}
 }
 
@@ -1205,6 +2363,44 @@ func TestTimeTable(t *testing.T) {
 			},
 			err: "start value should be before finish value",
 		},
+		{
+			timetable: []cloudprotocol.TimetableEntry{
+				{
+					DayOfWeek: 1, TimeSlots: []cloudprotocol.TimeSlot{
+						{
+							Start:  aostypes.Time{Time: time.Date(0, 1, 1, 8, 0, 0, 0, time.Local)},
+							Finish: aostypes.Time{Time: time.Date(0, 1, 1, 10, 0, 0, 0, time.Local)},
+						},
+					},
+				},
+				{
+					DayOfWeek: 1, TimeSlots: []cloudprotocol.TimeSlot{
+						{
+							Start:  aostypes.Time{Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.Local)},
+							Finish: aostypes.Time{Time: time.Date(0, 1, 1, 14, 0, 0, 0, time.Local)},
+						},
+					},
+				},
+			},
+			err: "duplicate entry for day of week 1",
+		},
+		{
+			timetable: []cloudprotocol.TimetableEntry{
+				{
+					DayOfWeek: 2, TimeSlots: []cloudprotocol.TimeSlot{
+						{
+							Start:  aostypes.Time{Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.Local)},
+							Finish: aostypes.Time{Time: time.Date(0, 1, 1, 14, 0, 0, 0, time.Local)},
+						},
+						{
+							Start:  aostypes.Time{Time: time.Date(0, 1, 1, 8, 0, 0, 0, time.Local)},
+							Finish: aostypes.Time{Time: time.Date(0, 1, 1, 12, 30, 0, 0, time.Local)},
+						},
+					},
+				},
+			},
+			err: "overlapping time slots for day of week 2",
+		},
 		{
 			fromDate: time.Date(1, 1, 1, 0, 0, 0, 0, time.Local),
 			timetable: []cloudprotocol.TimetableEntry{
@@ -1309,34 +2505,307 @@ func TestTimeTable(t *testing.T) {
 					},
 				},
 			},
-			result: 70 * time.Hour,
+			result: 70 * time.Hour,
+		},
+	}
+
+	for i, item := range data {
+		t.Logf("Item: %d", i)
+
+		availableTime, err := getAvailableTimetableTime(item.fromDate, item.timetable)
+		if err != nil {
+			if item.err == "" {
+				t.Errorf("Can't get available timetable time: %s", err)
+				continue
+			}
+
+			if !strings.Contains(err.Error(), item.err) {
+				t.Errorf("Wrong error: %s", err)
+			}
+
+			continue
+		}
+
+		if item.err != "" {
+			t.Errorf("Error expected")
+			continue
+		}
+
+		if availableTime != item.result {
+			t.Errorf("Wrong available time: %v", availableTime)
+		}
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	type testData struct {
+		testID      string
+		schedule    cloudprotocol.ScheduleRule
+		defaultTTL  time.Duration
+		checkWindow bool
+		err         string
+	}
+
+	// Monday 10:00
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)
+
+	// Timetable allows update on Tuesday only
+	tuesdayTimetable := []cloudprotocol.TimetableEntry{{DayOfWeek: 2, TimeSlots: []cloudprotocol.TimeSlot{{
+		Start:  aostypes.Time{Time: time.Date(0, 1, 1, 0, 0, 0, 0, time.Local)},
+		Finish: aostypes.Time{Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.Local)},
+	}}}}
+
+	data := []testData{
+		{
+			testID:      "valid trigger schedule",
+			schedule:    cloudprotocol.ScheduleRule{Type: cloudprotocol.TriggerUpdate, TTL: 3600},
+			checkWindow: true,
+		},
+		{
+			testID: "valid timetable schedule",
+			schedule: cloudprotocol.ScheduleRule{
+				Type: cloudprotocol.TimetableUpdate, TTL: 2 * 24 * 3600, Timetable: tuesdayTimetable,
+			},
+			checkWindow: true,
+		},
+		{
+			testID:      "default schedule",
+			checkWindow: true,
+		},
+		{
+			testID: "immediately expiring TTL",
+			schedule: cloudprotocol.ScheduleRule{
+				Type: cloudprotocol.TimetableUpdate, TTL: 3600, Timetable: tuesdayTimetable,
+			},
+			checkWindow: true,
+			err:         "update TTL 1h0m0s expires before next timetable window in 14h0m0s",
+		},
+		{
+			testID:      "default TTL expires before timetable window",
+			schedule:    cloudprotocol.ScheduleRule{Type: cloudprotocol.TimetableUpdate, Timetable: tuesdayTimetable},
+			defaultTTL:  2 * time.Hour,
+			checkWindow: true,
+			err:         "update TTL 2h0m0s expires before next timetable window",
+		},
+		{
+			testID: "unsynchronized clock skips timetable window check",
+			schedule: cloudprotocol.ScheduleRule{
+				Type: cloudprotocol.TimetableUpdate, TTL: 3600, Timetable: tuesdayTimetable,
+			},
+		},
+		{
+			testID: "emergency update ignores timetable window",
+			schedule: cloudprotocol.ScheduleRule{
+				Type: cloudprotocol.TimetableUpdate, TTL: 3600, Timetable: tuesdayTimetable, Emergency: true,
+			},
+			checkWindow: true,
+		},
+		{
+			testID:      "invalid timetable",
+			schedule:    cloudprotocol.ScheduleRule{Type: cloudprotocol.TimetableUpdate},
+			checkWindow: true,
+			err:         "timetable is empty",
+		},
+		{
+			testID:   "invalid timetable on unsynchronized clock",
+			schedule: cloudprotocol.ScheduleRule{Type: cloudprotocol.TimetableUpdate},
+			err:      "timetable is empty",
+		},
+		{
+			testID:      "wrong update type",
+			schedule:    cloudprotocol.ScheduleRule{Type: "unknown"},
+			checkWindow: true,
+			err:         "wrong update type unknown",
+		},
+	}
+
+	for _, item := range data {
+		t.Logf("Test item: %s", item.testID)
+
+		err := validateSchedule(item.schedule, item.defaultTTL, now, item.checkWindow)
+
+		if item.err == "" {
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), item.err) {
+			t.Errorf("Wrong error: %v", err)
+		}
+	}
+}
+
+func TestComponentUpToDate(t *testing.T) {
+	type testData struct {
+		testID   string
+		desired  cloudprotocol.ComponentInfo
+		upToDate bool
+	}
+
+	// Canary update: component is updated on UM1 only
+	installedComponents := []cloudprotocol.ComponentStatus{
+		{ID: "comp1", VendorVersion: "2", Status: cloudprotocol.InstalledStatus, UMID: "um1"},
+		{ID: "comp1", VendorVersion: "1", Status: cloudprotocol.InstalledStatus, UMID: "um2"},
+		{ID: "comp2", VendorVersion: "1", Status: cloudprotocol.InstalledStatus},
+		{ID: "comp2", VendorVersion: "2", Status: cloudprotocol.ErrorStatus},
+	}
+
+	data := []testData{
+		{
+			testID: "targeted UM is up to date",
+			desired: cloudprotocol.ComponentInfo{
+				ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"}, UMIDs: []string{"um1"},
+			},
+			upToDate: true,
+		},
+		{
+			testID: "targeted UM is outdated",
+			desired: cloudprotocol.ComponentInfo{
+				ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"}, UMIDs: []string{"um2"},
+			},
+		},
+		{
+			testID:  "not all UMs are up to date",
+			desired: cloudprotocol.ComponentInfo{ID: "comp1", VersionInfo: aostypes.VersionInfo{VendorVersion: "2"}},
+		},
+		{
+			testID:   "failed version is ignored",
+			desired:  cloudprotocol.ComponentInfo{ID: "comp2", VersionInfo: aostypes.VersionInfo{VendorVersion: "1"}},
+			upToDate: true,
+		},
+		{
+			testID:  "component not found",
+			desired: cloudprotocol.ComponentInfo{ID: "comp3", VersionInfo: aostypes.VersionInfo{VendorVersion: "1"}},
+		},
+	}
+
+	for _, item := range data {
+		t.Logf("Test item: %s", item.testID)
+
+		if upToDate := isComponentUpToDate(item.desired, installedComponents); upToDate != item.upToDate {
+			t.Errorf("Wrong up to date result: %v", upToDate)
+		}
+	}
+}
+
+func TestConfigPolicy(t *testing.T) {
+	type testData struct {
+		testID  string
+		policy  config.UpdatePolicy
+		check   func(checker PolicyChecker) error
+		allowed bool
+	}
+
+	blockedPolicy := config.UpdatePolicy{
+		BlockedServices: []string{"service1"}, BlockedLayers: []string{"layer1"}, BlockedComponents: []string{"comp1"},
+	}
+
+	data := []testData{
+		{
+			testID: "blocked service",
+			policy: blockedPolicy,
+			check: func(checker PolicyChecker) error {
+				return checker.CheckService(cloudprotocol.ServiceInfo{ID: "service1"}, nil)
+			},
+		},
+		{
+			testID: "blocked layer",
+			policy: blockedPolicy,
+			check: func(checker PolicyChecker) error {
+				return checker.CheckLayer(cloudprotocol.LayerInfo{ID: "layer1"}, nil)
+			},
+		},
+		{
+			testID: "blocked component",
+			policy: blockedPolicy,
+			check: func(checker PolicyChecker) error {
+				return checker.CheckComponent(cloudprotocol.ComponentInfo{ID: "comp1"}, nil)
+			},
+		},
+		{
+			testID: "not blocked service",
+			policy: blockedPolicy,
+			check: func(checker PolicyChecker) error {
+				return checker.CheckService(cloudprotocol.ServiceInfo{ID: "service2"}, nil)
+			},
+			allowed: true,
+		},
+		{
+			testID: "downgrade denied",
+			policy: config.UpdatePolicy{DenyDowngrade: true},
+			check: func(checker PolicyChecker) error {
+				return checker.CheckService(
+					cloudprotocol.ServiceInfo{ID: "service1", VersionInfo: aostypes.VersionInfo{AosVersion: 1}},
+					&cloudprotocol.ServiceStatus{ID: "service1", AosVersion: 2})
+			},
+		},
+		{
+			testID: "upgrade allowed",
+			policy: config.UpdatePolicy{DenyDowngrade: true},
+			check: func(checker PolicyChecker) error {
+				return checker.CheckLayer(
+					cloudprotocol.LayerInfo{ID: "layer1", VersionInfo: aostypes.VersionInfo{AosVersion: 3}},
+					&cloudprotocol.LayerStatus{ID: "layer1", AosVersion: 2})
+			},
+			allowed: true,
+		},
+		{
+			testID: "downgrade allowed",
+			policy: config.UpdatePolicy{},
+			check: func(checker PolicyChecker) error {
+				return checker.CheckComponent(
+					cloudprotocol.ComponentInfo{ID: "comp1", VersionInfo: aostypes.VersionInfo{AosVersion: 1}},
+					&cloudprotocol.ComponentStatus{ID: "comp1", AosVersion: 2})
+			},
+			allowed: true,
 		},
 	}
 
-	for i, item := range data {
-		t.Logf("Item: %d", i)
+	for _, item := range data {
+		t.Logf("Test item: %s", item.testID)
 
-		availableTime, err := getAvailableTimetableTime(item.fromDate, item.timetable)
-		if err != nil {
-			if item.err == "" {
-				t.Errorf("Can't get available timetable time: %s", err)
-				continue
-			}
+		if err := item.check(NewConfigPolicy(item.policy)); (err == nil) != item.allowed {
+			t.Errorf("Wrong policy check result: %v", err)
+		}
+	}
+}
 
-			if !strings.Contains(err.Error(), item.err) {
-				t.Errorf("Wrong error: %s", err)
-			}
+func TestUpdateResult(t *testing.T) {
+	type testData struct {
+		testID    string
+		event     string
+		updateErr string
+		result    string
+	}
 
-			continue
-		}
+	data := []testData{
+		{testID: "update finished", event: eventFinishUpdate, result: UpdateResultSuccess},
+		{
+			testID: "update failed", event: eventFinishUpdate, updateErr: "update error",
+			result: UpdateResultFailed,
+		},
+		{
+			testID: "update canceled", event: eventCancel, updateErr: aoserrors.Wrap(context.Canceled).Error(),
+			result: UpdateResultCanceled,
+		},
+		{
+			testID: "update timeout", event: eventCancel, updateErr: aoserrors.Wrap(errUpdateTimeout).Error(),
+			result: UpdateResultFailed,
+		},
+		{
+			testID: "download failed", event: eventCancel, updateErr: "download error",
+			result: UpdateResultFailed,
+		},
+	}
 
-		if item.err != "" {
-			t.Errorf("Error expected")
-			continue
-		}
+	for _, item := range data {
+		t.Logf("Test item: %s", item.testID)
 
-		if availableTime != item.result {
-			t.Errorf("Wrong available time: %v", availableTime)
+		if result := getUpdateResult(item.event, item.updateErr); result != item.result {
+			t.Errorf("Wrong update result: %s", result)
 		}
 	}
 }
@@ -1363,7 +2832,7 @@ func TestSyncExecutor(t *testing.T) {
 		updateSynchronizer.execute(ctx, func() {
 			time.Sleep(1 * time.Second)
 			resultChannel <- value
-		})
+		}, nil)
 	}
 
 	cancelFunc()
@@ -1395,6 +2864,60 @@ func TestSyncExecutor(t *testing.T) {
 	}
 }
 
+func TestSendStatusJitter(t *testing.T) {
+	const (
+		numCycles        = 10
+		sendStatusPeriod = 200 * time.Millisecond
+		sendStatusJitter = 50
+		timeTolerance    = 50 * time.Millisecond
+	)
+
+	sender := NewTestSender()
+
+	instance := &Instance{
+		statusSender:      sender,
+		sendStatusPeriod:  sendStatusPeriod,
+		sendStatusJitter:  sendStatusJitter,
+		componentStatuses: make(map[string]*itemStatus),
+		layerStatuses:     make(map[string]*itemStatus),
+		serviceStatuses:   make(map[string]*itemStatus),
+		softwareManager:   &softwareManager{instanceRunner: NewTestInstanceRunner()},
+		initDone:          true,
+		isConnected:       1,
+	}
+
+	minInterval := sendStatusPeriod - sendStatusPeriod*sendStatusJitter/100
+	maxInterval := sendStatusPeriod + sendStatusPeriod*sendStatusJitter/100
+
+	intervals := make([]time.Duration, 0, numCycles)
+
+	for i := 0; i < numCycles; i++ {
+		startTime := time.Now()
+
+		instance.statusMutex.Lock()
+		instance.statusChanged()
+		instance.statusMutex.Unlock()
+
+		if _, err := sender.WaitForStatus(maxInterval + timeTolerance); err != nil {
+			t.Fatalf("Can't receive unit status: %v", err)
+		}
+
+		interval := time.Since(startTime)
+
+		if interval < minInterval || interval > maxInterval+timeTolerance {
+			t.Errorf("Send interval %v out of jitter band [%v, %v]", interval, minInterval, maxInterval)
+		}
+
+		intervals = append(intervals, interval)
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+
+	if intervals[len(intervals)-1]-intervals[0] < timeTolerance/10 {
+		t.Errorf("Send intervals don't vary: %v", intervals)
+	}
+}
+
 /***********************************************************************************************************************
  * Interfaces
  **********************************************************************************************************************/
@@ -1404,7 +2927,10 @@ func TestSyncExecutor(t *testing.T) {
  **********************************************************************************************************************/
 
 func NewTestSender() (sender *TestSender) {
-	return &TestSender{statusChannel: make(chan cloudprotocol.UnitStatus, 1)}
+	return &TestSender{
+		statusChannel:      make(chan cloudprotocol.UnitStatus, 1),
+		newServicesChannel: make(chan cloudprotocol.NewServices, 1),
+	}
 }
 
 func (sender *TestSender) SendUnitStatus(unitStatus cloudprotocol.UnitStatus) (err error) {
@@ -1413,6 +2939,22 @@ func (sender *TestSender) SendUnitStatus(unitStatus cloudprotocol.UnitStatus) (e
 	return nil
 }
 
+func (sender *TestSender) SendNewServices(newServices cloudprotocol.NewServices) (err error) {
+	sender.newServicesChannel <- newServices
+
+	return nil
+}
+
+func (sender *TestSender) GetNewServices() (newServices cloudprotocol.NewServices, ok bool) {
+	select {
+	case newServices = <-sender.newServicesChannel:
+		return newServices, true
+
+	default:
+		return newServices, false
+	}
+}
+
 func (sender *TestSender) WaitForStatus(timeout time.Duration) (status cloudprotocol.UnitStatus, err error) {
 	select {
 	case receivedUnitStatus := <-sender.statusChannel:
@@ -1470,6 +3012,11 @@ func (updater *TestFirmwareUpdater) UpdateComponents(
 	certs []cloudprotocol.Certificate,
 ) (componentsInfo []cloudprotocol.ComponentStatus, err error) {
 	time.Sleep(updater.UpdateTime)
+
+	for _, component := range components {
+		updater.UpdatedComponents = append(updater.UpdatedComponents, component.ID)
+	}
+
 	return updater.UpdateComponentsInfo, updater.UpdateError
 }
 
@@ -1492,6 +3039,8 @@ func (updater *TestSoftwareUpdater) GetLayersStatus() ([]LayerStatus, error) {
 func (updater *TestSoftwareUpdater) InstallService(serviceInfo cloudprotocol.ServiceInfo,
 	chains []cloudprotocol.CertificateChain, certs []cloudprotocol.Certificate,
 ) error {
+	updater.InstalledServices = append(updater.InstalledServices, serviceInfo.ID)
+
 	return updater.UpdateError
 }
 
@@ -1517,6 +3066,18 @@ func (updater *TestSoftwareUpdater) RestoreLayer(digest string) error {
 	return nil
 }
 
+func (updater *TestSoftwareUpdater) PurgeService(serviceID string) error {
+	updater.PurgedServices = append(updater.PurgedServices, serviceID)
+
+	return nil
+}
+
+func (updater *TestSoftwareUpdater) PurgeLayer(digest string) error {
+	updater.PurgedLayers = append(updater.PurgedLayers, digest)
+
+	return nil
+}
+
 /***********************************************************************************************************************
  * TestInstanceRunner
  **********************************************************************************************************************/
@@ -1525,14 +3086,33 @@ func NewTestInstanceRunner() *TestInstanceRunner {
 	return &TestInstanceRunner{runInstanceChan: make(chan []cloudprotocol.InstanceInfo, 1)}
 }
 
-func (runner *TestInstanceRunner) RunInstances(instances []cloudprotocol.InstanceInfo, newServices []string) error {
-	runner.newServices = newServices
-	runner.runInstanceChan <- instances
+func (runner *TestInstanceRunner) RunInstances(
+	instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	runner.run("", instances, newServices, correlationID)
+
+	return nil
+}
+
+func (runner *TestInstanceRunner) RunSubjectInstances(
+	subjectID string, instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) error {
+	desiredInstances := make([]cloudprotocol.InstanceInfo, 0, len(runner.instances)+len(instances))
+
+	for _, instance := range runner.instances {
+		if instance.SubjectID != subjectID {
+			desiredInstances = append(desiredInstances, instance)
+		}
+	}
+
+	runner.run(subjectID, append(desiredInstances, instances...), newServices, correlationID)
 
 	return nil
 }
 
 func (runner *TestInstanceRunner) RestartInstances() error {
+	atomic.AddInt32(&runner.restartCount, 1)
+
 	return nil
 }
 
@@ -1546,6 +3126,24 @@ func (runner *TestInstanceRunner) WaitForRunInstance(timeout time.Duration) ([]c
 	}
 }
 
+func (runner *TestInstanceRunner) run(
+	subjectID string, instances []cloudprotocol.InstanceInfo, newServices []string, correlationID string,
+) {
+	runner.instances = instances
+	runner.subjectID = subjectID
+	runner.newServices = newServices
+	runner.correlationID = correlationID
+	runner.runInstanceChan <- instances
+}
+
+func (runner *TestInstanceRunner) GetSubjectID() string {
+	return runner.subjectID
+}
+
+func (runner *TestInstanceRunner) GetCorrelationID() string {
+	return runner.correlationID
+}
+
 func (runner *TestInstanceRunner) GetNodesConfiguration() (nodes []cloudprotocol.NodeInfo) {
 	return nodes
 }
@@ -1555,7 +3153,7 @@ func (runner *TestInstanceRunner) GetNodesConfiguration() (nodes []cloudprotocol
  **********************************************************************************************************************/
 
 func NewTestDownloader() (testDownloader *TestDownloader) {
-	return &TestDownloader{DownloadTime: 1 * time.Second}
+	return &TestDownloader{DownloadTime: 1 * time.Second, cancelFuncs: make(map[string]context.CancelFunc)}
 }
 
 func (testDownloader *TestDownloader) SetError(url string, err error) {
@@ -1584,6 +3182,24 @@ func (testDownloader *TestDownloader) Download(
 		}
 	}
 
+	ctx, cancelFunc := context.WithCancel(ctx)
+
+	// Record synthetic throughput as if package is downloaded during download time
+	var bps uint64
+
+	if testDownloader.DownloadTime > 0 {
+		bps = uint64(float64(packageInfo.Size) / testDownloader.DownloadTime.Seconds())
+	}
+
+	testDownloader.Lock()
+	testDownloader.cancelFuncs[packageInfo.TargetID] = cancelFunc
+	testDownloader.Stats = append(testDownloader.Stats, downloader.DownloadStat{
+		ID: packageInfo.TargetID, TargetType: packageInfo.TargetType, TargetID: packageInfo.TargetID,
+		StartTime: time.Now(), Duration: testDownloader.DownloadTime, Bytes: packageInfo.Size,
+		AverageBps: bps, PeakBps: bps,
+	})
+	testDownloader.Unlock()
+
 	return &TestResult{
 		ctx:          ctx,
 		downloadTime: testDownloader.DownloadTime,
@@ -1604,6 +3220,31 @@ func (testDownloader *TestDownloader) ReleaseByType(targetType string) error {
 	return nil
 }
 
+func (testDownloader *TestDownloader) ReleaseByID(id string) error {
+	testDownloader.Lock()
+	defer testDownloader.Unlock()
+
+	testDownloader.ReleasedIDs = append(testDownloader.ReleasedIDs, id)
+
+	return nil
+}
+
+func (testDownloader *TestDownloader) GetDownloadStats() []downloader.DownloadStat {
+	testDownloader.Lock()
+	defer testDownloader.Unlock()
+
+	return append([]downloader.DownloadStat{}, testDownloader.Stats...)
+}
+
+func (testDownloader *TestDownloader) CancelDownload(id string) {
+	testDownloader.Lock()
+	defer testDownloader.Unlock()
+
+	if cancelFunc, ok := testDownloader.cancelFuncs[id]; ok {
+		cancelFunc()
+	}
+}
+
 func (result *TestResult) GetFileName() (fileName string) { return result.fileName }
 
 func (result *TestResult) Wait() (err error) {
@@ -1628,7 +3269,7 @@ func (downloader *testGroupDownloader) download(
 	ctx context.Context, request map[string]downloader.PackageInfo, continueOnError bool, updateStatus statusNotifier,
 ) (result map[string]*downloadResult) {
 	for id := range request {
-		updateStatus(id, cloudprotocol.DownloadingStatus, "")
+		updateStatus(id, cloudprotocol.DownloadingStatus, nil)
 	}
 
 	select {
@@ -1647,9 +3288,10 @@ func (downloader *testGroupDownloader) download(
 			}
 
 			if downloader.result[id].Error != "" {
-				updateStatus(id, cloudprotocol.ErrorStatus, downloader.result[id].Error)
+				updateStatus(id, cloudprotocol.ErrorStatus,
+					newErrorInfo(cloudprotocol.ErrorCodeDownloadFailed, downloader.result[id].Error))
 			} else {
-				updateStatus(id, cloudprotocol.DownloadedStatus, "")
+				updateStatus(id, cloudprotocol.DownloadedStatus, nil)
 			}
 		}
 
@@ -1658,7 +3300,8 @@ func (downloader *testGroupDownloader) download(
 	case <-ctx.Done():
 		for id := range request {
 			downloader.result[id].Error = aoserrors.Wrap(context.Canceled).Error()
-			updateStatus(id, cloudprotocol.ErrorStatus, downloader.result[id].Error)
+			updateStatus(id, cloudprotocol.ErrorStatus,
+				newErrorInfo(cloudprotocol.ErrorCodeUpdateCanceled, downloader.result[id].Error))
 		}
 
 		return result
@@ -1692,6 +3335,10 @@ func (statusHandler *testStatusHandler) updateComponentStatus(componentInfo clou
 		"status":  componentInfo.Status,
 		"error":   componentInfo.ErrorInfo,
 	}).Debug("Update component status")
+
+	if componentInfo.Status == cloudprotocol.InstallingStatus {
+		statusHandler.addInstallingItem(componentInfo.ID)
+	}
 }
 
 func (statusHandler *testStatusHandler) updateUnitConfigStatus(unitConfigInfo cloudprotocol.UnitConfigStatus) {
@@ -1700,6 +3347,24 @@ func (statusHandler *testStatusHandler) updateUnitConfigStatus(unitConfigInfo cl
 		"status":  unitConfigInfo.Status,
 		"error":   unitConfigInfo.ErrorInfo,
 	}).Debug("Update unit config status")
+
+	if unitConfigInfo.Status == cloudprotocol.InstallingStatus {
+		statusHandler.addInstallingItem("unitConfig")
+	}
+}
+
+func (statusHandler *testStatusHandler) addInstallingItem(item string) {
+	statusHandler.Lock()
+	defer statusHandler.Unlock()
+
+	statusHandler.installingItems = append(statusHandler.installingItems, item)
+}
+
+func (statusHandler *testStatusHandler) getInstallingItems() []string {
+	statusHandler.Lock()
+	defer statusHandler.Unlock()
+
+	return statusHandler.installingItems
 }
 
 func (statusHandler *testStatusHandler) updateLayerStatus(layerInfo cloudprotocol.LayerStatus) {
@@ -1721,6 +3386,60 @@ func (statusHandler *testStatusHandler) updateServiceStatus(serviceInfo cloudpro
 	}).Debug("Update service status")
 }
 
+func (statusHandler *testStatusHandler) addUpdateHistoryEntry(entry UpdateHistoryEntry) {
+	log.WithFields(log.Fields{
+		"type":   entry.Type,
+		"result": entry.Result,
+		"error":  entry.Error,
+	}).Debug("Add update history entry")
+
+	statusHandler.Lock()
+	defer statusHandler.Unlock()
+
+	statusHandler.historyEntries = append(statusHandler.historyEntries, entry)
+}
+
+func (statusHandler *testStatusHandler) getHistoryEntries() []UpdateHistoryEntry {
+	statusHandler.Lock()
+	defer statusHandler.Unlock()
+
+	return statusHandler.historyEntries
+}
+
+func (statusHandler *testStatusHandler) sendNewServices(newServices cloudprotocol.NewServices) {
+	statusHandler.Lock()
+	defer statusHandler.Unlock()
+
+	statusHandler.newServices = append(statusHandler.newServices, newServices)
+}
+
+func checkNewServicesNotification(notifications []cloudprotocol.NewServices, expectedServices []string) error {
+	if len(notifications) == 0 {
+		return aoserrors.New("notification is not sent")
+	}
+
+	services := make([]string, 0, len(expectedServices))
+
+	for _, service := range notifications[len(notifications)-1].Services {
+		services = append(services, service.ServiceID)
+	}
+
+	sort.Strings(services)
+
+	if !reflect.DeepEqual(services, expectedServices) {
+		return aoserrors.Errorf("wrong services: %v", services)
+	}
+
+	return nil
+}
+
+func (statusHandler *testStatusHandler) getNewServices() []cloudprotocol.NewServices {
+	statusHandler.Lock()
+	defer statusHandler.Unlock()
+
+	return statusHandler.newServices
+}
+
 func (statusHandler *testStatusHandler) setInstanceStatus(status []cloudprotocol.InstanceStatus) {
 	for _, instanceStatus := range status {
 		log.WithFields(log.Fields{
@@ -1734,6 +3453,131 @@ func (statusHandler *testStatusHandler) setInstanceStatus(status []cloudprotocol
 	}
 }
 
+/***********************************************************************************************************************
+ * testClockSkewProvider
+ **********************************************************************************************************************/
+
+func (provider *testClockSkewProvider) setSkew(skew time.Duration) {
+	atomic.StoreInt64(&provider.skew, int64(skew))
+}
+
+func (provider *testClockSkewProvider) GetClockSkew() (skew time.Duration, ok bool) {
+	return time.Duration(atomic.LoadInt64(&provider.skew)), true
+}
+
+/***********************************************************************************************************************
+ * testClock
+ **********************************************************************************************************************/
+
+func newTestClock(now time.Time) *testClock {
+	return &testClock{now: now}
+}
+
+func (clock *testClock) Now() time.Time {
+	clock.Lock()
+	defer clock.Unlock()
+
+	return clock.now
+}
+
+func (clock *testClock) After(d time.Duration) <-chan time.Time {
+	return clock.NewTimer(d).C()
+}
+
+func (clock *testClock) NewTimer(d time.Duration) Timer {
+	return clock.addTimer(d, nil)
+}
+
+func (clock *testClock) AfterFunc(d time.Duration, f func()) Timer {
+	return clock.addTimer(d, f)
+}
+
+func (clock *testClock) addTimer(d time.Duration, callback func()) *testTimer {
+	clock.Lock()
+	defer clock.Unlock()
+
+	timer := &testTimer{
+		clock: clock, channel: make(chan time.Time, 1), callback: callback, deadline: clock.now.Add(d), active: true,
+	}
+
+	clock.timers = append(clock.timers, timer)
+
+	return timer
+}
+
+// advance moves clock forward and fires expired timers.
+func (clock *testClock) advance(d time.Duration) {
+	clock.Lock()
+
+	clock.now = clock.now.Add(d)
+
+	var (
+		expiredTimers []*testTimer
+		activeTimers  []*testTimer
+	)
+
+	for _, timer := range clock.timers {
+		if !timer.active {
+			continue
+		}
+
+		if timer.deadline.After(clock.now) {
+			activeTimers = append(activeTimers, timer)
+			continue
+		}
+
+		timer.active = false
+		expiredTimers = append(expiredTimers, timer)
+	}
+
+	clock.timers = activeTimers
+	now := clock.now
+
+	clock.Unlock()
+
+	for _, timer := range expiredTimers {
+		if timer.callback != nil {
+			go timer.callback()
+			continue
+		}
+
+		select {
+		case timer.channel <- now:
+		default:
+		}
+	}
+}
+
+func (timer *testTimer) C() <-chan time.Time {
+	return timer.channel
+}
+
+func (timer *testTimer) Stop() bool {
+	timer.clock.Lock()
+	defer timer.clock.Unlock()
+
+	wasActive := timer.active
+	timer.active = false
+
+	return wasActive
+}
+
+func (timer *testTimer) Reset(d time.Duration) bool {
+	timer.clock.Lock()
+	defer timer.clock.Unlock()
+
+	wasActive := timer.active
+
+	timer.deadline = timer.clock.now.Add(d)
+	timer.active = true
+
+	if !wasActive {
+		timer.clock.timers = append(timer.clock.timers, timer)
+	}
+
+	return wasActive
+}
+
 /***********************************************************************************************************************
  * testStorage
  **********************************************************************************************************************/
@@ -1743,23 +3587,50 @@ func NewTestStorage() (storage *TestStorage) {
 }
 
 func (storage *TestStorage) SetFirmwareUpdateState(state json.RawMessage) (err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	storage.fotaState = state
 	return nil
 }
 
 func (storage *TestStorage) GetFirmwareUpdateState() (state json.RawMessage, err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	return storage.fotaState, nil
 }
 
 func (storage *TestStorage) SetSoftwareUpdateState(state json.RawMessage) (err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	storage.sotaState = state
 	return nil
 }
 
 func (storage *TestStorage) GetSoftwareUpdateState() (state json.RawMessage, err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
 	return storage.sotaState, nil
 }
 
+func (storage *TestStorage) SetUpdateHistory(history json.RawMessage) (err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
+	storage.updateHistory = history
+	return nil
+}
+
+func (storage *TestStorage) GetUpdateHistory() (history json.RawMessage, err error) {
+	storage.Lock()
+	defer storage.Unlock()
+
+	return storage.updateHistory, nil
+}
+
 func (storage *TestStorage) saveFirmwareState(state *firmwareManager) (err error) {
 	if state == nil {
 		storage.fotaState = nil
@@ -20,6 +20,7 @@ package unitstatushandler_test
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,11 +41,20 @@ const (
 	waitRunInstanceTimeout = 5 * time.Second
 )
 
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+type testDowngradePolicy struct{}
+
 /***********************************************************************************************************************
  * Vars
  **********************************************************************************************************************/
 
-var cfg = &config.Config{UnitStatusSendTimeout: aostypes.Duration{Duration: 3 * time.Second}}
+var (
+	cfg              = &config.Config{UnitStatusSendTimeout: aostypes.Duration{Duration: 3 * time.Second}}
+	errDowngradeDeny = aoserrors.New("downgrade is not allowed")
+)
 
 /***********************************************************************************************************************
  * Tests
@@ -150,6 +160,114 @@ func TestSendInitialStatus(t *testing.T) {
 	}
 }
 
+func TestResendStatusOnReconnect(t *testing.T) {
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, unitstatushandler.NewTestDownloader(),
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	if err := statusHandler.ProcessRunStatus(
+		unitstatushandler.RunInstancesStatus{UnitSubjects: []string{"subject1"}}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err := sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	sender.Consumer.CloudDisconnected()
+
+	if err := statusHandler.ProcessRunStatus(
+		unitstatushandler.RunInstancesStatus{UnitSubjects: []string{"subject2"}}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err := sender.WaitForStatus(time.Second); err == nil {
+		t.Fatal("Should be receive status timeout")
+	}
+
+	// Current status should be sent automatically on reconnect
+
+	sender.Consumer.CloudConnected()
+
+	receivedUnitStatus, err := sender.WaitForStatus(waitStatusTimeout)
+	if err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	if !reflect.DeepEqual(receivedUnitStatus.UnitSubjects, []string{"subject2"}) {
+		t.Errorf("Wrong unit subjects: %v", receivedUnitStatus.UnitSubjects)
+	}
+}
+
+func TestSendNewServices(t *testing.T) {
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, unitstatushandler.NewTestDownloader(),
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	// Unit is offline: new services notification should be sent anyway as sender queues it
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{
+				ID: "service1", VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{1}},
+			},
+		},
+		Instances: []cloudprotocol.InstanceInfo{
+			{ServiceID: "service1", SubjectID: "subject1", NumInstances: 1},
+			{ServiceID: "service1", SubjectID: "subject2", NumInstances: 2},
+		},
+		CorrelationID: "correlation1",
+	})
+
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err != nil {
+		t.Fatalf("Wait run instances error: %v", err)
+	}
+
+	// New services notification should be sent before instances are run
+
+	receivedNewServices, ok := sender.GetNewServices()
+	if !ok {
+		t.Fatal("New services are not sent before run instances")
+	}
+
+	if !reflect.DeepEqual(receivedNewServices, cloudprotocol.NewServices{
+		CorrelationID: "correlation1", Services: []cloudprotocol.NewService{{ServiceID: "service1", NumInstances: 3}},
+	}) {
+		t.Errorf("Wrong new services: %v", receivedNewServices)
+	}
+}
+
 func TestUpdateUnitConfig(t *testing.T) {
 	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
 		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
@@ -210,7 +328,9 @@ func TestUpdateUnitConfig(t *testing.T) {
 
 	unitConfigUpdater.UnitConfigStatus = cloudprotocol.UnitConfigStatus{
 		VendorVersion: "1.2", Status: cloudprotocol.ErrorStatus,
-		ErrorInfo: &cloudprotocol.ErrorInfo{Message: unitConfigUpdater.UpdateError.Error()},
+		ErrorInfo: &cloudprotocol.ErrorInfo{
+			ErrorCode: cloudprotocol.ErrorCodeUnitConfigFailed, Message: unitConfigUpdater.UpdateError.Error(),
+		},
 	}
 	expectedUnitStatus.UnitConfig = append(expectedUnitStatus.UnitConfig, unitConfigUpdater.UnitConfigStatus)
 
@@ -300,7 +420,9 @@ func TestUpdateComponents(t *testing.T) {
 			{ID: "comp1", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
 			{
 				ID: "comp1", VendorVersion: "2.0", Status: cloudprotocol.ErrorStatus,
-				ErrorInfo: &cloudprotocol.ErrorInfo{Message: firmwareUpdater.UpdateError.Error()},
+				ErrorInfo: &cloudprotocol.ErrorInfo{
+					ErrorCode: cloudprotocol.ErrorCodeInstallFailed, Message: firmwareUpdater.UpdateError.Error(),
+				},
 			},
 			{ID: "comp2", VendorVersion: "2.0", Status: cloudprotocol.InstalledStatus},
 		},
@@ -446,7 +568,9 @@ func TestUpdateLayers(t *testing.T) {
 			{ID: "layer4", Digest: "digest4", AosVersion: 1, Status: cloudprotocol.InstalledStatus},
 			{
 				ID: "layer5", Digest: "digest5", AosVersion: 1, Status: cloudprotocol.ErrorStatus,
-				ErrorInfo: &cloudprotocol.ErrorInfo{Message: softwareUpdater.UpdateError.Error()},
+				ErrorInfo: &cloudprotocol.ErrorInfo{
+					ErrorCode: cloudprotocol.ErrorCodeInstallFailed, Message: softwareUpdater.UpdateError.Error(),
+				},
 			},
 		},
 		Services: []cloudprotocol.ServiceStatus{},
@@ -594,18 +718,24 @@ func TestUpdateServices(t *testing.T) {
 		Services: []cloudprotocol.ServiceStatus{
 			{
 				ID: "service0", AosVersion: 0, Status: cloudprotocol.ErrorStatus,
-				ErrorInfo: &cloudprotocol.ErrorInfo{Message: softwareUpdater.UpdateError.Error()},
+				ErrorInfo: &cloudprotocol.ErrorInfo{
+					ErrorCode: cloudprotocol.ErrorCodeInstallFailed, Message: softwareUpdater.UpdateError.Error(),
+				},
 			},
 			{ID: "service1", AosVersion: 1, Status: cloudprotocol.InstalledStatus},
 			{ID: "service2", Status: cloudprotocol.RemovedStatus},
 			{ID: "service3", AosVersion: 1, Status: cloudprotocol.InstalledStatus},
 			{
 				ID: "service3", AosVersion: 2, Status: cloudprotocol.ErrorStatus,
-				ErrorInfo: &cloudprotocol.ErrorInfo{Message: softwareUpdater.UpdateError.Error()},
+				ErrorInfo: &cloudprotocol.ErrorInfo{
+					ErrorCode: cloudprotocol.ErrorCodeInstallFailed, Message: softwareUpdater.UpdateError.Error(),
+				},
 			},
 			{
 				ID: "service4", AosVersion: 2, Status: cloudprotocol.ErrorStatus,
-				ErrorInfo: &cloudprotocol.ErrorInfo{Message: softwareUpdater.UpdateError.Error()},
+				ErrorInfo: &cloudprotocol.ErrorInfo{
+					ErrorCode: cloudprotocol.ErrorCodeInstallFailed, Message: softwareUpdater.UpdateError.Error(),
+				},
 			},
 		},
 	}
@@ -756,7 +886,7 @@ func TestRunInstances(t *testing.T) {
 	}
 }
 
-func TestUpdateInstancesStatus(t *testing.T) {
+func TestRunSubjectInstances(t *testing.T) {
 	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
 		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
 	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
@@ -776,108 +906,196 @@ func TestUpdateInstancesStatus(t *testing.T) {
 
 	go handleUpdateStatus(statusHandler)
 
-	if err := statusHandler.ProcessRunStatus(
-		unitstatushandler.RunInstancesStatus{Instances: []cloudprotocol.InstanceStatus{
-			{
-				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
-			},
-			{
-				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 1}, AosVersion: 1,
-			},
-			{
-				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv2", SubjectID: "Subj2", Instance: 1}, AosVersion: 1,
-			},
-		}}); err != nil {
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
 		t.Fatalf("Can't process run status: %v", err)
 	}
 
-	if _, err := sender.WaitForStatus(waitStatusTimeout); err != nil {
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
 		t.Fatalf("Can't receive unit status: %v", err)
 	}
 
-	expectedUnitStatus := cloudprotocol.UnitStatus{
-		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
-		Instances: []cloudprotocol.InstanceStatus{
-			{
-				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
-				RunState: "fail", ErrorInfo: &cloudprotocol.ErrorInfo{Message: "someError"},
+	type testData struct {
+		testID            string
+		desiredInstances  []cloudprotocol.InstanceInfo
+		expectedSubjectID string
+	}
+
+	data := []testData{
+		{
+			testID: "first run: all instances",
+			desiredInstances: []cloudprotocol.InstanceInfo{
+				{ServiceID: "Serv1", SubjectID: "Subj1", NumInstances: 1},
+				{ServiceID: "Serv1", SubjectID: "Subj2", NumInstances: 1},
 			},
-			{
-				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 1}, AosVersion: 1,
+		},
+		{
+			testID: "only subject 2 changed",
+			desiredInstances: []cloudprotocol.InstanceInfo{
+				{ServiceID: "Serv1", SubjectID: "Subj1", NumInstances: 1},
+				{ServiceID: "Serv1", SubjectID: "Subj2", NumInstances: 2},
 			},
-			{
-				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv2", SubjectID: "Subj2", Instance: 1}, AosVersion: 1,
-				StateChecksum: "newState",
+			expectedSubjectID: "Subj2",
+		},
+		{
+			testID: "subject 1 removed",
+			desiredInstances: []cloudprotocol.InstanceInfo{
+				{ServiceID: "Serv1", SubjectID: "Subj2", NumInstances: 2},
+			},
+			expectedSubjectID: "Subj1",
+		},
+		{
+			testID: "both subjects changed",
+			desiredInstances: []cloudprotocol.InstanceInfo{
+				{ServiceID: "Serv1", SubjectID: "Subj1", NumInstances: 1},
+				{ServiceID: "Serv1", SubjectID: "Subj2", NumInstances: 1},
 			},
 		},
 	}
 
-	statusHandler.ProcessUpdateInstanceStatus([]cloudprotocol.InstanceStatus{
+	for _, item := range data {
+		t.Logf("Test item: %s", item.testID)
+
+		statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{Instances: item.desiredInstances})
+
+		receivedRunInstances, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout)
+		if err != nil {
+			t.Fatalf("Can't receive run instances: %v", err)
+		}
+
+		if subjectID := instanceRunner.GetSubjectID(); subjectID != item.expectedSubjectID {
+			t.Errorf("Wrong run subject: %s", subjectID)
+		}
+
+		if !reflect.DeepEqual(receivedRunInstances, item.desiredInstances) {
+			t.Errorf("Incorrect run instances: %v", receivedRunInstances)
+		}
+
+		var instancesStatus []cloudprotocol.InstanceStatus
+
+		for _, instance := range item.desiredInstances {
+			for i := uint64(0); i < instance.NumInstances; i++ {
+				instancesStatus = append(instancesStatus, cloudprotocol.InstanceStatus{
+					InstanceIdent: aostypes.InstanceIdent{
+						ServiceID: instance.ServiceID, SubjectID: instance.SubjectID, Instance: i,
+					},
+				})
+			}
+		}
+
+		if err := statusHandler.ProcessRunStatus(
+			unitstatushandler.RunInstancesStatus{Instances: instancesStatus}); err != nil {
+			t.Fatalf("Can't process run status: %v", err)
+		}
+
+		if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+			t.Fatalf("Can't receive unit status: %v", err)
+		}
+	}
+}
+
+func TestRunStatusBeforeDesiredStatus(t *testing.T) {
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, unitstatushandler.NewTestDownloader(),
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %v", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	// Fresh unit reports running instances: failed instance is also reported from its previous node
+
+	reportedInstances := []cloudprotocol.InstanceStatus{
 		{
 			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
-			RunState: "fail", ErrorInfo: &cloudprotocol.ErrorInfo{Message: "someError"},
+			NodeID: "node1",
 		},
 		{
-			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv2", SubjectID: "Subj2", Instance: 1}, AosVersion: 1,
-			StateChecksum: "newState",
+			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 1}, AosVersion: 1,
+			NodeID: "node1",
 		},
-	})
+		{
+			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 1}, AosVersion: 1,
+			NodeID: "node2", RunState: cloudprotocol.InstanceStateFailed,
+			ErrorInfo: &cloudprotocol.ErrorInfo{Message: "node is offline"},
+		},
+	}
+
+	if err := statusHandler.ProcessRunStatus(
+		unitstatushandler.RunInstancesStatus{Instances: reportedInstances}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
 
 	receivedUnitStatus, err := sender.WaitForStatus(waitStatusTimeout)
 	if err != nil {
 		t.Fatalf("Can't receive unit status: %v", err)
 	}
 
+	expectedUnitStatus := cloudprotocol.UnitStatus{
+		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
+		Instances:  reportedInstances,
+	}
+
 	if err = compareUnitStatus(receivedUnitStatus, expectedUnitStatus); err != nil {
 		t.Errorf("Wrong unit status received: %v, expected: %v", receivedUnitStatus, expectedUnitStatus)
 	}
-}
 
-func TestUpdateCachedSOTA(t *testing.T) {
-	serviceStatuses := []unitstatushandler.ServiceStatus{
-		{ServiceStatus: cloudprotocol.ServiceStatus{
-			ID: "service0", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}},
-		{ServiceStatus: cloudprotocol.ServiceStatus{
-			ID: "service1", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}},
-		{ServiceStatus: cloudprotocol.ServiceStatus{
-			ID: "service2", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}},
-		{ServiceStatus: cloudprotocol.ServiceStatus{
-			ID: "service4", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}, Cached: true},
+	// Desired status matching reported instances should not produce diff
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Instances: []cloudprotocol.InstanceInfo{{ServiceID: "Serv1", SubjectID: "Subj1", NumInstances: 2}},
+	})
+
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err == nil {
+		t.Error("Should be no run instances request")
 	}
-	layerStatuses := []unitstatushandler.LayerStatus{
-		{LayerStatus: cloudprotocol.LayerStatus{
-			ID: "layer0", Digest: "digest0", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}},
-		{LayerStatus: cloudprotocol.LayerStatus{
-			ID: "layer1", Digest: "digest1", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}},
-		{LayerStatus: cloudprotocol.LayerStatus{
-			ID: "layer2", Digest: "digest2", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}},
-		{LayerStatus: cloudprotocol.LayerStatus{
-			ID: "layer4", Digest: "digest4", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}, Cached: true},
-		{LayerStatus: cloudprotocol.LayerStatus{
-			ID: "layer5", Digest: "digest5", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
-		}, Cached: true},
+
+	// Changed desired status should produce single run request
+
+	expectedRunInstances := []cloudprotocol.InstanceInfo{
+		{ServiceID: "Serv1", SubjectID: "Subj1", NumInstances: 3},
+	}
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{Instances: expectedRunInstances})
+
+	receivedRunInstances, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout)
+	if err != nil {
+		t.Fatalf("Can't receive run instances: %v", err)
+	}
+
+	if !reflect.DeepEqual(receivedRunInstances, expectedRunInstances) {
+		t.Errorf("Incorrect run instances: %v", receivedRunInstances)
+	}
+
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err == nil {
+		t.Error("Should be no more run instances requests")
 	}
+}
+
+func TestDesiredStatusCorrelationID(t *testing.T) {
 	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
 		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
 	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
-	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(serviceStatuses, layerStatuses)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(nil, nil)
 	instanceRunner := unitstatushandler.NewTestInstanceRunner()
 	sender := unitstatushandler.NewTestSender()
-	downloader := unitstatushandler.NewTestDownloader()
+	correlationID := "desiredStatus1"
 
 	statusHandler, err := unitstatushandler.New(
-		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, downloader,
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, unitstatushandler.NewTestDownloader(),
 		unitstatushandler.NewTestStorage(), sender)
 	if err != nil {
-		t.Fatalf("Can't create unit status handler: %s", err)
+		t.Fatalf("Can't create unit status handler: %v", err)
 	}
 	defer statusHandler.Close()
 
@@ -889,13 +1107,327 @@ func TestUpdateCachedSOTA(t *testing.T) {
 		t.Fatalf("Can't process run status: %v", err)
 	}
 
-	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
-		t.Fatalf("Can't receive unit status: %s", err)
+	if _, err := sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
 	}
 
-	expectedUnitStatus := cloudprotocol.UnitStatus{
-		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
-		Components: []cloudprotocol.ComponentStatus{},
+	runInstances := []cloudprotocol.InstanceInfo{{ServiceID: "Serv1", SubjectID: "Subj1", NumInstances: 1}}
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Instances: runInstances, CorrelationID: correlationID,
+	})
+
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err != nil {
+		t.Fatalf("Can't receive run instances: %v", err)
+	}
+
+	if instanceRunner.GetCorrelationID() != correlationID {
+		t.Errorf("Wrong run instances correlation ID: %s", instanceRunner.GetCorrelationID())
+	}
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{{
+			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
+		}},
+		CorrelationID: instanceRunner.GetCorrelationID(),
+	}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	receivedUnitStatus, err := sender.WaitForStatus(waitStatusTimeout)
+	if err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
+	}
+
+	if receivedUnitStatus.CorrelationID != correlationID {
+		t.Errorf("Wrong unit status correlation ID: %s", receivedUnitStatus.CorrelationID)
+	}
+
+	// Following unit status is not caused by the request
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{
+		Instances: []cloudprotocol.InstanceStatus{{
+			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
+		}},
+	}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if receivedUnitStatus, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
+	}
+
+	if receivedUnitStatus.CorrelationID != "" {
+		t.Errorf("Unexpected unit status correlation ID: %s", receivedUnitStatus.CorrelationID)
+	}
+}
+
+func TestObserverMode(t *testing.T) {
+	observerCfg := *cfg
+	observerCfg.ObserverMode = true
+
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp0", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	testDownloader := unitstatushandler.NewTestDownloader()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		&observerCfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, testDownloader,
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %v", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
+	}
+
+	// SOTA update
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service0"}, Sha256: []byte{0}},
+			},
+		},
+		Instances: []cloudprotocol.InstanceInfo{{ServiceID: "service0", SubjectID: "subject0", NumInstances: 1}},
+	})
+
+	receivedUnitStatus, err := sender.WaitForStatus(waitStatusTimeout)
+	if err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
+	}
+
+	expectedUnitStatus := cloudprotocol.UnitStatus{
+		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
+		Components: []cloudprotocol.ComponentStatus{
+			{ID: "comp0", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+		},
+		Layers: []cloudprotocol.LayerStatus{},
+		Services: []cloudprotocol.ServiceStatus{
+			{ID: "service0", AosVersion: 1, Status: cloudprotocol.InstalledStatus},
+		},
+	}
+
+	if err = compareUnitStatus(receivedUnitStatus, expectedUnitStatus); err != nil {
+		t.Errorf("Wrong unit status received: %v, expected: %v", receivedUnitStatus, expectedUnitStatus)
+	}
+
+	expectedInstances := []cloudprotocol.InstanceStatus{{
+		InstanceIdent: aostypes.InstanceIdent{ServiceID: "service0", SubjectID: "subject0", Instance: 0},
+		RunState:      cloudprotocol.InstanceStateActivating,
+	}}
+
+	if !reflect.DeepEqual(receivedUnitStatus.Instances, expectedInstances) {
+		t.Errorf("Wrong instances status: %v", receivedUnitStatus.Instances)
+	}
+
+	// FOTA update
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Components: []cloudprotocol.ComponentInfo{
+			{
+				ID: "comp0", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"comp0"}},
+			},
+		},
+		Services: []cloudprotocol.ServiceInfo{
+			{
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service0"}, Sha256: []byte{0}},
+			},
+		},
+		Instances: []cloudprotocol.InstanceInfo{{ServiceID: "service0", SubjectID: "subject0", NumInstances: 1}},
+	})
+
+	if receivedUnitStatus, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
+	}
+
+	// Component is not updated: installed version is kept and requested one is pending
+	expectedUnitStatus.Components = []cloudprotocol.ComponentStatus{
+		{ID: "comp0", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+		{ID: "comp0", VendorVersion: "2.0", Status: cloudprotocol.PendingStatus},
+	}
+
+	if err = compareUnitStatus(receivedUnitStatus, expectedUnitStatus); err != nil {
+		t.Errorf("Wrong unit status received: %v, expected: %v", receivedUnitStatus, expectedUnitStatus)
+	}
+
+	// No side-effecting calls should be performed
+
+	if len(testDownloader.DownloadedURLs) != 0 {
+		t.Errorf("Unexpected downloads: %v", testDownloader.DownloadedURLs)
+	}
+
+	if len(softwareUpdater.InstalledServices) != 0 {
+		t.Errorf("Unexpected installed services: %v", softwareUpdater.InstalledServices)
+	}
+
+	if len(firmwareUpdater.UpdatedComponents) != 0 {
+		t.Errorf("Unexpected updated components: %v", firmwareUpdater.UpdatedComponents)
+	}
+
+	if _, err := instanceRunner.WaitForRunInstance(time.Second); err == nil {
+		t.Error("Unexpected run instances request")
+	}
+}
+
+func TestUpdateInstancesStatus(t *testing.T) {
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, unitstatushandler.NewTestDownloader(),
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %v", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(
+		unitstatushandler.RunInstancesStatus{Instances: []cloudprotocol.InstanceStatus{
+			{
+				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
+			},
+			{
+				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 1}, AosVersion: 1,
+			},
+			{
+				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv2", SubjectID: "Subj2", Instance: 1}, AosVersion: 1,
+			},
+		}}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err := sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
+	}
+
+	expectedUnitStatus := cloudprotocol.UnitStatus{
+		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
+		Instances: []cloudprotocol.InstanceStatus{
+			{
+				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
+				RunState: "fail", ErrorInfo: &cloudprotocol.ErrorInfo{Message: "someError"},
+			},
+			{
+				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 1}, AosVersion: 1,
+			},
+			{
+				InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv2", SubjectID: "Subj2", Instance: 1}, AosVersion: 1,
+				StateChecksum: "newState",
+			},
+		},
+	}
+
+	statusHandler.ProcessUpdateInstanceStatus([]cloudprotocol.InstanceStatus{
+		{
+			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv1", SubjectID: "Subj1", Instance: 0}, AosVersion: 1,
+			RunState: "fail", ErrorInfo: &cloudprotocol.ErrorInfo{Message: "someError"},
+		},
+		{
+			InstanceIdent: aostypes.InstanceIdent{ServiceID: "Serv2", SubjectID: "Subj2", Instance: 1}, AosVersion: 1,
+			StateChecksum: "newState",
+		},
+	})
+
+	receivedUnitStatus, err := sender.WaitForStatus(waitStatusTimeout)
+	if err != nil {
+		t.Fatalf("Can't receive unit status: %v", err)
+	}
+
+	if err = compareUnitStatus(receivedUnitStatus, expectedUnitStatus); err != nil {
+		t.Errorf("Wrong unit status received: %v, expected: %v", receivedUnitStatus, expectedUnitStatus)
+	}
+}
+
+func TestUpdateCachedSOTA(t *testing.T) {
+	serviceStatuses := []unitstatushandler.ServiceStatus{
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service0", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service1", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service2", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service4", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}, Cached: true},
+	}
+	layerStatuses := []unitstatushandler.LayerStatus{
+		{LayerStatus: cloudprotocol.LayerStatus{
+			ID: "layer0", Digest: "digest0", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{LayerStatus: cloudprotocol.LayerStatus{
+			ID: "layer1", Digest: "digest1", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{LayerStatus: cloudprotocol.LayerStatus{
+			ID: "layer2", Digest: "digest2", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{LayerStatus: cloudprotocol.LayerStatus{
+			ID: "layer4", Digest: "digest4", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}, Cached: true},
+		{LayerStatus: cloudprotocol.LayerStatus{
+			ID: "layer5", Digest: "digest5", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}, Cached: true},
+	}
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(serviceStatuses, layerStatuses)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+	downloader := unitstatushandler.NewTestDownloader()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, downloader,
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	expectedUnitStatus := cloudprotocol.UnitStatus{
+		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
+		Components: []cloudprotocol.ComponentStatus{},
 		Layers: []cloudprotocol.LayerStatus{
 			{ID: "layer0", Digest: "digest0", AosVersion: 0, Status: cloudprotocol.InstalledStatus},
 			{ID: "layer1", Digest: "digest1", AosVersion: 0, Status: cloudprotocol.InstalledStatus},
@@ -915,46 +1447,461 @@ func TestUpdateCachedSOTA(t *testing.T) {
 	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
 		Services: []cloudprotocol.ServiceInfo{
 			{
-				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service0"}, Sha256: []byte{0}},
-			},
-			{
-				ID: "service1", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service1"}, Sha256: []byte{1}},
-			},
-			{
-				ID: "service2", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service2"}, Sha256: []byte{2}},
-			},
-			{
-				ID: "service3", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service3"}, Sha256: []byte{3}},
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service0"}, Sha256: []byte{0}},
+			},
+			{
+				ID: "service1", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service1"}, Sha256: []byte{1}},
+			},
+			{
+				ID: "service2", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service2"}, Sha256: []byte{2}},
+			},
+			{
+				ID: "service3", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service3"}, Sha256: []byte{3}},
+			},
+			{
+				ID: "service4", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service3"}, Sha256: []byte{3}},
+			},
+		},
+		Layers: []cloudprotocol.LayerInfo{
+			{
+				ID: "layer0", Digest: "digest0", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer0"}, Sha256: []byte{0}},
+			},
+			{
+				ID: "layer1", Digest: "digest1", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer1"}, Sha256: []byte{1}},
+			},
+			{
+				ID: "layer2", Digest: "digest2", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer2"}, Sha256: []byte{2}},
+			},
+			{
+				ID: "layer3", Digest: "digest3", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer3"}, Sha256: []byte{3}},
+			},
+			{
+				ID: "layer5", Digest: "digest5", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer5"}, Sha256: []byte{3}},
+			},
+		},
+	})
+
+	receivedUnitStatus, err := sender.WaitForStatus(waitStatusTimeout)
+	if err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	for _, url := range downloader.DownloadedURLs {
+		if url == "service1" || url == "service2" || url == "layer1" || url == "layer2" {
+			t.Errorf("Unexpected download URL: %s", url)
+		}
+
+		if url != "service3" && url != "layer3" {
+			t.Errorf("Unexpected download URL: %s", url)
+		}
+	}
+
+	if err = compareUnitStatus(receivedUnitStatus, expectedUnitStatus); err != nil {
+		t.Errorf("Wrong unit status received: %v, expected: %v", receivedUnitStatus, expectedUnitStatus)
+	}
+}
+
+func TestForceDownloadCachedSOTA(t *testing.T) {
+	serviceStatuses := []unitstatushandler.ServiceStatus{
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service0", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service1", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}, Cached: true},
+	}
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(serviceStatuses, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+	downloader := unitstatushandler.NewTestDownloader()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, downloader,
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	// Cached service1 is marked for forced download: it should be downloaded and installed instead of restored
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service0"}, Sha256: []byte{0}},
+			},
+			{
+				ID: "service1", VersionInfo: aostypes.VersionInfo{AosVersion: 0}, ForceDownload: true,
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service1"}, Sha256: []byte{1}},
+			},
+		},
+	})
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	if !reflect.DeepEqual(downloader.DownloadedURLs, []string{"service1"}) {
+		t.Errorf("Unexpected download URLs: %v", downloader.DownloadedURLs)
+	}
+
+	if !reflect.DeepEqual(softwareUpdater.InstalledServices, []string{"service1"}) {
+		t.Errorf("Unexpected installed services: %v", softwareUpdater.InstalledServices)
+	}
+}
+
+func TestCachePolicy(t *testing.T) {
+	serviceStatuses := []unitstatushandler.ServiceStatus{
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service0", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service1", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}, Cached: true, Timestamp: time.Now().Add(-3 * time.Hour)},
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service2", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}, Cached: true, Timestamp: time.Now().Add(-2 * time.Hour)},
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service3", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}, Cached: true, Timestamp: time.Now().Add(-1 * time.Hour)},
+	}
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(serviceStatuses, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+	downloader := unitstatushandler.NewTestDownloader()
+
+	cacheCfg := *cfg
+	cacheCfg.CachePolicy = config.CachePolicy{MaxItems: 1}
+
+	statusHandler, err := unitstatushandler.New(
+		&cacheCfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, downloader,
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	// service1 is the oldest cached item but it is required by desired status and should be restored,
+	// service2 should be evicted as the oldest one from the rest cached items
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service0"}, Sha256: []byte{0}},
+			},
+			{
+				ID: "service1", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service1"}, Sha256: []byte{1}},
+			},
+		},
+	})
+
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err != nil {
+		t.Fatalf("Can't wait for run instances: %v", err)
+	}
+
+	if !reflect.DeepEqual(softwareUpdater.PurgedServices, []string{"service2"}) {
+		t.Errorf("Wrong purged services: %v", softwareUpdater.PurgedServices)
+	}
+
+	if len(softwareUpdater.PurgedLayers) != 0 {
+		t.Errorf("Wrong purged layers: %v", softwareUpdater.PurgedLayers)
+	}
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+}
+
+func TestUpdateHistory(t *testing.T) {
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(nil, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, unitstatushandler.NewTestDownloader(),
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	// success update
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
+			{
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{0}},
 			},
+		},
+	})
+
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err != nil {
+		t.Fatalf("Wait run instances error: %v", err)
+	}
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	// failed update
+
+	softwareUpdater.AllServices = []unitstatushandler.ServiceStatus{
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service0", AosVersion: 1, Status: cloudprotocol.InstalledStatus,
+		}},
+	}
+	softwareUpdater.UpdateError = aoserrors.New("some error occurs")
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
 			{
-				ID: "service4", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"service3"}, Sha256: []byte{3}},
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 2},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{0}},
 			},
 		},
-		Layers: []cloudprotocol.LayerInfo{
+	})
+
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err != nil {
+		t.Fatalf("Wait run instances error: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	history, err := waitUpdateHistory(statusHandler, 2, waitStatusTimeout)
+	if err != nil {
+		t.Fatalf("Can't get update history: %v", err)
+	}
+
+	if len(history) != 2 {
+		t.Fatalf("Wrong update history entries count: %d", len(history))
+	}
+
+	expectedEntries := []unitstatushandler.UpdateHistoryEntry{
+		{
+			Type: unitstatushandler.UpdateTypeSOTA, Result: unitstatushandler.UpdateResultSuccess,
+			Targets: []unitstatushandler.UpdateTarget{{ID: "service0", Version: "1"}},
+		},
+		{
+			Type: unitstatushandler.UpdateTypeSOTA, Result: unitstatushandler.UpdateResultFailed,
+			Targets: []unitstatushandler.UpdateTarget{{ID: "service0", Version: "2"}},
+			Error:   softwareUpdater.UpdateError.Error(),
+		},
+	}
+
+	for i, entry := range history {
+		if entry.StartTime.IsZero() || entry.EndTime.Before(entry.StartTime) {
+			t.Errorf("Wrong update history entry time: %v - %v", entry.StartTime, entry.EndTime)
+		}
+
+		if !strings.Contains(entry.Error, expectedEntries[i].Error) ||
+			(entry.Error == "") != (expectedEntries[i].Error == "") {
+			t.Errorf("Wrong update history entry error: %s", entry.Error)
+		}
+
+		entry.StartTime, entry.EndTime, entry.Error = time.Time{}, time.Time{}, expectedEntries[i].Error
+
+		if !reflect.DeepEqual(entry, expectedEntries[i]) {
+			t.Errorf("Wrong update history entry: %v", entry)
+		}
+	}
+}
+
+func TestPolicyRejectsDowngrade(t *testing.T) {
+	serviceStatuses := []unitstatushandler.ServiceStatus{
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service0", AosVersion: 2, Status: cloudprotocol.InstalledStatus,
+		}},
+	}
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater(nil)
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(serviceStatuses, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	downloader := unitstatushandler.NewTestDownloader()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, downloader,
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	statusHandler.SetPolicyChecker(&testDowngradePolicy{})
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	expectedUnitStatus := cloudprotocol.UnitStatus{
+		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
+		Components: []cloudprotocol.ComponentStatus{},
+		Layers:     []cloudprotocol.LayerStatus{},
+		Services: []cloudprotocol.ServiceStatus{
+			{ID: "service0", AosVersion: 2, Status: cloudprotocol.InstalledStatus},
 			{
-				ID: "layer0", Digest: "digest0", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer0"}, Sha256: []byte{0}},
+				ID: "service0", AosVersion: 1, Status: cloudprotocol.ErrorStatus,
+				ErrorInfo: &cloudprotocol.ErrorInfo{
+					ErrorCode: cloudprotocol.ErrorCodePolicyRejected, Message: errDowngradeDeny.Error(),
+				},
 			},
+		},
+	}
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		Services: []cloudprotocol.ServiceInfo{
 			{
-				ID: "layer1", Digest: "digest1", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer1"}, Sha256: []byte{1}},
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{0}},
 			},
+		},
+	})
+
+	receivedUnitStatus, err := sender.WaitForStatus(waitStatusTimeout)
+	if err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	if err = compareUnitStatus(receivedUnitStatus, expectedUnitStatus); err != nil {
+		t.Errorf("Wrong unit status received: %v, expected: %v", receivedUnitStatus, expectedUnitStatus)
+	}
+
+	if len(downloader.DownloadedURLs) != 0 {
+		t.Errorf("Unexpected downloads: %v", downloader.DownloadedURLs)
+	}
+}
+
+func TestInvalidScheduleRejectsOwnPart(t *testing.T) {
+	serviceStatuses := []unitstatushandler.ServiceStatus{
+		{ServiceStatus: cloudprotocol.ServiceStatus{
+			ID: "service0", AosVersion: 0, Status: cloudprotocol.InstalledStatus,
+		}},
+	}
+	unitConfigUpdater := unitstatushandler.NewTestUnitConfigUpdater(
+		cloudprotocol.UnitConfigStatus{VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus})
+	firmwareUpdater := unitstatushandler.NewTestFirmwareUpdater([]cloudprotocol.ComponentStatus{
+		{ID: "comp0", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+	})
+	softwareUpdater := unitstatushandler.NewTestSoftwareUpdater(serviceStatuses, nil)
+	instanceRunner := unitstatushandler.NewTestInstanceRunner()
+	sender := unitstatushandler.NewTestSender()
+
+	statusHandler, err := unitstatushandler.New(
+		cfg, unitConfigUpdater, firmwareUpdater, softwareUpdater, instanceRunner, unitstatushandler.NewTestDownloader(),
+		unitstatushandler.NewTestStorage(), sender)
+	if err != nil {
+		t.Fatalf("Can't create unit status handler: %s", err)
+	}
+	defer statusHandler.Close()
+
+	sender.Consumer.CloudConnected()
+
+	go handleUpdateStatus(statusHandler)
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	if _, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
+	// Invalid FOTA schedule rejects components update only, SOTA part is processed
+
+	statusHandler.ProcessDesiredStatus(cloudprotocol.DesiredStatus{
+		FOTASchedule: cloudprotocol.ScheduleRule{Type: "unknown"},
+		Components: []cloudprotocol.ComponentInfo{
 			{
-				ID: "layer2", Digest: "digest2", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer2"}, Sha256: []byte{2}},
+				ID: "comp0", VersionInfo: aostypes.VersionInfo{VendorVersion: "2.0"},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{0}},
 			},
+		},
+		Services: []cloudprotocol.ServiceInfo{
 			{
-				ID: "layer3", Digest: "digest3", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer3"}, Sha256: []byte{3}},
+				ID: "service0", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{0}},
 			},
 			{
-				ID: "layer5", Digest: "digest5", VersionInfo: aostypes.VersionInfo{AosVersion: 0},
-				DecryptDataStruct: cloudprotocol.DecryptDataStruct{URLs: []string{"layer5"}, Sha256: []byte{3}},
+				ID: "service1", VersionInfo: aostypes.VersionInfo{AosVersion: 1},
+				DecryptDataStruct: cloudprotocol.DecryptDataStruct{Sha256: []byte{1}},
 			},
 		},
 	})
@@ -964,21 +1911,81 @@ func TestUpdateCachedSOTA(t *testing.T) {
 		t.Fatalf("Can't receive unit status: %s", err)
 	}
 
-	for _, url := range downloader.DownloadedURLs {
-		if url == "service1" || url == "service2" || url == "layer1" || url == "layer2" {
-			t.Errorf("Unexpected download URL: %s", url)
-		}
+	if len(receivedUnitStatus.Components) != 2 {
+		t.Fatalf("Wrong components status received: %v", receivedUnitStatus.Components)
+	}
 
-		if url != "service3" && url != "layer3" {
-			t.Errorf("Unexpected download URL: %s", url)
+	for _, component := range receivedUnitStatus.Components {
+		switch component.VendorVersion {
+		case "1.0":
+			if component.Status != cloudprotocol.InstalledStatus {
+				t.Errorf("Wrong installed component status: %v", component)
+			}
+
+		case "2.0":
+			if component.Status != cloudprotocol.ErrorStatus || component.ErrorInfo == nil ||
+				!strings.Contains(component.ErrorInfo.Message, "invalid FOTA schedule") {
+				t.Errorf("Wrong rejected component status: %v", component)
+			}
+
+		default:
+			t.Errorf("Unexpected component status: %v", component)
 		}
 	}
 
+	if _, err := instanceRunner.WaitForRunInstance(waitRunInstanceTimeout); err != nil {
+		t.Errorf("Wait run instances error: %v", err)
+	}
+
+	if err := statusHandler.ProcessRunStatus(unitstatushandler.RunInstancesStatus{}); err != nil {
+		t.Fatalf("Can't process run status: %v", err)
+	}
+
+	expectedUnitStatus := cloudprotocol.UnitStatus{
+		UnitConfig: []cloudprotocol.UnitConfigStatus{unitConfigUpdater.UnitConfigStatus},
+		Components: []cloudprotocol.ComponentStatus{
+			{ID: "comp0", VendorVersion: "1.0", Status: cloudprotocol.InstalledStatus},
+		},
+		Layers: []cloudprotocol.LayerStatus{},
+		Services: []cloudprotocol.ServiceStatus{
+			{ID: "service0", AosVersion: 0, Status: cloudprotocol.InstalledStatus},
+			{ID: "service1", AosVersion: 1, Status: cloudprotocol.InstalledStatus},
+		},
+	}
+
+	if receivedUnitStatus, err = sender.WaitForStatus(waitStatusTimeout); err != nil {
+		t.Fatalf("Can't receive unit status: %s", err)
+	}
+
 	if err = compareUnitStatus(receivedUnitStatus, expectedUnitStatus); err != nil {
 		t.Errorf("Wrong unit status received: %v, expected: %v", receivedUnitStatus, expectedUnitStatus)
 	}
 }
 
+/***********************************************************************************************************************
+ * Interfaces
+ **********************************************************************************************************************/
+
+func (policy *testDowngradePolicy) CheckComponent(
+	cloudprotocol.ComponentInfo, *cloudprotocol.ComponentStatus,
+) error {
+	return nil
+}
+
+func (policy *testDowngradePolicy) CheckService(
+	service cloudprotocol.ServiceInfo, installed *cloudprotocol.ServiceStatus,
+) error {
+	if installed != nil && service.AosVersion < installed.AosVersion {
+		return errDowngradeDeny
+	}
+
+	return nil
+}
+
+func (policy *testDowngradePolicy) CheckLayer(cloudprotocol.LayerInfo, *cloudprotocol.LayerStatus) error {
+	return nil
+}
+
 /***********************************************************************************************************************
  * Private
  **********************************************************************************************************************/
@@ -1053,6 +2060,18 @@ func compareUnitStatus(status1, status2 cloudprotocol.UnitStatus) (err error) {
 	return nil
 }
 
+func waitUpdateHistory(
+	handler *unitstatushandler.Instance, count int, timeout time.Duration,
+) (history []unitstatushandler.UpdateHistoryEntry, err error) {
+	for start := time.Now(); time.Since(start) < timeout; time.Sleep(100 * time.Millisecond) {
+		if history = handler.GetUpdateHistory(); len(history) >= count {
+			return history, nil
+		}
+	}
+
+	return history, aoserrors.Errorf("wrong update history entries count: %d", len(history))
+}
+
 func handleUpdateStatus(handler *unitstatushandler.Instance) {
 	for {
 		select {
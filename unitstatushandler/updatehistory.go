@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unitstatushandler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	log "github.com/sirupsen/logrus"
+)
+
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+// Update types.
+const (
+	UpdateTypeFOTA = "fota"
+	UpdateTypeSOTA = "sota"
+)
+
+// Update results.
+const (
+	UpdateResultSuccess  = "success"
+	UpdateResultFailed   = "failed"
+	UpdateResultCanceled = "canceled"
+)
+
+const maxUpdateHistoryEntries = 32
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// UpdateTarget update target item.
+type UpdateTarget struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// UpdateHistoryEntry update history entry.
+type UpdateHistoryEntry struct {
+	Type      string         `json:"type"`
+	StartTime time.Time      `json:"startTime"`
+	EndTime   time.Time      `json:"endTime"`
+	Targets   []UpdateTarget `json:"targets,omitempty"`
+	Result    string         `json:"result"`
+	Error     string         `json:"error,omitempty"`
+	Emergency bool           `json:"emergency,omitempty"`
+}
+
+type updateHistory struct {
+	sync.Mutex
+
+	storage Storage
+	entries []UpdateHistoryEntry
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func newUpdateHistory(storage Storage) (history *updateHistory, err error) {
+	history = &updateHistory{storage: storage}
+
+	historyJSON, err := storage.GetUpdateHistory()
+	if err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	if len(historyJSON) == 0 {
+		return history, nil
+	}
+
+	if err = json.Unmarshal(historyJSON, &history.entries); err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	return history, nil
+}
+
+func (history *updateHistory) addEntry(entry UpdateHistoryEntry) {
+	history.Lock()
+	defer history.Unlock()
+
+	log.WithFields(log.Fields{
+		"type": entry.Type, "result": entry.Result, "error": entry.Error,
+	}).Debug("Add update history entry")
+
+	history.entries = append(history.entries, entry)
+
+	if len(history.entries) > maxUpdateHistoryEntries {
+		history.entries = history.entries[len(history.entries)-maxUpdateHistoryEntries:]
+	}
+
+	historyJSON, err := json.Marshal(history.entries)
+	if err != nil {
+		log.Errorf("Can't marshal update history: %v", err)
+		return
+	}
+
+	if err = history.storage.SetUpdateHistory(historyJSON); err != nil {
+		log.Errorf("Can't save update history: %v", err)
+	}
+}
+
+func (history *updateHistory) getEntries() (entries []UpdateHistoryEntry) {
+	history.Lock()
+	defer history.Unlock()
+
+	return append(entries, history.entries...)
+}
+
+func getUpdateResult(event, updateErr string) (result string) {
+	switch {
+	// Cancel event is also used to stop update on download failure or timeout, only context cancel is canceled update
+	case event == eventCancel && (updateErr == "" || strings.Contains(updateErr, context.Canceled.Error())):
+		return UpdateResultCanceled
+
+	case updateErr != "":
+		return UpdateResultFailed
+
+	default:
+		return UpdateResultSuccess
+	}
+}
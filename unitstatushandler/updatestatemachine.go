@@ -20,6 +20,7 @@ package unitstatushandler
 import (
 	"container/list"
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -50,6 +51,11 @@ const (
 	eventCancel         = "cancel"
 )
 
+var (
+	errUpdateTimeout        = errors.New("update timeout")
+	errClockNotSynchronized = errors.New("clock not synchronized")
+)
+
 /***********************************************************************************************************************
  * Types
  **********************************************************************************************************************/
@@ -61,10 +67,14 @@ type updateStateMachine struct {
 	wg         sync.WaitGroup
 	cancelFunc context.CancelFunc
 
-	updateTimer *time.Timer
-	ttlTimer    *time.Timer
+	updateTimer Timer
+	ttlTimer    Timer
+	nextWindow  time.Time
 
 	defaultTTL time.Duration
+
+	clockChecker func() error
+	clock        Clock
 }
 
 type updateManager interface {
@@ -75,6 +85,9 @@ type updateManager interface {
 	noUpdate()
 	startUpdate() error
 	updateTimeout()
+	clockNotSynchronized(reason string)
+	updateDeferred()
+	retrySchedule()
 }
 
 type syncExecutor struct {
@@ -89,6 +102,8 @@ type syncExecutor struct {
 
 var updateSynchronizer = newSyncExecutor() //nolint:gochecknoglobals
 
+var clockSyncRetryPeriod = 1 * time.Minute //nolint:gochecknoglobals
+
 /***********************************************************************************************************************
  * Interface
  **********************************************************************************************************************/
@@ -99,6 +114,7 @@ func newUpdateStateMachine(
 	stateMachine = &updateStateMachine{
 		manager:    manager,
 		defaultTTL: defaultTTL,
+		clock:      systemClock{},
 	}
 
 	stateMachine.fsm = fsm.NewFSM(
@@ -135,7 +151,7 @@ func (stateMachine *updateStateMachine) init(ttlDate time.Time) (err error) {
 	}
 
 	if stateMachine.fsm.Current() != stateNoUpdate && !ttlDate.IsZero() {
-		stateMachine.setTTLTimer(time.Until(ttlDate))
+		stateMachine.setTTLTimer(ttlDate.Sub(stateMachine.clock.Now()))
 	}
 
 	return nil
@@ -158,19 +174,64 @@ func (stateMachine *updateStateMachine) sendEvent(event string, managerErr strin
 	return nil
 }
 
+// deferOnUnsynchronizedClock postpones update schedule until clock is synchronized.
+func (stateMachine *updateStateMachine) deferOnUnsynchronizedClock() (deferred bool) {
+	err := stateMachine.checkClock()
+	if err == nil {
+		return false
+	}
+
+	log.WithFields(log.Fields{"retryIn": clockSyncRetryPeriod}).Warnf("Update deferred: %v", err)
+
+	stateMachine.manager.clockNotSynchronized(err.Error())
+
+	stateMachine.updateTimer = stateMachine.clock.AfterFunc(clockSyncRetryPeriod, stateMachine.manager.retrySchedule)
+
+	return true
+}
+
 func (stateMachine *updateStateMachine) scheduleUpdate(schedule cloudprotocol.ScheduleRule) {
 	var updateTime time.Duration
 
-	switch schedule.Type {
-	case cloudprotocol.TriggerUpdate:
+	// Schedule could be changed: stop previously scheduled update
+	if stateMachine.updateTimer != nil {
+		stateMachine.updateTimer.Stop()
+		stateMachine.updateTimer = nil
+	}
+
+	stateMachine.nextWindow = time.Time{}
+
+	switch {
+	case schedule.Emergency:
+		// Emergency update ignores trigger and timetable but still requires synchronized clock
+		if stateMachine.deferOnUnsynchronizedClock() {
+			return
+		}
+
+		log.WithFields(log.Fields{"in": updateTime}).Warn("Schedule emergency update")
+
+	case schedule.Type == cloudprotocol.TriggerUpdate:
 		log.Debug("Wait for update trigger")
 		return
 
-	case cloudprotocol.TimetableUpdate:
-		updateTime, _ = getAvailableTimetableTime(time.Now(), schedule.Timetable)
+	case schedule.Type == cloudprotocol.TimetableUpdate:
+		// Timetable can't be applied on unsynchronized clock: postpone it until clock is synchronized
+		if stateMachine.deferOnUnsynchronizedClock() {
+			return
+		}
+
+		now := stateMachine.clock.Now()
+
+		updateTime, _ = getAvailableTimetableTime(now, schedule.Timetable)
 
 		log.WithFields(log.Fields{"in": updateTime}).Debug("Schedule timetable update")
 
+		if updateTime > 0 {
+			stateMachine.nextWindow = now.Add(updateTime)
+
+			stateMachine.manager.updateDeferred()
+		}
+
 	default:
 		// Schedule forces update by default
 		updateTime = 0
@@ -178,7 +239,7 @@ func (stateMachine *updateStateMachine) scheduleUpdate(schedule cloudprotocol.Sc
 		log.WithFields(log.Fields{"in": updateTime}).Debug("Schedule forced update")
 	}
 
-	stateMachine.updateTimer = time.AfterFunc(updateTime, func() {
+	stateMachine.updateTimer = stateMachine.clock.AfterFunc(updateTime, func() {
 		if err := stateMachine.manager.startUpdate(); err != nil {
 			log.Errorf("Can't start update: %s", err)
 		}
@@ -203,7 +264,7 @@ func (stateMachine *updateStateMachine) startNewUpdate(ttlTime time.Duration) (t
 
 	// if TTL is not received and default value is zero then do not set TTL timer
 	if ttlTime != 0 {
-		ttlDate = time.Now().Add(ttlTime)
+		ttlDate = stateMachine.clock.Now().Add(ttlTime)
 		stateMachine.setTTLTimer(ttlTime)
 	}
 
@@ -214,6 +275,48 @@ func (stateMachine *updateStateMachine) startNewUpdate(ttlTime time.Duration) (t
 	return ttlDate, nil
 }
 
+func (stateMachine *updateStateMachine) extendTTL(
+	ttlDate time.Time, extension time.Duration,
+) (newTTLDate time.Time, err error) {
+	if state := stateMachine.fsm.Current(); state != stateReadyToUpdate {
+		return ttlDate, aoserrors.Errorf("can't extend TTL in %s state", state)
+	}
+
+	if extension <= 0 {
+		return ttlDate, aoserrors.Errorf("wrong TTL extension: %s", extension)
+	}
+
+	if ttlDate.IsZero() {
+		return ttlDate, aoserrors.New("update has no TTL")
+	}
+
+	if !stateMachine.clock.Now().Before(ttlDate) {
+		return ttlDate, aoserrors.New("update TTL already expired")
+	}
+
+	if stateMachine.ttlTimer != nil && !stateMachine.ttlTimer.Stop() {
+		return ttlDate, aoserrors.New("update TTL already expired")
+	}
+
+	newTTLDate = ttlDate.Add(extension)
+
+	stateMachine.setTTLTimer(newTTLDate.Sub(stateMachine.clock.Now()))
+
+	return newTTLDate, nil
+}
+
+func (stateMachine *updateStateMachine) getNextWindow() time.Time {
+	return stateMachine.nextWindow
+}
+
+func (stateMachine *updateStateMachine) setClockChecker(checker func() error) {
+	stateMachine.clockChecker = checker
+}
+
+func (stateMachine *updateStateMachine) setClock(clock Clock) {
+	stateMachine.clock = clock
+}
+
 func convertState(state string) (updateState cmserver.UpdateState) {
 	switch state {
 	case stateDownloading:
@@ -235,11 +338,19 @@ func convertState(state string) (updateState cmserver.UpdateState) {
  **********************************************************************************************************************/
 
 func (stateMachine *updateStateMachine) setTTLTimer(ttlTime time.Duration) {
-	stateMachine.ttlTimer = time.AfterFunc(ttlTime, func() {
+	stateMachine.ttlTimer = stateMachine.clock.AfterFunc(ttlTime, func() {
 		stateMachine.manager.updateTimeout()
 	})
 }
 
+func (stateMachine *updateStateMachine) checkClock() error {
+	if stateMachine.clockChecker == nil {
+		return nil
+	}
+
+	return stateMachine.clockChecker()
+}
+
 func (stateMachine *updateStateMachine) cancel() {
 	if stateMachine.cancelFunc != nil {
 		stateMachine.cancelFunc()
@@ -287,10 +398,11 @@ func (stateMachine *updateStateMachine) onStateUpdating(ctx context.Context, eve
 
 	stateMachine.wg.Add(1)
 
-	updateSynchronizer.execute(ctx, func() {
+	// Update waiting for other update is dropped only when it is canceled by the state machine
+	updateSynchronizer.execute(updateCtx, func() {
 		defer stateMachine.wg.Done()
 		stateMachine.manager.update(updateCtx)
-	})
+	}, stateMachine.wg.Done)
 
 	go func() {
 	}()
@@ -303,6 +415,8 @@ func (stateMachine *updateStateMachine) resetTimers() {
 		stateMachine.updateTimer = nil
 	}
 
+	stateMachine.nextWindow = time.Time{}
+
 	// Reset TTL timer
 	if stateMachine.ttlTimer != nil {
 		stateMachine.ttlTimer.Stop()
@@ -322,7 +436,9 @@ func newSyncExecutor() (executor *syncExecutor) {
 	return executor
 }
 
-func (executor *syncExecutor) execute(ctx context.Context, f func()) {
+// execute executes f when other functions are finished. Waiting function is dropped on ctx cancel and onCancel is
+// called instead.
+func (executor *syncExecutor) execute(ctx context.Context, f func(), onCancel func()) {
 	executor.Lock()
 	defer executor.Unlock()
 
@@ -342,8 +458,20 @@ func (executor *syncExecutor) execute(ctx context.Context, f func()) {
 				executor.Lock()
 				defer executor.Unlock()
 
+				// Function is already taken for execution
+				select {
+				case <-channelDone:
+					return
+
+				default:
+				}
+
 				executor.waitQueue.Remove(element)
 
+				if onCancel != nil {
+					onCancel()
+				}
+
 			case <-channelDone:
 			}
 		}()
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health aggregates health status of CM components.
+package health
+
+import (
+	"sort"
+	"sync"
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// StatusProvider component health status provider.
+type StatusProvider interface {
+	IsInsecure() bool
+}
+
+// ErrorProvider component health error provider.
+type ErrorProvider interface {
+	GetHealthError() error
+}
+
+// Status aggregated health status.
+type Status struct {
+	Insecure           bool              `json:"insecure"`
+	InsecureComponents []string          `json:"insecureComponents,omitempty"`
+	Errors             map[string]string `json:"errors,omitempty"`
+}
+
+// Aggregator collects health status of registered components.
+type Aggregator struct {
+	sync.Mutex
+
+	providers      map[string]StatusProvider
+	errorProviders map[string]ErrorProvider
+}
+
+/***********************************************************************************************************************
+ * Public
+ **********************************************************************************************************************/
+
+// New creates health aggregator.
+func New() (aggregator *Aggregator) {
+	return &Aggregator{
+		providers: make(map[string]StatusProvider), errorProviders: make(map[string]ErrorProvider),
+	}
+}
+
+// Register registers component status provider.
+func (aggregator *Aggregator) Register(component string, provider StatusProvider) {
+	aggregator.Lock()
+	defer aggregator.Unlock()
+
+	aggregator.providers[component] = provider
+}
+
+// RegisterErrorProvider registers component error provider.
+func (aggregator *Aggregator) RegisterErrorProvider(component string, provider ErrorProvider) {
+	aggregator.Lock()
+	defer aggregator.Unlock()
+
+	aggregator.errorProviders[component] = provider
+}
+
+// GetStatus returns aggregated health status.
+func (aggregator *Aggregator) GetStatus() (status Status) {
+	aggregator.Lock()
+	defer aggregator.Unlock()
+
+	for component, provider := range aggregator.providers {
+		if provider.IsInsecure() {
+			status.InsecureComponents = append(status.InsecureComponents, component)
+		}
+	}
+
+	for component, provider := range aggregator.errorProviders {
+		if err := provider.GetHealthError(); err != nil {
+			if status.Errors == nil {
+				status.Errors = make(map[string]string)
+			}
+
+			status.Errors[component] = err.Error()
+		}
+	}
+
+	sort.Strings(status.InsecureComponents)
+
+	status.Insecure = len(status.InsecureComponents) != 0
+
+	return status
+}
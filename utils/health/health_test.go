@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aosedge/aos_communicationmanager/utils/health"
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+type testStatusProvider struct {
+	insecure bool
+}
+
+type testErrorProvider struct {
+	err error
+}
+
+/***********************************************************************************************************************
+ * Tests
+ **********************************************************************************************************************/
+
+func TestEmptyStatus(t *testing.T) {
+	aggregator := health.New()
+
+	if status := aggregator.GetStatus(); !reflect.DeepEqual(status, health.Status{}) {
+		t.Errorf("Wrong health status: %v", status)
+	}
+}
+
+func TestInsecureComponents(t *testing.T) {
+	aggregator := health.New()
+
+	aggregator.Register("umcontroller", &testStatusProvider{insecure: true})
+	aggregator.Register("cmserver", &testStatusProvider{})
+	aggregator.Register("amqp", &testStatusProvider{insecure: true})
+
+	status := aggregator.GetStatus()
+
+	if !status.Insecure {
+		t.Error("Insecure mode should be reported")
+	}
+
+	if !reflect.DeepEqual(status.InsecureComponents, []string{"amqp", "umcontroller"}) {
+		t.Errorf("Wrong insecure components: %v", status.InsecureComponents)
+	}
+}
+
+func TestComponentErrors(t *testing.T) {
+	aggregator := health.New()
+	errorProvider := &testErrorProvider{err: errors.New("storage error")}
+
+	aggregator.RegisterErrorProvider("launcher", errorProvider)
+	aggregator.RegisterErrorProvider("imagemanager", &testErrorProvider{})
+
+	status := aggregator.GetStatus()
+
+	if status.Insecure {
+		t.Error("Insecure mode should not be reported")
+	}
+
+	if !reflect.DeepEqual(status.Errors, map[string]string{"launcher": "storage error"}) {
+		t.Errorf("Wrong health errors: %v", status.Errors)
+	}
+
+	// Error is cleared once component recovered
+
+	errorProvider.err = nil
+
+	if status = aggregator.GetStatus(); status.Errors != nil {
+		t.Errorf("Health errors should be cleared: %v", status.Errors)
+	}
+}
+
+/***********************************************************************************************************************
+ * Interfaces
+ **********************************************************************************************************************/
+
+func (provider *testStatusProvider) IsInsecure() bool {
+	return provider.insecure
+}
+
+func (provider *testErrorProvider) GetHealthError() error {
+	return provider.err
+}
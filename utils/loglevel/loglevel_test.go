@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loglevel_test
+
+import (
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aosedge/aos_communicationmanager/utils/loglevel"
+)
+
+/***********************************************************************************************************************
+ * Init
+ **********************************************************************************************************************/
+
+func init() {
+	log.SetFormatter(&log.TextFormatter{
+		DisableTimestamp: false,
+		TimestampFormat:  "2006-01-02 15:04:05.000",
+		FullTimestamp:    true,
+	})
+	log.SetLevel(log.DebugLevel)
+	log.SetOutput(os.Stdout)
+}
+
+/***********************************************************************************************************************
+ * Tests
+ **********************************************************************************************************************/
+
+func TestSetLevel(t *testing.T) {
+	defer log.SetLevel(log.DebugLevel)
+
+	if err := loglevel.SetLevel("warning"); err != nil {
+		t.Fatalf("Can't set log level: %v", err)
+	}
+
+	if log.GetLevel() != log.WarnLevel {
+		t.Errorf("Wrong global log level: %v", log.GetLevel())
+	}
+
+	if err := loglevel.SetLevel("verbose"); err == nil {
+		t.Error("Error expected for invalid log level")
+	}
+
+	if log.GetLevel() != log.WarnLevel {
+		t.Errorf("Global log level should not be changed: %v", log.GetLevel())
+	}
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics aggregates CM subsystems metrics and exposes them in Prometheus text format.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aosedge/aos_common/aoserrors"
+	log "github.com/sirupsen/logrus"
+)
+
+/***********************************************************************************************************************
+ * Consts
+ **********************************************************************************************************************/
+
+// Metric types.
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+)
+
+const (
+	metricsPath     = "/metrics"
+	metricsPrefix   = "aos_cm_"
+	shutdownTimeout = 5 * time.Second
+)
+
+/***********************************************************************************************************************
+ * Types
+ **********************************************************************************************************************/
+
+// Metric metric sample.
+type Metric struct {
+	Name   string
+	Help   string
+	Type   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Collector returns current metrics snapshot of subsystem.
+type Collector func() []Metric
+
+// Registry collects metrics of registered subsystems.
+type Registry struct {
+	sync.Mutex
+
+	collectors map[string]Collector
+}
+
+// Server exposes registry metrics over HTTP.
+type Server struct {
+	httpServer *http.Server
+	listener   net.Listener
+}
+
+/***********************************************************************************************************************
+ * Vars
+ **********************************************************************************************************************/
+
+//nolint:gochecknoglobals
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+/***********************************************************************************************************************
+ * Public
+ **********************************************************************************************************************/
+
+// New creates metrics registry.
+func New() (registry *Registry) {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// Register registers subsystem metrics collector.
+func (registry *Registry) Register(subsystem string, collector Collector) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.collectors[subsystem] = collector
+}
+
+// Gather returns metrics of all registered subsystems sorted by name.
+func (registry *Registry) Gather() (metrics []Metric) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, collector := range registry.collectors {
+		metrics = append(metrics, collector()...)
+	}
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		if metrics[i].Name != metrics[j].Name {
+			return metrics[i].Name < metrics[j].Name
+		}
+
+		return formatLabels(metrics[i].Labels) < formatLabels(metrics[j].Labels)
+	})
+
+	return metrics
+}
+
+// WriteText writes metrics in Prometheus text exposition format.
+func (registry *Registry) WriteText(w io.Writer) error {
+	var (
+		buffer   bytes.Buffer
+		lastName string
+	)
+
+	for _, metric := range registry.Gather() {
+		name := metricsPrefix + metric.Name
+
+		if metric.Name != lastName {
+			if metric.Help != "" {
+				fmt.Fprintf(&buffer, "# HELP %s %s\n", name, metric.Help)
+			}
+
+			if metric.Type != "" {
+				fmt.Fprintf(&buffer, "# TYPE %s %s\n", name, metric.Type)
+			}
+
+			lastName = metric.Name
+		}
+
+		fmt.Fprintf(&buffer, "%s%s %s\n", name, formatLabels(metric.Labels),
+			strconv.FormatFloat(metric.Value, 'g', -1, 64))
+	}
+
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// NewServer starts HTTP server exposing registry metrics on listen address.
+func NewServer(listenAddress string, registry *Registry) (server *Server, err error) {
+	log.WithField("address", listenAddress).Debug("Start metrics server")
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(metricsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		if err := registry.WriteText(w); err != nil {
+			log.Errorf("Can't write metrics: %v", err)
+		}
+	})
+
+	server = &Server{httpServer: &http.Server{Handler: mux, ReadHeaderTimeout: shutdownTimeout}}
+
+	if server.listener, err = net.Listen("tcp", listenAddress); err != nil {
+		return nil, aoserrors.Wrap(err)
+	}
+
+	go func() {
+		if err := server.httpServer.Serve(server.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Metrics server error: %v", err)
+		}
+	}()
+
+	return server, nil
+}
+
+// Addr returns server listen address.
+func (server *Server) Addr() string {
+	return server.listener.Addr().String()
+}
+
+// Close stops metrics server.
+func (server *Server) Close() error {
+	log.Debug("Close metrics server")
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelFunc()
+
+	if err := server.httpServer.Shutdown(ctx); err != nil {
+		return aoserrors.Wrap(err)
+	}
+
+	return nil
+}
+
+/***********************************************************************************************************************
+ * Private
+ **********************************************************************************************************************/
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+
+	for _, name := range names {
+		pairs = append(pairs, name+"=\""+labelValueReplacer.Replace(labels[name])+"\"")
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright (C) 2026 Renesas Electronics Corporation.
+// Copyright (C) 2026 EPAM Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	"slices"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aosedge/aos_communicationmanager/utils/metrics"
+)
+
+/***********************************************************************************************************************
+ * Init
+ **********************************************************************************************************************/
+
+func init() {
+	log.SetFormatter(&log.TextFormatter{
+		DisableTimestamp: false,
+		TimestampFormat:  "2006-01-02 15:04:05.000",
+		FullTimestamp:    true,
+	})
+	log.SetLevel(log.DebugLevel)
+	log.SetOutput(os.Stdout)
+}
+
+/***********************************************************************************************************************
+ * Tests
+ **********************************************************************************************************************/
+
+func TestMetricsEndpoint(t *testing.T) {
+	dropped := uint64(3)
+
+	registry := metrics.New()
+
+	registry.Register("amqp", func() []metrics.Metric {
+		return []metrics.Metric{{
+			Name: "monitoring_dropped_total", Help: "Number of dropped monitoring messages.",
+			Type: metrics.TypeCounter, Value: float64(dropped),
+		}}
+	})
+
+	registry.Register("balancing", func() []metrics.Metric {
+		return []metrics.Metric{
+			{
+				Name: "scheduled_instances_total", Type: metrics.TypeCounter,
+				Labels: map[string]string{"node": "node2"}, Value: 1,
+			},
+			{
+				Name: "scheduled_instances_total", Type: metrics.TypeCounter,
+				Labels: map[string]string{"node": "node1"}, Value: 2,
+			},
+		}
+	})
+
+	server, err := metrics.NewServer("localhost:0", registry)
+	if err != nil {
+		t.Fatalf("Can't create metrics server: %v", err)
+	}
+	defer server.Close()
+
+	// Metrics are collected on each request: source value change should be reflected
+	dropped = 5
+
+	resp, err := http.Get("http://" + server.Addr() + "/metrics") //nolint:noctx
+	if err != nil {
+		t.Fatalf("Can't get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Wrong status code: %d", resp.StatusCode)
+	}
+
+	var lines []string
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err = scanner.Err(); err != nil {
+		t.Fatalf("Can't read metrics: %v", err)
+	}
+
+	expectedLines := []string{
+		"# HELP aos_cm_monitoring_dropped_total Number of dropped monitoring messages.",
+		"# TYPE aos_cm_monitoring_dropped_total counter",
+		"aos_cm_monitoring_dropped_total 5",
+		"# TYPE aos_cm_scheduled_instances_total counter",
+		`aos_cm_scheduled_instances_total{node="node1"} 2`,
+		`aos_cm_scheduled_instances_total{node="node2"} 1`,
+	}
+
+	if !slices.Equal(lines, expectedLines) {
+		t.Errorf("Wrong metrics: %v", lines)
+	}
+}
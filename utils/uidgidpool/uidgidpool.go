@@ -19,6 +19,7 @@
 package uidgidpool
 
 import (
+	"errors"
 	"os/user"
 	"strconv"
 	"sync"
@@ -35,13 +36,27 @@ const (
 	idsRangeEnd   int = 10000
 )
 
+/**********************************************************************************************************************
+* Vars
+**********************************************************************************************************************/
+
+// ErrPoolExhausted is returned when pool has no free ID.
+var ErrPoolExhausted = errors.New("ID pool is exhausted")
+
 /**********************************************************************************************************************
 * Types
 **********************************************************************************************************************/
 
+// Utilization identifiers pool utilization.
+type Utilization struct {
+	Allocated int
+	Capacity  int
+}
+
 type IdentifierPool struct {
 	sync.Mutex
 	lockedIDs          []int
+	capacity           int
 	systemAvailability func(int) bool
 }
 
@@ -51,6 +66,7 @@ type IdentifierPool struct {
 
 func NewGroupIDPool() (pool *IdentifierPool) {
 	pool = &IdentifierPool{
+		capacity: idsRangeEnd - idsRangeBegin + 1,
 		systemAvailability: func(gid int) bool {
 			if group, err := user.LookupGroupId(strconv.Itoa(gid)); err == nil || group != nil {
 				return false
@@ -65,6 +81,7 @@ func NewGroupIDPool() (pool *IdentifierPool) {
 
 func NewUserIDPool() (pool *IdentifierPool) {
 	pool = &IdentifierPool{
+		capacity: idsRangeEnd - idsRangeBegin + 1,
 		systemAvailability: func(uid int) bool {
 			if user, err := user.LookupId(strconv.Itoa(uid)); err == nil || user != nil {
 				return false
@@ -81,6 +98,10 @@ func (pool *IdentifierPool) GetFreeID() (id int, err error) {
 	pool.Lock()
 	defer pool.Unlock()
 
+	if len(pool.lockedIDs) >= pool.capacity {
+		return 0, aoserrors.Wrap(ErrPoolExhausted)
+	}
+
 	if id, err = pool.getFreeIDFromPool(pool.systemAvailability); err != nil {
 		return 0, err
 	}
@@ -117,6 +138,36 @@ func (pool *IdentifierPool) RemoveID(id int) (err error) {
 	return aoserrors.New("can't remove ID from pool: UID/GID is not found")
 }
 
+// SetCapacity limits number of IDs which can be allocated from the pool. Zero sets the whole IDs range.
+func (pool *IdentifierPool) SetCapacity(capacity int) {
+	pool.Lock()
+	defer pool.Unlock()
+
+	if capacity <= 0 || capacity > idsRangeEnd-idsRangeBegin+1 {
+		capacity = idsRangeEnd - idsRangeBegin + 1
+	}
+
+	pool.capacity = capacity
+}
+
+// GetUtilization returns number of allocated IDs and pool capacity.
+func (pool *IdentifierPool) GetUtilization() Utilization {
+	pool.Lock()
+	defer pool.Unlock()
+
+	return Utilization{Allocated: len(pool.lockedIDs), Capacity: pool.capacity}
+}
+
+// IsAboveThreshold returns true if allocated IDs reach threshold in percents of capacity. Zero threshold disables
+// the check.
+func (utilization Utilization) IsAboveThreshold(threshold int) bool {
+	if threshold <= 0 {
+		return false
+	}
+
+	return utilization.Allocated*100 >= utilization.Capacity*threshold
+}
+
 /**********************************************************************************************************************
 * Private
 **********************************************************************************************************************/
@@ -134,7 +185,7 @@ func (pool *IdentifierPool) getFreeIDFromPool(systemAvailability func(int) bool)
 		return i, nil
 	}
 
-	return 0, aoserrors.New("can't get free id")
+	return 0, aoserrors.Wrap(ErrPoolExhausted)
 }
 
 func isInPool(pool []int, id int) (exist bool) {
@@ -18,6 +18,7 @@
 package uidgidpool_test
 
 import (
+	"errors"
 	"os"
 	"testing"
 
@@ -84,6 +85,47 @@ func TestGIDs(t *testing.T) {
 	testFunction(t, pool, "group id")
 }
 
+func TestPoolCapacity(t *testing.T) {
+	pool := uidgidpool.NewUserIDPool()
+
+	pool.SetCapacity(2)
+
+	for i := 0; i < 2; i++ {
+		if _, err := pool.GetFreeID(); err != nil {
+			t.Fatalf("Can't get user id: %v", err)
+		}
+	}
+
+	if utilization := pool.GetUtilization(); utilization != (uidgidpool.Utilization{Allocated: 2, Capacity: 2}) {
+		t.Errorf("Incorrect pool utilization: %v", utilization)
+	}
+
+	if _, err := pool.GetFreeID(); !errors.Is(err, uidgidpool.ErrPoolExhausted) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestUtilizationThreshold(t *testing.T) {
+	type testData struct {
+		utilization uidgidpool.Utilization
+		threshold   int
+		above       bool
+	}
+
+	testItems := []testData{
+		{utilization: uidgidpool.Utilization{Allocated: 2, Capacity: 4}, threshold: 75, above: false},
+		{utilization: uidgidpool.Utilization{Allocated: 3, Capacity: 4}, threshold: 75, above: true},
+		{utilization: uidgidpool.Utilization{Allocated: 4, Capacity: 4}, threshold: 75, above: true},
+		{utilization: uidgidpool.Utilization{Allocated: 4, Capacity: 4}, threshold: 0, above: false},
+	}
+
+	for _, item := range testItems {
+		if above := item.utilization.IsAboveThreshold(item.threshold); above != item.above {
+			t.Errorf("Wrong threshold check for %v and threshold %d: %v", item.utilization, item.threshold, above)
+		}
+	}
+}
+
 /**********************************************************************************************************************
 * Private
 **********************************************************************************************************************/